@@ -0,0 +1,229 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/autobrr/qui/internal/buildinfo"
+	"github.com/autobrr/qui/internal/config"
+	"github.com/autobrr/qui/internal/configbackup"
+	"github.com/autobrr/qui/internal/database"
+	"github.com/autobrr/qui/internal/domain"
+)
+
+func RunBackupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up and restore qui's own configuration",
+		Long: `Back up and restore qui's own configuration: the user account, API keys,
+instances, automations, cross-seed and dir-scan settings, indexers,
+notification targets, themes and the other settings stored in the database.
+
+Bundles are portable between installs and database engines. Encrypt them
+with a passphrase to include passwords and API keys; without one, secrets
+are redacted and have to be entered again after a restore.`,
+	}
+
+	cmd.AddCommand(runBackupCreateCommand())
+	cmd.AddCommand(runBackupRestoreCommand())
+	return cmd
+}
+
+func runBackupCreateCommand() *cobra.Command {
+	var configDir, dataDir, output, passphrase, passphraseFile string
+	var noEncrypt bool
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Export qui's configuration to a bundle",
+		Long: `Export qui's configuration to a bundle file.
+
+The export reads a consistent snapshot, so qui can keep running. Without
+--output the bundle is saved to <backupDir>/qui-config, next to the
+scheduled ones. The passphrase defaults to configBackupPassphrase.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := loadBackupConfig(configDir, dataDir)
+			if err != nil {
+				return err
+			}
+
+			passphrase, err = resolveBackupPassphrase(passphrase, passphraseFile, cfg.Config)
+			if err != nil {
+				return err
+			}
+			if noEncrypt {
+				passphrase = ""
+			}
+			if passphrase != "" && len(passphrase) < configbackup.MinPassphraseLength {
+				return fmt.Errorf("passphrase must be at least %d characters", configbackup.MinPassphraseLength)
+			}
+
+			db, err := database.OpenFromConfig(cfg.Config, cfg.GetDatabasePath())
+			if err != nil {
+				return fmt.Errorf("failed to initialize database: %w", err)
+			}
+			defer db.Close()
+
+			bundle, err := configbackup.Export(cmd.Context(), db, cfg.GetEncryptionKey(), configbackup.ExportOptions{
+				AppVersion:     buildinfo.Version,
+				IncludeSecrets: passphrase != "",
+			})
+			if err != nil {
+				return fmt.Errorf("failed to export configuration: %w", err)
+			}
+
+			path := strings.TrimSpace(output)
+			name := configbackup.FileName(bundle.CreatedAt, passphrase != "")
+			if path == "" {
+				path = filepath.Join(cfg.GetConfigBackupDir(), name)
+			} else if info, err := os.Stat(path); err == nil && info.IsDir() {
+				path = filepath.Join(path, name)
+			}
+			if err := configbackup.WriteFile(path, bundle, passphrase); err != nil {
+				return err
+			}
+
+			rows := 0
+			for _, table := range bundle.Tables {
+				rows += len(table.Rows)
+			}
+			cmd.Printf("Configuration exported to %s (%d tables, %d rows)\n", path, len(bundle.Tables), rows)
+			if passphrase == "" {
+				cmd.Println("The bundle is not encrypted, so passwords, API keys and notification URLs were redacted.")
+			}
+			return nil
+		},
+	}
+
+	addBackupConfigFlags(cmd, &configDir, &dataDir)
+	cmd.Flags().StringVarP(&output, "output", "o", "", "bundle file or directory (defaults to <backupDir>/qui-config)")
+	cmd.Flags().StringVar(&passphrase, "passphrase", "", "encrypt the bundle and include secrets (minimum 8 characters)")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "read the passphrase from a file")
+	cmd.Flags().BoolVar(&noEncrypt, "no-encrypt", false, "write an unencrypted bundle with secrets redacted, ignoring configBackupPassphrase")
+
+	return cmd
+}
+
+func runBackupRestoreCommand() *cobra.Command {
+	var configDir, dataDir, passphrase, passphraseFile string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <bundle>",
+		Short: "Restore qui's configuration from a bundle",
+		Long: `Restore qui's configuration from a bundle file.
+
+Stop qui before restoring. The database is created if it does not exist yet,
+so a bundle can seed a fresh install. A database that already has a user or
+instances is only replaced with --force, which also deletes the history of
+the instances it replaces.
+
+Secrets are encrypted with this install's session secret.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadBackupConfig(configDir, dataDir)
+			if err != nil {
+				return err
+			}
+
+			passphrase, err = resolveBackupPassphrase(passphrase, passphraseFile, cfg.Config)
+			if err != nil {
+				return err
+			}
+
+			bundle, err := configbackup.ReadFile(args[0], passphrase)
+			if errors.Is(err, configbackup.ErrPassphraseRequired) || (errors.Is(err, configbackup.ErrWrongPassphrase) && passphrase == cfg.Config.ConfigBackupPassphrase) {
+				if passphrase, err = readPassword("Enter bundle passphrase: "); err != nil {
+					return err
+				}
+				bundle, err = configbackup.ReadFile(args[0], passphrase)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read bundle: %w", err)
+			}
+
+			db, err := database.OpenFromConfig(cfg.Config, cfg.GetDatabasePath())
+			if err != nil {
+				return fmt.Errorf("failed to initialize database: %w", err)
+			}
+			defer db.Close()
+
+			result, err := configbackup.Restore(cmd.Context(), db, cfg.GetEncryptionKey(), bundle, configbackup.RestoreOptions{Force: force})
+			if errors.Is(err, configbackup.ErrNotEmpty) {
+				return errors.New("the database already has a qui configuration; use --force to replace it")
+			}
+			if err != nil {
+				return fmt.Errorf("failed to restore configuration: %w", err)
+			}
+
+			cmd.Printf("Restored configuration from qui %s (%s), created %s\n", bundle.AppVersion, bundle.Engine, bundle.CreatedAt.Local().Format(time.RFC1123))
+			for _, table := range result.Tables {
+				cmd.Printf("  - %s: %d\n", table.Name, table.Rows)
+			}
+			for _, warning := range result.Warnings {
+				cmd.Printf("Warning: %s\n", warning)
+			}
+			return nil
+		},
+	}
+
+	addBackupConfigFlags(cmd, &configDir, &dataDir)
+	cmd.Flags().StringVar(&passphrase, "passphrase", "", "passphrase of an encrypted bundle (will prompt if needed)")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "read the passphrase from a file")
+	cmd.Flags().BoolVar(&force, "force", false, "replace an existing configuration")
+
+	return cmd
+}
+
+func addBackupConfigFlags(cmd *cobra.Command, configDir, dataDir *string) {
+	cmd.Flags().StringVar(configDir, "config-dir", "",
+		"config directory or file path (defaults to OS-specific location)")
+	cmd.Flags().StringVar(dataDir, "data-dir", "",
+		"data directory path (defaults to next to config file)")
+}
+
+func loadBackupConfig(configDir, dataDir string) (*config.AppConfig, error) {
+	cfg, err := config.New(configDir, buildinfo.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize configuration: %w", err)
+	}
+	if dataDir != "" {
+		cfg.SetDataDir(dataDir)
+	}
+	return cfg, nil
+}
+
+// resolveBackupPassphrase picks the passphrase from the flags, falling back
+// to the one configured for scheduled backups.
+func resolveBackupPassphrase(passphrase, passphraseFile string, conf *domain.Config) (string, error) {
+	if passphrase != "" {
+		return passphrase, nil
+	}
+	if passphraseFile != "" {
+		data, err := os.ReadFile(passphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return conf.ConfigBackupPassphrase, nil
+}
+
+// configBackupSchedule returns the scheduled configuration backup settings
+// of conf.
+func configBackupSchedule(conf *domain.Config) configbackup.Schedule {
+	return configbackup.Schedule{
+		Interval:   time.Duration(conf.ConfigBackupInterval) * time.Hour,
+		Keep:       conf.ConfigBackupRetention,
+		Passphrase: conf.ConfigBackupPassphrase,
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // embedded zone database so automation schedules resolve IANA time zones in minimal containers
 
 	"github.com/alexedwards/scs/v2"
 	qbt "github.com/autobrr/go-qbittorrent"
@@ -26,17 +27,23 @@ import (
 	"github.com/autobrr/qui/internal/auth"
 	"github.com/autobrr/qui/internal/backups"
 	"github.com/autobrr/qui/internal/buildinfo"
+	"github.com/autobrr/qui/internal/clients"
 	"github.com/autobrr/qui/internal/config"
+	"github.com/autobrr/qui/internal/configbackup"
 	"github.com/autobrr/qui/internal/database"
 	"github.com/autobrr/qui/internal/dodo"
 	"github.com/autobrr/qui/internal/domain"
+	"github.com/autobrr/qui/internal/fsops"
+	localfs "github.com/autobrr/qui/internal/fsops/local"
 	"github.com/autobrr/qui/internal/metrics"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/polar"
 	"github.com/autobrr/qui/internal/qbittorrent"
 	"github.com/autobrr/qui/internal/services/activity"
 	"github.com/autobrr/qui/internal/services/arr"
+	"github.com/autobrr/qui/internal/services/audit"
 	"github.com/autobrr/qui/internal/services/automations"
+	"github.com/autobrr/qui/internal/services/bulkjobs"
 	"github.com/autobrr/qui/internal/services/crossseed"
 	"github.com/autobrr/qui/internal/services/dirscan"
 	"github.com/autobrr/qui/internal/services/externalprograms"
@@ -45,6 +52,7 @@ import (
 	"github.com/autobrr/qui/internal/services/license"
 	"github.com/autobrr/qui/internal/services/notifications"
 	"github.com/autobrr/qui/internal/services/orphanscan"
+	"github.com/autobrr/qui/internal/services/prefprofiles"
 	"github.com/autobrr/qui/internal/services/reannounce"
 	"github.com/autobrr/qui/internal/services/trackericons"
 	"github.com/autobrr/qui/internal/update"
@@ -77,6 +85,7 @@ multiple qBittorrent instances with support for 10k+ torrents.`,
 	rootCmd.AddCommand(RunVersionCommand(buildinfo.Version))
 	rootCmd.AddCommand(RunGenerateConfigCommand())
 	rootCmd.AddCommand(RunDBCommand())
+	rootCmd.AddCommand(RunBackupCommand())
 	rootCmd.AddCommand(RunCreateUserCommand())
 	rootCmd.AddCommand(RunChangePasswordCommand())
 	rootCmd.AddCommand(RunUpdateCommand())
@@ -535,7 +544,11 @@ func (app *Application) runServer() {
 	}
 
 	automationStore := models.NewAutomationStore(db)
+	automationRevisionStore := models.NewAutomationRevisionStore(db)
 	trackerCustomizationStore := models.NewTrackerCustomizationStore(db)
+	trackerRequirementStore := models.NewTrackerRequirementStore(db)
+	virtualInstanceStore := models.NewVirtualInstanceStore(db)
+	auditStore := models.NewAuditStore(db)
 	dashboardSettingsStore := models.NewDashboardSettingsStore(db)
 	themeSettingsStore := models.NewThemeSettingsStore(db)
 	filterViewStore := models.NewFilterViewStore(db)
@@ -565,6 +578,8 @@ func (app *Application) runServer() {
 		log.Fatal().Err(err).Msg("Failed to initialize client pool")
 	}
 	defer clientPool.Close()
+	// Transmission and Deluge instances are served by their own client pool.
+	clientPool.SetBackendPool(clients.NewPool(instanceStore))
 
 	// Initialize managers
 	syncManager := qbittorrent.NewSyncManager(clientPool, trackerCustomizationStore)
@@ -631,6 +646,11 @@ func (app *Application) runServer() {
 		notificationService.Start(notificationCtx)
 	}
 
+	auditService := audit.NewService(auditStore, log.Logger.With().Str("module", "audit").Logger())
+	auditCtx, auditCancel := context.WithCancel(context.Background())
+	defer auditCancel()
+	auditService.Start(auditCtx)
+
 	// activityHub fans qui-owned server events (reannounce, scans, cross-seed,
 	// backups, automations, indexer activity, etc.) onto the SSE stream so the
 	// frontend can stop polling those endpoints. Background services publish to it;
@@ -674,6 +694,17 @@ func (app *Application) runServer() {
 	reannounceService.SetActivityPublisher(activityHub)
 	automationService := automations.NewService(automations.DefaultConfig(), instanceStore, automationStore, automationActivityStore, trackerCustomizationStore, syncManager, notificationService, externalProgramService, crossSeedService)
 	automationService.SetActivityPublisher(activityHub)
+	automationService.SetArrImportProvider(arrService)
+	automationService.SetTrackerRequirementStore(trackerRequirementStore)
+	automationService.SetAutomationObservationStore(models.NewAutomationObservationStore(db))
+	transferSampleStore := models.NewTorrentTransferSampleStore(db)
+	automationService.SetTransferSampleStore(transferSampleStore)
+	transferStatsStore := models.NewTransferStatsStore(db)
+	transferSampler := qbittorrent.NewTransferSampler(syncManager, instanceStore, transferSampleStore, transferStatsStore)
+	preferenceProfileStore := models.NewPreferenceProfileStore(db)
+	preferenceProfileService := prefprofiles.NewService(preferenceProfileStore, instanceStore, syncManager, notificationService)
+	bulkJobManager := bulkjobs.NewManager(syncManager)
+	bulkJobManager.SetActivityPublisher(activityHub)
 
 	orphanScanStore := models.NewOrphanScanStore(db)
 	orphanScanService := orphanscan.NewService(orphanscan.DefaultConfig(), instanceStore, orphanScanStore, syncManager, notificationService)
@@ -685,13 +716,17 @@ func (app *Application) runServer() {
 
 	syncManager.SetTorrentCompletionHandler(func(ctx context.Context, instanceID int, torrent qbt.Torrent) {
 		crossSeedService.HandleTorrentCompletion(ctx, instanceID, torrent)
+		automationService.HandleTorrentCompleted(ctx, instanceID, torrent)
 		notificationService.Notify(ctx, buildTorrentCompletedEvent(syncManager, instanceID, torrent))
 	})
 
 	syncManager.SetTorrentAddedHandler(func(ctx context.Context, instanceID int, torrent qbt.Torrent) {
+		automationService.HandleTorrentAdded(ctx, instanceID, torrent)
 		notifyTorrentAddedWithDelay(ctx, syncManager, notificationService, instanceID, torrent)
 	})
 
+	syncManager.SetTorrentStateChangeHandler(automationService.HandleTorrentStateChange)
+
 	automationCtx, automationCancel := context.WithCancel(context.Background())
 	defer func() {
 		automationCancel()
@@ -706,6 +741,18 @@ func (app *Application) runServer() {
 	defer automationsCancel()
 	automationService.Start(automationsCtx)
 
+	transferSamplerCtx, transferSamplerCancel := context.WithCancel(context.Background())
+	defer transferSamplerCancel()
+	transferSampler.Start(transferSamplerCtx)
+
+	preferenceProfileCtx, preferenceProfileCancel := context.WithCancel(context.Background())
+	defer preferenceProfileCancel()
+	preferenceProfileService.Start(preferenceProfileCtx)
+
+	bulkJobCtx, bulkJobCancel := context.WithCancel(context.Background())
+	defer bulkJobCancel()
+	bulkJobManager.Start(bulkJobCtx)
+
 	orphanScanCtx, orphanScanCancel := context.WithCancel(context.Background())
 	defer orphanScanCancel()
 	orphanScanService.Start(orphanScanCtx)
@@ -717,11 +764,23 @@ func (app *Application) runServer() {
 	}
 
 	backupStore := models.NewBackupStore(db)
+	if err := backupStore.SetEncryptionKey(cfg.GetEncryptionKey()); err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize backup store")
+	}
 	backupService := backups.NewService(backupStore, syncManager, jackettService, backups.Config{DataDir: cfg.GetDataDir(), BackupDir: cfg.GetBackupDir()}, notificationService)
 	backupService.SetActivityPublisher(activityHub)
+	backupService.SetFilesystem(fsops.NewPool(instanceStore, localfs.NewBackend()))
 	backupService.Start(context.Background())
 	defer backupService.Stop()
 
+	configBackupScheduler := configbackup.NewScheduler(db, cfg.GetEncryptionKey(), cfg.GetConfigBackupDir(), buildinfo.Version, configBackupSchedule(cfg.Config))
+	cfg.RegisterReloadListener(func(conf *domain.Config) {
+		configBackupScheduler.SetSchedule(configBackupSchedule(conf))
+	})
+	configBackupCtx, cancelConfigBackup := context.WithCancel(context.Background())
+	defer cancelConfigBackup()
+	configBackupScheduler.Start(configBackupCtx)
+
 	updateService := update.NewService(log.Logger, cfg.Config.CheckForUpdates, buildinfo.Version, buildinfo.UserAgent)
 	cfg.RegisterReloadListener(func(conf *domain.Config) {
 		updateService.SetEnabled(conf.CheckForUpdates)
@@ -750,6 +809,9 @@ func (app *Application) runServer() {
 					Msg("Skipping startup connection for disabled instance")
 				continue
 			}
+			if !instance.IsQBittorrent() {
+				continue
+			}
 
 			go func(instanceID int) {
 				// Use separate context for each connection attempt with longer timeout
@@ -799,9 +861,18 @@ func (app *Application) runServer() {
 		JackettService:                   jackettService,
 		TorznabIndexerStore:              torznabIndexerStore,
 		AutomationStore:                  automationStore,
+		AutomationRevisionStore:          automationRevisionStore,
 		AutomationActivityStore:          automationActivityStore,
 		AutomationService:                automationService,
 		TrackerCustomizationStore:        trackerCustomizationStore,
+		TrackerRequirementStore:          trackerRequirementStore,
+		VirtualInstanceStore:             virtualInstanceStore,
+		AuditStore:                       auditStore,
+		TransferStatsStore:               transferStatsStore,
+		PreferenceProfileStore:           preferenceProfileStore,
+		PreferenceProfileService:         preferenceProfileService,
+		BulkJobManager:                   bulkJobManager,
+		AuditService:                     auditService,
 		DashboardSettingsStore:           dashboardSettingsStore,
 		ThemeSettingsStore:               themeSettingsStore,
 		FilterViewStore:                  filterViewStore,
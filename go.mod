@@ -3,6 +3,7 @@ module github.com/autobrr/qui
 go 1.27.0
 
 require (
+	filippo.io/age v1.3.1
 	github.com/CAFxX/httpcompression v0.0.9
 	github.com/Hellseher/go-shellquote v0.1.0
 	github.com/Masterminds/semver/v3 v3.5.0
@@ -28,7 +29,9 @@ require (
 	github.com/moistari/rls v0.6.0
 	github.com/nicholas-fedor/shoutrrr v0.16.3
 	github.com/pkg/errors v0.9.1
+	github.com/pkg/sftp v1.13.10
 	github.com/prometheus/client_golang v1.24.1
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/rs/cors v1.11.1
 	github.com/rs/zerolog v1.35.1
 	github.com/spf13/cobra v1.10.2
@@ -52,6 +55,7 @@ require (
 
 require (
 	code.gitea.io/sdk/gitea v0.23.2 // indirect
+	filippo.io/hpke v0.4.0 // indirect
 	github.com/42wim/httpsig v1.2.4 // indirect
 	github.com/Masterminds/semver v1.5.0 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
@@ -72,6 +76,7 @@ require (
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
 	github.com/jackc/puddle/v2 v2.2.2 // indirect
+	github.com/kr/fs v0.1.0 // indirect
 	github.com/kylelemons/godebug v1.1.0 // indirect
 	github.com/mattn/go-colorable v0.1.15 // indirect
 	github.com/mattn/go-isatty v0.0.24 // indirect
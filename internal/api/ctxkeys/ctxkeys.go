@@ -8,4 +8,6 @@ type Key int
 
 const (
 	Username Key = iota
+	// APIKeyName is the name of the qui API key that authenticated the request.
+	APIKeyName
 )
@@ -0,0 +1,206 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/audit"
+)
+
+type AuditHandler struct {
+	store   *models.AuditStore
+	service *audit.Service
+}
+
+func NewAuditHandler(store *models.AuditStore, service *audit.Service) *AuditHandler {
+	return &AuditHandler{
+		store:   store,
+		service: service,
+	}
+}
+
+// List returns audit entries matching the query filters, newest first.
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, err := h.store.List(r.Context(), filter)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list audit entries")
+		RespondError(w, http.StatusInternalServerError, "Failed to load audit log")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, entries)
+}
+
+// Export downloads every audit entry matching the query filters as CSV or JSON.
+func (h *AuditHandler) Export(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := strings.ToLower(query.Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		RespondError(w, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+
+	filter, err := parseAuditFilter(query)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries := []*models.AuditEntry{}
+	filter.Limit = models.MaxAuditListLimit
+	for filter.Offset = 0; ; filter.Offset += filter.Limit {
+		page, err := h.store.List(r.Context(), filter)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to export audit entries")
+			RespondError(w, http.StatusInternalServerError, "Failed to export audit log")
+			return
+		}
+		entries = append(entries, page...)
+		if len(page) < filter.Limit {
+			break
+		}
+	}
+
+	filename := fmt.Sprintf("qui-audit-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(entries); err != nil {
+			log.Error().Err(err).Msg("failed to write audit export")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"id", "created_at", "source", "actor_type", "actor", "instance_id", "method", "endpoint", "hashes", "status", "success"})
+	for _, entry := range entries {
+		instanceID := ""
+		if entry.InstanceID != nil {
+			instanceID = strconv.Itoa(*entry.InstanceID)
+		}
+		_ = writer.Write([]string{
+			strconv.Itoa(entry.ID),
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			entry.Source,
+			entry.ActorType,
+			entry.Actor,
+			instanceID,
+			entry.Method,
+			entry.Endpoint,
+			strings.Join(entry.Hashes, "|"),
+			strconv.Itoa(entry.Status),
+			strconv.FormatBool(entry.Success),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Error().Err(err).Msg("failed to write audit export")
+	}
+}
+
+func (h *AuditHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.store.GetSettings(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load audit settings")
+		RespondError(w, http.StatusInternalServerError, "Failed to load audit settings")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, settings)
+}
+
+func (h *AuditHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var settings models.AuditSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := settings.Validate(); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid audit settings: "+err.Error())
+		return
+	}
+
+	var updated *models.AuditSettings
+	var err error
+	if h.service != nil {
+		updated, err = h.service.UpdateSettings(r.Context(), &settings)
+	} else {
+		updated, err = h.store.UpdateSettings(r.Context(), &settings)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to update audit settings")
+		RespondError(w, http.StatusInternalServerError, "Failed to update audit settings")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, updated)
+}
+
+// parseAuditFilter reads the list filters from query parameters. Times are RFC3339.
+func parseAuditFilter(query url.Values) (models.AuditFilter, error) {
+	filter := models.AuditFilter{
+		Source:    query.Get("source"),
+		ActorType: query.Get("actorType"),
+		Actor:     query.Get("actor"),
+		Endpoint:  query.Get("endpoint"),
+		Hash:      query.Get("hash"),
+	}
+
+	for name, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if raw := query.Get(name); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s: expected RFC3339 time", name)
+			}
+			*dst = &parsed
+		}
+	}
+	if raw := query.Get("instanceId"); raw != "" {
+		instanceID, err := strconv.Atoi(raw)
+		if err != nil {
+			return filter, errors.New("invalid instanceId")
+		}
+		filter.InstanceID = &instanceID
+	}
+	if raw := query.Get("success"); raw != "" {
+		success, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, errors.New("invalid success")
+		}
+		filter.Success = &success
+	}
+	for name, dst := range map[string]*int{"limit": &filter.Limit, "offset": &filter.Offset} {
+		if raw := query.Get(name); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 0 {
+				return filter, fmt.Errorf("invalid %s", name)
+			}
+			*dst = value
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,357 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/api/ctxkeys"
+	"github.com/autobrr/qui/internal/models"
+)
+
+// automationActor names who made a change for the revision history.
+func automationActor(r *http.Request) string {
+	if username, ok := r.Context().Value(ctxkeys.Username).(string); ok && username != "" {
+		return username
+	}
+	return "api key"
+}
+
+// recordRevision stores a revision of automation. Failures are logged but never
+// fail the request that saved the rule.
+func (h *AutomationHandler) recordRevision(r *http.Request, automation *models.Automation, action string) {
+	if h.revisionStore == nil || automation == nil {
+		return
+	}
+	if _, err := h.revisionStore.Record(r.Context(), automation, action, automationActor(r)); err != nil {
+		log.Warn().Err(err).Int("instanceID", automation.InstanceID).Int("automationID", automation.ID).Msg("automations: failed to record revision")
+	}
+}
+
+// payloadFromModel converts a stored rule back into a payload so it can go
+// through the same validation as user input.
+func payloadFromModel(automation *models.Automation) *AutomationPayload {
+	enabled := automation.Enabled
+	dryRun := automation.DryRun
+	notify := automation.Notify
+	sortOrder := automation.SortOrder
+	return &AutomationPayload{
+		Name:              automation.Name,
+		TrackerPattern:    automation.TrackerPattern,
+		TrackerDomains:    automation.TrackerDomains,
+		Enabled:           &enabled,
+		DryRun:            &dryRun,
+		Notify:            &notify,
+		SortOrder:         &sortOrder,
+		IntervalSeconds:   automation.IntervalSeconds,
+		TriggerMode:       automation.TriggerMode,
+		Schedule:          automation.Schedule,
+		Targets:           automation.Targets,
+		InstanceOverrides: automation.InstanceOverrides,
+		Conditions:        automation.Conditions,
+		FreeSpaceSource:   automation.FreeSpaceSource,
+		SortingConfig:     automation.SortingConfig,
+	}
+}
+
+func parseRuleID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	ruleID, err := strconv.Atoi(chi.URLParam(r, "ruleID"))
+	if err != nil || ruleID <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid automation ID")
+		return 0, false
+	}
+	return ruleID, true
+}
+
+func (h *AutomationHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+	ruleID, ok := parseRuleID(w, r)
+	if !ok {
+		return
+	}
+
+	if h.revisionStore == nil {
+		RespondError(w, http.StatusServiceUnavailable, "Revision history not available")
+		return
+	}
+
+	revisions, err := h.revisionStore.ListByAutomation(r.Context(), instanceID, ruleID)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Int("automationID", ruleID).Msg("failed to list automation revisions")
+		RespondError(w, http.StatusInternalServerError, "Failed to load revisions")
+		return
+	}
+	if revisions == nil {
+		revisions = []*models.AutomationRevision{}
+	}
+
+	RespondJSON(w, http.StatusOK, revisions)
+}
+
+// RollbackRevision restores a rule to the state stored in a previous revision.
+// The rollback itself is recorded as a new revision, so it can be undone too.
+func (h *AutomationHandler) RollbackRevision(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+	ruleID, ok := parseRuleID(w, r)
+	if !ok {
+		return
+	}
+	revisionID, err := strconv.Atoi(chi.URLParam(r, "revisionID"))
+	if err != nil || revisionID <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid revision ID")
+		return
+	}
+
+	if h.revisionStore == nil {
+		RespondError(w, http.StatusServiceUnavailable, "Revision history not available")
+		return
+	}
+
+	current, err := h.store.Get(r.Context(), instanceID, ruleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Automation not found")
+			return
+		}
+		log.Error().Err(err).Int("instanceID", instanceID).Int("automationID", ruleID).Msg("failed to load automation for rollback")
+		RespondError(w, http.StatusInternalServerError, "Failed to load automation")
+		return
+	}
+
+	revision, err := h.revisionStore.Get(r.Context(), instanceID, ruleID, revisionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Revision not found")
+			return
+		}
+		log.Error().Err(err).Int("instanceID", instanceID).Int("automationID", ruleID).Int("revisionID", revisionID).Msg("failed to load automation revision")
+		RespondError(w, http.StatusInternalServerError, "Failed to load revision")
+		return
+	}
+
+	payload := payloadFromModel(revision.Snapshot)
+	// Rule order is managed separately from rule content.
+	payload.SortOrder = &current.SortOrder
+
+	if status, msg, err := h.validatePayload(r.Context(), instanceID, payload); err != nil {
+		RespondError(w, status, fmt.Sprintf("Revision %d cannot be restored: %s", revision.Revision, msg))
+		return
+	}
+
+	automation, err := h.store.Update(r.Context(), payload.toModel(instanceID, ruleID))
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Int("automationID", ruleID).Int("revisionID", revisionID).Msg("failed to roll back automation")
+		RespondError(w, http.StatusInternalServerError, "Failed to roll back automation")
+		return
+	}
+
+	h.recordRevision(r, automation, models.AutomationRevisionRolledBack)
+
+	RespondJSON(w, http.StatusOK, automation)
+}
+
+func (h *AutomationHandler) referenceIndex(r *http.Request) (*models.AutomationReferenceIndex, error) {
+	if h.instanceStore == nil {
+		return nil, errors.New("instance store unavailable")
+	}
+	instances, err := h.instanceStore.List(r.Context())
+	if err != nil {
+		return nil, fmt.Errorf("list instances: %w", err)
+	}
+	var programs []*models.ExternalProgram
+	if h.externalProgramStore != nil {
+		programs, err = h.externalProgramStore.List(r.Context())
+		if err != nil {
+			return nil, fmt.Errorf("list external programs: %w", err)
+		}
+	}
+	return models.NewAutomationReferenceIndex(instances, programs), nil
+}
+
+// Export returns the instance's rules in the portable export format.
+func (h *AutomationHandler) Export(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	rules, err := h.store.ListByInstance(r.Context(), instanceID)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("failed to list automations for export")
+		RespondError(w, http.StatusInternalServerError, "Failed to load automations")
+		return
+	}
+
+	index, err := h.referenceIndex(r)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("automations: failed to build export references")
+		RespondError(w, http.StatusInternalServerError, "Failed to export automations")
+		return
+	}
+
+	export, err := models.ExportAutomations(rules, index)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("automations: failed to export rules")
+		RespondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to export automations: %v", err))
+		return
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		RespondError(w, http.StatusInternalServerError, "Failed to export automations")
+		return
+	}
+
+	filename := fmt.Sprintf("qui-automations-%d-%s.json", instanceID, time.Now().UTC().Format("20060102"))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(append(data, '\n'))
+}
+
+// AutomationImportResult summarizes an import.
+type AutomationImportResult struct {
+	Created []*models.Automation `json:"created"`
+	Updated []*models.Automation `json:"updated"`
+}
+
+// AutomationImportError describes why one rule of an import was rejected.
+type AutomationImportError struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Import creates or updates rules from an export document. Rules whose name
+// matches an existing rule on the instance (case-insensitive) replace it; all
+// others are created. Every rule is validated before anything is written, so a
+// file with an invalid rule changes nothing.
+func (h *AutomationHandler) Import(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	var document models.AutomationExport
+	if err := json.NewDecoder(r.Body).Decode(&document); err != nil {
+		log.Warn().Err(err).Int("instanceID", instanceID).Msg("automations: failed to decode import payload")
+		RespondError(w, http.StatusBadRequest, "Invalid import file")
+		return
+	}
+	if document.Version <= 0 || document.Version > models.AutomationExportVersion {
+		RespondError(w, http.StatusBadRequest, fmt.Sprintf("Unsupported export version %d", document.Version))
+		return
+	}
+	if len(document.Rules) == 0 {
+		RespondError(w, http.StatusBadRequest, "Import file contains no rules")
+		return
+	}
+
+	existing, err := h.store.ListByInstance(r.Context(), instanceID)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("failed to list automations for import")
+		RespondError(w, http.StatusInternalServerError, "Failed to load automations")
+		return
+	}
+	existingByName := make(map[string]*models.Automation, len(existing))
+	for _, rule := range existing {
+		existingByName[strings.ToLower(rule.Name)] = rule
+	}
+
+	index, err := h.referenceIndex(r)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("automations: failed to build import references")
+		RespondError(w, http.StatusInternalServerError, "Failed to import automations")
+		return
+	}
+
+	type plannedImport struct {
+		automation *models.Automation
+		update     bool
+	}
+	planned := make([]plannedImport, 0, len(document.Rules))
+	var importErrors []AutomationImportError
+	seen := make(map[string]struct{}, len(document.Rules))
+
+	for _, rule := range document.Rules {
+		if rule == nil {
+			continue
+		}
+		key := strings.ToLower(rule.Name)
+		if _, dup := seen[key]; dup {
+			importErrors = append(importErrors, AutomationImportError{Rule: rule.Name, Message: "duplicate rule name in import file"})
+			continue
+		}
+		seen[key] = struct{}{}
+
+		automation, err := rule.ToAutomation(instanceID, index)
+		if err != nil {
+			importErrors = append(importErrors, AutomationImportError{Rule: rule.Name, Message: err.Error()})
+			continue
+		}
+
+		payload := payloadFromModel(automation)
+		payload.SortOrder = nil
+		target, update := existingByName[key]
+		if update {
+			payload.SortOrder = &target.SortOrder
+		}
+		if _, msg, err := h.validatePayload(r.Context(), instanceID, payload); err != nil {
+			importErrors = append(importErrors, AutomationImportError{Rule: rule.Name, Message: msg})
+			continue
+		}
+
+		ruleID := 0
+		if update {
+			ruleID = target.ID
+		}
+		planned = append(planned, plannedImport{automation: payload.toModel(instanceID, ruleID), update: update})
+	}
+
+	if len(importErrors) > 0 {
+		RespondJSON(w, http.StatusBadRequest, map[string]any{
+			"error":  "Some rules could not be imported; nothing was changed",
+			"errors": importErrors,
+		})
+		return
+	}
+
+	result := AutomationImportResult{Created: []*models.Automation{}, Updated: []*models.Automation{}}
+	for _, item := range planned {
+		var saved *models.Automation
+		if item.update {
+			saved, err = h.store.Update(r.Context(), item.automation)
+		} else {
+			saved, err = h.store.Create(r.Context(), item.automation)
+		}
+		if err != nil {
+			log.Error().Err(err).Int("instanceID", instanceID).Str("rule", item.automation.Name).Msg("automations: failed to import rule")
+			RespondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to import rule %q", item.automation.Name))
+			return
+		}
+		h.recordRevision(r, saved, models.AutomationRevisionImported)
+		if item.update {
+			result.Updated = append(result.Updated, saved)
+		} else {
+			result.Created = append(result.Created, saved)
+		}
+	}
+
+	RespondJSON(w, http.StatusOK, result)
+}
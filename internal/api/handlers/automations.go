@@ -25,15 +25,17 @@ import (
 
 type AutomationHandler struct {
 	store                *models.AutomationStore
+	revisionStore        *models.AutomationRevisionStore
 	activityStore        *models.AutomationActivityStore
 	instanceStore        *models.InstanceStore
 	externalProgramStore *models.ExternalProgramStore
 	service              *automations.Service
 }
 
-func NewAutomationHandler(store *models.AutomationStore, activityStore *models.AutomationActivityStore, instanceStore *models.InstanceStore, externalProgramStore *models.ExternalProgramStore, service *automations.Service) *AutomationHandler {
+func NewAutomationHandler(store *models.AutomationStore, revisionStore *models.AutomationRevisionStore, activityStore *models.AutomationActivityStore, instanceStore *models.InstanceStore, externalProgramStore *models.ExternalProgramStore, service *automations.Service) *AutomationHandler {
 	return &AutomationHandler{
 		store:                store,
+		revisionStore:        revisionStore,
 		activityStore:        activityStore,
 		instanceStore:        instanceStore,
 		externalProgramStore: externalProgramStore,
@@ -42,20 +44,24 @@ func NewAutomationHandler(store *models.AutomationStore, activityStore *models.A
 }
 
 type AutomationPayload struct {
-	Name            string                   `json:"name"`
-	TrackerPattern  string                   `json:"trackerPattern"`
-	TrackerDomains  []string                 `json:"trackerDomains"`
-	Enabled         *bool                    `json:"enabled"`
-	DryRun          *bool                    `json:"dryRun"`
-	Notify          *bool                    `json:"notify"`
-	SortOrder       *int                     `json:"sortOrder"`
-	IntervalSeconds *int                     `json:"intervalSeconds,omitempty"` // nil = use DefaultRuleInterval (15m)
-	Conditions      *models.ActionConditions `json:"conditions"`
-	FreeSpaceSource *models.FreeSpaceSource  `json:"freeSpaceSource,omitempty"` // nil = default qBittorrent free space
-	SortingConfig   *models.SortingConfig    `json:"sortingConfig,omitempty"`   // nil = default (oldest first)
-	PreviewLimit    *int                     `json:"previewLimit"`
-	PreviewOffset   *int                     `json:"previewOffset"`
-	PreviewView     string                   `json:"previewView,omitempty"` // "needed" (default) or "eligible"
+	Name              string                                     `json:"name"`
+	TrackerPattern    string                                     `json:"trackerPattern"`
+	TrackerDomains    []string                                   `json:"trackerDomains"`
+	Enabled           *bool                                      `json:"enabled"`
+	DryRun            *bool                                      `json:"dryRun"`
+	Notify            *bool                                      `json:"notify"`
+	SortOrder         *int                                       `json:"sortOrder"`
+	IntervalSeconds   *int                                       `json:"intervalSeconds,omitempty"`   // nil = use DefaultRuleInterval (15m)
+	TriggerMode       models.AutomationTrigger                   `json:"triggerMode,omitempty"`       // "" = interval
+	Schedule          *models.AutomationSchedule                 `json:"schedule,omitempty"`          // nil = run on intervalSeconds at any time
+	Targets           *models.AutomationTargets                  `json:"targets,omitempty"`           // nil = this instance only
+	InstanceOverrides map[int]*models.AutomationInstanceOverride `json:"instanceOverrides,omitempty"` // per-instance path overrides for shared rules
+	Conditions        *models.ActionConditions                   `json:"conditions"`
+	FreeSpaceSource   *models.FreeSpaceSource                    `json:"freeSpaceSource,omitempty"` // nil = default qBittorrent free space
+	SortingConfig     *models.SortingConfig                      `json:"sortingConfig,omitempty"`   // nil = default (oldest first)
+	PreviewLimit      *int                                       `json:"previewLimit"`
+	PreviewOffset     *int                                       `json:"previewOffset"`
+	PreviewView       string                                     `json:"previewView,omitempty"` // "needed" (default) or "eligible"
 }
 
 type AutomationDryRunResult struct {
@@ -87,6 +93,14 @@ func (p *AutomationPayload) toModel(instanceID int, id int) *models.Automation {
 		DryRun:          false,
 		Notify:          true,
 		IntervalSeconds: p.IntervalSeconds,
+		TriggerMode:     models.NormalizeAutomationTrigger(p.TriggerMode),
+	}
+	if !p.Schedule.IsEmpty() {
+		automation.Schedule = p.Schedule
+	}
+	if !p.Targets.IsEmpty() {
+		automation.Targets = p.Targets
+		automation.InstanceOverrides = p.InstanceOverrides
 	}
 	if p.Enabled != nil {
 		automation.Enabled = *p.Enabled
@@ -116,9 +130,32 @@ func (h *AutomationHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.service.AnnotateNextRuns(instanceID, automations)
+
 	RespondJSON(w, http.StatusOK, automations)
 }
 
+// ListShared returns shared rules owned by other instances that also run on this instance.
+// They are read-only here; edit them through their owning instance.
+func (h *AutomationHandler) ListShared(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+
+	shared, err := h.store.ListSharedWith(r.Context(), instanceID)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("failed to list shared automations")
+		RespondError(w, http.StatusInternalServerError, "Failed to load shared automations")
+		return
+	}
+	if shared == nil {
+		shared = []*models.Automation{}
+	}
+
+	RespondJSON(w, http.StatusOK, shared)
+}
+
 func (h *AutomationHandler) Create(w http.ResponseWriter, r *http.Request) {
 	instanceID, err := parseInstanceID(w, r)
 	if err != nil {
@@ -144,6 +181,8 @@ func (h *AutomationHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.recordRevision(r, automation, models.AutomationRevisionCreated)
+
 	RespondJSON(w, http.StatusCreated, automation)
 }
 
@@ -184,6 +223,8 @@ func (h *AutomationHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.recordRevision(r, automation, models.AutomationRevisionUpdated)
+
 	RespondJSON(w, http.StatusOK, automation)
 }
 
@@ -361,6 +402,16 @@ func (h *AutomationHandler) validatePayload(ctx context.Context, instanceID int,
 		}
 	}
 
+	// Validate webhook action
+	if payload.Conditions.Webhook != nil && payload.Conditions.Webhook.Enabled {
+		if err := payload.Conditions.Webhook.Validate(); err != nil {
+			return http.StatusBadRequest, "Invalid webhook action: " + err.Error(), err
+		}
+		if err := automations.ValidateWebhookTemplate(payload.Conditions.Webhook.BodyTemplate); err != nil {
+			return http.StatusBadRequest, "Invalid webhook body template: " + err.Error(), err
+		}
+	}
+
 	// Validate delete is standalone - it cannot be combined with any other action
 	hasDelete := payload.Conditions.Delete != nil && payload.Conditions.Delete.Enabled
 	if hasDelete {
@@ -378,7 +429,8 @@ func (h *AutomationHandler) validatePayload(ctx context.Context, instanceID int,
 			(payload.Conditions.Move != nil && payload.Conditions.Move.Enabled) ||
 			(payload.Conditions.ExternalProgram != nil && payload.Conditions.ExternalProgram.Enabled) ||
 			(payload.Conditions.AutoManagement != nil) ||
-			(payload.Conditions.ExportToInstance != nil && payload.Conditions.ExportToInstance.Enabled)
+			(payload.Conditions.ExportToInstance != nil && payload.Conditions.ExportToInstance.Enabled) ||
+			(payload.Conditions.Webhook != nil && payload.Conditions.Webhook.Enabled)
 		if hasOtherAction {
 			return http.StatusBadRequest, "Delete action cannot be combined with other actions", errors.New("delete must be standalone")
 		}
@@ -389,6 +441,26 @@ func (h *AutomationHandler) validatePayload(ctx context.Context, instanceID int,
 		return http.StatusBadRequest, "intervalSeconds must be at least 60", errors.New("interval too short")
 	}
 
+	// Validate trigger mode
+	if payload.TriggerMode != "" && !payload.TriggerMode.IsValid() {
+		return http.StatusBadRequest, fmt.Sprintf("Invalid triggerMode %q (expected interval, onAdded, onCompleted or onStateChange)", payload.TriggerMode), errors.New("invalid trigger mode")
+	}
+
+	// Validate schedule (cron expression, time zone, active window)
+	if payload.Schedule != nil {
+		if err := payload.Schedule.Validate(); err != nil {
+			return http.StatusBadRequest, fmt.Sprintf("Invalid schedule: %v", err), err
+		}
+		if strings.TrimSpace(payload.Schedule.Cron) != "" && models.NormalizeAutomationTrigger(payload.TriggerMode).IsEvent() {
+			return http.StatusBadRequest, "A cron schedule can only be used with the interval trigger", errors.New("cron requires interval trigger")
+		}
+	}
+
+	// Validate shared rule targets and per-instance overrides
+	if status, msg, err := h.validateTargetsPayload(ctx, instanceID, payload); err != nil {
+		return status, msg, err
+	}
+
 	// Validate sorting config
 	if payload.SortingConfig != nil {
 		if err := payload.SortingConfig.Validate(); err != nil {
@@ -405,6 +477,11 @@ func (h *AutomationHandler) validatePayload(ctx context.Context, instanceID int,
 			msg := fmt.Sprintf("Invalid regex pattern in %s: %s (Go/RE2 does not support Perl features like lookahead/lookbehind)", firstErr.Field, firstErr.Message)
 			return http.StatusBadRequest, msg, errors.New("invalid regex")
 		}
+		if exprErrs := collectConditionExprErrors(payload.Conditions); len(exprErrs) > 0 {
+			firstErr := exprErrs[0]
+			msg := fmt.Sprintf("Invalid expression at %s: %s", firstErr.Path, firstErr.Message)
+			return http.StatusBadRequest, msg, errors.New("invalid expression")
+		}
 	}
 
 	// Validate fields that require local filesystem access
@@ -515,7 +592,8 @@ func conditionsUseField(conditions *models.ActionConditions, field automations.C
 		(c.Move != nil && check(c.Move.Enabled, c.Move.Condition)) ||
 		(c.ExternalProgram != nil && check(c.ExternalProgram.Enabled, c.ExternalProgram.Condition)) ||
 		(c.AutoManagement != nil && automations.ConditionUsesField(c.AutoManagement.Condition, field)) ||
-		(c.ExportToInstance != nil && check(c.ExportToInstance.Enabled, c.ExportToInstance.Condition))
+		(c.ExportToInstance != nil && check(c.ExportToInstance.Enabled, c.ExportToInstance.Condition)) ||
+		(c.Webhook != nil && check(c.Webhook.Enabled, c.Webhook.Condition))
 }
 
 func anyEnabledTagActionUsesField(actions []*models.TagAction, field automations.ConditionField) bool {
@@ -666,6 +744,9 @@ func conditionTreesForValidation(conditions *models.ActionConditions) []*models.
 	if conditions.ExportToInstance != nil && conditions.ExportToInstance.Enabled {
 		trees = append(trees, conditions.ExportToInstance.Condition)
 	}
+	if conditions.Webhook != nil && conditions.Webhook.Enabled {
+		trees = append(trees, conditions.Webhook.Condition)
+	}
 	return trees
 }
 
@@ -763,6 +844,55 @@ const (
 	errMsgWindowsPathSourceNotSupported = "Path-based free space source is not supported on Windows. Use the default qBittorrent free space instead."
 )
 
+// validateTargetsPayload checks that every target instance exists, that overrides
+// only reference instances the rule runs on, and that export actions never
+// target an instance the rule itself runs on.
+func (h *AutomationHandler) validateTargetsPayload(ctx context.Context, instanceID int, payload *AutomationPayload) (status int, msg string, err error) {
+	if payload.Targets.IsEmpty() {
+		if len(payload.InstanceOverrides) > 0 {
+			return http.StatusBadRequest, "Instance overrides require the rule to target other instances", errors.New("overrides without targets")
+		}
+		return 0, "", nil
+	}
+	payload.Targets.Normalize(instanceID)
+
+	export := payload.Conditions.ExportToInstance
+	if export != nil && export.Enabled && payload.Targets.Includes(export.TargetInstanceID) {
+		return http.StatusBadRequest, "A shared rule cannot run on its own export target instance", errors.New("shared rule targets export instance")
+	}
+
+	if h.instanceStore == nil {
+		return http.StatusInternalServerError, "Instance store not configured", errors.New("instance store unavailable")
+	}
+	requiresLocalAccess := conditionsRequireLocalAccess(payload.Conditions)
+	for _, targetID := range payload.Targets.InstanceIDs {
+		instance, err := h.instanceStore.Get(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, models.ErrInstanceNotFound) {
+				return http.StatusBadRequest, fmt.Sprintf("Target instance %d not found", targetID), err
+			}
+			return http.StatusInternalServerError, "Failed to validate target instances", err
+		}
+		if requiresLocalAccess && !instance.HasLocalFilesystemAccess {
+			return http.StatusBadRequest, fmt.Sprintf("File conditions require Local Filesystem Access on every target instance (%s does not have it)", instance.Name), errors.New("local access required on target")
+		}
+	}
+
+	for overrideID, override := range payload.InstanceOverrides {
+		if overrideID != instanceID && !payload.Targets.Includes(overrideID) {
+			return http.StatusBadRequest, fmt.Sprintf("Instance override for instance %d which the rule does not target", overrideID), errors.New("override for untargeted instance")
+		}
+		if override != nil && override.FreeSpacePath != nil {
+			path := *override.FreeSpacePath
+			if !strings.HasPrefix(path, "/") || strings.Contains(path, "..") {
+				return http.StatusBadRequest, fmt.Sprintf("Free space path override for instance %d must be an absolute path without '..'", overrideID), errors.New("invalid free space path override")
+			}
+		}
+	}
+
+	return 0, "", nil
+}
+
 // conditionsUseFreeSpace checks if any enabled action condition uses FREE_SPACE field.
 func conditionsUseFreeSpace(conditions *models.ActionConditions) bool {
 	return conditionsUseField(conditions, automations.FieldFreeSpace)
@@ -1096,54 +1226,72 @@ func (h *AutomationHandler) ValidateRegex(w http.ResponseWriter, r *http.Request
 	})
 }
 
-// collectConditionRegexErrors extracts all regex validation errors from action conditions.
-func collectConditionRegexErrors(conditions *models.ActionConditions) []RegexValidationError {
+// conditionRoot is the top-level condition of one action together with its JSON pointer.
+type conditionRoot struct {
+	path string
+	cond *models.RuleCondition
+}
+
+// actionConditionRoots lists the condition tree of every configured action.
+func actionConditionRoots(conditions *models.ActionConditions) []conditionRoot {
 	if conditions == nil {
 		return nil
 	}
 
-	var result []RegexValidationError
+	var roots []conditionRoot
 
 	if conditions.SpeedLimits != nil {
-		validateConditionRegex(conditions.SpeedLimits.Condition, "/conditions/speedLimits/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/speedLimits/condition", conditions.SpeedLimits.Condition})
 	}
 	if conditions.ShareLimits != nil {
-		validateConditionRegex(conditions.ShareLimits.Condition, "/conditions/shareLimits/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/shareLimits/condition", conditions.ShareLimits.Condition})
 	}
 	if conditions.Pause != nil {
-		validateConditionRegex(conditions.Pause.Condition, "/conditions/pause/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/pause/condition", conditions.Pause.Condition})
 	}
 	if conditions.Resume != nil {
-		validateConditionRegex(conditions.Resume.Condition, "/conditions/resume/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/resume/condition", conditions.Resume.Condition})
 	}
 	if conditions.Recheck != nil {
-		validateConditionRegex(conditions.Recheck.Condition, "/conditions/recheck/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/recheck/condition", conditions.Recheck.Condition})
 	}
 	if conditions.Reannounce != nil {
-		validateConditionRegex(conditions.Reannounce.Condition, "/conditions/reannounce/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/reannounce/condition", conditions.Reannounce.Condition})
 	}
 	if conditions.Delete != nil {
-		validateConditionRegex(conditions.Delete.Condition, "/conditions/delete/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/delete/condition", conditions.Delete.Condition})
 	}
 	for idx, action := range conditions.TagActions() {
-		validateConditionRegex(action.Condition, fmt.Sprintf("/conditions/tags/%d/condition", idx), &result)
+		roots = append(roots, conditionRoot{fmt.Sprintf("/conditions/tags/%d/condition", idx), action.Condition})
 	}
 	if conditions.Category != nil {
-		validateConditionRegex(conditions.Category.Condition, "/conditions/category/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/category/condition", conditions.Category.Condition})
 	}
 	if conditions.Move != nil {
-		validateConditionRegex(conditions.Move.Condition, "/conditions/move/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/move/condition", conditions.Move.Condition})
 	}
 	if conditions.ExternalProgram != nil {
-		validateConditionRegex(conditions.ExternalProgram.Condition, "/conditions/externalProgram/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/externalProgram/condition", conditions.ExternalProgram.Condition})
 	}
 	if conditions.AutoManagement != nil {
-		validateConditionRegex(conditions.AutoManagement.Condition, "/conditions/autoManagement/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/autoManagement/condition", conditions.AutoManagement.Condition})
 	}
 	if conditions.ExportToInstance != nil {
-		validateConditionRegex(conditions.ExportToInstance.Condition, "/conditions/exportToInstance/condition", &result)
+		roots = append(roots, conditionRoot{"/conditions/exportToInstance/condition", conditions.ExportToInstance.Condition})
+	}
+	if conditions.Webhook != nil {
+		roots = append(roots, conditionRoot{"/conditions/webhook/condition", conditions.Webhook.Condition})
 	}
 
+	return roots
+}
+
+// collectConditionRegexErrors extracts all regex validation errors from action conditions.
+func collectConditionRegexErrors(conditions *models.ActionConditions) []RegexValidationError {
+	var result []RegexValidationError
+	for _, root := range actionConditionRoots(conditions) {
+		validateConditionRegex(root.cond, root.path, &result)
+	}
 	return result
 }
 
@@ -1153,8 +1301,8 @@ func validateConditionRegex(cond *models.RuleCondition, path string, errs *[]Reg
 		return
 	}
 
-	// Check if this condition uses regex
-	isRegex := cond.Regex || cond.Operator == models.OperatorMatches
+	// Check if this condition uses regex (EXPR values are expressions, never patterns)
+	isRegex := cond.Field != models.FieldExpr && (cond.Regex || cond.Operator == models.OperatorMatches)
 	if isRegex && cond.Value != "" {
 		if err := cond.CompileRegex(); err != nil {
 			*errs = append(*errs, RegexValidationError{
@@ -1172,3 +1320,40 @@ func validateConditionRegex(cond *models.RuleCondition, path string, errs *[]Reg
 		validateConditionRegex(child, fmt.Sprintf("%s/conditions/%d", path, i), errs)
 	}
 }
+
+// ExprValidationError represents an EXPR condition that failed to compile.
+type ExprValidationError struct {
+	Path    string `json:"path"`    // JSON pointer to the condition
+	Expr    string `json:"expr"`    // The invalid expression
+	Message string `json:"message"` // Error message from compilation
+}
+
+// collectConditionExprErrors compiles every EXPR condition in the action conditions.
+func collectConditionExprErrors(conditions *models.ActionConditions) []ExprValidationError {
+	var result []ExprValidationError
+	for _, root := range actionConditionRoots(conditions) {
+		validateConditionExpr(root.cond, root.path, &result)
+	}
+	return result
+}
+
+// validateConditionExpr recursively compiles EXPR conditions in a condition tree.
+func validateConditionExpr(cond *models.RuleCondition, path string, errs *[]ExprValidationError) {
+	if cond == nil {
+		return
+	}
+
+	if cond.Field == models.FieldExpr {
+		if err := automations.CompileExpr(cond.Value); err != nil {
+			*errs = append(*errs, ExprValidationError{
+				Path:    path,
+				Expr:    cond.Value,
+				Message: err.Error(),
+			})
+		}
+	}
+
+	for i, child := range cond.Conditions {
+		validateConditionExpr(child, fmt.Sprintf("%s/conditions/%d", path, i), errs)
+	}
+}
@@ -33,7 +33,7 @@ func TestAutomationDryRunNow(t *testing.T) {
 	}`
 
 	t.Run("returns 503 when service is unavailable", func(t *testing.T) {
-		handler := NewAutomationHandler(nil, nil, nil, nil, nil)
+		handler := NewAutomationHandler(nil, nil, nil, nil, nil, nil)
 		rec := httptest.NewRecorder()
 
 		handler.DryRunNow(rec, newRequest(validPayload))
@@ -42,7 +42,7 @@ func TestAutomationDryRunNow(t *testing.T) {
 	})
 
 	t.Run("returns 400 on invalid JSON payload", func(t *testing.T) {
-		handler := NewAutomationHandler(nil, nil, nil, nil, &automations.Service{})
+		handler := NewAutomationHandler(nil, nil, nil, nil, nil, &automations.Service{})
 		rec := httptest.NewRecorder()
 
 		handler.DryRunNow(rec, newRequest("{"))
@@ -51,7 +51,7 @@ func TestAutomationDryRunNow(t *testing.T) {
 	})
 
 	t.Run("runs dry-run and returns accepted status", func(t *testing.T) {
-		handler := NewAutomationHandler(nil, nil, nil, nil, &automations.Service{})
+		handler := NewAutomationHandler(nil, nil, nil, nil, nil, &automations.Service{})
 		rec := httptest.NewRecorder()
 
 		handler.DryRunNow(rec, newRequest(validPayload))
@@ -402,3 +402,105 @@ func TestValidateConditionGroupingConfig(t *testing.T) {
 		require.Contains(t, msg, "does_not_exist")
 	})
 }
+
+func TestValidatePayloadTriggerMode(t *testing.T) {
+	handler := NewAutomationHandler(nil, nil, nil, nil, nil, nil)
+
+	newPayload := func(trigger models.AutomationTrigger) *AutomationPayload {
+		return &AutomationPayload{
+			Name:           "Trigger test",
+			TrackerPattern: "*",
+			TriggerMode:    trigger,
+			Conditions: &models.ActionConditions{
+				SchemaVersion: "1",
+				Pause:         &models.PauseAction{Enabled: true},
+			},
+		}
+	}
+
+	for _, trigger := range []models.AutomationTrigger{"", models.TriggerInterval, models.TriggerOnAdded, models.TriggerOnCompleted, models.TriggerOnStateChange} {
+		status, msg, err := handler.validatePayload(context.Background(), 1, newPayload(trigger))
+		require.NoError(t, err, "trigger %q", trigger)
+		require.Zero(t, status)
+		require.Empty(t, msg)
+	}
+
+	status, msg, err := handler.validatePayload(context.Background(), 1, newPayload("onDeleted"))
+	require.Error(t, err)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, msg, "triggerMode")
+
+	require.Equal(t, models.TriggerInterval, newPayload("").toModel(1, 0).TriggerMode)
+	require.Equal(t, models.TriggerOnCompleted, newPayload(models.TriggerOnCompleted).toModel(1, 0).TriggerMode)
+}
+
+func TestValidatePayloadExprConditions(t *testing.T) {
+	handler := NewAutomationHandler(nil, nil, nil, nil, nil, nil)
+
+	newPayload := func(source string) *AutomationPayload {
+		return &AutomationPayload{
+			Name:           "Expr test",
+			TrackerPattern: "*",
+			Conditions: &models.ActionConditions{
+				SchemaVersion: "1",
+				Pause: &models.PauseAction{
+					Enabled: true,
+					Condition: &models.RuleCondition{
+						Operator: models.OperatorAnd,
+						Conditions: []*models.RuleCondition{
+							{Field: models.FieldRatio, Operator: models.OperatorGreaterThan, Value: "1"},
+							{Field: models.FieldExpr, Operator: models.OperatorEqual, Value: source},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	status, msg, err := handler.validatePayload(context.Background(), 1, newPayload(`Ratio > 2 && RlsSource == "WEB-DL"`))
+	require.NoError(t, err)
+	require.Zero(t, status)
+	require.Empty(t, msg)
+
+	status, msg, err = handler.validatePayload(context.Background(), 1, newPayload(`Ratio >`))
+	require.Error(t, err)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, msg, "/conditions/pause/condition/conditions/1")
+
+	status, _, err = handler.validatePayload(context.Background(), 1, newPayload(`UnknownField == 1`))
+	require.Error(t, err)
+	require.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestValidatePayloadSharedTargets(t *testing.T) {
+	handler := NewAutomationHandler(nil, nil, nil, nil, nil, nil)
+
+	payload := &AutomationPayload{
+		Name:           "Shared test",
+		TrackerPattern: "*",
+		Conditions: &models.ActionConditions{
+			SchemaVersion: "1",
+			Pause:         &models.PauseAction{Enabled: true},
+		},
+		InstanceOverrides: map[int]*models.AutomationInstanceOverride{2: {MovePath: new("/data")}},
+	}
+	status, msg, err := handler.validatePayload(context.Background(), 1, payload)
+	require.Error(t, err)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, msg, "Instance overrides")
+
+	payload.InstanceOverrides = nil
+	payload.Targets = &models.AutomationTargets{AllInstances: true}
+	payload.Conditions.Pause = nil
+	payload.Conditions.ExportToInstance = &models.ExportToInstanceAction{Enabled: true, TargetInstanceID: 2}
+	_, msg, err = handler.validateTargetsPayload(context.Background(), 1, payload)
+	require.Error(t, err)
+	require.Contains(t, msg, "export target")
+
+	// Targets are normalized and only kept on the model when non-empty.
+	payload.Targets = &models.AutomationTargets{InstanceIDs: []int{3, 1, 3}}
+	payload.Targets.Normalize(1)
+	require.Equal(t, []int{3}, payload.toModel(1, 0).Targets.InstanceIDs)
+	payload.Targets = &models.AutomationTargets{}
+	require.Nil(t, payload.toModel(1, 0).Targets)
+}
@@ -6,6 +6,7 @@ package handlers
 import (
 	"archive/tar"
 	"archive/zip"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"database/sql"
@@ -22,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 
+	"filippo.io/age"
 	"github.com/andybalholm/brotli"
 	"github.com/go-chi/chi/v5"
 	kgzip "github.com/klauspost/compress/gzip"
@@ -170,6 +172,9 @@ type backupSettingsRequest struct {
 	IncludeCategories bool `json:"includeCategories"`
 	IncludeTags       bool `json:"includeTags"`
 	IncludeSavePaths  bool `json:"includeSavePaths"`
+
+	Destination *models.BackupDestination `json:"destination,omitempty"`
+	Encryption  *models.BackupEncryption  `json:"encryption,omitempty"`
 }
 
 func (h *BackupsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
@@ -215,9 +220,15 @@ func (h *BackupsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request)
 		IncludeCategories: req.IncludeCategories,
 		IncludeTags:       req.IncludeTags,
 		IncludeSavePaths:  req.IncludeSavePaths,
+		Destination:       req.Destination,
+		Encryption:        req.Encryption,
 	}
 
 	if err := h.service.UpdateSettings(r.Context(), settings); err != nil {
+		if errors.Is(err, backups.ErrInvalidDestination) || errors.Is(err, backups.ErrInvalidEncryption) {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		RespondError(w, http.StatusInternalServerError, "Failed to update backup settings")
 		return
 	}
@@ -237,12 +248,42 @@ type triggerBackupRequest struct {
 }
 
 type restoreRequest struct {
-	Mode               string   `json:"mode"`
-	DryRun             bool     `json:"dryRun"`
-	ExcludeHashes      []string `json:"excludeHashes"`
-	StartPaused        *bool    `json:"startPaused"`
-	SkipHashCheck      *bool    `json:"skipHashCheck"`
-	AutoResumeVerified *bool    `json:"autoResumeVerified"`
+	Mode               string                 `json:"mode"`
+	DryRun             bool                   `json:"dryRun"`
+	ExcludeHashes      []string               `json:"excludeHashes"`
+	StartPaused        *bool                  `json:"startPaused"`
+	SkipHashCheck      *bool                  `json:"skipHashCheck"`
+	AutoResumeVerified *bool                  `json:"autoResumeVerified"`
+	Target             *backups.RestoreTarget `json:"target,omitempty"`
+	StateFields        []backups.StateField   `json:"stateFields"`
+}
+
+// options applies the restore defaults: start paused, hash check on, and
+// auto-resume of torrents that verify.
+func (req restoreRequest) options() backups.RestoreOptions {
+	startPaused := true
+	if req.StartPaused != nil {
+		startPaused = *req.StartPaused
+	}
+	skipHashCheck := false
+	if req.SkipHashCheck != nil {
+		skipHashCheck = *req.SkipHashCheck
+	}
+
+	autoResume := true
+	if req.AutoResumeVerified != nil {
+		autoResume = *req.AutoResumeVerified
+	}
+
+	return backups.RestoreOptions{
+		DryRun:             req.DryRun,
+		StartPaused:        startPaused,
+		SkipHashCheck:      skipHashCheck,
+		AutoResumeVerified: autoResume,
+		ExcludeHashes:      req.ExcludeHashes,
+		Target:             req.Target,
+		StateFields:        req.StateFields,
+	}
 }
 
 func (h *BackupsHandler) TriggerBackup(w http.ResponseWriter, r *http.Request) {
@@ -403,7 +444,8 @@ func (h *BackupsHandler) GetManifest(w http.ResponseWriter, r *http.Request) {
 
 // DownloadRun downloads a backup archive.
 // Query parameters:
-//   - format: compression format (zip, tar.gz, tar.zst, tar.br, tar.xz, tar) - defaults to zip
+//   - format: compression format (zip, tar.gz, tar.zst, tar.br, tar.xz, tar) - defaults to zip.
+//     Append ".age" (e.g. zip.age) to encrypt the archive with the instance's backup key.
 func (h *BackupsHandler) DownloadRun(w http.ResponseWriter, r *http.Request) {
 	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
 	if err != nil {
@@ -449,6 +491,7 @@ func (h *BackupsHandler) DownloadRun(w http.ResponseWriter, r *http.Request) {
 	if format == "" {
 		format = "zip"
 	}
+	format, encrypted := strings.CutSuffix(format, ".age")
 	supportedFormats := map[string]bool{
 		"zip":     true,
 		"tar.gz":  true,
@@ -458,10 +501,23 @@ func (h *BackupsHandler) DownloadRun(w http.ResponseWriter, r *http.Request) {
 		"tar":     true,
 	}
 	if !supportedFormats[format] {
-		RespondError(w, http.StatusBadRequest, "Unsupported format. Supported: zip, tar.gz, tar.zst, tar.br, tar.xz, tar")
+		RespondError(w, http.StatusBadRequest, "Unsupported format. Supported: zip, tar.gz, tar.zst, tar.br, tar.xz, tar, each optionally with .age")
 		return
 	}
 
+	var recipient age.Recipient
+	if encrypted {
+		recipient, err = h.service.ExportRecipient(r.Context(), instanceID)
+		if err != nil {
+			if errors.Is(err, backups.ErrEncryptionNotConfigured) {
+				RespondError(w, http.StatusBadRequest, "Backup encryption is not configured for this instance")
+				return
+			}
+			RespondError(w, http.StatusInternalServerError, "Failed to load backup encryption key")
+			return
+		}
+	}
+
 	// Set headers based on format
 	var contentType, extension string
 	switch format {
@@ -493,13 +549,38 @@ func (h *BackupsHandler) DownloadRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if encrypted {
+		contentType = "application/octet-stream"
+		extension += ".age"
+	}
+
 	filename := fmt.Sprintf("qui-backup_instance-%d_%s_%s.%s", instanceID, strings.ToLower(string(run.Kind)), run.RequestedAt.Format("2006-01-02_15-04-05"), extension)
 	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Content-Disposition", "attachment; filename=\""+filename+"\"")
 
+	// out is what the archive is written to. Registered first, the age
+	// writer's deferred Close runs after the archive writers have flushed.
+	var out io.Writer = w
+	if encrypted {
+		encWriter, err := age.Encrypt(w, recipient)
+		if err != nil {
+			log.Error().Err(err).Int64("runID", runID).Msg("Failed to start encrypted backup download")
+			return
+		}
+		defer func() {
+			if err := encWriter.Close(); err != nil {
+				log.Error().Err(err).Int64("runID", runID).Msg("Failed to finalize encrypted backup download")
+			}
+		}()
+		out = encWriter
+	}
+
+	blobs := h.service.NewBlobReader(instanceID)
+	defer blobs.Close()
+
 	if format == "zip" {
 		// Create zip writer
-		zipWriter := zip.NewWriter(w)
+		zipWriter := zip.NewWriter(out)
 		defer zipWriter.Close()
 
 		// Add manifest to zip
@@ -524,12 +605,7 @@ func (h *BackupsHandler) DownloadRun(w http.ResponseWriter, r *http.Request) {
 				continue
 			}
 
-			// Validate blob path to prevent directory traversal
-			torrentPath := h.service.ResolveBackupPath(item.TorrentBlob)
-			if torrentPath == "" {
-				continue
-			}
-			file, err := os.Open(torrentPath)
+			data, err := blobs.Read(r.Context(), item.TorrentBlob)
 			if err != nil {
 				// Skip missing files
 				continue
@@ -543,18 +619,14 @@ func (h *BackupsHandler) DownloadRun(w http.ResponseWriter, r *http.Request) {
 
 			writer, err := zipWriter.CreateHeader(header)
 			if err != nil {
-				file.Close()
 				log.Error().Err(err).Int64("runID", runID).Str("path", item.ArchivePath).Msg("Failed to create zip entry")
 				return
 			}
 
-			if _, err := io.Copy(writer, file); err != nil {
-				file.Close()
+			if _, err := writer.Write(data); err != nil {
 				log.Error().Err(err).Int64("runID", runID).Str("path", item.ArchivePath).Msg("Failed to write torrent to zip")
 				return
 			}
-
-			file.Close()
 		}
 
 		// Close zip writer to finalize
@@ -568,15 +640,15 @@ func (h *BackupsHandler) DownloadRun(w http.ResponseWriter, r *http.Request) {
 		var err error
 		switch format {
 		case "tar.gz":
-			compressor, err = kgzip.NewWriterLevel(w, kgzip.DefaultCompression)
+			compressor, err = kgzip.NewWriterLevel(out, kgzip.DefaultCompression)
 		case "tar.zst":
-			compressor, err = zstd.NewWriter(w)
+			compressor, err = zstd.NewWriter(out)
 		case "tar.br":
-			compressor = brotli.NewWriter(w)
+			compressor = brotli.NewWriter(out)
 		case "tar.xz":
-			compressor, err = xz.NewWriter(w)
+			compressor, err = xz.NewWriter(out)
 		case "tar":
-			compressor = &nopCloser{w}
+			compressor = &nopCloser{out}
 		default:
 			RespondError(w, http.StatusInternalServerError, "Unsupported format")
 			return
@@ -612,42 +684,27 @@ func (h *BackupsHandler) DownloadRun(w http.ResponseWriter, r *http.Request) {
 				continue
 			}
 
-			// Validate blob path to prevent directory traversal
-			torrentPath := h.service.ResolveBackupPath(item.TorrentBlob)
-			if torrentPath == "" {
-				continue
-			}
-			file, err := os.Open(torrentPath)
+			data, err := blobs.Read(r.Context(), item.TorrentBlob)
 			if err != nil {
 				// Skip missing files
 				continue
 			}
 
-			stat, err := file.Stat()
-			if err != nil {
-				file.Close()
-				continue
-			}
-
 			header := &tar.Header{
 				Name:    item.ArchivePath,
-				Size:    stat.Size(),
+				Size:    int64(len(data)),
 				Mode:    0644,
 				ModTime: run.RequestedAt,
 			}
 			if err := tarWriter.WriteHeader(header); err != nil {
-				file.Close()
 				log.Error().Err(err).Int64("runID", runID).Str("path", item.ArchivePath).Msg("Failed to write tar header")
 				return
 			}
 
-			if _, err := io.Copy(tarWriter, file); err != nil {
-				file.Close()
+			if _, err := tarWriter.Write(data); err != nil {
 				log.Error().Err(err).Int64("runID", runID).Str("path", item.ArchivePath).Msg("Failed to write torrent to tar")
 				return
 			}
-
-			file.Close()
 		}
 		// tarWriter and compressor are closed by defers
 	}
@@ -673,23 +730,28 @@ func (h *BackupsHandler) ImportManifest(w http.ResponseWriter, r *http.Request)
 	if archiveFile, archiveHeader, err := r.FormFile("archive"); err == nil {
 		defer archiveFile.Close()
 
+		archive, filename, ok := h.openImportUpload(w, r, archiveFile, archiveHeader.Filename)
+		if !ok {
+			return
+		}
+
 		// Find streaming extractor
-		extractor := findStreamingExtractor(archiveHeader.Filename)
+		extractor := findStreamingExtractor(filename)
 		if extractor == nil {
-			RespondError(w, http.StatusBadRequest, "Unsupported format. Use .json (manifest-only), .zip, .tar.gz, .tar.zst, .tar.br, .tar.xz, or .tar")
+			RespondError(w, http.StatusBadRequest, "Unsupported format. Use .json (manifest-only), .zip, .tar.gz, .tar.zst, .tar.br, .tar.xz, or .tar, each optionally with .age")
 			return
 		}
 
 		if extractor.extractToDisk == nil {
 			// Manifest-only upload (JSON) - read directly (small file)
-			manifestData, err = io.ReadAll(archiveFile)
+			manifestData, err = io.ReadAll(archive)
 			if err != nil {
 				RespondError(w, http.StatusInternalServerError, "Failed to read manifest file")
 				return
 			}
 		} else {
 			// Save upload to temp file for streaming extraction
-			archivePath, err := saveUploadToTemp(archiveFile, archiveHeader.Filename)
+			archivePath, err := saveUploadToTemp(archive, filename)
 			if err != nil {
 				RespondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save archive: %v", err))
 				return
@@ -715,14 +777,19 @@ func (h *BackupsHandler) ImportManifest(w http.ResponseWriter, r *http.Request)
 		}
 	} else {
 		// Fall back to manifest-only upload
-		file, _, err := r.FormFile("manifest")
+		file, header, err := r.FormFile("manifest")
 		if err != nil {
 			RespondError(w, http.StatusBadRequest, "Either 'archive' (zip/tar.gz) or 'manifest' file is required")
 			return
 		}
 		defer file.Close()
 
-		manifestData, err = io.ReadAll(file)
+		manifest, _, ok := h.openImportUpload(w, r, file, header.Filename)
+		if !ok {
+			return
+		}
+
+		manifestData, err = io.ReadAll(manifest)
 		if err != nil {
 			RespondError(w, http.StatusInternalServerError, "Failed to read manifest file")
 			return
@@ -738,6 +805,10 @@ func (h *BackupsHandler) ImportManifest(w http.ResponseWriter, r *http.Request)
 
 	run, err := h.service.ImportManifestFromDir(r.Context(), instanceID, manifestData, requestedBy, torrentPaths)
 	if err != nil {
+		if errors.Is(err, backups.ErrUnsupportedManifest) {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		RespondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to import manifest: %v", err))
 		return
 	}
@@ -745,6 +816,29 @@ func (h *BackupsHandler) ImportManifest(w http.ResponseWriter, r *http.Request)
 	RespondJSON(w, http.StatusCreated, run)
 }
 
+// openImportUpload returns the readable contents of an uploaded backup and the
+// filename to pick an extractor by. Uploads named *.age are decrypted with
+// the configured backup keys or the "passphrase" form field; decryption is
+// done in memory, as encrypted exports are built from torrent files that
+// already fit in memory when they were written.
+func (h *BackupsHandler) openImportUpload(w http.ResponseWriter, r *http.Request, file io.Reader, filename string) (io.Reader, string, bool) {
+	name, encrypted := strings.CutSuffix(filename, ".age")
+	if !encrypted {
+		return file, filename, true
+	}
+
+	data, err := h.service.DecryptArchive(r.Context(), file, r.FormValue("passphrase"))
+	if err != nil {
+		if errors.Is(err, backups.ErrNoBackupKey) {
+			RespondError(w, http.StatusBadRequest, "Archive cannot be decrypted: provide the passphrase or configure the key it was encrypted with")
+			return nil, "", false
+		}
+		RespondError(w, http.StatusBadRequest, fmt.Sprintf("Failed to decrypt archive: %v", err))
+		return nil, "", false
+	}
+	return bytes.NewReader(data), name, true
+}
+
 // archiveEntryBaseName classifies an archive entry by its final element.
 // Entry names are slash-delimited by the zip and tar specs, so filepath.Base
 // only agrees with that on Windows: on Linux it would read "b\\manifest.json"
@@ -1099,28 +1193,27 @@ func (h *BackupsHandler) DownloadTorrentBlob(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	absTarget := h.service.ResolveBackupPath(*item.TorrentBlobPath)
-	if absTarget == "" {
+	if h.service.ResolveBackupPath(*item.TorrentBlobPath) == "" {
 		RespondError(w, http.StatusNotFound, "Cached torrent unavailable")
 		return
 	}
 
-	file, err := os.Open(absTarget) //nolint:gosec // G703: backupRelPath rejects escapes before the path resolves under the backup root
+	blobs := h.service.NewBlobReader(instanceID)
+	defer blobs.Close()
+
+	data, err := blobs.Read(r.Context(), *item.TorrentBlobPath)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, backups.ErrRemoteNotFound) {
 			RespondError(w, http.StatusNotFound, "Cached torrent file missing")
 			return
 		}
+		if errors.Is(err, backups.ErrNoBackupKey) {
+			RespondError(w, http.StatusInternalServerError, "No backup encryption key can decrypt this torrent file")
+			return
+		}
 		RespondError(w, http.StatusInternalServerError, "Failed to open torrent file")
 		return
 	}
-	defer file.Close()
-
-	info, err := file.Stat()
-	if err != nil {
-		RespondError(w, http.StatusInternalServerError, "Failed to inspect torrent file")
-		return
-	}
 
 	filename := ""
 	if item.ArchiveRelPath != nil && strings.TrimSpace(*item.ArchiveRelPath) != "" {
@@ -1132,7 +1225,7 @@ func (h *BackupsHandler) DownloadTorrentBlob(w http.ResponseWriter, r *http.Requ
 
 	w.Header().Set("Content-Type", "application/x-bittorrent")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
-	http.ServeContent(w, r, filename, info.ModTime(), file)
+	http.ServeContent(w, r, filename, run.RequestedAt, bytes.NewReader(data))
 }
 
 func (h *BackupsHandler) DeleteRun(w http.ResponseWriter, r *http.Request) {
@@ -1216,13 +1309,12 @@ func (h *BackupsHandler) PreviewRestore(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var planOpts *backups.RestorePlanOptions
-	if len(req.ExcludeHashes) > 0 {
-		planOpts = &backups.RestorePlanOptions{ExcludeHashes: req.ExcludeHashes}
-	}
-
-	plan, err := h.service.PlanRestoreDiff(r.Context(), runID, mode, planOpts)
+	plan, err := h.service.PlanRestoreDiff(r.Context(), runID, mode, req.options().PlanOptions())
 	if err != nil {
+		if errors.Is(err, backups.ErrInvalidRestoreTarget) || errors.Is(err, backups.ErrUnknownStateField) {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		RespondError(w, http.StatusInternalServerError, "Failed to build restore plan")
 		return
 	}
@@ -1260,28 +1352,12 @@ func (h *BackupsHandler) ExecuteRestore(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	startPaused := true
-	if req.StartPaused != nil {
-		startPaused = *req.StartPaused
-	}
-	skipHashCheck := false
-	if req.SkipHashCheck != nil {
-		skipHashCheck = *req.SkipHashCheck
-	}
-
-	autoResume := true
-	if req.AutoResumeVerified != nil {
-		autoResume = *req.AutoResumeVerified
-	}
-
-	result, err := h.service.ExecuteRestore(r.Context(), runID, mode, backups.RestoreOptions{
-		DryRun:             req.DryRun,
-		StartPaused:        startPaused,
-		SkipHashCheck:      skipHashCheck,
-		AutoResumeVerified: autoResume,
-		ExcludeHashes:      req.ExcludeHashes,
-	})
+	result, err := h.service.ExecuteRestore(r.Context(), runID, mode, req.options())
 	if err != nil {
+		if errors.Is(err, backups.ErrInvalidRestoreTarget) || errors.Is(err, backups.ErrUnknownStateField) {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		RespondError(w, http.StatusInternalServerError, "Failed to execute restore")
 		return
 	}
@@ -0,0 +1,150 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/backups"
+	"github.com/autobrr/qui/internal/models"
+)
+
+// TestDestination checks that a backup destination is reachable with the
+// given settings without saving them.
+func (h *BackupsHandler) TestDestination(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
+	var destination models.BackupDestination
+	if err := json.NewDecoder(r.Body).Decode(&destination); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.TestDestination(r.Context(), instanceID, &destination); err != nil {
+		if errors.Is(err, backups.ErrInvalidDestination) {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		RespondError(w, http.StatusBadGateway, "Backup destination check failed: "+err.Error())
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// ListRemoteRuns lists the runs stored on the instance's backup destination.
+func (h *BackupsHandler) ListRemoteRuns(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
+	runs, err := h.service.ListRemoteRuns(r.Context(), instanceID)
+	if err != nil {
+		respondRemoteError(w, instanceID, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, runs)
+}
+
+func (h *BackupsHandler) GetRemoteManifest(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
+	manifest, err := h.service.LoadRemoteManifest(r.Context(), instanceID, chi.URLParam(r, "name"))
+	if err != nil {
+		respondRemoteError(w, instanceID, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, manifest)
+}
+
+func (h *BackupsHandler) PreviewRemoteRestore(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
+	var req restoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	mode, err := backups.ParseRestoreMode(req.Mode)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	plan, err := h.service.PlanRemoteRestore(r.Context(), instanceID, chi.URLParam(r, "name"), mode, req.options().PlanOptions())
+	if err != nil {
+		respondRemoteError(w, instanceID, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, plan)
+}
+
+func (h *BackupsHandler) ExecuteRemoteRestore(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
+	var req restoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		RespondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	mode, err := backups.ParseRestoreMode(req.Mode)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.service.ExecuteRemoteRestore(r.Context(), instanceID, chi.URLParam(r, "name"), mode, req.options())
+	if err != nil {
+		respondRemoteError(w, instanceID, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, result)
+}
+
+func respondRemoteError(w http.ResponseWriter, instanceID int, err error) {
+	switch {
+	case errors.Is(err, backups.ErrNoDestination):
+		RespondError(w, http.StatusNotFound, "No backup destination configured")
+	case errors.Is(err, backups.ErrInvalidRemoteRun):
+		RespondError(w, http.StatusBadRequest, "Invalid remote backup run")
+	case errors.Is(err, backups.ErrRemoteNotFound):
+		RespondError(w, http.StatusNotFound, "Remote backup run not found")
+	case errors.Is(err, backups.ErrInvalidRestoreTarget), errors.Is(err, backups.ErrUnknownStateField), errors.Is(err, backups.ErrUnsupportedManifest):
+		RespondError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("Backup destination request failed")
+		RespondError(w, http.StatusBadGateway, "Backup destination request failed")
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/services/bulkjobs"
+)
+
+type BulkJobsHandler struct {
+	manager *bulkjobs.Manager
+}
+
+func NewBulkJobsHandler(manager *bulkjobs.Manager) *BulkJobsHandler {
+	return &BulkJobsHandler{manager: manager}
+}
+
+func (h *BulkJobsHandler) List(w http.ResponseWriter, _ *http.Request) {
+	if h.manager == nil {
+		RespondJSON(w, http.StatusOK, []*bulkjobs.Job{})
+		return
+	}
+	RespondJSON(w, http.StatusOK, h.manager.List())
+}
+
+func (h *BulkJobsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.jobID(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.manager.Get(id)
+	if err != nil {
+		respondBulkJobError(w, err)
+		return
+	}
+	RespondJSON(w, http.StatusOK, job)
+}
+
+// Cancel stops a queued or running job. A running job stops after the chunk it
+// is working on.
+func (h *BulkJobsHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.jobID(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.manager.Cancel(id)
+	if err != nil {
+		respondBulkJobError(w, err)
+		return
+	}
+	RespondJSON(w, http.StatusOK, job)
+}
+
+// Undo queues a job restoring the values the last finished reversible job
+// overwrote.
+func (h *BulkJobsHandler) Undo(w http.ResponseWriter, _ *http.Request) {
+	if h.manager == nil {
+		RespondError(w, http.StatusServiceUnavailable, "Bulk jobs are not available")
+		return
+	}
+
+	job, err := h.manager.UndoLast()
+	if err != nil {
+		respondBulkJobError(w, err)
+		return
+	}
+	RespondJSON(w, http.StatusAccepted, job)
+}
+
+func (h *BulkJobsHandler) jobID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	if h.manager == nil {
+		RespondError(w, http.StatusServiceUnavailable, "Bulk jobs are not available")
+		return 0, false
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid job ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func respondBulkJobError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, bulkjobs.ErrJobNotFound):
+		RespondError(w, http.StatusNotFound, "Bulk job not found")
+	case errors.Is(err, bulkjobs.ErrJobFinished):
+		RespondError(w, http.StatusConflict, "Bulk job already finished")
+	case errors.Is(err, bulkjobs.ErrNothingToUndo):
+		RespondError(w, http.StatusConflict, "No bulk job to undo")
+	case errors.Is(err, bulkjobs.ErrQueueFull):
+		RespondError(w, http.StatusServiceUnavailable, "Too many bulk jobs queued")
+	default:
+		log.Error().Err(err).Msg("bulk job request failed")
+		RespondError(w, http.StatusInternalServerError, "Bulk job request failed")
+	}
+}
+
+// submitBulkJob queues req as a background job over the resolved targets and
+// responds with the queued job.
+func (h *TorrentsHandler) submitBulkJob(w http.ResponseWriter, req BulkActionRequest, targetsByInstance map[int][]string) {
+	if h.bulkJobs == nil {
+		RespondError(w, http.StatusServiceUnavailable, "Bulk jobs are not available")
+		return
+	}
+
+	job, err := h.bulkJobs.Submit(bulkjobs.Spec{
+		Action:  req.Action,
+		Targets: targetsByInstance,
+		Run: func(ctx context.Context, instanceID int, hashes []string) error {
+			return h.executeBulkActionForInstance(ctx, instanceID, req, hashes)
+		},
+	})
+	if err != nil {
+		respondBulkJobError(w, err)
+		return
+	}
+
+	log.Debug().
+		Int("jobID", job.ID).
+		Str("action", req.Action).
+		Int("instanceCount", len(targetsByInstance)).
+		Int("targetCount", job.Total).
+		Msg("Bulk action queued as job")
+
+	RespondJSON(w, http.StatusAccepted, job)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/services/bulkjobs"
+)
+
+func TestBulkActionAsyncQueuesJob(t *testing.T) {
+	// The manager is not started, so the job stays queued.
+	manager := bulkjobs.NewManager(nil)
+	torrents := NewTorrentsHandler(nil, nil, nil)
+	torrents.SetBulkJobManager(manager)
+
+	body := `{"action":"pause","hashes":["aaa","bbb"],"async":true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/instances/1/torrents/bulk-action", strings.NewReader(body))
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("instanceID", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	rec := httptest.NewRecorder()
+
+	torrents.BulkAction(rec, req)
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var job bulkjobs.Job
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&job))
+	assert.Equal(t, "pause", job.Action)
+	assert.Equal(t, bulkjobs.StatusQueued, job.Status)
+	assert.Equal(t, 2, job.Total)
+	assert.Equal(t, []int{1}, job.InstanceIDs)
+
+	jobs := NewBulkJobsHandler(manager)
+	id := strconv.Itoa(job.ID)
+
+	rec = httptest.NewRecorder()
+	jobs.Cancel(rec, newRequestWithParams(http.MethodPost, "/api/bulk-jobs/1/cancel", map[string]string{"id": id}))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	jobs.Cancel(rec, newRequestWithParams(http.MethodPost, "/api/bulk-jobs/1/cancel", map[string]string{"id": id}))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = httptest.NewRecorder()
+	jobs.Get(rec, newRequestWithParams(http.MethodGet, "/api/bulk-jobs/2", map[string]string{"id": "2"}))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	jobs.Undo(rec, httptest.NewRequest(http.MethodPost, "/api/bulk-jobs/undo", nil))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestBulkActionAsyncWithoutManager(t *testing.T) {
+	torrents := NewTorrentsHandler(nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/instances/1/torrents/bulk-action", strings.NewReader(`{"action":"pause","hashes":["aaa"],"async":true}`))
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("instanceID", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+	rec := httptest.NewRecorder()
+
+	torrents.BulkAction(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
@@ -6,11 +6,19 @@ package handlers
 import (
 	"runtime"
 
+	"github.com/autobrr/qui/internal/clients"
+	"github.com/autobrr/qui/internal/models"
 	internalqbittorrent "github.com/autobrr/qui/internal/qbittorrent"
 )
 
 // InstanceCapabilitiesResponse describes supported features for an instance.
 type InstanceCapabilitiesResponse struct {
+	ClientType                  string `json:"clientType"`
+	SupportsCategories          bool   `json:"supportsCategories"`
+	SupportsTags                bool   `json:"supportsTags"`
+	SupportsSetLocation         bool   `json:"supportsSetLocation"`
+	SupportsShareLimits         bool   `json:"supportsShareLimits"`
+	SupportsBackups             bool   `json:"supportsBackups"`
 	SupportsTorrentCreation     bool   `json:"supportsTorrentCreation"`
 	SupportsTorrentExport       bool   `json:"supportsTorrentExport"`
 	SupportsSetTags             bool   `json:"supportsSetTags"`
@@ -35,6 +43,12 @@ type InstanceCapabilitiesResponse struct {
 // NewInstanceCapabilitiesResponse creates a response payload from a qBittorrent client.
 func NewInstanceCapabilitiesResponse(client *internalqbittorrent.Client) InstanceCapabilitiesResponse {
 	capabilities := InstanceCapabilitiesResponse{
+		ClientType:                  string(models.ClientTypeQBittorrent),
+		SupportsCategories:          true,
+		SupportsTags:                true,
+		SupportsSetLocation:         true,
+		SupportsShareLimits:         true,
+		SupportsBackups:             true,
 		SupportsTorrentCreation:     client.SupportsTorrentCreation(),
 		SupportsTorrentExport:       client.SupportsTorrentExport(),
 		SupportsSetTags:             client.SupportsSetTags(),
@@ -61,3 +75,18 @@ func NewInstanceCapabilitiesResponse(client *internalqbittorrent.Client) Instanc
 
 	return capabilities
 }
+
+// NewBackendCapabilitiesResponse creates a response payload for a Transmission
+// or Deluge instance. qBittorrent-only features stay off.
+func NewBackendCapabilitiesResponse(client clients.Client, version string) InstanceCapabilitiesResponse {
+	supported := client.Capabilities()
+	return InstanceCapabilitiesResponse{
+		ClientType:          string(client.Type()),
+		SupportsCategories:  supported.Categories,
+		SupportsTags:        supported.Tags,
+		SupportsSetLocation: supported.SetLocation,
+		SupportsShareLimits: supported.RatioLimit || supported.SeedingTimeLimit || supported.InactiveSeedingTimeLimit,
+		SupportsSetTags:     supported.Tags,
+		WebAPIVersion:       version,
+	}
+}
@@ -4,6 +4,8 @@
 package handlers
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -19,13 +21,15 @@ import (
 type ClientAPIKeysHandler struct {
 	clientAPIKeyStore *models.ClientAPIKeyStore
 	instanceStore     *models.InstanceStore
+	virtualStore      *models.VirtualInstanceStore
 	basePath          string
 }
 
-func NewClientAPIKeysHandler(clientAPIKeyStore *models.ClientAPIKeyStore, instanceStore *models.InstanceStore, baseURL string) *ClientAPIKeysHandler {
+func NewClientAPIKeysHandler(clientAPIKeyStore *models.ClientAPIKeyStore, instanceStore *models.InstanceStore, virtualStore *models.VirtualInstanceStore, baseURL string) *ClientAPIKeysHandler {
 	return &ClientAPIKeysHandler{
 		clientAPIKeyStore: clientAPIKeyStore,
 		instanceStore:     instanceStore,
+		virtualStore:      virtualStore,
 		basePath:          httphelpers.NormalizeBasePath(baseURL),
 	}
 }
@@ -33,18 +37,26 @@ func NewClientAPIKeysHandler(clientAPIKeyStore *models.ClientAPIKeyStore, instan
 type CreateClientAPIKeyRequest struct {
 	ClientName string `json:"clientName"`
 	InstanceID int    `json:"instanceId"`
+	// VirtualInstanceID targets a virtual instance instead of InstanceID.
+	VirtualInstanceID int                          `json:"virtualInstanceId"`
+	Scope             models.ClientAPIKeyScope     `json:"scope"`
+	AddPolicy         models.ClientAPIKeyAddPolicy `json:"addPolicy"`
+	// DuplicatePolicy handles adds of torrents that already exist on an instance.
+	DuplicatePolicy models.ClientAPIKeyDuplicatePolicy `json:"duplicatePolicy"`
 }
 
 type CreateClientAPIKeyResponse struct {
-	Key          string               `json:"key"`
-	ClientAPIKey *models.ClientAPIKey `json:"clientApiKey"`
-	Instance     *models.Instance     `json:"instance,omitempty"`
-	ProxyURL     string               `json:"proxyUrl"`
+	Key             string                  `json:"key"`
+	ClientAPIKey    *models.ClientAPIKey    `json:"clientApiKey"`
+	Instance        *models.Instance        `json:"instance,omitempty"`
+	VirtualInstance *models.VirtualInstance `json:"virtualInstance,omitempty"`
+	ProxyURL        string                  `json:"proxyUrl"`
 }
 
 type ClientAPIKeyWithInstance struct {
 	*models.ClientAPIKey
-	Instance *models.Instance `json:"instance"`
+	Instance        *models.Instance        `json:"instance"`
+	VirtualInstance *models.VirtualInstance `json:"virtualInstance,omitempty"`
 }
 
 // CreateClientAPIKey handles POST /api/client-api-keys
@@ -62,26 +74,65 @@ func (h *ClientAPIKeysHandler) CreateClientAPIKey(w http.ResponseWriter, r *http
 		return
 	}
 
-	if req.InstanceID == 0 {
+	if req.InstanceID == 0 && req.VirtualInstanceID == 0 {
 		http.Error(w, "Instance ID is required", http.StatusBadRequest)
 		return
 	}
+	if req.InstanceID != 0 && req.VirtualInstanceID != 0 {
+		http.Error(w, "Only one of instance ID and virtual instance ID may be set", http.StatusBadRequest)
+		return
+	}
+
+	req.Scope.Normalize()
+	if err := req.Scope.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.AddPolicy.Normalize()
+	if err := req.AddPolicy.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := req.DuplicatePolicy.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// Verify instance exists
+	// Verify the target exists
 	ctx := r.Context()
-	instance, err := h.instanceStore.Get(ctx, req.InstanceID)
-	if err != nil {
-		if errors.Is(err, models.ErrInstanceNotFound) {
-			http.Error(w, "Instance not found", http.StatusNotFound)
+	var instance *models.Instance
+	var virtualInstance *models.VirtualInstance
+	var err error
+	if req.VirtualInstanceID != 0 {
+		if h.virtualStore == nil {
+			http.Error(w, "Virtual instances are not available", http.StatusServiceUnavailable)
+			return
+		}
+		virtualInstance, err = h.virtualStore.Get(ctx, req.VirtualInstanceID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, "Virtual instance not found", http.StatusNotFound)
+				return
+			}
+			log.Error().Err(err).Int("virtualInstanceId", req.VirtualInstanceID).Msg("Failed to get virtual instance")
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	} else {
+		instance, err = h.instanceStore.Get(ctx, req.InstanceID)
+		if err != nil {
+			if errors.Is(err, models.ErrInstanceNotFound) {
+				http.Error(w, "Instance not found", http.StatusNotFound)
+				return
+			}
+			log.Error().Err(err).Int("instanceId", req.InstanceID).Msg("Failed to get instance")
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
 			return
 		}
-		log.Error().Err(err).Int("instanceId", req.InstanceID).Msg("Failed to get instance")
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
 	}
 
 	// Create the client API key
-	rawKey, clientAPIKey, err := h.clientAPIKeyStore.Create(ctx, req.ClientName, req.InstanceID)
+	rawKey, clientAPIKey, err := h.clientAPIKeyStore.Create(ctx, req.ClientName, req.InstanceID, req.VirtualInstanceID, req.Scope, req.AddPolicy, req.DuplicatePolicy)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create client API key")
 		http.Error(w, "Failed to create API key", http.StatusInternalServerError)
@@ -92,10 +143,11 @@ func (h *ClientAPIKeysHandler) CreateClientAPIKey(w http.ResponseWriter, r *http
 	proxyURL := httphelpers.JoinBasePath(h.basePath, "/proxy/"+rawKey)
 
 	response := CreateClientAPIKeyResponse{
-		Key:          rawKey,
-		ClientAPIKey: clientAPIKey,
-		Instance:     instance,
-		ProxyURL:     proxyURL,
+		Key:             rawKey,
+		ClientAPIKey:    clientAPIKey,
+		Instance:        instance,
+		VirtualInstance: virtualInstance,
+		ProxyURL:        proxyURL,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -117,6 +169,14 @@ func (h *ClientAPIKeysHandler) ListClientAPIKeys(w http.ResponseWriter, r *http.
 	// Enrich with instance information
 	var enrichedKeys []*ClientAPIKeyWithInstance
 	for _, key := range clientAPIKeys {
+		if key.VirtualInstanceID != nil {
+			enrichedKeys = append(enrichedKeys, &ClientAPIKeyWithInstance{
+				ClientAPIKey:    key,
+				VirtualInstance: h.lookupVirtualInstance(ctx, key),
+			})
+			continue
+		}
+
 		instance, err := h.instanceStore.Get(ctx, key.InstanceID)
 		if err != nil {
 			// Log error but continue - instance might have been deleted
@@ -139,6 +199,121 @@ func (h *ClientAPIKeysHandler) ListClientAPIKeys(w http.ResponseWriter, r *http.
 	_ = json.NewEncoder(w).Encode(enrichedKeys)
 }
 
+func (h *ClientAPIKeysHandler) lookupVirtualInstance(ctx context.Context, key *models.ClientAPIKey) *models.VirtualInstance {
+	if h.virtualStore == nil {
+		return nil
+	}
+	virtualInstance, err := h.virtualStore.Get(ctx, *key.VirtualInstanceID)
+	if err != nil {
+		log.Warn().Err(err).Int("virtualInstanceId", *key.VirtualInstanceID).Int("keyId", key.ID).
+			Msg("Failed to get virtual instance for client API key")
+		return nil
+	}
+	return virtualInstance
+}
+
+// UpdateClientAPIKeyScope handles PUT /api/client-api-keys/{id}/scope
+func (h *ClientAPIKeysHandler) UpdateClientAPIKeyScope(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid API key ID", http.StatusBadRequest)
+		return
+	}
+
+	var scope models.ClientAPIKeyScope
+	if err := json.NewDecoder(r.Body).Decode(&scope); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	scope.Normalize()
+	if err := scope.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.clientAPIKeyStore.UpdateScope(r.Context(), id, scope); err != nil {
+		if errors.Is(err, models.ErrClientAPIKeyNotFound) {
+			http.Error(w, "API key not found", http.StatusNotFound)
+			return
+		}
+		log.Error().Err(err).Int("keyId", id).Msg("Failed to update client API key scope")
+		http.Error(w, "Failed to update API key scope", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(scope)
+}
+
+// UpdateClientAPIKeyAddPolicy handles PUT /api/client-api-keys/{id}/add-policy
+func (h *ClientAPIKeysHandler) UpdateClientAPIKeyAddPolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid API key ID", http.StatusBadRequest)
+		return
+	}
+
+	var policy models.ClientAPIKeyAddPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	policy.Normalize()
+	if err := policy.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.clientAPIKeyStore.UpdateAddPolicy(r.Context(), id, policy); err != nil {
+		if errors.Is(err, models.ErrClientAPIKeyNotFound) {
+			http.Error(w, "API key not found", http.StatusNotFound)
+			return
+		}
+		log.Error().Err(err).Int("keyId", id).Msg("Failed to update client API key add policy")
+		http.Error(w, "Failed to update API key add policy", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(policy)
+}
+
+type UpdateClientAPIKeyDuplicatePolicyRequest struct {
+	DuplicatePolicy models.ClientAPIKeyDuplicatePolicy `json:"duplicatePolicy"`
+}
+
+// UpdateClientAPIKeyDuplicatePolicy handles PUT /api/client-api-keys/{id}/duplicate-policy
+func (h *ClientAPIKeysHandler) UpdateClientAPIKeyDuplicatePolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid API key ID", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateClientAPIKeyDuplicatePolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.DuplicatePolicy.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.clientAPIKeyStore.UpdateDuplicatePolicy(r.Context(), id, req.DuplicatePolicy); err != nil {
+		if errors.Is(err, models.ErrClientAPIKeyNotFound) {
+			http.Error(w, "API key not found", http.StatusNotFound)
+			return
+		}
+		log.Error().Err(err).Int("keyId", id).Msg("Failed to update client API key duplicate policy")
+		http.Error(w, "Failed to update API key duplicate policy", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(req)
+}
+
 // DeleteClientAPIKey handles DELETE /api/client-api-keys/{id}
 func (h *ClientAPIKeysHandler) DeleteClientAPIKey(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -10,6 +10,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/autobrr/qui/internal/clients"
 	internalqbittorrent "github.com/autobrr/qui/internal/qbittorrent"
 )
 
@@ -52,7 +53,7 @@ func RespondError(w http.ResponseWriter, status int, message string) {
 }
 
 func respondIfInstanceDisabled(w http.ResponseWriter, err error, instanceID int, context string) bool {
-	if errors.Is(err, internalqbittorrent.ErrInstanceDisabled) {
+	if errors.Is(err, internalqbittorrent.ErrInstanceDisabled) || errors.Is(err, clients.ErrInstanceDisabled) {
 		log.Trace().
 			Int("instanceID", instanceID).
 			Str("context", context).
@@ -17,6 +17,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 
+	"github.com/autobrr/qui/internal/clients"
 	"github.com/autobrr/qui/internal/domain"
 	"github.com/autobrr/qui/internal/models"
 	internalqbittorrent "github.com/autobrr/qui/internal/qbittorrent"
@@ -54,6 +55,23 @@ func (h *InstancesHandler) GetInstanceCapabilities(w http.ResponseWriter, r *htt
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
 
+	if backend, ok, err := h.syncManager.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			if respondIfInstanceDisabled(w, err, instanceID, "instances:getCapabilities") {
+				return
+			}
+			log.Error().Err(err).Int("instanceID", instanceID).Msg("Failed to get client for capabilities")
+			RespondError(w, http.StatusServiceUnavailable, instanceCapabilitiesClientErrorMessage(err))
+			return
+		}
+		version, err := backend.Version(ctx)
+		if err != nil {
+			log.Error().Err(err).Int("instanceID", instanceID).Msg("Unable to read client version during request")
+		}
+		RespondJSON(w, http.StatusOK, NewBackendCapabilitiesResponse(backend, version))
+		return
+	}
+
 	client, err := h.clientPool.GetClientOffline(ctx, instanceID)
 	if err != nil {
 		client, err = h.clientPool.GetClientWithTimeout(ctx, instanceID, 15*time.Second)
@@ -289,6 +307,10 @@ func (h *InstancesHandler) buildInstanceResponse(ctx context.Context, instance *
 	var connectionStatus string
 	if !instance.IsActive {
 		connectionStatus = "disabled"
+	} else if !instance.IsQBittorrent() {
+		// Other clients keep no cached health, so ask them directly.
+		managed, err := h.testBackendConnection(ctx, instance.ID)
+		healthy = managed && err == nil
 	} else if client != nil && h.syncManager != nil {
 		if status := internalqbittorrent.NormalizeConnectionStatus(h.syncManager.ReadCachedConnectionStatus(ctx, instance.ID)); status != "" {
 			connectionStatus = status
@@ -303,6 +325,7 @@ func (h *InstancesHandler) buildInstanceResponse(ctx context.Context, instance *
 		Name:                     instance.Name,
 		Host:                     instance.Host,
 		Username:                 instance.Username,
+		ClientType:               instance.ClientType.OrDefault(),
 		HasAPIKey:                instance.APIKeyEncrypted != "",
 		BasicUsername:            instance.BasicUsername,
 		TLSSkipVerify:            instance.TLSSkipVerify,
@@ -345,6 +368,7 @@ func (h *InstancesHandler) buildQuickInstanceResponse(instance *models.Instance)
 		Name:                     instance.Name,
 		Host:                     instance.Host,
 		Username:                 instance.Username,
+		ClientType:               instance.ClientType.OrDefault(),
 		HasAPIKey:                instance.APIKeyEncrypted != "",
 		BasicUsername:            instance.BasicUsername,
 		TLSSkipVerify:            instance.TLSSkipVerify,
@@ -369,6 +393,15 @@ func (h *InstancesHandler) testConnectionAsync(instanceID int) {
 
 	log.Debug().Int("instanceID", instanceID).Msg("Testing connection asynchronously")
 
+	if managed, err := h.testBackendConnection(ctx, instanceID); managed {
+		if err != nil {
+			log.Debug().Err(err).Int("instanceID", instanceID).Msg("Async connection test failed")
+			return
+		}
+		log.Debug().Int("instanceID", instanceID).Msg("Async connection test succeeded")
+		return
+	}
+
 	client, err := h.clientPool.GetClient(ctx, instanceID)
 	if err != nil {
 		log.Debug().Err(err).Int("instanceID", instanceID).Msg("Async connection test failed")
@@ -383,6 +416,20 @@ func (h *InstancesHandler) testConnectionAsync(instanceID int) {
 	log.Debug().Int("instanceID", instanceID).Msg("Async connection test succeeded")
 }
 
+// testBackendConnection checks a Transmission or Deluge instance. managed is
+// false for qBittorrent instances.
+func (h *InstancesHandler) testBackendConnection(ctx context.Context, instanceID int) (managed bool, err error) {
+	backend, ok, err := h.syncManager.BackendClient(ctx, instanceID)
+	if !ok {
+		return false, nil
+	}
+	if err != nil {
+		return true, err
+	}
+	_, err = backend.Version(ctx)
+	return true, err
+}
+
 func (h *InstancesHandler) loadReannounceSettings(ctx context.Context, instanceID int) *models.InstanceReannounceSettings {
 	if h.reannounceStore == nil {
 		return models.DefaultInstanceReannounceSettings(instanceID)
@@ -429,6 +476,7 @@ type CreateInstanceRequest struct {
 	Host                     string                             `json:"host"`
 	Username                 string                             `json:"username"`
 	Password                 string                             `json:"password"`
+	ClientType               models.ClientType                  `json:"clientType,omitempty"`
 	APIKey                   string                             `json:"apiKey,omitempty"`
 	BasicUsername            *string                            `json:"basicUsername,omitempty"`
 	BasicPassword            *string                            `json:"basicPassword,omitempty"`
@@ -443,6 +491,7 @@ type UpdateInstanceRequest struct {
 	Host                     string                             `json:"host"`
 	Username                 string                             `json:"username"`
 	Password                 string                             `json:"password,omitempty"` // Optional for updates
+	ClientType               *models.ClientType                 `json:"clientType,omitempty"`
 	APIKey                   *string                            `json:"apiKey,omitempty"`
 	BasicUsername            *string                            `json:"basicUsername,omitempty"`
 	BasicPassword            *string                            `json:"basicPassword,omitempty"`
@@ -466,6 +515,7 @@ type InstanceResponse struct {
 	Name                     string                            `json:"name"`
 	Host                     string                            `json:"host"`
 	Username                 string                            `json:"username"`
+	ClientType               models.ClientType                 `json:"clientType"`
 	HasAPIKey                bool                              `json:"hasApiKey"`
 	BasicUsername            *string                           `json:"basicUsername,omitempty"`
 	TLSSkipVerify            bool                              `json:"tlsSkipVerify"`
@@ -653,6 +703,11 @@ func (h *InstancesHandler) CreateInstance(w http.ResponseWriter, r *http.Request
 		RespondError(w, http.StatusBadRequest, "Name and host are required")
 		return
 	}
+	clientType := req.ClientType.OrDefault()
+	if !clientType.Valid() {
+		RespondError(w, http.StatusBadRequest, "Unsupported client type")
+		return
+	}
 
 	// Create instance
 	instance, err := h.instanceStore.Create(r.Context(), req.Name, req.Host, req.Username, req.Password, req.BasicUsername, req.BasicPassword, req.TLSSkipVerify, req.HasLocalFilesystemAccess, req.APIKey)
@@ -662,6 +717,15 @@ func (h *InstancesHandler) CreateInstance(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if clientType != models.ClientTypeQBittorrent {
+		instance, err = h.instanceStore.SetClientType(r.Context(), instance.ID, clientType)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to set instance client type")
+			RespondError(w, http.StatusInternalServerError, "Failed to create instance")
+			return
+		}
+	}
+
 	settings, err := h.persistReannounceSettings(r.Context(), instance.ID, req.ReannounceSettings)
 	if err != nil {
 		RespondError(w, http.StatusInternalServerError, "Failed to save reannounce settings")
@@ -698,6 +762,10 @@ func (h *InstancesHandler) UpdateInstance(w http.ResponseWriter, r *http.Request
 		RespondError(w, http.StatusBadRequest, "Name and host are required")
 		return
 	}
+	if req.ClientType != nil && !req.ClientType.Valid() {
+		RespondError(w, http.StatusBadRequest, "Unsupported client type")
+		return
+	}
 
 	// Fetch existing instance to handle redacted values
 	existingInstance, err := h.instanceStore.Get(r.Context(), instanceID)
@@ -781,6 +849,7 @@ func (h *InstancesHandler) UpdateInstance(w http.ResponseWriter, r *http.Request
 		HardlinkDirPreset:        req.HardlinkDirPreset,
 		UseReflinks:              req.UseReflinks,
 		FallbackToRegularMode:    req.FallbackToRegularMode,
+		ClientType:               req.ClientType,
 	}
 	instance, err := h.instanceStore.Update(r.Context(), instanceID, req.Name, req.Host, req.Username, req.Password, req.BasicUsername, req.BasicPassword, updateParams, req.APIKey)
 	if err != nil {
@@ -894,6 +963,19 @@ func (h *InstancesHandler) TestConnection(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if managed, err := h.testBackendConnection(r.Context(), instanceID); managed {
+		response := TestConnectionResponse{Connected: err == nil, Message: "Connection successful"}
+		if err != nil {
+			response.Message = ""
+			if errors.Is(err, clients.ErrInstanceDisabled) {
+				response.Message = "Instance is disabled"
+			}
+			response.Error = err.Error()
+		}
+		RespondJSON(w, http.StatusOK, response)
+		return
+	}
+
 	// Try to get client (this will create connection if needed)
 	client, err := h.clientPool.GetClient(r.Context(), instanceID)
 	if err != nil {
@@ -0,0 +1,270 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/prefprofiles"
+)
+
+type PreferenceProfileHandler struct {
+	store   *models.PreferenceProfileStore
+	service *prefprofiles.Service
+}
+
+func NewPreferenceProfileHandler(store *models.PreferenceProfileStore, service *prefprofiles.Service) *PreferenceProfileHandler {
+	return &PreferenceProfileHandler{
+		store:   store,
+		service: service,
+	}
+}
+
+type PreferenceProfilePayload struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Preferences map[string]any `json:"preferences"`
+	InstanceIDs []int          `json:"instanceIds"`
+	DriftAlerts bool           `json:"driftAlerts"`
+}
+
+func (p *PreferenceProfilePayload) toModel(id int) *models.PreferenceProfile {
+	return &models.PreferenceProfile{
+		ID:          id,
+		Name:        p.Name,
+		Description: p.Description,
+		Preferences: p.Preferences,
+		InstanceIDs: p.InstanceIDs,
+		DriftAlerts: p.DriftAlerts,
+	}
+}
+
+type PreferenceSnapshotRequest struct {
+	InstanceID int      `json:"instanceId"`
+	Keys       []string `json:"keys"`
+}
+
+type PreferenceProfilePushRequest struct {
+	InstanceIDs []int `json:"instanceIds"`
+}
+
+func (h *PreferenceProfileHandler) List(w http.ResponseWriter, r *http.Request) {
+	profiles, err := h.store.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list preference profiles")
+		RespondError(w, http.StatusInternalServerError, "Failed to load preference profiles")
+		return
+	}
+	if profiles == nil {
+		profiles = []*models.PreferenceProfile{}
+	}
+
+	RespondJSON(w, http.StatusOK, profiles)
+}
+
+// Keys lists the preference keys a profile can manage, grouped by area.
+func (h *PreferenceProfileHandler) Keys(w http.ResponseWriter, _ *http.Request) {
+	RespondJSON(w, http.StatusOK, models.PreferenceKeyGroups)
+}
+
+func (h *PreferenceProfileHandler) Get(w http.ResponseWriter, r *http.Request) {
+	profile, ok := h.loadProfile(w, r)
+	if !ok {
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, profile)
+}
+
+func (h *PreferenceProfileHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var payload PreferenceProfilePayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	profile := payload.toModel(0)
+	if err := profile.Validate(); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid preference profile: "+err.Error())
+		return
+	}
+
+	created, err := h.store.Create(r.Context(), profile)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create preference profile")
+		RespondError(w, http.StatusInternalServerError, "Failed to create preference profile")
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, created)
+}
+
+func (h *PreferenceProfileHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid preference profile ID")
+		return
+	}
+
+	var payload PreferenceProfilePayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	profile := payload.toModel(id)
+	if err := profile.Validate(); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid preference profile: "+err.Error())
+		return
+	}
+
+	updated, err := h.store.Update(r.Context(), profile)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Preference profile not found")
+			return
+		}
+		log.Error().Err(err).Int("id", id).Msg("failed to update preference profile")
+		RespondError(w, http.StatusInternalServerError, "Failed to update preference profile")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, updated)
+}
+
+func (h *PreferenceProfileHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid preference profile ID")
+		return
+	}
+
+	if err := h.store.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Preference profile not found")
+			return
+		}
+		log.Error().Err(err).Int("id", id).Msg("failed to delete preference profile")
+		RespondError(w, http.StatusInternalServerError, "Failed to delete preference profile")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Snapshot captures an instance's current values for the requested keys, to
+// seed a new profile.
+func (h *PreferenceProfileHandler) Snapshot(w http.ResponseWriter, r *http.Request) {
+	var req PreferenceSnapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if req.InstanceID <= 0 {
+		RespondError(w, http.StatusBadRequest, "instanceId is required")
+		return
+	}
+
+	prefs, err := h.service.Snapshot(r.Context(), req.InstanceID, req.Keys)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInstanceNotFound):
+			RespondError(w, http.StatusNotFound, "Instance not found")
+		case errors.Is(err, prefprofiles.ErrNotQBittorrent), errors.Is(err, models.ErrUnmanagedPreference):
+			RespondError(w, http.StatusBadRequest, err.Error())
+		default:
+			log.Error().Err(err).Int("instanceID", req.InstanceID).Msg("failed to snapshot preferences")
+			RespondError(w, http.StatusBadGateway, "Failed to read instance preferences")
+		}
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, map[string]any{"preferences": prefs})
+}
+
+// Diff compares the profile with the instances in the instanceIds query
+// parameter, or with the profile's own instances when it is absent.
+func (h *PreferenceProfileHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	profile, ok := h.loadProfile(w, r)
+	if !ok {
+		return
+	}
+
+	instanceIDs := profile.InstanceIDs
+	if raw := strings.TrimSpace(r.URL.Query().Get("instanceIds")); raw != "" {
+		instanceIDs = nil
+		for part := range strings.SplitSeq(raw, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || id <= 0 {
+				RespondError(w, http.StatusBadRequest, "Invalid instanceIds parameter")
+				return
+			}
+			instanceIDs = append(instanceIDs, id)
+		}
+	}
+
+	RespondJSON(w, http.StatusOK, h.service.DiffInstances(r.Context(), profile, instanceIDs))
+}
+
+// Push applies the profile to the requested instances, or to the profile's
+// own instances when none are given.
+func (h *PreferenceProfileHandler) Push(w http.ResponseWriter, r *http.Request) {
+	profile, ok := h.loadProfile(w, r)
+	if !ok {
+		return
+	}
+
+	var req PreferenceProfilePushRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+	instanceIDs := req.InstanceIDs
+	if len(instanceIDs) == 0 {
+		instanceIDs = profile.InstanceIDs
+	}
+	if len(instanceIDs) == 0 {
+		RespondError(w, http.StatusBadRequest, "No instances to push to")
+		return
+	}
+	for _, id := range instanceIDs {
+		if id <= 0 {
+			RespondError(w, http.StatusBadRequest, "Invalid instance ID")
+			return
+		}
+	}
+
+	RespondJSON(w, http.StatusOK, h.service.Push(r.Context(), profile, instanceIDs))
+}
+
+func (h *PreferenceProfileHandler) loadProfile(w http.ResponseWriter, r *http.Request) (*models.PreferenceProfile, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid preference profile ID")
+		return nil, false
+	}
+
+	profile, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Preference profile not found")
+			return nil, false
+		}
+		log.Error().Err(err).Int("id", id).Msg("failed to load preference profile")
+		RespondError(w, http.StatusInternalServerError, "Failed to load preference profile")
+		return nil, false
+	}
+	return profile, true
+}
@@ -5,6 +5,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -24,13 +25,9 @@ func NewPreferencesHandler(syncManager *qbittorrent.SyncManager) *PreferencesHan
 	}
 }
 
-// GetPreferences returns app preferences for an instance
-// TODO: The go-qbittorrent library is missing network interface list endpoints:
-// - /api/v2/app/networkInterfaceList (to get available network interfaces)
-// - /api/v2/app/networkInterfaceAddressList (to get addresses for an interface)
-// These are needed to properly populate network interface dropdowns like the official WebUI.
-// For now, current_network_interface and current_interface_address show actual values but
-// cannot be configured with proper dropdown selections.
+// GetPreferences returns app preferences for an instance. The choices for
+// current_network_interface and current_interface_address come from
+// GetNetworkInterfaces and GetNetworkInterfaceAddresses.
 func (h *PreferencesHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
 	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
 	if err != nil {
@@ -77,6 +74,18 @@ func (h *PreferencesHandler) UpdatePreferences(w http.ResponseWriter, r *http.Re
 		http.Error(w, "Invalid scan_dirs value", http.StatusBadRequest)
 		return
 	}
+	if err := h.syncManager.ValidateNetworkBindingPreferences(r.Context(), instanceID, prefs); err != nil {
+		if errors.Is(err, qbittorrent.ErrInvalidNetworkBinding) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if respondIfInstanceDisabled(w, err, instanceID, "preferences:validateNetworkBinding") {
+			return
+		}
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("Failed to validate network binding preferences")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	// NOTE: qBittorrent's app/setPreferences API does not properly support all preferences.
 	// Specifically, start_paused_enabled gets rejected/ignored. The frontend now handles
@@ -109,6 +118,51 @@ func (h *PreferencesHandler) UpdatePreferences(w http.ResponseWriter, r *http.Re
 	}
 }
 
+// GetNetworkInterfaces lists the network interfaces the instance can bind to
+func (h *PreferencesHandler) GetNetworkInterfaces(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid instance ID")
+		http.Error(w, "Invalid instance ID", http.StatusBadRequest)
+		return
+	}
+
+	interfaces, err := h.syncManager.GetNetworkInterfaces(r.Context(), instanceID)
+	if err != nil {
+		if respondIfInstanceDisabled(w, err, instanceID, "preferences:getNetworkInterfaces") {
+			return
+		}
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("Failed to get network interfaces")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, interfaces)
+}
+
+// GetNetworkInterfaceAddresses lists the addresses of the interface in the
+// iface query parameter, or of all interfaces when it is empty
+func (h *PreferencesHandler) GetNetworkInterfaceAddresses(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid instance ID")
+		http.Error(w, "Invalid instance ID", http.StatusBadRequest)
+		return
+	}
+
+	addresses, err := h.syncManager.GetNetworkInterfaceAddresses(r.Context(), instanceID, r.URL.Query().Get("iface"))
+	if err != nil {
+		if respondIfInstanceDisabled(w, err, instanceID, "preferences:getNetworkInterfaceAddresses") {
+			return
+		}
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("Failed to get network interface addresses")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, addresses)
+}
+
 // GetAlternativeSpeedLimitsMode returns the current alternative speed limits mode
 func (h *PreferencesHandler) GetAlternativeSpeedLimitsMode(w http.ResponseWriter, r *http.Request) {
 	instanceID, err := strconv.Atoi(chi.URLParam(r, "instanceID"))
@@ -27,6 +27,7 @@ import (
 
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/qbittorrent"
+	"github.com/autobrr/qui/internal/services/bulkjobs"
 	"github.com/autobrr/qui/internal/services/jackett"
 	"github.com/autobrr/qui/pkg/redact"
 	"github.com/autobrr/qui/pkg/torrentname"
@@ -59,6 +60,7 @@ type TorrentsHandler struct {
 	torrentDownloader torrentDownloader
 	contentResolver   torrentContentResolver
 	archiveExporter   torrentArchiveExporter
+	bulkJobs          *bulkjobs.Manager
 }
 
 // truncateExpr truncates long filter expressions for cleaner logging
@@ -104,6 +106,11 @@ func NewTorrentsHandler(syncManager *qbittorrent.SyncManager, jackettService *ja
 	}
 }
 
+// SetBulkJobManager enables async bulk actions, which run as background jobs.
+func (h *TorrentsHandler) SetBulkJobManager(manager *bulkjobs.Manager) {
+	h.bulkJobs = manager
+}
+
 // NewTorrentsHandlerForTesting creates a TorrentsHandler with mock interfaces for testing
 func NewTorrentsHandlerForTesting(adder torrentAdder, downloader torrentDownloader) *TorrentsHandler {
 	return &TorrentsHandler{
@@ -1160,6 +1167,7 @@ type BulkActionRequest struct {
 	TrackerOldURL            string                     `json:"trackerOldURL,omitempty"`            // For editTrackers action
 	TrackerNewURL            string                     `json:"trackerNewURL,omitempty"`            // For editTrackers action
 	TrackerURLs              string                     `json:"trackerURLs,omitempty"`              // For addTrackers/removeTrackers actions
+	Async                    bool                       `json:"async,omitempty"`                    // Run as a background job; responds 202 with the job
 }
 
 type BulkActionTarget struct {
@@ -1562,6 +1570,11 @@ func (h *TorrentsHandler) BulkAction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Async {
+		h.submitBulkJob(w, req, targetsByInstance)
+		return
+	}
+
 	targetInstanceIDs := make([]int, 0, len(targetsByInstance))
 	for targetInstanceID := range targetsByInstance {
 		targetInstanceIDs = append(targetInstanceIDs, targetInstanceID)
@@ -0,0 +1,177 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/automations"
+)
+
+// HnRReporter builds the per-instance hit-and-run risk report.
+type HnRReporter interface {
+	HnRReport(ctx context.Context, instanceID int) ([]automations.HnRRisk, error)
+}
+
+type TrackerRequirementHandler struct {
+	store    *models.TrackerRequirementStore
+	reporter HnRReporter
+}
+
+func NewTrackerRequirementHandler(store *models.TrackerRequirementStore, reporter HnRReporter) *TrackerRequirementHandler {
+	return &TrackerRequirementHandler{
+		store:    store,
+		reporter: reporter,
+	}
+}
+
+type TrackerRequirementPayload struct {
+	Name               string                              `json:"name"`
+	Domains            []string                            `json:"domains"`
+	Enabled            *bool                               `json:"enabled,omitempty"`
+	MinRatio           float64                             `json:"minRatio"`
+	MinSeedTimeMinutes int64                               `json:"minSeedTimeMinutes"`
+	GracePeriodMinutes int64                               `json:"gracePeriodMinutes"`
+	RequireAll         bool                                `json:"requireAll"`
+	CategoryOverrides  []models.TrackerRequirementOverride `json:"categoryOverrides,omitempty"`
+}
+
+func (p *TrackerRequirementPayload) toModel(id int) *models.TrackerRequirement {
+	enabled := true
+	if p.Enabled != nil {
+		enabled = *p.Enabled
+	}
+	return &models.TrackerRequirement{
+		ID:                 id,
+		Name:               strings.TrimSpace(p.Name),
+		Domains:            normalizeDomains(p.Domains),
+		Enabled:            enabled,
+		MinRatio:           p.MinRatio,
+		MinSeedTimeMinutes: p.MinSeedTimeMinutes,
+		GracePeriodMinutes: p.GracePeriodMinutes,
+		RequireAll:         p.RequireAll,
+		CategoryOverrides:  p.CategoryOverrides,
+	}
+}
+
+func (h *TrackerRequirementHandler) List(w http.ResponseWriter, r *http.Request) {
+	requirements, err := h.store.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list tracker requirements")
+		RespondError(w, http.StatusInternalServerError, "Failed to load tracker requirements")
+		return
+	}
+	if requirements == nil {
+		requirements = []*models.TrackerRequirement{}
+	}
+
+	RespondJSON(w, http.StatusOK, requirements)
+}
+
+func (h *TrackerRequirementHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var payload TrackerRequirementPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	requirement := payload.toModel(0)
+	if err := requirement.Validate(); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid tracker requirement: "+err.Error())
+		return
+	}
+
+	created, err := h.store.Create(r.Context(), requirement)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create tracker requirement")
+		RespondError(w, http.StatusInternalServerError, "Failed to create tracker requirement")
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, created)
+}
+
+func (h *TrackerRequirementHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid requirement ID")
+		return
+	}
+
+	var payload TrackerRequirementPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	requirement := payload.toModel(id)
+	if err := requirement.Validate(); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid tracker requirement: "+err.Error())
+		return
+	}
+
+	updated, err := h.store.Update(r.Context(), requirement)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Tracker requirement not found")
+			return
+		}
+		log.Error().Err(err).Int("id", id).Msg("failed to update tracker requirement")
+		RespondError(w, http.StatusInternalServerError, "Failed to update tracker requirement")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, updated)
+}
+
+func (h *TrackerRequirementHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid requirement ID")
+		return
+	}
+
+	if err := h.store.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Tracker requirement not found")
+			return
+		}
+		log.Error().Err(err).Int("id", id).Msg("failed to delete tracker requirement")
+		RespondError(w, http.StatusInternalServerError, "Failed to delete tracker requirement")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Report lists the instance's torrents that have not met their tracker's seeding requirement.
+func (h *TrackerRequirementHandler) Report(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := parseInstanceID(w, r)
+	if err != nil {
+		return
+	}
+	if h.reporter == nil {
+		RespondError(w, http.StatusServiceUnavailable, "Tracker requirement report unavailable")
+		return
+	}
+
+	risks, err := h.reporter.HnRReport(r.Context(), instanceID)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("failed to build hit-and-run report")
+		RespondError(w, http.StatusInternalServerError, "Failed to build hit-and-run report")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, risks)
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// defaultTransferStatsRange is used when a query has no from.
+const defaultTransferStatsRange = 24 * time.Hour
+
+type TransferStatsHandler struct {
+	store *models.TransferStatsStore
+	now   func() time.Time
+}
+
+func NewTransferStatsHandler(store *models.TransferStatsStore) *TransferStatsHandler {
+	return &TransferStatsHandler{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+type TransferStatsSeriesResponse struct {
+	InstanceID *int                           `json:"instanceId,omitempty"`
+	Tracker    string                         `json:"tracker,omitempty"`
+	Resolution models.TransferStatsResolution `json:"resolution"`
+	From       time.Time                      `json:"from"`
+	To         time.Time                      `json:"to"`
+	Points     []models.TransferStatsPoint    `json:"points"`
+}
+
+type TransferStatsTrackersResponse struct {
+	InstanceID *int                               `json:"instanceId,omitempty"`
+	Resolution models.TransferStatsResolution     `json:"resolution"`
+	From       time.Time                          `json:"from"`
+	To         time.Time                          `json:"to"`
+	Trackers   []models.TransferStatsTrackerTotal `json:"trackers"`
+}
+
+// transferStatsRange is the parsed range of a transfer stats query.
+type transferStatsRange struct {
+	instanceID *int
+	resolution models.TransferStatsResolution
+	from       time.Time
+	to         time.Time
+}
+
+// Series returns upload/download/torrent-count buckets for an instance (or all
+// instances) and optionally a single tracker.
+func (h *TransferStatsHandler) Series(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	rng, err := h.parseRange(query)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tracker := strings.TrimSpace(query.Get("tracker"))
+	points, err := h.store.Series(r.Context(), models.TransferStatsQuery{
+		InstanceID: rng.instanceID,
+		Tracker:    tracker,
+		Resolution: rng.resolution,
+		From:       rng.from,
+		To:         rng.to,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load transfer stats")
+		RespondError(w, http.StatusInternalServerError, "Failed to load transfer stats")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, TransferStatsSeriesResponse{
+		InstanceID: rng.instanceID,
+		Tracker:    tracker,
+		Resolution: rng.resolution,
+		From:       rng.from,
+		To:         rng.to,
+		Points:     points,
+	})
+}
+
+// Trackers returns each tracker's total transfer over the range.
+func (h *TransferStatsHandler) Trackers(w http.ResponseWriter, r *http.Request) {
+	rng, err := h.parseRange(r.URL.Query())
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	totals, err := h.store.TrackerTotals(r.Context(), rng.instanceID, rng.resolution, rng.from, rng.to)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load tracker transfer stats")
+		RespondError(w, http.StatusInternalServerError, "Failed to load transfer stats")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, TransferStatsTrackersResponse{
+		InstanceID: rng.instanceID,
+		Resolution: rng.resolution,
+		From:       rng.from,
+		To:         rng.to,
+		Trackers:   totals,
+	})
+}
+
+// parseRange reads instanceId, from, to (RFC3339) and resolution. Without a
+// resolution (or with "auto") the finest one covering the range is used.
+func (h *TransferStatsHandler) parseRange(query url.Values) (transferStatsRange, error) {
+	now := h.now().UTC()
+	rng := transferStatsRange{to: now}
+
+	if raw := query.Get("instanceId"); raw != "" {
+		instanceID, err := strconv.Atoi(raw)
+		if err != nil || instanceID <= 0 {
+			return rng, errors.New("invalid instanceId")
+		}
+		rng.instanceID = &instanceID
+	}
+	if raw := query.Get("to"); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return rng, errors.New("invalid to: expected RFC3339 time")
+		}
+		rng.to = to.UTC()
+	}
+	rng.from = rng.to.Add(-defaultTransferStatsRange)
+	if raw := query.Get("from"); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return rng, errors.New("invalid from: expected RFC3339 time")
+		}
+		rng.from = from.UTC()
+	}
+	if !rng.from.Before(rng.to) {
+		return rng, errors.New("from must be before to")
+	}
+
+	switch raw := models.TransferStatsResolution(strings.ToLower(query.Get("resolution"))); raw {
+	case "", "auto":
+		rng.resolution = models.TransferStatsResolutionFor(rng.from, rng.to, now)
+	default:
+		if !raw.Valid() {
+			return rng, fmt.Errorf("invalid resolution %q", raw)
+		}
+		rng.resolution = raw
+	}
+	return rng, nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestTransferStatsParseRange(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	h := &TransferStatsHandler{now: func() time.Time { return now }}
+
+	rng, err := h.parseRange(url.Values{})
+	require.NoError(t, err)
+	assert.Nil(t, rng.instanceID)
+	assert.Equal(t, now.Add(-24*time.Hour), rng.from)
+	assert.Equal(t, now, rng.to)
+	assert.Equal(t, models.TransferStatsMinute, rng.resolution)
+
+	rng, err = h.parseRange(url.Values{"instanceId": {"2"}, "from": {"2026-02-01T00:00:00Z"}, "to": {"2026-03-01T00:00:00Z"}})
+	require.NoError(t, err)
+	require.NotNil(t, rng.instanceID)
+	assert.Equal(t, 2, *rng.instanceID)
+	assert.Equal(t, models.TransferStatsHour, rng.resolution)
+
+	rng, err = h.parseRange(url.Values{"resolution": {"day"}})
+	require.NoError(t, err)
+	assert.Equal(t, models.TransferStatsDay, rng.resolution)
+
+	for name, query := range map[string]url.Values{
+		"bad instance":   {"instanceId": {"x"}},
+		"bad from":       {"from": {"yesterday"}},
+		"reversed":       {"from": {"2026-03-11T00:00:00Z"}},
+		"bad resolution": {"resolution": {"week"}},
+	} {
+		_, err := h.parseRange(query)
+		assert.Error(t, err, name)
+	}
+}
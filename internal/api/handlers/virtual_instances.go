@@ -0,0 +1,161 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+type VirtualInstanceHandler struct {
+	store         *models.VirtualInstanceStore
+	instanceStore *models.InstanceStore
+}
+
+func NewVirtualInstanceHandler(store *models.VirtualInstanceStore, instanceStore *models.InstanceStore) *VirtualInstanceHandler {
+	return &VirtualInstanceHandler{
+		store:         store,
+		instanceStore: instanceStore,
+	}
+}
+
+type VirtualInstancePayload struct {
+	Name              string                          `json:"name"`
+	MemberInstanceIDs []int                           `json:"memberInstanceIds"`
+	Placement         models.VirtualInstancePlacement `json:"placement"`
+	CategoryInstances map[string]int                  `json:"categoryInstances,omitempty"`
+}
+
+func (p *VirtualInstancePayload) toModel(id int) *models.VirtualInstance {
+	placement := p.Placement
+	if placement == "" {
+		placement = models.PlacementRoundRobin
+	}
+	return &models.VirtualInstance{
+		ID:                id,
+		Name:              p.Name,
+		MemberInstanceIDs: p.MemberInstanceIDs,
+		Placement:         placement,
+		CategoryInstances: p.CategoryInstances,
+	}
+}
+
+func (h *VirtualInstanceHandler) List(w http.ResponseWriter, r *http.Request) {
+	instances, err := h.store.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list virtual instances")
+		RespondError(w, http.StatusInternalServerError, "Failed to load virtual instances")
+		return
+	}
+	if instances == nil {
+		instances = []*models.VirtualInstance{}
+	}
+
+	RespondJSON(w, http.StatusOK, instances)
+}
+
+func (h *VirtualInstanceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var payload VirtualInstancePayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	virtual := payload.toModel(0)
+	if err := h.validate(r.Context(), virtual); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid virtual instance: "+err.Error())
+		return
+	}
+
+	created, err := h.store.Create(r.Context(), virtual)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create virtual instance")
+		RespondError(w, http.StatusInternalServerError, "Failed to create virtual instance")
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, created)
+}
+
+func (h *VirtualInstanceHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid virtual instance ID")
+		return
+	}
+
+	var payload VirtualInstancePayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	virtual := payload.toModel(id)
+	if err := h.validate(r.Context(), virtual); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid virtual instance: "+err.Error())
+		return
+	}
+
+	updated, err := h.store.Update(r.Context(), virtual)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Virtual instance not found")
+			return
+		}
+		log.Error().Err(err).Int("id", id).Msg("failed to update virtual instance")
+		RespondError(w, http.StatusInternalServerError, "Failed to update virtual instance")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, updated)
+}
+
+func (h *VirtualInstanceHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		RespondError(w, http.StatusBadRequest, "Invalid virtual instance ID")
+		return
+	}
+
+	if err := h.store.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondError(w, http.StatusNotFound, "Virtual instance not found")
+			return
+		}
+		log.Error().Err(err).Int("id", id).Msg("failed to delete virtual instance")
+		RespondError(w, http.StatusInternalServerError, "Failed to delete virtual instance")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validate checks the virtual instance and that every member is a known instance.
+func (h *VirtualInstanceHandler) validate(ctx context.Context, virtual *models.VirtualInstance) error {
+	if err := virtual.Validate(); err != nil {
+		return err
+	}
+	if h.instanceStore == nil {
+		return nil
+	}
+	for _, id := range virtual.MemberInstanceIDs {
+		if _, err := h.instanceStore.Get(ctx, id); err != nil {
+			if errors.Is(err, models.ErrInstanceNotFound) {
+				return fmt.Errorf("instance %d not found", id)
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/autobrr/qui/internal/api/ctxkeys"
+	"github.com/autobrr/qui/internal/models"
+)
+
+// maxAuditBodyPeek bounds how much of a JSON body is buffered to find affected hashes.
+const maxAuditBodyPeek = 4 << 20
+
+// AuditRecorder receives audit entries without blocking the request.
+type AuditRecorder interface {
+	Record(entry *models.AuditEntry)
+}
+
+// Audit records every mutating request (POST, PUT, PATCH, DELETE) with the
+// authenticated actor, route, instance, affected hashes and response status.
+// It must run after IsAuthenticated.
+func Audit(recorder AuditRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if recorder == nil || !isMutatingMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			hashes := peekJSONHashes(r)
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			actorType, actor := auditActor(r)
+			entry := &models.AuditEntry{
+				Source:    models.AuditSourceAPI,
+				ActorType: actorType,
+				Actor:     actor,
+				Method:    r.Method,
+				Endpoint:  r.URL.Path,
+				Hashes:    hashes,
+				Status:    status,
+				Success:   status < http.StatusBadRequest,
+			}
+			// The route pattern is only complete once the router has matched the request.
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				if pattern := rctx.RoutePattern(); pattern != "" {
+					entry.Endpoint = pattern
+				}
+				if instanceID, err := strconv.Atoi(rctx.URLParam("instanceID")); err == nil {
+					entry.InstanceID = &instanceID
+				}
+			}
+			recorder.Record(entry)
+		})
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func auditActor(r *http.Request) (actorType, actor string) {
+	if name, ok := r.Context().Value(ctxkeys.APIKeyName).(string); ok {
+		return models.AuditActorAPIKey, name
+	}
+	username, _ := r.Context().Value(ctxkeys.Username).(string)
+	return models.AuditActorUser, username
+}
+
+// peekJSONHashes returns the "hashes"/"hash" fields of a JSON body without
+// consuming it.
+func peekJSONHashes(r *http.Request) []string {
+	if r.Body == nil || r.ContentLength > maxAuditBodyPeek {
+		return nil
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAuditBodyPeek+1))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if err != nil || len(body) > maxAuditBodyPeek {
+		return nil
+	}
+
+	var payload struct {
+		Hashes json.RawMessage `json:"hashes"`
+		Hash   string          `json:"hash"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil
+	}
+
+	var hashes []string
+	if len(payload.Hashes) > 0 {
+		// Hashes are sent as a list by the qui API and as a "|" separated string elsewhere.
+		var list []string
+		var joined string
+		if json.Unmarshal(payload.Hashes, &list) == nil {
+			hashes = list
+		} else if json.Unmarshal(payload.Hashes, &joined) == nil {
+			hashes = strings.Split(joined, "|")
+		}
+	}
+	if payload.Hash != "" {
+		hashes = append(hashes, payload.Hash)
+	}
+
+	normalized := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		if hash = strings.ToUpper(strings.TrimSpace(hash)); hash != "" {
+			normalized = append(normalized, hash)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/api/ctxkeys"
+	"github.com/autobrr/qui/internal/models"
+)
+
+type recordedAudit struct {
+	entries []*models.AuditEntry
+}
+
+func (r *recordedAudit) Record(entry *models.AuditEntry) {
+	r.entries = append(r.entries, entry)
+}
+
+func TestAuditRecordsMutatingRequests(t *testing.T) {
+	recorder := &recordedAudit{}
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), ctxkeys.APIKeyName, "autobrr")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	router.Use(Audit(recorder))
+
+	var forwarded string
+	router.Post("/instances/{instanceID}/torrents/bulk-action", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		forwarded = string(body)
+		w.WriteHeader(http.StatusConflict)
+	})
+	router.Get("/instances/{instanceID}/torrents", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	body := `{"hashes":["abc"," def "],"action":"pause"}`
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/instances/3/torrents/bulk-action", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/instances/3/torrents", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, body, forwarded, "the handler still sees the full body")
+	require.Len(t, recorder.entries, 1, "reads are not audited")
+
+	entry := recorder.entries[0]
+	assert.Equal(t, models.AuditSourceAPI, entry.Source)
+	assert.Equal(t, models.AuditActorAPIKey, entry.ActorType)
+	assert.Equal(t, "autobrr", entry.Actor)
+	assert.Equal(t, "/instances/{instanceID}/torrents/bulk-action", entry.Endpoint)
+	require.NotNil(t, entry.InstanceID)
+	assert.Equal(t, 3, *entry.InstanceID)
+	assert.Equal(t, []string{"ABC", "DEF"}, entry.Hashes)
+	assert.Equal(t, http.StatusConflict, entry.Status)
+	assert.False(t, entry.Success)
+}
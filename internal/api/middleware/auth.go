@@ -31,13 +31,15 @@ func IsAuthenticated(authService *auth.Service, sessionManager *scs.SessionManag
 			apiKey := r.Header.Get("X-API-Key")
 			if apiKey != "" {
 				// Validate API key
-				if _, err := authService.ValidateAPIKey(r.Context(), apiKey); err != nil {
+				key, err := authService.ValidateAPIKey(r.Context(), apiKey)
+				if err != nil {
 					log.Warn().Err(err).Msg("Invalid API key")
 					http.Error(w, "Unauthorized", http.StatusUnauthorized)
 					return
 				}
 
-				next.ServeHTTP(w, r)
+				ctx := context.WithValue(r.Context(), ctxkeys.APIKeyName, key.Name)
+				next.ServeHTTP(w, r.WithContext(ctx))
 				return
 			}
 
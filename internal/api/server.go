@@ -30,7 +30,9 @@ import (
 	"github.com/autobrr/qui/internal/qbittorrent"
 	"github.com/autobrr/qui/internal/services/activity"
 	"github.com/autobrr/qui/internal/services/arr"
+	"github.com/autobrr/qui/internal/services/audit"
 	"github.com/autobrr/qui/internal/services/automations"
+	"github.com/autobrr/qui/internal/services/bulkjobs"
 	"github.com/autobrr/qui/internal/services/crossseed"
 	"github.com/autobrr/qui/internal/services/dirscan"
 	"github.com/autobrr/qui/internal/services/externalprograms"
@@ -39,6 +41,7 @@ import (
 	"github.com/autobrr/qui/internal/services/license"
 	"github.com/autobrr/qui/internal/services/notifications"
 	"github.com/autobrr/qui/internal/services/orphanscan"
+	"github.com/autobrr/qui/internal/services/prefprofiles"
 	"github.com/autobrr/qui/internal/services/reannounce"
 	"github.com/autobrr/qui/internal/services/trackericons"
 	"github.com/autobrr/qui/internal/update"
@@ -75,9 +78,18 @@ type Server struct {
 	jackettService                   *jackett.Service
 	torznabIndexerStore              *models.TorznabIndexerStore
 	automationStore                  *models.AutomationStore
+	automationRevisionStore          *models.AutomationRevisionStore
 	automationActivityStore          *models.AutomationActivityStore
 	automationService                *automations.Service
 	trackerCustomizationStore        *models.TrackerCustomizationStore
+	trackerRequirementStore          *models.TrackerRequirementStore
+	virtualInstanceStore             *models.VirtualInstanceStore
+	auditStore                       *models.AuditStore
+	transferStatsStore               *models.TransferStatsStore
+	preferenceProfileStore           *models.PreferenceProfileStore
+	preferenceProfileService         *prefprofiles.Service
+	bulkJobManager                   *bulkjobs.Manager
+	auditService                     *audit.Service
 	dashboardSettingsStore           *models.DashboardSettingsStore
 	themeSettingsStore               *models.ThemeSettingsStore
 	filterViewStore                  *models.FilterViewStore
@@ -118,9 +130,18 @@ type Dependencies struct {
 	JackettService                   *jackett.Service
 	TorznabIndexerStore              *models.TorznabIndexerStore
 	AutomationStore                  *models.AutomationStore
+	AutomationRevisionStore          *models.AutomationRevisionStore
 	AutomationActivityStore          *models.AutomationActivityStore
 	AutomationService                *automations.Service
 	TrackerCustomizationStore        *models.TrackerCustomizationStore
+	TrackerRequirementStore          *models.TrackerRequirementStore
+	VirtualInstanceStore             *models.VirtualInstanceStore
+	AuditStore                       *models.AuditStore
+	TransferStatsStore               *models.TransferStatsStore
+	PreferenceProfileStore           *models.PreferenceProfileStore
+	PreferenceProfileService         *prefprofiles.Service
+	BulkJobManager                   *bulkjobs.Manager
+	AuditService                     *audit.Service
 	DashboardSettingsStore           *models.DashboardSettingsStore
 	ThemeSettingsStore               *models.ThemeSettingsStore
 	FilterViewStore                  *models.FilterViewStore
@@ -184,9 +205,18 @@ func NewServer(deps *Dependencies) *Server {
 		jackettService:                   deps.JackettService,
 		torznabIndexerStore:              deps.TorznabIndexerStore,
 		automationStore:                  deps.AutomationStore,
+		automationRevisionStore:          deps.AutomationRevisionStore,
 		automationActivityStore:          deps.AutomationActivityStore,
 		automationService:                deps.AutomationService,
 		trackerCustomizationStore:        deps.TrackerCustomizationStore,
+		trackerRequirementStore:          deps.TrackerRequirementStore,
+		virtualInstanceStore:             deps.VirtualInstanceStore,
+		auditStore:                       deps.AuditStore,
+		transferStatsStore:               deps.TransferStatsStore,
+		preferenceProfileStore:           deps.PreferenceProfileStore,
+		preferenceProfileService:         deps.PreferenceProfileService,
+		bulkJobManager:                   deps.BulkJobManager,
+		auditService:                     deps.AuditService,
 		dashboardSettingsStore:           deps.DashboardSettingsStore,
 		themeSettingsStore:               deps.ThemeSettingsStore,
 		filterViewStore:                  deps.FilterViewStore,
@@ -348,8 +378,9 @@ func (s *Server) Handler() (*chi.Mux, error) {
 	}
 	instancesHandler := handlers.NewInstancesHandler(s.instanceStore, s.instanceReannounce, s.reannounceCache, s.clientPool, s.syncManager, s.reannounceService)
 	torrentsHandler := handlers.NewTorrentsHandler(s.syncManager, s.jackettService, s.instanceStore)
+	torrentsHandler.SetBulkJobManager(s.bulkJobManager)
 	preferencesHandler := handlers.NewPreferencesHandler(s.syncManager)
-	clientAPIKeysHandler := handlers.NewClientAPIKeysHandler(s.clientAPIKeyStore, s.instanceStore, s.config.Config.BaseURL)
+	clientAPIKeysHandler := handlers.NewClientAPIKeysHandler(s.clientAPIKeyStore, s.instanceStore, s.virtualInstanceStore, s.config.Config.BaseURL)
 	externalProgramsHandler := handlers.NewExternalProgramsHandler(s.externalProgramStore, s.externalProgramService, s.clientPool, s.automationStore)
 	arrHandler := handlers.NewArrHandler(s.arrInstanceStore, s.arrService)
 	versionHandler := handlers.NewVersionHandler(s.updateService, s.version)
@@ -358,6 +389,15 @@ func (s *Server) Handler() (*chi.Mux, error) {
 	backupsHandler := handlers.NewBackupsHandler(s.backupService)
 	trackerIconHandler := handlers.NewTrackerIconHandler(s.trackerIconService)
 	proxyHandler := proxy.NewHandler(s.clientPool, s.clientAPIKeyStore, s.instanceStore, s.syncManager, s.reannounceCache, s.reannounceService, s.config.Config.BaseURL)
+	if s.virtualInstanceStore != nil {
+		proxyHandler.SetVirtualInstanceStore(s.virtualInstanceStore)
+	}
+	if s.crossSeedService != nil {
+		proxyHandler.SetCrossSeedInjector(s.crossSeedService)
+	}
+	if s.auditService != nil {
+		proxyHandler.SetAuditRecorder(s.auditService)
+	}
 	licenseHandler := handlers.NewLicenseHandler(s.licenseService)
 	themesHandler := handlers.NewThemesHandler(s.config, s.licenseService, s.themeSettingsStore, func(ctx context.Context) bool {
 		// Auth-disabled installs never carry a session flag; every caller is the trusted admin.
@@ -369,13 +409,23 @@ func (s *Server) Handler() (*chi.Mux, error) {
 		s.instanceStore,
 		s.seasonPackRunStore,
 	)
-	automationsHandler := handlers.NewAutomationHandler(s.automationStore, s.automationActivityStore, s.instanceStore, s.externalProgramStore, s.automationService)
+	automationsHandler := handlers.NewAutomationHandler(s.automationStore, s.automationRevisionStore, s.automationActivityStore, s.instanceStore, s.externalProgramStore, s.automationService)
 	orphanScanHandler := handlers.NewOrphanScanHandler(s.orphanScanStore, s.instanceStore, s.orphanScanService)
 	var dirScanHandler *handlers.DirScanHandler
 	if s.dirScanService != nil {
 		dirScanHandler = handlers.NewDirScanHandler(s.dirScanService, s.instanceStore)
 	}
 	trackerCustomizationHandler := handlers.NewTrackerCustomizationHandler(s.trackerCustomizationStore, s.syncManager.InvalidateTrackerDisplayNameCache)
+	var hnrReporter handlers.HnRReporter
+	if s.automationService != nil {
+		hnrReporter = s.automationService
+	}
+	trackerRequirementHandler := handlers.NewTrackerRequirementHandler(s.trackerRequirementStore, hnrReporter)
+	virtualInstanceHandler := handlers.NewVirtualInstanceHandler(s.virtualInstanceStore, s.instanceStore)
+	auditHandler := handlers.NewAuditHandler(s.auditStore, s.auditService)
+	transferStatsHandler := handlers.NewTransferStatsHandler(s.transferStatsStore)
+	preferenceProfileHandler := handlers.NewPreferenceProfileHandler(s.preferenceProfileStore, s.preferenceProfileService)
+	bulkJobsHandler := handlers.NewBulkJobsHandler(s.bulkJobManager)
 	rssHandler := handlers.NewRSSHandler(s.syncManager)
 	rssSSEHandler := handlers.NewRSSSSEHandler(s.syncManager)
 	dashboardSettingsHandler := handlers.NewDashboardSettingsHandler(s.dashboardSettingsStore)
@@ -423,6 +473,13 @@ func (s *Server) Handler() (*chi.Mux, error) {
 
 		apiKeyQueryMiddleware := middleware.APIKeyFromQuery("apikey")
 		authMiddleware := middleware.IsAuthenticated(s.authService, s.sessionManager, s.config.Config)
+		if s.auditService != nil {
+			// Every authenticated route records its write calls to the audit log
+			isAuthenticated, auditMiddleware := authMiddleware, middleware.Audit(s.auditService)
+			authMiddleware = func(next http.Handler) http.Handler {
+				return isAuthenticated(auditMiddleware(next))
+			}
+		}
 
 		// Cross-seed routes (query param auth for select endpoints)
 		crossSeedHandler.Routes(r, authMiddleware, apiKeyQueryMiddleware)
@@ -469,6 +526,9 @@ func (s *Server) Handler() (*chi.Mux, error) {
 				r.Get("/", clientAPIKeysHandler.ListClientAPIKeys)
 				r.Post("/", clientAPIKeysHandler.CreateClientAPIKey)
 				r.Delete("/{id}", clientAPIKeysHandler.DeleteClientAPIKey)
+				r.Put("/{id}/scope", clientAPIKeysHandler.UpdateClientAPIKeyScope)
+				r.Put("/{id}/add-policy", clientAPIKeysHandler.UpdateClientAPIKeyAddPolicy)
+				r.Put("/{id}/duplicate-policy", clientAPIKeysHandler.UpdateClientAPIKeyDuplicatePolicy)
 			})
 
 			// External programs management
@@ -510,6 +570,57 @@ func (s *Server) Handler() (*chi.Mux, error) {
 				r.Delete("/{id}", trackerCustomizationHandler.Delete)
 			})
 
+			// Tracker seeding requirements (hit-and-run protection)
+			r.Route("/tracker-requirements", func(r chi.Router) {
+				r.Get("/", trackerRequirementHandler.List)
+				r.Post("/", trackerRequirementHandler.Create)
+				r.Put("/{id}", trackerRequirementHandler.Update)
+				r.Delete("/{id}", trackerRequirementHandler.Delete)
+			})
+
+			// Virtual instances (several instances served as one through the proxy)
+			r.Route("/virtual-instances", func(r chi.Router) {
+				r.Get("/", virtualInstanceHandler.List)
+				r.Post("/", virtualInstanceHandler.Create)
+				r.Put("/{id}", virtualInstanceHandler.Update)
+				r.Delete("/{id}", virtualInstanceHandler.Delete)
+			})
+
+			// Audit log of write calls through the API and the proxy
+			r.Route("/audit", func(r chi.Router) {
+				r.Get("/", auditHandler.List)
+				r.Get("/export", auditHandler.Export)
+				r.Get("/settings", auditHandler.GetSettings)
+				r.Put("/settings", auditHandler.UpdateSettings)
+			})
+
+			// Preference profiles shared across qBittorrent instances
+			r.Route("/preference-profiles", func(r chi.Router) {
+				r.Get("/", preferenceProfileHandler.List)
+				r.Post("/", preferenceProfileHandler.Create)
+				r.Get("/keys", preferenceProfileHandler.Keys)
+				r.Post("/snapshot", preferenceProfileHandler.Snapshot)
+				r.Get("/{id}", preferenceProfileHandler.Get)
+				r.Put("/{id}", preferenceProfileHandler.Update)
+				r.Delete("/{id}", preferenceProfileHandler.Delete)
+				r.Get("/{id}/diff", preferenceProfileHandler.Diff)
+				r.Post("/{id}/push", preferenceProfileHandler.Push)
+			})
+
+			// Bulk actions running as background jobs
+			r.Route("/bulk-jobs", func(r chi.Router) {
+				r.Get("/", bulkJobsHandler.List)
+				r.Post("/undo", bulkJobsHandler.Undo)
+				r.Get("/{id}", bulkJobsHandler.Get)
+				r.Post("/{id}/cancel", bulkJobsHandler.Cancel)
+			})
+
+			// Persisted transfer history per instance and tracker
+			r.Route("/transfer-stats", func(r chi.Router) {
+				r.Get("/", transferStatsHandler.Series)
+				r.Get("/trackers", transferStatsHandler.Trackers)
+			})
+
 			// Dashboard settings (per-user layout preferences)
 			r.Get("/dashboard-settings", dashboardSettingsHandler.Get)
 			r.Put("/dashboard-settings", dashboardSettingsHandler.Update)
@@ -606,10 +717,14 @@ func (s *Server) Handler() (*chi.Mux, error) {
 
 					// Trackers
 					r.Get("/trackers", torrentsHandler.GetActiveTrackers)
+					r.Get("/tracker-requirements/report", trackerRequirementHandler.Report)
 
 					// Automations
 					r.Route("/automations", func(r chi.Router) {
 						r.Get("/", automationsHandler.List)
+						r.Get("/shared", automationsHandler.ListShared)
+						r.Get("/export", automationsHandler.Export)
+						r.Post("/import", automationsHandler.Import)
 						r.Post("/", automationsHandler.Create)
 						r.Put("/order", automationsHandler.Reorder)
 						r.Post("/apply", automationsHandler.ApplyNow)
@@ -623,6 +738,8 @@ func (s *Server) Handler() (*chi.Mux, error) {
 						r.Route("/{ruleID}", func(r chi.Router) {
 							r.Put("/", automationsHandler.Update)
 							r.Delete("/", automationsHandler.Delete)
+							r.Get("/revisions", automationsHandler.ListRevisions)
+							r.Post("/revisions/{revisionID}/rollback", automationsHandler.RollbackRevision)
 						})
 					})
 
@@ -635,6 +752,8 @@ func (s *Server) Handler() (*chi.Mux, error) {
 					// Preferences
 					r.Get("/preferences", preferencesHandler.GetPreferences)
 					r.Patch("/preferences", preferencesHandler.UpdatePreferences)
+					r.Get("/preferences/network-interfaces", preferencesHandler.GetNetworkInterfaces)
+					r.Get("/preferences/network-interfaces/addresses", preferencesHandler.GetNetworkInterfaceAddresses)
 
 					// Alternative speed limits
 					r.Get("/alternative-speed-limits", preferencesHandler.GetAlternativeSpeedLimitsMode)
@@ -659,6 +778,11 @@ func (s *Server) Handler() (*chi.Mux, error) {
 						r.Post("/runs/{runID}/restore", backupsHandler.ExecuteRestore)
 						r.Get("/runs/{runID}/items/{torrentHash}/download", backupsHandler.DownloadTorrentBlob)
 						r.Delete("/runs/{runID}", backupsHandler.DeleteRun)
+						r.Post("/destination/test", backupsHandler.TestDestination)
+						r.Get("/remote/runs", backupsHandler.ListRemoteRuns)
+						r.Get("/remote/runs/{name}/manifest", backupsHandler.GetRemoteManifest)
+						r.Post("/remote/runs/{name}/restore/preview", backupsHandler.PreviewRemoteRestore)
+						r.Post("/remote/runs/{name}/restore", backupsHandler.ExecuteRemoteRestore)
 					})
 
 					// Orphan file scanning
@@ -26,6 +26,7 @@ import (
 	"github.com/autobrr/qui/internal/domain"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/qbittorrent"
+	"github.com/autobrr/qui/internal/services/bulkjobs"
 	"github.com/autobrr/qui/internal/services/dirscan"
 	"github.com/autobrr/qui/internal/services/license"
 	"github.com/autobrr/qui/internal/services/notifications"
@@ -42,34 +43,44 @@ type routeKey struct {
 }
 
 var undocumentedRoutes = map[routeKey]struct{}{
-	{Method: http.MethodGet, Path: "/api/auth/validate"}:                                            {},
-	{Method: http.MethodGet, Path: "/api/stream"}:                                                   {},
-	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/backups/run"}:                      {},
-	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/backups/runs"}:                      {},
-	{Method: http.MethodDelete, Path: "/api/instances/{instanceId}/backups/runs"}:                   {},
-	{Method: http.MethodDelete, Path: "/api/instances/{instanceId}/backups/runs/{runId}"}:           {},
-	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/backups/runs/{runId}/manifest"}:     {},
-	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/backups/settings"}:                  {},
-	{Method: http.MethodPut, Path: "/api/instances/{instanceId}/backups/settings"}:                  {},
-	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/automations"}:                       {},
-	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations"}:                      {},
-	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations/apply"}:                {},
-	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations/dry-run"}:              {},
-	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations/preview"}:              {},
-	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations/validate-regex"}:       {},
-	{Method: http.MethodPut, Path: "/api/instances/{instanceId}/automations/order"}:                 {},
-	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/automations/activity"}:              {},
-	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/automations/activity/{activityId}"}: {},
-	{Method: http.MethodDelete, Path: "/api/instances/{instanceId}/automations/activity"}:           {},
-	{Method: http.MethodDelete, Path: "/api/instances/{instanceId}/automations/{ruleID}"}:           {},
-	{Method: http.MethodPut, Path: "/api/instances/{instanceId}/automations/{ruleID}"}:              {},
-	{Method: http.MethodGet, Path: "/api/application/info"}:                                         {},
-	{Method: http.MethodGet, Path: "/api/tracker-customizations"}:                                   {},
-	{Method: http.MethodPost, Path: "/api/tracker-customizations"}:                                  {},
-	{Method: http.MethodPut, Path: "/api/tracker-customizations/{id}"}:                              {},
-	{Method: http.MethodDelete, Path: "/api/tracker-customizations/{id}"}:                           {},
-	{Method: http.MethodGet, Path: "/api/dashboard-settings"}:                                       {},
-	{Method: http.MethodPut, Path: "/api/dashboard-settings"}:                                       {},
+	{Method: http.MethodGet, Path: "/api/auth/validate"}:                                                                {},
+	{Method: http.MethodGet, Path: "/api/stream"}:                                                                       {},
+	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/backups/run"}:                                          {},
+	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/backups/runs"}:                                          {},
+	{Method: http.MethodDelete, Path: "/api/instances/{instanceId}/backups/runs"}:                                       {},
+	{Method: http.MethodDelete, Path: "/api/instances/{instanceId}/backups/runs/{runId}"}:                               {},
+	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/backups/runs/{runId}/manifest"}:                         {},
+	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/backups/settings"}:                                      {},
+	{Method: http.MethodPut, Path: "/api/instances/{instanceId}/backups/settings"}:                                      {},
+	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/automations"}:                                           {},
+	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations"}:                                          {},
+	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations/apply"}:                                    {},
+	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations/dry-run"}:                                  {},
+	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations/preview"}:                                  {},
+	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations/validate-regex"}:                           {},
+	{Method: http.MethodPut, Path: "/api/instances/{instanceId}/automations/order"}:                                     {},
+	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/automations/shared"}:                                    {},
+	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/automations/export"}:                                    {},
+	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations/import"}:                                   {},
+	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/automations/{ruleID}/revisions"}:                        {},
+	{Method: http.MethodPost, Path: "/api/instances/{instanceId}/automations/{ruleID}/revisions/{revisionID}/rollback"}: {},
+	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/automations/activity"}:                                  {},
+	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/automations/activity/{activityId}"}:                     {},
+	{Method: http.MethodDelete, Path: "/api/instances/{instanceId}/automations/activity"}:                               {},
+	{Method: http.MethodDelete, Path: "/api/instances/{instanceId}/automations/{ruleID}"}:                               {},
+	{Method: http.MethodPut, Path: "/api/instances/{instanceId}/automations/{ruleID}"}:                                  {},
+	{Method: http.MethodGet, Path: "/api/application/info"}:                                                             {},
+	{Method: http.MethodGet, Path: "/api/tracker-customizations"}:                                                       {},
+	{Method: http.MethodPost, Path: "/api/tracker-customizations"}:                                                      {},
+	{Method: http.MethodPut, Path: "/api/tracker-customizations/{id}"}:                                                  {},
+	{Method: http.MethodDelete, Path: "/api/tracker-customizations/{id}"}:                                               {},
+	{Method: http.MethodGet, Path: "/api/tracker-requirements"}:                                                         {},
+	{Method: http.MethodPost, Path: "/api/tracker-requirements"}:                                                        {},
+	{Method: http.MethodPut, Path: "/api/tracker-requirements/{id}"}:                                                    {},
+	{Method: http.MethodDelete, Path: "/api/tracker-requirements/{id}"}:                                                 {},
+	{Method: http.MethodGet, Path: "/api/instances/{instanceId}/tracker-requirements/report"}:                           {},
+	{Method: http.MethodGet, Path: "/api/dashboard-settings"}:                                                           {},
+	{Method: http.MethodPut, Path: "/api/dashboard-settings"}:                                                           {},
 }
 
 func TestNewServerRegistersStreamManagerAsSyncSink(t *testing.T) {
@@ -159,7 +170,14 @@ func newTestDependencies(t *testing.T) *Dependencies {
 		TrackerIconService:        trackerIconService,
 		BackupService:             &backups.Service{},
 		AutomationStore:           models.NewAutomationStore(db),
+		AutomationRevisionStore:   models.NewAutomationRevisionStore(db),
 		TrackerCustomizationStore: trackerCustomizationStore,
+		TrackerRequirementStore:   models.NewTrackerRequirementStore(db),
+		VirtualInstanceStore:      models.NewVirtualInstanceStore(db),
+		AuditStore:                models.NewAuditStore(db),
+		TransferStatsStore:        models.NewTransferStatsStore(db),
+		PreferenceProfileStore:    models.NewPreferenceProfileStore(db),
+		BulkJobManager:            bulkjobs.NewManager(nil),
 		DashboardSettingsStore:    models.NewDashboardSettingsStore(db),
 		FilterViewStore:           models.NewFilterViewStore(db),
 		NotificationTargetStore:   notificationTargetStore,
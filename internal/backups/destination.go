@@ -0,0 +1,128 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// ErrRemoteNotFound is returned when an object does not exist on a backup
+// destination.
+var ErrRemoteNotFound = errors.New("remote object not found")
+
+// destinationTimeout bounds a single request to a backup destination.
+const destinationTimeout = 2 * time.Minute
+
+// Destination stores backup files offsite. Names are slash-separated and
+// relative to the destination's configured path.
+type Destination interface {
+	Put(ctx context.Context, name string, data []byte) error
+	// Get returns ErrRemoteNotFound when name does not exist.
+	Get(ctx context.Context, name string) ([]byte, error)
+	// Delete succeeds when name does not exist.
+	Delete(ctx context.Context, name string) error
+	// List returns the files directly under dir. A missing dir is empty.
+	List(ctx context.Context, dir string) ([]RemoteObject, error)
+	Close() error
+}
+
+// RemoteObject is a file on a backup destination.
+type RemoteObject struct {
+	Name       string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// NewDestination returns a client for d. It does not connect; connection
+// problems surface on the first call.
+func NewDestination(d *models.BackupDestination) (Destination, error) {
+	if err := ValidateDestination(d); err != nil {
+		return nil, err
+	}
+
+	switch d.Type {
+	case models.BackupDestinationS3:
+		return newS3Destination(d)
+	case models.BackupDestinationWebDAV:
+		return newWebDAVDestination(d)
+	case models.BackupDestinationSFTP:
+		return newSFTPDestination(d)
+	default:
+		return nil, fmt.Errorf("unsupported destination type %q", d.Type)
+	}
+}
+
+// ValidateDestination checks that d has the fields its type needs.
+func ValidateDestination(d *models.BackupDestination) error {
+	if d == nil {
+		return errors.New("destination is required")
+	}
+	if strings.TrimSpace(d.Endpoint) == "" {
+		return errors.New("destination endpoint is required")
+	}
+	if slices.Contains(strings.Split(strings.ReplaceAll(d.Path, `\`, "/"), "/"), "..") {
+		return errors.New("destination path must not contain '..'")
+	}
+
+	switch d.Type {
+	case models.BackupDestinationS3:
+		if err := validateHTTPEndpoint(d.Endpoint); err != nil {
+			return err
+		}
+		if strings.TrimSpace(d.Bucket) == "" {
+			return errors.New("S3 destination requires a bucket")
+		}
+		if d.Username == "" || d.Password == "" {
+			return errors.New("S3 destination requires an access key and secret key")
+		}
+	case models.BackupDestinationWebDAV:
+		if err := validateHTTPEndpoint(d.Endpoint); err != nil {
+			return err
+		}
+	case models.BackupDestinationSFTP:
+		if _, _, err := net.SplitHostPort(sftpAddress(d.Endpoint)); err != nil {
+			return fmt.Errorf("SFTP endpoint must be host or host:port: %w", err)
+		}
+		if d.Username == "" {
+			return errors.New("SFTP destination requires a username")
+		}
+		if d.Password == "" && d.PrivateKey == "" {
+			return errors.New("SFTP destination requires a password or private key")
+		}
+		if strings.TrimSpace(d.HostKey) == "" {
+			return errors.New("SFTP destination requires the server host key")
+		}
+		if _, err := parseHostKey(d.HostKey); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported destination type %q", d.Type)
+	}
+
+	return nil
+}
+
+func validateHTTPEndpoint(raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("destination endpoint %q must be an http or https URL", raw)
+	}
+	return nil
+}
+
+// joinRemote joins a destination prefix and a name into a clean slash path
+// without leading or trailing slashes.
+func joinRemote(prefix, name string) string {
+	return strings.Trim(path.Join("/", prefix, name), "/")
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// sftpDestination stores files over SFTP. It connects on first use and
+// keeps the connection until Close, or until a request fails in a way that
+// leaves it unusable.
+type sftpDestination struct {
+	prefix string
+	dial   func(ctx context.Context) (*sftp.Client, io.Closer, error)
+
+	mu     sync.Mutex
+	client *sftp.Client
+	closer io.Closer
+}
+
+func newSFTPDestination(d *models.BackupDestination) (*sftpDestination, error) {
+	hostKey, err := parseHostKey(d.HostKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var auth []ssh.AuthMethod
+	if d.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(d.PrivateKey))
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) && d.Password != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(d.PrivateKey), []byte(d.Password))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse SFTP private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if d.Password != "" {
+		auth = append(auth, ssh.Password(d.Password))
+	}
+
+	config := &ssh.ClientConfig{
+		User:            d.Username,
+		Auth:            auth,
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+		Timeout:         30 * time.Second,
+	}
+	addr := sftpAddress(d.Endpoint)
+
+	return &sftpDestination{
+		prefix: d.Path,
+		dial: func(ctx context.Context) (*sftp.Client, io.Closer, error) {
+			return dialSFTP(ctx, addr, config)
+		},
+	}, nil
+}
+
+// sftpAddress adds the default SSH port to endpoint when it has none.
+func sftpAddress(endpoint string) string {
+	endpoint = strings.TrimPrefix(strings.TrimSpace(endpoint), "sftp://")
+	if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		return net.JoinHostPort(strings.Trim(endpoint, "[]"), "22")
+	}
+	return endpoint
+}
+
+// parseHostKey accepts a public key in authorized_keys format
+// ("ssh-ed25519 AAAA...") or a known_hosts line.
+func parseHostKey(raw string) (ssh.PublicKey, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(raw))
+	if err == nil {
+		return key, nil
+	}
+	_, _, key, _, _, err = ssh.ParseKnownHosts([]byte(raw))
+	if err != nil {
+		return nil, fmt.Errorf("SFTP host key must be a public key like \"ssh-ed25519 AAAA...\": %w", err)
+	}
+	return key, nil
+}
+
+func dialSFTP(ctx context.Context, addr string, config *ssh.ClientConfig) (*sftp.Client, io.Closer, error) {
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("SFTP connect %s: %w", addr, err)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, addr, config)
+	if err != nil {
+		netConn.Close()
+		return nil, nil, fmt.Errorf("SFTP handshake %s: %w", addr, err)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, nil, fmt.Errorf("SFTP subsystem: %w", err)
+	}
+	return client, sshClient, nil
+}
+
+// remotePath keeps an absolute destination path absolute; a relative one
+// is resolved by the server against the login directory.
+func (d *sftpDestination) remotePath(name string) string {
+	joined := joinRemote(d.prefix, name)
+	if strings.HasPrefix(strings.TrimSpace(d.prefix), "/") {
+		return "/" + joined
+	}
+	if joined == "" {
+		return "."
+	}
+	return joined
+}
+
+// do runs fn on the cached connection, dialing one if needed. When fn fails
+// with anything but an SFTP status the connection is dropped, and fn is
+// retried once on a new one; every request the destination makes is safe
+// to repeat.
+func (d *sftpDestination) do(ctx context.Context, fn func(*sftp.Client) error) error {
+	for attempt := 0; ; attempt++ {
+		client, err := d.connection(ctx)
+		if err != nil {
+			return err
+		}
+		err = fn(client)
+		if !isSFTPConnError(err) {
+			return err
+		}
+		d.drop(client)
+		if attempt > 0 || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// isSFTPConnError reports whether err came from the connection rather than
+// from a reply of the server.
+func isSFTPConnError(err error) bool {
+	if err == nil || errors.Is(err, ErrRemoteNotFound) || errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+		return false
+	}
+	var status *sftp.StatusError
+	return !errors.As(err, &status)
+}
+
+func (d *sftpDestination) connection(ctx context.Context) (*sftp.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client != nil {
+		return d.client, nil
+	}
+	client, closer, err := d.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.client, d.closer = client, closer
+	return client, nil
+}
+
+// drop closes client if it is still the cached connection, so the next
+// request dials a new one.
+func (d *sftpDestination) drop(client *sftp.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client != client {
+		return
+	}
+	_ = d.closeLocked()
+}
+
+func (d *sftpDestination) Put(ctx context.Context, name string, data []byte) error {
+	target := d.remotePath(name)
+	return d.do(ctx, func(client *sftp.Client) error {
+		if err := client.MkdirAll(path.Dir(target)); err != nil {
+			return fmt.Errorf("SFTP mkdir %s: %w", path.Dir(target), err)
+		}
+		f, err := client.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+		if err != nil {
+			return fmt.Errorf("SFTP open %s: %w", target, err)
+		}
+		if _, err := f.Write(data); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("SFTP write %s: %w", target, err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("SFTP close %s: %w", target, err)
+		}
+		return nil
+	})
+}
+
+func (d *sftpDestination) Get(ctx context.Context, name string) ([]byte, error) {
+	target := d.remotePath(name)
+	var data []byte
+	err := d.do(ctx, func(client *sftp.Client) error {
+		f, err := client.Open(target)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("%s: %w", name, ErrRemoteNotFound)
+			}
+			return fmt.Errorf("SFTP open %s: %w", target, err)
+		}
+		defer f.Close()
+		if data, err = io.ReadAll(f); err != nil {
+			return fmt.Errorf("SFTP read %s: %w", target, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (d *sftpDestination) Delete(ctx context.Context, name string) error {
+	target := d.remotePath(name)
+	return d.do(ctx, func(client *sftp.Client) error {
+		if err := client.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("SFTP remove %s: %w", target, err)
+		}
+		return nil
+	})
+}
+
+func (d *sftpDestination) List(ctx context.Context, dir string) ([]RemoteObject, error) {
+	target := d.remotePath(dir)
+	var objects []RemoteObject
+	err := d.do(ctx, func(client *sftp.Client) error {
+		entries, err := client.ReadDirContext(ctx, target)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("SFTP list %s: %w", target, err)
+		}
+		objects = objects[:0]
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			object := RemoteObject{Name: entry.Name(), Size: entry.Size()}
+			if modified := entry.ModTime(); modified.Unix() > 0 {
+				object.ModifiedAt = modified.UTC()
+			}
+			objects = append(objects, object)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+func (d *sftpDestination) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closeLocked()
+}
+
+func (d *sftpDestination) closeLocked() error {
+	if d.client == nil {
+		return nil
+	}
+	_ = d.client.Close()
+	err := d.closer.Close()
+	d.client, d.closer = nil, nil
+	return err
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// webdavDestination stores files on a WebDAV server (Nextcloud, ownCloud,
+// rclone serve webdav, Apache mod_dav, ...).
+type webdavDestination struct {
+	client   *http.Client
+	base     *url.URL
+	prefix   string
+	username string
+	password string
+
+	// created remembers collections made by ensureCollections so each is
+	// only created once per client.
+	created   map[string]struct{}
+	createdMu sync.Mutex
+}
+
+func newWebDAVDestination(d *models.BackupDestination) (*webdavDestination, error) {
+	base, err := url.Parse(strings.TrimRight(strings.TrimSpace(d.Endpoint), "/"))
+	if err != nil {
+		return nil, err
+	}
+	return &webdavDestination{
+		client:   &http.Client{Timeout: destinationTimeout},
+		base:     base,
+		prefix:   d.Path,
+		username: d.Username,
+		password: d.Password,
+		created:  make(map[string]struct{}),
+	}, nil
+}
+
+func (d *webdavDestination) Put(ctx context.Context, name string, data []byte) error {
+	rel := joinRemote(d.prefix, name)
+	if err := d.ensureCollections(ctx, path.Dir(rel)); err != nil {
+		return err
+	}
+
+	resp, err := d.do(ctx, http.MethodPut, rel, nil, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return webdavError(resp)
+	}
+	return nil
+}
+
+func (d *webdavDestination) Get(ctx context.Context, name string) ([]byte, error) {
+	resp, err := d.do(ctx, http.MethodGet, joinRemote(d.prefix, name), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", name, ErrRemoteNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, webdavError(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (d *webdavDestination) Delete(ctx context.Context, name string) error {
+	resp, err := d.do(ctx, http.MethodDelete, joinRemote(d.prefix, name), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return webdavError(resp)
+	}
+}
+
+const webdavPropfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/><d:getcontentlength/><d:getlastmodified/></d:prop></d:propfind>`
+
+type webdavMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Prop struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+				ContentLength string `xml:"getcontentlength"`
+				LastModified  string `xml:"getlastmodified"`
+			} `xml:"prop"`
+			Status string `xml:"status"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+func (d *webdavDestination) List(ctx context.Context, dir string) ([]RemoteObject, error) {
+	rel := joinRemote(d.prefix, dir)
+	header := http.Header{
+		"Depth":        {"1"},
+		"Content-Type": {"application/xml; charset=utf-8"},
+	}
+	resp, err := d.do(ctx, "PROPFIND", rel+"/", header, []byte(webdavPropfindBody))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, webdavError(resp)
+	}
+
+	var status webdavMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("decode WebDAV listing: %w", err)
+	}
+
+	objects := make([]RemoteObject, 0, len(status.Responses))
+	for _, entry := range status.Responses {
+		// Servers answer with escaped absolute paths or full URLs; the
+		// listed collection itself ends with a slash.
+		href := entry.Href
+		if u, err := url.Parse(href); err == nil && u.Path != "" {
+			href = u.Path
+		}
+		if strings.HasSuffix(href, "/") {
+			continue
+		}
+
+		object := RemoteObject{Name: path.Base(href)}
+		isCollection := false
+		for _, propstat := range entry.Propstat {
+			if !strings.Contains(propstat.Status, " 200 ") {
+				continue
+			}
+			prop := propstat.Prop
+			if prop.ResourceType.Collection != nil {
+				isCollection = true
+			}
+			if size, err := strconv.ParseInt(strings.TrimSpace(prop.ContentLength), 10, 64); err == nil {
+				object.Size = size
+			}
+			if modified, err := http.ParseTime(strings.TrimSpace(prop.LastModified)); err == nil {
+				object.ModifiedAt = modified
+			}
+		}
+		if isCollection {
+			continue
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+func (d *webdavDestination) Close() error { return nil }
+
+// ensureCollections creates dir and its parents with MKCOL. Existing
+// collections answer 405, which is fine.
+func (d *webdavDestination) ensureCollections(ctx context.Context, dir string) error {
+	if dir == "." || dir == "" {
+		return nil
+	}
+
+	current := ""
+	for _, segment := range strings.Split(dir, "/") {
+		current = path.Join(current, segment)
+
+		d.createdMu.Lock()
+		_, done := d.created[current]
+		d.createdMu.Unlock()
+		if done {
+			continue
+		}
+
+		resp, err := d.do(ctx, "MKCOL", current+"/", nil, nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusMethodNotAllowed {
+			return webdavError(resp)
+		}
+
+		d.createdMu.Lock()
+		d.created[current] = struct{}{}
+		d.createdMu.Unlock()
+	}
+	return nil
+}
+
+func (d *webdavDestination) do(ctx context.Context, method, rel string, header http.Header, body []byte) (*http.Response, error) {
+	u := *d.base
+	u.Path = strings.TrimRight(u.Path, "/") + "/" + rel
+	u.RawPath = ""
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if d.username != "" || d.password != "" {
+		req.SetBasicAuth(d.username, d.password)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("WebDAV %s %s: %w", method, rel, err)
+	}
+	return resp, nil
+}
+
+func webdavError(resp *http.Response) error {
+	return fmt.Errorf("WebDAV %s %s: unexpected status %s", resp.Request.Method, resp.Request.URL.Path, resp.Status)
+}
@@ -0,0 +1,524 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// Encrypted backup files are plain age files (https://age-encryption.org),
+// so they can be opened outside qui with the age CLI. Readers recognise them
+// by their header rather than their name: a blob cached by one instance may
+// be referenced by another with different settings.
+const (
+	ageHeader        = "age-encryption.org/v1\n"
+	sealedBlobSuffix = ".torrent.age"
+
+	minPassphraseLength = 8
+
+	// Passphrase keys are derived with a random salt that is saved with the
+	// settings and written into a stanza of every file sealed with them, so
+	// a fresh install can derive the key again from the passphrase alone.
+	passphraseSaltStanza = "qui-scrypt-salt"
+	passphraseSaltSize   = 16
+	passphraseScryptN    = 1 << 15
+
+	// legacyPassphraseSalt derived every passphrase key before salts were
+	// random. It is only used to open files without a salt stanza.
+	legacyPassphraseSalt = "qui backup encryption v1"
+)
+
+var (
+	// ErrInvalidEncryption wraps backup encryption validation errors.
+	ErrInvalidEncryption = errors.New("invalid backup encryption")
+	// ErrEncryptionNotConfigured is returned for encrypted exports of an
+	// instance without backup encryption.
+	ErrEncryptionNotConfigured = errors.New("backup encryption is not configured")
+	// ErrNoBackupKey is returned when no configured key opens an encrypted
+	// backup file.
+	ErrNoBackupKey = errors.New("no configured backup key can decrypt this file")
+)
+
+func isSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(ageHeader))
+}
+
+// ValidateEncryption checks that e holds a usable key for its mode.
+func ValidateEncryption(e *models.BackupEncryption) error {
+	switch e.Mode {
+	case models.BackupEncryptionPassphrase:
+		if len(e.Passphrase) < minPassphraseLength {
+			return fmt.Errorf("passphrase must be at least %d characters", minPassphraseLength)
+		}
+	case models.BackupEncryptionAge:
+		if _, err := age.ParseX25519Identity(strings.TrimSpace(e.Identity)); err != nil {
+			return fmt.Errorf("identity: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported encryption mode %q", e.Mode)
+	}
+	return nil
+}
+
+// identityFor returns the identity of e's current key. Passphrase-derived
+// identities are cached, as scrypt is deliberately slow.
+func (s *Service) identityFor(e *models.BackupEncryption) (*age.X25519Identity, error) {
+	switch e.Mode {
+	case models.BackupEncryptionAge:
+		return age.ParseX25519Identity(strings.TrimSpace(e.Identity))
+	case models.BackupEncryptionPassphrase:
+		salt, err := passphraseSaltOf(e)
+		if err != nil {
+			return nil, err
+		}
+		return s.passphraseIdentity(e.Passphrase, salt)
+	default:
+		return nil, fmt.Errorf("unsupported encryption mode %q", e.Mode)
+	}
+}
+
+// passphraseSaltOf returns the salt e's passphrase key is derived with.
+// Settings saved before salts were random have none and use the legacy one.
+func passphraseSaltOf(e *models.BackupEncryption) ([]byte, error) {
+	if e.Salt == "" {
+		return []byte(legacyPassphraseSalt), nil
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(e.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("decode passphrase salt: %w", err)
+	}
+	return salt, nil
+}
+
+func newPassphraseSalt() (string, error) {
+	salt := make([]byte, passphraseSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate passphrase salt: %w", err)
+	}
+	return base64.RawStdEncoding.EncodeToString(salt), nil
+}
+
+func (s *Service) passphraseIdentity(passphrase string, salt []byte) (*age.X25519Identity, error) {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte{0})
+	h.Write([]byte(passphrase))
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	if cached, ok := s.passphraseIdentities.Load(sum); ok {
+		return cached.(*age.X25519Identity), nil
+	}
+	identity, err := derivePassphraseIdentity(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	s.passphraseIdentities.Store(sum, identity)
+	return identity, nil
+}
+
+func derivePassphraseIdentity(passphrase string, salt []byte) (*age.X25519Identity, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, passphraseScryptN, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("derive backup key: %w", err)
+	}
+	return age.ParseX25519Identity(encodeAgeSecretKey(key))
+}
+
+// saltedPassphraseIdentity opens files sealed with a passphrase key from
+// any install: it derives the key with the salt from the file's salt stanza,
+// or with the legacy salt when the file has none.
+type saltedPassphraseIdentity struct {
+	svc        *Service
+	passphrase string
+}
+
+func (i *saltedPassphraseIdentity) Unwrap(stanzas []*age.Stanza) ([]byte, error) {
+	salt := []byte(legacyPassphraseSalt)
+	for _, stanza := range stanzas {
+		if stanza.Type != passphraseSaltStanza {
+			continue
+		}
+		if len(stanza.Args) != 1 {
+			return nil, errors.New("invalid passphrase salt stanza")
+		}
+		decoded, err := base64.RawStdEncoding.DecodeString(stanza.Args[0])
+		if err != nil {
+			return nil, fmt.Errorf("decode passphrase salt: %w", err)
+		}
+		salt = decoded
+	}
+	identity, err := i.svc.passphraseIdentity(i.passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	return identity.Unwrap(stanzas)
+}
+
+// saltRecipient adds the salt stanza to files sealed with a passphrase key.
+// It wraps nothing; age and other identities skip stanzas of unknown types.
+type saltRecipient struct {
+	salt []byte
+}
+
+func (r saltRecipient) Wrap([]byte) ([]*age.Stanza, error) {
+	return []*age.Stanza{{Type: passphraseSaltStanza, Args: []string{base64.RawStdEncoding.EncodeToString(r.salt)}}}, nil
+}
+
+// prepareEncryption readies e for storage: it validates the key, records
+// its recipient, and retires the key it replaces so older runs stay
+// readable. An e without a mode turns encryption off but keeps the retired
+// keys.
+func (s *Service) prepareEncryption(e, existing *models.BackupEncryption) (*models.BackupEncryption, error) {
+	var retired []string
+	if existing != nil {
+		retired = slices.Clone(existing.RetiredIdentities)
+		if existing.Enabled() {
+			if previous, err := s.identityFor(existing); err == nil {
+				retired = append(retired, previous.String())
+			}
+		}
+	}
+
+	if !e.Enabled() {
+		if len(retired) == 0 {
+			return nil, nil
+		}
+		slices.Sort(retired)
+		return &models.BackupEncryption{RetiredIdentities: slices.Compact(retired)}, nil
+	}
+
+	e.KeepRedactedSecrets(existing)
+	if err := ValidateEncryption(e); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncryption, err)
+	}
+	if e.Mode == models.BackupEncryptionPassphrase {
+		// The key only stays the same while the passphrase does.
+		if existing != nil && existing.Mode == e.Mode && existing.Passphrase == e.Passphrase && existing.Salt != "" {
+			e.Salt = existing.Salt
+		} else {
+			salt, err := newPassphraseSalt()
+			if err != nil {
+				return nil, err
+			}
+			e.Salt = salt
+		}
+	}
+	identity, err := s.identityFor(e)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncryption, err)
+	}
+
+	prepared := &models.BackupEncryption{Mode: e.Mode, Recipient: identity.Recipient().String()}
+	switch e.Mode {
+	case models.BackupEncryptionPassphrase:
+		prepared.Passphrase = e.Passphrase
+		prepared.Salt = e.Salt
+	case models.BackupEncryptionAge:
+		prepared.Identity = strings.TrimSpace(e.Identity)
+	}
+
+	current := identity.String()
+	retired = slices.DeleteFunc(retired, func(id string) bool { return id == current })
+	if len(retired) > 0 {
+		slices.Sort(retired)
+		prepared.RetiredIdentities = slices.Compact(retired)
+	}
+	return prepared, nil
+}
+
+// blobSealer encrypts new backup files for an instance's current key.
+type blobSealer struct {
+	keyID      string
+	recipients []age.Recipient
+}
+
+// newBlobSealer returns a sealer for identity. salt is the salt of a
+// passphrase key, recorded in every sealed file, and nil for age keys.
+func newBlobSealer(identity *age.X25519Identity, salt []byte) *blobSealer {
+	recipient := identity.Recipient()
+	sum := sha256.Sum256([]byte(recipient.String()))
+	sealer := &blobSealer{keyID: hex.EncodeToString(sum[:8]), recipients: []age.Recipient{recipient}}
+	if salt != nil {
+		sealer.recipients = append(sealer.recipients, saltRecipient{salt: salt})
+	}
+	return sealer
+}
+
+func (z *blobSealer) seal(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, z.recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// blobCacheName returns the content-addressed name of a blob inside the
+// torrent cache. Sealed blobs are also keyed by the key ID, so the same
+// torrent sealed for different keys doesn't collide and a sealed blob's name
+// doesn't reveal the plaintext's hash.
+func blobCacheName(data []byte, sealer *blobSealer) string {
+	h := sha256.New()
+	suffix := ".torrent"
+	if sealer != nil {
+		h.Write([]byte(sealer.keyID))
+		suffix = sealedBlobSuffix
+	}
+	h.Write(data)
+	hash := hex.EncodeToString(h.Sum(nil))
+	return path.Join(hash[0:2], hash[2:4], hash[4:6], hash+suffix)
+}
+
+// backupKeys is the encryption state of one backup operation: the sealer
+// for new files, nil when encryption is off, and the identities that open
+// existing ones.
+type backupKeys struct {
+	sealer     *blobSealer
+	identities []age.Identity
+}
+
+// loadBackupKeys builds the keys for an operation on an instance with
+// encryption settings e. Blobs are shared between instances, so the
+// identities cover every key configured on any instance, current or retired.
+func (s *Service) loadBackupKeys(ctx context.Context, e *models.BackupEncryption) (*backupKeys, error) {
+	keys := &backupKeys{}
+	if e.Enabled() {
+		identity, err := s.identityFor(e)
+		if err != nil {
+			return nil, fmt.Errorf("load backup encryption key: %w", err)
+		}
+		var salt []byte
+		if e.Mode == models.BackupEncryptionPassphrase {
+			if salt, err = passphraseSaltOf(e); err != nil {
+				return nil, fmt.Errorf("load backup encryption key: %w", err)
+			}
+		}
+		keys.sealer = newBlobSealer(identity, salt)
+	}
+
+	if s.store == nil {
+		return keys, nil
+	}
+	configured, err := s.store.ListEncryptionKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list backup encryption keys: %w", err)
+	}
+	for _, c := range configured {
+		if c.Enabled() {
+			identity, err := s.identityFor(c)
+			if err != nil {
+				log.Warn().Err(err).Msg("Skipping unusable backup encryption key")
+			} else {
+				keys.identities = append(keys.identities, identity)
+			}
+			// Runs sealed on another install with the same passphrase carry
+			// that install's salt.
+			if c.Mode == models.BackupEncryptionPassphrase {
+				keys.identities = append(keys.identities, &saltedPassphraseIdentity{svc: s, passphrase: c.Passphrase})
+			}
+		}
+		for _, raw := range c.RetiredIdentities {
+			identity, err := age.ParseX25519Identity(raw)
+			if err != nil {
+				log.Warn().Err(err).Msg("Skipping unusable retired backup encryption key")
+				continue
+			}
+			keys.identities = append(keys.identities, identity)
+		}
+	}
+	return keys, nil
+}
+
+// seal encrypts data for the current key, or returns it as-is when
+// encryption is off.
+func (k *backupKeys) seal(data []byte) ([]byte, error) {
+	if k == nil || k.sealer == nil {
+		return data, nil
+	}
+	return k.sealer.seal(data)
+}
+
+// open decrypts data if it is an age file and returns it unchanged otherwise.
+func (k *backupKeys) open(data []byte) ([]byte, error) {
+	if !isSealed(data) {
+		return data, nil
+	}
+	if k == nil || len(k.identities) == 0 {
+		return nil, ErrNoBackupKey
+	}
+	return decryptAge(bytes.NewReader(data), k.identities)
+}
+
+func decryptAge(r io.Reader, identities []age.Identity) ([]byte, error) {
+	plain, err := age.Decrypt(r, identities...)
+	if err != nil {
+		if _, ok := errors.AsType[*age.NoIdentityMatchError](err); ok {
+			return nil, ErrNoBackupKey
+		}
+		return nil, fmt.Errorf("decrypt backup file: %w", err)
+	}
+	data, err := io.ReadAll(plain)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt backup file: %w", err)
+	}
+	return data, nil
+}
+
+// openSealed decrypts data with any configured key if it is encrypted.
+func (s *Service) openSealed(ctx context.Context, data []byte) ([]byte, error) {
+	if !isSealed(data) {
+		return data, nil
+	}
+	keys, err := s.loadBackupKeys(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return keys.open(data)
+}
+
+// ExportRecipient returns the recipient encrypted exports of an instance's
+// runs are written for: the passphrase itself in passphrase mode, so the
+// export opens with `age -d` and the passphrase, or the instance's X25519
+// recipient.
+func (s *Service) ExportRecipient(ctx context.Context, instanceID int) (age.Recipient, error) {
+	settings, err := s.store.GetSettings(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	e := settings.Encryption
+	if !e.Enabled() {
+		return nil, ErrEncryptionNotConfigured
+	}
+	if e.Mode == models.BackupEncryptionPassphrase {
+		return age.NewScryptRecipient(e.Passphrase)
+	}
+	identity, err := s.identityFor(e)
+	if err != nil {
+		return nil, err
+	}
+	return identity.Recipient(), nil
+}
+
+// DecryptArchive decrypts an encrypted export. It tries every configured
+// key and, for exports from another install, passphrase.
+func (s *Service) DecryptArchive(ctx context.Context, r io.Reader, passphrase string) ([]byte, error) {
+	keys, err := s.loadBackupKeys(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	identities := keys.identities
+
+	var passphrases []string
+	if passphrase != "" {
+		passphrases = append(passphrases, passphrase)
+	}
+	if s.store != nil {
+		configured, err := s.store.ListEncryptionKeys(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list backup encryption keys: %w", err)
+		}
+		for _, c := range configured {
+			if c.Mode == models.BackupEncryptionPassphrase && !slices.Contains(passphrases, c.Passphrase) {
+				passphrases = append(passphrases, c.Passphrase)
+			}
+		}
+	}
+	for _, p := range passphrases {
+		identity, err := age.NewScryptIdentity(p)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+
+	if len(identities) == 0 {
+		return nil, ErrNoBackupKey
+	}
+	return decryptAge(r, identities)
+}
+
+// encodeAgeSecretKey encodes a 32-byte X25519 scalar as an age identity
+// string (Bech32 with the "age-secret-key-" prefix, upper case).
+func encodeAgeSecretKey(key []byte) string {
+	const (
+		hrp     = "age-secret-key-"
+		charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	)
+
+	var data []byte
+	acc, bits := 0, 0
+	for _, b := range key {
+		acc = (acc<<8 | int(b)) & 0xffff
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			data = append(data, byte(acc>>bits&31))
+		}
+	}
+	if bits > 0 {
+		data = append(data, byte(acc<<(5-bits)&31))
+	}
+
+	values := make([]byte, 0, len(hrp)*2+1+len(data)+6)
+	for i := range len(hrp) {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := range len(hrp) {
+		values = append(values, hrp[i]&31)
+	}
+	values = append(values, data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+
+	checksum := bech32Polymod(values) ^ 1
+	for i := range 6 {
+		data = append(data, byte(checksum>>(5*(5-i))&31))
+	}
+
+	var out strings.Builder
+	out.WriteString(hrp)
+	out.WriteByte('1')
+	for _, v := range data {
+		out.WriteByte(charset[v])
+	}
+	return strings.ToUpper(out.String())
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := range 5 {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestEncodeAgeSecretKeyParses(t *testing.T) {
+	t.Parallel()
+
+	key := bytes.Repeat([]byte{0x5a}, 32)
+	encoded := encodeAgeSecretKey(key)
+	require.True(t, strings.HasPrefix(encoded, "AGE-SECRET-KEY-1"))
+
+	identity, err := age.ParseX25519Identity(encoded)
+	require.NoError(t, err)
+	require.Equal(t, encoded, identity.String())
+}
+
+func TestPrepareEncryptionRetiresReplacedKeys(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(nil, nil, nil, Config{WorkerCount: 1}, nil)
+
+	_, err := svc.prepareEncryption(&models.BackupEncryption{Mode: models.BackupEncryptionPassphrase, Passphrase: "short"}, nil)
+	require.ErrorIs(t, err, ErrInvalidEncryption)
+
+	first, err := svc.prepareEncryption(&models.BackupEncryption{Mode: models.BackupEncryptionPassphrase, Passphrase: "correct horse"}, nil)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(first.Recipient, "age1"))
+	require.Empty(t, first.RetiredIdentities)
+
+	// Resending the redacted settings keeps the key and retires nothing.
+	same, err := svc.prepareEncryption(first.Redacted(), first)
+	require.NoError(t, err)
+	require.Equal(t, first.Passphrase, same.Passphrase)
+	require.Equal(t, first.Recipient, same.Recipient)
+	require.Empty(t, same.RetiredIdentities)
+
+	next, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	second, err := svc.prepareEncryption(&models.BackupEncryption{Mode: models.BackupEncryptionAge, Identity: next.String()}, first)
+	require.NoError(t, err)
+	require.Equal(t, next.Recipient().String(), second.Recipient)
+	require.Len(t, second.RetiredIdentities, 1)
+
+	// Turning encryption off keeps every key that wrote a backup.
+	off, err := svc.prepareEncryption(&models.BackupEncryption{}, second)
+	require.NoError(t, err)
+	require.False(t, off.Enabled())
+	require.ElementsMatch(t, []string{second.RetiredIdentities[0], next.String()}, off.RetiredIdentities)
+}
+
+func TestPassphraseKeysUseRandomSalt(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(nil, nil, nil, Config{WorkerCount: 1}, nil)
+	other := NewService(nil, nil, nil, Config{WorkerCount: 1}, nil)
+
+	first, err := svc.prepareEncryption(&models.BackupEncryption{Mode: models.BackupEncryptionPassphrase, Passphrase: "correct horse"}, nil)
+	require.NoError(t, err)
+	second, err := other.prepareEncryption(&models.BackupEncryption{Mode: models.BackupEncryptionPassphrase, Passphrase: "correct horse"}, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, first.Salt)
+	require.NotEqual(t, first.Salt, second.Salt)
+	require.NotEqual(t, first.Recipient, second.Recipient)
+
+	same, err := svc.prepareEncryption(first.Redacted(), first)
+	require.NoError(t, err)
+	require.Equal(t, first.Salt, same.Salt)
+
+	keys, err := svc.loadBackupKeys(context.Background(), first)
+	require.NoError(t, err)
+	sealed, err := keys.seal([]byte("d8:announce0:e"))
+	require.NoError(t, err)
+	require.Contains(t, string(sealed), "-> "+passphraseSaltStanza+" ")
+
+	// Another install derives the key from the passphrase and the file's salt.
+	opener := &backupKeys{identities: []age.Identity{&saltedPassphraseIdentity{svc: other, passphrase: "correct horse"}}}
+	opened, err := opener.open(sealed)
+	require.NoError(t, err)
+	require.Equal(t, []byte("d8:announce0:e"), opened)
+
+	wrong := &backupKeys{identities: []age.Identity{&saltedPassphraseIdentity{svc: other, passphrase: "wrong horse"}}}
+	_, err = wrong.open(sealed)
+	require.ErrorIs(t, err, ErrNoBackupKey)
+
+	// Files sealed before salts were random have no salt stanza.
+	legacy, err := derivePassphraseIdentity("correct horse", []byte(legacyPassphraseSalt))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, legacy.Recipient())
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "legacy")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	opened, err = opener.open(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, []byte("legacy"), opened)
+}
+
+func TestBackupKeysSealAndOpen(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestBackupDB(t)
+	ctx := context.Background()
+	store := models.NewBackupStore(db)
+	require.NoError(t, store.SetEncryptionKey(make([]byte, 32)))
+	svc := NewService(store, nil, nil, Config{WorkerCount: 1, DataDir: t.TempDir()}, nil)
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	encryption, err := svc.prepareEncryption(&models.BackupEncryption{Mode: models.BackupEncryptionAge, Identity: identity.String()}, nil)
+	require.NoError(t, err)
+
+	plain := []byte("d8:announce0:e")
+	writer, err := svc.loadBackupKeys(ctx, encryption)
+	require.NoError(t, err)
+	sealed, err := writer.seal(plain)
+	require.NoError(t, err)
+	require.True(t, isSealed(sealed))
+	require.NotEqual(t, blobCacheName(plain, nil), blobCacheName(plain, writer.sealer))
+	require.True(t, strings.HasSuffix(blobCacheName(plain, writer.sealer), sealedBlobSuffix))
+
+	// The key is not stored on any instance yet, so nothing opens the blob.
+	_, err = svc.openSealed(ctx, sealed)
+	require.ErrorIs(t, err, ErrNoBackupKey)
+
+	instanceID := insertTestInstance(t, db, "encrypted")
+	require.NoError(t, store.UpsertSettings(ctx, &models.BackupSettings{InstanceID: instanceID, Encryption: encryption}))
+
+	opened, err := svc.openSealed(ctx, sealed)
+	require.NoError(t, err)
+	require.Equal(t, plain, opened)
+
+	unsealed, err := svc.openSealed(ctx, plain)
+	require.NoError(t, err)
+	require.Equal(t, plain, unsealed)
+}
+
+func TestDecryptArchiveWithPassphrase(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestBackupDB(t)
+	svc := NewService(models.NewBackupStore(db), nil, nil, Config{WorkerCount: 1, DataDir: t.TempDir()}, nil)
+
+	recipient, err := age.NewScryptRecipient("export passphrase")
+	require.NoError(t, err)
+	recipient.SetWorkFactor(10)
+
+	var archive bytes.Buffer
+	w, err := age.Encrypt(&archive, recipient)
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "archive-bytes")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	_, err = svc.DecryptArchive(context.Background(), bytes.NewReader(archive.Bytes()), "")
+	require.ErrorIs(t, err, ErrNoBackupKey)
+
+	data, err := svc.DecryptArchive(context.Background(), bytes.NewReader(archive.Bytes()), "export passphrase")
+	require.NoError(t, err)
+	require.Equal(t, []byte("archive-bytes"), data)
+}
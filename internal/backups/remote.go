@@ -0,0 +1,323 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// Layout on a destination: manifests and torrent blobs sit in two flat
+// directories under the configured path. Blobs are content-addressed, so a
+// blob shared by several runs is uploaded once.
+const (
+	remoteManifestDir = "manifests"
+	remoteTorrentDir  = "torrents"
+	manifestSuffix    = "_manifest.json"
+)
+
+var (
+	// ErrNoDestination is returned when an instance has no backup destination.
+	ErrNoDestination = errors.New("no backup destination configured")
+	// ErrInvalidDestination wraps backup destination validation errors.
+	ErrInvalidDestination = errors.New("invalid backup destination")
+	// ErrInvalidRemoteRun is returned for remote run names that are not a
+	// manifest file name.
+	ErrInvalidRemoteRun = errors.New("invalid remote backup run name")
+)
+
+// RemoteRun is a backup run stored on an instance's destination, identified
+// by its manifest file name.
+type RemoteRun struct {
+	Name       string     `json:"name"`
+	SizeBytes  int64      `json:"sizeBytes"`
+	ModifiedAt *time.Time `json:"modifiedAt,omitempty"`
+}
+
+func remoteManifestKey(manifestPath string) string {
+	return remoteManifestDir + "/" + path.Base(strings.ReplaceAll(manifestPath, `\`, "/"))
+}
+
+func remoteTorrentKey(blobPath string) string {
+	return remoteTorrentDir + "/" + path.Base(strings.ReplaceAll(blobPath, `\`, "/"))
+}
+
+func validRemoteRunName(name string) bool {
+	return strings.HasSuffix(name, manifestSuffix) &&
+		!strings.ContainsAny(name, `/\`) &&
+		name != manifestSuffix
+}
+
+// openDestination returns a client for the instance's destination, or
+// ErrNoDestination. Callers must Close it.
+func (s *Service) openDestination(ctx context.Context, instanceID int) (Destination, error) {
+	if s.store == nil {
+		return nil, ErrNoDestination
+	}
+	settings, err := s.store.GetSettings(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.Destination == nil {
+		return nil, ErrNoDestination
+	}
+	return NewDestination(settings.Destination)
+}
+
+// TestDestination checks that d is valid and reachable by listing its
+// manifests. Redacted secrets are taken from the instance's saved
+// destination, so a saved destination can be tested as the client shows it.
+func (s *Service) TestDestination(ctx context.Context, instanceID int, d *models.BackupDestination) error {
+	if d == nil {
+		return fmt.Errorf("%w: destination is required", ErrInvalidDestination)
+	}
+	candidate := *d
+
+	existing, err := s.store.GetSettings(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	candidate.KeepRedactedSecrets(existing.Destination)
+
+	if err := ValidateDestination(&candidate); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDestination, err)
+	}
+	dest, err := NewDestination(&candidate)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDestination, err)
+	}
+	defer dest.Close()
+
+	_, err = dest.List(ctx, remoteManifestDir)
+	return err
+}
+
+// uploadRun copies a finished run to the destination: the torrent blobs the
+// destination doesn't have yet, then the manifest. Files are uploaded as
+// stored, so encrypted runs stay encrypted offsite. The manifest goes last so
+// a listed run always has its blobs.
+func (s *Service) uploadRun(ctx context.Context, destination *models.BackupDestination, manifestName string, manifestData []byte, items []models.BackupItem) error {
+	dest, err := NewDestination(destination)
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+
+	existing, err := dest.List(ctx, remoteTorrentDir)
+	if err != nil {
+		return fmt.Errorf("list remote torrents: %w", err)
+	}
+	uploaded := make(map[string]struct{}, len(existing))
+	for _, object := range existing {
+		uploaded[object.Name] = struct{}{}
+	}
+
+	for _, item := range items {
+		if item.TorrentBlobPath == nil {
+			continue
+		}
+		key := remoteTorrentKey(*item.TorrentBlobPath)
+		name := path.Base(key)
+		if _, ok := uploaded[name]; ok {
+			continue
+		}
+
+		data, err := s.loadTorrentBlobData(*item.TorrentBlobPath)
+		if err != nil {
+			return err
+		}
+		if err := dest.Put(ctx, key, data); err != nil {
+			return fmt.Errorf("upload torrent %s: %w", item.TorrentHash, err)
+		}
+		uploaded[name] = struct{}{}
+	}
+
+	if err := dest.Put(ctx, remoteManifestKey(manifestName), manifestData); err != nil {
+		return fmt.Errorf("upload manifest: %w", err)
+	}
+	return nil
+}
+
+// pruneRemote removes deleted runs' manifests and blobs no run references
+// anymore from the instance's destination. Failures are logged; the local
+// deletion already happened.
+func (s *Service) pruneRemote(ctx context.Context, instanceID int, manifestPaths, blobPaths []string) {
+	if len(manifestPaths) == 0 && len(blobPaths) == 0 {
+		return
+	}
+
+	dest, err := s.openDestination(ctx, instanceID)
+	if errors.Is(err, ErrNoDestination) {
+		return
+	}
+	if err != nil {
+		log.Warn().Err(err).Int("instanceID", instanceID).Msg("Failed to open backup destination for pruning")
+		return
+	}
+	defer dest.Close()
+
+	keys := make([]string, 0, len(manifestPaths)+len(blobPaths))
+	for _, manifestPath := range manifestPaths {
+		keys = append(keys, remoteManifestKey(manifestPath))
+	}
+	for _, blobPath := range blobPaths {
+		keys = append(keys, remoteTorrentKey(blobPath))
+	}
+
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := dest.Delete(ctx, key); err != nil {
+			log.Warn().Err(err).Int("instanceID", instanceID).Str("key", key).Msg("Failed to delete file from backup destination")
+		}
+	}
+}
+
+// ListRemoteRuns lists the runs on the instance's destination, newest first.
+func (s *Service) ListRemoteRuns(ctx context.Context, instanceID int) ([]RemoteRun, error) {
+	dest, err := s.openDestination(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer dest.Close()
+
+	objects, err := dest.List(ctx, remoteManifestDir)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]RemoteRun, 0, len(objects))
+	for _, object := range objects {
+		if !validRemoteRunName(object.Name) {
+			continue
+		}
+		run := RemoteRun{Name: object.Name, SizeBytes: object.Size}
+		if !object.ModifiedAt.IsZero() {
+			modified := object.ModifiedAt
+			run.ModifiedAt = &modified
+		}
+		runs = append(runs, run)
+	}
+
+	// Manifest names embed the run timestamp, so they sort by age even when
+	// the destination reports no modification times.
+	sort.Slice(runs, func(i, j int) bool {
+		a, b := runs[i].ModifiedAt, runs[j].ModifiedAt
+		if a != nil && b != nil && !a.Equal(*b) {
+			return a.After(*b)
+		}
+		return runs[i].Name > runs[j].Name
+	})
+	return runs, nil
+}
+
+// LoadRemoteManifest reads a run's manifest from the instance's destination.
+func (s *Service) LoadRemoteManifest(ctx context.Context, instanceID int, name string) (*Manifest, error) {
+	if !validRemoteRunName(name) {
+		return nil, ErrInvalidRemoteRun
+	}
+
+	dest, err := s.openDestination(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer dest.Close()
+
+	data, err := dest.Get(ctx, remoteManifestKey(name))
+	if err != nil {
+		return nil, err
+	}
+	if data, err = s.openSealed(ctx, data); err != nil {
+		return nil, fmt.Errorf("remote manifest %s: %w", name, err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("decode remote manifest %s: %w", name, err)
+	}
+	if err := checkManifestVersion(&manifest); err != nil {
+		return nil, fmt.Errorf("remote manifest %s: %w", name, err)
+	}
+	return &manifest, nil
+}
+
+// BlobReader reads torrent blobs for an instance, decrypting encrypted
+// ones. Blobs missing from the local cache are read from the instance's
+// destination, which is opened on first use. Callers must Close it.
+type BlobReader struct {
+	svc        *Service
+	instanceID int
+	dest       Destination
+	destErr    error
+	opened     bool
+	keys       *backupKeys
+}
+
+func (s *Service) NewBlobReader(instanceID int) *BlobReader {
+	return &BlobReader{svc: s, instanceID: instanceID}
+}
+
+// Read returns the decrypted contents of the blob at blobPath.
+func (b *BlobReader) Read(ctx context.Context, blobPath string) ([]byte, error) {
+	data, err := b.readRaw(ctx, blobPath)
+	if err != nil {
+		return nil, err
+	}
+	if !isSealed(data) {
+		return data, nil
+	}
+
+	if b.keys == nil {
+		if b.keys, err = b.svc.loadBackupKeys(ctx, nil); err != nil {
+			return nil, err
+		}
+	}
+	data, err = b.keys.open(data)
+	if err != nil {
+		return nil, fmt.Errorf("torrent blob %q: %w", blobPath, err)
+	}
+	return data, nil
+}
+
+func (b *BlobReader) readRaw(ctx context.Context, blobPath string) ([]byte, error) {
+	data, localErr := b.svc.loadTorrentBlobData(blobPath)
+	if localErr == nil {
+		return data, nil
+	}
+
+	if !b.opened {
+		b.opened = true
+		b.dest, b.destErr = b.svc.openDestination(ctx, b.instanceID)
+	}
+	if errors.Is(b.destErr, ErrNoDestination) {
+		return nil, localErr
+	}
+	if b.destErr != nil {
+		return nil, fmt.Errorf("%w; backup destination: %w", localErr, b.destErr)
+	}
+
+	data, err := b.dest.Get(ctx, remoteTorrentKey(blobPath))
+	if err != nil {
+		return nil, fmt.Errorf("read torrent blob %q from backup destination: %w", blobPath, err)
+	}
+	return data, nil
+}
+
+func (b *BlobReader) Close() error {
+	if b.dest != nil {
+		return b.dest.Close()
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestRemoteRunUploadListRestoreAndPrune(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestBackupDB(t)
+	ctx := context.Background()
+	instanceID := insertTestInstance(t, db, "remote-runs")
+	store := models.NewBackupStore(db)
+	require.NoError(t, store.SetEncryptionKey(make([]byte, 32)))
+
+	s3 := newFakeS3(t)
+	require.NoError(t, store.UpsertSettings(ctx, &models.BackupSettings{
+		InstanceID:    instanceID,
+		Enabled:       true,
+		HourlyEnabled: true,
+		KeepHourly:    1,
+		Destination:   s3.destination("qui"),
+	}))
+	settings, err := store.GetSettings(ctx, instanceID)
+	require.NoError(t, err)
+	require.NotNil(t, settings.Destination)
+	require.Equal(t, s3.secretKey, settings.Destination.Password, "stored secret should decrypt")
+
+	dataDir := t.TempDir()
+	svc := NewService(store, nil, nil, Config{WorkerCount: 1, DataDir: dataDir}, nil)
+
+	manifestName := "qui-backup_remote-runs_manual_20260102T030405Z" + manifestSuffix
+	manifestRelPath := filepath.ToSlash(filepath.Join("backups", "instance-1", manifestName))
+	blobRelPath := filepath.ToSlash(filepath.Join("backups", "torrents", "aa", "bb", "aabb.torrent"))
+	for rel, data := range map[string][]byte{manifestRelPath: nil, blobRelPath: []byte("torrent-bytes")} {
+		abs := filepath.Join(dataDir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(abs), 0o755))
+		require.NoError(t, os.WriteFile(abs, data, 0o600))
+	}
+
+	manifestData, err := json.Marshal(Manifest{
+		InstanceID:   instanceID,
+		Kind:         string(models.BackupRunKindManual),
+		TorrentCount: 1,
+		Items:        []ManifestItem{{Hash: "aabb", Name: "Torrent", TorrentBlob: blobRelPath}},
+	})
+	require.NoError(t, err)
+
+	now := time.Unix(0, 0).UTC()
+	run := &models.BackupRun{
+		InstanceID:   instanceID,
+		Kind:         models.BackupRunKindManual,
+		Status:       models.BackupRunStatusSuccess,
+		RequestedBy:  "tester",
+		RequestedAt:  now,
+		CompletedAt:  &now,
+		ManifestPath: &manifestRelPath,
+	}
+	require.NoError(t, store.CreateRun(ctx, run))
+	items := []models.BackupItem{{RunID: run.ID, TorrentHash: "aabb", Name: "Torrent", TorrentBlobPath: &blobRelPath}}
+	require.NoError(t, store.InsertItems(ctx, run.ID, items))
+
+	require.NoError(t, svc.uploadRun(ctx, settings.Destination, manifestName, manifestData, items))
+	require.ElementsMatch(t, []string{"qui/manifests/" + manifestName, "qui/torrents/aabb.torrent"}, s3.keys())
+
+	runs, err := svc.ListRemoteRuns(ctx, instanceID)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, manifestName, runs[0].Name)
+
+	manifest, err := svc.LoadRemoteManifest(ctx, instanceID, manifestName)
+	require.NoError(t, err)
+	require.Len(t, manifest.Items, 1)
+	require.Equal(t, "aabb", manifest.Items[0].Hash)
+
+	_, err = svc.LoadRemoteManifest(ctx, instanceID, "../"+manifestName)
+	require.ErrorIs(t, err, ErrInvalidRemoteRun)
+
+	// A blob missing locally is read back from the destination.
+	require.NoError(t, os.Remove(filepath.Join(dataDir, blobRelPath)))
+	blobs := svc.NewBlobReader(instanceID)
+	data, err := blobs.Read(ctx, blobRelPath)
+	require.NoError(t, blobs.Close())
+	require.NoError(t, err)
+	require.Equal(t, []byte("torrent-bytes"), data)
+
+	require.NoError(t, svc.DeleteRun(ctx, run.ID))
+	require.Empty(t, s3.keys())
+}
+
+func TestRemoteRunsWithoutDestination(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestBackupDB(t)
+	ctx := context.Background()
+	instanceID := insertTestInstance(t, db, "no-destination")
+	svc := NewService(models.NewBackupStore(db), nil, nil, Config{WorkerCount: 1, DataDir: t.TempDir()}, nil)
+
+	_, err := svc.ListRemoteRuns(ctx, instanceID)
+	require.ErrorIs(t, err, ErrNoDestination)
+}
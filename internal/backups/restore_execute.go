@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/rs/zerolog/log"
@@ -22,6 +23,17 @@ type RestoreOptions struct {
 	SkipHashCheck      bool
 	AutoResumeVerified bool
 	ExcludeHashes      []string
+	Target             *RestoreTarget
+	StateFields        []StateField
+}
+
+// PlanOptions returns the plan options matching opts, so previews plan
+// exactly what executing with opts would do.
+func (opts RestoreOptions) PlanOptions() *RestorePlanOptions {
+	if len(opts.ExcludeHashes) == 0 && opts.Target == nil && opts.StateFields == nil {
+		return nil
+	}
+	return &RestorePlanOptions{ExcludeHashes: opts.ExcludeHashes, Target: opts.Target, StateFields: opts.StateFields}
 }
 
 // RestoreError captures an operation failure during restore execution.
@@ -72,16 +84,27 @@ type RestoreResult struct {
 
 // ExecuteRestore executes the restore plan for the given run and mode.
 func (s *Service) ExecuteRestore(ctx context.Context, runID int64, mode RestoreMode, opts RestoreOptions) (*RestoreResult, error) {
-	var planOpts *RestorePlanOptions
-	if len(opts.ExcludeHashes) > 0 {
-		planOpts = &RestorePlanOptions{ExcludeHashes: opts.ExcludeHashes}
+	plan, err := s.PlanRestoreDiff(ctx, runID, mode, opts.PlanOptions())
+	if err != nil {
+		return nil, err
 	}
 
-	plan, err := s.PlanRestoreDiff(ctx, runID, mode, planOpts)
+	return s.executeRestorePlan(ctx, plan, opts)
+}
+
+// ExecuteRemoteRestore restores a run read directly from the instance's
+// backup destination. Torrent files come from the local cache when present
+// and from the destination otherwise.
+func (s *Service) ExecuteRemoteRestore(ctx context.Context, instanceID int, name string, mode RestoreMode, opts RestoreOptions) (*RestoreResult, error) {
+	plan, err := s.PlanRemoteRestore(ctx, instanceID, name, mode, opts.PlanOptions())
 	if err != nil {
 		return nil, err
 	}
 
+	return s.executeRestorePlan(ctx, plan, opts)
+}
+
+func (s *Service) executeRestorePlan(ctx context.Context, plan *RestorePlan, opts RestoreOptions) (*RestoreResult, error) {
 	result := &RestoreResult{
 		Mode:       plan.Mode,
 		RunID:      plan.RunID,
@@ -237,7 +260,14 @@ func (s *Service) applyTorrentPlan(ctx context.Context, plan *RestorePlan, appli
 	instanceID := plan.InstanceID
 	var warnings []string
 	var pendingResume []string
+	var pendingFiles []TorrentSpec
 	pinnedSavePaths := 0
+	restoreTrackers := slices.Contains(plan.StateFields, StateTrackers)
+
+	// Blobs belong to the run's own instance, whose destination holds any
+	// that are not cached locally.
+	blobs := s.NewBlobReader(plan.SourceInstanceID)
+	defer blobs.Close()
 
 	for _, spec := range plan.Torrents.Add {
 		if err := ctx.Err(); err != nil {
@@ -256,17 +286,37 @@ func (s *Service) applyTorrentPlan(ctx context.Context, plan *RestorePlan, appli
 			continue
 		}
 
-		payload, err := s.loadTorrentBlobData(blobPath)
+		payload, err := blobs.Read(ctx, blobPath)
 		if err != nil {
 			appendRestoreError(errs, "add_torrent", spec.Manifest.Hash, err)
 			log.Warn().Err(err).Int("instanceID", instanceID).Str("hash", spec.Manifest.Hash).Msg("Restore: failed to load torrent blob")
 			continue
 		}
+		// Captured trackers include ones added after the torrent was, so they
+		// replace the .torrent's list. The target already rewrote them.
+		trackersRestored := false
+		if state := spec.Manifest.State; restoreTrackers && state != nil && len(state.Trackers) > 0 {
+			if patched, _, err := patchTorrentTrackers(payload, state.Trackers); err != nil {
+				log.Warn().Err(err).Int("instanceID", instanceID).Str("hash", spec.Manifest.Hash).Msg("Restore: failed to restore trackers")
+			} else {
+				payload, trackersRestored = patched, true
+			}
+		}
+		if len(plan.TrackerRewrites) > 0 && !trackersRestored {
+			if payload, err = rewriteTorrentTrackers(payload, plan.TrackerRewrites); err != nil {
+				appendRestoreError(errs, "add_torrent", spec.Manifest.Hash, err)
+				log.Warn().Err(err).Int("instanceID", instanceID).Str("hash", spec.Manifest.Hash).Msg("Restore: failed to rewrite trackers")
+				continue
+			}
+		}
 
-		options := map[string]string{}
+		options := addStateOptions(spec.Manifest.State, plan.StateFields)
+		// Torrents with file state stay stopped until their files are renamed
+		// and reprioritised, so nothing downloads under the wrong name.
+		withFiles := hasFileState(spec, plan.StateFields)
 		paused := "false"
 		stopped := "false"
-		if opts.StartPaused || opts.SkipHashCheck {
+		if opts.StartPaused || opts.SkipHashCheck || withFiles {
 			paused = "true"
 			stopped = "true"
 		}
@@ -319,6 +369,9 @@ func (s *Service) applyTorrentPlan(ctx context.Context, plan *RestorePlan, appli
 		}
 
 		applied.Torrents.Added = append(applied.Torrents.Added, spec.Manifest.Hash)
+		if withFiles {
+			pendingFiles = append(pendingFiles, spec)
+		}
 
 		if opts.SkipHashCheck && opts.AutoResumeVerified {
 			pendingResume = append(pendingResume, spec.Manifest.Hash)
@@ -329,6 +382,27 @@ func (s *Service) applyTorrentPlan(ctx context.Context, plan *RestorePlan, appli
 		warnings = append(warnings, fmt.Sprintf("%d torrent(s) were placed at their saved path with Auto TMM disabled; ensure those locations exist on this host", pinnedSavePaths))
 	}
 
+	if len(pendingFiles) > 0 {
+		var recheck, resume []string
+		for _, spec := range pendingFiles {
+			if err := ctx.Err(); err != nil {
+				return warnings, err
+			}
+			renamed, err := s.applyFileState(ctx, instanceID, spec.Manifest.Hash, spec.Manifest.State)
+			if err != nil {
+				appendRestoreError(errs, "restore_files", spec.Manifest.Hash, err)
+				log.Warn().Err(err).Int("instanceID", instanceID).Str("hash", spec.Manifest.Hash).Msg("Restore: failed to restore file state")
+			}
+			if renamed {
+				recheck = append(recheck, spec.Manifest.Hash)
+			}
+			if !opts.StartPaused && !opts.SkipHashCheck {
+				resume = append(resume, spec.Manifest.Hash)
+			}
+		}
+		s.resumeRestored(ctx, instanceID, recheck, resume, errs)
+	}
+
 	for _, update := range plan.Torrents.Update {
 		if err := ctx.Err(); err != nil {
 			return warnings, err
@@ -366,6 +440,15 @@ func (s *Service) applyTorrentPlan(ctx context.Context, plan *RestorePlan, appli
 				} else {
 					supportedApplied = true
 				}
+			case "shareLimits", "speedLimits", "trackers", "sequentialDownload":
+				if update.Desired.State == nil {
+					continue
+				}
+				if err := s.applyStateChange(ctx, instanceID, update.Hash, change.Field, update.Desired.State, update.Current); err != nil {
+					appendRestoreError(errs, "set_"+change.Field, update.Hash, err)
+				} else {
+					supportedApplied = true
+				}
 			}
 		}
 
@@ -444,6 +527,8 @@ func shouldSkipTorrent(hash string, exclude map[string]struct{}) bool {
 	return skip
 }
 
+// loadTorrentBlobData reads a blob from the local cache as stored, which may
+// be encrypted; BlobReader decrypts.
 func (s *Service) loadTorrentBlobData(blobPath string) ([]byte, error) {
 	abs := s.ResolveBackupPath(blobPath)
 	if abs == "" {
@@ -49,8 +49,12 @@ type TagUpdate struct {
 }
 
 // TorrentSpec describes a torrent that should exist after the restore completes.
+// DataPath is where its content is expected on the target instance, and
+// DataMissing is set when nothing was found there.
 type TorrentSpec struct {
-	Manifest ManifestItem `json:"manifest"`
+	Manifest    ManifestItem `json:"manifest"`
+	DataPath    string       `json:"dataPath,omitempty"`
+	DataMissing bool         `json:"dataMissing,omitempty"`
 }
 
 // TorrentUpdate captures adjustments required for an existing torrent.
@@ -91,18 +95,28 @@ type TorrentPlan struct {
 }
 
 // RestorePlan is the full set of actions required to align the live instance with the snapshot.
+// InstanceID is the instance being restored into and SourceInstanceID the
+// one the run was taken from; they differ for restores onto another instance.
 type RestorePlan struct {
-	Mode       RestoreMode  `json:"mode"`
-	RunID      int64        `json:"runId"`
-	InstanceID int          `json:"instanceId"`
-	Categories CategoryPlan `json:"categories"`
-	Tags       TagPlan      `json:"tags"`
-	Torrents   TorrentPlan  `json:"torrents"`
-}
-
-// RestorePlanOptions controls post-processing applied to a generated plan.
+	Mode             RestoreMode      `json:"mode"`
+	RunID            int64            `json:"runId"`
+	InstanceID       int              `json:"instanceId"`
+	SourceInstanceID int              `json:"sourceInstanceId"`
+	Categories       CategoryPlan     `json:"categories"`
+	Tags             TagPlan          `json:"tags"`
+	Torrents         TorrentPlan      `json:"torrents"`
+	TrackerRewrites  []TrackerRewrite `json:"trackerRewrites,omitempty"`
+	StateFields      []StateField     `json:"stateFields"`
+	Warnings         []string         `json:"warnings,omitempty"`
+}
+
+// RestorePlanOptions controls how a plan is generated and post-processed.
+// StateFields selects the captured torrent state to re-apply: nil selects
+// all of it and an empty slice none.
 type RestorePlanOptions struct {
 	ExcludeHashes []string
+	Target        *RestoreTarget
+	StateFields   []StateField
 }
 
 // SnapshotTorrent provides convenient access to torrent metadata captured in the snapshot.
@@ -117,6 +131,8 @@ type SnapshotTorrent struct {
 	SizeBytes   int64    `json:"sizeBytes,omitempty"`
 	InfoHashV1  *string  `json:"infoHashV1,omitempty"`
 	InfoHashV2  *string  `json:"infoHashV2,omitempty"`
+
+	State *models.BackupTorrentState `json:"state,omitempty"`
 }
 
 // SnapshotState represents the desired state recorded in a backup snapshot.
@@ -145,6 +161,15 @@ type LiveTorrent struct {
 	InfoHashV2  string   `json:"infoHashV2,omitempty"`
 	SavePath    string   `json:"savePath,omitempty"`
 	SizeBytes   int64    `json:"sizeBytes,omitempty"`
+
+	RatioLimit               float64 `json:"ratioLimit"`
+	SeedingTimeLimit         int64   `json:"seedingTimeLimit"`
+	InactiveSeedingTimeLimit int64   `json:"inactiveSeedingTimeLimit"`
+	ShareLimitAction         string  `json:"shareLimitAction,omitempty"`
+	UploadLimit              int64   `json:"uploadLimit,omitempty"`
+	DownloadLimit            int64   `json:"downloadLimit,omitempty"`
+	SequentialDownload       bool    `json:"sequentialDownload,omitempty"`
+	FirstLastPiecePrio       bool    `json:"firstLastPiecePrio,omitempty"`
 }
 
 // LiveState represents the state of the live qBittorrent instance relevant for planning a restore.
@@ -157,26 +182,69 @@ type LiveState struct {
 
 // PlanRestoreDiff loads snapshot and live state, returning the diff plan for the requested mode.
 func (s *Service) PlanRestoreDiff(ctx context.Context, runID int64, mode RestoreMode, opts *RestorePlanOptions) (*RestorePlan, error) {
+	if err := s.checkRestorable(mode); err != nil {
+		return nil, err
+	}
+
+	manifest, err := s.LoadManifest(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.planRestore(ctx, snapshotStateFromManifest(runID, manifest), mode, opts)
+}
+
+// PlanRemoteRestore plans restoring a run read directly from the instance's
+// backup destination into the instance.
+func (s *Service) PlanRemoteRestore(ctx context.Context, instanceID int, name string, mode RestoreMode, opts *RestorePlanOptions) (*RestorePlan, error) {
+	if err := s.checkRestorable(mode); err != nil {
+		return nil, err
+	}
+
+	manifest, err := s.LoadRemoteManifest(ctx, instanceID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := snapshotStateFromManifest(0, manifest)
+	snapshot.InstanceID = instanceID
+	return s.planRestore(ctx, snapshot, mode, opts)
+}
+
+func (s *Service) checkRestorable(mode RestoreMode) error {
 	if s == nil {
-		return nil, errors.New("nil backup service")
+		return errors.New("nil backup service")
 	}
 	if s.reader == nil {
-		return nil, errors.New("sync manager unavailable")
+		return errors.New("sync manager unavailable")
+	}
+	if mode != "" && !isValidRestoreMode(mode) {
+		return fmt.Errorf("unsupported restore mode: %s", mode)
 	}
+	return nil
+}
 
+func (s *Service) planRestore(ctx context.Context, snapshot *SnapshotState, mode RestoreMode, opts *RestorePlanOptions) (*RestorePlan, error) {
 	if mode == "" {
 		mode = RestoreModeIncremental
 	}
 
-	if !isValidRestoreMode(mode) {
-		return nil, fmt.Errorf("unsupported restore mode: %s", mode)
+	var target *RestoreTarget
+	var stateFields []StateField
+	if opts != nil {
+		target = opts.Target
+		stateFields = opts.StateFields
 	}
-
-	snapshot, err := s.loadSnapshotState(ctx, runID)
-	if err != nil {
+	if err := target.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateStateFields(stateFields); err != nil {
 		return nil, err
 	}
 
+	sourceInstanceID := snapshot.InstanceID
+	target.apply(snapshot)
+
 	live, err := s.loadLiveState(ctx, snapshot.InstanceID)
 	if err != nil {
 		return nil, err
@@ -186,8 +254,14 @@ func (s *Service) PlanRestoreDiff(ctx context.Context, runID int64, mode Restore
 	if err != nil {
 		return nil, err
 	}
+	plan.SourceInstanceID = sourceInstanceID
+	if target != nil {
+		plan.TrackerRewrites = target.TrackerRewrites
+	}
 
+	applyStateFields(plan, resolveStateFields(stateFields))
 	applyRestorePlanOptions(plan, opts)
+	s.checkRestoreData(ctx, plan, effectiveCategoryPaths(live.Categories, plan.Categories))
 
 	return plan, nil
 }
@@ -295,12 +369,13 @@ func buildRestorePlan(snapshot *SnapshotState, live *LiveState, mode RestoreMode
 	}
 
 	plan := &RestorePlan{
-		Mode:       mode,
-		RunID:      snapshot.RunID,
-		InstanceID: snapshot.InstanceID,
-		Categories: CategoryPlan{},
-		Tags:       TagPlan{},
-		Torrents:   TorrentPlan{},
+		Mode:             mode,
+		RunID:            snapshot.RunID,
+		InstanceID:       snapshot.InstanceID,
+		SourceInstanceID: snapshot.InstanceID,
+		Categories:       CategoryPlan{},
+		Tags:             TagPlan{},
+		Torrents:         TorrentPlan{},
 	}
 
 	plan.Categories = buildCategoryPlan(snapshot.Categories, live.Categories, mode)
@@ -314,12 +389,7 @@ func buildRestorePlan(snapshot *SnapshotState, live *LiveState, mode RestoreMode
 	return plan, nil
 }
 
-func (s *Service) loadSnapshotState(ctx context.Context, runID int64) (*SnapshotState, error) {
-	manifest, err := s.LoadManifest(ctx, runID)
-	if err != nil {
-		return nil, err
-	}
-
+func snapshotStateFromManifest(runID int64, manifest *Manifest) *SnapshotState {
 	torrents := make(map[string]SnapshotTorrent, len(manifest.Items))
 	for _, item := range manifest.Items {
 		hash := normalizeLowerTrim(item.Hash)
@@ -341,6 +411,7 @@ func (s *Service) loadSnapshotState(ctx context.Context, runID int64) (*Snapshot
 			SizeBytes:   item.SizeBytes,
 			InfoHashV1:  item.InfoHashV1,
 			InfoHashV2:  item.InfoHashV2,
+			State:       item.State,
 		}
 	}
 
@@ -368,7 +439,7 @@ func (s *Service) loadSnapshotState(ctx context.Context, runID int64) (*Snapshot
 		Categories: categorySnapshots,
 		Tags:       tagSet,
 		Torrents:   torrents,
-	}, nil
+	}
 }
 
 func (s *Service) loadLiveState(ctx context.Context, instanceID int) (*LiveState, error) {
@@ -425,6 +496,15 @@ func (s *Service) loadLiveState(ctx context.Context, instanceID int) (*LiveState
 			InfoHashV2:  strings.TrimSpace(torrent.InfohashV2),
 			SavePath:    strings.TrimSpace(torrent.SavePath),
 			SizeBytes:   torrent.TotalSize,
+
+			RatioLimit:               torrent.RatioLimit,
+			SeedingTimeLimit:         torrent.SeedingTimeLimit,
+			InactiveSeedingTimeLimit: torrent.InactiveSeedingTimeLimit,
+			ShareLimitAction:         torrent.ShareLimitAction,
+			UploadLimit:              torrent.UpLimit,
+			DownloadLimit:            torrent.DlLimit,
+			SequentialDownload:       torrent.SequentialDownload,
+			FirstLastPiecePrio:       torrent.FirstLastPiecePrio,
 		}
 	}
 
@@ -614,6 +694,7 @@ func snapshotTorrentToManifestItem(t SnapshotTorrent) ManifestItem {
 		SizeBytes:   t.SizeBytes,
 		TorrentBlob: t.BlobPath,
 		SavePath:    t.SavePath,
+		State:       t.State,
 	}
 	if t.Category != nil {
 		categoryCopy := strings.TrimSpace(*t.Category)
@@ -708,6 +789,8 @@ func computeTorrentChanges(snapshot SnapshotTorrent, live LiveTorrent) []DiffCha
 		})
 	}
 
+	changes = append(changes, computeStateChanges(snapshot.State, live)...)
+
 	return changes
 }
 
@@ -722,6 +805,15 @@ func cloneLiveTorrent(t LiveTorrent) LiveTorrent {
 		InfoHashV2:  t.InfoHashV2,
 		SavePath:    t.SavePath,
 		SizeBytes:   t.SizeBytes,
+
+		RatioLimit:               t.RatioLimit,
+		SeedingTimeLimit:         t.SeedingTimeLimit,
+		InactiveSeedingTimeLimit: t.InactiveSeedingTimeLimit,
+		ShareLimitAction:         t.ShareLimitAction,
+		UploadLimit:              t.UploadLimit,
+		DownloadLimit:            t.DownloadLimit,
+		SequentialDownload:       t.SequentialDownload,
+		FirstLastPiecePrio:       t.FirstLastPiecePrio,
 	}
 }
 
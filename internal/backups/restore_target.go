@@ -0,0 +1,239 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/fsops"
+	"github.com/autobrr/qui/internal/models"
+)
+
+// ErrInvalidRestoreTarget wraps restore target validation errors.
+var ErrInvalidRestoreTarget = errors.New("invalid restore target")
+
+// RestoreTarget redirects a restore to another instance and rewrites the
+// snapshot for it, e.g. when migrating a seedbox to a new qBittorrent
+// install whose data lives under a different mount.
+type RestoreTarget struct {
+	// InstanceID is the instance to restore into; zero keeps the run's own.
+	InstanceID int `json:"instanceId,omitempty"`
+	// PathMappings rewrite torrent and category save paths by prefix. The
+	// longest matching prefix wins.
+	PathMappings []PathMapping `json:"pathMappings,omitempty"`
+	// CategoryPaths sets the save path of categories by name, after the
+	// path mappings have been applied.
+	CategoryPaths map[string]string `json:"categoryPaths,omitempty"`
+	// TrackerRewrites are applied in order to every announce URL of the
+	// torrents added by the restore, e.g. to swap a passkey.
+	TrackerRewrites []TrackerRewrite `json:"trackerRewrites,omitempty"`
+}
+
+// PathMapping replaces the save path prefix From with To. Prefixes match on
+// whole path elements, so /data/tv does not match /data/tv2.
+type PathMapping struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// TrackerRewrite replaces From with To in announce URLs.
+type TrackerRewrite struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (t *RestoreTarget) validate() error {
+	if t == nil {
+		return nil
+	}
+	if t.InstanceID < 0 {
+		return fmt.Errorf("%w: invalid instance ID %d", ErrInvalidRestoreTarget, t.InstanceID)
+	}
+	for _, m := range t.PathMappings {
+		if normalizeSavePathForCompare(m.From) == "" {
+			return fmt.Errorf("%w: path mapping needs a source prefix", ErrInvalidRestoreTarget)
+		}
+	}
+	for name := range t.CategoryPaths {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%w: category path needs a category name", ErrInvalidRestoreTarget)
+		}
+	}
+	for _, r := range t.TrackerRewrites {
+		if r.From == "" {
+			return fmt.Errorf("%w: tracker rewrite needs a value to replace", ErrInvalidRestoreTarget)
+		}
+	}
+	return nil
+}
+
+// apply rewrites the save paths in snapshot and points it at the target
+// instance.
+func (t *RestoreTarget) apply(snapshot *SnapshotState) {
+	if t == nil {
+		return
+	}
+	if t.InstanceID > 0 {
+		snapshot.InstanceID = t.InstanceID
+	}
+
+	for hash, torrent := range snapshot.Torrents {
+		torrent.SavePath = remapSavePath(torrent.SavePath, t.PathMappings)
+		if torrent.State != nil && len(torrent.State.Trackers) > 0 && len(t.TrackerRewrites) > 0 {
+			state := *torrent.State
+			state.Trackers = make([]string, len(torrent.State.Trackers))
+			for i, url := range torrent.State.Trackers {
+				state.Trackers[i] = rewriteTrackerURL(url, t.TrackerRewrites)
+			}
+			torrent.State = &state
+		}
+		snapshot.Torrents[hash] = torrent
+	}
+	for name, category := range snapshot.Categories {
+		category.SavePath = remapSavePath(category.SavePath, t.PathMappings)
+		snapshot.Categories[name] = category
+	}
+	for name, savePath := range t.CategoryPaths {
+		name = strings.TrimSpace(name)
+		if _, ok := snapshot.Categories[name]; ok {
+			snapshot.Categories[name] = models.CategorySnapshot{SavePath: strings.TrimSpace(savePath)}
+		}
+	}
+}
+
+// remapSavePath rewrites p with the mapping whose prefix matches most of it.
+// Like normalizeSavePathForCompare, it treats paths as opaque qBittorrent-side
+// strings in either POSIX or Windows style.
+func remapSavePath(p string, mappings []PathMapping) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return p
+	}
+
+	best := -1
+	var rest string
+	for i, m := range mappings {
+		from := normalizeSavePathForCompare(m.From)
+		if from == "" || !strings.HasPrefix(p, from) {
+			continue
+		}
+		remainder := p[len(from):]
+		if remainder != "" && remainder[0] != '/' && remainder[0] != '\\' {
+			continue
+		}
+		if best < 0 || len(from) > len(normalizeSavePathForCompare(mappings[best].From)) {
+			best, rest = i, remainder
+		}
+	}
+	if best < 0 {
+		return p
+	}
+
+	to := strings.TrimSpace(mappings[best].To)
+	if rest == "" {
+		return to
+	}
+	// Moving between Windows and POSIX hosts: give the remainder the
+	// separators of the new prefix.
+	fromSep, toSep := pathSeparator(mappings[best].From), pathSeparator(to)
+	if fromSep != 0 && toSep != 0 && fromSep != toSep {
+		rest = strings.ReplaceAll(rest, string(fromSep), string(toSep))
+	}
+	return normalizeSavePathForCompare(to) + rest
+}
+
+// pathSeparator returns the separator p uses, or 0 when it uses none or both.
+func pathSeparator(p string) byte {
+	slash, backslash := strings.Contains(p, "/"), strings.Contains(p, `\`)
+	switch {
+	case slash && !backslash:
+		return '/'
+	case backslash && !slash:
+		return '\\'
+	default:
+		return 0
+	}
+}
+
+// rewriteTrackerURL applies rewrites to one announce URL.
+func rewriteTrackerURL(url string, rewrites []TrackerRewrite) string {
+	for _, r := range rewrites {
+		url = strings.ReplaceAll(url, r.From, r.To)
+	}
+	return url
+}
+
+// checkRestoreData looks for the content of every torrent the plan adds at
+// the path it will be added with, and flags the ones with nothing there.
+// Torrents whose location depends on the target's default save path are not
+// checked.
+func (s *Service) checkRestoreData(ctx context.Context, plan *RestorePlan, categories map[string]string) {
+	if s.filesystem == nil || len(plan.Torrents.Add) == 0 {
+		return
+	}
+
+	backend, err := s.filesystem.GetBackend(ctx, plan.InstanceID)
+	if err != nil {
+		log.Debug().Err(err).Int("instanceID", plan.InstanceID).Msg("Restore: data check unavailable")
+		return
+	}
+
+	missing := 0
+	for i := range plan.Torrents.Add {
+		spec := &plan.Torrents.Add[i]
+
+		base := strings.TrimSpace(spec.Manifest.SavePath)
+		if base == "" && spec.Manifest.Category != nil {
+			base = categories[strings.TrimSpace(*spec.Manifest.Category)]
+		}
+		if base == "" || !filepath.IsAbs(base) || spec.Manifest.Name == "" {
+			continue
+		}
+
+		dataPath := filepath.Join(base, spec.Manifest.Name)
+		if _, err := backend.Stat(ctx, dataPath); err != nil {
+			if errors.Is(err, fsops.ErrNoFilesystemAccess) {
+				plan.Warnings = append(plan.Warnings, "Data check skipped: the target instance has no filesystem access")
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				log.Debug().Err(err).Str("path", dataPath).Msg("Restore: data check failed")
+				continue
+			}
+			spec.DataMissing = true
+			missing++
+		}
+		spec.DataPath = dataPath
+	}
+
+	if missing > 0 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("%d torrent(s) have no data at their restore location; they will need to download again", missing))
+	}
+}
+
+// effectiveCategoryPaths returns the save path each category will have once
+// the plan's category changes are applied.
+func effectiveCategoryPaths(live map[string]LiveCategory, plan CategoryPlan) map[string]string {
+	paths := make(map[string]string, len(live)+len(plan.Create))
+	for name, category := range live {
+		paths[name] = category.SavePath
+	}
+	for _, spec := range plan.Create {
+		paths[spec.Name] = spec.SavePath
+	}
+	for _, update := range plan.Update {
+		paths[update.Name] = update.DesiredPath
+	}
+	return paths
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/fsops"
+	localfs "github.com/autobrr/qui/internal/fsops/local"
+	"github.com/autobrr/qui/internal/models"
+)
+
+type staticFilesystem struct {
+	backend fsops.Backend
+}
+
+func (f staticFilesystem) GetBackend(context.Context, int) (fsops.Backend, error) {
+	return f.backend, nil
+}
+
+func TestRemapSavePath(t *testing.T) {
+	t.Parallel()
+
+	mappings := []PathMapping{
+		{From: "/data", To: "/srv"},
+		{From: "/data/tv/", To: "/mnt/media/tv"},
+		{From: `D:\Torrents`, To: "/downloads"},
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "longest prefix wins", in: "/data/tv/Show", want: "/mnt/media/tv/Show"},
+		{name: "shorter prefix", in: "/data/movies", want: "/srv/movies"},
+		{name: "exact match", in: "/data/tv", want: "/mnt/media/tv"},
+		{name: "partial element does not match", in: "/data/tv2/Show", want: "/srv/tv2/Show"},
+		{name: "unmapped path", in: "/other/Show", want: "/other/Show"},
+		{name: "windows to posix", in: `D:\Torrents\cross-seed\Show`, want: "/downloads/cross-seed/Show"},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, remapSavePath(tt.in, mappings))
+		})
+	}
+}
+
+func TestPlanRestoreOntoOtherInstance(t *testing.T) {
+	t.Parallel()
+
+	dataRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dataRoot, "tv", "Show"), 0o755))
+
+	svc := NewService(nil, &stubBackupSyncManager{}, nil, Config{WorkerCount: 1}, nil)
+	svc.SetFilesystem(staticFilesystem{backend: localfs.NewBackend()})
+
+	tv, movies := "tv", "movies"
+	snapshot := &SnapshotState{
+		RunID:      7,
+		InstanceID: 1,
+		Categories: map[string]models.CategorySnapshot{
+			"tv":     {SavePath: "/data/tv"},
+			"movies": {SavePath: "/data/movies"},
+		},
+		Tags: map[string]struct{}{},
+		Torrents: map[string]SnapshotTorrent{
+			"aaa": {Hash: "aaa", Name: "Show", Category: &tv},
+			"bbb": {Hash: "bbb", Name: "Film", Category: &movies, SavePath: "/data/cross-seed"},
+		},
+	}
+
+	plan, err := svc.planRestore(context.Background(), snapshot, RestoreModeIncremental, &RestorePlanOptions{
+		Target: &RestoreTarget{
+			InstanceID:      2,
+			PathMappings:    []PathMapping{{From: "/data", To: dataRoot}},
+			CategoryPaths:   map[string]string{"movies": "/srv/movies"},
+			TrackerRewrites: []TrackerRewrite{{From: "OLDKEY", To: "NEWKEY"}},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, plan.InstanceID)
+	require.Equal(t, 1, plan.SourceInstanceID)
+	require.Equal(t, []TrackerRewrite{{From: "OLDKEY", To: "NEWKEY"}}, plan.TrackerRewrites)
+	require.ElementsMatch(t, []CategorySpec{
+		{Name: "tv", SavePath: filepath.Join(dataRoot, "tv")},
+		{Name: "movies", SavePath: "/srv/movies"},
+	}, plan.Categories.Create)
+
+	require.Len(t, plan.Torrents.Add, 2)
+	show, film := plan.Torrents.Add[0], plan.Torrents.Add[1]
+	require.Equal(t, filepath.Join(dataRoot, "tv", "Show"), show.DataPath)
+	require.False(t, show.DataMissing)
+	require.Equal(t, filepath.Join(dataRoot, "cross-seed"), film.Manifest.SavePath)
+	require.True(t, film.DataMissing, "pinned torrent without data at the remapped path must be flagged")
+	require.Len(t, plan.Warnings, 1)
+}
+
+func TestPlanRestoreRejectsInvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(nil, &stubBackupSyncManager{}, nil, Config{WorkerCount: 1}, nil)
+	snapshot := &SnapshotState{InstanceID: 1}
+
+	_, err := svc.planRestore(context.Background(), snapshot, RestoreModeIncremental, &RestorePlanOptions{
+		Target: &RestoreTarget{PathMappings: []PathMapping{{From: " / ", To: "/srv"}}},
+	})
+	require.ErrorIs(t, err, ErrInvalidRestoreTarget)
+}
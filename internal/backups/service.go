@@ -5,9 +5,7 @@ package backups
 
 import (
 	"context"
-	"crypto/sha256"
 	"database/sql"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -29,6 +27,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/autobrr/qui/internal/fsops"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/qbittorrent"
 	"github.com/autobrr/qui/internal/services/activity"
@@ -70,9 +69,11 @@ type Service struct {
 	store          *models.BackupStore
 	reader         backupReader
 	tracker        backupTrackerSource
+	files          backupFilesSource
 	categoryWriter backupCategoryMutator
 	tagWriter      backupTagMutator
 	torrentWriter  backupTorrentMutator
+	stateWriter    backupStateMutator
 	jackettSvc     *jackett.Service
 	notifier       notifications.Notifier
 	cfg            Config
@@ -93,7 +94,12 @@ type Service struct {
 
 	now func() time.Time
 
+	// passphraseIdentities caches keys derived from backup passphrases,
+	// keyed by the SHA-256 of the salt and passphrase.
+	passphraseIdentities sync.Map
+
 	activityPublisher activity.Publisher
+	filesystem        backupFilesystem
 }
 
 type backupReader interface {
@@ -123,6 +129,28 @@ type backupTorrentMutator interface {
 	BulkAction(ctx context.Context, instanceID int, hashes []string, action string) error
 }
 
+// backupFilesSource lists torrent files for capturing priorities and renames.
+type backupFilesSource interface {
+	GetTorrentFilesBatch(ctx context.Context, instanceID int, hashes []string) (map[string]qbt.TorrentFiles, error)
+}
+
+// backupStateMutator re-applies captured per-torrent state during restores.
+type backupStateMutator interface {
+	SetTorrentShareLimit(ctx context.Context, instanceID int, hashes []string, ratioLimit float64, seedingTimeLimit, inactiveSeedingTimeLimit int64, shareLimitAction, shareLimitsMode string) error
+	SetTorrentUploadLimit(ctx context.Context, instanceID int, hashes []string, limitKBs int64) error
+	SetTorrentDownloadLimit(ctx context.Context, instanceID int, hashes []string, limitKBs int64) error
+	AddTorrentTrackers(ctx context.Context, instanceID int, hash, urls string) error
+	RemoveTorrentTrackers(ctx context.Context, instanceID int, hash, urls string) error
+	GetTorrentFiles(ctx context.Context, instanceID int, hash string) (*qbt.TorrentFiles, error)
+	SetTorrentFilePriority(ctx context.Context, instanceID int, hash string, indices []int, priority int) error
+	RenameTorrentFile(ctx context.Context, instanceID int, hash, oldPath, newPath string) error
+}
+
+// backupFilesystem resolves the filesystem holding an instance's torrent data.
+type backupFilesystem interface {
+	GetBackend(ctx context.Context, instanceID int) (fsops.Backend, error)
+}
+
 type job struct {
 	runID      int64
 	instanceID int
@@ -130,7 +158,10 @@ type job struct {
 }
 
 // Manifest captures details about a backup run and its contents for API responses and archived metadata.
+// Version is ManifestVersion for manifests written by this release; manifests
+// from before versioning have none and carry no torrent state.
 type Manifest struct {
+	Version      int                                `json:"version,omitempty"`
 	InstanceID   int                                `json:"instanceId"`
 	Kind         string                             `json:"kind"`
 	GeneratedAt  time.Time                          `json:"generatedAt"`
@@ -152,6 +183,8 @@ type ManifestItem struct {
 	Tags        []string `json:"tags,omitempty"`
 	TorrentBlob string   `json:"torrentBlob,omitempty"`
 	SavePath    string   `json:"savePath,omitempty"`
+	// State is the torrent's captured state; absent in version 1 manifests.
+	State *models.BackupTorrentState `json:"state,omitempty"`
 }
 
 func NewService(store *models.BackupStore, reader backupReader, jackettSvc any, cfg Config, notifier notifications.Notifier) *Service {
@@ -206,6 +239,9 @@ func NewService(store *models.BackupStore, reader backupReader, jackettSvc any,
 	if tracker, ok := reader.(backupTrackerSource); ok {
 		svc.tracker = tracker
 	}
+	if files, ok := reader.(backupFilesSource); ok {
+		svc.files = files
+	}
 	if writer, ok := reader.(backupCategoryMutator); ok {
 		svc.categoryWriter = writer
 	}
@@ -215,6 +251,9 @@ func NewService(store *models.BackupStore, reader backupReader, jackettSvc any,
 	if writer, ok := reader.(backupTorrentMutator); ok {
 		svc.torrentWriter = writer
 	}
+	if writer, ok := reader.(backupStateMutator); ok {
+		svc.stateWriter = writer
+	}
 
 	return svc
 }
@@ -229,6 +268,16 @@ func (s *Service) SetActivityPublisher(publisher activity.Publisher) {
 	s.activityPublisher = publisher
 }
 
+// SetFilesystem wires the filesystem backends restore previews use to check
+// that torrent data exists on the target instance. Safe to call once at
+// startup.
+func (s *Service) SetFilesystem(filesystem backupFilesystem) {
+	if s == nil || filesystem == nil {
+		return
+	}
+	s.filesystem = filesystem
+}
+
 // emitRunActivity signals connected clients that a backup run's status changed
 // so they refetch instead of polling. Must be called after the state transition
 // is persisted and any held lock released.
@@ -633,6 +682,19 @@ func (s *Service) handleJob(ctx context.Context, j job) {
 			}
 		}
 
+		if result.settings != nil && result.settings.Destination != nil && len(result.manifestData) > 0 {
+			if err := s.uploadRun(ctx, result.settings.Destination, result.manifestName, result.manifestData, result.items); err != nil {
+				// The local run is intact, so the run stays successful; the
+				// message tells the user the offsite copy is missing.
+				msg := "Upload to backup destination failed: " + err.Error()
+				log.Warn().Err(err).Int("instanceID", j.instanceID).Int64("runID", j.runID).Msg("Failed to upload backup to destination")
+				_ = s.store.UpdateRunMetadata(ctx, j.runID, func(run *models.BackupRun) error {
+					run.ErrorMessage = &msg
+					return nil
+				})
+			}
+		}
+
 		if result.settings != nil {
 			if err := s.applyRetention(ctx, j.instanceID, result.settings); err != nil {
 				log.Warn().Err(err).Int("instanceID", j.instanceID).Msg("Failed to apply backup retention")
@@ -662,6 +724,8 @@ func (s *Service) notify(ctx context.Context, event notifications.Event) {
 
 type backupResult struct {
 	manifestRelPath *string
+	manifestName    string
+	manifestData    []byte
 	totalBytes      int64
 	torrentCount    int
 	categoryCounts  map[string]int
@@ -732,6 +796,11 @@ func (s *Service) executeBackup(ctx context.Context, j job) (*backupResult, erro
 		sort.Strings(snapshotTags)
 	}
 
+	keys, err := s.loadBackupKeys(ctx, settings.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
 	webAPIVersion := ""
 	patchTrackers := false
 	if version, err := s.reader.GetInstanceWebAPIVersion(ctx, j.instanceID); err != nil {
@@ -800,7 +869,7 @@ func (s *Service) executeBackup(ctx context.Context, j job) (*backupResult, erro
 			// bounded by exportWorkers regardless of how the pool schedules.
 			var lastExportElapsed time.Duration
 			for idx := range indexes {
-				res, err := s.exportBackupTorrent(gctx, j, torrents[idx], patchTrackers, webAPIVersion, &lastExportElapsed)
+				res, err := s.exportBackupTorrent(gctx, j, keys, torrents[idx], patchTrackers, webAPIVersion, &lastExportElapsed)
 				if err != nil {
 					return err
 				}
@@ -814,6 +883,22 @@ func (s *Service) executeBackup(ctx context.Context, j job) (*backupResult, erro
 		return nil, err
 	}
 
+	// File priorities and names come from one batched files request; the rest
+	// of the captured state is already on the torrent list.
+	var torrentFiles map[string]qbt.TorrentFiles
+	if s.files != nil {
+		hashes := make([]string, 0, len(torrents))
+		for idx, torrent := range torrents {
+			if !results[idx].skipped {
+				hashes = append(hashes, torrent.Hash)
+			}
+		}
+		if torrentFiles, err = s.files.GetTorrentFilesBatch(ctx, j.instanceID, hashes); err != nil {
+			log.Warn().Err(err).Int("instanceID", j.instanceID).Msg("Failed to load torrent files; backup will not include file priorities or renames")
+			torrentFiles = nil
+		}
+	}
+
 	for idx, torrent := range torrents {
 		res := results[idx]
 		if res.skipped {
@@ -885,6 +970,8 @@ func (s *Service) executeBackup(ctx context.Context, j job) (*backupResult, erro
 			sp := storeSavePath
 			item.SavePath = &sp
 		}
+		state := captureTorrentState(torrent, res.fileNames, torrentFiles[normalizeLowerTrim(torrent.Hash)])
+		item.State = state
 		items = append(items, item)
 
 		manifestItem := ManifestItem{
@@ -911,10 +998,12 @@ func (s *Service) executeBackup(ctx context.Context, j job) (*backupResult, erro
 		if storeSavePath != "" {
 			manifestItem.SavePath = storeSavePath
 		}
+		manifestItem.State = state
 		manifestItems = append(manifestItems, manifestItem)
 	}
 
 	manifest := Manifest{
+		Version:      ManifestVersion,
 		InstanceID:   j.instanceID,
 		Kind:         string(j.kind),
 		GeneratedAt:  s.now().UTC(),
@@ -928,6 +1017,9 @@ func (s *Service) executeBackup(ctx context.Context, j job) (*backupResult, erro
 	if err != nil {
 		return nil, fmt.Errorf("marshal manifest: %w", err)
 	}
+	if manifestData, err = keys.seal(manifestData); err != nil {
+		return nil, fmt.Errorf("encrypt manifest: %w", err)
+	}
 
 	manifestPointer := &manifestRelPath
 	if err := os.WriteFile(manifestAbsPath, manifestData, 0o600); err != nil {
@@ -937,6 +1029,8 @@ func (s *Service) executeBackup(ctx context.Context, j job) (*backupResult, erro
 
 	return &backupResult{
 		manifestRelPath: manifestPointer,
+		manifestName:    manifestFileName,
+		manifestData:    manifestData,
 		totalBytes:      totalBytes,
 		torrentCount:    len(manifestItems),
 		categoryCounts:  categoryCounts,
@@ -964,14 +1058,16 @@ type exportedTorrent struct {
 	dataLen     int
 	filename    string
 	blobRelPath *string
+	// fileNames are the file names in the .torrent, used to detect renames.
+	fileNames []string
 }
 
 // exportBackupTorrent produces the .torrent payload for one torrent (cached
 // blob or live export), patches trackers if needed, and persists the blob to
-// the cache. It touches no order-dependent backup state, so callers may run it
+// the cache, encrypted when keys has a sealer. It touches no order-dependent backup state, so callers may run it
 // concurrently for distinct torrents. lastExportElapsed carries the adaptive
 // delay state between consecutive calls on the same worker.
-func (s *Service) exportBackupTorrent(ctx context.Context, j job, torrent qbt.Torrent, patchTrackers bool, webAPIVersion string, lastExportElapsed *time.Duration) (exportedTorrent, error) {
+func (s *Service) exportBackupTorrent(ctx context.Context, j job, keys *backupKeys, torrent qbt.Torrent, patchTrackers bool, webAPIVersion string, lastExportElapsed *time.Duration) (exportedTorrent, error) {
 	select {
 	case <-ctx.Done():
 		return exportedTorrent{}, ctx.Err()
@@ -985,7 +1081,7 @@ func (s *Service) exportBackupTorrent(ctx context.Context, j job, torrent qbt.To
 		blobRelPath   *string
 	)
 
-	cachedTorrent, cacheErr := s.loadCachedTorrent(ctx, j.instanceID, torrent.Hash)
+	cachedTorrent, cacheErr := s.loadCachedTorrent(ctx, j.instanceID, keys, torrent.Hash)
 	if cacheErr != nil {
 		log.Warn().Err(cacheErr).Str("hash", torrent.Hash).Msg("Failed to load cached torrent blob")
 	}
@@ -993,8 +1089,12 @@ func (s *Service) exportBackupTorrent(ctx context.Context, j job, torrent qbt.To
 		data = cachedTorrent.data
 		suggestedName = torrent.Name
 		trackerDomain = trackerDomainFromTorrent(torrent)
-		rel := cachedTorrent.relPath
-		blobRelPath = &rel
+		// A blob cached under other encryption settings is re-cached below
+		// so the run matches the instance's current settings.
+		if cachedTorrent.matches(keys) {
+			rel := cachedTorrent.relPath
+			blobRelPath = &rel
+		}
 	}
 
 	if data == nil {
@@ -1042,24 +1142,28 @@ func (s *Service) exportBackupTorrent(ctx context.Context, j job, torrent qbt.To
 	}
 
 	if blobRelPath == nil && s.cacheDir != "" {
-		sum := sha256.Sum256(data)
-		hash := hex.EncodeToString(sum[:])
-		blobName := hash + ".torrent"
-		subdir := ""
-		if len(hash) >= 6 {
-			subdir = filepath.Join(hash[0:2], hash[2:4], hash[4:6])
-		}
-		if err := cacheTorrentBlob(s.cacheDir, filepath.Join(subdir, blobName), data); err != nil {
+		blobName := blobCacheName(data, keys.sealer)
+		payload, err := keys.seal(data)
+		if err != nil {
+			return exportedTorrent{}, fmt.Errorf("encrypt torrent %s: %w", torrent.Hash, err)
+		}
+		if err := cacheTorrentBlob(s.cacheDir, filepath.FromSlash(blobName), payload); err != nil {
 			return exportedTorrent{}, err
 		}
-		rel := filepath.ToSlash(filepath.Join("backups", "torrents", subdir, blobName))
+		rel := "backups/torrents/" + blobName
 		blobRelPath = &rel
 	}
 
+	fileNames, err := torrentFileNames(data)
+	if err != nil {
+		log.Debug().Err(err).Str("hash", torrent.Hash).Int("instanceID", j.instanceID).Msg("Failed to read torrent file names; renames will not be captured")
+	}
+
 	return exportedTorrent{
 		dataLen:     len(data),
 		filename:    torrentname.SanitizeExportFilename(suggestedName, torrent.Hash, trackerDomain, torrent.Hash),
 		blobRelPath: blobRelPath,
+		fileNames:   fileNames,
 	}, nil
 }
 
@@ -1539,6 +1643,7 @@ func (s *Service) cleanupRunFiles(ctx context.Context, runIDs []int64) error {
 
 	var filesToDelete []string
 	var itemsToCleanup []*models.BackupItem
+	remoteManifests := make(map[int][]string)
 
 	for _, runID := range runIDs {
 		run, exists := runMap[runID]
@@ -1557,6 +1662,7 @@ func (s *Service) cleanupRunFiles(ctx context.Context, runIDs []int64) error {
 		itemsToCleanup = append(itemsToCleanup, runItems...)
 
 		if run.ManifestPath != nil {
+			remoteManifests[run.InstanceID] = append(remoteManifests[run.InstanceID], *run.ManifestPath)
 			if abs := s.ResolveBackupPath(*run.ManifestPath); abs != "" {
 				filesToDelete = append(filesToDelete, abs)
 			} else {
@@ -1580,7 +1686,11 @@ func (s *Service) cleanupRunFiles(ctx context.Context, runIDs []int64) error {
 		log.Warn().Err(err).Msg("Failed to cleanup runs from database")
 	}
 
-	s.cleanupTorrentBlobs(ctx, itemsToCleanup)
+	removedBlobs := s.cleanupTorrentBlobs(ctx, itemsToCleanup)
+
+	for instanceID, manifests := range remoteManifests {
+		s.pruneRemote(ctx, instanceID, manifests, removedBlobs)
+	}
 
 	return nil
 }
@@ -1593,6 +1703,8 @@ func (s *Service) GetSettings(ctx context.Context, instanceID int) (*models.Back
 
 	s.normalizeAndPersistSettings(ctx, settings)
 	settings.CustomPath = nil
+	settings.Destination = settings.Destination.Redacted()
+	settings.Encryption = settings.Encryption.Redacted()
 
 	return settings, nil
 }
@@ -1600,6 +1712,32 @@ func (s *Service) GetSettings(ctx context.Context, instanceID int) (*models.Back
 func (s *Service) UpdateSettings(ctx context.Context, settings *models.BackupSettings) error {
 	settings.CustomPath = nil
 	normalizeBackupSettings(settings)
+
+	existing, err := s.store.GetSettings(ctx, settings.InstanceID)
+	if err != nil {
+		return err
+	}
+
+	// Omitted destination and encryption settings are kept, so clients that
+	// only edit the schedule don't drop them; an empty type or mode clears them.
+	switch {
+	case settings.Destination == nil:
+		settings.Destination = existing.Destination
+	case settings.Destination.Type == "":
+		settings.Destination = nil
+	default:
+		settings.Destination.KeepRedactedSecrets(existing.Destination)
+		if err := ValidateDestination(settings.Destination); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidDestination, err)
+		}
+	}
+
+	if settings.Encryption == nil {
+		settings.Encryption = existing.Encryption
+	} else if settings.Encryption, err = s.prepareEncryption(settings.Encryption, existing.Encryption); err != nil {
+		return err
+	}
+
 	return s.store.UpsertSettings(ctx, settings)
 }
 
@@ -1649,7 +1787,13 @@ func (s *Service) DeleteRun(ctx context.Context, runID int64) error {
 		return err
 	}
 
-	s.cleanupTorrentBlobs(ctx, items)
+	removedBlobs := s.cleanupTorrentBlobs(ctx, items)
+
+	var remoteManifests []string
+	if run.ManifestPath != nil {
+		remoteManifests = append(remoteManifests, *run.ManifestPath)
+	}
+	s.pruneRemote(ctx, run.InstanceID, remoteManifests, removedBlobs)
 
 	return nil
 }
@@ -1685,6 +1829,7 @@ func (s *Service) LoadManifest(ctx context.Context, runID int64) (*Manifest, err
 	}
 
 	manifest := &Manifest{
+		Version:      ManifestVersion,
 		InstanceID:   run.InstanceID,
 		Kind:         string(run.Kind),
 		GeneratedAt:  run.RequestedAt,
@@ -1722,6 +1867,7 @@ func (s *Service) LoadManifest(ctx context.Context, runID int64) (*Manifest, err
 		if item.SavePath != nil {
 			entry.SavePath = *item.SavePath
 		}
+		entry.State = item.State
 		manifest.Items = append(manifest.Items, entry)
 	}
 
@@ -1743,11 +1889,30 @@ func (s *Service) ImportManifestFromDir(ctx context.Context, instanceID int, man
 
 	log.Info().Int("instanceID", instanceID).Str("requestedBy", requestedBy).Int("dataSize", len(manifestData)).Int("torrentPaths", len(torrentPaths)).Str("backupDir", rootDir).Msg("Starting manifest import from dir")
 
+	manifestData, err := s.openSealed(ctx, manifestData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt manifest: %w", err)
+	}
+
 	var manifest Manifest
 	if err := json.Unmarshal(manifestData, &manifest); err != nil {
 		log.Error().Err(err).Msg("Failed to parse manifest JSON")
 		return nil, fmt.Errorf("failed to parse manifest: %w", err)
 	}
+	if err := checkManifestVersion(&manifest); err != nil {
+		return nil, err
+	}
+
+	// Imported blobs are stored encrypted when the instance encrypts its
+	// backups, whatever the export they came from.
+	settings, err := s.store.GetSettings(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load backup settings: %w", err)
+	}
+	keys, err := s.loadBackupKeys(ctx, settings.Encryption)
+	if err != nil {
+		return nil, err
+	}
 
 	log.Info().Int("manifestItemCount", len(manifest.Items)).Int("manifestTorrentCount", manifest.TorrentCount).Msg("Manifest parsed successfully")
 
@@ -1827,6 +1992,8 @@ func (s *Service) ImportManifestFromDir(ctx context.Context, instanceID int, man
 			backupItem.SavePath = &savePath
 		}
 
+		backupItem.State = item.State
+
 		if item.TorrentBlob != "" {
 			// Validate blob path to prevent directory traversal
 			slashRel := backupRelPath(item.TorrentBlob)
@@ -1845,7 +2012,7 @@ func (s *Service) ImportManifestFromDir(ctx context.Context, instanceID int, man
 			// Check if torrent file path was provided from temp directory
 			if torrentPaths != nil && item.ArchivePath != "" {
 				if tempPath, ok := torrentPaths[item.ArchivePath]; ok {
-					err := s.copyTorrentFromTemp(tempPath, rootDir, rel)
+					err := s.copyTorrentFromTemp(tempPath, rootDir, rel, keys)
 					if err == nil {
 						if info, statErr := os.Stat(absPath); statErr == nil {
 							totalTorrentFileBytes += info.Size()
@@ -1896,7 +2063,7 @@ func (s *Service) ImportManifestFromDir(ctx context.Context, instanceID int, man
 		s.progressMu.Unlock()
 		log.Info().Int64("runID", run.ID).Int("total", len(missing)).Msg("Initialized import progress")
 		s.wg.Go(func() {
-			s.downloadMissingTorrents(run.ID, instanceID, rootDir, missing)
+			s.downloadMissingTorrents(run.ID, instanceID, rootDir, missing, keys)
 		})
 	} else {
 		// No missing torrents, mark as completed immediately
@@ -1932,8 +2099,9 @@ func (s *Service) ImportManifestFromDir(ctx context.Context, instanceID int, man
 }
 
 // copyTorrentFromTemp validates a torrent from the import temp dir and caches
-// it at the final blob location through the same atomic write as live exports.
-func (s *Service) copyTorrentFromTemp(srcPath, rootDir, relPath string) error {
+// it at the final blob location through the same atomic write as live exports,
+// encrypted when keys has a sealer.
+func (s *Service) copyTorrentFromTemp(srcPath, rootDir, relPath string, keys *backupKeys) error {
 	data, err := os.ReadFile(srcPath)
 	if err != nil {
 		return fmt.Errorf("read temp file: %w", err)
@@ -1946,13 +2114,17 @@ func (s *Service) copyTorrentFromTemp(srcPath, rootDir, relPath string) error {
 		return errors.New("invalid torrent data: not a bencoded dict")
 	}
 
-	return cacheTorrentBlob(rootDir, relPath, data)
+	payload, err := keys.seal(data)
+	if err != nil {
+		return fmt.Errorf("encrypt torrent: %w", err)
+	}
+	return cacheTorrentBlob(rootDir, relPath, payload)
 }
 
 // downloadMissingTorrents downloads torrent blobs in the background for
 // imported manifests. rootDir is the import's normalized backup root, the
 // same root missingTorrent.absPath was built from.
-func (s *Service) downloadMissingTorrents(runID int64, instanceID int, rootDir string, missing []missingTorrent) {
+func (s *Service) downloadMissingTorrents(runID int64, instanceID int, rootDir string, missing []missingTorrent, keys *backupKeys) {
 	if s.reader == nil {
 		log.Warn().Int64("runID", runID).Msg("No sync manager available for background torrent downloads")
 		s.markImportComplete(instanceID, runID)
@@ -1992,7 +2164,11 @@ func (s *Service) downloadMissingTorrents(runID int64, instanceID int, rootDir s
 			ctx = context.Background()
 		}
 		if data, _, _, err := s.reader.ExportTorrent(ctx, instanceID, mt.hash); err == nil {
-			if err := cacheTorrentBlob(rootDir, mt.relPath, data); err == nil {
+			payload, err := keys.seal(data)
+			if err == nil {
+				err = cacheTorrentBlob(rootDir, mt.relPath, payload)
+			}
+			if err == nil {
 				log.Trace().Int("downloaded", successCount+1).Int("total", total).Int64("runID", runID).Str("hash", mt.hash).Str("path", mt.absPath).Msg("Successfully cached missing torrent blob")
 				totalTorrentBytes += int64(len(data))
 				successCount++
@@ -2118,9 +2294,19 @@ func (s *Service) ResolveBackupPath(rel string) string {
 type cachedTorrent struct {
 	data    []byte
 	relPath string
+	sealed  bool
 }
 
-func (s *Service) loadCachedTorrent(ctx context.Context, instanceID int, hash string) (*cachedTorrent, error) {
+// matches reports whether the cached blob can be reused as-is under keys:
+// unencrypted when encryption is off, or sealed for the current key.
+func (c *cachedTorrent) matches(keys *backupKeys) bool {
+	if keys == nil || keys.sealer == nil {
+		return !c.sealed
+	}
+	return c.sealed && backupRelPath(c.relPath) == "torrents/"+blobCacheName(c.data, keys.sealer)
+}
+
+func (s *Service) loadCachedTorrent(ctx context.Context, instanceID int, keys *backupKeys, hash string) (*cachedTorrent, error) {
 	if s.cacheDir == "" {
 		return nil, nil
 	}
@@ -2145,14 +2331,21 @@ func (s *Service) loadCachedTorrent(ctx context.Context, instanceID int, hash st
 		return nil, err
 	}
 
+	sealed := isSealed(data)
+	if data, err = keys.open(data); err != nil {
+		return nil, fmt.Errorf("open cached torrent blob: %w", err)
+	}
+
 	// Keep the stored spelling so rows that share a blob agree on the rel
 	// path; blob refcounts compare these strings.
-	return &cachedTorrent{data: data, relPath: *rel}, nil
+	return &cachedTorrent{data: data, relPath: *rel, sealed: sealed}, nil
 }
 
-func (s *Service) cleanupTorrentBlobs(ctx context.Context, items []*models.BackupItem) {
+// cleanupTorrentBlobs deletes the blobs of items that no run references
+// anymore and returns their canonical rel paths.
+func (s *Service) cleanupTorrentBlobs(ctx context.Context, items []*models.BackupItem) []string {
 	if len(items) == 0 {
-		return
+		return nil
 	}
 
 	// Rows can reference the same blob under the canonical "backups/"-prefixed
@@ -2180,7 +2373,7 @@ func (s *Service) cleanupTorrentBlobs(ctx context.Context, items []*models.Backu
 	}
 
 	if len(uniqueBlobs) == 0 {
-		return
+		return nil
 	}
 
 	// Item rows are committed only at run end, so a live run's blob reuse is
@@ -2188,10 +2381,10 @@ func (s *Service) cleanupTorrentBlobs(ctx context.Context, items []*models.Backu
 	// across instances. Defer to the startup sweep while any run is active.
 	if active, err := s.store.FindIncompleteRuns(ctx); err != nil {
 		log.Warn().Err(err).Msg("Failed to check for active backup runs; skipping blob cleanup")
-		return
+		return nil
 	} else if len(active) > 0 {
 		log.Debug().Int("activeRuns", len(active)).Msg("Backup run in progress; deferring blob cleanup to the startup sweep")
-		return
+		return nil
 	}
 
 	refCounts, err := s.store.CountBlobReferencesBatch(ctx, lookup)
@@ -2200,10 +2393,10 @@ func (s *Service) cleanupTorrentBlobs(ctx context.Context, items []*models.Backu
 		// another run still references. Startup's cleanupOrphanedBlobs sweeps
 		// anything truly unreferenced later.
 		log.Warn().Err(err).Msg("Failed to count torrent blob references; skipping blob cleanup")
-		return
+		return nil
 	}
 
-	var blobsToDelete []string
+	var blobsToDelete, removed []string
 
 	for _, canon := range uniqueBlobs {
 		if refCounts[canon]+refCounts["backups/"+canon] > 0 {
@@ -2216,9 +2409,11 @@ func (s *Service) cleanupTorrentBlobs(ctx context.Context, items []*models.Backu
 			continue
 		}
 		blobsToDelete = append(blobsToDelete, abs)
+		removed = append(removed, canon)
 	}
 
 	s.deleteFilesParallel(ctx, blobsToDelete)
+	return removed
 }
 
 func (s *Service) deleteFilesParallel(ctx context.Context, paths []string) {
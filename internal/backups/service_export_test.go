@@ -89,7 +89,7 @@ func TestCopyTorrentFromTempAtomicWrite(t *testing.T) {
 	require.NoError(t, os.WriteFile(src, payload, 0o600))
 
 	rel := filepath.Join("backups", "torrents", "aa", "bb", "cc", "deadbeef.torrent")
-	require.NoError(t, svc.copyTorrentFromTemp(src, dataDir, rel))
+	require.NoError(t, svc.copyTorrentFromTemp(src, dataDir, rel, nil))
 
 	got, err := os.ReadFile(filepath.Join(dataDir, rel))
 	require.NoError(t, err)
@@ -103,7 +103,7 @@ func TestCopyTorrentFromTempAtomicWrite(t *testing.T) {
 	// Invalid payloads are rejected before anything is written.
 	badSrc := filepath.Join(t.TempDir(), "bad.torrent")
 	require.NoError(t, os.WriteFile(badSrc, []byte("not bencoded, but long enough to pass the size check"), 0o600))
-	require.ErrorContains(t, svc.copyTorrentFromTemp(badSrc, dataDir, filepath.Join("backups", "torrents", "bad.torrent")), "not a bencoded dict")
+	require.ErrorContains(t, svc.copyTorrentFromTemp(badSrc, dataDir, filepath.Join("backups", "torrents", "bad.torrent"), nil), "not a bencoded dict")
 	_, err = os.Stat(filepath.Join(dataDir, "backups", "torrents", "bad.torrent"))
 	require.ErrorIs(t, err, os.ErrNotExist)
 }
@@ -77,6 +77,86 @@ func patchTorrentTrackers(data []byte, trackers []string) ([]byte, bool, error)
 	return buf.Bytes(), true, nil
 }
 
+// rewriteTorrentTrackers applies rewrites to the announce URLs of a .torrent
+// file, keeping the tier layout of its announce-list. Like
+// patchTorrentTrackers, it never re-encodes the info dict, so the infohash is
+// unchanged.
+func rewriteTorrentTrackers(data []byte, rewrites []TrackerRewrite) ([]byte, error) {
+	if len(rewrites) == 0 {
+		return data, nil
+	}
+
+	var root map[string]bencode.Bytes
+	if err := bencode.Unmarshal(data, &root); err != nil {
+		return data, fmt.Errorf("decode torrent: %w", err)
+	}
+
+	changed := false
+	if announce, ok := bencodeText(decodeRawValue(root["announce"])); ok {
+		if rewritten := rewriteTrackerURL(announce, rewrites); rewritten != announce {
+			raw, err := bencode.Marshal(rewritten)
+			if err != nil {
+				return data, fmt.Errorf("encode announce: %w", err)
+			}
+			root["announce"] = raw
+			changed = true
+		}
+	}
+
+	if tiers, ok := decodeRawValue(root["announce-list"]).([]any); ok {
+		list := make([]any, 0, len(tiers))
+		listChanged := false
+		for _, tier := range tiers {
+			entries, ok := tier.([]any)
+			if !ok {
+				list = append(list, tier)
+				continue
+			}
+			rewrittenTier := make([]any, 0, len(entries))
+			for _, entry := range entries {
+				url, ok := bencodeText(entry)
+				if !ok {
+					rewrittenTier = append(rewrittenTier, entry)
+					continue
+				}
+				rewritten := rewriteTrackerURL(url, rewrites)
+				listChanged = listChanged || rewritten != url
+				rewrittenTier = append(rewrittenTier, rewritten)
+			}
+			list = append(list, rewrittenTier)
+		}
+		if listChanged {
+			raw, err := bencode.Marshal(list)
+			if err != nil {
+				return data, fmt.Errorf("encode announce-list: %w", err)
+			}
+			root["announce-list"] = raw
+			changed = true
+		}
+	}
+
+	if !changed {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	if err := bencode.NewEncoder(&buf).Encode(root); err != nil {
+		return data, fmt.Errorf("encode torrent: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func bencodeText(v any) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case []byte:
+		return string(val), true
+	default:
+		return "", false
+	}
+}
+
 // decodeRawValue returns the decoded form of one raw top-level value, or nil
 // when the key is absent or its value does not decode.
 func decodeRawValue(raw bencode.Bytes) any {
@@ -107,6 +107,51 @@ func TestPatchTorrentTrackersKeepsOversizedIntegers(t *testing.T) {
 	}
 }
 
+func TestRewriteTorrentTrackers(t *testing.T) {
+	infoRaw := "d6:pieces0:4:name1:n12:piece lengthi016ee"
+	data := []byte("d8:announce30:https://old.example/OLDKEY/ann13:announce-listll30:https://old.example/OLDKEY/ann21:udp://public.example/eel22:https://other.example/ee4:info" + infoRaw + "e")
+
+	rewritten, err := rewriteTorrentTrackers(data, []TrackerRewrite{
+		{From: "old.example", To: "new.example"},
+		{From: "OLDKEY", To: "NEWKEY"},
+	})
+	if err != nil {
+		t.Fatalf("rewriteTorrentTrackers returned error: %v", err)
+	}
+
+	var root map[string]bencode.Bytes
+	if err := bencode.Unmarshal(rewritten, &root); err != nil {
+		t.Fatalf("decode rewritten torrent: %v", err)
+	}
+	if got := string(root["info"]); got != infoRaw {
+		t.Fatalf("info bytes changed: got %q want %q", got, infoRaw)
+	}
+	if got := bencodeString(decodeRawValue(root["announce"])); got != "https://new.example/NEWKEY/ann" {
+		t.Fatalf("announce mismatch: got %q", got)
+	}
+
+	var tiers [][]string
+	for _, tier := range decodeRawValue(root["announce-list"]).([]any) {
+		var urls []string
+		for _, entry := range tier.([]any) {
+			urls = append(urls, bencodeString(entry))
+		}
+		tiers = append(tiers, urls)
+	}
+	want := [][]string{{"https://new.example/NEWKEY/ann", "udp://public.example/"}, {"https://other.example/"}}
+	if !reflect.DeepEqual(tiers, want) {
+		t.Fatalf("announce-list mismatch: got %v want %v", tiers, want)
+	}
+
+	unchanged, err := rewriteTorrentTrackers(data, []TrackerRewrite{{From: "absent", To: "x"}})
+	if err != nil {
+		t.Fatalf("rewriteTorrentTrackers returned error: %v", err)
+	}
+	if !bytes.Equal(unchanged, data) {
+		t.Fatalf("torrent without matching trackers must be returned unchanged")
+	}
+}
+
 func loadTorrentFixture(t *testing.T) []byte {
 	t.Helper()
 	fixturePath := filepath.Join("testdata", "qbittorrent_4_6.torrent")
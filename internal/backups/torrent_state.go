@@ -0,0 +1,510 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/autobrr/go-torrent/bencode"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+// ManifestVersion is the manifest schema written by this release. Version 2
+// added per-torrent state; older manifests restore without it.
+const ManifestVersion = 2
+
+var (
+	// ErrUnsupportedManifest is returned for manifests written by a newer release.
+	ErrUnsupportedManifest = errors.New("unsupported manifest version")
+	// ErrUnknownStateField is returned for restore state toggles that do not exist.
+	ErrUnknownStateField = errors.New("unknown torrent state field")
+)
+
+// StateField names a group of captured torrent state that a restore can
+// re-apply or leave alone.
+type StateField string
+
+const (
+	StateShareLimits   StateField = "shareLimits"
+	StateSpeedLimits   StateField = "speedLimits"
+	StateTrackers      StateField = "trackers"
+	StateFiles         StateField = "files"
+	StateDownloadOrder StateField = "downloadOrder"
+)
+
+var allStateFields = []StateField{StateShareLimits, StateSpeedLimits, StateTrackers, StateFiles, StateDownloadOrder}
+
+// stateChangeFields maps the plan's diff fields to the toggle controlling them.
+var stateChangeFields = map[string]StateField{
+	"shareLimits":        StateShareLimits,
+	"speedLimits":        StateSpeedLimits,
+	"trackers":           StateTrackers,
+	"sequentialDownload": StateDownloadOrder,
+	"firstLastPiecePrio": StateDownloadOrder,
+}
+
+const (
+	filesReadyAttempts = 10
+	filesReadyInterval = 500 * time.Millisecond
+)
+
+func validateStateFields(fields []StateField) error {
+	for _, field := range fields {
+		if !slices.Contains(allStateFields, field) {
+			return fmt.Errorf("%w: %q", ErrUnknownStateField, field)
+		}
+	}
+	return nil
+}
+
+// resolveStateFields returns the fields a restore re-applies, in a stable
+// order. A nil slice selects every field and an empty one none.
+func resolveStateFields(fields []StateField) []StateField {
+	if fields == nil {
+		return slices.Clone(allStateFields)
+	}
+	resolved := make([]StateField, 0, len(fields))
+	for _, field := range allStateFields {
+		if slices.Contains(fields, field) {
+			resolved = append(resolved, field)
+		}
+	}
+	return resolved
+}
+
+func checkManifestVersion(manifest *Manifest) error {
+	if manifest.Version > ManifestVersion {
+		return fmt.Errorf("%w: version %d is newer than the supported version %d", ErrUnsupportedManifest, manifest.Version, ManifestVersion)
+	}
+	return nil
+}
+
+// torrentFileNames returns the paths of the files in a .torrent the way
+// qBittorrent names them, skipping BEP 47 padding files. It returns nil for
+// v2-only torrents, which have no v1 file list.
+func torrentFileNames(data []byte) ([]string, error) {
+	var meta struct {
+		Info struct {
+			Name     string `bencode:"name"`
+			NameUTF8 string `bencode:"name.utf-8"`
+			Length   int64  `bencode:"length"`
+			Files    []struct {
+				Path     []string `bencode:"path"`
+				PathUTF8 []string `bencode:"path.utf-8"`
+				Attr     string   `bencode:"attr"`
+			} `bencode:"files"`
+		} `bencode:"info"`
+	}
+	if err := bencode.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("decode torrent: %w", err)
+	}
+
+	name := meta.Info.Name
+	if meta.Info.NameUTF8 != "" {
+		name = meta.Info.NameUTF8
+	}
+	if len(meta.Info.Files) == 0 {
+		if meta.Info.Length == 0 {
+			return nil, nil
+		}
+		return []string{name}, nil
+	}
+
+	names := make([]string, 0, len(meta.Info.Files))
+	for _, file := range meta.Info.Files {
+		if strings.Contains(file.Attr, "p") {
+			continue
+		}
+		path := file.Path
+		if len(file.PathUTF8) > 0 {
+			path = file.PathUTF8
+		}
+		names = append(names, name+"/"+strings.Join(path, "/"))
+	}
+	return names, nil
+}
+
+// captureTorrentState records the state of a torrent that its .torrent file
+// does not carry. Files are only recorded when their priority is not the
+// default or qBittorrent names them differently from the .torrent, so
+// untouched torrents keep a small manifest entry.
+func captureTorrentState(torrent qbt.Torrent, originalNames []string, files qbt.TorrentFiles) *models.BackupTorrentState {
+	state := &models.BackupTorrentState{
+		RatioLimit:               torrent.RatioLimit,
+		SeedingTimeLimit:         torrent.SeedingTimeLimit,
+		InactiveSeedingTimeLimit: torrent.InactiveSeedingTimeLimit,
+		ShareLimitAction:         strings.TrimSpace(torrent.ShareLimitAction),
+		UploadLimit:              torrent.UpLimit,
+		DownloadLimit:            torrent.DlLimit,
+		SequentialDownload:       torrent.SequentialDownload,
+		FirstLastPiecePrio:       torrent.FirstLastPiecePrio,
+		Trackers:                 stateTrackerURLs(torrent.Trackers),
+		AddedOn:                  torrent.AddedOn,
+	}
+
+	// Indices only line up with the .torrent when the file counts agree.
+	compareNames := len(originalNames) == len(files)
+	for _, file := range files {
+		entry := models.BackupFileState{Index: file.Index, Priority: file.Priority}
+		if compareNames && file.Index >= 0 && file.Index < len(originalNames) && file.Name != originalNames[file.Index] {
+			entry.Name = file.Name
+		}
+		if entry.Priority == 1 && entry.Name == "" {
+			continue
+		}
+		state.Files = append(state.Files, entry)
+	}
+
+	return state
+}
+
+// stateTrackerURLs returns the announce URLs of a torrent without the DHT,
+// PeX and LSD pseudo-trackers.
+func stateTrackerURLs(trackers []qbt.TorrentTracker) []string {
+	var urls []string
+	for _, tracker := range trackers {
+		url := strings.TrimSpace(tracker.Url)
+		if url == "" || strings.HasPrefix(url, "** [") || slices.Contains(urls, url) {
+			continue
+		}
+		urls = append(urls, url)
+	}
+	return urls
+}
+
+// normalizeShareLimit maps a share limit to -2 (use the global limit) unless
+// it sets a limit or explicitly disables one.
+func normalizeShareLimit[T int64 | float64](limit T) T {
+	if limit > 0 || limit == -1 {
+		return limit
+	}
+	return -2
+}
+
+// normalizeShareLimitAction maps an unset action to "Default", which is what
+// qBittorrent reports for torrents that follow the global action.
+func normalizeShareLimitAction(action string) string {
+	if action = strings.TrimSpace(action); action == "" {
+		return "Default"
+	}
+	return action
+}
+
+func shareLimitsOf(state *models.BackupTorrentState) map[string]any {
+	limits := map[string]any{
+		"ratioLimit":               normalizeShareLimit(state.RatioLimit),
+		"seedingTimeLimit":         normalizeShareLimit(state.SeedingTimeLimit),
+		"inactiveSeedingTimeLimit": normalizeShareLimit(state.InactiveSeedingTimeLimit),
+	}
+	if state.ShareLimitAction != "" {
+		limits["shareLimitAction"] = state.ShareLimitAction
+	}
+	return limits
+}
+
+func speedLimitsOf(up, down int64) map[string]int64 {
+	return map[string]int64{"uploadLimit": max(up, 0), "downloadLimit": max(down, 0)}
+}
+
+// computeStateChanges diffs the captured state of a torrent against the live
+// torrent. File priorities and names are only restored onto added torrents,
+// so they are not diffed here.
+func computeStateChanges(state *models.BackupTorrentState, live LiveTorrent) []DiffChange {
+	if state == nil {
+		return nil
+	}
+
+	var changes []DiffChange
+
+	desiredRatio, liveRatio := normalizeShareLimit(state.RatioLimit), normalizeShareLimit(live.RatioLimit)
+	if math.Abs(desiredRatio-liveRatio) > 0.001 ||
+		normalizeShareLimit(state.SeedingTimeLimit) != normalizeShareLimit(live.SeedingTimeLimit) ||
+		normalizeShareLimit(state.InactiveSeedingTimeLimit) != normalizeShareLimit(live.InactiveSeedingTimeLimit) ||
+		// Manifests written before the action was captured leave it empty.
+		(state.ShareLimitAction != "" && live.ShareLimitAction != "" &&
+			!strings.EqualFold(normalizeShareLimitAction(state.ShareLimitAction), normalizeShareLimitAction(live.ShareLimitAction))) {
+		changes = append(changes, DiffChange{
+			Field:     "shareLimits",
+			Supported: true,
+			Current: shareLimitsOf(&models.BackupTorrentState{
+				RatioLimit:               live.RatioLimit,
+				SeedingTimeLimit:         live.SeedingTimeLimit,
+				InactiveSeedingTimeLimit: live.InactiveSeedingTimeLimit,
+				ShareLimitAction:         live.ShareLimitAction,
+			}),
+			Desired: shareLimitsOf(state),
+		})
+	}
+
+	if max(state.UploadLimit, 0) != max(live.UploadLimit, 0) || max(state.DownloadLimit, 0) != max(live.DownloadLimit, 0) {
+		changes = append(changes, DiffChange{
+			Field:     "speedLimits",
+			Supported: true,
+			Current:   speedLimitsOf(live.UploadLimit, live.DownloadLimit),
+			Desired:   speedLimitsOf(state.UploadLimit, state.DownloadLimit),
+		})
+	}
+
+	liveTrackers := stateTrackerURLsFromStrings(live.TrackerURLs)
+	if len(state.Trackers) > 0 && len(liveTrackers) > 0 && !stringSetsEqual(state.Trackers, liveTrackers) {
+		changes = append(changes, DiffChange{
+			Field:     "trackers",
+			Supported: true,
+			Current:   liveTrackers,
+			Desired:   cloneStringSlice(state.Trackers),
+		})
+	}
+
+	if state.SequentialDownload != live.SequentialDownload {
+		changes = append(changes, DiffChange{
+			Field:     "sequentialDownload",
+			Supported: true,
+			Current:   live.SequentialDownload,
+			Desired:   state.SequentialDownload,
+		})
+	}
+	if state.FirstLastPiecePrio != live.FirstLastPiecePrio {
+		changes = append(changes, DiffChange{
+			Field:     "firstLastPiecePrio",
+			Supported: false,
+			Current:   live.FirstLastPiecePrio,
+			Desired:   state.FirstLastPiecePrio,
+			Message:   "first and last piece priority cannot be set on an existing torrent; toggle it manually",
+		})
+	}
+
+	return changes
+}
+
+func stateTrackerURLsFromStrings(urls []string) []string {
+	var out []string
+	for _, url := range urls {
+		if url = strings.TrimSpace(url); url != "" && !strings.HasPrefix(url, "** [") {
+			out = append(out, url)
+		}
+	}
+	return out
+}
+
+// applyStateFields drops the state changes of torrent updates whose toggle is
+// off, along with updates left without changes.
+func applyStateFields(plan *RestorePlan, fields []StateField) {
+	plan.StateFields = fields
+
+	updates := plan.Torrents.Update[:0]
+	for _, update := range plan.Torrents.Update {
+		changes := update.Changes[:0]
+		for _, change := range update.Changes {
+			if field, ok := stateChangeFields[change.Field]; ok && !slices.Contains(fields, field) {
+				continue
+			}
+			changes = append(changes, change)
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		update.Changes = changes
+		updates = append(updates, update)
+	}
+	plan.Torrents.Update = updates
+}
+
+// addStateOptions returns the torrents/add options that restore state on an
+// added torrent. Trackers are restored by patching the .torrent and files
+// after the add, since neither has an add option.
+func addStateOptions(state *models.BackupTorrentState, fields []StateField) map[string]string {
+	options := map[string]string{}
+	if state == nil {
+		return options
+	}
+
+	if slices.Contains(fields, StateShareLimits) {
+		if limit := normalizeShareLimit(state.RatioLimit); limit != -2 {
+			options["ratioLimit"] = strconv.FormatFloat(limit, 'f', -1, 64)
+		}
+		if limit := normalizeShareLimit(state.SeedingTimeLimit); limit != -2 {
+			options["seedingTimeLimit"] = strconv.FormatInt(limit, 10)
+		}
+		if limit := normalizeShareLimit(state.InactiveSeedingTimeLimit); limit != -2 {
+			options["inactiveSeedingTimeLimit"] = strconv.FormatInt(limit, 10)
+		}
+		if action := normalizeShareLimitAction(state.ShareLimitAction); action != "Default" {
+			options["shareLimitAction"] = action
+		}
+	}
+	if slices.Contains(fields, StateSpeedLimits) {
+		if state.UploadLimit > 0 {
+			options["upLimit"] = strconv.FormatInt(state.UploadLimit, 10)
+		}
+		if state.DownloadLimit > 0 {
+			options["dlLimit"] = strconv.FormatInt(state.DownloadLimit, 10)
+		}
+	}
+	if slices.Contains(fields, StateDownloadOrder) {
+		if state.SequentialDownload {
+			options["sequentialDownload"] = "true"
+		}
+		if state.FirstLastPiecePrio {
+			options["firstLastPiecePrio"] = "true"
+		}
+	}
+
+	return options
+}
+
+// hasFileState reports whether restoring spec touches its files after the add.
+func hasFileState(spec TorrentSpec, fields []StateField) bool {
+	return spec.Manifest.State != nil && len(spec.Manifest.State.Files) > 0 && slices.Contains(fields, StateFiles)
+}
+
+// applyFileState renames and reprioritises the files of a torrent the restore
+// just added. It reports whether any file was renamed, which invalidates the
+// check qBittorrent ran on add.
+func (s *Service) applyFileState(ctx context.Context, instanceID int, hash string, state *models.BackupTorrentState) (bool, error) {
+	if s.stateWriter == nil {
+		return false, errors.New("torrent state unavailable")
+	}
+
+	var files *qbt.TorrentFiles
+	for attempt := 0; ; attempt++ {
+		var err error
+		files, err = s.stateWriter.GetTorrentFiles(qbittorrent.WithForceFilesRefresh(ctx), instanceID, hash)
+		if err != nil {
+			return false, fmt.Errorf("load files: %w", err)
+		}
+		if files != nil && len(*files) > 0 {
+			break
+		}
+		if attempt+1 >= filesReadyAttempts {
+			return false, errors.New("torrent files not available yet")
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(filesReadyInterval):
+		}
+	}
+
+	current := make(map[int]string, len(*files))
+	for _, file := range *files {
+		current[file.Index] = file.Name
+	}
+
+	renamed := false
+	byPriority := make(map[int][]int)
+	for _, file := range state.Files {
+		name, ok := current[file.Index]
+		if !ok {
+			continue
+		}
+		if file.Name != "" && file.Name != name {
+			if err := s.stateWriter.RenameTorrentFile(ctx, instanceID, hash, name, file.Name); err != nil {
+				return renamed, fmt.Errorf("rename %q: %w", name, err)
+			}
+			renamed = true
+		}
+		if file.Priority != 1 {
+			byPriority[file.Priority] = append(byPriority[file.Priority], file.Index)
+		}
+	}
+
+	for priority, indices := range byPriority {
+		if err := s.stateWriter.SetTorrentFilePriority(ctx, instanceID, hash, indices, priority); err != nil {
+			return renamed, fmt.Errorf("set file priority: %w", err)
+		}
+	}
+
+	return renamed, nil
+}
+
+// applyStateChange re-applies one supported state change to an existing
+// torrent.
+func (s *Service) applyStateChange(ctx context.Context, instanceID int, hash string, field string, state *models.BackupTorrentState, live LiveTorrent) error {
+	if s.stateWriter == nil && field != "sequentialDownload" {
+		return errors.New("torrent state unavailable")
+	}
+
+	hashes := []string{hash}
+	switch field {
+	case "shareLimits":
+		return s.stateWriter.SetTorrentShareLimit(ctx, instanceID, hashes,
+			normalizeShareLimit(state.RatioLimit),
+			normalizeShareLimit(state.SeedingTimeLimit),
+			normalizeShareLimit(state.InactiveSeedingTimeLimit), state.ShareLimitAction, "")
+	case "speedLimits":
+		if err := s.stateWriter.SetTorrentUploadLimit(ctx, instanceID, hashes, bytesToKiB(state.UploadLimit)); err != nil {
+			return err
+		}
+		return s.stateWriter.SetTorrentDownloadLimit(ctx, instanceID, hashes, bytesToKiB(state.DownloadLimit))
+	case "trackers":
+		liveTrackers := stateTrackerURLsFromStrings(live.TrackerURLs)
+		var add, remove []string
+		for _, url := range state.Trackers {
+			if !slices.Contains(liveTrackers, url) {
+				add = append(add, url)
+			}
+		}
+		for _, url := range liveTrackers {
+			if !slices.Contains(state.Trackers, url) {
+				remove = append(remove, url)
+			}
+		}
+		// Add first so the torrent is never left without a tracker.
+		if len(add) > 0 {
+			if err := s.stateWriter.AddTorrentTrackers(ctx, instanceID, hash, strings.Join(add, "\n")); err != nil {
+				return err
+			}
+		}
+		if len(remove) > 0 {
+			return s.stateWriter.RemoveTorrentTrackers(ctx, instanceID, hash, strings.Join(remove, "\n"))
+		}
+		return nil
+	case "sequentialDownload":
+		return s.torrentWriter.BulkAction(ctx, instanceID, hashes, "toggleSequentialDownload")
+	default:
+		return fmt.Errorf("unsupported state field %q", field)
+	}
+}
+
+// bytesToKiB converts a bytes/s limit to the KiB/s the limit setters take,
+// rounding up so small limits are not lost. Zero and below mean unlimited.
+func bytesToKiB(limit int64) int64 {
+	if limit <= 0 {
+		return 0
+	}
+	return (limit + 1023) / 1024
+}
+
+// resumeRestored starts torrents that were held stopped while their files
+// were restored, rechecking the renamed ones first.
+func (s *Service) resumeRestored(ctx context.Context, instanceID int, recheck, resume []string, errs *[]RestoreError) {
+	ctx = qbittorrent.WithPostAddBulkActionRetry(ctx)
+	if len(recheck) > 0 {
+		if err := s.torrentWriter.BulkAction(ctx, instanceID, recheck, "recheck"); err != nil {
+			log.Warn().Err(err).Int("instanceID", instanceID).Strs("hashes", recheck).Msg("Restore: recheck after file renames failed")
+			for _, hash := range recheck {
+				appendRestoreError(errs, "recheck_torrent", hash, err)
+			}
+		}
+	}
+	if len(resume) > 0 {
+		if err := s.torrentWriter.BulkAction(ctx, instanceID, resume, "resume"); err != nil {
+			log.Warn().Err(err).Int("instanceID", instanceID).Strs("hashes", resume).Msg("Restore: resume after file state failed")
+			for _, hash := range resume {
+				appendRestoreError(errs, "resume_torrent", hash, err)
+			}
+		}
+	}
+}
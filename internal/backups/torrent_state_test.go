@@ -0,0 +1,134 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backups
+
+import (
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestCaptureTorrentState(t *testing.T) {
+	t.Parallel()
+
+	torrent := qbt.Torrent{
+		RatioLimit:         2.5,
+		SeedingTimeLimit:   -2,
+		UpLimit:            51200,
+		SequentialDownload: true,
+		AddedOn:            1700000000,
+		Trackers: []qbt.TorrentTracker{
+			{Url: "** [DHT] **"},
+			{Url: "https://tracker.example/announce"},
+			{Url: " https://tracker.example/announce "},
+			{Url: "udp://backup.example:1337"},
+		},
+	}
+	original := []string{"Show/e01.mkv", "Show/e02.mkv", "Show/sample.mkv"}
+	files := qbt.TorrentFiles{
+		{Index: 0, Name: "Show/e01.mkv", Priority: 1},
+		{Index: 1, Name: "Show/e02.mkv", Priority: 0},
+		{Index: 2, Name: "Show/extras/sample.mkv", Priority: 1},
+	}
+
+	state := captureTorrentState(torrent, original, files)
+	require.Equal(t, 2.5, state.RatioLimit)
+	require.Equal(t, int64(-2), state.SeedingTimeLimit)
+	require.Equal(t, int64(51200), state.UploadLimit)
+	require.True(t, state.SequentialDownload)
+	require.Equal(t, int64(1700000000), state.AddedOn)
+	require.Equal(t, []string{"https://tracker.example/announce", "udp://backup.example:1337"}, state.Trackers)
+	require.Equal(t, []models.BackupFileState{
+		{Index: 1, Priority: 0},
+		{Index: 2, Name: "Show/extras/sample.mkv", Priority: 1},
+	}, state.Files)
+
+	// Without the .torrent's file list, renames cannot be told apart.
+	state = captureTorrentState(torrent, nil, files)
+	require.Equal(t, []models.BackupFileState{{Index: 1, Priority: 0}}, state.Files)
+}
+
+func TestResolveStateFields(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, allStateFields, resolveStateFields(nil))
+	require.Empty(t, resolveStateFields([]StateField{}))
+	require.Equal(t, []StateField{StateShareLimits, StateTrackers}, resolveStateFields([]StateField{StateTrackers, StateShareLimits, StateTrackers}))
+
+	require.NoError(t, validateStateFields([]StateField{StateFiles}))
+	require.ErrorIs(t, validateStateFields([]StateField{"addedOn"}), ErrUnknownStateField)
+}
+
+func TestAddStateOptions(t *testing.T) {
+	t.Parallel()
+
+	state := &models.BackupTorrentState{
+		RatioLimit:               -1,
+		SeedingTimeLimit:         1440,
+		InactiveSeedingTimeLimit: -2,
+		DownloadLimit:            1048576,
+		FirstLastPiecePrio:       true,
+	}
+
+	require.Equal(t, map[string]string{
+		"ratioLimit":         "-1",
+		"seedingTimeLimit":   "1440",
+		"dlLimit":            "1048576",
+		"firstLastPiecePrio": "true",
+	}, addStateOptions(state, allStateFields))
+	require.Equal(t, map[string]string{"dlLimit": "1048576"}, addStateOptions(state, []StateField{StateSpeedLimits}))
+	require.Empty(t, addStateOptions(state, []StateField{}))
+	require.Empty(t, addStateOptions(nil, allStateFields))
+
+	state.ShareLimitAction = "Remove"
+	require.Equal(t, "Remove", addStateOptions(state, []StateField{StateShareLimits})["shareLimitAction"])
+}
+
+func TestComputeStateChangesAndToggles(t *testing.T) {
+	t.Parallel()
+
+	state := &models.BackupTorrentState{
+		RatioLimit:  2,
+		UploadLimit: 1024,
+		Trackers:    []string{"https://a.example/announce"},
+	}
+	live := LiveTorrent{
+		RatioLimit:       -2,
+		SeedingTimeLimit: 0,
+		TrackerURLs:      []string{"** [DHT] **", "https://b.example/announce"},
+	}
+
+	changes := computeStateChanges(state, live)
+	fields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	require.Equal(t, []string{"shareLimits", "speedLimits", "trackers"}, fields)
+
+	// Unset limits read back as zero or -2 and are the same thing.
+	require.Empty(t, computeStateChanges(&models.BackupTorrentState{SeedingTimeLimit: -2}, LiveTorrent{}))
+
+	// The share limit action is diffed once both sides report one.
+	actionChanges := computeStateChanges(&models.BackupTorrentState{ShareLimitAction: "Remove"}, LiveTorrent{ShareLimitAction: "Stop"})
+	require.Len(t, actionChanges, 1)
+	require.Equal(t, "Remove", actionChanges[0].Desired.(map[string]any)["shareLimitAction"])
+	require.Empty(t, computeStateChanges(&models.BackupTorrentState{ShareLimitAction: "Default"}, LiveTorrent{ShareLimitAction: "default"}))
+	require.Empty(t, computeStateChanges(&models.BackupTorrentState{}, LiveTorrent{ShareLimitAction: "Stop"}))
+
+	plan := &RestorePlan{Torrents: TorrentPlan{Update: []TorrentUpdate{
+		{Hash: "aaa", Changes: append([]DiffChange{{Field: "category", Supported: true}}, changes...)},
+		{Hash: "bbb", Changes: []DiffChange{{Field: "speedLimits", Supported: true}}},
+	}}}
+	applyStateFields(plan, []StateField{StateTrackers})
+
+	require.Equal(t, []StateField{StateTrackers}, plan.StateFields)
+	require.Len(t, plan.Torrents.Update, 1)
+	require.Equal(t, "aaa", plan.Torrents.Update[0].Hash)
+	require.Len(t, plan.Torrents.Update[0].Changes, 2)
+	require.Equal(t, "category", plan.Torrents.Update[0].Changes[0].Field)
+	require.Equal(t, "trackers", plan.Torrents.Update[0].Changes[1].Field)
+}
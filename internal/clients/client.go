@@ -0,0 +1,151 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package clients talks to the torrent clients qui manages besides
+// qBittorrent. Each implementation covers the subset of operations qui needs
+// and speaks go-qbittorrent's types, so callers can treat every instance's
+// torrents, files and trackers the same way.
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// ErrUnsupported is returned for operations a client type cannot perform.
+var ErrUnsupported = errors.New("operation not supported by this client")
+
+// defaultTimeout bounds every RPC call made by a client.
+const defaultTimeout = 30 * time.Second
+
+// qBittorrent conventions the implementations translate to.
+const (
+	// globalLimit applies the client's global share limit.
+	globalLimit = -2
+	// unlimitedLimit removes a share limit.
+	unlimitedLimit = -1
+	// infiniteETA is qBittorrent's ETA for torrents that will not finish.
+	infiniteETA = 8640000
+
+	qbtFilePrioritySkip   = 0
+	qbtFilePriorityNormal = 1
+	qbtFilePriorityHigh   = 6
+	qbtFilePriorityMax    = 7
+)
+
+// Capabilities describes which optional operations a client supports.
+type Capabilities struct {
+	Categories               bool
+	Tags                     bool
+	SetLocation              bool
+	RatioLimit               bool
+	SeedingTimeLimit         bool
+	InactiveSeedingTimeLimit bool
+}
+
+// AddRequest describes torrents to add. Files are .torrent contents; URLs may
+// be magnet links or http(s) links to .torrent files.
+type AddRequest struct {
+	Files    [][]byte
+	URLs     []string
+	SavePath string
+	Category string
+	Tags     []string
+	Paused   bool
+}
+
+// ShareLimits uses qBittorrent's conventions: -2 applies the client's global
+// limit, -1 removes the limit. Time limits are in minutes.
+type ShareLimits struct {
+	RatioLimit               float64
+	SeedingTimeLimit         int64
+	InactiveSeedingTimeLimit int64
+}
+
+// Client is a non-qBittorrent torrent client.
+type Client interface {
+	Type() models.ClientType
+	Capabilities() Capabilities
+	// Version returns the client's version and doubles as a connection check.
+	Version(ctx context.Context) (string, error)
+	Torrents(ctx context.Context) ([]qbt.Torrent, error)
+	Files(ctx context.Context, hash string) (qbt.TorrentFiles, error)
+	Trackers(ctx context.Context, hash string) ([]qbt.TorrentTracker, error)
+	// TorrentFilePath returns where the client keeps the torrent's .torrent
+	// file. The path is on the client's host; neither client serves the file
+	// itself over RPC.
+	TorrentFilePath(ctx context.Context, hash string) (string, error)
+	Add(ctx context.Context, req AddRequest) error
+	Delete(ctx context.Context, hashes []string, deleteFiles bool) error
+	Start(ctx context.Context, hashes []string) error
+	Stop(ctx context.Context, hashes []string) error
+	SetCategory(ctx context.Context, hashes []string, category string) error
+	// SetTags replaces the tags of the torrent.
+	SetTags(ctx context.Context, hash string, tags []string) error
+	SetLocation(ctx context.Context, hashes []string, location string) error
+	SetShareLimits(ctx context.Context, hashes []string, limits ShareLimits) error
+}
+
+// Config holds the connection settings of an instance.
+type Config struct {
+	Host          string
+	Username      string
+	Password      string
+	BasicUsername *string
+	BasicPassword *string
+	TLSSkipVerify bool
+}
+
+// New returns the client implementation for clientType.
+func New(clientType models.ClientType, cfg Config) (Client, error) {
+	switch clientType {
+	case models.ClientTypeTransmission:
+		return NewTransmission(cfg), nil
+	case models.ClientTypeDeluge:
+		return NewDeluge(cfg)
+	default:
+		return nil, fmt.Errorf("no client implementation for %q", clientType)
+	}
+}
+
+func newHTTPClient(cfg Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLSSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // opt-in per instance
+	}
+	return &http.Client{Timeout: defaultTimeout, Transport: transport}
+}
+
+// decodeJSONResponse decodes a successful response body into out.
+func decodeJSONResponse(resp *http.Response, out any) error {
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("authentication failed (status %d)", resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// applyBasicAuth sets the reverse proxy credentials of cfg, if any.
+func applyBasicAuth(req *http.Request, cfg Config) {
+	if cfg.BasicUsername != nil && *cfg.BasicUsername != "" {
+		password := ""
+		if cfg.BasicPassword != nil {
+			password = *cfg.BasicPassword
+		}
+		req.SetBasicAuth(*cfg.BasicUsername, password)
+	}
+}
@@ -0,0 +1,578 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// delugeNotAuthenticated is the Web UI error code for an expired session.
+const delugeNotAuthenticated = 1
+
+// delugeNoLabel clears a torrent's label in the Label plugin.
+const delugeNoLabel = "No Label"
+
+// delugeLabelPattern is the Label plugin's rule for label names.
+var delugeLabelPattern = regexp.MustCompile(`^[a-z0-9_\-.]+$`)
+
+var delugeTorrentKeys = []string{
+	"hash", "name", "state", "message", "progress", "total_size", "total_wanted",
+	"total_done", "download_payload_rate", "upload_payload_rate", "all_time_download",
+	"total_uploaded", "ratio", "eta", "time_added", "completed_time", "active_time",
+	"seeding_time", "download_location", "label", "tracker", "tracker_status",
+	"num_seeds", "num_peers", "private", "comment", "stop_at_ratio", "stop_ratio",
+	"is_finished",
+}
+
+var errDelugeNotAuthenticated = errors.New("deluge: not authenticated")
+
+// Deluge talks to Deluge 2's Web UI JSON-RPC interface. The Web UI proxies to
+// a daemon; when it is not connected to one, the first configured host is used.
+type Deluge struct {
+	cfg      Config
+	endpoint string
+	http     *http.Client
+	nextID   atomic.Int64
+
+	mu        sync.Mutex
+	connected bool
+}
+
+// NewDeluge returns a Deluge client. A host without a /json path gets the Web
+// UI's default /json endpoint.
+func NewDeluge(cfg Config) (*Deluge, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := newHTTPClient(cfg)
+	httpClient.Jar = jar
+
+	endpoint := strings.TrimRight(cfg.Host, "/")
+	if !strings.HasSuffix(endpoint, "/json") {
+		endpoint += "/json"
+	}
+	return &Deluge{
+		cfg:      cfg,
+		endpoint: endpoint,
+		http:     httpClient,
+	}, nil
+}
+
+func (d *Deluge) Type() models.ClientType {
+	return models.ClientTypeDeluge
+}
+
+func (d *Deluge) Capabilities() Capabilities {
+	return Capabilities{
+		Categories:  true,
+		SetLocation: true,
+		RatioLimit:  true,
+	}
+}
+
+type delugeRequest struct {
+	Method string `json:"method"`
+	Params []any  `json:"params"`
+	ID     int64  `json:"id"`
+}
+
+type delugeResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error"`
+}
+
+// rawCall runs a method without logging in first.
+func (d *Deluge) rawCall(ctx context.Context, method string, params []any, out any) error {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(delugeRequest{Method: method, Params: params, ID: d.nextID.Add(1)})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	applyBasicAuth(req, d.cfg)
+
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("deluge %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded delugeResponse
+	if err := decodeJSONResponse(resp, &decoded); err != nil {
+		return fmt.Errorf("deluge %s: %w", method, err)
+	}
+	if decoded.Error != nil {
+		if decoded.Error.Code == delugeNotAuthenticated {
+			return errDelugeNotAuthenticated
+		}
+		return fmt.Errorf("deluge %s: %s", method, decoded.Error.Message)
+	}
+	if out != nil && len(decoded.Result) > 0 {
+		if err := json.Unmarshal(decoded.Result, out); err != nil {
+			return fmt.Errorf("deluge %s: decode result: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// call runs a method, logging in and connecting to a daemon as needed.
+func (d *Deluge) call(ctx context.Context, method string, params []any, out any) error {
+	if err := d.connect(ctx); err != nil {
+		return err
+	}
+	err := d.rawCall(ctx, method, params, out)
+	if !errors.Is(err, errDelugeNotAuthenticated) {
+		return err
+	}
+
+	// The session expired; log in again once.
+	d.mu.Lock()
+	d.connected = false
+	d.mu.Unlock()
+	if err := d.connect(ctx); err != nil {
+		return err
+	}
+	return d.rawCall(ctx, method, params, out)
+}
+
+func (d *Deluge) connect(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.connected {
+		return nil
+	}
+
+	var ok bool
+	if err := d.rawCall(ctx, "auth.login", []any{d.cfg.Password}, &ok); err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("deluge: authentication failed")
+	}
+
+	var daemonConnected bool
+	if err := d.rawCall(ctx, "web.connected", nil, &daemonConnected); err != nil {
+		return err
+	}
+	if !daemonConnected {
+		var hosts [][]any
+		if err := d.rawCall(ctx, "web.get_hosts", nil, &hosts); err != nil {
+			return err
+		}
+		if len(hosts) == 0 || len(hosts[0]) == 0 {
+			return errors.New("deluge: web UI has no daemon configured")
+		}
+		if err := d.rawCall(ctx, "web.connect", []any{hosts[0][0]}, nil); err != nil {
+			return err
+		}
+	}
+
+	d.connected = true
+	return nil
+}
+
+func (d *Deluge) Version(ctx context.Context) (string, error) {
+	var version string
+	if err := d.call(ctx, "daemon.info", nil, &version); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// delugeTorrent holds the status keys qui reads. Deluge reports several
+// integer values as floats, so numbers are decoded as float64.
+type delugeTorrent struct {
+	Hash                string  `json:"hash"`
+	Name                string  `json:"name"`
+	State               string  `json:"state"`
+	Message             string  `json:"message"`
+	Progress            float64 `json:"progress"`
+	TotalSize           float64 `json:"total_size"`
+	TotalWanted         float64 `json:"total_wanted"`
+	TotalDone           float64 `json:"total_done"`
+	DownloadPayloadRate float64 `json:"download_payload_rate"`
+	UploadPayloadRate   float64 `json:"upload_payload_rate"`
+	AllTimeDownload     float64 `json:"all_time_download"`
+	TotalUploaded       float64 `json:"total_uploaded"`
+	Ratio               float64 `json:"ratio"`
+	ETA                 float64 `json:"eta"`
+	TimeAdded           float64 `json:"time_added"`
+	CompletedTime       float64 `json:"completed_time"`
+	ActiveTime          float64 `json:"active_time"`
+	SeedingTime         float64 `json:"seeding_time"`
+	DownloadLocation    string  `json:"download_location"`
+	Label               string  `json:"label"`
+	Tracker             string  `json:"tracker"`
+	TrackerStatus       string  `json:"tracker_status"`
+	NumSeeds            float64 `json:"num_seeds"`
+	NumPeers            float64 `json:"num_peers"`
+	Private             bool    `json:"private"`
+	Comment             string  `json:"comment"`
+	StopAtRatio         bool    `json:"stop_at_ratio"`
+	StopRatio           float64 `json:"stop_ratio"`
+	IsFinished          bool    `json:"is_finished"`
+}
+
+func (d *Deluge) Torrents(ctx context.Context) ([]qbt.Torrent, error) {
+	var result map[string]delugeTorrent
+	if err := d.call(ctx, "core.get_torrents_status", []any{map[string]any{}, delugeTorrentKeys}, &result); err != nil {
+		return nil, err
+	}
+
+	torrents := make([]qbt.Torrent, 0, len(result))
+	for hash, torrent := range result {
+		if torrent.Hash == "" {
+			torrent.Hash = hash
+		}
+		torrents = append(torrents, torrent.toQBT())
+	}
+	return torrents, nil
+}
+
+func (dt delugeTorrent) toQBT() qbt.Torrent {
+	eta := int64(dt.ETA)
+	if eta <= 0 && dt.Progress < 100 {
+		eta = infiniteETA
+	}
+
+	ratioLimit := float64(unlimitedLimit)
+	if dt.StopAtRatio {
+		ratioLimit = dt.StopRatio
+	}
+
+	torrent := qbt.Torrent{
+		Hash:                     dt.Hash,
+		InfohashV1:               dt.Hash,
+		Name:                     dt.Name,
+		State:                    dt.state(),
+		Progress:                 dt.Progress / 100,
+		Size:                     int64(dt.TotalWanted),
+		TotalSize:                int64(dt.TotalSize),
+		Completed:                int64(dt.TotalDone),
+		AmountLeft:               max(int64(dt.TotalWanted-dt.TotalDone), 0),
+		DlSpeed:                  int64(dt.DownloadPayloadRate),
+		UpSpeed:                  int64(dt.UploadPayloadRate),
+		Downloaded:               int64(dt.AllTimeDownload),
+		Uploaded:                 int64(dt.TotalUploaded),
+		Ratio:                    max(dt.Ratio, 0),
+		ETA:                      eta,
+		AddedOn:                  int64(dt.TimeAdded),
+		CompletionOn:             int64(dt.CompletedTime),
+		TimeActive:               int64(dt.ActiveTime),
+		SeedingTime:              int64(dt.SeedingTime),
+		SavePath:                 dt.DownloadLocation,
+		ContentPath:              path.Join(dt.DownloadLocation, dt.Name),
+		Category:                 dt.Label,
+		Private:                  dt.Private,
+		Comment:                  dt.Comment,
+		NumSeeds:                 int64(dt.NumSeeds),
+		NumLeechs:                int64(dt.NumPeers),
+		RatioLimit:               ratioLimit,
+		SeedingTimeLimit:         globalLimit,
+		InactiveSeedingTimeLimit: globalLimit,
+		Tracker:                  dt.Tracker,
+	}
+	if dt.Tracker != "" {
+		torrent.TrackersCount = 1
+		torrent.Trackers = []qbt.TorrentTracker{delugeTracker(dt.Tracker, dt.Tracker, dt.TrackerStatus)}
+	}
+	return torrent
+}
+
+func (dt delugeTorrent) state() qbt.TorrentState {
+	done := dt.IsFinished || dt.Progress >= 100
+	switch dt.State {
+	case "Downloading":
+		if dt.DownloadPayloadRate > 0 {
+			return qbt.TorrentStateDownloading
+		}
+		return qbt.TorrentStateStalledDl
+	case "Seeding":
+		if dt.UploadPayloadRate > 0 {
+			return qbt.TorrentStateUploading
+		}
+		return qbt.TorrentStateStalledUp
+	case "Paused":
+		if done {
+			return qbt.TorrentStateStoppedUp
+		}
+		return qbt.TorrentStateStoppedDl
+	case "Checking":
+		if done {
+			return qbt.TorrentStateCheckingUp
+		}
+		return qbt.TorrentStateCheckingDl
+	case "Queued":
+		if done {
+			return qbt.TorrentStateQueuedUp
+		}
+		return qbt.TorrentStateQueuedDl
+	case "Error":
+		return qbt.TorrentStateError
+	case "Moving":
+		return qbt.TorrentStateMoving
+	case "Allocating":
+		return qbt.TorrentStateAllocating
+	default:
+		return qbt.TorrentStateUnknown
+	}
+}
+
+// delugeTracker builds a tracker entry. Deluge only reports the status of the
+// tracker it currently announces to.
+func delugeTracker(url, current, status string) qbt.TorrentTracker {
+	tracker := qbt.TorrentTracker{Url: url, Status: qbt.TrackerStatusNotContacted}
+	if url != current || status == "" {
+		return tracker
+	}
+	switch {
+	case strings.Contains(status, "Error"):
+		tracker.Status = qbt.TrackerStatusNotWorking
+		_, message, _ := strings.Cut(status, ": ")
+		tracker.Message = message
+	case strings.Contains(status, "Announce OK"):
+		tracker.Status = qbt.TrackerStatusOK
+	case strings.Contains(status, "Announce Sent"):
+		tracker.Status = qbt.TrackerStatusUpdating
+	case strings.Contains(status, "Warning"):
+		tracker.Status = qbt.TrackerStatusOK
+		_, message, _ := strings.Cut(status, ": ")
+		tracker.Message = message
+	}
+	return tracker
+}
+
+func (d *Deluge) torrentStatus(ctx context.Context, hash string, keys []string, out any) error {
+	var raw json.RawMessage
+	if err := d.call(ctx, "core.get_torrent_status", []any{hash, keys}, &raw); err != nil {
+		return err
+	}
+	// Unknown torrents return an empty status.
+	if len(raw) == 0 || string(raw) == "{}" || string(raw) == "null" {
+		return fmt.Errorf("deluge: torrent %s not found", hash)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("deluge: decode torrent status: %w", err)
+	}
+	return nil
+}
+
+func (d *Deluge) Files(ctx context.Context, hash string) (qbt.TorrentFiles, error) {
+	var status struct {
+		Files []struct {
+			Index int     `json:"index"`
+			Path  string  `json:"path"`
+			Size  float64 `json:"size"`
+		} `json:"files"`
+		FileProgress   []float64 `json:"file_progress"`
+		FilePriorities []int     `json:"file_priorities"`
+	}
+	if err := d.torrentStatus(ctx, hash, []string{"files", "file_progress", "file_priorities"}, &status); err != nil {
+		return nil, err
+	}
+
+	files := make(qbt.TorrentFiles, len(status.Files))
+	for i, file := range status.Files {
+		files[i].Index = file.Index
+		files[i].Name = file.Path
+		files[i].Size = int64(file.Size)
+		files[i].Priority = qbtFilePriorityNormal
+		if i < len(status.FileProgress) {
+			files[i].Progress = float32(status.FileProgress[i])
+			files[i].IsSeed = status.FileProgress[i] >= 1
+		}
+		if i < len(status.FilePriorities) {
+			files[i].Priority = delugeFilePriority(status.FilePriorities[i])
+		}
+	}
+	return files, nil
+}
+
+// delugeFilePriority maps Deluge's 0 (skip) to 7 (high) file priorities to qBittorrent's.
+func delugeFilePriority(priority int) int {
+	switch {
+	case priority <= 0:
+		return qbtFilePrioritySkip
+	case priority <= 4:
+		return qbtFilePriorityNormal
+	case priority < 7:
+		return qbtFilePriorityHigh
+	default:
+		return qbtFilePriorityMax
+	}
+}
+
+func (d *Deluge) Trackers(ctx context.Context, hash string) ([]qbt.TorrentTracker, error) {
+	var status struct {
+		Trackers []struct {
+			URL string `json:"url"`
+		} `json:"trackers"`
+		Tracker       string `json:"tracker"`
+		TrackerStatus string `json:"tracker_status"`
+	}
+	if err := d.torrentStatus(ctx, hash, []string{"trackers", "tracker", "tracker_status"}, &status); err != nil {
+		return nil, err
+	}
+
+	trackers := make([]qbt.TorrentTracker, 0, len(status.Trackers))
+	for _, tracker := range status.Trackers {
+		trackers = append(trackers, delugeTracker(tracker.URL, status.Tracker, status.TrackerStatus))
+	}
+	return trackers, nil
+}
+
+// TorrentFilePath returns the copy of the .torrent Deluge keeps in the state
+// directory of its config directory. The config directory is not exposed over
+// RPC, so it is derived from the plugins location, which lives next to it.
+func (d *Deluge) TorrentFilePath(ctx context.Context, hash string) (string, error) {
+	var status struct {
+		Hash string `json:"hash"`
+	}
+	if err := d.torrentStatus(ctx, hash, []string{"hash"}, &status); err != nil {
+		return "", err
+	}
+
+	var pluginsLocation string
+	if err := d.call(ctx, "core.get_config_value", []any{"plugins_location"}, &pluginsLocation); err != nil {
+		return "", err
+	}
+	if pluginsLocation == "" {
+		return "", errors.New("deluge: config directory unknown")
+	}
+	return filepath.Join(filepath.Dir(pluginsLocation), "state", strings.ToLower(status.Hash)+".torrent"), nil
+}
+
+func (d *Deluge) Add(ctx context.Context, req AddRequest) error {
+	if len(req.Tags) > 0 {
+		return fmt.Errorf("deluge tags: %w", ErrUnsupported)
+	}
+
+	options := map[string]any{"add_paused": req.Paused}
+	if req.SavePath != "" {
+		options["download_location"] = req.SavePath
+	}
+
+	label := func(hash string) error {
+		if req.Category == "" || hash == "" {
+			return nil
+		}
+		return d.SetCategory(ctx, []string{hash}, req.Category)
+	}
+
+	var errs []error
+	for i, content := range req.Files {
+		var hash string
+		filename := fmt.Sprintf("qui-%d.torrent", i)
+		err := d.call(ctx, "core.add_torrent_file", []any{filename, base64.StdEncoding.EncodeToString(content), options}, &hash)
+		errs = append(errs, err)
+		if err == nil {
+			errs = append(errs, label(hash))
+		}
+	}
+	for _, url := range req.URLs {
+		method := "core.add_torrent_url"
+		if strings.HasPrefix(url, "magnet:") {
+			method = "core.add_torrent_magnet"
+		}
+		var hash string
+		err := d.call(ctx, method, []any{url, options}, &hash)
+		errs = append(errs, err)
+		if err == nil {
+			errs = append(errs, label(hash))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Deluge) Delete(ctx context.Context, hashes []string, deleteFiles bool) error {
+	var errs []error
+	for _, hash := range hashes {
+		errs = append(errs, d.call(ctx, "core.remove_torrent", []any{hash, deleteFiles}, nil))
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Deluge) Start(ctx context.Context, hashes []string) error {
+	return d.call(ctx, "core.resume_torrents", []any{hashes}, nil)
+}
+
+func (d *Deluge) Stop(ctx context.Context, hashes []string) error {
+	return d.call(ctx, "core.pause_torrents", []any{hashes}, nil)
+}
+
+// SetCategory sets the Label plugin label, creating it when needed. Deluge
+// labels are lowercase; an empty category removes the label.
+func (d *Deluge) SetCategory(ctx context.Context, hashes []string, category string) error {
+	label := strings.ToLower(strings.TrimSpace(category))
+	if label == "" {
+		label = delugeNoLabel
+	} else {
+		if !delugeLabelPattern.MatchString(label) {
+			return fmt.Errorf("deluge: invalid label %q: use letters, digits, '_', '-' and '.'", category)
+		}
+		var labels []string
+		if err := d.call(ctx, "label.get_labels", nil, &labels); err != nil {
+			return fmt.Errorf("deluge: label plugin unavailable: %w", err)
+		}
+		if !slices.Contains(labels, label) {
+			if err := d.call(ctx, "label.add", []any{label}, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	var errs []error
+	for _, hash := range hashes {
+		errs = append(errs, d.call(ctx, "label.set_torrent", []any{hash, label}, nil))
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Deluge) SetTags(context.Context, string, []string) error {
+	return fmt.Errorf("deluge tags: %w", ErrUnsupported)
+}
+
+func (d *Deluge) SetLocation(ctx context.Context, hashes []string, location string) error {
+	return d.call(ctx, "core.move_storage", []any{hashes, location}, nil)
+}
+
+// SetShareLimits applies the ratio limit. Deluge has no seeding time limits,
+// and a torrent cannot go back to the global ratio once it has its own, so
+// -2 and -1 both turn the torrent's ratio limit off.
+func (d *Deluge) SetShareLimits(ctx context.Context, hashes []string, limits ShareLimits) error {
+	options := map[string]any{"stop_at_ratio": limits.RatioLimit >= 0}
+	if limits.RatioLimit >= 0 {
+		options["stop_ratio"] = limits.RatioLimit
+	}
+	return d.call(ctx, "core.set_torrent_options", []any{hashes, options}, nil)
+}
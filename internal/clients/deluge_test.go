@@ -0,0 +1,185 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type delugeCall struct {
+	Method string
+	Params []any
+}
+
+// delugeServer emulates a Web UI that is not yet connected to its daemon. It
+// answers methods past login with handle and records every call.
+func delugeServer(t *testing.T, handle func(method string, params []any) any) (*httptest.Server, func() []delugeCall) {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		calls    []delugeCall
+		loggedIn bool
+		daemon   bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/json", r.URL.Path)
+		var req delugeRequest
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			return
+		}
+
+		mu.Lock()
+		calls = append(calls, delugeCall{Method: req.Method, Params: req.Params})
+		mu.Unlock()
+
+		reply := map[string]any{"id": req.ID, "error": nil}
+		switch {
+		case req.Method == "auth.login":
+			loggedIn = len(req.Params) == 1 && req.Params[0] == "deluge"
+			reply["result"] = loggedIn
+		case !loggedIn:
+			reply["error"] = map[string]any{"message": "Not authenticated", "code": delugeNotAuthenticated}
+		case req.Method == "web.connected":
+			reply["result"] = daemon
+		case req.Method == "web.get_hosts":
+			reply["result"] = [][]any{{"host-1", "127.0.0.1", 58846, "localclient"}}
+		case req.Method == "web.connect":
+			daemon = req.Params[0] == "host-1"
+			reply["result"] = nil
+		default:
+			reply["result"] = handle(req.Method, req.Params)
+		}
+		_ = json.NewEncoder(w).Encode(reply)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []delugeCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]delugeCall(nil), calls...)
+	}
+}
+
+func TestDelugeConnectsToFirstHost(t *testing.T) {
+	server, calls := delugeServer(t, func(method string, _ []any) any {
+		assert.Equal(t, "daemon.info", method)
+		return "2.1.1"
+	})
+
+	client, err := NewDeluge(Config{Host: server.URL, Password: "deluge"})
+	require.NoError(t, err)
+
+	version, err := client.Version(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "2.1.1", version)
+
+	methods := make([]string, 0, len(calls()))
+	for _, call := range calls() {
+		methods = append(methods, call.Method)
+	}
+	assert.Equal(t, []string{"auth.login", "web.connected", "web.get_hosts", "web.connect", "daemon.info"}, methods)
+}
+
+func TestDelugeTorrents(t *testing.T) {
+	server, _ := delugeServer(t, func(method string, _ []any) any {
+		assert.Equal(t, "core.get_torrents_status", method)
+		return map[string]any{"def456": map[string]any{
+			"name":                  "Some.Release",
+			"state":                 "Paused",
+			"progress":              40.0,
+			"total_wanted":          1000.0,
+			"total_done":            400.0,
+			"download_location":     "/downloads",
+			"label":                 "movies",
+			"tracker":               "https://tracker.example/announce",
+			"tracker_status":        "Error: unregistered torrent",
+			"stop_at_ratio":         true,
+			"stop_ratio":            2.0,
+			"eta":                   0.0,
+			"download_payload_rate": 0.0,
+		}}
+	})
+
+	client, err := NewDeluge(Config{Host: server.URL, Password: "deluge"})
+	require.NoError(t, err)
+
+	torrents, err := client.Torrents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, torrents, 1)
+
+	torrent := torrents[0]
+	assert.Equal(t, "def456", torrent.Hash)
+	assert.Equal(t, qbt.TorrentStateStoppedDl, torrent.State)
+	assert.InDelta(t, 0.4, torrent.Progress, 0.001)
+	assert.Equal(t, int64(600), torrent.AmountLeft)
+	assert.Equal(t, "movies", torrent.Category)
+	assert.InDelta(t, 2.0, torrent.RatioLimit, 0.001)
+	assert.Equal(t, int64(infiniteETA), torrent.ETA)
+	require.Len(t, torrent.Trackers, 1)
+	assert.Equal(t, qbt.TrackerStatusNotWorking, torrent.Trackers[0].Status)
+	assert.Equal(t, "unregistered torrent", torrent.Trackers[0].Message)
+}
+
+func TestDelugeSetCategoryCreatesLabel(t *testing.T) {
+	server, calls := delugeServer(t, func(method string, _ []any) any {
+		if method == "label.get_labels" {
+			return []string{"tv"}
+		}
+		return nil
+	})
+
+	client, err := NewDeluge(Config{Host: server.URL, Password: "deluge"})
+	require.NoError(t, err)
+
+	require.NoError(t, client.SetCategory(context.Background(), []string{"def456"}, "Movies"))
+	require.Error(t, client.SetCategory(context.Background(), []string{"def456"}, "bad label!"))
+
+	var labelCalls []delugeCall
+	for _, call := range calls() {
+		if call.Method == "label.add" || call.Method == "label.set_torrent" {
+			labelCalls = append(labelCalls, call)
+		}
+	}
+	assert.Equal(t, []delugeCall{
+		{Method: "label.add", Params: []any{"movies"}},
+		{Method: "label.set_torrent", Params: []any{"def456", "movies"}},
+	}, labelCalls)
+}
+
+func TestDelugeFilePriority(t *testing.T) {
+	assert.Equal(t, qbtFilePrioritySkip, delugeFilePriority(0))
+	assert.Equal(t, qbtFilePriorityNormal, delugeFilePriority(4))
+	assert.Equal(t, qbtFilePriorityHigh, delugeFilePriority(5))
+	assert.Equal(t, qbtFilePriorityMax, delugeFilePriority(7))
+}
+
+func TestDelugeTorrentFilePath(t *testing.T) {
+	server, _ := delugeServer(t, func(method string, params []any) any {
+		switch method {
+		case "core.get_torrent_status":
+			return map[string]any{"hash": "abcdef"}
+		case "core.get_config_value":
+			assert.Equal(t, []any{"plugins_location"}, params)
+			return "/config/plugins"
+		}
+		t.Errorf("unexpected method %s", method)
+		return nil
+	})
+
+	client, err := NewDeluge(Config{Host: server.URL, Password: "deluge"})
+	require.NoError(t, err)
+	path, err := client.TorrentFilePath(context.Background(), "ABCDEF")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/config", "state", "abcdef.torrent"), path)
+}
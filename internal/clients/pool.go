@@ -0,0 +1,148 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+var (
+	// ErrQBittorrentInstance is returned by Get for instances qui manages through
+	// its qBittorrent client pool.
+	ErrQBittorrentInstance = errors.New("instance is a qBittorrent client")
+	// ErrInstanceDisabled indicates the instance exists but is not active.
+	ErrInstanceDisabled = errors.New("instance is disabled")
+)
+
+// InstanceLookup is the subset of the instance store the pool needs.
+type InstanceLookup interface {
+	Get(ctx context.Context, id int) (*models.Instance, error)
+	GetDecryptedPassword(instance *models.Instance) (string, error)
+	GetDecryptedBasicPassword(instance *models.Instance) (*string, error)
+}
+
+// Pool caches one Client per Transmission or Deluge instance, plus every
+// instance's client type so callers can cheaply tell them apart.
+type Pool struct {
+	instances InstanceLookup
+	newClient func(models.ClientType, Config) (Client, error)
+
+	mu      sync.RWMutex
+	types   map[int]models.ClientType
+	clients map[int]Client
+}
+
+func NewPool(instances InstanceLookup) *Pool {
+	return &Pool{
+		instances: instances,
+		newClient: New,
+		types:     make(map[int]models.ClientType),
+		clients:   make(map[int]Client),
+	}
+}
+
+// ClientType returns the client type of an instance.
+func (p *Pool) ClientType(ctx context.Context, instanceID int) (models.ClientType, error) {
+	p.mu.RLock()
+	clientType, ok := p.types[instanceID]
+	p.mu.RUnlock()
+	if ok {
+		return clientType, nil
+	}
+
+	instance, err := p.instances.Get(ctx, instanceID)
+	if err != nil {
+		return "", err
+	}
+	clientType = instance.ClientType.OrDefault()
+
+	p.mu.Lock()
+	p.types[instanceID] = clientType
+	p.mu.Unlock()
+	return clientType, nil
+}
+
+// IsManaged reports whether the instance is served by this pool rather than
+// the qBittorrent client pool. Lookup failures report false.
+func (p *Pool) IsManaged(ctx context.Context, instanceID int) bool {
+	if p == nil {
+		return false
+	}
+	clientType, err := p.ClientType(ctx, instanceID)
+	return err == nil && clientType != models.ClientTypeQBittorrent
+}
+
+// Get returns the client of an instance, creating it on first use.
+func (p *Pool) Get(ctx context.Context, instanceID int) (Client, error) {
+	p.mu.RLock()
+	client, ok := p.clients[instanceID]
+	p.mu.RUnlock()
+	if ok {
+		return client, nil
+	}
+
+	instance, err := p.instances.Get(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	clientType := instance.ClientType.OrDefault()
+
+	p.mu.Lock()
+	p.types[instanceID] = clientType
+	p.mu.Unlock()
+
+	if clientType == models.ClientTypeQBittorrent {
+		return nil, ErrQBittorrentInstance
+	}
+	if !instance.IsActive {
+		return nil, ErrInstanceDisabled
+	}
+
+	password, err := p.instances.GetDecryptedPassword(instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt password: %w", err)
+	}
+	basicPassword, err := p.instances.GetDecryptedBasicPassword(instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt basic auth password: %w", err)
+	}
+
+	client, err = p.newClient(clientType, Config{
+		Host:          instance.Host,
+		Username:      instance.Username,
+		Password:      password,
+		BasicUsername: instance.BasicUsername,
+		BasicPassword: basicPassword,
+		TLSSkipVerify: instance.TLSSkipVerify,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// Another caller may have created the client meanwhile; keep the first one.
+	if existing, ok := p.clients[instanceID]; ok {
+		return existing, nil
+	}
+	p.clients[instanceID] = client
+	return client, nil
+}
+
+// Remove drops the cached client and client type of an instance, e.g. after
+// its settings changed.
+func (p *Pool) Remove(instanceID int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.clients, instanceID)
+	delete(p.types, instanceID)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+type fakeInstances map[int]*models.Instance
+
+func (f fakeInstances) Get(_ context.Context, id int) (*models.Instance, error) {
+	instance, ok := f[id]
+	if !ok {
+		return nil, models.ErrInstanceNotFound
+	}
+	return instance, nil
+}
+
+func (f fakeInstances) GetDecryptedPassword(instance *models.Instance) (string, error) {
+	return instance.PasswordEncrypted, nil
+}
+
+func (f fakeInstances) GetDecryptedBasicPassword(*models.Instance) (*string, error) {
+	return nil, nil
+}
+
+func TestPool(t *testing.T) {
+	ctx := context.Background()
+	instances := fakeInstances{
+		1: {ID: 1, Host: "http://qbit:8080", IsActive: true},
+		2: {ID: 2, Host: "http://transmission:9091", ClientType: models.ClientTypeTransmission, IsActive: true, PasswordEncrypted: "secret"},
+		3: {ID: 3, Host: "http://deluge:8112", ClientType: models.ClientTypeDeluge},
+	}
+	pool := NewPool(instances)
+
+	assert.False(t, pool.IsManaged(ctx, 1))
+	assert.True(t, pool.IsManaged(ctx, 2))
+	assert.True(t, pool.IsManaged(ctx, 3))
+	assert.False(t, pool.IsManaged(ctx, 99))
+
+	_, err := pool.Get(ctx, 1)
+	require.ErrorIs(t, err, ErrQBittorrentInstance)
+	_, err = pool.Get(ctx, 3)
+	require.ErrorIs(t, err, ErrInstanceDisabled)
+
+	client, err := pool.Get(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, models.ClientTypeTransmission, client.Type())
+	again, err := pool.Get(ctx, 2)
+	require.NoError(t, err)
+	assert.Same(t, client, again)
+
+	// A changed client type takes effect after Remove.
+	instances[2].ClientType = models.ClientTypeQBittorrent
+	assert.True(t, pool.IsManaged(ctx, 2))
+	pool.Remove(2)
+	assert.False(t, pool.IsManaged(ctx, 2))
+
+	var nilPool *Pool
+	assert.False(t, nilPool.IsManaged(ctx, 2))
+	nilPool.Remove(2)
+}
@@ -0,0 +1,545 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+const transmissionSessionHeader = "X-Transmission-Session-Id"
+
+// Transmission torrent status codes.
+const (
+	transmissionStopped      = 0
+	transmissionCheckWait    = 1
+	transmissionCheck        = 2
+	transmissionDownloadWait = 3
+	transmissionDownload     = 4
+	transmissionSeedWait     = 5
+	transmissionSeed         = 6
+)
+
+// Transmission limit modes shared by seedRatioMode and seedIdleMode.
+const (
+	transmissionLimitGlobal    = 0
+	transmissionLimitSingle    = 1
+	transmissionLimitUnlimited = 2
+)
+
+// transmissionLocalError is the torrent error code for local (non-tracker) errors.
+const transmissionLocalError = 3
+
+var transmissionTorrentFields = []string{
+	"hashString", "name", "status", "error", "errorString", "percentDone",
+	"totalSize", "sizeWhenDone", "leftUntilDone", "rateDownload", "rateUpload",
+	"uploadedEver", "downloadedEver", "uploadRatio", "eta", "addedDate",
+	"doneDate", "activityDate", "secondsSeeding", "secondsDownloading",
+	"downloadDir", "labels", "isPrivate", "comment", "magnetLink",
+	"peersGettingFromUs", "peersSendingToUs", "seedRatioLimit", "seedRatioMode",
+	"seedIdleLimit", "seedIdleMode", "trackerStats",
+}
+
+// Transmission talks to Transmission's RPC interface.
+type Transmission struct {
+	cfg      Config
+	endpoint string
+	http     *http.Client
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+// NewTransmission returns a Transmission client. A host without an /rpc path
+// gets Transmission's default /transmission/rpc.
+func NewTransmission(cfg Config) *Transmission {
+	endpoint := strings.TrimRight(cfg.Host, "/")
+	if !strings.HasSuffix(endpoint, "/rpc") {
+		endpoint += "/transmission/rpc"
+	}
+	return &Transmission{
+		cfg:      cfg,
+		endpoint: endpoint,
+		http:     newHTTPClient(cfg),
+	}
+}
+
+func (t *Transmission) Type() models.ClientType {
+	return models.ClientTypeTransmission
+}
+
+func (t *Transmission) Capabilities() Capabilities {
+	return Capabilities{
+		Tags:                     true,
+		SetLocation:              true,
+		RatioLimit:               true,
+		InactiveSeedingTimeLimit: true,
+	}
+}
+
+type transmissionRequest struct {
+	Method    string `json:"method"`
+	Arguments any    `json:"arguments,omitempty"`
+}
+
+type transmissionResponse struct {
+	Result    string          `json:"result"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// call runs an RPC method, redoing the session id handshake when Transmission
+// answers 409, and decodes the arguments of the reply into out.
+func (t *Transmission) call(ctx context.Context, method string, args, out any) error {
+	body, err := json.Marshal(transmissionRequest{Method: method, Arguments: args})
+	if err != nil {
+		return err
+	}
+
+	for range 2 {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		t.mu.Lock()
+		if t.sessionID != "" {
+			req.Header.Set(transmissionSessionHeader, t.sessionID)
+		}
+		t.mu.Unlock()
+		// Transmission's own credentials are HTTP basic auth, so they win over
+		// the reverse proxy ones.
+		if t.cfg.Username != "" {
+			req.SetBasicAuth(t.cfg.Username, t.cfg.Password)
+		} else {
+			applyBasicAuth(req, t.cfg)
+		}
+
+		resp, err := t.http.Do(req)
+		if err != nil {
+			return fmt.Errorf("transmission %s: %w", method, err)
+		}
+
+		if resp.StatusCode == http.StatusConflict {
+			t.mu.Lock()
+			t.sessionID = resp.Header.Get(transmissionSessionHeader)
+			t.mu.Unlock()
+			resp.Body.Close()
+			continue
+		}
+
+		var decoded transmissionResponse
+		err = decodeJSONResponse(resp, &decoded)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("transmission %s: %w", method, err)
+		}
+		if decoded.Result != "success" {
+			return fmt.Errorf("transmission %s: %s", method, decoded.Result)
+		}
+		if out != nil && len(decoded.Arguments) > 0 {
+			if err := json.Unmarshal(decoded.Arguments, out); err != nil {
+				return fmt.Errorf("transmission %s: decode arguments: %w", method, err)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("transmission %s: session id handshake failed", method)
+}
+
+func (t *Transmission) Version(ctx context.Context) (string, error) {
+	var session struct {
+		Version string `json:"version"`
+	}
+	if err := t.call(ctx, "session-get", map[string]any{"fields": []string{"version"}}, &session); err != nil {
+		return "", err
+	}
+	return session.Version, nil
+}
+
+type transmissionTrackerStat struct {
+	Announce              string `json:"announce"`
+	AnnounceState         int    `json:"announceState"`
+	HasAnnounced          bool   `json:"hasAnnounced"`
+	LastAnnounceSucceeded bool   `json:"lastAnnounceSucceeded"`
+	LastAnnounceResult    string `json:"lastAnnounceResult"`
+	SeederCount           int    `json:"seederCount"`
+	LeecherCount          int    `json:"leecherCount"`
+	DownloadCount         int    `json:"downloadCount"`
+}
+
+type transmissionTorrent struct {
+	HashString         string                    `json:"hashString"`
+	Name               string                    `json:"name"`
+	Status             int                       `json:"status"`
+	Error              int                       `json:"error"`
+	ErrorString        string                    `json:"errorString"`
+	PercentDone        float64                   `json:"percentDone"`
+	TotalSize          int64                     `json:"totalSize"`
+	SizeWhenDone       int64                     `json:"sizeWhenDone"`
+	LeftUntilDone      int64                     `json:"leftUntilDone"`
+	RateDownload       int64                     `json:"rateDownload"`
+	RateUpload         int64                     `json:"rateUpload"`
+	UploadedEver       int64                     `json:"uploadedEver"`
+	DownloadedEver     int64                     `json:"downloadedEver"`
+	UploadRatio        float64                   `json:"uploadRatio"`
+	ETA                int64                     `json:"eta"`
+	AddedDate          int64                     `json:"addedDate"`
+	DoneDate           int64                     `json:"doneDate"`
+	ActivityDate       int64                     `json:"activityDate"`
+	SecondsSeeding     int64                     `json:"secondsSeeding"`
+	SecondsDownloading int64                     `json:"secondsDownloading"`
+	DownloadDir        string                    `json:"downloadDir"`
+	Labels             []string                  `json:"labels"`
+	IsPrivate          bool                      `json:"isPrivate"`
+	Comment            string                    `json:"comment"`
+	MagnetLink         string                    `json:"magnetLink"`
+	PeersGettingFromUs int64                     `json:"peersGettingFromUs"`
+	PeersSendingToUs   int64                     `json:"peersSendingToUs"`
+	SeedRatioLimit     float64                   `json:"seedRatioLimit"`
+	SeedRatioMode      int                       `json:"seedRatioMode"`
+	SeedIdleLimit      int64                     `json:"seedIdleLimit"`
+	SeedIdleMode       int                       `json:"seedIdleMode"`
+	TrackerStats       []transmissionTrackerStat `json:"trackerStats"`
+}
+
+func (t *Transmission) Torrents(ctx context.Context) ([]qbt.Torrent, error) {
+	var result struct {
+		Torrents []transmissionTorrent `json:"torrents"`
+	}
+	if err := t.call(ctx, "torrent-get", map[string]any{"fields": transmissionTorrentFields}, &result); err != nil {
+		return nil, err
+	}
+
+	torrents := make([]qbt.Torrent, 0, len(result.Torrents))
+	for _, torrent := range result.Torrents {
+		torrents = append(torrents, torrent.toQBT())
+	}
+	return torrents, nil
+}
+
+func (tt transmissionTorrent) toQBT() qbt.Torrent {
+	trackers := make([]qbt.TorrentTracker, 0, len(tt.TrackerStats))
+	currentTracker := ""
+	for _, stat := range tt.TrackerStats {
+		tracker := stat.toQBT()
+		if currentTracker == "" && tracker.Status == qbt.TrackerStatusOK {
+			currentTracker = tracker.Url
+		}
+		trackers = append(trackers, tracker)
+	}
+	if currentTracker == "" && len(trackers) > 0 {
+		currentTracker = trackers[0].Url
+	}
+
+	eta := tt.ETA
+	if eta < 0 {
+		eta = infiniteETA
+	}
+
+	return qbt.Torrent{
+		Hash:                     tt.HashString,
+		InfohashV1:               tt.HashString,
+		Name:                     tt.Name,
+		State:                    tt.state(),
+		Progress:                 tt.PercentDone,
+		Size:                     tt.SizeWhenDone,
+		TotalSize:                tt.TotalSize,
+		AmountLeft:               tt.LeftUntilDone,
+		Completed:                tt.SizeWhenDone - tt.LeftUntilDone,
+		DlSpeed:                  tt.RateDownload,
+		UpSpeed:                  tt.RateUpload,
+		Downloaded:               tt.DownloadedEver,
+		Uploaded:                 tt.UploadedEver,
+		Ratio:                    max(tt.UploadRatio, 0),
+		ETA:                      eta,
+		AddedOn:                  tt.AddedDate,
+		CompletionOn:             tt.DoneDate,
+		LastActivity:             tt.ActivityDate,
+		SeedingTime:              tt.SecondsSeeding,
+		TimeActive:               tt.SecondsSeeding + tt.SecondsDownloading,
+		SavePath:                 tt.DownloadDir,
+		ContentPath:              path.Join(tt.DownloadDir, tt.Name),
+		Tags:                     strings.Join(tt.Labels, ", "),
+		Private:                  tt.IsPrivate,
+		Comment:                  tt.Comment,
+		MagnetURI:                tt.MagnetLink,
+		NumSeeds:                 tt.PeersSendingToUs,
+		NumLeechs:                tt.PeersGettingFromUs,
+		RatioLimit:               transmissionLimit(tt.SeedRatioMode, tt.SeedRatioLimit),
+		SeedingTimeLimit:         globalLimit,
+		InactiveSeedingTimeLimit: int64(transmissionLimit(tt.SeedIdleMode, float64(tt.SeedIdleLimit))),
+		Tracker:                  currentTracker,
+		TrackersCount:            int64(len(trackers)),
+		Trackers:                 trackers,
+	}
+}
+
+func (tt transmissionTorrent) state() qbt.TorrentState {
+	done := tt.PercentDone >= 1
+	switch {
+	case tt.Error == transmissionLocalError:
+		return qbt.TorrentStateError
+	case tt.Status == transmissionStopped && done:
+		return qbt.TorrentStateStoppedUp
+	case tt.Status == transmissionStopped:
+		return qbt.TorrentStateStoppedDl
+	case tt.Status == transmissionCheckWait, tt.Status == transmissionCheck:
+		if done {
+			return qbt.TorrentStateCheckingUp
+		}
+		return qbt.TorrentStateCheckingDl
+	case tt.Status == transmissionDownloadWait:
+		return qbt.TorrentStateQueuedDl
+	case tt.Status == transmissionDownload && tt.RateDownload > 0:
+		return qbt.TorrentStateDownloading
+	case tt.Status == transmissionDownload:
+		return qbt.TorrentStateStalledDl
+	case tt.Status == transmissionSeedWait:
+		return qbt.TorrentStateQueuedUp
+	case tt.Status == transmissionSeed && tt.RateUpload > 0:
+		return qbt.TorrentStateUploading
+	case tt.Status == transmissionSeed:
+		return qbt.TorrentStateStalledUp
+	default:
+		return qbt.TorrentStateUnknown
+	}
+}
+
+// transmissionAnnounceActive is the announceState of a tracker being announced to.
+const transmissionAnnounceActive = 3
+
+func (stat transmissionTrackerStat) toQBT() qbt.TorrentTracker {
+	tracker := qbt.TorrentTracker{
+		Url:           stat.Announce,
+		NumPeers:      stat.SeederCount + stat.LeecherCount,
+		NumSeeds:      stat.SeederCount,
+		NumLeechers:   stat.LeecherCount,
+		NumDownloaded: stat.DownloadCount,
+	}
+	switch {
+	case stat.AnnounceState == transmissionAnnounceActive:
+		tracker.Status = qbt.TrackerStatusUpdating
+	case stat.HasAnnounced && stat.LastAnnounceSucceeded:
+		tracker.Status = qbt.TrackerStatusOK
+	case stat.HasAnnounced:
+		tracker.Status = qbt.TrackerStatusNotWorking
+		tracker.Message = stat.LastAnnounceResult
+	default:
+		tracker.Status = qbt.TrackerStatusNotContacted
+	}
+	return tracker
+}
+
+// transmissionLimit converts a Transmission limit mode and value to
+// qBittorrent's -2 (global) / -1 (unlimited) / value convention.
+func transmissionLimit(mode int, value float64) float64 {
+	switch mode {
+	case transmissionLimitSingle:
+		return value
+	case transmissionLimitUnlimited:
+		return unlimitedLimit
+	default:
+		return globalLimit
+	}
+}
+
+// transmissionLimitArgs returns the mode and value to send for a limit in
+// qBittorrent's convention.
+func transmissionLimitArgs(limit float64) (int, float64) {
+	switch {
+	case limit == globalLimit:
+		return transmissionLimitGlobal, 0
+	case limit < 0:
+		return transmissionLimitUnlimited, 0
+	default:
+		return transmissionLimitSingle, limit
+	}
+}
+
+func (t *Transmission) torrentByHash(ctx context.Context, hash string, fields ...string) (json.RawMessage, error) {
+	var result struct {
+		Torrents []json.RawMessage `json:"torrents"`
+	}
+	if err := t.call(ctx, "torrent-get", map[string]any{"ids": []string{hash}, "fields": fields}, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Torrents) == 0 {
+		return nil, fmt.Errorf("transmission: torrent %s not found", hash)
+	}
+	return result.Torrents[0], nil
+}
+
+func (t *Transmission) Files(ctx context.Context, hash string) (qbt.TorrentFiles, error) {
+	raw, err := t.torrentByHash(ctx, hash, "files", "fileStats")
+	if err != nil {
+		return nil, err
+	}
+	var torrent struct {
+		Files []struct {
+			Name           string `json:"name"`
+			Length         int64  `json:"length"`
+			BytesCompleted int64  `json:"bytesCompleted"`
+		} `json:"files"`
+		FileStats []struct {
+			Wanted   bool `json:"wanted"`
+			Priority int  `json:"priority"`
+		} `json:"fileStats"`
+	}
+	if err := json.Unmarshal(raw, &torrent); err != nil {
+		return nil, fmt.Errorf("transmission: decode files: %w", err)
+	}
+
+	files := make(qbt.TorrentFiles, len(torrent.Files))
+	for i, file := range torrent.Files {
+		files[i].Index = i
+		files[i].Name = file.Name
+		files[i].Size = file.Length
+		if file.Length > 0 {
+			files[i].Progress = float32(file.BytesCompleted) / float32(file.Length)
+		}
+		files[i].IsSeed = file.Length > 0 && file.BytesCompleted == file.Length
+		files[i].Priority = qbtFilePriorityNormal
+		if i < len(torrent.FileStats) {
+			stat := torrent.FileStats[i]
+			switch {
+			case !stat.Wanted:
+				files[i].Priority = qbtFilePrioritySkip
+			case stat.Priority > 0:
+				files[i].Priority = qbtFilePriorityHigh
+			}
+		}
+	}
+	return files, nil
+}
+
+func (t *Transmission) Trackers(ctx context.Context, hash string) ([]qbt.TorrentTracker, error) {
+	raw, err := t.torrentByHash(ctx, hash, "trackerStats")
+	if err != nil {
+		return nil, err
+	}
+	var torrent struct {
+		TrackerStats []transmissionTrackerStat `json:"trackerStats"`
+	}
+	if err := json.Unmarshal(raw, &torrent); err != nil {
+		return nil, fmt.Errorf("transmission: decode trackers: %w", err)
+	}
+
+	trackers := make([]qbt.TorrentTracker, 0, len(torrent.TrackerStats))
+	for _, stat := range torrent.TrackerStats {
+		trackers = append(trackers, stat.toQBT())
+	}
+	return trackers, nil
+}
+
+func (t *Transmission) TorrentFilePath(ctx context.Context, hash string) (string, error) {
+	raw, err := t.torrentByHash(ctx, hash, "torrentFile")
+	if err != nil {
+		return "", err
+	}
+	var torrent struct {
+		TorrentFile string `json:"torrentFile"`
+	}
+	if err := json.Unmarshal(raw, &torrent); err != nil {
+		return "", fmt.Errorf("transmission: decode torrent file: %w", err)
+	}
+	if torrent.TorrentFile == "" {
+		return "", fmt.Errorf("transmission: torrent %s has no torrent file", hash)
+	}
+	return torrent.TorrentFile, nil
+}
+
+func (t *Transmission) Add(ctx context.Context, req AddRequest) error {
+	if req.Category != "" {
+		return fmt.Errorf("transmission categories: %w", ErrUnsupported)
+	}
+
+	add := func(args map[string]any) error {
+		if req.SavePath != "" {
+			args["download-dir"] = req.SavePath
+		}
+		args["paused"] = req.Paused
+
+		var result struct {
+			Added     *struct{ HashString string } `json:"torrent-added"`
+			Duplicate *struct{ HashString string } `json:"torrent-duplicate"`
+		}
+		if err := t.call(ctx, "torrent-add", args, &result); err != nil {
+			return err
+		}
+		if result.Added == nil || len(req.Tags) == 0 {
+			return nil
+		}
+		// Labels on torrent-add need a recent RPC version, torrent-set works everywhere labels do.
+		return t.SetTags(ctx, result.Added.HashString, req.Tags)
+	}
+
+	var errs []error
+	for _, content := range req.Files {
+		errs = append(errs, add(map[string]any{"metainfo": base64.StdEncoding.EncodeToString(content)}))
+	}
+	for _, url := range req.URLs {
+		errs = append(errs, add(map[string]any{"filename": url}))
+	}
+	return errors.Join(errs...)
+}
+
+func (t *Transmission) Delete(ctx context.Context, hashes []string, deleteFiles bool) error {
+	return t.call(ctx, "torrent-remove", map[string]any{"ids": hashes, "delete-local-data": deleteFiles}, nil)
+}
+
+func (t *Transmission) Start(ctx context.Context, hashes []string) error {
+	return t.call(ctx, "torrent-start", map[string]any{"ids": hashes}, nil)
+}
+
+func (t *Transmission) Stop(ctx context.Context, hashes []string) error {
+	return t.call(ctx, "torrent-stop", map[string]any{"ids": hashes}, nil)
+}
+
+func (t *Transmission) SetCategory(context.Context, []string, string) error {
+	return fmt.Errorf("transmission categories: %w", ErrUnsupported)
+}
+
+func (t *Transmission) SetTags(ctx context.Context, hash string, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	return t.call(ctx, "torrent-set", map[string]any{"ids": []string{hash}, "labels": tags}, nil)
+}
+
+func (t *Transmission) SetLocation(ctx context.Context, hashes []string, location string) error {
+	return t.call(ctx, "torrent-set-location", map[string]any{"ids": hashes, "location": location, "move": true}, nil)
+}
+
+// SetShareLimits applies the ratio and inactive seeding limits. Transmission
+// has no total seeding time limit, so SeedingTimeLimit is ignored.
+func (t *Transmission) SetShareLimits(ctx context.Context, hashes []string, limits ShareLimits) error {
+	ratioMode, ratio := transmissionLimitArgs(limits.RatioLimit)
+	idleMode, idle := transmissionLimitArgs(float64(limits.InactiveSeedingTimeLimit))
+
+	args := map[string]any{
+		"ids":           hashes,
+		"seedRatioMode": ratioMode,
+		"seedIdleMode":  idleMode,
+	}
+	if ratioMode == transmissionLimitSingle {
+		args["seedRatioLimit"] = ratio
+	}
+	if idleMode == transmissionLimitSingle {
+		args["seedIdleLimit"] = int64(idle)
+	}
+	return t.call(ctx, "torrent-set", args, nil)
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transmissionServer answers RPC calls with handle after enforcing the
+// session id handshake and the configured credentials.
+func transmissionServer(t *testing.T, handle func(method string, args map[string]any) any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/transmission/rpc", r.URL.Path)
+		if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(transmissionSessionHeader) != "session-1" {
+			w.Header().Set(transmissionSessionHeader, "session-1")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		var req struct {
+			Method    string         `json:"method"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"result":    "success",
+			"arguments": handle(req.Method, req.Arguments),
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTransmissionVersionHandshake(t *testing.T) {
+	server := transmissionServer(t, func(method string, _ map[string]any) any {
+		assert.Equal(t, "session-get", method)
+		return map[string]any{"version": "4.0.6"}
+	})
+
+	client := NewTransmission(Config{Host: server.URL, Username: "admin", Password: "secret"})
+	version, err := client.Version(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "4.0.6", version)
+}
+
+func TestTransmissionTorrents(t *testing.T) {
+	server := transmissionServer(t, func(string, map[string]any) any {
+		return map[string]any{"torrents": []map[string]any{{
+			"hashString":     "abc123",
+			"name":           "Some.Release",
+			"status":         transmissionSeed,
+			"percentDone":    1,
+			"sizeWhenDone":   1000,
+			"totalSize":      1000,
+			"rateUpload":     50,
+			"uploadRatio":    1.5,
+			"eta":            -1,
+			"downloadDir":    "/data",
+			"labels":         []string{"tv", "hd"},
+			"seedRatioMode":  transmissionLimitSingle,
+			"seedRatioLimit": 2,
+			"seedIdleMode":   transmissionLimitUnlimited,
+			"trackerStats": []map[string]any{{
+				"announce":              "https://tracker.example/announce",
+				"hasAnnounced":          true,
+				"lastAnnounceSucceeded": true,
+				"seederCount":           4,
+				"leecherCount":          1,
+			}},
+		}}}
+	})
+
+	client := NewTransmission(Config{Host: server.URL, Username: "admin", Password: "secret"})
+	torrents, err := client.Torrents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, torrents, 1)
+
+	torrent := torrents[0]
+	assert.Equal(t, "abc123", torrent.Hash)
+	assert.Equal(t, qbt.TorrentStateUploading, torrent.State)
+	assert.Equal(t, "tv, hd", torrent.Tags)
+	assert.Equal(t, "/data/Some.Release", torrent.ContentPath)
+	assert.InDelta(t, 2.0, torrent.RatioLimit, 0.001)
+	assert.Equal(t, int64(unlimitedLimit), torrent.InactiveSeedingTimeLimit)
+	assert.Equal(t, int64(infiniteETA), torrent.ETA)
+	assert.Equal(t, "https://tracker.example/announce", torrent.Tracker)
+	require.Len(t, torrent.Trackers, 1)
+	assert.Equal(t, qbt.TrackerStatusOK, torrent.Trackers[0].Status)
+	assert.Equal(t, 5, torrent.Trackers[0].NumPeers)
+}
+
+func TestTransmissionSetShareLimits(t *testing.T) {
+	var got map[string]any
+	server := transmissionServer(t, func(method string, args map[string]any) any {
+		assert.Equal(t, "torrent-set", method)
+		got = args
+		return map[string]any{}
+	})
+
+	client := NewTransmission(Config{Host: server.URL, Username: "admin", Password: "secret"})
+	err := client.SetShareLimits(context.Background(), []string{"abc123"}, ShareLimits{
+		RatioLimit:               1.25,
+		SeedingTimeLimit:         60,
+		InactiveSeedingTimeLimit: globalLimit,
+	})
+	require.NoError(t, err)
+
+	assert.InDelta(t, float64(transmissionLimitSingle), got["seedRatioMode"], 0)
+	assert.InDelta(t, 1.25, got["seedRatioLimit"], 0.001)
+	assert.InDelta(t, float64(transmissionLimitGlobal), got["seedIdleMode"], 0)
+	assert.NotContains(t, got, "seedIdleLimit")
+}
+
+func TestTransmissionRejectsCategories(t *testing.T) {
+	client := NewTransmission(Config{Host: "http://localhost:9091"})
+	require.ErrorIs(t, client.SetCategory(context.Background(), []string{"abc"}, "tv"), ErrUnsupported)
+	require.ErrorIs(t, client.Add(context.Background(), AddRequest{URLs: []string{"magnet:?xt=1"}, Category: "tv"}), ErrUnsupported)
+}
+
+func TestTransmissionTorrentFilePath(t *testing.T) {
+	server := transmissionServer(t, func(method string, args map[string]any) any {
+		assert.Equal(t, "torrent-get", method)
+		assert.Equal(t, []any{"torrentFile"}, args["fields"])
+		return map[string]any{"torrents": []any{map[string]any{"torrentFile": "/config/torrents/abc.torrent"}}}
+	})
+
+	client := NewTransmission(Config{Host: server.URL, Username: "admin", Password: "secret"})
+	path, err := client.TorrentFilePath(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "/config/torrents/abc.torrent", path)
+}
@@ -110,6 +110,9 @@ func (c *AppConfig) defaults() {
 	c.viper.SetDefault("logMaxBackups", 10)
 	c.viper.SetDefault("dataDir", "")   // Empty means auto-detect (next to config file)
 	c.viper.SetDefault("backupDir", "") // Empty means <dataDir>/backups
+	c.viper.SetDefault("configBackupInterval", 24)
+	c.viper.SetDefault("configBackupRetention", 14)
+	c.viper.SetDefault("configBackupPassphrase", "")
 	c.viper.SetDefault("databaseEngine", "sqlite")
 	c.viper.SetDefault("databaseDsn", "")
 	c.viper.SetDefault("databaseHost", "localhost")
@@ -214,6 +217,9 @@ func (c *AppConfig) loadFromEnv() {
 	c.viper.BindEnv("logMaxBackups", envPrefix+"LOG_MAX_BACKUPS")
 	c.viper.BindEnv("dataDir", envPrefix+"DATA_DIR")
 	c.viper.BindEnv("backupDir", envPrefix+"BACKUP_DIR")
+	c.viper.BindEnv("configBackupInterval", envPrefix+"CONFIG_BACKUP_INTERVAL")
+	c.viper.BindEnv("configBackupRetention", envPrefix+"CONFIG_BACKUP_RETENTION")
+	c.bindOrReadFromFile("configBackupPassphrase", envPrefix+"CONFIG_BACKUP_PASSPHRASE")
 	c.viper.BindEnv("databaseEngine", envPrefix+"DATABASE_ENGINE")
 	c.bindOrReadFromFile("databaseDsn", envPrefix+"DATABASE_DSN")
 	c.viper.BindEnv("databaseHost", envPrefix+"DATABASE_HOST")
@@ -333,6 +339,9 @@ func (c *AppConfig) hydrateConfigFromViper() {
 
 	c.Config.DataDir = c.viper.GetString("dataDir")
 	c.Config.BackupDir = c.viper.GetString("backupDir")
+	c.Config.ConfigBackupInterval = c.viper.GetInt("configBackupInterval")
+	c.Config.ConfigBackupRetention = c.viper.GetInt("configBackupRetention")
+	c.Config.ConfigBackupPassphrase = c.viper.GetString("configBackupPassphrase")
 	c.Config.DatabaseEngine = c.viper.GetString("databaseEngine")
 	c.Config.DatabaseDSN = c.viper.GetString("databaseDsn")
 	c.Config.DatabaseHost = c.viper.GetString("databaseHost")
@@ -514,6 +523,17 @@ sessionSecret = "{{ .sessionSecret }}"
 # <dataDir>/backups into the new directory yourself.
 #backupDir = "/mnt/storage/qui-backups"
 
+# Scheduled configuration backups
+# qui exports its own configuration (instances, automations, cross-seed,
+# indexers, notifications, dir-scan, themes, ...) to <backupDir>/qui-config
+# every configBackupInterval hours (0 disables) and keeps the newest
+# configBackupRetention bundles (0 keeps all). Restore with "qui backup restore".
+# Without a passphrase, passwords and API keys are redacted from the bundles.
+# Default: 24 / 14
+#configBackupInterval = 24
+#configBackupRetention = 14
+#configBackupPassphrase = ""
+
 # Custom themes directory (default: <config-dir>/themes, auto-created)
 # Drop sideloaded *.css theme files here. Listing requires premium access.
 # A relative path is resolved against the config directory.
@@ -825,6 +845,12 @@ func (c *AppConfig) GetBackupDir() string {
 	return dir
 }
 
+// GetConfigBackupDir returns the directory scheduled configuration bundles
+// are saved to.
+func (c *AppConfig) GetConfigBackupDir() string {
+	return filepath.Join(c.GetBackupDir(), "qui-config")
+}
+
 // GetConfigDir returns the directory containing the config file
 func (c *AppConfig) GetConfigDir() string {
 	if c.viper.ConfigFileUsed() != "" {
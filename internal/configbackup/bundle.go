@@ -0,0 +1,156 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package configbackup exports qui's own configuration (instances,
+// automations, cross-seed settings, indexers, notification targets, dir-scan
+// config, themes and so on) to a portable bundle, and restores such a bundle
+// into a SQLite or Postgres database.
+package configbackup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"filippo.io/age"
+)
+
+const (
+	// BundleFormat identifies qui configuration bundles.
+	BundleFormat = "qui-config-backup"
+	// BundleVersion is the bundle layout version this build writes and the
+	// newest it reads.
+	BundleVersion = 1
+
+	// MinPassphraseLength matches the minimum for backup encryption.
+	MinPassphraseLength = 8
+
+	ageHeader = "age-encryption.org/v1\n"
+)
+
+var (
+	// ErrInvalidBundle is returned for files that are not configuration
+	// bundles or were written by a newer qui.
+	ErrInvalidBundle = errors.New("invalid configuration bundle")
+	// ErrPassphraseRequired is returned when reading an encrypted bundle
+	// without a passphrase.
+	ErrPassphraseRequired = errors.New("bundle is encrypted; a passphrase is required")
+	// ErrWrongPassphrase is returned when the passphrase does not open an
+	// encrypted bundle.
+	ErrWrongPassphrase = errors.New("passphrase does not decrypt this bundle")
+)
+
+// Bundle is a snapshot of qui's configuration tables.
+type Bundle struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	AppVersion string    `json:"appVersion"`
+	Engine     string    `json:"engine"`
+	CreatedAt  time.Time `json:"createdAt"`
+	// Secrets reports whether passwords and API keys are included. Bundles
+	// written without a passphrase carry them redacted.
+	Secrets bool    `json:"secrets"`
+	Tables  []Table `json:"tables"`
+}
+
+// Table holds the rows of one table. Pooled columns carry their strings and
+// secret columns their plaintext, so rows don't depend on the source
+// database or its encryption key.
+type Table struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+}
+
+// Encode writes b to w, encrypted with passphrase when one is given. The
+// encrypted form is a plain age file, so it can be opened with
+// `age -d` and the passphrase outside qui.
+func Encode(w io.Writer, b *Bundle, passphrase string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode bundle: %w", err)
+	}
+
+	if passphrase == "" {
+		_, err = w.Write(data)
+		return err
+	}
+
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return fmt.Errorf("encrypt bundle: %w", err)
+	}
+	aw, err := age.Encrypt(w, recipient)
+	if err != nil {
+		return fmt.Errorf("encrypt bundle: %w", err)
+	}
+	if _, err := aw.Write(data); err != nil {
+		return fmt.Errorf("encrypt bundle: %w", err)
+	}
+	if err := aw.Close(); err != nil {
+		return fmt.Errorf("encrypt bundle: %w", err)
+	}
+	return nil
+}
+
+// Decode reads a bundle written by Encode, decrypting it with passphrase if
+// it is encrypted.
+func Decode(r io.Reader, passphrase string) (*Bundle, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read bundle: %w", err)
+	}
+
+	if bytes.HasPrefix(data, []byte(ageHeader)) {
+		if passphrase == "" {
+			return nil, ErrPassphraseRequired
+		}
+		identity, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt bundle: %w", err)
+		}
+		plain, err := age.Decrypt(bytes.NewReader(data), identity)
+		if err != nil {
+			if _, ok := errors.AsType[*age.NoIdentityMatchError](err); ok {
+				return nil, ErrWrongPassphrase
+			}
+			return nil, fmt.Errorf("decrypt bundle: %w", err)
+		}
+		if data, err = io.ReadAll(plain); err != nil {
+			return nil, fmt.Errorf("decrypt bundle: %w", err)
+		}
+	}
+
+	// Numbers stay json.Number so 64-bit IDs and sizes survive the round
+	// trip; restore converts them once it knows the column.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var b Bundle
+	if err := dec.Decode(&b); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (b *Bundle) validate() error {
+	if b.Format != BundleFormat {
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidBundle, b.Format)
+	}
+	if b.Version < 1 || b.Version > BundleVersion {
+		return fmt.Errorf("%w: version %d is not supported by this qui (max %d)", ErrInvalidBundle, b.Version, BundleVersion)
+	}
+	for _, table := range b.Tables {
+		for _, row := range table.Rows {
+			if len(row) != len(table.Columns) {
+				return fmt.Errorf("%w: table %s has a row with %d values for %d columns", ErrInvalidBundle, table.Name, len(row), len(table.Columns))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package configbackup
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/database"
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/testutil/testdb"
+)
+
+var (
+	sourceKey = bytes.Repeat([]byte{1}, 32)
+	targetKey = bytes.Repeat([]byte{2}, 32)
+)
+
+// seedSource creates a configured install and returns its database and the
+// ID of its instance.
+func seedSource(t *testing.T) (*database.DB, int) {
+	t.Helper()
+	ctx := context.Background()
+	db := testdb.NewMigratedSQLite(t, "configbackup-source")
+
+	_, err := db.ExecContext(ctx, `INSERT INTO "user" (username, password_hash) VALUES (?, ?)`, "admin", "$2a$10$hash")
+	require.NoError(t, err)
+
+	instances, err := models.NewInstanceStore(db, sourceKey)
+	require.NoError(t, err)
+	basicUser, basicPass := "proxy", "proxy-secret"
+	instance, err := instances.Create(ctx, "Seedbox", "http://seedbox:8080", "qbit", "hunter22", &basicUser, &basicPass, false, nil)
+	require.NoError(t, err)
+
+	backupStore := models.NewBackupStore(db)
+	require.NoError(t, backupStore.SetEncryptionKey(sourceKey))
+	require.NoError(t, backupStore.UpsertSettings(ctx, &models.BackupSettings{
+		InstanceID: instance.ID,
+		Enabled:    true,
+		Destination: &models.BackupDestination{
+			Type:     models.BackupDestinationWebDAV,
+			Endpoint: "https://dav.example",
+			Username: "dav",
+			Password: "dav-secret",
+		},
+		Encryption: &models.BackupEncryption{Mode: models.BackupEncryptionPassphrase, Passphrase: "backup passphrase"},
+	}))
+
+	_, err = models.NewNotificationTargetStore(db).Create(ctx, &models.NotificationTargetCreate{
+		Name:    "Discord",
+		URL:     "discord://token@channel",
+		Enabled: true,
+	})
+	require.NoError(t, err)
+
+	_, err = models.NewAutomationStore(db).Create(ctx, &models.Automation{
+		InstanceID: instance.ID,
+		Name:       "Announce",
+		Enabled:    true,
+		Conditions: &models.ActionConditions{Webhook: &models.WebhookAction{
+			Enabled: true,
+			URL:     "https://hooks.example/webhook-token",
+			Headers: map[string]string{"Authorization": "Bearer header-token"},
+		}},
+	})
+	require.NoError(t, err)
+
+	return db, instance.ID
+}
+
+func TestExportRestoreRoundTrip(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	source, instanceID := seedSource(t)
+	bundle, err := Export(ctx, source, sourceKey, ExportOptions{AppVersion: "v1.2.3", IncludeSecrets: true})
+	require.NoError(t, err)
+	require.True(t, bundle.Secrets)
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, bundle, "bundle passphrase"))
+	require.True(t, bytes.HasPrefix(buf.Bytes(), []byte(ageHeader)))
+	require.NotContains(t, buf.String(), "hunter22")
+
+	_, err = Decode(bytes.NewReader(buf.Bytes()), "")
+	require.ErrorIs(t, err, ErrPassphraseRequired)
+	_, err = Decode(bytes.NewReader(buf.Bytes()), "wrong passphrase")
+	require.ErrorIs(t, err, ErrWrongPassphrase)
+	decoded, err := Decode(bytes.NewReader(buf.Bytes()), "bundle passphrase")
+	require.NoError(t, err)
+
+	target := testdb.NewMigratedSQLite(t, "configbackup-target")
+	result, err := Restore(ctx, target, targetKey, decoded, RestoreOptions{})
+	require.NoError(t, err)
+	require.Empty(t, result.Warnings)
+
+	instances, err := models.NewInstanceStore(target, targetKey)
+	require.NoError(t, err)
+	instance, err := instances.Get(ctx, instanceID)
+	require.NoError(t, err)
+	require.Equal(t, "Seedbox", instance.Name)
+	require.Equal(t, "http://seedbox:8080", instance.Host)
+	password, err := instances.GetDecryptedPassword(instance)
+	require.NoError(t, err)
+	require.Equal(t, "hunter22", password)
+	basicPassword, err := instances.GetDecryptedBasicPassword(instance)
+	require.NoError(t, err)
+	require.Equal(t, "proxy-secret", *basicPassword)
+
+	backupStore := models.NewBackupStore(target)
+	require.NoError(t, backupStore.SetEncryptionKey(targetKey))
+	settings, err := backupStore.GetSettings(ctx, instanceID)
+	require.NoError(t, err)
+	require.Equal(t, "dav-secret", settings.Destination.Password)
+	require.Equal(t, "backup passphrase", settings.Encryption.Passphrase)
+
+	targets, err := models.NewNotificationTargetStore(target).List(ctx)
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	require.Equal(t, "discord://token@channel", targets[0].URL)
+
+	automations, err := models.NewAutomationStore(target).ListByInstance(ctx, instanceID)
+	require.NoError(t, err)
+	require.Len(t, automations, 1)
+	require.Equal(t, "https://hooks.example/webhook-token", automations[0].Conditions.Webhook.URL)
+	require.Equal(t, "Bearer header-token", automations[0].Conditions.Webhook.Headers["Authorization"])
+
+	// New rows must not collide with the restored IDs.
+	second, err := instances.Create(ctx, "Second", "http://second:8080", "qbit", "pw", nil, nil, false, nil)
+	require.NoError(t, err)
+	require.Greater(t, second.ID, instanceID)
+
+	_, err = Restore(ctx, target, targetKey, decoded, RestoreOptions{})
+	require.ErrorIs(t, err, ErrNotEmpty)
+	_, err = Restore(ctx, target, targetKey, decoded, RestoreOptions{Force: true})
+	require.NoError(t, err)
+	list, err := instances.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+}
+
+func TestExportWithoutSecrets(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	source, instanceID := seedSource(t)
+	bundle, err := Export(ctx, source, sourceKey, ExportOptions{})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, bundle, ""))
+	require.NotContains(t, buf.String(), "hunter22")
+	require.NotContains(t, buf.String(), "dav-secret")
+	require.NotContains(t, buf.String(), "discord://token")
+	require.NotContains(t, buf.String(), "webhook-token")
+	require.NotContains(t, buf.String(), "header-token")
+	decoded, err := Decode(&buf, "")
+	require.NoError(t, err)
+
+	target := testdb.NewMigratedSQLite(t, "configbackup-redacted")
+	result, err := Restore(ctx, target, targetKey, decoded, RestoreOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 4)
+
+	instances, err := models.NewInstanceStore(target, targetKey)
+	require.NoError(t, err)
+	instance, err := instances.Get(ctx, instanceID)
+	require.NoError(t, err)
+	password, err := instances.GetDecryptedPassword(instance)
+	require.NoError(t, err)
+	require.Empty(t, password)
+
+	backupStore := models.NewBackupStore(target)
+	require.NoError(t, backupStore.SetEncryptionKey(targetKey))
+	settings, err := backupStore.GetSettings(ctx, instanceID)
+	require.NoError(t, err)
+	require.Equal(t, "dav", settings.Destination.Username)
+	require.Empty(t, settings.Destination.Password)
+	require.Nil(t, settings.Encryption)
+
+	targets, err := models.NewNotificationTargetStore(target).List(ctx)
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	require.Empty(t, targets[0].URL)
+
+	automations, err := models.NewAutomationStore(target).ListByInstance(ctx, instanceID)
+	require.NoError(t, err)
+	require.Len(t, automations, 1)
+	webhook := automations[0].Conditions.Webhook
+	require.True(t, webhook.Enabled)
+	require.Empty(t, webhook.URL)
+	require.Empty(t, webhook.Headers)
+}
+
+func TestDecodeRejectsNewerBundles(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, &Bundle{Format: BundleFormat, Version: BundleVersion + 1}, ""))
+	_, err := Decode(&buf, "")
+	require.ErrorIs(t, err, ErrInvalidBundle)
+}
+
+func TestPruneKeepsNewestBundles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 4 {
+		name := FileName(start.Add(time.Duration(i)*time.Hour), i%2 == 1)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o600))
+
+	removed, err := Prune(dir, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, removed)
+
+	paths, err := ListFiles(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "qui-config-20260101T030000Z.json.age"),
+		filepath.Join(dir, "qui-config-20260101T020000Z.json"),
+	}, paths)
+	last, ok := fileTime(paths[0])
+	require.True(t, ok)
+	require.Equal(t, start.Add(3*time.Hour), last)
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package configbackup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// stringPoolBatch keeps ID lookups under SQLite's parameter limit.
+const stringPoolBatch = 900
+
+// ExportOptions controls what Export puts in a bundle.
+type ExportOptions struct {
+	AppVersion string
+	// IncludeSecrets puts passwords, API keys and notification URLs in the
+	// bundle in plaintext. Only set it for bundles that will be encrypted;
+	// otherwise they are redacted and have to be entered again after a
+	// restore.
+	IncludeSecrets bool
+}
+
+type column struct {
+	name string
+	typ  string
+}
+
+// Export reads every configuration table in one read-only transaction, so
+// the bundle is a consistent snapshot even while qui is running. key is the
+// install's encryption key, used to decrypt secrets.
+func Export(ctx context.Context, db dbinterface.Querier, key []byte, opts ExportOptions) (*Bundle, error) {
+	box, err := newSecretBox(key)
+	if err != nil {
+		return nil, err
+	}
+
+	dialect := dbinterface.DialectOf(db)
+	txOpts := &sql.TxOptions{ReadOnly: true}
+	if dialect == "postgres" {
+		txOpts.Isolation = sql.LevelRepeatableRead
+	}
+	tx, err := db.BeginTx(ctx, txOpts)
+	if err != nil {
+		return nil, fmt.Errorf("begin export transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	bundle := &Bundle{
+		Format:     BundleFormat,
+		Version:    BundleVersion,
+		AppVersion: opts.AppVersion,
+		Engine:     dialect,
+		CreatedAt:  time.Now().UTC(),
+		Secrets:    opts.IncludeSecrets,
+		Tables:     make([]Table, 0, len(tables)),
+	}
+	for _, spec := range tables {
+		table, err := exportTable(ctx, tx, dialect, spec, box, opts.IncludeSecrets)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", spec.name, err)
+		}
+		if table != nil {
+			bundle.Tables = append(bundle.Tables, *table)
+		}
+	}
+	return bundle, nil
+}
+
+// exportTable returns the rows of one table, or nil if the database has no
+// such table.
+func exportTable(ctx context.Context, tx dbinterface.TxQuerier, dialect string, spec tableSpec, box *secretBox, includeSecrets bool) (*Table, error) {
+	columns, err := tableColumns(ctx, tx, dialect, spec.name)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, len(columns))
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+		quoted[i] = quoteIdent(c.name)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY 1", strings.Join(quoted, ", "), quoteIdent(spec.name))
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	table := &Table{Name: spec.name, Columns: names, Rows: [][]any{}}
+	for rows.Next() {
+		raw := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range raw {
+			dest[i] = &raw[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i := range raw {
+			raw[i] = exportValue(raw[i])
+		}
+		table.Rows = append(table.Rows, raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := resolvePooled(ctx, tx, spec, table); err != nil {
+		return nil, err
+	}
+
+	for i, name := range table.Columns {
+		var transform func(any) (any, error)
+		switch {
+		case slices.Contains(spec.secrets, name), slices.Contains(spec.documents, name):
+			transform = func(v any) (any, error) { return box.exportSecret(v, includeSecrets) }
+		case spec.jsonSecrets[name] != nil:
+			fields := spec.jsonSecrets[name]
+			transform = func(v any) (any, error) { return box.exportJSONSecrets(v, fields, includeSecrets) }
+		case slices.Contains(spec.plainSecrets, name):
+			transform = func(v any) (any, error) { return exportPlainSecret(v, includeSecrets), nil }
+		case spec.plainJSONSecrets[name] != nil:
+			paths := spec.plainJSONSecrets[name]
+			transform = func(v any) (any, error) { return exportJSONPaths(v, paths, includeSecrets) }
+		default:
+			continue
+		}
+		for _, row := range table.Rows {
+			if row[i], err = transform(row[i]); err != nil {
+				return nil, fmt.Errorf("decrypt %s: %w", name, err)
+			}
+		}
+	}
+	return table, nil
+}
+
+// resolvePooled replaces the string_pool IDs in table with their strings.
+func resolvePooled(ctx context.Context, tx dbinterface.TxQuerier, spec tableSpec, table *Table) error {
+	var indexes []int
+	for i, name := range table.Columns {
+		if slices.Contains(spec.pooled, name) {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 || len(table.Rows) == 0 {
+		return nil
+	}
+
+	seen := make(map[int64]struct{})
+	var ids []int64
+	for _, row := range table.Rows {
+		for _, i := range indexes {
+			id, ok := row[i].(int64)
+			if !ok {
+				continue
+			}
+			if _, dup := seen[id]; !dup {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	values, err := lookupStrings(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+	for _, row := range table.Rows {
+		for _, i := range indexes {
+			if row[i] == nil {
+				continue
+			}
+			id, ok := row[i].(int64)
+			if !ok {
+				return fmt.Errorf("column %s: unexpected string_pool ID %v", table.Columns[i], row[i])
+			}
+			value, ok := values[id]
+			if !ok {
+				return fmt.Errorf("column %s: string_pool ID %d not found", table.Columns[i], id)
+			}
+			row[i] = value
+		}
+	}
+	return nil
+}
+
+func lookupStrings(ctx context.Context, tx dbinterface.TxQuerier, ids []int64) (map[int64]string, error) {
+	values := make(map[int64]string, len(ids))
+	for start := 0; start < len(ids); start += stringPoolBatch {
+		chunk := ids[start:min(start+stringPoolBatch, len(ids))]
+		args := make([]any, len(chunk))
+		for i, id := range chunk {
+			args[i] = id
+		}
+		query := "SELECT id, value FROM string_pool WHERE id IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(chunk)), ", ") + ")"
+
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("look up pooled strings: %w", err)
+		}
+		for rows.Next() {
+			var id int64
+			var value string
+			if err := rows.Scan(&id, &value); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			values[id] = value
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// exportValue normalizes driver values to what both engines accept back and
+// JSON can carry.
+func exportValue(v any) any {
+	switch value := v.(type) {
+	case []byte:
+		return string(value)
+	case bool:
+		if value {
+			return int64(1)
+		}
+		return int64(0)
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// tableColumns lists the columns of table in declaration order, or none if
+// it doesn't exist.
+func tableColumns(ctx context.Context, q dbinterface.TxQuerier, dialect, table string) ([]column, error) {
+	query := "SELECT name, type FROM pragma_table_info(?)"
+	if dialect == "postgres" {
+		query = `
+			SELECT column_name, data_type
+			FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ?
+			ORDER BY ordinal_position
+		`
+	}
+
+	rows, err := q.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, fmt.Errorf("list columns: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []column
+	for rows.Next() {
+		var c column
+		if err := rows.Scan(&c.name, &c.typ); err != nil {
+			return nil, fmt.Errorf("list columns: %w", err)
+		}
+		c.typ = strings.ToUpper(c.typ)
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
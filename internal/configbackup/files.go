@@ -0,0 +1,125 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package configbackup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	filePrefix    = "qui-config-"
+	fileSuffix    = ".json"
+	fileAgeSuffix = ".json.age"
+	fileTimestamp = "20060102T150405Z"
+)
+
+// FileName returns the name bundles created at t are saved under.
+func FileName(t time.Time, encrypted bool) string {
+	suffix := fileSuffix
+	if encrypted {
+		suffix = fileAgeSuffix
+	}
+	return filePrefix + t.UTC().Format(fileTimestamp) + suffix
+}
+
+// WriteFile saves b to path, encrypted with passphrase when one is given.
+// The file is written next to path first and renamed into place, so a
+// crash never leaves a truncated bundle behind. Bundles can hold secrets,
+// so they are only readable by their owner.
+func WriteFile(path string, b *Bundle, passphrase string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create bundle directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".qui-config-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create bundle file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("create bundle file: %w", err)
+	}
+	if err := Encode(tmp, b, passphrase); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write bundle file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write bundle file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write bundle file: %w", err)
+	}
+	return nil
+}
+
+// ReadFile loads a bundle saved by WriteFile.
+func ReadFile(path, passphrase string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(f, passphrase)
+}
+
+// ListFiles returns the bundles saved in dir under FileName, newest first.
+func ListFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) {
+			continue
+		}
+		if strings.HasSuffix(name, fileSuffix) || strings.HasSuffix(name, fileAgeSuffix) {
+			names = append(names, name)
+		}
+	}
+	// The timestamp sorts lexically; the suffix only breaks ties.
+	slices.SortFunc(names, func(a, b string) int { return strings.Compare(b, a) })
+
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dir, name)
+	}
+	return paths, nil
+}
+
+// Prune deletes all but the newest keep bundles in dir. keep <= 0 keeps all.
+func Prune(dir string, keep int) (int, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+	paths, err := ListFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, path := range paths[min(keep, len(paths)):] {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
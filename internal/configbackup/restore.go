@@ -0,0 +1,325 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package configbackup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+	"github.com/autobrr/qui/internal/domain"
+)
+
+// ErrNotEmpty is returned when restoring into a database that already has a
+// user or instances, unless RestoreOptions.Force is set.
+var ErrNotEmpty = errors.New("database already has a qui configuration")
+
+// RestoreOptions controls Restore.
+type RestoreOptions struct {
+	// Force replaces the configuration of a database that already has one.
+	// Deleting its instances also deletes their history, such as backup
+	// runs and automation activity.
+	Force bool
+}
+
+// RestoreResult reports what Restore wrote.
+type RestoreResult struct {
+	Tables   []TableResult
+	Warnings []string
+}
+
+// TableResult is the number of rows restored into one table.
+type TableResult struct {
+	Name string
+	Rows int
+}
+
+// Restore replaces the configuration tables of db with the contents of b in
+// one transaction. Rows keep their IDs, so references between them stay
+// valid. Secrets are encrypted with key, the target install's encryption
+// key. Tables the bundle doesn't have are left alone, and columns this
+// database doesn't have are skipped, so bundles from other qui versions
+// restore as far as they can.
+func Restore(ctx context.Context, db dbinterface.Querier, key []byte, b *Bundle, opts RestoreOptions) (*RestoreResult, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	box, err := newSecretBox(key)
+	if err != nil {
+		return nil, err
+	}
+
+	dialect := dbinterface.DialectOf(db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin restore transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := dbinterface.DeferForeignKeyChecks(ctx, tx); err != nil {
+		return nil, fmt.Errorf("defer foreign key checks: %w", err)
+	}
+
+	if !opts.Force {
+		configured, err := hasConfiguration(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		if configured {
+			return nil, ErrNotEmpty
+		}
+	}
+
+	result := &RestoreResult{}
+
+	// Pair the bundle's tables with this database's, in restore order.
+	type restoreTable struct {
+		spec    tableSpec
+		data    Table
+		columns map[string]string
+	}
+	var plan []restoreTable
+	for _, spec := range tables {
+		idx := slices.IndexFunc(b.Tables, func(t Table) bool { return t.Name == spec.name })
+		if idx < 0 {
+			continue
+		}
+		columns, err := tableColumns(ctx, tx, dialect, spec.name)
+		if err != nil {
+			return nil, fmt.Errorf("restore %s: %w", spec.name, err)
+		}
+		if len(columns) == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Skipped table %s: it does not exist in this database", spec.name))
+			continue
+		}
+		types := make(map[string]string, len(columns))
+		for _, c := range columns {
+			types[c.name] = c.typ
+		}
+		plan = append(plan, restoreTable{spec: spec, data: b.Tables[idx], columns: types})
+	}
+	for _, table := range b.Tables {
+		if _, ok := lookupTable(table.Name); !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Skipped table %s: this version of qui does not restore it", table.Name))
+		}
+	}
+
+	// Children first, so nothing is left pointing at a deleted row.
+	for i := len(plan) - 1; i >= 0; i-- {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+quoteIdent(plan[i].spec.name)); err != nil {
+			return nil, fmt.Errorf("clear %s: %w", plan[i].spec.name, err)
+		}
+	}
+
+	var pooled []string
+	for _, t := range plan {
+		for i, name := range t.data.Columns {
+			if !slices.Contains(t.spec.pooled, name) {
+				continue
+			}
+			for _, row := range t.data.Rows {
+				if s, ok := row[i].(string); ok {
+					pooled = append(pooled, s)
+				}
+			}
+		}
+	}
+	poolIDs, err := internStrings(ctx, tx, pooled)
+	if err != nil {
+		return nil, err
+	}
+
+	withheld := !b.Secrets
+	for _, t := range plan {
+		rows, redacted, err := restoreTableRows(ctx, tx, box, t.spec, t.data, t.columns, poolIDs, withheld)
+		if err != nil {
+			return nil, fmt.Errorf("restore %s: %w", t.spec.name, err)
+		}
+		result.Tables = append(result.Tables, TableResult{Name: t.spec.name, Rows: rows})
+		if redacted > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %d secret(s) were not in the bundle and must be entered again", t.spec.name, redacted))
+		}
+		for _, name := range t.data.Columns {
+			if _, ok := t.columns[name]; !ok {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Skipped column %s.%s: it does not exist in this database", t.spec.name, name))
+			}
+		}
+
+		if _, ok := t.columns["id"]; ok && dialect == "postgres" {
+			if err := resetIdentity(ctx, tx, t.spec.name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit restore: %w", err)
+	}
+	return result, nil
+}
+
+func hasConfiguration(ctx context.Context, tx dbinterface.TxQuerier) (bool, error) {
+	for _, table := range []string{"user", "instances"} {
+		var count int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+quoteIdent(table)).Scan(&count); err != nil {
+			return false, fmt.Errorf("check %s: %w", table, err)
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// internStrings adds values to this database's string pool and maps them to
+// their IDs.
+func internStrings(ctx context.Context, tx dbinterface.TxQuerier, values []string) (map[string]int64, error) {
+	ids := make(map[string]int64, len(values))
+	nonEmpty := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			nonEmpty = append(nonEmpty, v)
+			continue
+		}
+		if _, ok := ids[""]; ok {
+			continue
+		}
+		id, err := dbinterface.InternEmptyString(ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("intern pooled strings: %w", err)
+		}
+		ids[""] = id
+	}
+
+	interned, err := dbinterface.InternStrings(ctx, tx, nonEmpty...)
+	if err != nil {
+		return nil, fmt.Errorf("intern pooled strings: %w", err)
+	}
+	for i, v := range nonEmpty {
+		ids[v] = interned[i]
+	}
+	return ids, nil
+}
+
+// restoreTableRows inserts the rows of one table and returns how many it
+// wrote and how many secrets were withheld from them.
+func restoreTableRows(ctx context.Context, tx dbinterface.TxQuerier, box *secretBox, spec tableSpec, data Table, types map[string]string, poolIDs map[string]int64, withheld bool) (rows, redacted int, err error) {
+	var (
+		indexes []int
+		quoted  []string
+	)
+	for i, name := range data.Columns {
+		if _, ok := types[name]; ok {
+			indexes = append(indexes, i)
+			quoted = append(quoted, quoteIdent(name))
+		}
+	}
+	if len(indexes) == 0 || len(data.Rows) == 0 {
+		return 0, 0, nil
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		quoteIdent(spec.name),
+		strings.Join(quoted, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(indexes)), ", "))
+
+	for _, row := range data.Rows {
+		args := make([]any, len(indexes))
+		for j, i := range indexes {
+			name := data.Columns[i]
+			value, wasRedacted, err := restoreCell(box, spec, name, row[i], poolIDs, withheld)
+			if err != nil {
+				return 0, 0, fmt.Errorf("column %s: %w", name, err)
+			}
+			if wasRedacted {
+				redacted++
+			}
+			if args[j], err = restoreValue(value, types[name]); err != nil {
+				return 0, 0, fmt.Errorf("column %s: %w", name, err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return 0, 0, err
+		}
+		rows++
+	}
+	return rows, redacted, nil
+}
+
+// restoreCell maps a bundle value back to what the column stores: pooled
+// strings to IDs and plaintext secrets to this install's ciphertext.
+func restoreCell(box *secretBox, spec tableSpec, name string, v any, poolIDs map[string]int64, withheld bool) (any, bool, error) {
+	switch {
+	case slices.Contains(spec.pooled, name):
+		if v == nil {
+			return nil, false, nil
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, false, fmt.Errorf("expected a string, got %T", v)
+		}
+		return poolIDs[s], false, nil
+	case slices.Contains(spec.secrets, name):
+		return box.restoreSecret(v, withheld)
+	case slices.Contains(spec.documents, name):
+		if s, ok := v.(string); ok && withheld && s == domain.RedactedStr {
+			return nil, true, nil
+		}
+		return box.restoreSecret(v, withheld)
+	case spec.jsonSecrets[name] != nil:
+		return box.restoreJSONSecrets(v, spec.jsonSecrets[name], withheld)
+	case slices.Contains(spec.plainSecrets, name):
+		out, redacted := restorePlainSecret(v, withheld)
+		return out, redacted, nil
+	case spec.plainJSONSecrets[name] != nil:
+		return restoreJSONPaths(v, spec.plainJSONSecrets[name], withheld)
+	default:
+		return v, false, nil
+	}
+}
+
+// restoreValue converts a decoded JSON value for a column of type typ.
+func restoreValue(v any, typ string) (any, error) {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i, nil
+		}
+		return value.Float64()
+	case bool:
+		if value {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case string:
+		if value != "" && (strings.Contains(typ, "TIMESTAMP") || strings.Contains(typ, "DATE")) {
+			if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				return t, nil
+			}
+		}
+		return value, nil
+	default:
+		return v, nil
+	}
+}
+
+// resetIdentity moves a Postgres identity sequence past the restored IDs.
+func resetIdentity(ctx context.Context, tx dbinterface.TxQuerier, table string) error {
+	query := fmt.Sprintf(`
+		SELECT setval(
+			pg_get_serial_sequence(?, 'id'),
+			COALESCE((SELECT MAX("id") FROM %s), 0) + 1,
+			false
+		)
+	`, quoteIdent(table))
+	if _, err := tx.ExecContext(ctx, query, quoteIdent(table)); err != nil {
+		return fmt.Errorf("reset identity for %s: %w", table, err)
+	}
+	return nil
+}
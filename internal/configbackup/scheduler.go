@@ -0,0 +1,150 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package configbackup
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+const (
+	scheduleCheckInterval = 15 * time.Minute
+	scheduleStartDelay    = time.Minute
+)
+
+// Schedule is when and how the scheduler saves bundles.
+type Schedule struct {
+	// Interval between bundles; zero disables the scheduler.
+	Interval time.Duration
+	// Keep is the number of bundles to keep; zero keeps all.
+	Keep int
+	// Passphrase encrypts the bundles and lets them carry secrets.
+	Passphrase string
+}
+
+// Scheduler saves a bundle to a directory at a fixed interval. It goes by
+// the newest bundle in the directory rather than an in-memory timer, so
+// restarts don't delay or repeat runs.
+type Scheduler struct {
+	db         dbinterface.Querier
+	key        []byte
+	dir        string
+	appVersion string
+
+	mu       sync.Mutex
+	schedule Schedule
+}
+
+// NewScheduler returns a scheduler that saves bundles of db to dir.
+func NewScheduler(db dbinterface.Querier, key []byte, dir, appVersion string, schedule Schedule) *Scheduler {
+	return &Scheduler{db: db, key: key, dir: dir, appVersion: appVersion, schedule: schedule}
+}
+
+// SetSchedule replaces the schedule, e.g. after a config reload.
+func (s *Scheduler) SetSchedule(schedule Schedule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedule = schedule
+}
+
+func (s *Scheduler) currentSchedule() Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.schedule
+}
+
+// Start runs the scheduler until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		timer := time.NewTimer(scheduleStartDelay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			s.runIfDue(ctx)
+		}
+
+		ticker := time.NewTicker(scheduleCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.runIfDue(ctx)
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) runIfDue(ctx context.Context) {
+	schedule := s.currentSchedule()
+	if schedule.Interval <= 0 {
+		return
+	}
+
+	paths, err := ListFiles(s.dir)
+	if err != nil {
+		log.Error().Err(err).Str("dir", s.dir).Msg("Config backup: failed to list bundles")
+		return
+	}
+	if len(paths) > 0 {
+		if last, ok := fileTime(paths[0]); ok && time.Since(last) < schedule.Interval {
+			return
+		}
+	}
+
+	path, err := s.Run(ctx, schedule)
+	if err != nil {
+		log.Error().Err(err).Msg("Config backup: scheduled export failed")
+		return
+	}
+	log.Info().Str("path", path).Bool("encrypted", schedule.Passphrase != "").Msg("Config backup: saved configuration bundle")
+}
+
+// Run saves one bundle now and prunes old ones, returning its path.
+func (s *Scheduler) Run(ctx context.Context, schedule Schedule) (string, error) {
+	if schedule.Passphrase != "" && len(schedule.Passphrase) < MinPassphraseLength {
+		return "", fmt.Errorf("passphrase must be at least %d characters", MinPassphraseLength)
+	}
+
+	bundle, err := Export(ctx, s.db, s.key, ExportOptions{
+		AppVersion:     s.appVersion,
+		IncludeSecrets: schedule.Passphrase != "",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(s.dir, FileName(bundle.CreatedAt, schedule.Passphrase != ""))
+	if err := WriteFile(path, bundle, schedule.Passphrase); err != nil {
+		return "", err
+	}
+
+	if removed, err := Prune(s.dir, schedule.Keep); err != nil {
+		log.Warn().Err(err).Str("dir", s.dir).Msg("Config backup: failed to prune old bundles")
+	} else if removed > 0 {
+		log.Debug().Int("removed", removed).Msg("Config backup: pruned old bundles")
+	}
+	return path, nil
+}
+
+// fileTime parses the creation time out of a bundle's file name.
+func fileTime(path string) (time.Time, bool) {
+	name := strings.TrimPrefix(filepath.Base(path), filePrefix)
+	name = strings.TrimSuffix(strings.TrimSuffix(name, fileAgeSuffix), fileSuffix)
+	t, err := time.Parse(fileTimestamp, name)
+	return t, err == nil
+}
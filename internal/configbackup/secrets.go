@@ -0,0 +1,276 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package configbackup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/autobrr/qui/internal/domain"
+)
+
+// secretBox encrypts and decrypts column values the way the stores do:
+// AES-GCM under the install's key, base64(nonce || ciphertext).
+type secretBox struct {
+	key []byte
+}
+
+func newSecretBox(key []byte) (*secretBox, error) {
+	if len(key) != 32 {
+		return nil, errors.New("encryption key must be 32 bytes")
+	}
+	return &secretBox{key: key}, nil
+}
+
+func (b *secretBox) encrypt(plaintext string) (string, error) {
+	block, err := aes.NewCipher(b.key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func (b *secretBox) decrypt(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(b.key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("malformed ciphertext")
+	}
+	nonce, ciphertextBytes := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertextBytes, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// exportSecret turns an encrypted column value into its bundle form: the
+// plaintext, or the redacted placeholder when secrets are withheld. NULL
+// and empty values are kept as they are.
+func (b *secretBox) exportSecret(v any, include bool) (any, error) {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return v, nil
+	}
+	if !include {
+		return domain.RedactedStr, nil
+	}
+	return b.decrypt(s)
+}
+
+// restoreSecret encrypts a bundle value for this install. A withheld secret
+// restores as an encrypted empty string, so it reads back as unset rather
+// than failing to decrypt. redacted reports whether that happened.
+func (b *secretBox) restoreSecret(v any, withheld bool) (_ any, redacted bool, err error) {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return v, false, nil
+	}
+	if withheld && s == domain.RedactedStr {
+		encrypted, err := b.encrypt("")
+		return encrypted, true, err
+	}
+	encrypted, err := b.encrypt(s)
+	return encrypted, false, err
+}
+
+// exportJSONSecrets decrypts, or redacts, fields of a JSON column value.
+func (b *secretBox) exportJSONSecrets(v any, fields []string, include bool) (any, error) {
+	return transformJSONFields(v, fields, func(s string) (string, error) {
+		if !include {
+			return domain.RedactedStr, nil
+		}
+		return b.decrypt(s)
+	})
+}
+
+// restoreJSONSecrets encrypts fields of a JSON column value. Withheld
+// fields are dropped.
+func (b *secretBox) restoreJSONSecrets(v any, fields []string, withheld bool) (_ any, redacted bool, err error) {
+	out, err := transformJSONFields(v, fields, func(s string) (string, error) {
+		if withheld && s == domain.RedactedStr {
+			redacted = true
+			return "", nil
+		}
+		return b.encrypt(s)
+	})
+	return out, redacted, err
+}
+
+// transformJSONFields rewrites the non-empty string fields of the JSON
+// object in v. Empty results remove the field.
+func transformJSONFields(v any, fields []string, fn func(string) (string, error)) (any, error) {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return v, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
+	}
+
+	changed := false
+	for _, field := range fields {
+		value, ok := doc[field].(string)
+		if !ok || value == "" {
+			continue
+		}
+		out, err := fn(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field, err)
+		}
+		if out == "" {
+			delete(doc, field)
+		} else {
+			doc[field] = out
+		}
+		changed = true
+	}
+	if !changed {
+		return s, nil
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// exportPlainSecret redacts a plaintext column value when secrets are
+// withheld.
+func exportPlainSecret(v any, include bool) any {
+	if s, ok := v.(string); ok && s != "" && !include {
+		return domain.RedactedStr
+	}
+	return v
+}
+
+// restorePlainSecret restores a withheld plaintext column value as an empty
+// string.
+func restorePlainSecret(v any, withheld bool) (_ any, redacted bool) {
+	if s, ok := v.(string); ok && withheld && s == domain.RedactedStr {
+		return "", true
+	}
+	return v, false
+}
+
+// exportJSONPaths redacts the strings under paths of a plaintext JSON column
+// value when secrets are withheld.
+func exportJSONPaths(v any, paths []string, include bool) (any, error) {
+	if include {
+		return v, nil
+	}
+	out, _, err := transformJSONPaths(v, paths, func(string) string { return domain.RedactedStr })
+	return out, err
+}
+
+// restoreJSONPaths drops the withheld strings under paths of a plaintext
+// JSON column value.
+func restoreJSONPaths(v any, paths []string, withheld bool) (_ any, redacted bool, err error) {
+	if !withheld {
+		return v, false, nil
+	}
+	return transformJSONPaths(v, paths, func(s string) string {
+		if s == domain.RedactedStr {
+			return ""
+		}
+		return s
+	})
+}
+
+// transformJSONPaths rewrites the non-empty strings under the dot-separated
+// paths of the JSON object in v. Empty results remove the value.
+func transformJSONPaths(v any, paths []string, fn func(string) string) (_ any, changed bool, err error) {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return v, false, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, false, fmt.Errorf("decode JSON: %w", err)
+	}
+
+	for _, path := range paths {
+		keys := strings.Split(path, ".")
+		parent := doc
+		for _, key := range keys[:len(keys)-1] {
+			if parent, ok = parent[key].(map[string]any); !ok {
+				break
+			}
+		}
+		if parent != nil && rewriteJSONValue(parent, keys[len(keys)-1], fn) {
+			changed = true
+		}
+	}
+	if !changed {
+		return s, false, nil
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, err
+	}
+	return string(data), true, nil
+}
+
+// rewriteJSONValue applies fn to parent[key], or to every string inside it
+// when it is an object, and reports whether anything changed.
+func rewriteJSONValue(parent map[string]any, key string, fn func(string) string) bool {
+	switch value := parent[key].(type) {
+	case string:
+		out := fn(value)
+		if value == "" || out == value {
+			return false
+		}
+		if out == "" {
+			delete(parent, key)
+		} else {
+			parent[key] = out
+		}
+		return true
+	case map[string]any:
+		changed := false
+		for name := range value {
+			if rewriteJSONValue(value, name, fn) {
+				changed = true
+			}
+		}
+		return changed
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package configbackup
+
+// tableSpec describes one configuration table in a bundle.
+type tableSpec struct {
+	name string
+	// pooled columns hold string_pool IDs. Bundles carry the strings, since
+	// IDs differ between databases.
+	pooled []string
+	// secrets are columns encrypted with the install's key.
+	secrets []string
+	// documents are columns holding a JSON document encrypted as a whole.
+	// Unlike secrets, a withheld document restores as NULL.
+	documents []string
+	// jsonSecrets are JSON columns in which only the listed fields are
+	// encrypted.
+	jsonSecrets map[string][]string
+	// plainSecrets are plaintext columns that carry credentials, such as
+	// notification URLs. Withheld ones restore empty.
+	plainSecrets []string
+	// plainJSONSecrets are plaintext JSON columns in which the listed
+	// dot-separated paths carry credentials. A path ending at an object
+	// covers every string in it.
+	plainJSONSecrets map[string][]string
+}
+
+// tables lists the configuration tables in restore order: every table comes
+// after the tables it references. Run history, caches, statistics and
+// sessions are not configuration and are left out.
+var tables = []tableSpec{
+	{name: "user"},
+	{name: "api_keys", pooled: []string{"name_id"}},
+	{
+		name:    "instances",
+		pooled:  []string{"name_id", "host_id", "username_id", "basic_username_id"},
+		secrets: []string{"password_encrypted", "api_key_encrypted", "basic_password_encrypted"},
+	},
+	{name: "virtual_instances"},
+	{name: "client_api_keys", pooled: []string{"client_name_id"}},
+	{name: "instance_reannounce_settings"},
+	{name: "instance_crossseed_completion_settings"},
+	{
+		name:        "instance_backup_settings",
+		documents:   []string{"encryption_json"},
+		jsonSecrets: map[string][]string{"destination_json": {"password", "privateKey"}},
+	},
+	{
+		name:    "arr_instances",
+		pooled:  []string{"name_id", "base_url_id", "basic_username_id"},
+		secrets: []string{"api_key_encrypted", "basic_password_encrypted"},
+	},
+	{
+		name:    "torznab_indexers",
+		pooled:  []string{"name_id", "base_url_id", "indexer_id_string_id", "basic_username_id"},
+		secrets: []string{"api_key_encrypted", "basic_password_encrypted"},
+	},
+	{name: "torznab_indexer_categories", pooled: []string{"category_name_id"}},
+	{name: "torznab_indexer_capabilities", pooled: []string{"capability_type_id"}},
+	{name: "torznab_search_cache_settings"},
+	{
+		name:             "automations",
+		plainJSONSecrets: map[string][]string{"conditions": {"webhook.url", "webhook.headers"}},
+	},
+	{name: "external_programs"},
+	{
+		name: "cross_seed_settings",
+		secrets: []string{
+			"redacted_api_key_encrypted",
+			"orpheus_api_key_encrypted",
+			"season_pack_tvdb_api_key_encrypted",
+			"season_pack_tvdb_pin_encrypted",
+		},
+	},
+	{name: "cross_seed_search_settings"},
+	{name: "cross_seed_blocklist"},
+	{name: "dir_scan_settings"},
+	{name: "dir_scan_directories"},
+	{name: "orphan_scan_settings"},
+	{name: "notification_targets", plainSecrets: []string{"url"}},
+	{name: "tracker_customizations"},
+	{name: "tracker_requirements"},
+	{name: "preference_profiles"},
+	{name: "filter_views"},
+	{name: "dashboard_settings"},
+	{name: "theme_settings"},
+	{name: "log_exclusions"},
+	{name: "audit_settings"},
+	{name: "licenses"},
+}
+
+func lookupTable(name string) (tableSpec, bool) {
+	for _, spec := range tables {
+		if spec.name == name {
+			return spec, true
+		}
+	}
+	return tableSpec{}, false
+}
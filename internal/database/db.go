@@ -1450,6 +1450,7 @@ func (db *DB) applyAllMigrations(ctx context.Context, migrations []string) error
 	// Migrations that need foreign keys disabled due to table recreation
 	needsForeignKeysOff := map[string]bool{
 		"010_add_files_cache_and_string_interning.sql": true,
+		"099_create_virtual_instances.sql":             true,
 	}
 
 	// Begin single transaction for all migrations using BeginTx for proper connection handling
@@ -209,6 +209,7 @@ var expectedSchema = map[string][]columnSpec{
 		{Name: "hardlink_dir_preset", Type: "TEXT"},
 		{Name: "use_reflinks", Type: "BOOLEAN"},
 		{Name: "fallback_to_regular_mode", Type: "BOOLEAN"},
+		{Name: "client_type", Type: "TEXT"},
 	},
 	"licenses": {
 		{Name: "id", Type: "INTEGER", PrimaryKey: true},
@@ -232,6 +233,10 @@ var expectedSchema = map[string][]columnSpec{
 		{Name: "key_hash", Type: "TEXT"},
 		{Name: "client_name_id", Type: "INTEGER"},
 		{Name: "instance_id", Type: "INTEGER"},
+		{Name: "virtual_instance_id", Type: "INTEGER"},
+		{Name: "scope", Type: "TEXT"},
+		{Name: "add_policy", Type: "TEXT"},
+		{Name: "duplicate_policy", Type: "TEXT"},
 		{Name: "created_at", Type: "TIMESTAMP"},
 		{Name: "last_used_at", Type: "TIMESTAMP"},
 	},
@@ -279,6 +284,10 @@ var expectedSchema = map[string][]columnSpec{
 		{Name: "dry_run", Type: "INTEGER"},
 		{Name: "sort_order", Type: "INTEGER"},
 		{Name: "interval_seconds", Type: "INTEGER"},
+		{Name: "trigger_mode", Type: "TEXT"},
+		{Name: "schedule", Type: "TEXT"},
+		{Name: "targets", Type: "TEXT"},
+		{Name: "instance_overrides", Type: "TEXT"},
 		{Name: "free_space_source", Type: "TEXT"},
 		{Name: "created_at", Type: "DATETIME"},
 		{Name: "updated_at", Type: "DATETIME"},
@@ -299,24 +308,66 @@ var expectedSchema = map[string][]columnSpec{
 		{Name: "details", Type: "TEXT"},
 		{Name: "created_at", Type: "DATETIME"},
 	},
+	"audit_log": {
+		{Name: "id", Type: "INTEGER", PrimaryKey: true},
+		{Name: "created_at", Type: "TIMESTAMP"},
+		{Name: "source", Type: "TEXT"},
+		{Name: "actor_type", Type: "TEXT"},
+		{Name: "actor", Type: "TEXT"},
+		{Name: "instance_id", Type: "INTEGER"},
+		{Name: "method", Type: "TEXT"},
+		{Name: "endpoint", Type: "TEXT"},
+		{Name: "hashes", Type: "TEXT"},
+		{Name: "status", Type: "INTEGER"},
+		{Name: "success", Type: "INTEGER"},
+	},
+	"transfer_stats": {
+		{Name: "instance_id", Type: "INTEGER", PrimaryKey: true},
+		{Name: "tracker", Type: "TEXT", PrimaryKey: true},
+		{Name: "resolution", Type: "TEXT", PrimaryKey: true},
+		{Name: "bucket_start", Type: "INTEGER", PrimaryKey: true},
+		{Name: "uploaded", Type: "INTEGER"},
+		{Name: "downloaded", Type: "INTEGER"},
+		{Name: "torrent_count", Type: "INTEGER"},
+	},
+	"audit_settings": {
+		{Name: "id", Type: "INTEGER", PrimaryKey: true},
+		{Name: "enabled", Type: "INTEGER"},
+		{Name: "retention_days", Type: "INTEGER"},
+		{Name: "updated_at", Type: "TIMESTAMP"},
+	},
+	"preference_profiles": {
+		{Name: "id", Type: "INTEGER", PrimaryKey: true},
+		{Name: "name", Type: "TEXT"},
+		{Name: "description", Type: "TEXT"},
+		{Name: "preferences", Type: "TEXT"},
+		{Name: "instance_ids", Type: "TEXT"},
+		{Name: "drift_alerts", Type: "INTEGER"},
+		{Name: "created_at", Type: "TIMESTAMP"},
+		{Name: "updated_at", Type: "TIMESTAMP"},
+	},
 }
 
 var expectedIndexes = map[string][]string{
 	"instances":           {"idx_instances_sort_order", "idx_instances_is_active"},
 	"licenses":            {"idx_licenses_status", "idx_licenses_theme", "idx_licenses_key"},
-	"client_api_keys":     {"idx_client_api_keys_instance_id"},
+	"client_api_keys":     {"idx_client_api_keys_instance_id", "idx_client_api_keys_virtual_instance_id"},
 	"instance_errors":     {"idx_instance_errors_lookup"},
 	"sessions":            {"sessions_expiry_idx"},
 	"torrent_files_cache": {"idx_torrent_files_cache_lookup", "idx_torrent_files_cache_cached_at"},
 	"torrent_files_sync":  {"idx_torrent_files_sync_last_synced"},
 	"automations":         {"idx_automations_instance"},
 	"automation_activity": {"idx_automation_activity_instance_created"},
+	"audit_log":           {"idx_audit_log_created_at", "idx_audit_log_instance_id"},
+	"transfer_stats":      {"idx_transfer_stats_bucket"},
 }
 
 var expectedTriggers = []string{
 	"update_user_updated_at",
 	"cleanup_old_instance_errors",
 	"trg_automations_updated",
+	"trg_audit_settings_updated",
+	"trg_preference_profiles_updated",
 }
 
 func listMigrationFiles(t *testing.T) []string {
@@ -0,0 +1,8 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Add per-rule trigger mode for automations.
+-- 'interval' keeps the existing timer-based behavior; event modes
+-- ('onAdded', 'onCompleted', 'onStateChange') run the rule immediately
+-- for the affected torrents only.
+ALTER TABLE automations ADD COLUMN trigger_mode TEXT NOT NULL DEFAULT 'interval';
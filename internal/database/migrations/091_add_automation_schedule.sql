@@ -0,0 +1,6 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Add optional schedule (cron expression, time zone and active window) for automations.
+-- NULL means run on the per-rule interval at any time of day.
+ALTER TABLE automations ADD COLUMN schedule TEXT;
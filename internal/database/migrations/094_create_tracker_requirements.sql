@@ -0,0 +1,24 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Tracker seeding requirements (hit-and-run rules). Domains use the same
+-- comma-separated lowercase form as tracker_customizations.
+CREATE TABLE IF NOT EXISTS tracker_requirements (
+    id                    INTEGER PRIMARY KEY AUTOINCREMENT,
+    name                  TEXT NOT NULL,
+    domains               TEXT NOT NULL,
+    enabled               INTEGER NOT NULL DEFAULT 1,
+    min_ratio             REAL NOT NULL DEFAULT 0,
+    min_seed_time_minutes INTEGER NOT NULL DEFAULT 0,
+    grace_period_minutes  INTEGER NOT NULL DEFAULT 0,
+    require_all           INTEGER NOT NULL DEFAULT 0,
+    category_overrides    TEXT NOT NULL DEFAULT '[]',
+    created_at            TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at            TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER IF NOT EXISTS trg_tracker_requirements_updated
+AFTER UPDATE ON tracker_requirements
+BEGIN
+    UPDATE tracker_requirements SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,22 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Per-torrent match streaks for automation rules that use history fields
+-- (RULE_MATCH_STREAK, CONDITION_TRUE_FOR). A row exists only while the torrent
+-- keeps matching; signature identifies the conditions the streak was counted
+-- against so edited rules start over.
+CREATE TABLE IF NOT EXISTS automation_observations (
+    automation_id    INTEGER NOT NULL,
+    instance_id      INTEGER NOT NULL,
+    torrent_hash     TEXT NOT NULL,
+    signature        TEXT NOT NULL,
+    first_matched_at DATETIME NOT NULL,
+    last_matched_at  DATETIME NOT NULL,
+    match_count      INTEGER NOT NULL DEFAULT 1,
+    FOREIGN KEY (automation_id) REFERENCES automations(id) ON DELETE CASCADE,
+    FOREIGN KEY (instance_id) REFERENCES instances(id) ON DELETE CASCADE,
+    PRIMARY KEY (automation_id, instance_id, torrent_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_automation_observations_instance ON automation_observations(instance_id);
+CREATE INDEX IF NOT EXISTS idx_automation_observations_last_matched ON automation_observations(last_matched_at);
@@ -0,0 +1,66 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Virtual instances group several qBittorrent instances behind one proxy API.
+-- member_instance_ids and category_instances are JSON.
+CREATE TABLE IF NOT EXISTS virtual_instances (
+    id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+    name                TEXT NOT NULL UNIQUE,
+    member_instance_ids TEXT NOT NULL DEFAULT '[]',
+    placement           TEXT NOT NULL DEFAULT 'round_robin',
+    category_instances  TEXT NOT NULL DEFAULT '{}',
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER IF NOT EXISTS trg_virtual_instances_updated
+AFTER UPDATE ON virtual_instances
+BEGIN
+    UPDATE virtual_instances SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Client API keys target either an instance or a virtual instance. SQLite can't
+-- relax NOT NULL in place, so rebuild the table.
+DROP VIEW IF EXISTS client_api_keys_view;
+
+CREATE TABLE client_api_keys_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    key_hash TEXT NOT NULL UNIQUE,
+    client_name_id INTEGER NOT NULL,
+    instance_id INTEGER,
+    virtual_instance_id INTEGER,
+    scope TEXT NOT NULL DEFAULT '',
+    add_policy TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP,
+    FOREIGN KEY (instance_id) REFERENCES instances(id) ON DELETE CASCADE,
+    FOREIGN KEY (virtual_instance_id) REFERENCES virtual_instances(id) ON DELETE CASCADE,
+    FOREIGN KEY (client_name_id) REFERENCES string_pool(id) ON DELETE RESTRICT,
+    CHECK ((instance_id IS NULL) <> (virtual_instance_id IS NULL))
+);
+
+INSERT INTO client_api_keys_new (id, key_hash, client_name_id, instance_id, scope, add_policy, created_at, last_used_at)
+SELECT id, key_hash, client_name_id, instance_id, scope, add_policy, created_at, last_used_at
+FROM client_api_keys;
+
+DROP TABLE client_api_keys;
+ALTER TABLE client_api_keys_new RENAME TO client_api_keys;
+
+CREATE INDEX idx_client_api_keys_key_hash ON client_api_keys(key_hash);
+CREATE INDEX idx_client_api_keys_instance_id ON client_api_keys(instance_id);
+CREATE INDEX idx_client_api_keys_client_name_id ON client_api_keys(client_name_id);
+CREATE INDEX idx_client_api_keys_virtual_instance_id ON client_api_keys(virtual_instance_id);
+
+CREATE VIEW client_api_keys_view AS
+SELECT
+    cak.id,
+    cak.key_hash,
+    sp.value AS client_name,
+    cak.instance_id,
+    cak.virtual_instance_id,
+    cak.scope,
+    cak.add_policy,
+    cak.created_at,
+    cak.last_used_at
+FROM client_api_keys cak
+INNER JOIN string_pool sp ON cak.client_name_id = sp.id;
@@ -0,0 +1,22 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- What the proxy does when a torrents/add request made with a client API key
+-- adds a torrent that already exists on an instance. An empty string disables the check.
+ALTER TABLE client_api_keys ADD COLUMN duplicate_policy TEXT NOT NULL DEFAULT '';
+
+DROP VIEW IF EXISTS client_api_keys_view;
+CREATE VIEW client_api_keys_view AS
+SELECT
+    cak.id,
+    cak.key_hash,
+    sp.value AS client_name,
+    cak.instance_id,
+    cak.virtual_instance_id,
+    cak.scope,
+    cak.add_policy,
+    cak.duplicate_policy,
+    cak.created_at,
+    cak.last_used_at
+FROM client_api_keys cak
+INNER JOIN string_pool sp ON cak.client_name_id = sp.id;
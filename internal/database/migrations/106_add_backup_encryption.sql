@@ -0,0 +1,6 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Optional at-rest encryption key (passphrase or age identity) for an
+-- instance's backups. The whole JSON document is encrypted before storage.
+ALTER TABLE instance_backup_settings ADD COLUMN encryption_json TEXT;
@@ -0,0 +1,7 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Shared automation rules: additional target instances and per-instance path overrides.
+-- NULL targets means the rule only runs on its owning instance.
+ALTER TABLE automations ADD COLUMN IF NOT EXISTS targets TEXT;
+ALTER TABLE automations ADD COLUMN IF NOT EXISTS instance_overrides TEXT;
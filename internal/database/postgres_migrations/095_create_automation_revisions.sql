@@ -0,0 +1,20 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Revision history for automation rules. Each row stores the full rule as it
+-- was saved (snapshot) plus the field-level changes from the previous revision.
+-- History is removed together with the rule.
+CREATE TABLE IF NOT EXISTS automation_revisions (
+    id              INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    automation_id   INTEGER NOT NULL REFERENCES automations(id) ON DELETE CASCADE,
+    instance_id     INTEGER NOT NULL,
+    revision        INTEGER NOT NULL,
+    action          TEXT NOT NULL,
+    changed_by      TEXT,
+    snapshot        TEXT NOT NULL,
+    changes         TEXT,
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (automation_id, revision)
+);
+
+CREATE INDEX IF NOT EXISTS idx_automation_revisions_instance ON automation_revisions(instance_id, automation_id, revision DESC);
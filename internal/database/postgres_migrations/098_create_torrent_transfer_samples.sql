@@ -0,0 +1,18 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Sampled per-torrent transfer counters in hourly buckets (bucket_start is a
+-- unix timestamp). A row is only written when a counter changed, and each
+-- torrent keeps its newest row before the retention window as a baseline.
+-- last_upload_at is when the uploaded counter last grew, as seen by the sampler.
+CREATE TABLE IF NOT EXISTS torrent_transfer_samples (
+    instance_id    INTEGER NOT NULL REFERENCES instances(id) ON DELETE CASCADE,
+    torrent_hash   TEXT NOT NULL,
+    bucket_start   BIGINT NOT NULL,
+    uploaded       BIGINT NOT NULL,
+    downloaded     BIGINT NOT NULL,
+    last_upload_at BIGINT NOT NULL,
+    PRIMARY KEY (instance_id, torrent_hash, bucket_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_torrent_transfer_samples_bucket ON torrent_transfer_samples(instance_id, bucket_start);
@@ -0,0 +1,19 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Optional scope policy (JSON) limiting what a client API key may do through
+-- the proxy. An empty string means the key has full access.
+ALTER TABLE client_api_keys ADD COLUMN IF NOT EXISTS scope TEXT NOT NULL DEFAULT '';
+
+DROP VIEW IF EXISTS client_api_keys_view;
+CREATE VIEW client_api_keys_view AS
+SELECT
+    cak.id,
+    cak.key_hash,
+    sp.value AS client_name,
+    cak.instance_id,
+    cak.scope,
+    cak.created_at,
+    cak.last_used_at
+FROM client_api_keys cak
+INNER JOIN string_pool sp ON cak.client_name_id = sp.id;
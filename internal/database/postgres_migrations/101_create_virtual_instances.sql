@@ -0,0 +1,40 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Virtual instances group several qBittorrent instances behind one proxy API.
+-- member_instance_ids and category_instances are JSON.
+CREATE TABLE IF NOT EXISTS virtual_instances (
+    id                  INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name                TEXT NOT NULL UNIQUE,
+    member_instance_ids TEXT NOT NULL DEFAULT '[]',
+    placement           TEXT NOT NULL DEFAULT 'round_robin',
+    category_instances  TEXT NOT NULL DEFAULT '{}',
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Client API keys target either an instance or a virtual instance.
+DROP VIEW IF EXISTS client_api_keys_view;
+
+ALTER TABLE client_api_keys ALTER COLUMN instance_id DROP NOT NULL;
+ALTER TABLE client_api_keys ADD COLUMN IF NOT EXISTS virtual_instance_id INTEGER
+    REFERENCES virtual_instances(id) ON DELETE CASCADE;
+ALTER TABLE client_api_keys DROP CONSTRAINT IF EXISTS client_api_keys_target_check;
+ALTER TABLE client_api_keys ADD CONSTRAINT client_api_keys_target_check
+    CHECK ((instance_id IS NULL) <> (virtual_instance_id IS NULL));
+
+CREATE INDEX IF NOT EXISTS idx_client_api_keys_virtual_instance_id ON client_api_keys(virtual_instance_id);
+
+CREATE VIEW client_api_keys_view AS
+SELECT
+    cak.id,
+    cak.key_hash,
+    sp.value AS client_name,
+    cak.instance_id,
+    cak.virtual_instance_id,
+    cak.scope,
+    cak.add_policy,
+    cak.created_at,
+    cak.last_used_at
+FROM client_api_keys cak
+INNER JOIN string_pool sp ON cak.client_name_id = sp.id;
@@ -0,0 +1,30 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Audit trail of mutating API and proxy calls. Actors and endpoints are kept as
+-- plain text so entries outlive the users, keys and instances they reference.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id          INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    source      TEXT NOT NULL,
+    actor_type  TEXT NOT NULL,
+    actor       TEXT NOT NULL DEFAULT '',
+    instance_id INTEGER,
+    method      TEXT NOT NULL,
+    endpoint    TEXT NOT NULL,
+    hashes      TEXT NOT NULL DEFAULT '',
+    status      INTEGER NOT NULL DEFAULT 0,
+    success     INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_instance_id ON audit_log(instance_id, created_at);
+
+CREATE TABLE IF NOT EXISTS audit_settings (
+    id             INTEGER PRIMARY KEY CHECK (id = 1),
+    enabled        INTEGER NOT NULL DEFAULT 1,
+    retention_days INTEGER NOT NULL DEFAULT 90,
+    updated_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO audit_settings (id) VALUES (1) ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,20 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Transfer history per instance and tracker. Each sample is added to a minute,
+-- an hour and a day bucket (bucket_start is a unix timestamp); older minute and
+-- hour rows are pruned so long ranges are served from coarser buckets.
+-- uploaded/downloaded are the bytes transferred during the bucket and
+-- torrent_count is the newest count seen. tracker '' is the instance total.
+CREATE TABLE IF NOT EXISTS transfer_stats (
+    instance_id   INTEGER NOT NULL REFERENCES instances(id) ON DELETE CASCADE,
+    tracker       TEXT NOT NULL DEFAULT '',
+    resolution    TEXT NOT NULL,
+    bucket_start  BIGINT NOT NULL,
+    uploaded      BIGINT NOT NULL DEFAULT 0,
+    downloaded    BIGINT NOT NULL DEFAULT 0,
+    torrent_count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (instance_id, resolution, tracker, bucket_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_stats_bucket ON transfer_stats(resolution, bucket_start);
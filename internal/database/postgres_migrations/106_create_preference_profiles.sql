@@ -0,0 +1,16 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Named subsets of qBittorrent preferences that can be diffed against and
+-- pushed to instances. preferences and instance_ids are JSON; instance_ids are
+-- the instances checked for drift.
+CREATE TABLE IF NOT EXISTS preference_profiles (
+    id           INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name         TEXT NOT NULL UNIQUE,
+    description  TEXT NOT NULL DEFAULT '',
+    preferences  TEXT NOT NULL DEFAULT '{}',
+    instance_ids TEXT NOT NULL DEFAULT '[]',
+    drift_alerts INTEGER NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,6 @@
+-- Copyright (c) 2026, s0up and the autobrr contributors.
+-- SPDX-License-Identifier: GPL-2.0-or-later
+
+-- Optional offsite destination (S3, WebDAV or SFTP) that runs are uploaded to.
+-- Stored as JSON; the password and private key inside it are encrypted.
+ALTER TABLE instance_backup_settings ADD COLUMN destination_json TEXT;
@@ -50,6 +50,15 @@ type Config struct {
 	MetricsBasicAuthUsers    string `toml:"metricsBasicAuthUsers" mapstructure:"metricsBasicAuthUsers"`
 	TrackerIconsFetchEnabled bool   `toml:"trackerIconsFetchEnabled" mapstructure:"trackerIconsFetchEnabled"`
 
+	// ConfigBackupInterval is the number of hours between scheduled exports of
+	// qui's own configuration to <backupDir>/qui-config; 0 disables them.
+	// ConfigBackupRetention is how many of those bundles are kept (0 keeps all).
+	// ConfigBackupPassphrase encrypts them; without it secrets are redacted.
+	ConfigBackupInterval  int `toml:"configBackupInterval" mapstructure:"configBackupInterval"`
+	ConfigBackupRetention int `toml:"configBackupRetention" mapstructure:"configBackupRetention"`
+	//nolint:gosec // Config schema requires this field name; value is provided by runtime configuration.
+	ConfigBackupPassphrase string `toml:"configBackupPassphrase" mapstructure:"configBackupPassphrase"`
+
 	// CustomThemesDir is the directory sideloaded custom theme CSS files are read from.
 	// Empty means <config-dir>/themes. A relative value is resolved against the config dir.
 	CustomThemesDir string `toml:"customThemesDir" mapstructure:"customThemesDir"`
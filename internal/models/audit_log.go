@@ -0,0 +1,283 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// Audit entry sources
+const (
+	AuditSourceAPI   = "api"
+	AuditSourceProxy = "proxy"
+)
+
+// Audit actor types
+const (
+	AuditActorUser      = "user"       // Session user
+	AuditActorAPIKey    = "api_key"    // qui API key
+	AuditActorClientKey = "client_key" // Client proxy API key
+)
+
+const (
+	DefaultAuditRetentionDays = 90
+	MaxAuditListLimit         = 1000
+)
+
+// AuditEntry records a single mutating API or proxy call.
+type AuditEntry struct {
+	ID         int       `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Source     string    `json:"source"`
+	ActorType  string    `json:"actorType"`
+	Actor      string    `json:"actor"`
+	InstanceID *int      `json:"instanceId,omitempty"`
+	Method     string    `json:"method"`
+	Endpoint   string    `json:"endpoint"`
+	Hashes     []string  `json:"hashes"`
+	Status     int       `json:"status"`
+	Success    bool      `json:"success"`
+}
+
+// AuditFilter narrows an audit log listing. Zero fields don't filter.
+type AuditFilter struct {
+	From       *time.Time
+	To         *time.Time
+	Source     string
+	ActorType  string
+	Actor      string
+	InstanceID *int
+	// Endpoint matches entries whose endpoint contains it.
+	Endpoint string
+	Hash     string
+	Success  *bool
+	Limit    int
+	Offset   int
+}
+
+// AuditSettings controls whether calls are recorded and how long entries are kept.
+type AuditSettings struct {
+	Enabled       bool      `json:"enabled"`
+	RetentionDays int       `json:"retentionDays"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// Validate checks the retention period.
+func (s *AuditSettings) Validate() error {
+	if s == nil {
+		return errors.New("settings are nil")
+	}
+	if s.RetentionDays < 1 {
+		return errors.New("retention days must be at least 1")
+	}
+	return nil
+}
+
+type AuditStore struct {
+	db dbinterface.Querier
+}
+
+func NewAuditStore(db dbinterface.Querier) *AuditStore {
+	return &AuditStore{db: db}
+}
+
+func (s *AuditStore) Create(ctx context.Context, entry *AuditEntry) error {
+	if s == nil || s.db == nil || entry == nil {
+		return nil
+	}
+
+	var instanceID sql.NullInt64
+	if entry.InstanceID != nil {
+		instanceID = sql.NullInt64{Int64: int64(*entry.InstanceID), Valid: true}
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO audit_log
+			(source, actor_type, actor, instance_id, method, endpoint, hashes, status, success)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.Source, entry.ActorType, entry.Actor, instanceID, entry.Method, entry.Endpoint,
+		strings.Join(entry.Hashes, ","), entry.Status, BoolToSQLite(entry.Success))
+	return err
+}
+
+// List returns the entries matching filter, newest first.
+func (s *AuditStore) List(ctx context.Context, filter AuditFilter) ([]*AuditEntry, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	limit = min(limit, MaxAuditListLimit)
+	offset := max(filter.Offset, 0)
+
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, filter.To.UTC())
+	}
+	if filter.Source != "" {
+		conditions = append(conditions, "source = ?")
+		args = append(args, filter.Source)
+	}
+	if filter.ActorType != "" {
+		conditions = append(conditions, "actor_type = ?")
+		args = append(args, filter.ActorType)
+	}
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.InstanceID != nil {
+		conditions = append(conditions, "instance_id = ?")
+		args = append(args, *filter.InstanceID)
+	}
+	if filter.Endpoint != "" {
+		conditions = append(conditions, "endpoint LIKE ?")
+		args = append(args, "%"+filter.Endpoint+"%")
+	}
+	if hash := strings.ToUpper(strings.TrimSpace(filter.Hash)); hash != "" {
+		conditions = append(conditions, "(',' || UPPER(hashes) || ',') LIKE ?")
+		args = append(args, "%,"+hash+",%")
+	}
+	if filter.Success != nil {
+		conditions = append(conditions, "success = ?")
+		args = append(args, BoolToSQLite(*filter.Success))
+	}
+
+	query := `
+		SELECT id, created_at, source, actor_type, actor, instance_id, method, endpoint, hashes, status, success
+		FROM audit_log`
+	if len(conditions) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(conditions, " AND ")
+	}
+	query += `
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*AuditEntry{}
+	for rows.Next() {
+		var (
+			e          AuditEntry
+			instanceID sql.NullInt64
+			hashes     string
+			success    int
+		)
+		if err := rows.Scan(&e.ID, &e.CreatedAt, &e.Source, &e.ActorType, &e.Actor, &instanceID,
+			&e.Method, &e.Endpoint, &hashes, &e.Status, &success); err != nil {
+			return nil, err
+		}
+		if instanceID.Valid {
+			id := int(instanceID.Int64)
+			e.InstanceID = &id
+		}
+		e.Hashes = []string{}
+		if hashes != "" {
+			e.Hashes = strings.Split(hashes, ",")
+		}
+		e.Success = SQLiteIntToBool(success)
+		entries = append(entries, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Prune deletes entries older than retentionDays.
+func (s *AuditStore) Prune(ctx context.Context, retentionDays int) (int64, error) {
+	if retentionDays <= 0 {
+		retentionDays = DefaultAuditRetentionDays
+	}
+
+	cutoff := time.Now().UTC().Add(-time.Duration(retentionDays) * 24 * time.Hour)
+	res, err := s.db.ExecContext(ctx, `
+		DELETE FROM audit_log
+		WHERE created_at < ?
+	`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// GetSettings returns the audit settings, creating defaults if none exist
+func (s *AuditStore) GetSettings(ctx context.Context) (*AuditSettings, error) {
+	settings, err := s.getSettings(ctx)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return settings, err
+	}
+
+	switch dbinterface.DialectOf(s.db) {
+	case "postgres":
+		_, err = s.db.ExecContext(ctx, `INSERT INTO audit_settings (id) VALUES (1) ON CONFLICT (id) DO NOTHING`)
+	default:
+		_, err = s.db.ExecContext(ctx, `INSERT OR IGNORE INTO audit_settings (id) VALUES (1)`)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.getSettings(ctx)
+}
+
+func (s *AuditStore) UpdateSettings(ctx context.Context, settings *AuditSettings) (*AuditSettings, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Ensure we have a record (creates if none)
+	if _, err := s.GetSettings(ctx); err != nil {
+		return nil, err
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE audit_settings
+		SET enabled = ?, retention_days = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = 1
+	`, BoolToSQLite(settings.Enabled), settings.RetentionDays)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getSettings(ctx)
+}
+
+func (s *AuditStore) getSettings(ctx context.Context) (*AuditSettings, error) {
+	var (
+		settings AuditSettings
+		enabled  int
+	)
+	err := s.db.QueryRowContext(ctx, `
+		SELECT enabled, retention_days, updated_at
+		FROM audit_settings
+		WHERE id = 1
+	`).Scan(&enabled, &settings.RetentionDays, &settings.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	settings.Enabled = SQLiteIntToBool(enabled)
+	return &settings, nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuditStore(t *testing.T) (*AuditStore, func(query string, args ...any)) {
+	t.Helper()
+
+	db := openSQLiteDB(t)
+	mustExec(t, db, `
+		CREATE TABLE audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			source TEXT NOT NULL,
+			actor_type TEXT NOT NULL,
+			actor TEXT NOT NULL DEFAULT '',
+			instance_id INTEGER,
+			method TEXT NOT NULL,
+			endpoint TEXT NOT NULL,
+			hashes TEXT NOT NULL DEFAULT '',
+			status INTEGER NOT NULL DEFAULT 0,
+			success INTEGER NOT NULL DEFAULT 0
+		)
+	`)
+	mustExec(t, db, `
+		CREATE TABLE audit_settings (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			enabled INTEGER NOT NULL DEFAULT 1,
+			retention_days INTEGER NOT NULL DEFAULT 90,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+
+	return NewAuditStore(&capturingQuerier{db: db}), func(query string, args ...any) {
+		mustExec(t, db, query, args...)
+	}
+}
+
+func TestAuditStoreListFilters(t *testing.T) {
+	store, _ := newTestAuditStore(t)
+	ctx := context.Background()
+
+	instanceID := 1
+	otherInstanceID := 2
+	for _, entry := range []*AuditEntry{
+		{Source: AuditSourceAPI, ActorType: AuditActorUser, Actor: "admin", InstanceID: &instanceID, Method: "POST", Endpoint: "/api/instances/{instanceID}/torrents/bulk-action", Hashes: []string{"AAA", "BBB"}, Status: 200, Success: true},
+		{Source: AuditSourceProxy, ActorType: AuditActorClientKey, Actor: "sonarr", InstanceID: &otherInstanceID, Method: "POST", Endpoint: "torrents/delete", Hashes: []string{"CCC"}, Status: 403},
+		{Source: AuditSourceAPI, ActorType: AuditActorAPIKey, Actor: "autobrr", Method: "PUT", Endpoint: "/api/settings", Status: 200, Success: true},
+	} {
+		require.NoError(t, store.Create(ctx, entry))
+	}
+
+	all, err := store.List(ctx, AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "autobrr", all[0].Actor, "newest entries come first")
+	assert.Empty(t, all[0].Hashes)
+	assert.Nil(t, all[0].InstanceID)
+
+	failed := false
+	byFailure, err := store.List(ctx, AuditFilter{Success: &failed})
+	require.NoError(t, err)
+	require.Len(t, byFailure, 1)
+	assert.Equal(t, "sonarr", byFailure[0].Actor)
+	assert.Equal(t, otherInstanceID, *byFailure[0].InstanceID)
+
+	byHash, err := store.List(ctx, AuditFilter{Hash: "bbb"})
+	require.NoError(t, err)
+	require.Len(t, byHash, 1)
+	assert.Equal(t, []string{"AAA", "BBB"}, byHash[0].Hashes)
+
+	byEndpoint, err := store.List(ctx, AuditFilter{Endpoint: "torrents", Source: AuditSourceAPI})
+	require.NoError(t, err)
+	require.Len(t, byEndpoint, 1)
+	assert.Equal(t, "admin", byEndpoint[0].Actor)
+
+	paged, err := store.List(ctx, AuditFilter{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, paged, 1)
+	assert.Equal(t, "sonarr", paged[0].Actor)
+}
+
+func TestAuditStorePruneAndSettings(t *testing.T) {
+	store, exec := newTestAuditStore(t)
+	ctx := context.Background()
+
+	exec(`INSERT INTO audit_log (created_at, source, actor_type, method, endpoint) VALUES (datetime('now', '-40 days'), 'api', 'user', 'POST', '/old')`)
+	require.NoError(t, store.Create(ctx, &AuditEntry{Source: AuditSourceAPI, ActorType: AuditActorUser, Method: "POST", Endpoint: "/new"}))
+
+	pruned, err := store.Prune(ctx, 30)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	remaining, err := store.List(ctx, AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "/new", remaining[0].Endpoint)
+
+	settings, err := store.GetSettings(ctx)
+	require.NoError(t, err)
+	assert.True(t, settings.Enabled)
+	assert.Equal(t, DefaultAuditRetentionDays, settings.RetentionDays)
+
+	updated, err := store.UpdateSettings(ctx, &AuditSettings{Enabled: false, RetentionDays: 14})
+	require.NoError(t, err)
+	assert.False(t, updated.Enabled)
+	assert.Equal(t, 14, updated.RetentionDays)
+
+	_, err = store.UpdateSettings(ctx, &AuditSettings{RetentionDays: 0})
+	require.Error(t, err)
+}
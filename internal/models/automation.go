@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -33,6 +34,44 @@ const (
 	TagModeRemove = "remove" // Only remove from matches
 )
 
+// AutomationTrigger defines what causes a rule to run.
+type AutomationTrigger string
+
+const (
+	// TriggerInterval runs the rule on its per-rule interval (default).
+	TriggerInterval AutomationTrigger = "interval"
+	// TriggerOnAdded runs the rule as soon as new torrents are seen.
+	TriggerOnAdded AutomationTrigger = "onAdded"
+	// TriggerOnCompleted runs the rule as soon as torrents finish downloading.
+	TriggerOnCompleted AutomationTrigger = "onCompleted"
+	// TriggerOnStateChange runs the rule whenever a torrent's state changes.
+	TriggerOnStateChange AutomationTrigger = "onStateChange"
+)
+
+// IsValid reports whether t is a known trigger mode.
+func (t AutomationTrigger) IsValid() bool {
+	switch t {
+	case TriggerInterval, TriggerOnAdded, TriggerOnCompleted, TriggerOnStateChange:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsEvent reports whether t fires on torrent events instead of the interval timer.
+func (t AutomationTrigger) IsEvent() bool {
+	return t == TriggerOnAdded || t == TriggerOnCompleted || t == TriggerOnStateChange
+}
+
+// NormalizeAutomationTrigger maps empty or unknown values to TriggerInterval.
+func NormalizeAutomationTrigger(t AutomationTrigger) AutomationTrigger {
+	t = AutomationTrigger(strings.TrimSpace(string(t)))
+	if !t.IsValid() {
+		return TriggerInterval
+	}
+	return t
+}
+
 // FreeSpaceSourceType defines the source for free space checks in workflows.
 type FreeSpaceSourceType string
 
@@ -165,21 +204,26 @@ func (c *SortingConfig) Validate() error {
 }
 
 type Automation struct {
-	ID              int               `json:"id"`
-	InstanceID      int               `json:"instanceId"`
-	Name            string            `json:"name"`
-	TrackerPattern  string            `json:"trackerPattern"`
-	TrackerDomains  []string          `json:"trackerDomains,omitempty"`
-	Conditions      *ActionConditions `json:"conditions"`
-	FreeSpaceSource *FreeSpaceSource  `json:"freeSpaceSource,omitempty"` // nil = default qBittorrent free space
-	SortingConfig   *SortingConfig    `json:"sortingConfig,omitempty"`   // nil = default sorting (oldest first)
-	Enabled         bool              `json:"enabled"`
-	DryRun          bool              `json:"dryRun"`
-	Notify          bool              `json:"notify"`
-	SortOrder       int               `json:"sortOrder"`
-	IntervalSeconds *int              `json:"intervalSeconds,omitempty"` // nil = use DefaultRuleInterval (15m)
-	CreatedAt       time.Time         `json:"createdAt"`
-	UpdatedAt       time.Time         `json:"updatedAt"`
+	ID                int                                 `json:"id"`
+	InstanceID        int                                 `json:"instanceId"`
+	Name              string                              `json:"name"`
+	TrackerPattern    string                              `json:"trackerPattern"`
+	TrackerDomains    []string                            `json:"trackerDomains,omitempty"`
+	Conditions        *ActionConditions                   `json:"conditions"`
+	FreeSpaceSource   *FreeSpaceSource                    `json:"freeSpaceSource,omitempty"` // nil = default qBittorrent free space
+	SortingConfig     *SortingConfig                      `json:"sortingConfig,omitempty"`   // nil = default sorting (oldest first)
+	Enabled           bool                                `json:"enabled"`
+	DryRun            bool                                `json:"dryRun"`
+	Notify            bool                                `json:"notify"`
+	SortOrder         int                                 `json:"sortOrder"`
+	IntervalSeconds   *int                                `json:"intervalSeconds,omitempty"`   // nil = use DefaultRuleInterval (15m)
+	TriggerMode       AutomationTrigger                   `json:"triggerMode"`                 // "interval" (default), "onAdded", "onCompleted", "onStateChange"
+	Schedule          *AutomationSchedule                 `json:"schedule,omitempty"`          // nil = run on IntervalSeconds at any time
+	Targets           *AutomationTargets                  `json:"targets,omitempty"`           // nil = owning instance only
+	InstanceOverrides map[int]*AutomationInstanceOverride `json:"instanceOverrides,omitempty"` // keyed by instance ID
+	NextRunAt         *time.Time                          `json:"nextRunAt,omitempty"`         // computed by the service, not stored
+	CreatedAt         time.Time                           `json:"createdAt"`
+	UpdatedAt         time.Time                           `json:"updatedAt"`
 }
 
 type AutomationStore struct {
@@ -230,104 +274,25 @@ func normalizeTrackerPattern(pattern string, domains []string) string {
 	return strings.Join(parts, ",")
 }
 
-func (s *AutomationStore) ListByInstance(ctx context.Context, instanceID int) ([]*Automation, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, instance_id, name, tracker_pattern, conditions, enabled, dry_run, notify, sort_order, interval_seconds, free_space_source, sorting_config, created_at, updated_at
-		FROM automations
-		WHERE instance_id = ?
-		ORDER BY sort_order ASC, id ASC
-	`, instanceID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var automations []*Automation
-	for rows.Next() {
-		var automation Automation
-		var conditionsJSON string
-		var intervalSeconds sql.NullInt64
-		var freeSpaceSourceJSON sql.NullString
-		var sortingConfigJSON sql.NullString
-		var enabled, dryRun, notify int
-
-		if err := rows.Scan(
-			&automation.ID,
-			&automation.InstanceID,
-			&automation.Name,
-			&automation.TrackerPattern,
-			&conditionsJSON,
-			&enabled,
-			&dryRun,
-			&notify,
-			&automation.SortOrder,
-			&intervalSeconds,
-			&freeSpaceSourceJSON,
-			&sortingConfigJSON,
-			&automation.CreatedAt,
-			&automation.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-
-		var conditions ActionConditions
-		if err := json.Unmarshal([]byte(conditionsJSON), &conditions); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal conditions for automation %d: %w", automation.ID, err)
-		}
-		conditions.Normalize()
-		automation.Conditions = &conditions
-
-		automation.Enabled = SQLiteIntToBool(enabled)
-		automation.DryRun = SQLiteIntToBool(dryRun)
-		automation.Notify = SQLiteIntToBool(notify)
-		automation.TrackerDomains = splitPatterns(automation.TrackerPattern)
-
-		if intervalSeconds.Valid {
-			v := int(intervalSeconds.Int64)
-			automation.IntervalSeconds = &v
-		}
-
-		if freeSpaceSourceJSON.Valid && freeSpaceSourceJSON.String != "" {
-			var freeSpaceSource FreeSpaceSource
-			if err := json.Unmarshal([]byte(freeSpaceSourceJSON.String), &freeSpaceSource); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal free_space_source for automation %d: %w", automation.ID, err)
-			}
-			automation.FreeSpaceSource = &freeSpaceSource
-		}
-
-		if sortingConfigJSON.Valid && sortingConfigJSON.String != "" {
-			var sortingConfig SortingConfig
-			if err := json.Unmarshal([]byte(sortingConfigJSON.String), &sortingConfig); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal sorting_config for automation %d: %w", automation.ID, err)
-			}
-			automation.SortingConfig = &sortingConfig
-		}
-
-		automations = append(automations, &automation)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
+const automationColumns = `id, instance_id, name, tracker_pattern, conditions, enabled, dry_run, notify, sort_order, interval_seconds, trigger_mode, schedule, targets, instance_overrides, free_space_source, sorting_config, created_at, updated_at`
 
-	return automations, nil
+type automationScanner interface {
+	Scan(dest ...any) error
 }
 
-func (s *AutomationStore) Get(ctx context.Context, instanceID, id int) (*Automation, error) {
-	row := s.db.QueryRowContext(ctx, `
-		SELECT id, instance_id, name, tracker_pattern, conditions, enabled, dry_run, notify, sort_order, interval_seconds, free_space_source, sorting_config, created_at, updated_at
-		FROM automations
-		WHERE id = ? AND instance_id = ?
-	`, id, instanceID)
-
+func scanAutomation(scanner automationScanner) (*Automation, error) {
 	var automation Automation
 	var conditionsJSON string
 	var intervalSeconds sql.NullInt64
+	var triggerMode string
+	var scheduleJSON sql.NullString
+	var targetsJSON sql.NullString
+	var instanceOverridesJSON sql.NullString
 	var freeSpaceSourceJSON sql.NullString
 	var sortingConfigJSON sql.NullString
 	var enabled, dryRun, notify int
 
-	if err := row.Scan(
+	if err := scanner.Scan(
 		&automation.ID,
 		&automation.InstanceID,
 		&automation.Name,
@@ -338,6 +303,10 @@ func (s *AutomationStore) Get(ctx context.Context, instanceID, id int) (*Automat
 		&notify,
 		&automation.SortOrder,
 		&intervalSeconds,
+		&triggerMode,
+		&scheduleJSON,
+		&targetsJSON,
+		&instanceOverridesJSON,
 		&freeSpaceSourceJSON,
 		&sortingConfigJSON,
 		&automation.CreatedAt,
@@ -357,12 +326,37 @@ func (s *AutomationStore) Get(ctx context.Context, instanceID, id int) (*Automat
 	automation.DryRun = SQLiteIntToBool(dryRun)
 	automation.Notify = SQLiteIntToBool(notify)
 	automation.TrackerDomains = splitPatterns(automation.TrackerPattern)
+	automation.TriggerMode = NormalizeAutomationTrigger(AutomationTrigger(triggerMode))
 
 	if intervalSeconds.Valid {
 		v := int(intervalSeconds.Int64)
 		automation.IntervalSeconds = &v
 	}
 
+	if scheduleJSON.Valid && scheduleJSON.String != "" {
+		var schedule AutomationSchedule
+		if err := json.Unmarshal([]byte(scheduleJSON.String), &schedule); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal schedule for automation %d: %w", automation.ID, err)
+		}
+		automation.Schedule = &schedule
+	}
+
+	if targetsJSON.Valid && targetsJSON.String != "" {
+		var targets AutomationTargets
+		if err := json.Unmarshal([]byte(targetsJSON.String), &targets); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal targets for automation %d: %w", automation.ID, err)
+		}
+		automation.Targets = &targets
+	}
+
+	if instanceOverridesJSON.Valid && instanceOverridesJSON.String != "" {
+		var overrides map[int]*AutomationInstanceOverride
+		if err := json.Unmarshal([]byte(instanceOverridesJSON.String), &overrides); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal instance_overrides for automation %d: %w", automation.ID, err)
+		}
+		automation.InstanceOverrides = overrides
+	}
+
 	if freeSpaceSourceJSON.Valid && freeSpaceSourceJSON.String != "" {
 		var freeSpaceSource FreeSpaceSource
 		if err := json.Unmarshal([]byte(freeSpaceSourceJSON.String), &freeSpaceSource); err != nil {
@@ -382,6 +376,95 @@ func (s *AutomationStore) Get(ctx context.Context, instanceID, id int) (*Automat
 	return &automation, nil
 }
 
+func (s *AutomationStore) queryAutomations(ctx context.Context, query string, args ...any) ([]*Automation, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var automations []*Automation
+	for rows.Next() {
+		automation, err := scanAutomation(rows)
+		if err != nil {
+			return nil, err
+		}
+		automations = append(automations, automation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return automations, nil
+}
+
+// ListByInstance returns the rules owned by instanceID, including shared rules
+// it owns, exactly as stored.
+func (s *AutomationStore) ListByInstance(ctx context.Context, instanceID int) ([]*Automation, error) {
+	return s.queryAutomations(ctx, `
+		SELECT `+automationColumns+`
+		FROM automations
+		WHERE instance_id = ?
+		ORDER BY sort_order ASC, id ASC
+	`, instanceID)
+}
+
+// ListForInstance returns every rule that runs on instanceID: its own rules plus
+// shared rules owned by other instances that target it. Per-instance overrides
+// are applied, so the result is meant for evaluation rather than editing.
+func (s *AutomationStore) ListForInstance(ctx context.Context, instanceID int) ([]*Automation, error) {
+	candidates, err := s.queryAutomations(ctx, `
+		SELECT `+automationColumns+`
+		FROM automations
+		WHERE instance_id = ? OR targets IS NOT NULL
+		ORDER BY sort_order ASC, id ASC
+	`, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	automations := make([]*Automation, 0, len(candidates))
+	for _, automation := range candidates {
+		if !automation.AppliesTo(instanceID) {
+			continue
+		}
+		automations = append(automations, automation.ForInstance(instanceID))
+	}
+	return automations, nil
+}
+
+// ListSharedWith returns shared rules owned by other instances that target instanceID, as stored.
+func (s *AutomationStore) ListSharedWith(ctx context.Context, instanceID int) ([]*Automation, error) {
+	candidates, err := s.queryAutomations(ctx, `
+		SELECT `+automationColumns+`
+		FROM automations
+		WHERE instance_id <> ? AND targets IS NOT NULL
+		ORDER BY instance_id ASC, sort_order ASC, id ASC
+	`, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var automations []*Automation
+	for _, automation := range candidates {
+		if automation.Targets.Includes(instanceID) {
+			automations = append(automations, automation)
+		}
+	}
+	return automations, nil
+}
+
+func (s *AutomationStore) Get(ctx context.Context, instanceID, id int) (*Automation, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+automationColumns+`
+		FROM automations
+		WHERE id = ? AND instance_id = ?
+	`, id, instanceID)
+
+	return scanAutomation(row)
+}
+
 func (s *AutomationStore) nextSortOrder(ctx context.Context, instanceID int) (int, error) {
 	row := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(sort_order), 0) FROM automations WHERE instance_id = ?`, instanceID)
 	var maxOrder int
@@ -405,14 +488,24 @@ func (s *AutomationStore) Create(ctx context.Context, automation *Automation) (*
 	if err := automation.Conditions.ExportToInstance.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid export to instance action: %w", err)
 	}
+	if err := automation.Conditions.Webhook.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid webhook action: %w", err)
+	}
 
 	if automation.SortingConfig != nil {
 		if err := automation.SortingConfig.Validate(); err != nil {
 			return nil, fmt.Errorf("invalid sorting config: %w", err)
 		}
 	}
+	if err := automation.Schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+	if err := automation.validateSharing(); err != nil {
+		return nil, fmt.Errorf("invalid instance targets: %w", err)
+	}
 
 	automation.TrackerPattern = normalizeTrackerPattern(automation.TrackerPattern, automation.TrackerDomains)
+	automation.TriggerMode = NormalizeAutomationTrigger(automation.TriggerMode)
 
 	sortOrder := automation.SortOrder
 	if sortOrder == 0 {
@@ -433,6 +526,33 @@ func (s *AutomationStore) Create(ctx context.Context, automation *Automation) (*
 		intervalSeconds = sql.NullInt64{Int64: int64(*automation.IntervalSeconds), Valid: true}
 	}
 
+	var scheduleJSON sql.NullString
+	if !automation.Schedule.IsEmpty() {
+		data, marshalErr := json.Marshal(automation.Schedule)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal schedule: %w", marshalErr)
+		}
+		scheduleJSON = sql.NullString{String: string(data), Valid: true}
+	}
+
+	var targetsJSON sql.NullString
+	if !automation.Targets.IsEmpty() {
+		data, marshalErr := json.Marshal(automation.Targets)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal targets: %w", marshalErr)
+		}
+		targetsJSON = sql.NullString{String: string(data), Valid: true}
+	}
+
+	var instanceOverridesJSON sql.NullString
+	if len(automation.InstanceOverrides) > 0 {
+		data, marshalErr := json.Marshal(automation.InstanceOverrides)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal instance_overrides: %w", marshalErr)
+		}
+		instanceOverridesJSON = sql.NullString{String: string(data), Valid: true}
+	}
+
 	var freeSpaceSourceJSON sql.NullString
 	if automation.FreeSpaceSource != nil {
 		data, marshalErr := json.Marshal(automation.FreeSpaceSource)
@@ -453,11 +573,11 @@ func (s *AutomationStore) Create(ctx context.Context, automation *Automation) (*
 	var id int
 	err = s.db.QueryRowContext(ctx, `
 		INSERT INTO automations
-			(instance_id, name, tracker_pattern, conditions, enabled, dry_run, notify, sort_order, interval_seconds, free_space_source, sorting_config)
+			(instance_id, name, tracker_pattern, conditions, enabled, dry_run, notify, sort_order, interval_seconds, trigger_mode, schedule, targets, instance_overrides, free_space_source, sorting_config)
 		VALUES
-			(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		RETURNING id
-	`, automation.InstanceID, automation.Name, automation.TrackerPattern, string(conditionsJSON), boolToInt(automation.Enabled), boolToInt(automation.DryRun), boolToInt(automation.Notify), sortOrder, intervalSeconds, freeSpaceSourceJSON, sortingConfigJSON).Scan(&id)
+	`, automation.InstanceID, automation.Name, automation.TrackerPattern, string(conditionsJSON), boolToInt(automation.Enabled), boolToInt(automation.DryRun), boolToInt(automation.Notify), sortOrder, intervalSeconds, string(automation.TriggerMode), scheduleJSON, targetsJSON, instanceOverridesJSON, freeSpaceSourceJSON, sortingConfigJSON).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
@@ -479,14 +599,24 @@ func (s *AutomationStore) Update(ctx context.Context, automation *Automation) (*
 	if err := automation.Conditions.ExportToInstance.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid export to instance action: %w", err)
 	}
+	if err := automation.Conditions.Webhook.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid webhook action: %w", err)
+	}
 
 	if automation.SortingConfig != nil {
 		if err := automation.SortingConfig.Validate(); err != nil {
 			return nil, fmt.Errorf("invalid sorting config: %w", err)
 		}
 	}
+	if err := automation.Schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+	if err := automation.validateSharing(); err != nil {
+		return nil, fmt.Errorf("invalid instance targets: %w", err)
+	}
 
 	automation.TrackerPattern = normalizeTrackerPattern(automation.TrackerPattern, automation.TrackerDomains)
+	automation.TriggerMode = NormalizeAutomationTrigger(automation.TriggerMode)
 
 	conditionsJSON, err := json.Marshal(automation.Conditions)
 	if err != nil {
@@ -498,6 +628,33 @@ func (s *AutomationStore) Update(ctx context.Context, automation *Automation) (*
 		intervalSeconds = sql.NullInt64{Int64: int64(*automation.IntervalSeconds), Valid: true}
 	}
 
+	var scheduleJSON sql.NullString
+	if !automation.Schedule.IsEmpty() {
+		data, marshalErr := json.Marshal(automation.Schedule)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal schedule: %w", marshalErr)
+		}
+		scheduleJSON = sql.NullString{String: string(data), Valid: true}
+	}
+
+	var targetsJSON sql.NullString
+	if !automation.Targets.IsEmpty() {
+		data, marshalErr := json.Marshal(automation.Targets)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal targets: %w", marshalErr)
+		}
+		targetsJSON = sql.NullString{String: string(data), Valid: true}
+	}
+
+	var instanceOverridesJSON sql.NullString
+	if len(automation.InstanceOverrides) > 0 {
+		data, marshalErr := json.Marshal(automation.InstanceOverrides)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal instance_overrides: %w", marshalErr)
+		}
+		instanceOverridesJSON = sql.NullString{String: string(data), Valid: true}
+	}
+
 	var freeSpaceSourceJSON sql.NullString
 	if automation.FreeSpaceSource != nil {
 		data, marshalErr := json.Marshal(automation.FreeSpaceSource)
@@ -518,9 +675,9 @@ func (s *AutomationStore) Update(ctx context.Context, automation *Automation) (*
 
 	res, err := s.db.ExecContext(ctx, `
 		UPDATE automations
-		SET name = ?, tracker_pattern = ?, conditions = ?, enabled = ?, dry_run = ?, notify = ?, sort_order = ?, interval_seconds = ?, free_space_source = ?, sorting_config = ?
+		SET name = ?, tracker_pattern = ?, conditions = ?, enabled = ?, dry_run = ?, notify = ?, sort_order = ?, interval_seconds = ?, trigger_mode = ?, schedule = ?, targets = ?, instance_overrides = ?, free_space_source = ?, sorting_config = ?
 		WHERE id = ? AND instance_id = ?
-	`, automation.Name, automation.TrackerPattern, string(conditionsJSON), boolToInt(automation.Enabled), boolToInt(automation.DryRun), boolToInt(automation.Notify), automation.SortOrder, intervalSeconds, freeSpaceSourceJSON, sortingConfigJSON, automation.ID, automation.InstanceID)
+	`, automation.Name, automation.TrackerPattern, string(conditionsJSON), boolToInt(automation.Enabled), boolToInt(automation.DryRun), boolToInt(automation.Notify), automation.SortOrder, intervalSeconds, string(automation.TriggerMode), scheduleJSON, targetsJSON, instanceOverridesJSON, freeSpaceSourceJSON, sortingConfigJSON, automation.ID, automation.InstanceID)
 	if err != nil {
 		return nil, err
 	}
@@ -755,6 +912,24 @@ const (
 	FieldExistsOnSameInstance   ConditionField = "EXISTS_ON_SAME_INSTANCE"
 	FieldSeedingOnSameInstance  ConditionField = "SEEDING_ON_SAME_INSTANCE"
 	FieldCrossSeedTags          ConditionField = "CROSS_SEED_TAGS"
+	FieldArrImported            ConditionField = "ARR_IMPORTED"
+	FieldArrInQueue             ConditionField = "ARR_IN_QUEUE"
+
+	// Sonarr/Radarr import age (seconds since the download was last imported)
+	FieldArrImportAge ConditionField = "ARR_IMPORT_AGE"
+
+	// Tracker seeding requirements (hit-and-run protection)
+	FieldHnRSatisfied        ConditionField = "HNR_SATISFIED"
+	FieldHnRRemainingSeconds ConditionField = "HNR_REMAINING_SECONDS"
+
+	// Rule history fields (derived from the torrent's match streak for the rule)
+	FieldRuleMatchStreak  ConditionField = "RULE_MATCH_STREAK"
+	FieldConditionTrueFor ConditionField = "CONDITION_TRUE_FOR"
+
+	// Transfer history fields (derived from sampled per-torrent transfer counters)
+	FieldUploadedLast24h ConditionField = "UPLOADED_LAST_24H"
+	FieldUploadedLast7d  ConditionField = "UPLOADED_LAST_7D"
+	FieldIdleUploadDays  ConditionField = "IDLE_UPLOAD_DAYS"
 
 	// System time fields
 	FieldSystemHour      ConditionField = "SYSTEM_HOUR"
@@ -767,6 +942,10 @@ const (
 	// Enum-like fields
 	FieldHardlinkScope      ConditionField = "HARDLINK_SCOPE"
 	FieldHardlinkScopeCross ConditionField = "HARDLINK_SCOPE_CROSS"
+
+	// Expression field: Value holds an expr-lang boolean expression evaluated
+	// against the enriched torrent environment. Operator and Regex are ignored.
+	FieldExpr ConditionField = "EXPR"
 )
 
 //nolint:exhaustive // Only sortable numeric fields belong here.
@@ -778,7 +957,8 @@ func (f ConditionField) IsNumeric() bool {
 		FieldRatio, FieldProgress, FieldAvailability,
 		FieldDlSpeed, FieldUpSpeed,
 		FieldNumSeeds, FieldNumLeechs, FieldNumComplete, FieldNumIncomplete, FieldTrackersCount,
-		FieldSystemHour, FieldSystemMinute, FieldSystemDayOfWeek, FieldSystemDay, FieldSystemMonth, FieldSystemYear:
+		FieldSystemHour, FieldSystemMinute, FieldSystemDayOfWeek, FieldSystemDay, FieldSystemMonth, FieldSystemYear,
+		FieldUploadedLast24h, FieldUploadedLast7d, FieldIdleUploadDays:
 		return true
 	default:
 		return false
@@ -886,6 +1066,7 @@ type ActionConditions struct {
 	ExternalProgram  *ExternalProgramAction  `json:"externalProgram,omitempty"`
 	AutoManagement   *AutoManagementAction   `json:"autoManagement,omitempty"`
 	ExportToInstance *ExportToInstanceAction `json:"exportToInstance,omitempty"`
+	Webhook          *WebhookAction          `json:"webhook,omitempty"`
 }
 
 // SpeedLimitAction configures speed limit application with optional conditions.
@@ -1049,6 +1230,74 @@ func (a *ExportToInstanceAction) Validate() error {
 	return nil
 }
 
+// Webhook delivery modes.
+const (
+	WebhookModeBatch      = "batch"      // One request per rule per run listing every matched torrent
+	WebhookModePerTorrent = "perTorrent" // One request per matched torrent
+)
+
+const (
+	WebhookDefaultTimeoutSeconds = 10
+	WebhookMaxTimeoutSeconds     = 120
+	WebhookMaxRetries            = 5
+)
+
+// WebhookAction POSTs a JSON payload describing matched torrents to an HTTP endpoint.
+type WebhookAction struct {
+	Enabled        bool              `json:"enabled"`
+	URL            string            `json:"url"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	BodyTemplate   string            `json:"bodyTemplate,omitempty"`   // Go template rendering the JSON body; empty uses the default payload
+	TimeoutSeconds int               `json:"timeoutSeconds,omitempty"` // Per-attempt timeout (defaults 10)
+	Retries        int               `json:"retries,omitempty"`        // Extra attempts after a failed request
+	Mode           string            `json:"mode,omitempty"`           // "batch" (default) or "perTorrent"
+	Condition      *RuleCondition    `json:"condition,omitempty"`
+}
+
+// EffectiveMode returns the delivery mode, defaulting to batch.
+func (a *WebhookAction) EffectiveMode() string {
+	if a.Mode == WebhookModePerTorrent {
+		return WebhookModePerTorrent
+	}
+	return WebhookModeBatch
+}
+
+// Timeout returns the per-attempt request timeout.
+func (a *WebhookAction) Timeout() time.Duration {
+	if a.TimeoutSeconds <= 0 {
+		return WebhookDefaultTimeoutSeconds * time.Second
+	}
+	return time.Duration(a.TimeoutSeconds) * time.Second
+}
+
+// Validate checks that the WebhookAction has valid configuration.
+func (a *WebhookAction) Validate() error {
+	if a == nil || !a.Enabled {
+		return nil
+	}
+	parsed, err := url.Parse(strings.TrimSpace(a.URL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("enabled webhook action requires an http(s) url")
+	}
+	switch a.Mode {
+	case "", WebhookModeBatch, WebhookModePerTorrent:
+	default:
+		return fmt.Errorf("unknown webhook mode %q", a.Mode)
+	}
+	if a.TimeoutSeconds < 0 || a.TimeoutSeconds > WebhookMaxTimeoutSeconds {
+		return fmt.Errorf("webhook timeoutSeconds must be between 0 and %d", WebhookMaxTimeoutSeconds)
+	}
+	if a.Retries < 0 || a.Retries > WebhookMaxRetries {
+		return fmt.Errorf("webhook retries must be between 0 and %d", WebhookMaxRetries)
+	}
+	for name := range a.Headers {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("webhook header names must not be empty")
+		}
+	}
+	return nil
+}
+
 // IsEmpty returns true if no actions are configured.
 func (ac *ActionConditions) IsEmpty() bool {
 	if ac == nil {
@@ -1066,7 +1315,8 @@ func (ac *ActionConditions) IsEmpty() bool {
 		ac.Move == nil &&
 		ac.ExternalProgram == nil &&
 		ac.AutoManagement == nil &&
-		ac.ExportToInstance == nil
+		ac.ExportToInstance == nil &&
+		ac.Webhook == nil
 }
 
 // Normalize normalizes legacy/new action fields for in-memory use.
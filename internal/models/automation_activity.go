@@ -31,6 +31,7 @@ const (
 	ActivityActionMoved               = "moved"                // Batch move operation
 	ActivityActionAutoManaged         = "auto_managed"         // Batch auto management operation
 	ActivityActionExportedToInstance  = "exported_to_instance" // Export torrent to another instance
+	ActivityActionWebhook             = "webhook"              // Webhook delivery
 	ActivityActionDryRunNoMatch       = "dry_run_no_match"     // Manual dry-run completed with no matching actions
 )
 
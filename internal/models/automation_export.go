@@ -0,0 +1,281 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AutomationExportVersion is the current version of the automation export format.
+const AutomationExportVersion = 1
+
+// AutomationExport is a portable rule set. Database IDs are replaced by names
+// (instances, external programs) so the file can be imported into another qui
+// and reviewed in version control. Categories and tags are already referenced
+// by name and are exported unchanged.
+type AutomationExport struct {
+	Version    int                     `json:"version"`
+	ExportedAt time.Time               `json:"exportedAt"`
+	Rules      []*AutomationExportRule `json:"rules"`
+}
+
+// AutomationExportRule is an Automation without database IDs.
+type AutomationExportRule struct {
+	Name              string                                 `json:"name"`
+	TrackerPattern    string                                 `json:"trackerPattern"`
+	TrackerDomains    []string                               `json:"trackerDomains,omitempty"`
+	Enabled           bool                                   `json:"enabled"`
+	DryRun            bool                                   `json:"dryRun"`
+	Notify            bool                                   `json:"notify"`
+	IntervalSeconds   *int                                   `json:"intervalSeconds,omitempty"`
+	TriggerMode       AutomationTrigger                      `json:"triggerMode,omitempty"`
+	Schedule          *AutomationSchedule                    `json:"schedule,omitempty"`
+	Conditions        *ActionConditions                      `json:"conditions"`
+	FreeSpaceSource   *FreeSpaceSource                       `json:"freeSpaceSource,omitempty"`
+	SortingConfig     *SortingConfig                         `json:"sortingConfig,omitempty"`
+	Targets           *AutomationExportTargets               `json:"targets,omitempty"`
+	InstanceOverrides map[string]*AutomationInstanceOverride `json:"instanceOverrides,omitempty"` // keyed by instance name
+	References        *AutomationExportReferences            `json:"references,omitempty"`
+}
+
+// AutomationExportTargets is AutomationTargets with instance names instead of IDs.
+type AutomationExportTargets struct {
+	AllInstances bool     `json:"allInstances,omitempty"`
+	Instances    []string `json:"instances,omitempty"`
+}
+
+// AutomationExportReferences names the objects a rule's actions point at.
+type AutomationExportReferences struct {
+	ExternalProgram      string `json:"externalProgram,omitempty"`      // replaces conditions.externalProgram.programId
+	ExportTargetInstance string `json:"exportTargetInstance,omitempty"` // replaces conditions.exportToInstance.targetInstanceId
+}
+
+// AutomationReferenceIndex maps instance and external program IDs to names and back.
+// Name lookups are case-insensitive.
+type AutomationReferenceIndex struct {
+	instanceNames map[int]string
+	instanceIDs   map[string]int
+	programNames  map[int]string
+	programIDs    map[string]int
+}
+
+func NewAutomationReferenceIndex(instances []*Instance, programs []*ExternalProgram) *AutomationReferenceIndex {
+	index := &AutomationReferenceIndex{
+		instanceNames: make(map[int]string, len(instances)),
+		instanceIDs:   make(map[string]int, len(instances)),
+		programNames:  make(map[int]string, len(programs)),
+		programIDs:    make(map[string]int, len(programs)),
+	}
+	for _, instance := range instances {
+		if instance == nil {
+			continue
+		}
+		index.instanceNames[instance.ID] = instance.Name
+		index.instanceIDs[strings.ToLower(instance.Name)] = instance.ID
+	}
+	for _, program := range programs {
+		if program == nil {
+			continue
+		}
+		index.programNames[program.ID] = program.Name
+		index.programIDs[strings.ToLower(program.Name)] = program.ID
+	}
+	return index
+}
+
+func (idx *AutomationReferenceIndex) instanceName(id int) (string, error) {
+	name, ok := idx.instanceNames[id]
+	if !ok {
+		return "", fmt.Errorf("instance %d not found", id)
+	}
+	return name, nil
+}
+
+func (idx *AutomationReferenceIndex) instanceID(name string) (int, error) {
+	id, ok := idx.instanceIDs[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return 0, fmt.Errorf("instance %q not found", name)
+	}
+	return id, nil
+}
+
+// ExportAutomations converts rules into the portable export format.
+func ExportAutomations(rules []*Automation, index *AutomationReferenceIndex) (*AutomationExport, error) {
+	if index == nil {
+		return nil, errors.New("reference index is nil")
+	}
+
+	export := &AutomationExport{
+		Version:    AutomationExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Rules:      make([]*AutomationExportRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		exported, err := exportAutomation(rule, index)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		export.Rules = append(export.Rules, exported)
+	}
+	return export, nil
+}
+
+func exportAutomation(rule *Automation, index *AutomationReferenceIndex) (*AutomationExportRule, error) {
+	exported := &AutomationExportRule{
+		Name:            rule.Name,
+		TrackerPattern:  rule.TrackerPattern,
+		TrackerDomains:  rule.TrackerDomains,
+		Enabled:         rule.Enabled,
+		DryRun:          rule.DryRun,
+		Notify:          rule.Notify,
+		IntervalSeconds: rule.IntervalSeconds,
+		TriggerMode:     rule.TriggerMode,
+		Schedule:        rule.Schedule,
+		FreeSpaceSource: rule.FreeSpaceSource,
+		SortingConfig:   rule.SortingConfig,
+	}
+
+	references := &AutomationExportReferences{}
+	if rule.Conditions != nil {
+		conditions := *rule.Conditions
+		if program := conditions.ExternalProgram; program != nil && program.ProgramID > 0 {
+			name, ok := index.programNames[program.ProgramID]
+			if !ok {
+				return nil, fmt.Errorf("external program %d not found", program.ProgramID)
+			}
+			references.ExternalProgram = name
+			cloned := *program
+			cloned.ProgramID = 0
+			conditions.ExternalProgram = &cloned
+		}
+		if target := conditions.ExportToInstance; target != nil && target.TargetInstanceID > 0 {
+			name, err := index.instanceName(target.TargetInstanceID)
+			if err != nil {
+				return nil, fmt.Errorf("export target: %w", err)
+			}
+			references.ExportTargetInstance = name
+			cloned := *target
+			cloned.TargetInstanceID = 0
+			conditions.ExportToInstance = &cloned
+		}
+		exported.Conditions = &conditions
+	}
+	if *references != (AutomationExportReferences{}) {
+		exported.References = references
+	}
+
+	if !rule.Targets.IsEmpty() {
+		targets := &AutomationExportTargets{AllInstances: rule.Targets.AllInstances}
+		for _, id := range rule.Targets.InstanceIDs {
+			name, err := index.instanceName(id)
+			if err != nil {
+				return nil, fmt.Errorf("target: %w", err)
+			}
+			targets.Instances = append(targets.Instances, name)
+		}
+		slices.Sort(targets.Instances)
+		exported.Targets = targets
+	}
+
+	if len(rule.InstanceOverrides) > 0 {
+		exported.InstanceOverrides = make(map[string]*AutomationInstanceOverride, len(rule.InstanceOverrides))
+		for id, override := range rule.InstanceOverrides {
+			name, err := index.instanceName(id)
+			if err != nil {
+				return nil, fmt.Errorf("instance override: %w", err)
+			}
+			exported.InstanceOverrides[name] = override
+		}
+	}
+
+	return exported, nil
+}
+
+// ToAutomation resolves the rule's named references and returns an Automation
+// owned by instanceID. The result still needs the usual payload validation.
+func (r *AutomationExportRule) ToAutomation(instanceID int, index *AutomationReferenceIndex) (*Automation, error) {
+	if r == nil {
+		return nil, errors.New("rule is nil")
+	}
+	if index == nil {
+		return nil, errors.New("reference index is nil")
+	}
+
+	automation := &Automation{
+		InstanceID:      instanceID,
+		Name:            r.Name,
+		TrackerPattern:  r.TrackerPattern,
+		TrackerDomains:  r.TrackerDomains,
+		Enabled:         r.Enabled,
+		DryRun:          r.DryRun,
+		Notify:          r.Notify,
+		IntervalSeconds: r.IntervalSeconds,
+		TriggerMode:     NormalizeAutomationTrigger(r.TriggerMode),
+		Schedule:        r.Schedule,
+		FreeSpaceSource: r.FreeSpaceSource,
+		SortingConfig:   r.SortingConfig,
+	}
+
+	if r.Conditions != nil {
+		conditions := *r.Conditions
+		if program := conditions.ExternalProgram; program != nil && (program.Enabled || r.References != nil && r.References.ExternalProgram != "") {
+			if r.References == nil || r.References.ExternalProgram == "" {
+				return nil, errors.New("external program action has no program reference")
+			}
+			id, ok := index.programIDs[strings.ToLower(strings.TrimSpace(r.References.ExternalProgram))]
+			if !ok {
+				return nil, fmt.Errorf("external program %q not found", r.References.ExternalProgram)
+			}
+			cloned := *program
+			cloned.ProgramID = id
+			conditions.ExternalProgram = &cloned
+		}
+		if target := conditions.ExportToInstance; target != nil && (target.Enabled || r.References != nil && r.References.ExportTargetInstance != "") {
+			if r.References == nil || r.References.ExportTargetInstance == "" {
+				return nil, errors.New("export to instance action has no target instance reference")
+			}
+			id, err := index.instanceID(r.References.ExportTargetInstance)
+			if err != nil {
+				return nil, fmt.Errorf("export target: %w", err)
+			}
+			cloned := *target
+			cloned.TargetInstanceID = id
+			conditions.ExportToInstance = &cloned
+		}
+		automation.Conditions = &conditions
+	}
+
+	if r.Targets != nil && (r.Targets.AllInstances || len(r.Targets.Instances) > 0) {
+		targets := &AutomationTargets{AllInstances: r.Targets.AllInstances}
+		for _, name := range r.Targets.Instances {
+			id, err := index.instanceID(name)
+			if err != nil {
+				return nil, fmt.Errorf("target: %w", err)
+			}
+			targets.InstanceIDs = append(targets.InstanceIDs, id)
+		}
+		targets.Normalize(instanceID)
+		automation.Targets = targets
+	}
+
+	if len(r.InstanceOverrides) > 0 {
+		automation.InstanceOverrides = make(map[int]*AutomationInstanceOverride, len(r.InstanceOverrides))
+		for name, override := range r.InstanceOverrides {
+			id, err := index.instanceID(name)
+			if err != nil {
+				return nil, fmt.Errorf("instance override: %w", err)
+			}
+			automation.InstanceOverrides[id] = override
+		}
+	}
+
+	return automation, nil
+}
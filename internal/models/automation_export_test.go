@@ -0,0 +1,68 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomationExportRoundTripUsesNames(t *testing.T) {
+	t.Parallel()
+
+	source := NewAutomationReferenceIndex(
+		[]*Instance{{ID: 1, Name: "seedbox"}, {ID: 2, Name: "archive"}, {ID: 3, Name: "nas"}},
+		[]*ExternalProgram{{ID: 9, Name: "notify-script"}},
+	)
+	rule := &Automation{
+		ID:             5,
+		InstanceID:     1,
+		Name:           "export finished",
+		TrackerPattern: "*",
+		Enabled:        true,
+		Conditions: &ActionConditions{
+			Category:         &CategoryAction{Enabled: true, Category: "done"},
+			ExternalProgram:  &ExternalProgramAction{Enabled: true, ProgramID: 9},
+			ExportToInstance: &ExportToInstanceAction{Enabled: true, TargetInstanceID: 2, SavePath: "/data"},
+		},
+		Targets:           &AutomationTargets{InstanceIDs: []int{3}},
+		InstanceOverrides: map[int]*AutomationInstanceOverride{3: {ExportSavePath: new("/mnt/data")}},
+	}
+
+	export, err := ExportAutomations([]*Automation{rule}, source)
+	require.NoError(t, err)
+	require.Len(t, export.Rules, 1)
+
+	data, err := json.Marshal(export)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"programId":9`)
+	assert.NotContains(t, string(data), `"targetInstanceId":2`)
+	assert.Contains(t, string(data), `"exportTargetInstance":"archive"`)
+	assert.Equal(t, 9, rule.Conditions.ExternalProgram.ProgramID, "export must not modify the stored rule")
+
+	// A second qui with different IDs for the same names.
+	target := NewAutomationReferenceIndex(
+		[]*Instance{{ID: 10, Name: "Seedbox"}, {ID: 11, Name: "NAS"}, {ID: 12, Name: "Archive"}},
+		[]*ExternalProgram{{ID: 4, Name: "notify-script"}},
+	)
+	var decoded AutomationExport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	imported, err := decoded.Rules[0].ToAutomation(10, target)
+	require.NoError(t, err)
+	assert.Equal(t, 10, imported.InstanceID)
+	assert.Equal(t, 4, imported.Conditions.ExternalProgram.ProgramID)
+	assert.Equal(t, 12, imported.Conditions.ExportToInstance.TargetInstanceID)
+	assert.Equal(t, "done", imported.Conditions.Category.Category)
+	assert.Equal(t, []int{11}, imported.Targets.InstanceIDs)
+	require.Contains(t, imported.InstanceOverrides, 11)
+	assert.Equal(t, "/mnt/data", *imported.InstanceOverrides[11].ExportSavePath)
+
+	missing := NewAutomationReferenceIndex([]*Instance{{ID: 10, Name: "seedbox"}}, nil)
+	_, err = decoded.Rules[0].ToAutomation(10, missing)
+	require.Error(t, err)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// AutomationObservation is a torrent's current match streak for one rule on one instance.
+// Rows only exist while the torrent keeps matching; a miss removes the row.
+type AutomationObservation struct {
+	AutomationID int    `json:"automationId"`
+	InstanceID   int    `json:"instanceId"`
+	Hash         string `json:"hash"`
+	// Signature identifies the conditions the streak was counted against.
+	Signature      string    `json:"-"`
+	FirstMatchedAt time.Time `json:"firstMatchedAt"`
+	LastMatchedAt  time.Time `json:"lastMatchedAt"`
+	// MatchCount is the number of consecutive rule runs the torrent matched.
+	MatchCount int `json:"matchCount"`
+}
+
+type AutomationObservationStore struct {
+	db dbinterface.Querier
+}
+
+func NewAutomationObservationStore(db dbinterface.Querier) *AutomationObservationStore {
+	return &AutomationObservationStore{db: db}
+}
+
+// ListForInstance returns every stored streak for the instance.
+func (s *AutomationObservationStore) ListForInstance(ctx context.Context, instanceID int) ([]*AutomationObservation, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT automation_id, instance_id, torrent_hash, signature, first_matched_at, last_matched_at, match_count
+		FROM automation_observations
+		WHERE instance_id = ?
+	`, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var observations []*AutomationObservation
+	for rows.Next() {
+		var o AutomationObservation
+		if err := rows.Scan(&o.AutomationID, &o.InstanceID, &o.Hash, &o.Signature, &o.FirstMatchedAt, &o.LastMatchedAt, &o.MatchCount); err != nil {
+			return nil, err
+		}
+		observations = append(observations, &o)
+	}
+
+	return observations, rows.Err()
+}
+
+// ReplaceForRule swaps the stored streaks of a rule on an instance for observations.
+// Torrents missing from observations lose their streak.
+func (s *AutomationObservationStore) ReplaceForRule(ctx context.Context, automationID, instanceID int, observations []*AutomationObservation) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM automation_observations WHERE automation_id = ? AND instance_id = ?`, automationID, instanceID); err != nil {
+		return fmt.Errorf("failed to clear automation observations: %w", err)
+	}
+
+	// 7 params per row keeps full chunks well under SQLite's default 999 variable limit.
+	const chunkSize = 100
+	const paramsPerItem = 7
+
+	queryTemplate := `INSERT INTO automation_observations (
+		automation_id, instance_id, torrent_hash, signature, first_matched_at, last_matched_at, match_count
+	) VALUES %s`
+	fullQuery := dbinterface.BuildQueryWithPlaceholders(queryTemplate, paramsPerItem, chunkSize)
+
+	for i := 0; i < len(observations); i += chunkSize {
+		chunk := observations[i:min(i+chunkSize, len(observations))]
+
+		query := fullQuery
+		if len(chunk) < chunkSize {
+			query = dbinterface.BuildQueryWithPlaceholders(queryTemplate, paramsPerItem, len(chunk))
+		}
+
+		args := make([]any, 0, len(chunk)*paramsPerItem)
+		for _, o := range chunk {
+			args = append(args, automationID, instanceID, o.Hash, o.Signature, o.FirstMatchedAt.UTC(), o.LastMatchedAt.UTC(), o.MatchCount)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to insert automation observations: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteOlderThan removes streaks that have not been extended since cutoff,
+// e.g. for rules that stopped running or torrents that were never revisited.
+func (s *AutomationObservationStore) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM automation_observations WHERE last_matched_at < ?`, cutoff.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
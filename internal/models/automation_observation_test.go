@@ -0,0 +1,74 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomationObservationStoreReplaceForRule(t *testing.T) {
+	db := openSQLiteDB(t)
+	mustExec(t, db, `
+		CREATE TABLE automation_observations (
+			automation_id INTEGER NOT NULL,
+			instance_id INTEGER NOT NULL,
+			torrent_hash TEXT NOT NULL,
+			signature TEXT NOT NULL,
+			first_matched_at DATETIME NOT NULL,
+			last_matched_at DATETIME NOT NULL,
+			match_count INTEGER NOT NULL DEFAULT 1,
+			PRIMARY KEY (automation_id, instance_id, torrent_hash)
+		)
+	`)
+
+	store := NewAutomationObservationStore(&capturingQuerier{db: db})
+	ctx := context.Background()
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	last := first.Add(90 * time.Minute)
+
+	observations := make([]*AutomationObservation, 0, 150)
+	for i := range 150 {
+		observations = append(observations, &AutomationObservation{
+			Hash:           fmt.Sprintf("hash%03d", i),
+			Signature:      "sig",
+			FirstMatchedAt: first,
+			LastMatchedAt:  last,
+			MatchCount:     i + 1,
+		})
+	}
+	require.NoError(t, store.ReplaceForRule(ctx, 1, 10, observations))
+	require.NoError(t, store.ReplaceForRule(ctx, 2, 10, observations[:1]))
+	require.NoError(t, store.ReplaceForRule(ctx, 1, 20, observations[:1]))
+
+	stored, err := store.ListForInstance(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, stored, 151)
+
+	require.NoError(t, store.ReplaceForRule(ctx, 1, 10, observations[:2]))
+	stored, err = store.ListForInstance(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, stored, 3, "replacing one rule leaves other rules alone")
+	for _, o := range stored {
+		assert.Equal(t, 10, o.InstanceID)
+		assert.Equal(t, "sig", o.Signature)
+		assert.True(t, first.Equal(o.FirstMatchedAt))
+		assert.True(t, last.Equal(o.LastMatchedAt))
+	}
+
+	require.NoError(t, store.ReplaceForRule(ctx, 1, 10, nil))
+	stored, err = store.ListForInstance(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, 2, stored[0].AutomationID)
+
+	pruned, err := store.DeleteOlderThan(ctx, last.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), pruned)
+}
@@ -0,0 +1,309 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// Revision actions
+const (
+	AutomationRevisionCreated    = "created"
+	AutomationRevisionUpdated    = "updated"
+	AutomationRevisionRolledBack = "rolled_back"
+	AutomationRevisionImported   = "imported"
+)
+
+// maxAutomationRevisions is how many revisions are kept per rule; older ones are pruned.
+const maxAutomationRevisions = 100
+
+// AutomationRevision is one saved version of an automation rule.
+type AutomationRevision struct {
+	ID           int                     `json:"id"`
+	AutomationID int                     `json:"automationId"`
+	InstanceID   int                     `json:"instanceId"`
+	Revision     int                     `json:"revision"`
+	Action       string                  `json:"action"`
+	ChangedBy    string                  `json:"changedBy,omitempty"`
+	Snapshot     *Automation             `json:"snapshot"`
+	Changes      []AutomationFieldChange `json:"changes,omitempty"` // relative to the previous revision
+	CreatedAt    time.Time               `json:"createdAt"`
+}
+
+// AutomationFieldChange is a single changed leaf value between two revisions.
+// Path uses dotted JSON names, e.g. "conditions.delete.mode" or "trackerDomains.1".
+type AutomationFieldChange struct {
+	Path   string          `json:"path"`
+	Before json.RawMessage `json:"before,omitempty"` // omitted when the field was added
+	After  json.RawMessage `json:"after,omitempty"`  // omitted when the field was removed
+}
+
+type AutomationRevisionStore struct {
+	db dbinterface.Querier
+}
+
+func NewAutomationRevisionStore(db dbinterface.Querier) *AutomationRevisionStore {
+	return &AutomationRevisionStore{db: db}
+}
+
+// Record stores automation as a new revision, computing the changes from the
+// latest existing revision. Returns nil without error when nothing changed.
+func (s *AutomationRevisionStore) Record(ctx context.Context, automation *Automation, action, changedBy string) (*AutomationRevision, error) {
+	if s == nil || s.db == nil || automation == nil {
+		return nil, nil
+	}
+
+	snapshot, err := marshalRevisionSnapshot(automation)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastRevision int
+	var lastSnapshot sql.NullString
+	err = s.db.QueryRowContext(ctx, `
+		SELECT revision, snapshot
+		FROM automation_revisions
+		WHERE automation_id = ?
+		ORDER BY revision DESC
+		LIMIT 1
+	`, automation.ID).Scan(&lastRevision, &lastSnapshot)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	var changes []AutomationFieldChange
+	if lastSnapshot.Valid {
+		changes, err = diffRevisionSnapshots([]byte(lastSnapshot.String), snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 && action == AutomationRevisionUpdated {
+			return nil, nil
+		}
+	}
+
+	var changesJSON sql.NullString
+	if len(changes) > 0 {
+		data, marshalErr := json.Marshal(changes)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal changes: %w", marshalErr)
+		}
+		changesJSON = sql.NullString{String: string(data), Valid: true}
+	}
+
+	var changedByValue sql.NullString
+	if changedBy != "" {
+		changedByValue = sql.NullString{String: changedBy, Valid: true}
+	}
+
+	var id int
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO automation_revisions
+			(automation_id, instance_id, revision, action, changed_by, snapshot, changes)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`, automation.ID, automation.InstanceID, lastRevision+1, action, changedByValue, string(snapshot), changesJSON).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.ExecContext(ctx, `
+		DELETE FROM automation_revisions
+		WHERE automation_id = ? AND revision <= ?
+	`, automation.ID, lastRevision+1-maxAutomationRevisions); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, automation.InstanceID, automation.ID, id)
+}
+
+// ListByAutomation returns the revisions of a rule, newest first.
+func (s *AutomationRevisionStore) ListByAutomation(ctx context.Context, instanceID, automationID int) ([]*AutomationRevision, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, automation_id, instance_id, revision, action, changed_by, snapshot, changes, created_at
+		FROM automation_revisions
+		WHERE instance_id = ? AND automation_id = ?
+		ORDER BY revision DESC
+	`, instanceID, automationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*AutomationRevision
+	for rows.Next() {
+		revision, err := scanAutomationRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// Get returns a single revision by its row ID.
+func (s *AutomationRevisionStore) Get(ctx context.Context, instanceID, automationID, id int) (*AutomationRevision, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, automation_id, instance_id, revision, action, changed_by, snapshot, changes, created_at
+		FROM automation_revisions
+		WHERE id = ? AND instance_id = ? AND automation_id = ?
+	`, id, instanceID, automationID)
+
+	return scanAutomationRevision(row)
+}
+
+func scanAutomationRevision(scanner automationScanner) (*AutomationRevision, error) {
+	var revision AutomationRevision
+	var changedBy sql.NullString
+	var snapshotJSON string
+	var changesJSON sql.NullString
+
+	if err := scanner.Scan(
+		&revision.ID,
+		&revision.AutomationID,
+		&revision.InstanceID,
+		&revision.Revision,
+		&revision.Action,
+		&changedBy,
+		&snapshotJSON,
+		&changesJSON,
+		&revision.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	revision.ChangedBy = changedBy.String
+
+	var snapshot Automation
+	if err := json.Unmarshal([]byte(snapshotJSON), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot for automation revision %d: %w", revision.ID, err)
+	}
+	snapshot.Conditions.Normalize()
+	revision.Snapshot = &snapshot
+
+	if changesJSON.Valid && changesJSON.String != "" {
+		if err := json.Unmarshal([]byte(changesJSON.String), &revision.Changes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal changes for automation revision %d: %w", revision.ID, err)
+		}
+	}
+
+	return &revision, nil
+}
+
+// marshalRevisionSnapshot serializes the user-editable part of a rule.
+func marshalRevisionSnapshot(automation *Automation) ([]byte, error) {
+	snapshot := *automation
+	snapshot.NextRunAt = nil
+	snapshot.CreatedAt = time.Time{}
+	snapshot.UpdatedAt = time.Time{}
+	data, err := json.Marshal(&snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal automation snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// revisionIgnoredFields are bookkeeping fields that never count as a change.
+var revisionIgnoredFields = []string{"id", "instanceId", "createdAt", "updatedAt", "nextRunAt", "sortOrder"}
+
+func diffRevisionSnapshots(before, after []byte) ([]AutomationFieldChange, error) {
+	var beforeValue, afterValue any
+	if err := json.Unmarshal(before, &beforeValue); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(after, &afterValue); err != nil {
+		return nil, err
+	}
+	if beforeMap, ok := beforeValue.(map[string]any); ok {
+		for _, field := range revisionIgnoredFields {
+			delete(beforeMap, field)
+		}
+	}
+	if afterMap, ok := afterValue.(map[string]any); ok {
+		for _, field := range revisionIgnoredFields {
+			delete(afterMap, field)
+		}
+	}
+
+	var changes []AutomationFieldChange
+	diffJSONValues("", beforeValue, afterValue, &changes)
+	slices.SortFunc(changes, func(a, b AutomationFieldChange) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return changes, nil
+}
+
+func diffJSONValues(path string, before, after any, changes *[]AutomationFieldChange) {
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	if beforeIsMap && afterIsMap {
+		for key, value := range beforeMap {
+			diffJSONValues(joinChangePath(path, key), value, afterMap[key], changes)
+		}
+		for key, value := range afterMap {
+			if _, ok := beforeMap[key]; !ok {
+				diffJSONValues(joinChangePath(path, key), nil, value, changes)
+			}
+		}
+		return
+	}
+
+	beforeSlice, beforeIsSlice := before.([]any)
+	afterSlice, afterIsSlice := after.([]any)
+	if beforeIsSlice && afterIsSlice {
+		for i := range max(len(beforeSlice), len(afterSlice)) {
+			var b, a any
+			if i < len(beforeSlice) {
+				b = beforeSlice[i]
+			}
+			if i < len(afterSlice) {
+				a = afterSlice[i]
+			}
+			diffJSONValues(joinChangePath(path, strconv.Itoa(i)), b, a, changes)
+		}
+		return
+	}
+
+	beforeJSON := rawJSONOrNil(before)
+	afterJSON := rawJSONOrNil(after)
+	if bytes.Equal(beforeJSON, afterJSON) {
+		return
+	}
+	*changes = append(*changes, AutomationFieldChange{Path: path, Before: beforeJSON, After: afterJSON})
+}
+
+func joinChangePath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func rawJSONOrNil(value any) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return data
+}
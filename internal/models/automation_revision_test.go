@@ -0,0 +1,101 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRevisionSnapshots(t *testing.T) {
+	t.Parallel()
+
+	before, err := marshalRevisionSnapshot(&Automation{
+		ID:             1,
+		Name:           "cleanup",
+		TrackerDomains: []string{"a.example"},
+		SortOrder:      1,
+		Conditions: &ActionConditions{
+			Delete: &DeleteAction{Enabled: true, Mode: DeleteModeKeepFiles},
+		},
+	})
+	require.NoError(t, err)
+
+	after, err := marshalRevisionSnapshot(&Automation{
+		ID:             1,
+		Name:           "cleanup",
+		TrackerDomains: []string{"a.example", "b.example"},
+		SortOrder:      4,
+		Conditions: &ActionConditions{
+			Delete: &DeleteAction{Enabled: true, Mode: DeleteModeWithFiles},
+		},
+	})
+	require.NoError(t, err)
+
+	changes, err := diffRevisionSnapshots(before, after)
+	require.NoError(t, err)
+
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	assert.Equal(t, []string{"conditions.delete.mode", "trackerDomains.1"}, paths, "sortOrder is not a content change")
+	assert.JSONEq(t, `"`+DeleteModeKeepFiles+`"`, string(changes[0].Before))
+	assert.JSONEq(t, `"`+DeleteModeWithFiles+`"`, string(changes[0].After))
+	assert.Nil(t, changes[1].Before)
+	assert.JSONEq(t, `"b.example"`, string(changes[1].After))
+}
+
+func TestAutomationRevisionStoreRecord(t *testing.T) {
+	db := openSQLiteDB(t)
+	mustExec(t, db, `
+		CREATE TABLE automation_revisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			automation_id INTEGER NOT NULL,
+			instance_id INTEGER NOT NULL,
+			revision INTEGER NOT NULL,
+			action TEXT NOT NULL,
+			changed_by TEXT,
+			snapshot TEXT NOT NULL,
+			changes TEXT,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (automation_id, revision)
+		)
+	`)
+
+	store := NewAutomationRevisionStore(&capturingQuerier{db: db})
+	ctx := context.Background()
+
+	rule := &Automation{
+		ID:         7,
+		InstanceID: 1,
+		Name:       "cleanup",
+		Conditions: &ActionConditions{Pause: &PauseAction{Enabled: true}},
+	}
+	first, err := store.Record(ctx, rule, AutomationRevisionCreated, "admin")
+	require.NoError(t, err)
+	require.Equal(t, 1, first.Revision)
+	assert.Empty(t, first.Changes)
+
+	unchanged, err := store.Record(ctx, rule, AutomationRevisionUpdated, "admin")
+	require.NoError(t, err)
+	assert.Nil(t, unchanged, "saving an identical rule does not create a revision")
+
+	rule.Name = "cleanup v2"
+	second, err := store.Record(ctx, rule, AutomationRevisionUpdated, "someone")
+	require.NoError(t, err)
+	require.Equal(t, 2, second.Revision)
+	require.Len(t, second.Changes, 1)
+	assert.Equal(t, "name", second.Changes[0].Path)
+	assert.Equal(t, "someone", second.ChangedBy)
+
+	revisions, err := store.ListByAutomation(ctx, 1, 7)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Revision)
+	assert.Equal(t, "cleanup", revisions[1].Snapshot.Name)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/pkg/cronexpr"
+)
+
+// AutomationSchedule configures when an interval-triggered rule runs.
+// This is the structure stored in the `schedule` JSON column.
+type AutomationSchedule struct {
+	// Cron replaces the per-rule interval with a five-field cron expression.
+	Cron string `json:"cron,omitempty"`
+	// Timezone is an IANA zone name used for Cron and ActiveWindow. Empty = server local time.
+	Timezone string `json:"timezone,omitempty"`
+	// ActiveWindow restricts the rule to certain days and hours.
+	ActiveWindow *ActiveWindow `json:"activeWindow,omitempty"`
+}
+
+// ActiveWindow is a recurring daily time range a rule is allowed to run in.
+// A window whose End is before its Start wraps past midnight; Days then refers
+// to the day the window opens.
+type ActiveWindow struct {
+	Days  []int  `json:"days,omitempty"` // 0=Sunday..6=Saturday; empty = every day
+	Start string `json:"start"`          // "HH:MM", inclusive
+	End   string `json:"end"`            // "HH:MM", exclusive; equal to Start = whole day
+}
+
+// IsEmpty returns true if the schedule has no effect.
+func (s *AutomationSchedule) IsEmpty() bool {
+	return s == nil || (strings.TrimSpace(s.Cron) == "" && s.ActiveWindow == nil)
+}
+
+// Validate checks the cron expression, time zone and active window.
+func (s *AutomationSchedule) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if _, err := s.Location(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(s.Cron) != "" {
+		if _, err := cronexpr.Parse(s.Cron); err != nil {
+			return fmt.Errorf("invalid cron expression: %w", err)
+		}
+	}
+	if s.ActiveWindow != nil {
+		if err := s.ActiveWindow.Validate(); err != nil {
+			return fmt.Errorf("invalid active window: %w", err)
+		}
+	}
+	return nil
+}
+
+// Location resolves the configured time zone, defaulting to time.Local.
+func (s *AutomationSchedule) Location() (*time.Location, error) {
+	if s == nil || strings.TrimSpace(s.Timezone) == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(strings.TrimSpace(s.Timezone))
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+	}
+	return loc, nil
+}
+
+// CronSchedule parses the cron expression. Returns nil when no cron is configured.
+func (s *AutomationSchedule) CronSchedule() (*cronexpr.Schedule, error) {
+	if s == nil || strings.TrimSpace(s.Cron) == "" {
+		return nil, nil
+	}
+	return cronexpr.Parse(s.Cron)
+}
+
+// InWindow reports whether t falls inside the active window (always true without one).
+func (s *AutomationSchedule) InWindow(t time.Time) bool {
+	if s == nil || s.ActiveWindow == nil {
+		return true
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return false
+	}
+	return s.ActiveWindow.Contains(t.In(loc))
+}
+
+// NextWindowOpen returns t if it is inside the active window, otherwise the next
+// time the window opens. Returns the zero time if the window never opens.
+func (s *AutomationSchedule) NextWindowOpen(t time.Time) time.Time {
+	if s == nil || s.ActiveWindow == nil {
+		return t
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return time.Time{}
+	}
+	return s.ActiveWindow.NextOpen(t.In(loc))
+}
+
+// Validate checks the window's days and clock times.
+func (w *ActiveWindow) Validate() error {
+	if w == nil {
+		return nil
+	}
+	for _, day := range w.Days {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("day %d out of range (0=Sunday..6=Saturday)", day)
+		}
+	}
+	if _, err := parseClockMinutes(w.Start); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	if _, err := parseClockMinutes(w.End); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	return nil
+}
+
+// Contains reports whether t (already in the window's location) is inside the window.
+func (w *ActiveWindow) Contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	start, errStart := parseClockMinutes(w.Start)
+	end, errEnd := parseClockMinutes(w.End)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	today := int(t.Weekday())
+	yesterday := (today + 6) % 7
+
+	switch {
+	case start == end:
+		return w.dayAllowed(today)
+	case start < end:
+		return w.dayAllowed(today) && minute >= start && minute < end
+	default:
+		// Wraps past midnight: the evening part belongs to today, the
+		// early-morning part to the window that opened yesterday.
+		return (minute >= start && w.dayAllowed(today)) || (minute < end && w.dayAllowed(yesterday))
+	}
+}
+
+// NextOpen returns t if it is inside the window, otherwise the next opening time.
+func (w *ActiveWindow) NextOpen(t time.Time) time.Time {
+	if w == nil || w.Contains(t) {
+		return t
+	}
+	start, err := parseClockMinutes(w.Start)
+	if err != nil {
+		return time.Time{}
+	}
+	for offset := 0; offset <= 7; offset++ {
+		day := t.AddDate(0, 0, offset)
+		open := time.Date(day.Year(), day.Month(), day.Day(), start/60, start%60, 0, 0, t.Location())
+		if open.After(t) && w.dayAllowed(int(open.Weekday())) {
+			return open
+		}
+	}
+	return time.Time{}
+}
+
+func (w *ActiveWindow) dayAllowed(day int) bool {
+	return len(w.Days) == 0 || slices.Contains(w.Days, day)
+}
+
+// parseClockMinutes parses "HH:MM" into minutes after midnight.
+func parseClockMinutes(value string) (int, error) {
+	hourPart, minutePart, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	hour, errHour := strconv.Atoi(hourPart)
+	minute, errMinute := strconv.Atoi(minutePart)
+	if errHour != nil || errMinute != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, errors.New("time out of range (00:00-23:59)")
+	}
+	return hour*60 + minute, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomationScheduleValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := []*AutomationSchedule{
+		nil,
+		{Cron: "0 2 * * *"},
+		{Cron: "@daily", Timezone: "Europe/Berlin"},
+		{ActiveWindow: &ActiveWindow{Days: []int{1, 2, 3, 4, 5}, Start: "01:00", End: "06:00"}},
+		{ActiveWindow: &ActiveWindow{Start: "22:00", End: "04:30"}, Timezone: "America/New_York"},
+	}
+	for _, sched := range valid {
+		require.NoError(t, sched.Validate())
+	}
+
+	invalid := []*AutomationSchedule{
+		{Cron: "every night"},
+		{Timezone: "Mars/Olympus_Mons"},
+		{ActiveWindow: &ActiveWindow{Start: "25:00", End: "06:00"}},
+		{ActiveWindow: &ActiveWindow{Start: "01:00", End: "6"}},
+		{ActiveWindow: &ActiveWindow{Days: []int{7}, Start: "01:00", End: "06:00"}},
+	}
+	for _, sched := range invalid {
+		require.Error(t, sched.Validate(), "%+v", sched)
+	}
+}
+
+func TestActiveWindowContains(t *testing.T) {
+	t.Parallel()
+
+	// 2026-03-04 is a Wednesday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	weekdayNights := &ActiveWindow{Days: []int{1, 2, 3, 4, 5}, Start: "01:00", End: "06:00"}
+	assert.True(t, weekdayNights.Contains(at(4, 1, 0)))
+	assert.True(t, weekdayNights.Contains(at(4, 5, 59)))
+	assert.False(t, weekdayNights.Contains(at(4, 6, 0)))
+	assert.False(t, weekdayNights.Contains(at(4, 0, 59)))
+	assert.False(t, weekdayNights.Contains(at(7, 2, 0)), "Saturday is not in the window")
+
+	// Wrapping window opened on Friday night runs into Saturday morning.
+	fridayLate := &ActiveWindow{Days: []int{5}, Start: "22:00", End: "04:00"}
+	assert.True(t, fridayLate.Contains(at(6, 23, 0)))
+	assert.True(t, fridayLate.Contains(at(7, 3, 59)))
+	assert.False(t, fridayLate.Contains(at(7, 22, 30)))
+	assert.False(t, fridayLate.Contains(at(6, 3, 0)), "Friday early morning belongs to Thursday's window")
+
+	allDay := &ActiveWindow{Days: []int{0}, Start: "00:00", End: "00:00"}
+	assert.True(t, allDay.Contains(at(8, 13, 0)))
+	assert.False(t, allDay.Contains(at(9, 13, 0)))
+}
+
+func TestAutomationScheduleNextWindowOpen(t *testing.T) {
+	t.Parallel()
+
+	sched := &AutomationSchedule{
+		Timezone:     "UTC",
+		ActiveWindow: &ActiveWindow{Days: []int{1, 2, 3, 4, 5}, Start: "01:00", End: "06:00"},
+	}
+
+	inside := time.Date(2026, time.March, 4, 2, 0, 0, 0, time.UTC)
+	assert.Equal(t, inside, sched.NextWindowOpen(inside))
+
+	// Friday afternoon -> Monday 01:00.
+	friday := time.Date(2026, time.March, 6, 15, 0, 0, 0, time.UTC)
+	assert.True(t, sched.NextWindowOpen(friday).Equal(time.Date(2026, time.March, 9, 1, 0, 0, 0, time.UTC)))
+
+	assert.True(t, (*AutomationSchedule)(nil).InWindow(friday))
+	assert.True(t, (*AutomationSchedule)(nil).IsEmpty())
+}
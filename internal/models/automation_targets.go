@@ -0,0 +1,134 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AutomationTargets makes a rule run on instances besides the one that owns it.
+// This is the structure stored in the `targets` JSON column.
+type AutomationTargets struct {
+	AllInstances bool  `json:"allInstances,omitempty"` // every instance, including ones added later
+	InstanceIDs  []int `json:"instanceIds,omitempty"`  // additional instances; the owner is always included
+}
+
+// IsEmpty returns true if the rule only runs on its owning instance.
+func (t *AutomationTargets) IsEmpty() bool {
+	return t == nil || (!t.AllInstances && len(t.InstanceIDs) == 0)
+}
+
+// Includes reports whether the targets cover instanceID (the owner is not implied).
+func (t *AutomationTargets) Includes(instanceID int) bool {
+	if t == nil {
+		return false
+	}
+	return t.AllInstances || slices.Contains(t.InstanceIDs, instanceID)
+}
+
+// Normalize sorts and de-duplicates InstanceIDs and drops the owner and invalid IDs.
+func (t *AutomationTargets) Normalize(ownerInstanceID int) {
+	if t == nil {
+		return
+	}
+	if t.AllInstances {
+		t.InstanceIDs = nil
+		return
+	}
+	ids := make([]int, 0, len(t.InstanceIDs))
+	for _, id := range t.InstanceIDs {
+		if id > 0 && id != ownerInstanceID {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	t.InstanceIDs = slices.Compact(ids)
+}
+
+// AutomationInstanceOverride replaces path settings of a shared rule when it runs
+// on a specific instance. Nil fields keep the rule's own value.
+type AutomationInstanceOverride struct {
+	MovePath       *string `json:"movePath,omitempty"`       // replaces MoveAction.Path
+	ExportSavePath *string `json:"exportSavePath,omitempty"` // replaces ExportToInstanceAction.SavePath
+	FreeSpacePath  *string `json:"freeSpacePath,omitempty"`  // replaces FreeSpaceSource.Path (type "path" only)
+}
+
+// IsEmpty returns true if the override changes nothing.
+func (o *AutomationInstanceOverride) IsEmpty() bool {
+	return o == nil || (o.MovePath == nil && o.ExportSavePath == nil && o.FreeSpacePath == nil)
+}
+
+// IsShared returns true if the rule runs on instances other than its owner.
+func (a *Automation) IsShared() bool {
+	return a != nil && !a.Targets.IsEmpty()
+}
+
+// AppliesTo reports whether the rule should be evaluated on instanceID.
+func (a *Automation) AppliesTo(instanceID int) bool {
+	if a == nil {
+		return false
+	}
+	return a.InstanceID == instanceID || a.Targets.Includes(instanceID)
+}
+
+// ForInstance returns the rule as it should run on instanceID, with that
+// instance's path overrides applied. The receiver is never modified; when there
+// is no override the receiver itself is returned.
+func (a *Automation) ForInstance(instanceID int) *Automation {
+	if a == nil {
+		return nil
+	}
+	override := a.InstanceOverrides[instanceID]
+	if override.IsEmpty() {
+		return a
+	}
+
+	cloned := *a
+	if a.Conditions != nil {
+		conditions := *a.Conditions
+		if override.MovePath != nil && conditions.Move != nil {
+			move := *conditions.Move
+			move.Path = *override.MovePath
+			conditions.Move = &move
+		}
+		if override.ExportSavePath != nil && conditions.ExportToInstance != nil {
+			export := *conditions.ExportToInstance
+			export.SavePath = *override.ExportSavePath
+			conditions.ExportToInstance = &export
+		}
+		cloned.Conditions = &conditions
+	}
+	if override.FreeSpacePath != nil && a.FreeSpaceSource != nil && a.FreeSpaceSource.Type == FreeSpaceSourcePath {
+		source := *a.FreeSpaceSource
+		source.Path = *override.FreeSpacePath
+		cloned.FreeSpaceSource = &source
+	}
+	return &cloned
+}
+
+// validateSharing checks targets and overrides against the rule's owner.
+func (a *Automation) validateSharing() error {
+	if a.Targets != nil {
+		a.Targets.Normalize(a.InstanceID)
+	}
+	for instanceID, override := range a.InstanceOverrides {
+		if instanceID <= 0 {
+			return fmt.Errorf("invalid instance override key %d", instanceID)
+		}
+		if override.IsEmpty() {
+			delete(a.InstanceOverrides, instanceID)
+			continue
+		}
+		if !a.AppliesTo(instanceID) {
+			return fmt.Errorf("instance override for instance %d which the rule does not target", instanceID)
+		}
+		if override.FreeSpacePath != nil && strings.TrimSpace(*override.FreeSpacePath) == "" {
+			return errors.New("free space path override cannot be empty")
+		}
+	}
+	return nil
+}
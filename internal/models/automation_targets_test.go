@@ -0,0 +1,128 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomationForInstanceAppliesOverrides(t *testing.T) {
+	t.Parallel()
+
+	rule := &Automation{
+		ID:         1,
+		InstanceID: 1,
+		Targets:    &AutomationTargets{InstanceIDs: []int{2, 3}},
+		Conditions: &ActionConditions{
+			Move: &MoveAction{Enabled: true, Path: "/data/archive"},
+		},
+		FreeSpaceSource: &FreeSpaceSource{Type: FreeSpaceSourcePath, Path: "/data"},
+		InstanceOverrides: map[int]*AutomationInstanceOverride{
+			2: {MovePath: new("/mnt/pool/archive"), FreeSpacePath: new("/mnt/pool")},
+		},
+	}
+
+	assert.True(t, rule.AppliesTo(1))
+	assert.True(t, rule.AppliesTo(2))
+	assert.False(t, rule.AppliesTo(4))
+
+	onTwo := rule.ForInstance(2)
+	assert.Equal(t, "/mnt/pool/archive", onTwo.Conditions.Move.Path)
+	assert.Equal(t, "/mnt/pool", onTwo.FreeSpaceSource.Path)
+	assert.Equal(t, "/data/archive", rule.Conditions.Move.Path, "stored rule must not be modified")
+	assert.Equal(t, "/data", rule.FreeSpaceSource.Path)
+
+	assert.Same(t, rule, rule.ForInstance(3))
+}
+
+func TestAutomationValidateSharing(t *testing.T) {
+	t.Parallel()
+
+	rule := &Automation{
+		InstanceID: 1,
+		Targets:    &AutomationTargets{InstanceIDs: []int{3, 1, 2, 3, 0}},
+	}
+	require.NoError(t, rule.validateSharing())
+	assert.Equal(t, []int{2, 3}, rule.Targets.InstanceIDs)
+
+	rule.InstanceOverrides = map[int]*AutomationInstanceOverride{5: {MovePath: new("/x")}}
+	require.Error(t, rule.validateSharing(), "override for an instance the rule does not target")
+
+	rule.Targets = &AutomationTargets{AllInstances: true, InstanceIDs: []int{2}}
+	require.NoError(t, rule.validateSharing())
+	assert.Nil(t, rule.Targets.InstanceIDs)
+}
+
+func TestAutomationStoreListForInstanceIncludesSharedRules(t *testing.T) {
+	db := openSQLiteDB(t)
+	mustExec(t, db, `
+		CREATE TABLE automations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			instance_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			tracker_pattern TEXT NOT NULL,
+			conditions TEXT NOT NULL,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			dry_run INTEGER NOT NULL DEFAULT 0,
+			notify INTEGER NOT NULL DEFAULT 1,
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			interval_seconds INTEGER,
+			trigger_mode TEXT NOT NULL DEFAULT 'interval',
+			schedule TEXT,
+			targets TEXT,
+			instance_overrides TEXT,
+			free_space_source TEXT,
+			sorting_config TEXT,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+
+	store := NewAutomationStore(&capturingQuerier{db: db})
+	ctx := context.Background()
+	conditions := func(path string) *ActionConditions {
+		return &ActionConditions{Move: &MoveAction{Enabled: true, Path: path}}
+	}
+
+	_, err := store.Create(ctx, &Automation{InstanceID: 1, Name: "local", TrackerPattern: "*", Conditions: conditions("/a"), Enabled: true})
+	require.NoError(t, err)
+	shared, err := store.Create(ctx, &Automation{
+		InstanceID:        1,
+		Name:              "shared",
+		TrackerPattern:    "*",
+		Conditions:        conditions("/a"),
+		Enabled:           true,
+		Targets:           &AutomationTargets{InstanceIDs: []int{2}},
+		InstanceOverrides: map[int]*AutomationInstanceOverride{2: {MovePath: new("/b")}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, shared.Targets)
+	assert.Equal(t, []int{2}, shared.Targets.InstanceIDs)
+	_, err = store.Create(ctx, &Automation{InstanceID: 3, Name: "everywhere", TrackerPattern: "*", Conditions: conditions("/c"), Enabled: true, Targets: &AutomationTargets{AllInstances: true}})
+	require.NoError(t, err)
+
+	forTwo, err := store.ListForInstance(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, forTwo, 2)
+	names := []string{forTwo[0].Name, forTwo[1].Name}
+	assert.ElementsMatch(t, []string{"shared", "everywhere"}, names)
+	for _, rule := range forTwo {
+		if rule.Name == "shared" {
+			assert.Equal(t, "/b", rule.Conditions.Move.Path)
+		}
+	}
+
+	owned, err := store.ListByInstance(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, owned, 2)
+
+	sharedWithOne, err := store.ListSharedWith(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, sharedWithOne, 1)
+	assert.Equal(t, "everywhere", sharedWithOne[0].Name)
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/autobrr/qui/internal/domain"
+)
+
+type BackupDestinationType string
+
+const (
+	BackupDestinationS3     BackupDestinationType = "s3"
+	BackupDestinationWebDAV BackupDestinationType = "webdav"
+	BackupDestinationSFTP   BackupDestinationType = "sftp"
+)
+
+// BackupDestination is an offsite location backup runs are uploaded to.
+//
+// Endpoint is the S3 or WebDAV base URL, or host:port for SFTP. For S3,
+// Username and Password are the access key ID and secret key. Path is the
+// key prefix or directory the runs are stored under.
+type BackupDestination struct {
+	Type       BackupDestinationType `json:"type"`
+	Endpoint   string                `json:"endpoint"`
+	Bucket     string                `json:"bucket,omitempty"`
+	Region     string                `json:"region,omitempty"`
+	PathStyle  bool                  `json:"pathStyle,omitempty"`
+	Path       string                `json:"path,omitempty"`
+	Username   string                `json:"username,omitempty"`
+	Password   string                `json:"password,omitempty"`
+	PrivateKey string                `json:"privateKey,omitempty"`
+	HostKey    string                `json:"hostKey,omitempty"`
+}
+
+// Redacted returns a copy with the password and private key replaced by the
+// redacted placeholder.
+func (d *BackupDestination) Redacted() *BackupDestination {
+	if d == nil {
+		return nil
+	}
+	out := *d
+	out.Password = domain.RedactString(d.Password)
+	out.PrivateKey = domain.RedactString(d.PrivateKey)
+	return &out
+}
+
+// KeepRedactedSecrets replaces redacted secrets in d with the ones from
+// existing, so a client can send settings back without knowing the secrets.
+func (d *BackupDestination) KeepRedactedSecrets(existing *BackupDestination) {
+	if d == nil {
+		return
+	}
+	var password, privateKey string
+	if existing != nil {
+		password, privateKey = existing.Password, existing.PrivateKey
+	}
+	if domain.IsRedactedString(d.Password) {
+		d.Password = password
+	}
+	if domain.IsRedactedString(d.PrivateKey) {
+		d.PrivateKey = privateKey
+	}
+}
+
+// SetEncryptionKey sets the key destination secrets, backup encryption
+// settings and captured torrent state are encrypted with. Settings holding
+// either secret can't be saved or loaded without it.
+func (s *BackupStore) SetEncryptionKey(key []byte) error {
+	if len(key) != 32 {
+		return errors.New("encryption key must be 32 bytes")
+	}
+	s.encryptionKey = key
+	return nil
+}
+
+func (s *BackupStore) encrypt(plaintext string) (string, error) {
+	if len(s.encryptionKey) == 0 {
+		return "", errors.New("backup store has no encryption key")
+	}
+	block, err := aes.NewCipher(s.encryptionKey)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func (s *BackupStore) decrypt(ciphertext string) (string, error) {
+	if len(s.encryptionKey) == 0 {
+		return "", errors.New("backup store has no encryption key")
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(s.encryptionKey)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("malformed ciphertext")
+	}
+	nonce, ciphertextBytes := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertextBytes, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// marshalDestination encodes d for the destination_json column with its
+// secrets encrypted.
+func (s *BackupStore) marshalDestination(d *BackupDestination) (*string, error) {
+	if d == nil {
+		return nil, nil
+	}
+	stored := *d
+	if stored.Password != "" {
+		encrypted, err := s.encrypt(stored.Password)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt destination password: %w", err)
+		}
+		stored.Password = encrypted
+	}
+	if stored.PrivateKey != "" {
+		encrypted, err := s.encrypt(stored.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt destination private key: %w", err)
+		}
+		stored.PrivateKey = encrypted
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, err
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+func (s *BackupStore) unmarshalDestination(raw sql.NullString) (*BackupDestination, error) {
+	if !raw.Valid || raw.String == "" {
+		return nil, nil
+	}
+	var d BackupDestination
+	if err := json.Unmarshal([]byte(raw.String), &d); err != nil {
+		return nil, fmt.Errorf("decode backup destination: %w", err)
+	}
+	if d.Password != "" {
+		decrypted, err := s.decrypt(d.Password)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt destination password: %w", err)
+		}
+		d.Password = decrypted
+	}
+	if d.PrivateKey != "" {
+		decrypted, err := s.decrypt(d.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt destination private key: %w", err)
+		}
+		d.PrivateKey = decrypted
+	}
+	return &d, nil
+}
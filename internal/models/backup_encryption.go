@@ -0,0 +1,142 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/autobrr/qui/internal/domain"
+)
+
+type BackupEncryptionMode string
+
+const (
+	// BackupEncryptionPassphrase derives the key from a passphrase.
+	BackupEncryptionPassphrase BackupEncryptionMode = "passphrase"
+	// BackupEncryptionAge uses an age X25519 identity (AGE-SECRET-KEY-1...).
+	BackupEncryptionAge BackupEncryptionMode = "age"
+)
+
+// BackupEncryption is the at-rest encryption key for an instance's backups.
+//
+// Recipient is the public age key matching the current key; it is derived
+// when the settings are saved. Salt is the random scrypt salt a passphrase
+// key is derived with. RetiredIdentities keeps the keys this one
+// replaced, so runs written with them stay readable. An empty Mode means
+// encryption is off; the value then only exists to keep retired keys.
+type BackupEncryption struct {
+	Mode              BackupEncryptionMode `json:"mode"`
+	Passphrase        string               `json:"passphrase,omitempty"`
+	Identity          string               `json:"identity,omitempty"`
+	Recipient         string               `json:"recipient,omitempty"`
+	Salt              string               `json:"salt,omitempty"`
+	RetiredIdentities []string             `json:"retiredIdentities,omitempty"`
+}
+
+// Enabled reports whether new backups should be encrypted.
+func (e *BackupEncryption) Enabled() bool {
+	return e != nil && e.Mode != ""
+}
+
+// Redacted returns a copy safe to send to clients: secrets are replaced by
+// the redacted placeholder and retired keys are dropped. Returns nil when
+// encryption is off.
+func (e *BackupEncryption) Redacted() *BackupEncryption {
+	if !e.Enabled() {
+		return nil
+	}
+	return &BackupEncryption{
+		Mode:       e.Mode,
+		Passphrase: domain.RedactString(e.Passphrase),
+		Identity:   domain.RedactString(e.Identity),
+		Recipient:  e.Recipient,
+	}
+}
+
+// KeepRedactedSecrets replaces redacted secrets in e with the ones from
+// existing, so a client can send settings back without knowing the secrets.
+func (e *BackupEncryption) KeepRedactedSecrets(existing *BackupEncryption) {
+	if e == nil {
+		return
+	}
+	var passphrase, identity string
+	if existing != nil {
+		passphrase, identity = existing.Passphrase, existing.Identity
+	}
+	if domain.IsRedactedString(e.Passphrase) {
+		e.Passphrase = passphrase
+	}
+	if domain.IsRedactedString(e.Identity) {
+		e.Identity = identity
+	}
+}
+
+// marshalEncryption encodes e for the encryption_json column. Unlike the
+// destination, every field is a secret or derived from one, so the whole
+// document is encrypted.
+func (s *BackupStore) marshalEncryption(e *BackupEncryption) (*string, error) {
+	if e == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.encrypt(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("encrypt backup encryption settings: %w", err)
+	}
+	return &encrypted, nil
+}
+
+func (s *BackupStore) unmarshalEncryption(raw sql.NullString) (*BackupEncryption, error) {
+	if !raw.Valid || raw.String == "" {
+		return nil, nil
+	}
+	decrypted, err := s.decrypt(raw.String)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt backup encryption settings: %w", err)
+	}
+	var e BackupEncryption
+	if err := json.Unmarshal([]byte(decrypted), &e); err != nil {
+		return nil, fmt.Errorf("decode backup encryption settings: %w", err)
+	}
+	return &e, nil
+}
+
+// ListEncryptionKeys returns the encryption settings of every instance that
+// has any, enabled or only holding retired keys.
+func (s *BackupStore) ListEncryptionKeys(ctx context.Context) ([]*BackupEncryption, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT encryption_json
+		FROM instance_backup_settings
+		WHERE encryption_json IS NOT NULL AND encryption_json != ''
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*BackupEncryption
+	for rows.Next() {
+		var raw sql.NullString
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		e, err := s.unmarshalEncryption(raw)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			keys = append(keys, e)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
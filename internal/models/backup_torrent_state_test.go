@@ -0,0 +1,40 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTorrentStateEncryptedAtRest(t *testing.T) {
+	t.Parallel()
+
+	store := NewBackupStore(nil)
+	require.NoError(t, store.SetEncryptionKey(make([]byte, 32)))
+
+	state := &BackupTorrentState{RatioLimit: 2, Trackers: []string{"https://tracker.example/abcdef123456/announce"}}
+	raw, err := store.marshalTorrentState(state)
+	require.NoError(t, err)
+	require.NotNil(t, raw)
+	require.NotContains(t, *raw, "abcdef123456")
+
+	decoded, err := store.unmarshalTorrentState(sql.NullString{String: *raw, Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, state, decoded)
+
+	// A store without a key writes plain JSON, which stays readable.
+	plain, err := NewBackupStore(nil).marshalTorrentState(state)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(*plain, "{"))
+	decoded, err = store.unmarshalTorrentState(sql.NullString{String: *plain, Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, state, decoded)
+
+	_, err = NewBackupStore(nil).unmarshalTorrentState(sql.NullString{String: *raw, Valid: true})
+	require.Error(t, err)
+}
@@ -17,22 +17,24 @@ import (
 )
 
 type BackupSettings struct {
-	InstanceID        int       `json:"instanceId"`
-	Enabled           bool      `json:"enabled"`
-	HourlyEnabled     bool      `json:"hourlyEnabled"`
-	DailyEnabled      bool      `json:"dailyEnabled"`
-	WeeklyEnabled     bool      `json:"weeklyEnabled"`
-	MonthlyEnabled    bool      `json:"monthlyEnabled"`
-	KeepHourly        int       `json:"keepHourly"`
-	KeepDaily         int       `json:"keepDaily"`
-	KeepWeekly        int       `json:"keepWeekly"`
-	KeepMonthly       int       `json:"keepMonthly"`
-	IncludeCategories bool      `json:"includeCategories"`
-	IncludeTags       bool      `json:"includeTags"`
-	IncludeSavePaths  bool      `json:"includeSavePaths"`
-	CustomPath        *string   `json:"customPath,omitempty"`
-	CreatedAt         time.Time `json:"createdAt"`
-	UpdatedAt         time.Time `json:"updatedAt"`
+	InstanceID        int                `json:"instanceId"`
+	Enabled           bool               `json:"enabled"`
+	HourlyEnabled     bool               `json:"hourlyEnabled"`
+	DailyEnabled      bool               `json:"dailyEnabled"`
+	WeeklyEnabled     bool               `json:"weeklyEnabled"`
+	MonthlyEnabled    bool               `json:"monthlyEnabled"`
+	KeepHourly        int                `json:"keepHourly"`
+	KeepDaily         int                `json:"keepDaily"`
+	KeepWeekly        int                `json:"keepWeekly"`
+	KeepMonthly       int                `json:"keepMonthly"`
+	IncludeCategories bool               `json:"includeCategories"`
+	IncludeTags       bool               `json:"includeTags"`
+	IncludeSavePaths  bool               `json:"includeSavePaths"`
+	CustomPath        *string            `json:"customPath,omitempty"`
+	Destination       *BackupDestination `json:"destination,omitempty"`
+	Encryption        *BackupEncryption  `json:"encryption,omitempty"`
+	CreatedAt         time.Time          `json:"createdAt"`
+	UpdatedAt         time.Time          `json:"updatedAt"`
 }
 
 func DefaultBackupSettings(instanceID int) *BackupSettings {
@@ -98,27 +100,55 @@ type BackupRun struct {
 }
 
 type BackupItem struct {
-	ID              int64     `json:"id"`
-	RunID           int64     `json:"runId"`
-	TorrentHash     string    `json:"torrentHash"`
-	Name            string    `json:"name"`
-	Category        *string   `json:"category,omitempty"`
-	SizeBytes       int64     `json:"sizeBytes"`
-	ArchiveRelPath  *string   `json:"archiveRelPath,omitempty"`
-	InfoHashV1      *string   `json:"infohashV1,omitempty"`
-	InfoHashV2      *string   `json:"infohashV2,omitempty"`
-	Tags            *string   `json:"tags,omitempty"`
-	TorrentBlobPath *string   `json:"torrentBlobPath,omitempty"`
-	SavePath        *string   `json:"savePath,omitempty"`
-	CreatedAt       time.Time `json:"createdAt"`
+	ID              int64               `json:"id"`
+	RunID           int64               `json:"runId"`
+	TorrentHash     string              `json:"torrentHash"`
+	Name            string              `json:"name"`
+	Category        *string             `json:"category,omitempty"`
+	SizeBytes       int64               `json:"sizeBytes"`
+	ArchiveRelPath  *string             `json:"archiveRelPath,omitempty"`
+	InfoHashV1      *string             `json:"infohashV1,omitempty"`
+	InfoHashV2      *string             `json:"infohashV2,omitempty"`
+	Tags            *string             `json:"tags,omitempty"`
+	TorrentBlobPath *string             `json:"torrentBlobPath,omitempty"`
+	SavePath        *string             `json:"savePath,omitempty"`
+	State           *BackupTorrentState `json:"state,omitempty"`
+	CreatedAt       time.Time           `json:"createdAt"`
 }
 
 type CategorySnapshot struct {
 	SavePath string `json:"savePath,omitempty"`
 }
 
+// BackupTorrentState is the per-torrent state a backup captures beyond
+// category, tags and save path. Limits use qBittorrent's units and sentinels:
+// share limits are -2 for the global default and -1 for unlimited, speed
+// limits are bytes/s with 0 for unlimited.
+type BackupTorrentState struct {
+	RatioLimit               float64           `json:"ratioLimit"`
+	SeedingTimeLimit         int64             `json:"seedingTimeLimit"`
+	InactiveSeedingTimeLimit int64             `json:"inactiveSeedingTimeLimit"`
+	ShareLimitAction         string            `json:"shareLimitAction,omitempty"`
+	UploadLimit              int64             `json:"uploadLimit,omitempty"`
+	DownloadLimit            int64             `json:"downloadLimit,omitempty"`
+	SequentialDownload       bool              `json:"sequentialDownload,omitempty"`
+	FirstLastPiecePrio       bool              `json:"firstLastPiecePrio,omitempty"`
+	Trackers                 []string          `json:"trackers,omitempty"`
+	Files                    []BackupFileState `json:"files,omitempty"`
+	AddedOn                  int64             `json:"addedOn,omitempty"` // informational; the Web API cannot set it
+}
+
+// BackupFileState records a file whose priority is not normal or whose name
+// differs from the one in the .torrent. Name is only set for renamed files.
+type BackupFileState struct {
+	Index    int    `json:"index"`
+	Name     string `json:"name,omitempty"`
+	Priority int    `json:"priority"`
+}
+
 type BackupStore struct {
-	db dbinterface.Querier
+	db            dbinterface.Querier
+	encryptionKey []byte
 }
 
 func NewBackupStore(db dbinterface.Querier) *BackupStore {
@@ -129,7 +159,7 @@ func (s *BackupStore) GetSettings(ctx context.Context, instanceID int) (*BackupS
 	query := `
         SELECT instance_id, enabled, hourly_enabled, daily_enabled, weekly_enabled, monthly_enabled,
                keep_hourly, keep_daily, keep_weekly, keep_monthly,
-               include_categories, include_tags, include_save_paths, custom_path, created_at, updated_at
+               include_categories, include_tags, include_save_paths, custom_path, destination_json, encryption_json, created_at, updated_at
         FROM instance_backup_settings
         WHERE instance_id = ?
     `
@@ -138,6 +168,8 @@ func (s *BackupStore) GetSettings(ctx context.Context, instanceID int) (*BackupS
 
 	var settings BackupSettings
 	var customPath sql.NullString
+	var destinationJSON sql.NullString
+	var encryptionJSON sql.NullString
 	var createdAt sql.NullTime
 	var updatedAt sql.NullTime
 	var enabled, hourlyEnabled, dailyEnabled, weeklyEnabled, monthlyEnabled int
@@ -158,6 +190,8 @@ func (s *BackupStore) GetSettings(ctx context.Context, instanceID int) (*BackupS
 		&includeTags,
 		&includeSavePaths,
 		&customPath,
+		&destinationJSON,
+		&encryptionJSON,
 		&createdAt,
 		&updatedAt,
 	)
@@ -172,6 +206,12 @@ func (s *BackupStore) GetSettings(ctx context.Context, instanceID int) (*BackupS
 	if customPath.Valid {
 		settings.CustomPath = &customPath.String
 	}
+	if settings.Destination, err = s.unmarshalDestination(destinationJSON); err != nil {
+		return nil, err
+	}
+	if settings.Encryption, err = s.unmarshalEncryption(encryptionJSON); err != nil {
+		return nil, err
+	}
 	if createdAt.Valid {
 		settings.CreatedAt = createdAt.Time
 	}
@@ -205,8 +245,8 @@ func (s *BackupStore) UpsertSettings(ctx context.Context, settings *BackupSettin
         INSERT INTO instance_backup_settings (
             instance_id, enabled, hourly_enabled, daily_enabled, weekly_enabled, monthly_enabled,
             keep_hourly, keep_daily, keep_weekly, keep_monthly,
-            include_categories, include_tags, include_save_paths, custom_path
-        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+            include_categories, include_tags, include_save_paths, custom_path, destination_json, encryption_json
+        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
         ON CONFLICT(instance_id) DO UPDATE SET
             enabled = excluded.enabled,
             hourly_enabled = excluded.hourly_enabled,
@@ -220,9 +260,20 @@ func (s *BackupStore) UpsertSettings(ctx context.Context, settings *BackupSettin
             include_categories = excluded.include_categories,
             include_tags = excluded.include_tags,
             include_save_paths = excluded.include_save_paths,
-            custom_path = excluded.custom_path
+            custom_path = excluded.custom_path,
+            destination_json = excluded.destination_json,
+            encryption_json = excluded.encryption_json
     `
 
+	destinationJSON, err := s.marshalDestination(settings.Destination)
+	if err != nil {
+		return err
+	}
+	encryptionJSON, err := s.marshalEncryption(settings.Encryption)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.ExecContext(
 		ctx,
 		query,
@@ -240,6 +291,8 @@ func (s *BackupStore) UpsertSettings(ctx context.Context, settings *BackupSettin
 		BoolToSQLite(settings.IncludeTags),
 		BoolToSQLite(settings.IncludeSavePaths),
 		settings.CustomPath,
+		destinationJSON,
+		encryptionJSON,
 	)
 
 	if err != nil {
@@ -364,6 +417,49 @@ func unmarshalCategories(raw sql.NullString) (map[string]CategorySnapshot, error
 	return categories, nil
 }
 
+// marshalTorrentState encodes state for the state_json column. Tracker URLs
+// carry passkeys, so the document is encrypted with the store key; it is only
+// stored as plain JSON by a store that has no key.
+func (s *BackupStore) marshalTorrentState(state *BackupTorrentState) (*string, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	encoded := string(data)
+	if len(s.encryptionKey) == 0 {
+		return &encoded, nil
+	}
+	if encoded, err = s.encrypt(encoded); err != nil {
+		return nil, err
+	}
+	return &encoded, nil
+}
+
+func (s *BackupStore) unmarshalTorrentState(raw sql.NullString) (*BackupTorrentState, error) {
+	if !raw.Valid || raw.String == "" {
+		return nil, nil
+	}
+
+	decoded := raw.String
+	// Encrypted documents are base64, which never starts with a brace.
+	if !strings.HasPrefix(decoded, "{") {
+		var err error
+		if decoded, err = s.decrypt(decoded); err != nil {
+			return nil, err
+		}
+	}
+
+	var state BackupTorrentState
+	if err := json.Unmarshal([]byte(decoded), &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
 func marshalTags(tags []string) (*string, error) {
 	if len(tags) == 0 {
 		return nil, nil
@@ -849,16 +945,17 @@ func (s *BackupStore) InsertItems(ctx context.Context, runID int64, items []Back
 
 	// Batch insert items with larger chunks for better performance
 	// SQLite SQLITE_MAX_VARIABLE_NUMBER is typically 32766 on modern systems
-	// but default is 999. Use 90 items * 11 params = 990 to stay safe
-	const chunkSize = 90
-	const paramsPerItem = 11
+	// but default is 999. Use 80 items * 12 params = 960 to stay safe
+	const chunkSize = 80
+	const paramsPerItem = 12
 
 	// Pre-build the query template for full chunks to avoid repeated string building in hot path.
 	// save_path is a plain TEXT column (not interned): cross-seed paths are unique
 	// per torrent and would only bloat string_pool, so it is written verbatim.
+	// state_json is per-torrent too and is stored the same way.
 	queryTemplate := `INSERT INTO instance_backup_items (
 		run_id, torrent_hash_id, name_id, category_id, size_bytes,
-		archive_rel_path_id, infohash_v1_id, infohash_v2_id, tags_id, torrent_blob_path_id, save_path
+		archive_rel_path_id, infohash_v1_id, infohash_v2_id, tags_id, torrent_blob_path_id, save_path, state_json
 	) VALUES %s`
 	fullQuery := dbinterface.BuildQueryWithPlaceholders(queryTemplate, paramsPerItem, chunkSize)
 
@@ -878,6 +975,11 @@ func (s *BackupStore) InsertItems(ctx context.Context, runID int64, items []Back
 			torrentHashID := stringToID[item.TorrentHash]
 			nameID := stringToID[item.Name]
 
+			stateJSON, err := s.marshalTorrentState(item.State)
+			if err != nil {
+				return fmt.Errorf("failed to encode torrent state: %w", err)
+			}
+
 			args = append(args,
 				runID,
 				torrentHashID,
@@ -890,6 +992,7 @@ func (s *BackupStore) InsertItems(ctx context.Context, runID int64, items []Back
 				getID(item.Tags),
 				getID(item.TorrentBlobPath),
 				item.SavePath,
+				stateJSON,
 			)
 		}
 
@@ -909,7 +1012,7 @@ func (s *BackupStore) ListItems(ctx context.Context, runID int64) ([]*BackupItem
 	}
 
 	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
-		SELECT id, run_id, torrent_hash, name, category, size_bytes, archive_rel_path, infohash_v1, infohash_v2, tags, torrent_blob_path, save_path, created_at
+		SELECT id, run_id, torrent_hash, name, category, size_bytes, archive_rel_path, infohash_v1, infohash_v2, tags, torrent_blob_path, save_path, state_json, created_at
 		FROM instance_backup_items_view
 		WHERE run_id = ?
 		ORDER BY %s
@@ -930,6 +1033,7 @@ func (s *BackupStore) ListItems(ctx context.Context, runID int64) ([]*BackupItem
 		var tags sql.NullString
 		var blobPath sql.NullString
 		var savePath sql.NullString
+		var stateJSON sql.NullString
 		if err := rows.Scan(
 			&item.ID,
 			&item.RunID,
@@ -943,6 +1047,7 @@ func (s *BackupStore) ListItems(ctx context.Context, runID int64) ([]*BackupItem
 			&tags,
 			&blobPath,
 			&savePath,
+			&stateJSON,
 			&item.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -968,6 +1073,9 @@ func (s *BackupStore) ListItems(ctx context.Context, runID int64) ([]*BackupItem
 		if savePath.Valid {
 			item.SavePath = &savePath.String
 		}
+		if item.State, err = s.unmarshalTorrentState(stateJSON); err != nil {
+			return nil, fmt.Errorf("failed to decode torrent state: %w", err)
+		}
 		items = append(items, &item)
 	}
 
@@ -1014,7 +1122,7 @@ func (s *BackupStore) listItemsForRunsChunk(ctx context.Context, runIDs []int64)
 	}
 
 	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
-		SELECT id, run_id, torrent_hash, name, category, size_bytes, archive_rel_path, infohash_v1, infohash_v2, tags, torrent_blob_path, save_path, created_at
+		SELECT id, run_id, torrent_hash, name, category, size_bytes, archive_rel_path, infohash_v1, infohash_v2, tags, torrent_blob_path, save_path, state_json, created_at
 		FROM instance_backup_items_view
 		WHERE run_id IN `+buildInPlaceholders(len(runIDs))+`
 		ORDER BY run_id, %s
@@ -1035,6 +1143,7 @@ func (s *BackupStore) listItemsForRunsChunk(ctx context.Context, runIDs []int64)
 		var tags sql.NullString
 		var blobPath sql.NullString
 		var savePath sql.NullString
+		var stateJSON sql.NullString
 		if err := rows.Scan(
 			&item.ID,
 			&item.RunID,
@@ -1048,6 +1157,7 @@ func (s *BackupStore) listItemsForRunsChunk(ctx context.Context, runIDs []int64)
 			&tags,
 			&blobPath,
 			&savePath,
+			&stateJSON,
 			&item.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -1073,6 +1183,9 @@ func (s *BackupStore) listItemsForRunsChunk(ctx context.Context, runIDs []int64)
 		if savePath.Valid {
 			item.SavePath = &savePath.String
 		}
+		if item.State, err = s.unmarshalTorrentState(stateJSON); err != nil {
+			return nil, fmt.Errorf("failed to decode torrent state: %w", err)
+		}
 		items = append(items, &item)
 	}
 
@@ -1085,7 +1198,7 @@ func (s *BackupStore) listItemsForRunsChunk(ctx context.Context, runIDs []int64)
 
 func (s *BackupStore) GetItemByHash(ctx context.Context, runID int64, hash string) (*BackupItem, error) {
 	row := s.db.QueryRowContext(ctx, `
-		SELECT id, run_id, torrent_hash, name, category, size_bytes, archive_rel_path, infohash_v1, infohash_v2, tags, torrent_blob_path, save_path, created_at
+		SELECT id, run_id, torrent_hash, name, category, size_bytes, archive_rel_path, infohash_v1, infohash_v2, tags, torrent_blob_path, save_path, state_json, created_at
 		FROM instance_backup_items_view
 		WHERE run_id = ? AND torrent_hash = ?
 		LIMIT 1
@@ -1099,6 +1212,7 @@ func (s *BackupStore) GetItemByHash(ctx context.Context, runID int64, hash strin
 	var tags sql.NullString
 	var blobPath sql.NullString
 	var savePath sql.NullString
+	var stateJSON sql.NullString
 
 	if err := row.Scan(
 		&item.ID,
@@ -1113,6 +1227,7 @@ func (s *BackupStore) GetItemByHash(ctx context.Context, runID int64, hash strin
 		&tags,
 		&blobPath,
 		&savePath,
+		&stateJSON,
 		&item.CreatedAt,
 	); err != nil {
 		return nil, err
@@ -1139,6 +1254,11 @@ func (s *BackupStore) GetItemByHash(ctx context.Context, runID int64, hash strin
 	if savePath.Valid {
 		item.SavePath = &savePath.String
 	}
+	state, err := s.unmarshalTorrentState(stateJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode torrent state: %w", err)
+	}
+	item.State = state
 
 	return &item, nil
 }
@@ -1600,7 +1720,7 @@ func (s *BackupStore) ListEnabledSettings(ctx context.Context) ([]*BackupSetting
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT instance_id, enabled, hourly_enabled, daily_enabled, weekly_enabled, monthly_enabled,
 		       keep_hourly, keep_daily, keep_weekly, keep_monthly,
-		       include_categories, include_tags, include_save_paths, custom_path, created_at, updated_at
+		       include_categories, include_tags, include_save_paths, custom_path, destination_json, encryption_json, created_at, updated_at
 		FROM instance_backup_settings
 		WHERE enabled = 1
 	`)
@@ -1609,31 +1729,35 @@ func (s *BackupStore) ListEnabledSettings(ctx context.Context) ([]*BackupSetting
 	}
 	defer rows.Close()
 
-	settings := make([]*BackupSettings, 0)
+	results := make([]*BackupSettings, 0)
 
 	for rows.Next() {
-		var s BackupSettings
+		var settings BackupSettings
 		var customPath sql.NullString
+		var destinationJSON sql.NullString
+		var encryptionJSON sql.NullString
 		var createdAt sql.NullTime
 		var updatedAt sql.NullTime
 		var enabled, hourlyEnabled, dailyEnabled, weeklyEnabled, monthlyEnabled int
 		var includeCategories, includeTags, includeSavePaths int
 
 		if err := rows.Scan(
-			&s.InstanceID,
+			&settings.InstanceID,
 			&enabled,
 			&hourlyEnabled,
 			&dailyEnabled,
 			&weeklyEnabled,
 			&monthlyEnabled,
-			&s.KeepHourly,
-			&s.KeepDaily,
-			&s.KeepWeekly,
-			&s.KeepMonthly,
+			&settings.KeepHourly,
+			&settings.KeepDaily,
+			&settings.KeepWeekly,
+			&settings.KeepMonthly,
 			&includeCategories,
 			&includeTags,
 			&includeSavePaths,
 			&customPath,
+			&destinationJSON,
+			&encryptionJSON,
 			&createdAt,
 			&updatedAt,
 		); err != nil {
@@ -1641,31 +1765,37 @@ func (s *BackupStore) ListEnabledSettings(ctx context.Context) ([]*BackupSetting
 		}
 
 		if customPath.Valid {
-			s.CustomPath = &customPath.String
+			settings.CustomPath = &customPath.String
+		}
+		if settings.Destination, err = s.unmarshalDestination(destinationJSON); err != nil {
+			return nil, err
+		}
+		if settings.Encryption, err = s.unmarshalEncryption(encryptionJSON); err != nil {
+			return nil, err
 		}
 		if createdAt.Valid {
-			s.CreatedAt = createdAt.Time
+			settings.CreatedAt = createdAt.Time
 		}
 		if updatedAt.Valid {
-			s.UpdatedAt = updatedAt.Time
+			settings.UpdatedAt = updatedAt.Time
 		}
-		s.Enabled = SQLiteIntToBool(enabled)
-		s.HourlyEnabled = SQLiteIntToBool(hourlyEnabled)
-		s.DailyEnabled = SQLiteIntToBool(dailyEnabled)
-		s.WeeklyEnabled = SQLiteIntToBool(weeklyEnabled)
-		s.MonthlyEnabled = SQLiteIntToBool(monthlyEnabled)
-		s.IncludeCategories = SQLiteIntToBool(includeCategories)
-		s.IncludeTags = SQLiteIntToBool(includeTags)
-		s.IncludeSavePaths = SQLiteIntToBool(includeSavePaths)
+		settings.Enabled = SQLiteIntToBool(enabled)
+		settings.HourlyEnabled = SQLiteIntToBool(hourlyEnabled)
+		settings.DailyEnabled = SQLiteIntToBool(dailyEnabled)
+		settings.WeeklyEnabled = SQLiteIntToBool(weeklyEnabled)
+		settings.MonthlyEnabled = SQLiteIntToBool(monthlyEnabled)
+		settings.IncludeCategories = SQLiteIntToBool(includeCategories)
+		settings.IncludeTags = SQLiteIntToBool(includeTags)
+		settings.IncludeSavePaths = SQLiteIntToBool(includeSavePaths)
 
-		settings = append(settings, &s)
+		results = append(results, &settings)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return settings, nil
+	return results, nil
 }
 
 func (s *BackupStore) DeleteRunsOlderThan(ctx context.Context, instanceID int, kind BackupRunKind, keep int) ([]int64, error) {
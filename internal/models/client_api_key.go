@@ -16,12 +16,20 @@ import (
 var ErrClientAPIKeyNotFound = errors.New("client api key not found")
 
 type ClientAPIKey struct {
-	ID         int        `json:"id"`
-	KeyHash    string     `json:"-"`
-	ClientName string     `json:"clientName"`
-	InstanceID int        `json:"instanceId"`
-	CreatedAt  time.Time  `json:"createdAt"`
-	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	ID         int    `json:"id"`
+	KeyHash    string `json:"-"`
+	ClientName string `json:"clientName"`
+	// InstanceID is 0 for keys that target a virtual instance.
+	InstanceID        int  `json:"instanceId"`
+	VirtualInstanceID *int `json:"virtualInstanceId,omitempty"`
+	// Scope limits what the key may do through the proxy; zero means full access.
+	Scope ClientAPIKeyScope `json:"scope"`
+	// AddPolicy rewrites torrents/add requests made with the key.
+	AddPolicy ClientAPIKeyAddPolicy `json:"addPolicy"`
+	// DuplicatePolicy handles adds of torrents that already exist on an instance.
+	DuplicatePolicy ClientAPIKeyDuplicatePolicy `json:"duplicatePolicy"`
+	CreatedAt       time.Time                   `json:"createdAt"`
+	LastUsedAt      *time.Time                  `json:"lastUsedAt,omitempty"`
 }
 
 type ClientAPIKeyStore struct {
@@ -32,7 +40,23 @@ func NewClientAPIKeyStore(db dbinterface.Querier) *ClientAPIKeyStore {
 	return &ClientAPIKeyStore{db: db}
 }
 
-func (s *ClientAPIKeyStore) Create(ctx context.Context, clientName string, instanceID int) (string, *ClientAPIKey, error) {
+// Create stores a new key for either instanceID or virtualInstanceID; the other must be 0.
+func (s *ClientAPIKeyStore) Create(ctx context.Context, clientName string, instanceID, virtualInstanceID int, scope ClientAPIKeyScope, addPolicy ClientAPIKeyAddPolicy, duplicatePolicy ClientAPIKeyDuplicatePolicy) (string, *ClientAPIKey, error) {
+	if (instanceID == 0) == (virtualInstanceID == 0) {
+		return "", nil, errors.New("exactly one of instance or virtual instance is required")
+	}
+	if err := duplicatePolicy.Validate(); err != nil {
+		return "", nil, err
+	}
+	scopeJSON, err := encodeClientAPIKeyScope(scope)
+	if err != nil {
+		return "", nil, err
+	}
+	addPolicyJSON, err := encodeClientAPIKeyAddPolicy(addPolicy)
+	if err != nil {
+		return "", nil, err
+	}
+
 	// Generate new API key
 	rawKey, err := GenerateAPIKey()
 	if err != nil {
@@ -59,13 +83,12 @@ func (s *ClientAPIKeyStore) Create(ctx context.Context, clientName string, insta
 	clientAPIKey := &ClientAPIKey{}
 	var createdAt, lastUsedAt sql.NullTime
 	err = tx.QueryRowContext(ctx, `
-		INSERT INTO client_api_keys (key_hash, client_name_id, instance_id)
-		VALUES (?, ?, ?)
-		RETURNING id, key_hash, instance_id, created_at, last_used_at
-	`, keyHash, ids[0], instanceID).Scan(
+		INSERT INTO client_api_keys (key_hash, client_name_id, instance_id, virtual_instance_id, scope, add_policy, duplicate_policy)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING id, key_hash, created_at, last_used_at
+	`, keyHash, ids[0], nullableID(instanceID), nullableID(virtualInstanceID), scopeJSON, addPolicyJSON, string(duplicatePolicy)).Scan(
 		&clientAPIKey.ID,
 		&clientAPIKey.KeyHash,
-		&clientAPIKey.InstanceID,
 		&createdAt,
 		&lastUsedAt,
 	)
@@ -79,6 +102,13 @@ func (s *ClientAPIKeyStore) Create(ctx context.Context, clientName string, insta
 	}
 
 	clientAPIKey.ClientName = clientName
+	clientAPIKey.InstanceID = instanceID
+	if virtualInstanceID != 0 {
+		clientAPIKey.VirtualInstanceID = &virtualInstanceID
+	}
+	clientAPIKey.Scope, _ = decodeClientAPIKeyScope(scopeJSON)
+	clientAPIKey.AddPolicy, _ = decodeClientAPIKeyAddPolicy(addPolicyJSON)
+	clientAPIKey.DuplicatePolicy = duplicatePolicy
 	clientAPIKey.CreatedAt = createdAt.Time
 	if lastUsedAt.Valid {
 		clientAPIKey.LastUsedAt = &lastUsedAt.Time
@@ -90,7 +120,7 @@ func (s *ClientAPIKeyStore) Create(ctx context.Context, clientName string, insta
 
 func (s *ClientAPIKeyStore) GetAll(ctx context.Context) ([]*ClientAPIKey, error) {
 	query := `
-		SELECT id, key_hash, client_name, instance_id, created_at, last_used_at
+		SELECT id, key_hash, client_name, instance_id, virtual_instance_id, scope, add_policy, duplicate_policy, created_at, last_used_at
 		FROM client_api_keys_view
 		ORDER BY created_at DESC
 	`
@@ -104,17 +134,30 @@ func (s *ClientAPIKeyStore) GetAll(ctx context.Context) ([]*ClientAPIKey, error)
 	var keys []*ClientAPIKey
 	for rows.Next() {
 		key := &ClientAPIKey{}
+		var scopeJSON, addPolicyJSON string
+		var instanceID, virtualInstanceID sql.NullInt64
 		err := rows.Scan(
 			&key.ID,
 			&key.KeyHash,
 			&key.ClientName,
-			&key.InstanceID,
+			&instanceID,
+			&virtualInstanceID,
+			&scopeJSON,
+			&addPolicyJSON,
+			&key.DuplicatePolicy,
 			&key.CreatedAt,
 			&key.LastUsedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		key.setTarget(instanceID, virtualInstanceID)
+		if key.Scope, err = decodeClientAPIKeyScope(scopeJSON); err != nil {
+			return nil, fmt.Errorf("client api key %d: %w", key.ID, err)
+		}
+		if key.AddPolicy, err = decodeClientAPIKeyAddPolicy(addPolicyJSON); err != nil {
+			return nil, fmt.Errorf("client api key %d: %w", key.ID, err)
+		}
 		keys = append(keys, key)
 	}
 
@@ -127,17 +170,23 @@ func (s *ClientAPIKeyStore) GetAll(ctx context.Context) ([]*ClientAPIKey, error)
 
 func (s *ClientAPIKeyStore) GetByKeyHash(ctx context.Context, keyHash string) (*ClientAPIKey, error) {
 	query := `
-		SELECT id, key_hash, client_name, instance_id, created_at, last_used_at
+		SELECT id, key_hash, client_name, instance_id, virtual_instance_id, scope, add_policy, duplicate_policy, created_at, last_used_at
 		FROM client_api_keys_view
 		WHERE key_hash = ?
 	`
 
 	key := &ClientAPIKey{}
+	var scopeJSON, addPolicyJSON string
+	var instanceID, virtualInstanceID sql.NullInt64
 	err := s.db.QueryRowContext(ctx, query, keyHash).Scan(
 		&key.ID,
 		&key.KeyHash,
 		&key.ClientName,
-		&key.InstanceID,
+		&instanceID,
+		&virtualInstanceID,
+		&scopeJSON,
+		&addPolicyJSON,
+		&key.DuplicatePolicy,
 		&key.CreatedAt,
 		&key.LastUsedAt,
 	)
@@ -150,9 +199,34 @@ func (s *ClientAPIKeyStore) GetByKeyHash(ctx context.Context, keyHash string) (*
 		return nil, err
 	}
 
+	key.setTarget(instanceID, virtualInstanceID)
+
+	// A scope that can't be read must not silently grant full access.
+	if key.Scope, err = decodeClientAPIKeyScope(scopeJSON); err != nil {
+		return nil, fmt.Errorf("client api key %d: %w", key.ID, err)
+	}
+	if key.AddPolicy, err = decodeClientAPIKeyAddPolicy(addPolicyJSON); err != nil {
+		return nil, fmt.Errorf("client api key %d: %w", key.ID, err)
+	}
+
 	return key, nil
 }
 
+func (k *ClientAPIKey) setTarget(instanceID, virtualInstanceID sql.NullInt64) {
+	k.InstanceID = int(instanceID.Int64)
+	if virtualInstanceID.Valid {
+		id := int(virtualInstanceID.Int64)
+		k.VirtualInstanceID = &id
+	}
+}
+
+func nullableID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
 func (s *ClientAPIKeyStore) ValidateKey(ctx context.Context, rawKey string) (*ClientAPIKey, error) {
 	keyHash := HashAPIKey(rawKey)
 	return s.GetByKeyHash(ctx, keyHash)
@@ -187,6 +261,77 @@ func (s *ClientAPIKeyStore) UpdateLastUsed(ctx context.Context, keyHash string)
 	return nil
 }
 
+// UpdateScope replaces the scope policy of a key.
+func (s *ClientAPIKeyStore) UpdateScope(ctx context.Context, id int, scope ClientAPIKeyScope) error {
+	scopeJSON, err := encodeClientAPIKeyScope(scope)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx, `UPDATE client_api_keys SET scope = ? WHERE id = ?`, scopeJSON, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrClientAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// UpdateAddPolicy replaces the add policy of a key.
+func (s *ClientAPIKeyStore) UpdateAddPolicy(ctx context.Context, id int, policy ClientAPIKeyAddPolicy) error {
+	policyJSON, err := encodeClientAPIKeyAddPolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx, `UPDATE client_api_keys SET add_policy = ? WHERE id = ?`, policyJSON, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrClientAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// UpdateDuplicatePolicy replaces the duplicate policy of a key.
+func (s *ClientAPIKeyStore) UpdateDuplicatePolicy(ctx context.Context, id int, policy ClientAPIKeyDuplicatePolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx, `UPDATE client_api_keys SET duplicate_policy = ? WHERE id = ?`, string(policy), id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrClientAPIKeyNotFound
+	}
+
+	return nil
+}
+
 func (s *ClientAPIKeyStore) Delete(ctx context.Context, id int) error {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
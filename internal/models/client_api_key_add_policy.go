@@ -0,0 +1,167 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/autobrr/qui/pkg/pathutil"
+)
+
+// ClientAPIKeyAddPolicy rewrites torrents/add requests made through the proxy
+// with a client API key. The zero value leaves requests unchanged.
+type ClientAPIKeyAddPolicy struct {
+	// Category is applied when the request sets none, or always when ForceCategory is set.
+	Category      string `json:"category,omitempty"`
+	ForceCategory bool   `json:"forceCategory,omitempty"`
+	// Tags are added to the tags of the request.
+	Tags []string `json:"tags,omitempty"`
+	// SavePath is a Go template for the save path; see AddPolicyPathData for the
+	// available fields. Setting it disables automatic torrent management.
+	SavePath string `json:"savePath,omitempty"`
+	// Paused forces whether torrents are added paused (stopped).
+	Paused *bool `json:"paused,omitempty"`
+	// UploadLimit is the per-torrent upload limit in bytes per second (-1 for none).
+	UploadLimit *int64 `json:"uploadLimit,omitempty"`
+	// Share limits use qBittorrent's conventions: -2 uses the global limit and -1 means none.
+	RatioLimit               *float64 `json:"ratioLimit,omitempty"`
+	SeedingTimeLimit         *int64   `json:"seedingTimeLimit,omitempty"`         // minutes
+	InactiveSeedingTimeLimit *int64   `json:"inactiveSeedingTimeLimit,omitempty"` // minutes
+}
+
+// AddPolicyPathData is the data available to save path templates.
+type AddPolicyPathData struct {
+	// Category is the category the torrent is added with, after the policy is applied.
+	Category string
+	// Tags is the comma-separated tag list after the policy is applied.
+	Tags string
+	// SavePath is the save path requested by the client, if any.
+	SavePath string
+	// Client is the name of the client API key.
+	Client string
+}
+
+var addPolicyTemplateFuncs = template.FuncMap{
+	"sanitize": pathutil.SanitizePathSegment,
+}
+
+// IsZero reports whether the policy leaves requests unchanged.
+func (p ClientAPIKeyAddPolicy) IsZero() bool {
+	return p.Category == "" && !p.ForceCategory && len(p.Tags) == 0 && p.SavePath == "" &&
+		p.Paused == nil && p.UploadLimit == nil && p.RatioLimit == nil &&
+		p.SeedingTimeLimit == nil && p.InactiveSeedingTimeLimit == nil
+}
+
+// Normalize trims whitespace and drops empty and duplicate tags.
+func (p *ClientAPIKeyAddPolicy) Normalize() {
+	p.Category = strings.TrimSpace(p.Category)
+	p.SavePath = strings.TrimSpace(p.SavePath)
+	var tags []string
+	for _, tag := range p.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	p.Tags = tags
+}
+
+// Validate checks that the policy can be applied.
+func (p ClientAPIKeyAddPolicy) Validate() error {
+	for _, tag := range p.Tags {
+		if strings.Contains(tag, ",") {
+			return fmt.Errorf("tag %q must not contain a comma", tag)
+		}
+	}
+	if p.SavePath != "" {
+		if _, err := p.RenderSavePath(AddPolicyPathData{Category: "category", Tags: "tag", SavePath: "/downloads", Client: "client"}); err != nil {
+			return err
+		}
+	}
+	if p.UploadLimit != nil && *p.UploadLimit < -1 {
+		return errors.New("upload limit must be -1 or greater")
+	}
+	if p.RatioLimit != nil && *p.RatioLimit < -2 {
+		return errors.New("ratio limit must be -2 or greater")
+	}
+	if p.SeedingTimeLimit != nil && *p.SeedingTimeLimit < -2 {
+		return errors.New("seeding time limit must be -2 or greater")
+	}
+	if p.InactiveSeedingTimeLimit != nil && *p.InactiveSeedingTimeLimit < -2 {
+		return errors.New("inactive seeding time limit must be -2 or greater")
+	}
+	return nil
+}
+
+// ApplyCategory returns the category to add a torrent with given the requested one.
+func (p ClientAPIKeyAddPolicy) ApplyCategory(requested string) string {
+	if p.Category != "" && (p.ForceCategory || requested == "") {
+		return p.Category
+	}
+	return requested
+}
+
+// ApplyTags returns the comma-separated requested tags with the policy's tags added.
+func (p ClientAPIKeyAddPolicy) ApplyTags(requested string) string {
+	var tags []string
+	for tag := range strings.SplitSeq(requested, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	for _, tag := range p.Tags {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return strings.Join(tags, ",")
+}
+
+// RenderSavePath executes the SavePath template. It returns "" when the policy
+// has no save path.
+func (p ClientAPIKeyAddPolicy) RenderSavePath(data AddPolicyPathData) (string, error) {
+	if p.SavePath == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("savePath").
+		Option("missingkey=error").
+		Funcs(addPolicyTemplateFuncs).
+		Parse(p.SavePath)
+	if err != nil {
+		return "", fmt.Errorf("invalid save path template: %w", err)
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render save path template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func encodeClientAPIKeyAddPolicy(policy ClientAPIKeyAddPolicy) (string, error) {
+	policy.Normalize()
+	if policy.IsZero() {
+		return "", nil
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal add policy: %w", err)
+	}
+	return string(data), nil
+}
+
+func decodeClientAPIKeyAddPolicy(raw string) (ClientAPIKeyAddPolicy, error) {
+	var policy ClientAPIKeyAddPolicy
+	if raw == "" {
+		return policy, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &policy); err != nil {
+		return policy, fmt.Errorf("failed to unmarshal add policy: %w", err)
+	}
+	return policy, nil
+}
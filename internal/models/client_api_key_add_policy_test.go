@@ -0,0 +1,50 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientAPIKeyAddPolicy(t *testing.T) {
+	policy := ClientAPIKeyAddPolicy{
+		Category: " tv ",
+		Tags:     []string{"sonarr", " sonarr", ""},
+		SavePath: "/data/{{ .Category }}",
+	}
+	policy.Normalize()
+	assert.Equal(t, "tv", policy.Category)
+	assert.Equal(t, []string{"sonarr"}, policy.Tags)
+	require.NoError(t, policy.Validate())
+
+	assert.Equal(t, "tv", policy.ApplyCategory(""))
+	assert.Equal(t, "movies", policy.ApplyCategory("movies"))
+	policy.ForceCategory = true
+	assert.Equal(t, "tv", policy.ApplyCategory("movies"))
+
+	assert.Equal(t, "a,sonarr", policy.ApplyTags("a, sonarr"))
+	assert.Equal(t, "sonarr", policy.ApplyTags(""))
+
+	savePath, err := policy.RenderSavePath(AddPolicyPathData{Category: "tv"})
+	require.NoError(t, err)
+	assert.Equal(t, "/data/tv", savePath)
+
+	assert.Error(t, ClientAPIKeyAddPolicy{SavePath: "{{ .Missing }}"}.Validate())
+	assert.Error(t, ClientAPIKeyAddPolicy{Tags: []string{"a,b"}}.Validate())
+	limit := int64(-3)
+	assert.Error(t, ClientAPIKeyAddPolicy{SeedingTimeLimit: &limit}.Validate())
+
+	encoded, err := encodeClientAPIKeyAddPolicy(ClientAPIKeyAddPolicy{Tags: []string{" "}})
+	require.NoError(t, err)
+	assert.Empty(t, encoded)
+
+	encoded, err = encodeClientAPIKeyAddPolicy(policy)
+	require.NoError(t, err)
+	decoded, err := decodeClientAPIKeyAddPolicy(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, policy, decoded)
+}
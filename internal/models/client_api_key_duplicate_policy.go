@@ -0,0 +1,32 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import "fmt"
+
+// ClientAPIKeyDuplicatePolicy selects what the proxy does when a torrents/add
+// request adds a torrent that already exists on any instance.
+type ClientAPIKeyDuplicatePolicy string
+
+const (
+	// DuplicatePolicyOff skips the duplicate check.
+	DuplicatePolicyOff ClientAPIKeyDuplicatePolicy = ""
+	// DuplicatePolicyAllow adds duplicates anyway but logs them.
+	DuplicatePolicyAllow ClientAPIKeyDuplicatePolicy = "allow"
+	// DuplicatePolicyReject answers duplicate adds with "Fails." without adding anything.
+	DuplicatePolicyReject ClientAPIKeyDuplicatePolicy = "reject"
+	// DuplicatePolicyCrossSeed hands duplicate .torrent files to cross-seed
+	// injection, which only adds them when matching data is already on disk.
+	DuplicatePolicyCrossSeed ClientAPIKeyDuplicatePolicy = "cross_seed"
+)
+
+// Validate checks that the policy is known.
+func (p ClientAPIKeyDuplicatePolicy) Validate() error {
+	switch p {
+	case DuplicatePolicyOff, DuplicatePolicyAllow, DuplicatePolicyReject, DuplicatePolicyCrossSeed:
+		return nil
+	default:
+		return fmt.Errorf("unknown duplicate policy %q", p)
+	}
+}
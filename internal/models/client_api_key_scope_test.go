@@ -0,0 +1,45 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientAPIKeyScope(t *testing.T) {
+	scope := ClientAPIKeyScope{
+		AllowedEndpoints: []string{" /api/v2/torrents/add ", "sync/*", "sync/*", ""},
+		Categories:       []string{"tv", " tv "},
+		Tags:             []string{"sonarr"},
+	}
+	scope.Normalize()
+	assert.Equal(t, []string{"torrents/add", "sync/*"}, scope.AllowedEndpoints)
+	assert.Equal(t, []string{"tv"}, scope.Categories)
+	require.NoError(t, scope.Validate())
+
+	assert.True(t, scope.AllowsEndpoint("sync/maindata"))
+	assert.False(t, scope.AllowsEndpoint("torrents/delete"))
+
+	assert.True(t, scope.AllowsTorrent("tv", ""))
+	assert.True(t, scope.AllowsTorrent("movies", "foo, sonarr"))
+	assert.False(t, scope.AllowsTorrent("movies", "radarr"))
+	assert.False(t, scope.AllowsCategory("movies"))
+
+	assert.True(t, ClientAPIKeyScope{}.IsZero())
+	assert.True(t, ClientAPIKeyScope{}.AllowsTorrent("anything", ""))
+	assert.Error(t, ClientAPIKeyScope{AllowedEndpoints: []string{"torrents/["}}.Validate())
+
+	encoded, err := encodeClientAPIKeyScope(ClientAPIKeyScope{AllowedEndpoints: []string{" "}})
+	require.NoError(t, err)
+	assert.Empty(t, encoded, "scopes that normalize to nothing are stored as full access")
+
+	encoded, err = encodeClientAPIKeyScope(scope)
+	require.NoError(t, err)
+	decoded, err := decodeClientAPIKeyScope(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, scope, decoded)
+}
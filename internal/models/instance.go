@@ -24,6 +24,33 @@ import (
 
 var ErrInstanceNotFound = errors.New("instance not found")
 
+// ClientType identifies the torrent client software behind an instance.
+type ClientType string
+
+const (
+	ClientTypeQBittorrent  ClientType = "qbittorrent"
+	ClientTypeTransmission ClientType = "transmission"
+	ClientTypeDeluge       ClientType = "deluge"
+)
+
+// Valid reports whether t is a supported client type.
+func (t ClientType) Valid() bool {
+	switch t {
+	case ClientTypeQBittorrent, ClientTypeTransmission, ClientTypeDeluge:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrDefault returns t, or qBittorrent when t is empty.
+func (t ClientType) OrDefault() ClientType {
+	if t == "" {
+		return ClientTypeQBittorrent
+	}
+	return t
+}
+
 type Instance struct {
 	ID                       int     `json:"id"`
 	Name                     string  `json:"name"`
@@ -45,6 +72,13 @@ type Instance struct {
 	UseReflinks bool `json:"useReflinks"`
 	// Fallback to regular mode when reflink/hardlink fails
 	FallbackToRegularMode bool `json:"fallbackToRegularMode"`
+	// ClientType is the torrent client behind Host; empty means qBittorrent.
+	ClientType ClientType `json:"clientType"`
+}
+
+// IsQBittorrent reports whether the instance is a qBittorrent client.
+func (i *Instance) IsQBittorrent() bool {
+	return i.ClientType.OrDefault() == ClientTypeQBittorrent
 }
 
 func (i Instance) MarshalJSON() ([]byte, error) {
@@ -66,6 +100,7 @@ func (i Instance) MarshalJSON() ([]byte, error) {
 		HardlinkDirPreset        string     `json:"hardlinkDirPreset"`
 		UseReflinks              bool       `json:"useReflinks"`
 		FallbackToRegularMode    bool       `json:"fallbackToRegularMode"`
+		ClientType               ClientType `json:"clientType"`
 		LastConnectedAt          *time.Time `json:"last_connected_at,omitempty"`
 		CreatedAt                time.Time  `json:"created_at"`
 		UpdatedAt                time.Time  `json:"updated_at"`
@@ -93,6 +128,7 @@ func (i Instance) MarshalJSON() ([]byte, error) {
 		HardlinkDirPreset:        i.HardlinkDirPreset,
 		UseReflinks:              i.UseReflinks,
 		FallbackToRegularMode:    i.FallbackToRegularMode,
+		ClientType:               i.ClientType.OrDefault(),
 	})
 }
 
@@ -115,6 +151,7 @@ func (i *Instance) UnmarshalJSON(data []byte) error {
 		HardlinkDirPreset        *string    `json:"hardlinkDirPreset,omitempty"`
 		UseReflinks              *bool      `json:"useReflinks,omitempty"`
 		FallbackToRegularMode    *bool      `json:"fallbackToRegularMode,omitempty"`
+		ClientType               ClientType `json:"clientType,omitempty"`
 		LastConnectedAt          *time.Time `json:"last_connected_at,omitempty"`
 		CreatedAt                time.Time  `json:"created_at"`
 		UpdatedAt                time.Time  `json:"updated_at"`
@@ -165,6 +202,7 @@ func (i *Instance) UnmarshalJSON(data []byte) error {
 	if temp.FallbackToRegularMode != nil {
 		i.FallbackToRegularMode = *temp.FallbackToRegularMode
 	}
+	i.ClientType = temp.ClientType.OrDefault()
 
 	// Handle password - don't overwrite if redacted
 	if temp.Password != "" && !domain.IsRedactedString(temp.Password) {
@@ -447,7 +485,7 @@ func (s *InstanceStore) Create(ctx context.Context, name, rawHost, username, pas
 
 func (s *InstanceStore) Get(ctx context.Context, id int) (*Instance, error) {
 	query := `
-		SELECT id, name, host, username, password_encrypted, api_key_encrypted, basic_username, basic_password_encrypted, tls_skip_verify, sort_order, is_active, has_local_filesystem_access, use_hardlinks, hardlink_base_dir, hardlink_dir_preset, use_reflinks, fallback_to_regular_mode
+		SELECT id, name, host, username, password_encrypted, api_key_encrypted, basic_username, basic_password_encrypted, tls_skip_verify, sort_order, is_active, has_local_filesystem_access, use_hardlinks, hardlink_base_dir, hardlink_dir_preset, use_reflinks, fallback_to_regular_mode, client_type
 		FROM instances_view
 		WHERE id = ?
 	`
@@ -463,6 +501,7 @@ func (s *InstanceStore) Get(ctx context.Context, id int) (*Instance, error) {
 	var hardlinkBaseDir, hardlinkDirPreset string
 	var useReflinks int
 	var fallbackToRegularMode int
+	var clientType string
 
 	err := s.db.QueryRowContext(ctx, query, id).Scan(
 		&instanceID,
@@ -482,6 +521,7 @@ func (s *InstanceStore) Get(ctx context.Context, id int) (*Instance, error) {
 		&hardlinkDirPreset,
 		&useReflinks,
 		&fallbackToRegularMode,
+		&clientType,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -506,6 +546,7 @@ func (s *InstanceStore) Get(ctx context.Context, id int) (*Instance, error) {
 		HardlinkDirPreset:        hardlinkDirPreset,
 		UseReflinks:              SQLiteIntToBool(useReflinks),
 		FallbackToRegularMode:    SQLiteIntToBool(fallbackToRegularMode),
+		ClientType:               ClientType(clientType).OrDefault(),
 	}
 
 	if basicUsername.Valid {
@@ -525,7 +566,7 @@ func (s *InstanceStore) List(ctx context.Context) ([]*Instance, error) {
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, name, host, username, password_encrypted, api_key_encrypted, basic_username, basic_password_encrypted, tls_skip_verify, sort_order, is_active, has_local_filesystem_access, use_hardlinks, hardlink_base_dir, hardlink_dir_preset, use_reflinks, fallback_to_regular_mode
+		SELECT id, name, host, username, password_encrypted, api_key_encrypted, basic_username, basic_password_encrypted, tls_skip_verify, sort_order, is_active, has_local_filesystem_access, use_hardlinks, hardlink_base_dir, hardlink_dir_preset, use_reflinks, fallback_to_regular_mode, client_type
 		FROM instances_view
 		ORDER BY sort_order ASC, %s ASC, id ASC
 	`, orderByName)
@@ -549,6 +590,7 @@ func (s *InstanceStore) List(ctx context.Context) ([]*Instance, error) {
 		var hardlinkBaseDir, hardlinkDirPreset string
 		var useReflinks int
 		var fallbackToRegularMode int
+		var clientType string
 
 		err := rows.Scan(
 			&id,
@@ -568,6 +610,7 @@ func (s *InstanceStore) List(ctx context.Context) ([]*Instance, error) {
 			&hardlinkDirPreset,
 			&useReflinks,
 			&fallbackToRegularMode,
+			&clientType,
 		)
 		if err != nil {
 			return nil, err
@@ -589,6 +632,7 @@ func (s *InstanceStore) List(ctx context.Context) ([]*Instance, error) {
 			HardlinkDirPreset:        hardlinkDirPreset,
 			UseReflinks:              SQLiteIntToBool(useReflinks),
 			FallbackToRegularMode:    SQLiteIntToBool(fallbackToRegularMode),
+			ClientType:               ClientType(clientType).OrDefault(),
 		}
 
 		if basicUsername.Valid {
@@ -617,6 +661,7 @@ type InstanceUpdateParams struct {
 	HardlinkDirPreset        *string
 	UseReflinks              *bool
 	FallbackToRegularMode    *bool
+	ClientType               *ClientType
 }
 
 func (s *InstanceStore) Update(ctx context.Context, id int, name, rawHost, username, password string, basicUsername, basicPassword *string, params *InstanceUpdateParams, apiKey ...*string) (*Instance, error) {
@@ -764,6 +809,14 @@ func (s *InstanceStore) Update(ctx context.Context, id int, name, rawHost, usern
 			query += ", fallback_to_regular_mode = ?"
 			args = append(args, BoolToSQLite(*params.FallbackToRegularMode))
 		}
+
+		if params.ClientType != nil {
+			if !params.ClientType.Valid() {
+				return nil, fmt.Errorf("unsupported client type %q", *params.ClientType)
+			}
+			query += ", client_type = ?"
+			args = append(args, string(*params.ClientType))
+		}
 	}
 
 	query += " WHERE id = ?"
@@ -818,6 +871,29 @@ func (s *InstanceStore) SetActiveState(ctx context.Context, id int, active bool)
 	return s.Get(ctx, id)
 }
 
+// SetClientType records which torrent client an instance talks to.
+func (s *InstanceStore) SetClientType(ctx context.Context, id int, clientType ClientType) (*Instance, error) {
+	if !clientType.Valid() {
+		return nil, fmt.Errorf("unsupported client type %q", clientType)
+	}
+
+	result, err := s.db.ExecContext(ctx, `UPDATE instances SET client_type = ? WHERE id = ?`, string(clientType), id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if rows == 0 {
+		return nil, ErrInstanceNotFound
+	}
+
+	return s.Get(ctx, id)
+}
+
 func (s *InstanceStore) UpdateOrder(ctx context.Context, instanceIDs []int) error {
 	if len(instanceIDs) == 0 {
 		return errors.New("instance ids cannot be empty")
@@ -171,6 +171,7 @@ func TestInstanceStoreWithHost(t *testing.T) {
 			hardlink_dir_preset TEXT NOT NULL DEFAULT '',
 			use_reflinks BOOLEAN NOT NULL DEFAULT 0,
 			fallback_to_regular_mode BOOLEAN NOT NULL DEFAULT 0,
+			client_type TEXT NOT NULL DEFAULT 'qbittorrent',
 			last_connected_at TIMESTAMP,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -198,7 +199,8 @@ func TestInstanceStoreWithHost(t *testing.T) {
 			i.hardlink_base_dir,
 			i.hardlink_dir_preset,
 			i.use_reflinks,
-			i.fallback_to_regular_mode
+			i.fallback_to_regular_mode,
+			i.client_type
 		FROM instances i
 		LEFT JOIN string_pool sp_name ON i.name_id = sp_name.id
 		LEFT JOIN string_pool sp_host ON i.host_id = sp_host.id
@@ -237,6 +239,23 @@ func TestInstanceStoreWithHost(t *testing.T) {
 	enabled, err := store.SetActiveState(ctx, instance.ID, true)
 	require.NoError(t, err, "Failed to re-enable instance")
 	assert.True(t, enabled.IsActive)
+
+	// Client type defaults to qBittorrent and can be changed
+	assert.Equal(t, ClientTypeQBittorrent, enabled.ClientType)
+	assert.True(t, enabled.IsQBittorrent())
+
+	transmission, err := store.SetClientType(ctx, instance.ID, ClientTypeTransmission)
+	require.NoError(t, err, "Failed to set client type")
+	assert.Equal(t, ClientTypeTransmission, transmission.ClientType)
+	assert.False(t, transmission.IsQBittorrent())
+
+	deluge := ClientTypeDeluge
+	updated, err = store.Update(ctx, instance.ID, "Updated Instance", "https://example.com:8443/qbittorrent", "newuser", "", nil, nil, &InstanceUpdateParams{ClientType: &deluge})
+	require.NoError(t, err, "Failed to update client type")
+	assert.Equal(t, ClientTypeDeluge, updated.ClientType)
+
+	_, err = store.SetClientType(ctx, instance.ID, ClientType("rtorrent"))
+	require.Error(t, err, "unknown client types should be rejected")
 }
 
 func TestInstanceStoreWithEmptyUsername(t *testing.T) {
@@ -293,6 +312,7 @@ func TestInstanceStoreWithEmptyUsername(t *testing.T) {
 			hardlink_dir_preset TEXT NOT NULL DEFAULT '',
 			use_reflinks BOOLEAN NOT NULL DEFAULT 0,
 			fallback_to_regular_mode BOOLEAN NOT NULL DEFAULT 0,
+			client_type TEXT NOT NULL DEFAULT 'qbittorrent',
 			last_connected_at TIMESTAMP,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -320,7 +340,8 @@ func TestInstanceStoreWithEmptyUsername(t *testing.T) {
 			i.hardlink_base_dir,
 			i.hardlink_dir_preset,
 			i.use_reflinks,
-			i.fallback_to_regular_mode
+			i.fallback_to_regular_mode,
+			i.client_type
 		FROM instances i
 		LEFT JOIN string_pool sp_name ON i.name_id = sp_name.id
 		LEFT JOIN string_pool sp_host ON i.host_id = sp_host.id
@@ -401,6 +422,7 @@ func TestInstanceStoreEmptyUsernameSelfHealing(t *testing.T) {
 			hardlink_dir_preset TEXT NOT NULL DEFAULT '',
 			use_reflinks BOOLEAN NOT NULL DEFAULT 0,
 			fallback_to_regular_mode BOOLEAN NOT NULL DEFAULT 0,
+			client_type TEXT NOT NULL DEFAULT 'qbittorrent',
 			FOREIGN KEY (name_id) REFERENCES string_pool(id),
 			FOREIGN KEY (host_id) REFERENCES string_pool(id),
 			FOREIGN KEY (username_id) REFERENCES string_pool(id),
@@ -425,7 +447,8 @@ func TestInstanceStoreEmptyUsernameSelfHealing(t *testing.T) {
 			i.hardlink_base_dir,
 			i.hardlink_dir_preset,
 			i.use_reflinks,
-			i.fallback_to_regular_mode
+			i.fallback_to_regular_mode,
+			i.client_type
 		FROM instances i
 		LEFT JOIN string_pool sp_name ON i.name_id = sp_name.id
 		LEFT JOIN string_pool sp_host ON i.host_id = sp_host.id
@@ -498,6 +521,7 @@ func TestInstanceStoreUpdateEmptyUsernameSelfHealing(t *testing.T) {
 			hardlink_dir_preset TEXT NOT NULL DEFAULT '',
 			use_reflinks BOOLEAN NOT NULL DEFAULT 0,
 			fallback_to_regular_mode BOOLEAN NOT NULL DEFAULT 0,
+			client_type TEXT NOT NULL DEFAULT 'qbittorrent',
 			FOREIGN KEY (name_id) REFERENCES string_pool(id),
 			FOREIGN KEY (host_id) REFERENCES string_pool(id),
 			FOREIGN KEY (username_id) REFERENCES string_pool(id),
@@ -522,7 +546,8 @@ func TestInstanceStoreUpdateEmptyUsernameSelfHealing(t *testing.T) {
 			i.hardlink_base_dir,
 			i.hardlink_dir_preset,
 			i.use_reflinks,
-			i.fallback_to_regular_mode
+			i.fallback_to_regular_mode,
+			i.client_type
 		FROM instances i
 		LEFT JOIN string_pool sp_name ON i.name_id = sp_name.id
 		LEFT JOIN string_pool sp_host ON i.host_id = sp_host.id
@@ -595,6 +620,7 @@ func TestInstanceStoreUpdateOrder(t *testing.T) {
 			hardlink_dir_preset TEXT NOT NULL DEFAULT '',
 			use_reflinks BOOLEAN NOT NULL DEFAULT 0,
 			fallback_to_regular_mode BOOLEAN NOT NULL DEFAULT 0,
+			client_type TEXT NOT NULL DEFAULT 'qbittorrent',
 			last_connected_at TIMESTAMP,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -622,7 +648,8 @@ func TestInstanceStoreUpdateOrder(t *testing.T) {
 			i.hardlink_base_dir,
 			i.hardlink_dir_preset,
 			i.use_reflinks,
-			i.fallback_to_regular_mode
+			i.fallback_to_regular_mode,
+			i.client_type
 		FROM instances i
 		LEFT JOIN string_pool sp_name ON i.name_id = sp_name.id
 		LEFT JOIN string_pool sp_host ON i.host_id = sp_host.id
@@ -702,6 +729,7 @@ func TestInstanceStoreAPIKeyAuth(t *testing.T) {
 			hardlink_dir_preset TEXT NOT NULL DEFAULT '',
 			use_reflinks BOOLEAN NOT NULL DEFAULT 0,
 			fallback_to_regular_mode BOOLEAN NOT NULL DEFAULT 0,
+			client_type TEXT NOT NULL DEFAULT 'qbittorrent',
 			FOREIGN KEY (name_id) REFERENCES string_pool(id),
 			FOREIGN KEY (host_id) REFERENCES string_pool(id),
 			FOREIGN KEY (username_id) REFERENCES string_pool(id),
@@ -726,7 +754,8 @@ func TestInstanceStoreAPIKeyAuth(t *testing.T) {
 			i.hardlink_base_dir,
 			i.hardlink_dir_preset,
 			i.use_reflinks,
-			i.fallback_to_regular_mode
+			i.fallback_to_regular_mode,
+			i.client_type
 		FROM instances i
 		LEFT JOIN string_pool sp_name ON i.name_id = sp_name.id
 		LEFT JOIN string_pool sp_host ON i.host_id = sp_host.id
@@ -836,6 +865,7 @@ func newInstanceStoreWithAPIKeySchema(t *testing.T) *InstanceStore {
 			hardlink_dir_preset TEXT NOT NULL DEFAULT '',
 			use_reflinks BOOLEAN NOT NULL DEFAULT 0,
 			fallback_to_regular_mode BOOLEAN NOT NULL DEFAULT 0,
+			client_type TEXT NOT NULL DEFAULT 'qbittorrent',
 			FOREIGN KEY (name_id) REFERENCES string_pool(id),
 			FOREIGN KEY (host_id) REFERENCES string_pool(id),
 			FOREIGN KEY (username_id) REFERENCES string_pool(id),
@@ -860,7 +890,8 @@ func newInstanceStoreWithAPIKeySchema(t *testing.T) *InstanceStore {
 			i.hardlink_base_dir,
 			i.hardlink_dir_preset,
 			i.use_reflinks,
-			i.fallback_to_regular_mode
+			i.fallback_to_regular_mode,
+			i.client_type
 		FROM instances i
 		LEFT JOIN string_pool sp_name ON i.name_id = sp_name.id
 		LEFT JOIN string_pool sp_host ON i.host_id = sp_host.id
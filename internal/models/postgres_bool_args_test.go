@@ -319,7 +319,9 @@ func TestBackupUpsertSettingsUsesIntegerBooleanArgs(t *testing.T) {
 			include_categories INTEGER NOT NULL DEFAULT 1,
 			include_tags INTEGER NOT NULL DEFAULT 1,
 			include_save_paths INTEGER NOT NULL DEFAULT 1,
-			custom_path TEXT
+			custom_path TEXT,
+			destination_json TEXT,
+			encryption_json TEXT
 		)
 	`)
 
@@ -354,7 +356,7 @@ func TestBackupUpsertSettingsUsesIntegerBooleanArgs(t *testing.T) {
 
 	err := store.UpsertSettings(context.Background(), settings)
 	require.NoError(t, err)
-	require.Len(t, insertArgs, 14)
+	require.Len(t, insertArgs, 16)
 
 	boolIndexes := []int{1, 2, 3, 4, 5, 10, 11, 12}
 	for _, idx := range boolIndexes {
@@ -480,6 +482,10 @@ func TestAutomationReadsIntegerBooleanColumns(t *testing.T) {
 			notify INTEGER NOT NULL DEFAULT 1,
 			sort_order INTEGER NOT NULL DEFAULT 0,
 			interval_seconds INTEGER,
+			trigger_mode TEXT NOT NULL DEFAULT 'interval',
+			schedule TEXT,
+			targets TEXT,
+			instance_overrides TEXT,
 			free_space_source TEXT,
 			sorting_config TEXT,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -0,0 +1,261 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// PreferenceKeyGroup is a named set of qBittorrent preference keys a profile
+// may hold.
+type PreferenceKeyGroup struct {
+	Name string   `json:"name"`
+	Keys []string `json:"keys"`
+}
+
+// PreferenceKeyGroups lists the qBittorrent preferences profiles can manage,
+// by their Web API names. Paths, credentials and network bindings are left out
+// on purpose: they differ per instance.
+var PreferenceKeyGroups = []PreferenceKeyGroup{
+	{Name: "connections", Keys: []string{
+		"max_connec", "max_connec_per_torrent", "max_uploads", "max_uploads_per_torrent",
+	}},
+	{Name: "queueing", Keys: []string{
+		"queueing_enabled", "max_active_downloads", "max_active_uploads", "max_active_torrents",
+		"max_active_checking_torrents", "dont_count_slow_torrents", "slow_torrent_dl_rate_threshold",
+		"slow_torrent_ul_rate_threshold", "slow_torrent_inactive_timer",
+	}},
+	{Name: "speed_schedule", Keys: []string{
+		"alt_dl_limit", "alt_up_limit", "scheduler_enabled", "schedule_from_hour",
+		"schedule_from_min", "schedule_to_hour", "schedule_to_min", "scheduler_days",
+	}},
+	{Name: "disk_cache", Keys: []string{
+		"disk_cache", "disk_cache_ttl", "disk_queue_size", "disk_io_read_mode", "disk_io_write_mode",
+	}},
+}
+
+// ErrUnmanagedPreference is returned for preference keys outside PreferenceKeyGroups.
+var ErrUnmanagedPreference = errors.New("preference cannot be managed by a profile")
+
+// IsPreferenceProfileKey reports whether key belongs to one of the PreferenceKeyGroups.
+func IsPreferenceProfileKey(key string) bool {
+	for _, group := range PreferenceKeyGroups {
+		if slices.Contains(group.Keys, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// PreferenceProfile is a named set of qBittorrent preferences. InstanceIDs are
+// the instances the profile is meant for; with DriftAlerts on, qui checks them
+// periodically and notifies when their preferences stop matching.
+type PreferenceProfile struct {
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Preferences map[string]any `json:"preferences"`
+	InstanceIDs []int          `json:"instanceIds"`
+	DriftAlerts bool           `json:"driftAlerts"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+// Validate checks the name, the preference keys and values, and the instances.
+func (p *PreferenceProfile) Validate() error {
+	if p == nil {
+		return errors.New("preference profile is nil")
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(p.Preferences) == 0 {
+		return errors.New("at least one preference is required")
+	}
+	for key, value := range p.Preferences {
+		if !IsPreferenceProfileKey(key) {
+			return fmt.Errorf("%w: %q", ErrUnmanagedPreference, key)
+		}
+		switch value.(type) {
+		case bool, string, float64, int, int64, json.Number:
+		default:
+			return fmt.Errorf("preference %q must be a boolean, number or string", key)
+		}
+	}
+	for _, id := range p.InstanceIDs {
+		if id <= 0 {
+			return fmt.Errorf("invalid instance ID %d", id)
+		}
+	}
+	if p.DriftAlerts && len(p.InstanceIDs) == 0 {
+		return errors.New("drift alerts need at least one instance")
+	}
+	return nil
+}
+
+type PreferenceProfileStore struct {
+	db dbinterface.Querier
+}
+
+func NewPreferenceProfileStore(db dbinterface.Querier) *PreferenceProfileStore {
+	return &PreferenceProfileStore{db: db}
+}
+
+const preferenceProfileColumns = `id, name, description, preferences, instance_ids, drift_alerts, created_at, updated_at`
+
+func (s *PreferenceProfileStore) List(ctx context.Context) ([]*PreferenceProfile, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+preferenceProfileColumns+`
+		FROM preference_profiles
+		ORDER BY name ASC, id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []*PreferenceProfile
+	for rows.Next() {
+		profile, err := scanPreferenceProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+func (s *PreferenceProfileStore) Get(ctx context.Context, id int) (*PreferenceProfile, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+preferenceProfileColumns+`
+		FROM preference_profiles
+		WHERE id = ?
+	`, id)
+	return scanPreferenceProfile(row)
+}
+
+func (s *PreferenceProfileStore) Create(ctx context.Context, p *PreferenceProfile) (*PreferenceProfile, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	preferences, instanceIDs, err := marshalPreferenceProfile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO preference_profiles (name, description, preferences, instance_ids, drift_alerts)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`, strings.TrimSpace(p.Name), strings.TrimSpace(p.Description), preferences, instanceIDs, BoolToSQLite(p.DriftAlerts)).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, id)
+}
+
+func (s *PreferenceProfileStore) Update(ctx context.Context, p *PreferenceProfile) (*PreferenceProfile, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	preferences, instanceIDs, err := marshalPreferenceProfile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE preference_profiles
+		SET name = ?, description = ?, preferences = ?, instance_ids = ?, drift_alerts = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, strings.TrimSpace(p.Name), strings.TrimSpace(p.Description), preferences, instanceIDs, BoolToSQLite(p.DriftAlerts), p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.Get(ctx, p.ID)
+}
+
+func (s *PreferenceProfileStore) Delete(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM preference_profiles WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+type preferenceProfileScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPreferenceProfile(row preferenceProfileScanner) (*PreferenceProfile, error) {
+	var (
+		p           PreferenceProfile
+		preferences string
+		instanceIDs string
+		driftAlerts int
+	)
+	if err := row.Scan(&p.ID, &p.Name, &p.Description, &preferences, &instanceIDs, &driftAlerts, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	p.DriftAlerts = SQLiteIntToBool(driftAlerts)
+	p.Preferences = map[string]any{}
+	if err := json.Unmarshal([]byte(preferences), &p.Preferences); err != nil {
+		return nil, fmt.Errorf("decode preferences: %w", err)
+	}
+	p.InstanceIDs = []int{}
+	if err := json.Unmarshal([]byte(instanceIDs), &p.InstanceIDs); err != nil {
+		return nil, fmt.Errorf("decode instance ids: %w", err)
+	}
+	return &p, nil
+}
+
+func marshalPreferenceProfile(p *PreferenceProfile) (preferences, instanceIDs string, err error) {
+	data, err := json.Marshal(p.Preferences)
+	if err != nil {
+		return "", "", err
+	}
+	preferences = string(data)
+
+	ids := slices.Clone(p.InstanceIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if ids == nil {
+		ids = []int{}
+	}
+	data, err = json.Marshal(ids)
+	if err != nil {
+		return "", "", err
+	}
+	return preferences, string(data), nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferenceProfileValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := &PreferenceProfile{Name: "Seedbox", Preferences: map[string]any{"max_connec": 500.0, "queueing_enabled": true}, InstanceIDs: []int{1}, DriftAlerts: true}
+	require.NoError(t, valid.Validate())
+
+	for name, profile := range map[string]*PreferenceProfile{
+		"missing name":      {Preferences: map[string]any{"max_connec": 500}},
+		"no preferences":    {Name: "Seedbox"},
+		"unmanaged key":     {Name: "Seedbox", Preferences: map[string]any{"save_path": "/data"}},
+		"non-scalar value":  {Name: "Seedbox", Preferences: map[string]any{"max_connec": []any{1}}},
+		"invalid instance":  {Name: "Seedbox", Preferences: map[string]any{"max_connec": 500}, InstanceIDs: []int{0}},
+		"alerts, no target": {Name: "Seedbox", Preferences: map[string]any{"max_connec": 500}, DriftAlerts: true},
+	} {
+		assert.Error(t, profile.Validate(), name)
+	}
+}
+
+func TestPreferenceProfileStoreCRUD(t *testing.T) {
+	db := openSQLiteDB(t)
+	mustExec(t, db, `
+		CREATE TABLE preference_profiles (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			description TEXT NOT NULL DEFAULT '',
+			preferences TEXT NOT NULL DEFAULT '{}',
+			instance_ids TEXT NOT NULL DEFAULT '[]',
+			drift_alerts INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+
+	store := NewPreferenceProfileStore(&capturingQuerier{db: db})
+	ctx := context.Background()
+
+	created, err := store.Create(ctx, &PreferenceProfile{
+		Name:        " Seedbox ",
+		Preferences: map[string]any{"max_connec": 500, "scheduler_enabled": true},
+		InstanceIDs: []int{3, 1, 3},
+		DriftAlerts: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Seedbox", created.Name)
+	assert.Equal(t, []int{1, 3}, created.InstanceIDs)
+	assert.Equal(t, map[string]any{"max_connec": 500.0, "scheduler_enabled": true}, created.Preferences)
+	assert.True(t, created.DriftAlerts)
+
+	created.DriftAlerts = false
+	created.InstanceIDs = nil
+	updated, err := store.Update(ctx, created)
+	require.NoError(t, err)
+	assert.False(t, updated.DriftAlerts)
+	assert.Empty(t, updated.InstanceIDs)
+
+	list, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	require.NoError(t, store.Delete(ctx, created.ID))
+	require.ErrorIs(t, store.Delete(ctx, created.ID), sql.ErrNoRows)
+
+	_, err = store.Update(ctx, created)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
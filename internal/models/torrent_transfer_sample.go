@@ -0,0 +1,146 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// TorrentTransferSample is a torrent's cumulative transfer counters as of an hourly bucket.
+type TorrentTransferSample struct {
+	Hash        string `json:"hash"`
+	BucketStart int64  `json:"bucketStart"` // unix seconds, truncated to the hour
+	Uploaded    int64  `json:"uploaded"`
+	Downloaded  int64  `json:"downloaded"`
+	// LastUploadAt is when the uploaded counter last grew (unix seconds). For torrents
+	// the sampler has never seen grow it is the time the torrent was first sampled.
+	LastUploadAt int64 `json:"lastUploadAt"`
+}
+
+type TorrentTransferSampleStore struct {
+	db dbinterface.Querier
+}
+
+func NewTorrentTransferSampleStore(db dbinterface.Querier) *TorrentTransferSampleStore {
+	return &TorrentTransferSampleStore{db: db}
+}
+
+// Upsert writes samples, replacing any sample already stored for the same torrent and bucket.
+func (s *TorrentTransferSampleStore) Upsert(ctx context.Context, instanceID int, samples []TorrentTransferSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// 6 params per row keeps full chunks well under SQLite's default 999 variable limit.
+	const chunkSize = 150
+	const paramsPerItem = 6
+
+	queryTemplate := `INSERT INTO torrent_transfer_samples (
+		instance_id, torrent_hash, bucket_start, uploaded, downloaded, last_upload_at
+	) VALUES %s
+	ON CONFLICT(instance_id, torrent_hash, bucket_start) DO UPDATE SET
+		uploaded = excluded.uploaded,
+		downloaded = excluded.downloaded,
+		last_upload_at = excluded.last_upload_at`
+	fullQuery := dbinterface.BuildQueryWithPlaceholders(queryTemplate, paramsPerItem, chunkSize)
+
+	for i := 0; i < len(samples); i += chunkSize {
+		chunk := samples[i:min(i+chunkSize, len(samples))]
+
+		query := fullQuery
+		if len(chunk) < chunkSize {
+			query = dbinterface.BuildQueryWithPlaceholders(queryTemplate, paramsPerItem, len(chunk))
+		}
+
+		args := make([]any, 0, len(chunk)*paramsPerItem)
+		for _, sample := range chunk {
+			args = append(args, instanceID, sample.Hash, sample.BucketStart, sample.Uploaded, sample.Downloaded, sample.LastUploadAt)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to upsert torrent transfer samples: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LatestAt returns, per torrent, the newest sample whose bucket starts at or before at (unix seconds).
+func (s *TorrentTransferSampleStore) LatestAt(ctx context.Context, instanceID int, at int64) (map[string]TorrentTransferSample, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT s.torrent_hash, s.bucket_start, s.uploaded, s.downloaded, s.last_upload_at
+		FROM torrent_transfer_samples s
+		JOIN (
+			SELECT torrent_hash, MAX(bucket_start) AS bucket_start
+			FROM torrent_transfer_samples
+			WHERE instance_id = ? AND bucket_start <= ?
+			GROUP BY torrent_hash
+		) latest ON latest.torrent_hash = s.torrent_hash AND latest.bucket_start = s.bucket_start
+		WHERE s.instance_id = ?
+	`, instanceID, at, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	samples := make(map[string]TorrentTransferSample)
+	for rows.Next() {
+		var sample TorrentTransferSample
+		if err := rows.Scan(&sample.Hash, &sample.BucketStart, &sample.Uploaded, &sample.Downloaded, &sample.LastUploadAt); err != nil {
+			return nil, err
+		}
+		samples[sample.Hash] = sample
+	}
+
+	return samples, rows.Err()
+}
+
+// DeleteTorrents removes every sample of the given torrents.
+func (s *TorrentTransferSampleStore) DeleteTorrents(ctx context.Context, instanceID int, hashes []string) error {
+	const chunkSize = 500
+	for i := 0; i < len(hashes); i += chunkSize {
+		chunk := hashes[i:min(i+chunkSize, len(hashes))]
+
+		args := make([]any, 0, len(chunk)+1)
+		args = append(args, instanceID)
+		for _, hash := range chunk {
+			args = append(args, hash)
+		}
+
+		query := fmt.Sprintf(`DELETE FROM torrent_transfer_samples WHERE instance_id = ? AND torrent_hash IN (%s)`, buildPlaceholders(len(chunk)))
+		if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to delete torrent transfer samples: %w", err)
+		}
+	}
+	return nil
+}
+
+// Prune removes samples older than cutoff (unix seconds), keeping each torrent's
+// newest sample at or before cutoff so windows reaching back to it keep a baseline.
+func (s *TorrentTransferSampleStore) Prune(ctx context.Context, instanceID int, cutoff int64) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM torrent_transfer_samples
+		WHERE instance_id = ? AND bucket_start < ?
+			AND EXISTS (
+				SELECT 1 FROM torrent_transfer_samples newer
+				WHERE newer.instance_id = torrent_transfer_samples.instance_id
+					AND newer.torrent_hash = torrent_transfer_samples.torrent_hash
+					AND newer.bucket_start > torrent_transfer_samples.bucket_start
+					AND newer.bucket_start <= ?
+			)
+	`, instanceID, cutoff, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTorrentTransferSampleStore(t *testing.T) {
+	db := openSQLiteDB(t)
+	mustExec(t, db, `
+		CREATE TABLE torrent_transfer_samples (
+			instance_id INTEGER NOT NULL,
+			torrent_hash TEXT NOT NULL,
+			bucket_start INTEGER NOT NULL,
+			uploaded INTEGER NOT NULL,
+			downloaded INTEGER NOT NULL,
+			last_upload_at INTEGER NOT NULL,
+			PRIMARY KEY (instance_id, torrent_hash, bucket_start)
+		)
+	`)
+
+	store := NewTorrentTransferSampleStore(&capturingQuerier{db: db})
+	ctx := context.Background()
+	const hour = int64(3600)
+
+	var samples []TorrentTransferSample
+	for i := range 200 {
+		samples = append(samples, TorrentTransferSample{Hash: fmt.Sprintf("hash%03d", i), BucketStart: 0, Uploaded: 10, LastUploadAt: 0})
+	}
+	samples = append(samples,
+		TorrentTransferSample{Hash: "hash000", BucketStart: hour, Uploaded: 20, LastUploadAt: hour},
+		TorrentTransferSample{Hash: "hash000", BucketStart: 2 * hour, Uploaded: 30, LastUploadAt: 2 * hour},
+	)
+	require.NoError(t, store.Upsert(ctx, 1, samples))
+	require.NoError(t, store.Upsert(ctx, 2, samples[:1]))
+
+	// Rewriting the current bucket replaces the counters.
+	require.NoError(t, store.Upsert(ctx, 1, []TorrentTransferSample{{Hash: "hash000", BucketStart: 2 * hour, Uploaded: 35, Downloaded: 5, LastUploadAt: 2*hour + 60}}))
+
+	latest, err := store.LatestAt(ctx, 1, 10*hour)
+	require.NoError(t, err)
+	assert.Len(t, latest, 200)
+	assert.Equal(t, TorrentTransferSample{Hash: "hash000", BucketStart: 2 * hour, Uploaded: 35, Downloaded: 5, LastUploadAt: 2*hour + 60}, latest["hash000"])
+
+	baseline, err := store.LatestAt(ctx, 1, hour+59)
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), baseline["hash000"].Uploaded)
+
+	pruned, err := store.Prune(ctx, 1, hour+59)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned, "only hash000 has a newer baseline before the cutoff")
+
+	baseline, err = store.LatestAt(ctx, 1, hour+59)
+	require.NoError(t, err)
+	assert.Len(t, baseline, 200)
+	assert.Equal(t, int64(20), baseline["hash000"].Uploaded)
+
+	require.NoError(t, store.DeleteTorrents(ctx, 1, []string{"hash000", "hash001"}))
+	latest, err = store.LatestAt(ctx, 1, 10*hour)
+	require.NoError(t, err)
+	assert.Len(t, latest, 198)
+	assert.NotContains(t, latest, "hash000")
+
+	other, err := store.LatestAt(ctx, 2, 10*hour)
+	require.NoError(t, err)
+	assert.Len(t, other, 1, "other instances are left alone")
+}
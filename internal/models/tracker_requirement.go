@@ -0,0 +1,354 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// TrackerRequirementOverride replaces a requirement's thresholds for one category.
+// Nil fields inherit the requirement's defaults.
+type TrackerRequirementOverride struct {
+	Category           string   `json:"category"`
+	MinRatio           *float64 `json:"minRatio,omitempty"`
+	MinSeedTimeMinutes *int64   `json:"minSeedTimeMinutes,omitempty"`
+	GracePeriodMinutes *int64   `json:"gracePeriodMinutes,omitempty"`
+}
+
+// TrackerRequirement describes a tracker's seeding rules (hit-and-run protection).
+// Domains are matched the same way as TrackerCustomization domains.
+//
+// A torrent satisfies the requirement once it reaches MinRatio or has seeded for
+// MinSeedTimeMinutes plus GracePeriodMinutes (both when RequireAll is set).
+// Zero thresholds are not enforced. The grace period is a safety margin for
+// tracker-side reporting lag.
+type TrackerRequirement struct {
+	ID                 int                          `json:"id"`
+	Name               string                       `json:"name"`
+	Domains            []string                     `json:"domains"`
+	Enabled            bool                         `json:"enabled"`
+	MinRatio           float64                      `json:"minRatio"`
+	MinSeedTimeMinutes int64                        `json:"minSeedTimeMinutes"`
+	GracePeriodMinutes int64                        `json:"gracePeriodMinutes"`
+	RequireAll         bool                         `json:"requireAll"`
+	CategoryOverrides  []TrackerRequirementOverride `json:"categoryOverrides"`
+	CreatedAt          time.Time                    `json:"createdAt"`
+	UpdatedAt          time.Time                    `json:"updatedAt"`
+}
+
+// Validate checks thresholds and overrides.
+func (r *TrackerRequirement) Validate() error {
+	if r == nil {
+		return errors.New("requirement is nil")
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(splitDomains(joinDomains(r.Domains))) == 0 {
+		return errors.New("at least one domain is required")
+	}
+	if err := validateRequirementThresholds(&r.MinRatio, &r.MinSeedTimeMinutes, &r.GracePeriodMinutes); err != nil {
+		return err
+	}
+	seen := make(map[string]struct{}, len(r.CategoryOverrides))
+	for i := range r.CategoryOverrides {
+		override := &r.CategoryOverrides[i]
+		key := strings.ToLower(strings.TrimSpace(override.Category))
+		if key == "" {
+			return fmt.Errorf("category override %d: category is required", i)
+		}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("duplicate category override %q", override.Category)
+		}
+		seen[key] = struct{}{}
+		if err := validateRequirementThresholds(override.MinRatio, override.MinSeedTimeMinutes, override.GracePeriodMinutes); err != nil {
+			return fmt.Errorf("category override %q: %w", override.Category, err)
+		}
+	}
+	return nil
+}
+
+func validateRequirementThresholds(minRatio *float64, minSeedTime, grace *int64) error {
+	if minRatio != nil && (*minRatio < 0 || math.IsNaN(*minRatio) || math.IsInf(*minRatio, 0)) {
+		return errors.New("minRatio must be a non-negative number")
+	}
+	if minSeedTime != nil && *minSeedTime < 0 {
+		return errors.New("minSeedTimeMinutes must not be negative")
+	}
+	if grace != nil && *grace < 0 {
+		return errors.New("gracePeriodMinutes must not be negative")
+	}
+	return nil
+}
+
+// HnRStatus is the outcome of checking a torrent against a TrackerRequirement.
+type HnRStatus struct {
+	Satisfied bool
+	// RemainingSeconds is the seed time still needed, 0 once satisfied, and -1
+	// when seed time alone cannot satisfy the requirement (ratio-only rules, or
+	// RequireAll with an unmet ratio).
+	RemainingSeconds   int64
+	MinRatio           float64
+	MinSeedTimeSeconds int64 // includes the grace period
+}
+
+// Evaluate checks a torrent's ratio and seeding time (seconds) against the
+// thresholds that apply to category.
+func (r *TrackerRequirement) Evaluate(category string, ratio float64, seedingSeconds int64) HnRStatus {
+	minRatio := r.MinRatio
+	minSeed := r.MinSeedTimeMinutes
+	grace := r.GracePeriodMinutes
+	for _, override := range r.CategoryOverrides {
+		if !strings.EqualFold(strings.TrimSpace(override.Category), strings.TrimSpace(category)) {
+			continue
+		}
+		if override.MinRatio != nil {
+			minRatio = *override.MinRatio
+		}
+		if override.MinSeedTimeMinutes != nil {
+			minSeed = *override.MinSeedTimeMinutes
+		}
+		if override.GracePeriodMinutes != nil {
+			grace = *override.GracePeriodMinutes
+		}
+		break
+	}
+
+	status := HnRStatus{MinRatio: minRatio}
+	if minSeed > 0 {
+		status.MinSeedTimeSeconds = (minSeed + grace) * 60
+	}
+
+	hasRatio := minRatio > 0
+	hasSeed := status.MinSeedTimeSeconds > 0
+	ratioMet := hasRatio && ratio >= minRatio
+	seedMet := hasSeed && seedingSeconds >= status.MinSeedTimeSeconds
+
+	switch {
+	case !hasRatio && !hasSeed:
+		status.Satisfied = true
+	case r.RequireAll:
+		status.Satisfied = (!hasRatio || ratioMet) && (!hasSeed || seedMet)
+	default:
+		status.Satisfied = ratioMet || seedMet
+	}
+
+	switch {
+	case status.Satisfied:
+		status.RemainingSeconds = 0
+	case !hasSeed, r.RequireAll && hasRatio && !ratioMet:
+		status.RemainingSeconds = -1
+	default:
+		status.RemainingSeconds = status.MinSeedTimeSeconds - max(seedingSeconds, 0)
+	}
+	return status
+}
+
+// TrackerRequirementIndex resolves tracker domains to enabled requirements.
+type TrackerRequirementIndex struct {
+	byDomain map[string]*TrackerRequirement
+}
+
+// NewTrackerRequirementIndex indexes enabled requirements by lowercase domain.
+// When two requirements list the same domain the first (by name) wins.
+func NewTrackerRequirementIndex(requirements []*TrackerRequirement) *TrackerRequirementIndex {
+	index := &TrackerRequirementIndex{byDomain: make(map[string]*TrackerRequirement)}
+	for _, requirement := range requirements {
+		if requirement == nil || !requirement.Enabled {
+			continue
+		}
+		for _, domain := range requirement.Domains {
+			key := strings.ToLower(strings.TrimSpace(domain))
+			if key == "" {
+				continue
+			}
+			if _, exists := index.byDomain[key]; !exists {
+				index.byDomain[key] = requirement
+			}
+		}
+	}
+	return index
+}
+
+// Match returns the requirement for the first domain that has one.
+func (idx *TrackerRequirementIndex) Match(domains ...string) *TrackerRequirement {
+	if idx == nil {
+		return nil
+	}
+	for _, domain := range domains {
+		if requirement, ok := idx.byDomain[strings.ToLower(strings.TrimSpace(domain))]; ok {
+			return requirement
+		}
+	}
+	return nil
+}
+
+// Len returns the number of indexed domains.
+func (idx *TrackerRequirementIndex) Len() int {
+	if idx == nil {
+		return 0
+	}
+	return len(idx.byDomain)
+}
+
+type TrackerRequirementStore struct {
+	db dbinterface.Querier
+}
+
+func NewTrackerRequirementStore(db dbinterface.Querier) *TrackerRequirementStore {
+	return &TrackerRequirementStore{db: db}
+}
+
+const trackerRequirementColumns = `id, name, domains, enabled, min_ratio, min_seed_time_minutes, grace_period_minutes, require_all, category_overrides, created_at, updated_at`
+
+func (s *TrackerRequirementStore) List(ctx context.Context) ([]*TrackerRequirement, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+trackerRequirementColumns+`
+		FROM tracker_requirements
+		ORDER BY name ASC, id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requirements []*TrackerRequirement
+	for rows.Next() {
+		requirement, err := scanTrackerRequirement(rows)
+		if err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, requirement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requirements, nil
+}
+
+func (s *TrackerRequirementStore) Get(ctx context.Context, id int) (*TrackerRequirement, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+trackerRequirementColumns+`
+		FROM tracker_requirements
+		WHERE id = ?
+	`, id)
+	return scanTrackerRequirement(row)
+}
+
+func (s *TrackerRequirementStore) Create(ctx context.Context, r *TrackerRequirement) (*TrackerRequirement, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	overrides, err := marshalRequirementOverrides(r.CategoryOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO tracker_requirements (name, domains, enabled, min_ratio, min_seed_time_minutes, grace_period_minutes, require_all, category_overrides)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`, strings.TrimSpace(r.Name), joinDomains(r.Domains), BoolToSQLite(r.Enabled), r.MinRatio, r.MinSeedTimeMinutes, r.GracePeriodMinutes, BoolToSQLite(r.RequireAll), overrides).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, id)
+}
+
+func (s *TrackerRequirementStore) Update(ctx context.Context, r *TrackerRequirement) (*TrackerRequirement, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	overrides, err := marshalRequirementOverrides(r.CategoryOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE tracker_requirements
+		SET name = ?, domains = ?, enabled = ?, min_ratio = ?, min_seed_time_minutes = ?, grace_period_minutes = ?, require_all = ?, category_overrides = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, strings.TrimSpace(r.Name), joinDomains(r.Domains), BoolToSQLite(r.Enabled), r.MinRatio, r.MinSeedTimeMinutes, r.GracePeriodMinutes, BoolToSQLite(r.RequireAll), overrides, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.Get(ctx, r.ID)
+}
+
+func (s *TrackerRequirementStore) Delete(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM tracker_requirements WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+type trackerRequirementScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTrackerRequirement(row trackerRequirementScanner) (*TrackerRequirement, error) {
+	var (
+		r          TrackerRequirement
+		domainsStr string
+		enabled    int
+		requireAll int
+		overrides  string
+	)
+	if err := row.Scan(&r.ID, &r.Name, &domainsStr, &enabled, &r.MinRatio, &r.MinSeedTimeMinutes, &r.GracePeriodMinutes, &requireAll, &overrides, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	r.Domains = splitDomains(domainsStr)
+	r.Enabled = SQLiteIntToBool(enabled)
+	r.RequireAll = SQLiteIntToBool(requireAll)
+	r.CategoryOverrides = []TrackerRequirementOverride{}
+	if strings.TrimSpace(overrides) != "" {
+		if err := json.Unmarshal([]byte(overrides), &r.CategoryOverrides); err != nil {
+			return nil, fmt.Errorf("decode category overrides: %w", err)
+		}
+	}
+	return &r, nil
+}
+
+func marshalRequirementOverrides(overrides []TrackerRequirementOverride) (string, error) {
+	if overrides == nil {
+		overrides = []TrackerRequirementOverride{}
+	}
+	for i := range overrides {
+		overrides[i].Category = strings.TrimSpace(overrides[i].Category)
+	}
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackerRequirementEvaluate(t *testing.T) {
+	t.Parallel()
+
+	ratio := func(v float64) *float64 { return &v }
+	minutes := func(v int64) *int64 { return &v }
+
+	requirement := &TrackerRequirement{
+		MinRatio:           1,
+		MinSeedTimeMinutes: 60,
+		GracePeriodMinutes: 10,
+		CategoryOverrides: []TrackerRequirementOverride{
+			{Category: "Movies", MinSeedTimeMinutes: minutes(120)},
+			{Category: "freeleech", MinRatio: ratio(0), MinSeedTimeMinutes: minutes(0)},
+		},
+	}
+	requireAll := *requirement
+	requireAll.RequireAll = true
+	ratioOnly := &TrackerRequirement{MinRatio: 2}
+
+	tests := []struct {
+		name        string
+		requirement *TrackerRequirement
+		category    string
+		ratio       float64
+		seeding     int64
+		satisfied   bool
+		remaining   int64
+	}{
+		{name: "ratio met", requirement: requirement, ratio: 1.2, seeding: 0, satisfied: true},
+		{name: "seed time met including grace", requirement: requirement, ratio: 0.1, seeding: 70 * 60, satisfied: true},
+		{name: "seed time short by grace", requirement: requirement, ratio: 0.1, seeding: 60 * 60, remaining: 10 * 60},
+		{name: "category override", requirement: requirement, category: "movies", ratio: 0.1, seeding: 70 * 60, remaining: 60 * 60},
+		{name: "override clearing thresholds", requirement: requirement, category: "freeleech", satisfied: true},
+		{name: "require all missing ratio", requirement: &requireAll, ratio: 0.5, seeding: 80 * 60, remaining: -1},
+		{name: "require all missing seed", requirement: &requireAll, ratio: 1.5, seeding: 30 * 60, remaining: 40 * 60},
+		{name: "require all met", requirement: &requireAll, ratio: 1.5, seeding: 80 * 60, satisfied: true},
+		{name: "ratio only", requirement: ratioOnly, ratio: 1, seeding: 999999, remaining: -1},
+		{name: "no thresholds", requirement: &TrackerRequirement{}, satisfied: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			status := tt.requirement.Evaluate(tt.category, tt.ratio, tt.seeding)
+			assert.Equal(t, tt.satisfied, status.Satisfied)
+			assert.Equal(t, tt.remaining, status.RemainingSeconds)
+		})
+	}
+}
+
+func TestTrackerRequirementValidate(t *testing.T) {
+	t.Parallel()
+
+	negative := -1.0
+	valid := &TrackerRequirement{Name: "Tracker", Domains: []string{"tracker.example"}, MinRatio: 1}
+	require.NoError(t, valid.Validate())
+
+	for name, requirement := range map[string]*TrackerRequirement{
+		"missing name":       {Domains: []string{"tracker.example"}},
+		"missing domains":    {Name: "Tracker", Domains: []string{" "}},
+		"negative seed time": {Name: "Tracker", Domains: []string{"a"}, MinSeedTimeMinutes: -5},
+		"blank override":     {Name: "Tracker", Domains: []string{"a"}, CategoryOverrides: []TrackerRequirementOverride{{}}},
+		"duplicate override": {Name: "Tracker", Domains: []string{"a"}, CategoryOverrides: []TrackerRequirementOverride{{Category: "tv"}, {Category: "TV"}}},
+		"negative override":  {Name: "Tracker", Domains: []string{"a"}, CategoryOverrides: []TrackerRequirementOverride{{Category: "tv", MinRatio: &negative}}},
+	} {
+		assert.Error(t, requirement.Validate(), name)
+	}
+}
+
+func TestTrackerRequirementIndexMatch(t *testing.T) {
+	t.Parallel()
+
+	first := &TrackerRequirement{ID: 1, Name: "A", Enabled: true, Domains: []string{"Tracker.Example", "alt.example"}}
+	shadowed := &TrackerRequirement{ID: 2, Name: "B", Enabled: true, Domains: []string{"tracker.example"}}
+	disabled := &TrackerRequirement{ID: 3, Name: "C", Enabled: false, Domains: []string{"other.example"}}
+
+	index := NewTrackerRequirementIndex([]*TrackerRequirement{first, shadowed, disabled})
+	assert.Same(t, first, index.Match("tracker.example"))
+	assert.Same(t, first, index.Match("unknown.example", "ALT.example"))
+	assert.Nil(t, index.Match("other.example"))
+	assert.Nil(t, (*TrackerRequirementIndex)(nil).Match("tracker.example"))
+}
+
+func TestTrackerRequirementStoreCRUD(t *testing.T) {
+	db := openSQLiteDB(t)
+	mustExec(t, db, `
+		CREATE TABLE tracker_requirements (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			domains TEXT NOT NULL,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			min_ratio REAL NOT NULL DEFAULT 0,
+			min_seed_time_minutes INTEGER NOT NULL DEFAULT 0,
+			grace_period_minutes INTEGER NOT NULL DEFAULT 0,
+			require_all INTEGER NOT NULL DEFAULT 0,
+			category_overrides TEXT NOT NULL DEFAULT '[]',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+
+	store := NewTrackerRequirementStore(&capturingQuerier{db: db})
+	ctx := context.Background()
+
+	seedMinutes := int64(4320)
+	created, err := store.Create(ctx, &TrackerRequirement{
+		Name:               "Example",
+		Domains:            []string{"tracker.example", "tracker.example", "alt.example"},
+		Enabled:            true,
+		MinRatio:           1,
+		MinSeedTimeMinutes: 2880,
+		RequireAll:         true,
+		CategoryOverrides:  []TrackerRequirementOverride{{Category: " tv ", MinSeedTimeMinutes: &seedMinutes}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tracker.example", "alt.example"}, created.Domains)
+	assert.True(t, created.Enabled)
+	assert.True(t, created.RequireAll)
+	require.Len(t, created.CategoryOverrides, 1)
+	assert.Equal(t, "tv", created.CategoryOverrides[0].Category)
+	assert.Equal(t, seedMinutes, *created.CategoryOverrides[0].MinSeedTimeMinutes)
+
+	created.Enabled = false
+	created.CategoryOverrides = nil
+	updated, err := store.Update(ctx, created)
+	require.NoError(t, err)
+	assert.False(t, updated.Enabled)
+	assert.Empty(t, updated.CategoryOverrides)
+
+	list, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	require.NoError(t, store.Delete(ctx, created.ID))
+	require.ErrorIs(t, store.Delete(ctx, created.ID), sql.ErrNoRows)
+
+	_, err = store.Update(ctx, created)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,271 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// TransferStatsResolution is the bucket size of a transfer stats series.
+type TransferStatsResolution string
+
+const (
+	TransferStatsMinute TransferStatsResolution = "minute"
+	TransferStatsHour   TransferStatsResolution = "hour"
+	TransferStatsDay    TransferStatsResolution = "day"
+)
+
+// TransferStatsResolutions lists the resolutions from finest to coarsest.
+var TransferStatsResolutions = []TransferStatsResolution{TransferStatsMinute, TransferStatsHour, TransferStatsDay}
+
+// Bucket sizes and how long each resolution is kept. Day buckets are kept forever.
+const (
+	TransferStatsMinuteRetention = 48 * time.Hour
+	TransferStatsHourRetention   = 90 * 24 * time.Hour
+)
+
+// Duration returns the bucket size.
+func (r TransferStatsResolution) Duration() time.Duration {
+	switch r {
+	case TransferStatsMinute:
+		return time.Minute
+	case TransferStatsHour:
+		return time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// Retention returns how long buckets are kept, or 0 when they are kept forever.
+func (r TransferStatsResolution) Retention() time.Duration {
+	switch r {
+	case TransferStatsMinute:
+		return TransferStatsMinuteRetention
+	case TransferStatsHour:
+		return TransferStatsHourRetention
+	default:
+		return 0
+	}
+}
+
+// Valid reports whether r is a known resolution.
+func (r TransferStatsResolution) Valid() bool {
+	switch r {
+	case TransferStatsMinute, TransferStatsHour, TransferStatsDay:
+		return true
+	default:
+		return false
+	}
+}
+
+// maxAutoTransferStatsPoints bounds the series length when the resolution is chosen automatically.
+const maxAutoTransferStatsPoints = 1500
+
+// TransferStatsResolutionFor picks the finest resolution that still covers from
+// and keeps the range within a chart-sized number of points.
+func TransferStatsResolutionFor(from, to, now time.Time) TransferStatsResolution {
+	span := to.Sub(from)
+	for _, resolution := range TransferStatsResolutions {
+		if retention := resolution.Retention(); retention > 0 && from.Before(now.Add(-retention)) {
+			continue
+		}
+		if span/resolution.Duration() > maxAutoTransferStatsPoints {
+			continue
+		}
+		return resolution
+	}
+	return TransferStatsDay
+}
+
+// TransferStatsSample is the transfer of one tracker (or, with an empty
+// Tracker, the whole instance) since the previous sample.
+type TransferStatsSample struct {
+	Tracker      string
+	Uploaded     int64
+	Downloaded   int64
+	TorrentCount int
+}
+
+// TransferStatsPoint is one bucket of a transfer stats series.
+type TransferStatsPoint struct {
+	BucketStart  int64 `json:"bucketStart"` // unix seconds
+	Uploaded     int64 `json:"uploaded"`
+	Downloaded   int64 `json:"downloaded"`
+	TorrentCount int   `json:"torrentCount"`
+}
+
+// TransferStatsTrackerTotal is a tracker's transfer over a range.
+type TransferStatsTrackerTotal struct {
+	Tracker    string `json:"tracker"`
+	Uploaded   int64  `json:"uploaded"`
+	Downloaded int64  `json:"downloaded"`
+}
+
+// TransferStatsQuery selects a series. A nil InstanceID sums all instances and
+// an empty Tracker selects instance totals.
+type TransferStatsQuery struct {
+	InstanceID *int
+	Tracker    string
+	Resolution TransferStatsResolution
+	From       time.Time
+	To         time.Time
+}
+
+type TransferStatsStore struct {
+	db dbinterface.Querier
+}
+
+func NewTransferStatsStore(db dbinterface.Querier) *TransferStatsStore {
+	return &TransferStatsStore{db: db}
+}
+
+// Record adds samples taken at at to the minute, hour and day buckets containing it.
+func (s *TransferStatsStore) Record(ctx context.Context, instanceID int, at time.Time, samples []TransferStatsSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const chunkSize = 100
+	const paramsPerItem = 7
+
+	queryTemplate := `INSERT INTO transfer_stats (
+		instance_id, tracker, resolution, bucket_start, uploaded, downloaded, torrent_count
+	) VALUES %s
+	ON CONFLICT(instance_id, resolution, tracker, bucket_start) DO UPDATE SET
+		uploaded = transfer_stats.uploaded + excluded.uploaded,
+		downloaded = transfer_stats.downloaded + excluded.downloaded,
+		torrent_count = excluded.torrent_count`
+
+	type row struct {
+		resolution TransferStatsResolution
+		bucket     int64
+		sample     TransferStatsSample
+	}
+	rows := make([]row, 0, len(samples)*len(TransferStatsResolutions))
+	for _, resolution := range TransferStatsResolutions {
+		bucket := at.UTC().Truncate(resolution.Duration()).Unix()
+		for _, sample := range samples {
+			rows = append(rows, row{resolution: resolution, bucket: bucket, sample: sample})
+		}
+	}
+
+	for i := 0; i < len(rows); i += chunkSize {
+		chunk := rows[i:min(i+chunkSize, len(rows))]
+		query := dbinterface.BuildQueryWithPlaceholders(queryTemplate, paramsPerItem, len(chunk))
+
+		args := make([]any, 0, len(chunk)*paramsPerItem)
+		for _, r := range chunk {
+			args = append(args, instanceID, r.sample.Tracker, string(r.resolution), r.bucket,
+				r.sample.Uploaded, r.sample.Downloaded, r.sample.TorrentCount)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to record transfer stats: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Series returns the buckets of q in ascending order. Empty buckets are omitted.
+func (s *TransferStatsStore) Series(ctx context.Context, q TransferStatsQuery) ([]TransferStatsPoint, error) {
+	conditions := []string{"resolution = ?", "tracker = ?", "bucket_start >= ?", "bucket_start <= ?"}
+	args := []any{string(q.Resolution), q.Tracker, q.From.UTC().Truncate(q.Resolution.Duration()).Unix(), q.To.Unix()}
+	if q.InstanceID != nil {
+		conditions = append(conditions, "instance_id = ?")
+		args = append(args, *q.InstanceID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT bucket_start, CAST(SUM(uploaded) AS BIGINT), CAST(SUM(downloaded) AS BIGINT), CAST(SUM(torrent_count) AS BIGINT)
+		FROM transfer_stats
+		WHERE `+strings.Join(conditions, " AND ")+`
+		GROUP BY bucket_start
+		ORDER BY bucket_start ASC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []TransferStatsPoint{}
+	for rows.Next() {
+		var point TransferStatsPoint
+		if err := rows.Scan(&point.BucketStart, &point.Uploaded, &point.Downloaded, &point.TorrentCount); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
+
+// TrackerTotals sums each tracker's transfer over the buckets of resolution in
+// [from, to], largest upload first.
+func (s *TransferStatsStore) TrackerTotals(ctx context.Context, instanceID *int, resolution TransferStatsResolution, from, to time.Time) ([]TransferStatsTrackerTotal, error) {
+	conditions := []string{"resolution = ?", "tracker <> ''", "bucket_start >= ?", "bucket_start <= ?"}
+	args := []any{string(resolution), from.UTC().Truncate(resolution.Duration()).Unix(), to.Unix()}
+	if instanceID != nil {
+		conditions = append(conditions, "instance_id = ?")
+		args = append(args, *instanceID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT tracker, CAST(SUM(uploaded) AS BIGINT) AS total_uploaded, CAST(SUM(downloaded) AS BIGINT)
+		FROM transfer_stats
+		WHERE `+strings.Join(conditions, " AND ")+`
+		GROUP BY tracker
+		ORDER BY total_uploaded DESC, tracker ASC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := []TransferStatsTrackerTotal{}
+	for rows.Next() {
+		var total TransferStatsTrackerTotal
+		if err := rows.Scan(&total.Tracker, &total.Uploaded, &total.Downloaded); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
+// Prune drops the minute and hour buckets that have aged out of their retention.
+func (s *TransferStatsStore) Prune(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	for _, resolution := range TransferStatsResolutions {
+		retention := resolution.Retention()
+		if retention == 0 {
+			continue
+		}
+		res, err := s.db.ExecContext(ctx, `
+			DELETE FROM transfer_stats
+			WHERE resolution = ? AND bucket_start < ?
+		`, string(resolution), now.Add(-retention).Unix())
+		if err != nil {
+			return deleted, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferStatsResolutionFor(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, TransferStatsMinute, TransferStatsResolutionFor(now.Add(-6*time.Hour), now, now))
+	assert.Equal(t, TransferStatsHour, TransferStatsResolutionFor(now.Add(-72*time.Hour), now, now), "minute buckets are gone")
+	assert.Equal(t, TransferStatsHour, TransferStatsResolutionFor(now.Add(-30*24*time.Hour), now, now))
+	assert.Equal(t, TransferStatsDay, TransferStatsResolutionFor(now.Add(-365*24*time.Hour), now, now))
+}
+
+func TestTransferStatsStore(t *testing.T) {
+	db := openSQLiteDB(t)
+	mustExec(t, db, `
+		CREATE TABLE transfer_stats (
+			instance_id   INTEGER NOT NULL,
+			tracker       TEXT NOT NULL DEFAULT '',
+			resolution    TEXT NOT NULL,
+			bucket_start  INTEGER NOT NULL,
+			uploaded      INTEGER NOT NULL DEFAULT 0,
+			downloaded    INTEGER NOT NULL DEFAULT 0,
+			torrent_count INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (instance_id, resolution, tracker, bucket_start)
+		)
+	`)
+
+	store := NewTransferStatsStore(&capturingQuerier{db: db})
+	ctx := context.Background()
+
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	first := day.Add(10*time.Hour + 5*time.Minute + 10*time.Second)
+	require.NoError(t, store.Record(ctx, 1, first, []TransferStatsSample{
+		{Uploaded: 100, Downloaded: 10, TorrentCount: 3},
+		{Tracker: "tracker.example", Uploaded: 100, Downloaded: 10, TorrentCount: 2},
+	}))
+	require.NoError(t, store.Record(ctx, 1, first.Add(30*time.Second), []TransferStatsSample{
+		{Uploaded: 50, TorrentCount: 4},
+		{Tracker: "tracker.example", Uploaded: 50, TorrentCount: 3},
+	}))
+	require.NoError(t, store.Record(ctx, 2, first.Add(time.Hour), []TransferStatsSample{
+		{Uploaded: 7, TorrentCount: 1},
+		{Tracker: "other.example", Uploaded: 7, TorrentCount: 1},
+	}))
+
+	instanceID := 1
+	minutes, err := store.Series(ctx, TransferStatsQuery{InstanceID: &instanceID, Resolution: TransferStatsMinute, From: day, To: day.Add(24 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, minutes, 1, "samples in the same minute share a bucket")
+	assert.Equal(t, TransferStatsPoint{BucketStart: day.Add(10*time.Hour + 5*time.Minute).Unix(), Uploaded: 150, Downloaded: 10, TorrentCount: 4}, minutes[0])
+
+	hours, err := store.Series(ctx, TransferStatsQuery{Resolution: TransferStatsHour, From: day, To: day.Add(24 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, hours, 2)
+	assert.Equal(t, int64(150), hours[0].Uploaded)
+	assert.Equal(t, int64(7), hours[1].Uploaded)
+
+	days, err := store.Series(ctx, TransferStatsQuery{Resolution: TransferStatsDay, From: day.Add(time.Hour), To: day.Add(24 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, days, 1, "from is aligned down to the bucket")
+	assert.Equal(t, TransferStatsPoint{BucketStart: day.Unix(), Uploaded: 157, Downloaded: 10, TorrentCount: 5}, days[0], "all instances are summed")
+
+	totals, err := store.TrackerTotals(ctx, nil, TransferStatsDay, day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []TransferStatsTrackerTotal{
+		{Tracker: "tracker.example", Uploaded: 150, Downloaded: 10},
+		{Tracker: "other.example", Uploaded: 7},
+	}, totals)
+
+	deleted, err := store.Prune(ctx, day.Add(5*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), deleted, "only minute buckets are older than their retention")
+
+	days, err = store.Series(ctx, TransferStatsQuery{Resolution: TransferStatsDay, From: day, To: day.Add(24 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/autobrr/qui/internal/dbinterface"
+)
+
+// VirtualInstancePlacement selects the member instance new torrents are added to.
+type VirtualInstancePlacement string
+
+const (
+	// PlacementRoundRobin cycles through the member instances.
+	PlacementRoundRobin VirtualInstancePlacement = "round_robin"
+	// PlacementFreeSpace picks the member with the most free disk space.
+	PlacementFreeSpace VirtualInstancePlacement = "free_space"
+	// PlacementCategory picks the member mapped to the torrent's category in
+	// CategoryInstances, falling back to free space for unmapped categories.
+	PlacementCategory VirtualInstancePlacement = "category"
+)
+
+// VirtualInstance groups several qBittorrent instances that the proxy serves as
+// a single qBittorrent Web API.
+type VirtualInstance struct {
+	ID                int                      `json:"id"`
+	Name              string                   `json:"name"`
+	MemberInstanceIDs []int                    `json:"memberInstanceIds"`
+	Placement         VirtualInstancePlacement `json:"placement"`
+	// CategoryInstances maps categories to the member instance used by PlacementCategory.
+	CategoryInstances map[string]int `json:"categoryInstances"`
+	CreatedAt         time.Time      `json:"createdAt"`
+	UpdatedAt         time.Time      `json:"updatedAt"`
+}
+
+// Validate checks the name, members and placement settings.
+func (v *VirtualInstance) Validate() error {
+	if v == nil {
+		return errors.New("virtual instance is nil")
+	}
+	if strings.TrimSpace(v.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(v.MemberInstanceIDs) == 0 {
+		return errors.New("at least one member instance is required")
+	}
+	for _, id := range v.MemberInstanceIDs {
+		if id <= 0 {
+			return fmt.Errorf("invalid member instance ID %d", id)
+		}
+	}
+	switch v.Placement {
+	case PlacementRoundRobin, PlacementFreeSpace, PlacementCategory:
+	default:
+		return fmt.Errorf("unknown placement %q", v.Placement)
+	}
+	for category, id := range v.CategoryInstances {
+		if !slices.Contains(v.MemberInstanceIDs, id) {
+			return fmt.Errorf("category %q maps to instance %d which is not a member", category, id)
+		}
+	}
+	return nil
+}
+
+// HasMember reports whether instanceID is one of the members.
+func (v *VirtualInstance) HasMember(instanceID int) bool {
+	return slices.Contains(v.MemberInstanceIDs, instanceID)
+}
+
+type VirtualInstanceStore struct {
+	db dbinterface.Querier
+}
+
+func NewVirtualInstanceStore(db dbinterface.Querier) *VirtualInstanceStore {
+	return &VirtualInstanceStore{db: db}
+}
+
+const virtualInstanceColumns = `id, name, member_instance_ids, placement, category_instances, created_at, updated_at`
+
+func (s *VirtualInstanceStore) List(ctx context.Context) ([]*VirtualInstance, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+virtualInstanceColumns+`
+		FROM virtual_instances
+		ORDER BY name ASC, id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instances []*VirtualInstance
+	for rows.Next() {
+		instance, err := scanVirtualInstance(rows)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+func (s *VirtualInstanceStore) Get(ctx context.Context, id int) (*VirtualInstance, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+virtualInstanceColumns+`
+		FROM virtual_instances
+		WHERE id = ?
+	`, id)
+	return scanVirtualInstance(row)
+}
+
+func (s *VirtualInstanceStore) Create(ctx context.Context, v *VirtualInstance) (*VirtualInstance, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	members, categories, err := marshalVirtualInstanceMembers(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO virtual_instances (name, member_instance_ids, placement, category_instances)
+		VALUES (?, ?, ?, ?)
+		RETURNING id
+	`, strings.TrimSpace(v.Name), members, string(v.Placement), categories).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, id)
+}
+
+func (s *VirtualInstanceStore) Update(ctx context.Context, v *VirtualInstance) (*VirtualInstance, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	members, categories, err := marshalVirtualInstanceMembers(v)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE virtual_instances
+		SET name = ?, member_instance_ids = ?, placement = ?, category_instances = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, strings.TrimSpace(v.Name), members, string(v.Placement), categories, v.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.Get(ctx, v.ID)
+}
+
+func (s *VirtualInstanceStore) Delete(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM virtual_instances WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+type virtualInstanceScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanVirtualInstance(row virtualInstanceScanner) (*VirtualInstance, error) {
+	var (
+		v          VirtualInstance
+		members    string
+		placement  string
+		categories string
+	)
+	if err := row.Scan(&v.ID, &v.Name, &members, &placement, &categories, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	v.Placement = VirtualInstancePlacement(placement)
+	v.MemberInstanceIDs = []int{}
+	if err := json.Unmarshal([]byte(members), &v.MemberInstanceIDs); err != nil {
+		return nil, fmt.Errorf("decode member instance ids: %w", err)
+	}
+	v.CategoryInstances = map[string]int{}
+	if strings.TrimSpace(categories) != "" {
+		if err := json.Unmarshal([]byte(categories), &v.CategoryInstances); err != nil {
+			return nil, fmt.Errorf("decode category instances: %w", err)
+		}
+	}
+	return &v, nil
+}
+
+func marshalVirtualInstanceMembers(v *VirtualInstance) (members, categories string, err error) {
+	ids := slices.Clone(v.MemberInstanceIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return "", "", err
+	}
+	members = string(data)
+
+	mapping := make(map[string]int, len(v.CategoryInstances))
+	for category, id := range v.CategoryInstances {
+		if category = strings.TrimSpace(category); category != "" {
+			mapping[category] = id
+		}
+	}
+	data, err = json.Marshal(mapping)
+	if err != nil {
+		return "", "", err
+	}
+	return members, string(data), nil
+}
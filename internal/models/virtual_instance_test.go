@@ -0,0 +1,77 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualInstanceValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := &VirtualInstance{Name: "All", MemberInstanceIDs: []int{1, 2}, Placement: PlacementCategory, CategoryInstances: map[string]int{"tv": 2}}
+	require.NoError(t, valid.Validate())
+
+	for name, virtual := range map[string]*VirtualInstance{
+		"missing name":       {MemberInstanceIDs: []int{1}, Placement: PlacementRoundRobin},
+		"no members":         {Name: "All", Placement: PlacementRoundRobin},
+		"invalid member":     {Name: "All", MemberInstanceIDs: []int{0}, Placement: PlacementRoundRobin},
+		"unknown placement":  {Name: "All", MemberInstanceIDs: []int{1}, Placement: "random"},
+		"category nonmember": {Name: "All", MemberInstanceIDs: []int{1}, Placement: PlacementCategory, CategoryInstances: map[string]int{"tv": 3}},
+	} {
+		assert.Error(t, virtual.Validate(), name)
+	}
+}
+
+func TestVirtualInstanceStoreCRUD(t *testing.T) {
+	db := openSQLiteDB(t)
+	mustExec(t, db, `
+		CREATE TABLE virtual_instances (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			member_instance_ids TEXT NOT NULL DEFAULT '[]',
+			placement TEXT NOT NULL DEFAULT 'round_robin',
+			category_instances TEXT NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+
+	store := NewVirtualInstanceStore(&capturingQuerier{db: db})
+	ctx := context.Background()
+
+	created, err := store.Create(ctx, &VirtualInstance{
+		Name:              " Seedbox pool ",
+		MemberInstanceIDs: []int{3, 1, 3},
+		Placement:         PlacementCategory,
+		CategoryInstances: map[string]int{" tv ": 3, "": 1},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Seedbox pool", created.Name)
+	assert.Equal(t, []int{1, 3}, created.MemberInstanceIDs)
+	assert.Equal(t, map[string]int{"tv": 3}, created.CategoryInstances)
+	assert.True(t, created.HasMember(3))
+
+	created.Placement = PlacementFreeSpace
+	created.CategoryInstances = nil
+	updated, err := store.Update(ctx, created)
+	require.NoError(t, err)
+	assert.Equal(t, PlacementFreeSpace, updated.Placement)
+	assert.Empty(t, updated.CategoryInstances)
+
+	list, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	require.NoError(t, store.Delete(ctx, created.ID))
+	require.ErrorIs(t, store.Delete(ctx, created.ID), sql.ErrNoRows)
+
+	_, err = store.Update(ctx, created)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
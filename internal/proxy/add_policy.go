@@ -0,0 +1,226 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// addPolicyChange records one form field rewritten by an add policy.
+type addPolicyChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// addPolicyMiddleware applies the client API key's add policy to torrents/add
+// requests. It runs before the scope check so scopes see the rewritten request.
+func (h *Handler) addPolicyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientAPIKey := GetClientAPIKeyFromContext(r.Context())
+		if clientAPIKey == nil || clientAPIKey.AddPolicy.IsZero() || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if proxyEndpoint(h.stripProxyPrefix(r.URL.Path, chi.URLParam(r, "api-key"))) != "torrents/add" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		changes, err := applyAddPolicy(r, clientAPIKey.AddPolicy, clientAPIKey.ClientName)
+		if err != nil {
+			log.Warn().Err(err).Int("keyId", clientAPIKey.ID).Str("client", clientAPIKey.ClientName).Msg("Failed to apply client API key add policy")
+			if errors.Is(err, errInvalidProxyForm) {
+				http.Error(w, "Invalid form data", http.StatusBadRequest)
+				return
+			}
+			h.writeProxyError(w)
+			return
+		}
+
+		if len(changes) > 0 {
+			event := log.Info().
+				Int("keyId", clientAPIKey.ID).
+				Str("client", clientAPIKey.ClientName).
+				Int("instanceId", GetInstanceIDFromContext(r.Context()))
+			for _, change := range changes {
+				event = event.Str(change.Field, fmt.Sprintf("%q -> %q", change.From, change.To))
+			}
+			event.Msg("Client API key add policy rewrote torrents/add request")
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// applyAddPolicy rewrites the torrents/add form in r according to policy and
+// returns the fields whose values changed.
+func applyAddPolicy(r *http.Request, policy models.ClientAPIKeyAddPolicy, clientName string) ([]addPolicyChange, error) {
+	form, err := peekForm(r)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := addPolicyOverrides(form, policy, clientName)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []addPolicyChange
+	for _, field := range slices.Sorted(maps.Keys(overrides)) {
+		if from := form.Get(field); from != overrides[field] {
+			changes = append(changes, addPolicyChange{Field: field, From: from, To: overrides[field]})
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	body, err := bufferRequestBody(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var rewritten []byte
+	var contentType string
+	if mediaType == "multipart/form-data" {
+		rewritten, contentType, err = rewriteMultipartForm(body, params["boundary"], overrides)
+	} else {
+		rewritten, contentType, err = rewriteURLEncodedForm(body, overrides)
+	}
+	if err != nil {
+		restoreBody(r, body)
+		return nil, err
+	}
+
+	// Query parameters would otherwise shadow the rewritten body fields.
+	if query := r.URL.Query(); len(query) > 0 {
+		for field := range overrides {
+			query.Del(field)
+		}
+		r.URL.RawQuery = query.Encode()
+	}
+
+	r.Header.Set("Content-Type", contentType)
+	r.Header.Del("Content-Length")
+	restoreBody(r, rewritten)
+	return changes, nil
+}
+
+// addPolicyOverrides returns the form fields the policy sets for a request with form.
+func addPolicyOverrides(form url.Values, policy models.ClientAPIKeyAddPolicy, clientName string) (map[string]string, error) {
+	overrides := make(map[string]string)
+
+	category := policy.ApplyCategory(form.Get("category"))
+	if category != "" {
+		overrides["category"] = category
+	}
+	tags := policy.ApplyTags(form.Get("tags"))
+	if len(policy.Tags) > 0 {
+		overrides["tags"] = tags
+	}
+
+	savePath, err := policy.RenderSavePath(models.AddPolicyPathData{
+		Category: category,
+		Tags:     tags,
+		SavePath: form.Get("savepath"),
+		Client:   clientName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if savePath != "" {
+		overrides["savepath"] = savePath
+		// qBittorrent ignores savepath for torrents in automatic management.
+		overrides["autoTMM"] = "false"
+	}
+
+	if policy.Paused != nil {
+		paused := strconv.FormatBool(*policy.Paused)
+		// qBittorrent 5 renamed paused to stopped; send both.
+		overrides["paused"] = paused
+		overrides["stopped"] = paused
+	}
+	if policy.UploadLimit != nil {
+		overrides["upLimit"] = strconv.FormatInt(*policy.UploadLimit, 10)
+	}
+	if policy.RatioLimit != nil {
+		overrides["ratioLimit"] = strconv.FormatFloat(*policy.RatioLimit, 'f', -1, 64)
+	}
+	if policy.SeedingTimeLimit != nil {
+		overrides["seedingTimeLimit"] = strconv.FormatInt(*policy.SeedingTimeLimit, 10)
+	}
+	if policy.InactiveSeedingTimeLimit != nil {
+		overrides["inactiveSeedingTimeLimit"] = strconv.FormatInt(*policy.InactiveSeedingTimeLimit, 10)
+	}
+	return overrides, nil
+}
+
+// rewriteMultipartForm copies a multipart body, replacing the fields in overrides.
+// File parts are copied unchanged.
+func rewriteMultipartForm(body []byte, boundary string, overrides map[string]string) ([]byte, string, error) {
+	if boundary == "" {
+		return nil, "", fmt.Errorf("%w: missing multipart boundary", errInvalidProxyForm)
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextRawPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+		}
+		if _, ok := overrides[part.FormName()]; ok && part.FileName() == "" {
+			continue
+		}
+		dst, err := writer.CreatePart(part.Header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(dst, part); err != nil {
+			return nil, "", fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+		}
+	}
+	for _, field := range slices.Sorted(maps.Keys(overrides)) {
+		if err := writer.WriteField(field, overrides[field]); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// rewriteURLEncodedForm replaces the fields in overrides in a urlencoded body.
+func rewriteURLEncodedForm(body []byte, overrides map[string]string) ([]byte, string, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+	}
+	for field, value := range overrides {
+		values.Set(field, value)
+	}
+	return []byte(values.Encode()), "application/x-www-form-urlencoded", nil
+}
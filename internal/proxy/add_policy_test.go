@@ -0,0 +1,115 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func serveWithAddPolicy(t *testing.T, policy models.ClientAPIKeyAddPolicy, req *http.Request) (*httptest.ResponseRecorder, *http.Request, []byte) {
+	t.Helper()
+
+	h := NewHandler(nil, nil, nil, nil, nil, nil, "/")
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("api-key", "abc123")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+	ctx = context.WithValue(ctx, ClientAPIKeyContextKey, &models.ClientAPIKey{ID: 1, ClientName: "sonarr", InstanceID: 1, AddPolicy: policy})
+	ctx = context.WithValue(ctx, InstanceIDContextKey, 1)
+
+	var forwardedReq *http.Request
+	var forwarded []byte
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		forwardedReq = r
+		forwarded = append([]byte{}, body...)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	h.addPolicyMiddleware(next).ServeHTTP(rec, req.WithContext(ctx))
+	return rec, forwardedReq, forwarded
+}
+
+func TestAddPolicyMiddleware(t *testing.T) {
+	paused := true
+	ratio := 2.5
+	policy := models.ClientAPIKeyAddPolicy{
+		Category:   "tv",
+		Tags:       []string{"sonarr"},
+		SavePath:   "/data/{{ .Category }}/{{ sanitize .Client }}",
+		Paused:     &paused,
+		RatioLimit: &ratio,
+	}
+
+	t.Run("multipart", func(t *testing.T) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		require.NoError(t, mw.WriteField("category", "tv-custom"))
+		require.NoError(t, mw.WriteField("tags", "imported"))
+		fw, err := mw.CreateFormFile("torrents", "show.torrent")
+		require.NoError(t, err)
+		_, err = fw.Write([]byte("d8:announce0:e"))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/proxy/abc123/api/v2/torrents/add", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		rec, forwardedReq, forwarded := serveWithAddPolicy(t, policy, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, int64(len(forwarded)), forwardedReq.ContentLength)
+
+		forwardedReq.Body = io.NopCloser(bytes.NewReader(forwarded))
+		require.NoError(t, forwardedReq.ParseMultipartForm(1<<20))
+		form := forwardedReq.MultipartForm
+		assert.Equal(t, []string{"tv-custom"}, form.Value["category"], "the default category does not replace a requested one")
+		assert.Equal(t, []string{"imported,sonarr"}, form.Value["tags"])
+		assert.Equal(t, []string{"/data/tv-custom/sonarr"}, form.Value["savepath"])
+		assert.Equal(t, []string{"false"}, form.Value["autoTMM"])
+		assert.Equal(t, []string{"true"}, form.Value["paused"])
+		assert.Equal(t, []string{"true"}, form.Value["stopped"])
+		assert.Equal(t, []string{"2.5"}, form.Value["ratioLimit"])
+
+		require.Len(t, form.File["torrents"], 1)
+		file, err := form.File["torrents"][0].Open()
+		require.NoError(t, err)
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, "d8:announce0:e", string(content))
+	})
+
+	t.Run("forced category", func(t *testing.T) {
+		forced := models.ClientAPIKeyAddPolicy{Category: "tv", ForceCategory: true}
+		rec, _, forwarded := serveWithAddPolicy(t, forced, formRequest("torrents/add", "urls=magnet&category=movies"))
+		require.Equal(t, http.StatusOK, rec.Code)
+		values, err := url.ParseQuery(string(forwarded))
+		require.NoError(t, err)
+		assert.Equal(t, "tv", values.Get("category"))
+		assert.Equal(t, "magnet", values.Get("urls"))
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		rec, _, forwarded := serveWithAddPolicy(t, models.ClientAPIKeyAddPolicy{Category: "tv"}, formRequest("torrents/add", "urls=magnet&category=tv"))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "urls=magnet&category=tv", string(forwarded))
+
+		rec, _, forwarded = serveWithAddPolicy(t, policy, formRequest("torrents/delete", "hashes=abc"))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "hashes=abc", string(forwarded), "other endpoints are not rewritten")
+	})
+}
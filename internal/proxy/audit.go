@@ -0,0 +1,128 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+const auditRoutingContextKey contextKey = "audit_routing"
+
+// auditRouting collects the member instances a virtual instance request was
+// routed to, so its audit entries name the instances that were changed.
+type auditRouting struct {
+	instanceIDs []int
+}
+
+// recordAuditRouting notes that the request of ctx was served by instanceID.
+func recordAuditRouting(ctx context.Context, instanceID int) {
+	if routing, ok := ctx.Value(auditRoutingContextKey).(*auditRouting); ok && !slices.Contains(routing.instanceIDs, instanceID) {
+		routing.instanceIDs = append(routing.instanceIDs, instanceID)
+	}
+}
+
+// AuditRecorder receives audit entries without blocking the request.
+type AuditRecorder interface {
+	Record(entry *models.AuditEntry)
+}
+
+// SetAuditRecorder enables auditing of proxied write calls.
+func (h *Handler) SetAuditRecorder(recorder AuditRecorder) {
+	h.auditRecorder = recorder
+}
+
+// auditMiddleware records every proxied call that can change an instance,
+// including calls later rejected by the key's scope or duplicate policy.
+func (h *Handler) auditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientAPIKey := GetClientAPIKeyFromContext(r.Context())
+		endpoint := proxyEndpoint(h.stripProxyPrefix(r.URL.Path, chi.URLParam(r, "api-key")))
+		if h.auditRecorder == nil || clientAPIKey == nil || !isAuditedProxyCall(r.Method, endpoint) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		hashes := auditProxyHashes(r, endpoint)
+		routing := &auditRouting{}
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), auditRoutingContextKey, routing)))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		// Keys bound to an instance always change that instance. Virtual
+		// instance keys get one entry per member the call was routed to, or a
+		// single entry without instance when it was rejected before routing.
+		instanceIDs := routing.instanceIDs
+		if clientAPIKey.InstanceID > 0 {
+			instanceIDs = []int{clientAPIKey.InstanceID}
+		}
+		if len(instanceIDs) == 0 {
+			instanceIDs = []int{0}
+		}
+
+		for _, instanceID := range instanceIDs {
+			entry := &models.AuditEntry{
+				Source:    models.AuditSourceProxy,
+				ActorType: models.AuditActorClientKey,
+				Actor:     clientAPIKey.ClientName,
+				Method:    r.Method,
+				Endpoint:  endpoint,
+				Hashes:    hashes,
+				Status:    status,
+				Success:   status < http.StatusBadRequest,
+			}
+			if instanceID > 0 {
+				entry.InstanceID = &instanceID
+			}
+			h.auditRecorder.Record(entry)
+		}
+	})
+}
+
+// isAuditedProxyCall reports whether a call may change the instance. Logins and
+// non-API paths (the WebUI) are not audited.
+func isAuditedProxyCall(method, endpoint string) bool {
+	if endpoint == "" || strings.HasPrefix(endpoint, "auth/") {
+		return false
+	}
+	if _, ok := readOnlyEndpoints[endpoint]; ok {
+		return false
+	}
+	return method != http.MethodGet && method != http.MethodHead
+}
+
+// auditProxyHashes returns the torrents a call targets, or the infohashes of
+// the torrents it adds.
+func auditProxyHashes(r *http.Request, endpoint string) []string {
+	if endpoint == "torrents/add" {
+		files, urls, err := readAddItems(r)
+		if err != nil {
+			return nil
+		}
+		var hashes []string
+		for _, item := range append(files, urls...) {
+			if len(item.hashes) > 0 {
+				hashes = append(hashes, item.hashes[0])
+			}
+		}
+		return normalizeHashes(hashes)
+	}
+
+	form, err := peekForm(r)
+	if err != nil {
+		return nil
+	}
+	return normalizeHashes(append(strings.Split(form.Get("hashes"), "|"), form.Get("hash")))
+}
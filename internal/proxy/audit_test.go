@@ -0,0 +1,101 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+type recordedAudit struct {
+	entries []*models.AuditEntry
+}
+
+func (r *recordedAudit) Record(entry *models.AuditEntry) {
+	r.entries = append(r.entries, entry)
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	recorder := &recordedAudit{}
+	h := NewHandler(nil, nil, nil, nil, nil, nil, "/")
+	h.SetAuditRecorder(recorder)
+
+	serve := func(req *http.Request) string {
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("api-key", "abc123")
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+		ctx = context.WithValue(ctx, ClientAPIKeyContextKey, &models.ClientAPIKey{ID: 1, ClientName: "sonarr", InstanceID: 2})
+
+		var forwarded string
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			forwarded = string(body)
+			w.WriteHeader(http.StatusForbidden)
+		})
+		h.auditMiddleware(next).ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+		return forwarded
+	}
+
+	forwarded := serve(formRequest("torrents/delete", "hashes=abc|def&deleteFiles=true"))
+	assert.Equal(t, "hashes=abc|def&deleteFiles=true", forwarded)
+	serve(httptest.NewRequest(http.MethodGet, "/proxy/abc123/api/v2/torrents/info", nil))
+	serve(formRequest("auth/login", "username=admin&password=secret"))
+
+	require.Len(t, recorder.entries, 1, "reads and logins are not audited")
+	entry := recorder.entries[0]
+	assert.Equal(t, models.AuditSourceProxy, entry.Source)
+	assert.Equal(t, models.AuditActorClientKey, entry.ActorType)
+	assert.Equal(t, "sonarr", entry.Actor)
+	assert.Equal(t, "torrents/delete", entry.Endpoint)
+	require.NotNil(t, entry.InstanceID)
+	assert.Equal(t, 2, *entry.InstanceID)
+	assert.Equal(t, []string{"ABC", "DEF"}, entry.Hashes)
+	assert.Equal(t, http.StatusForbidden, entry.Status)
+	assert.False(t, entry.Success)
+}
+
+func TestAuditMiddlewareVirtualInstance(t *testing.T) {
+	recorder := &recordedAudit{}
+	h := NewHandler(nil, nil, nil, nil, nil, nil, "/")
+	h.SetAuditRecorder(recorder)
+
+	virtualInstanceID := 5
+	serve := func(req *http.Request, members ...int) {
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("api-key", "abc123")
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+		ctx = context.WithValue(ctx, ClientAPIKeyContextKey, &models.ClientAPIKey{ID: 1, ClientName: "sonarr", VirtualInstanceID: &virtualInstanceID})
+
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, instanceID := range members {
+				recordAuditRouting(r.Context(), instanceID)
+			}
+			w.WriteHeader(http.StatusOK)
+		})
+		h.auditMiddleware(next).ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+	}
+
+	serve(formRequest("torrents/pause", "hashes=abc|def"), 3, 4, 3)
+	require.Len(t, recorder.entries, 2, "one entry per member the call was routed to")
+	for i, want := range []int{3, 4} {
+		require.NotNil(t, recorder.entries[i].InstanceID)
+		assert.Equal(t, want, *recorder.entries[i].InstanceID)
+		assert.Equal(t, []string{"ABC", "DEF"}, recorder.entries[i].Hashes)
+	}
+
+	recorder.entries = nil
+	serve(formRequest("app/setPreferences", "json={}"))
+	require.Len(t, recorder.entries, 1)
+	assert.Nil(t, recorder.entries[0].InstanceID, "calls rejected before routing have no instance")
+}
@@ -0,0 +1,452 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/autobrr/go-torrent/metainfo"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/crossseed"
+)
+
+const duplicateDecisionHeader = "X-Qui-Duplicate"
+
+// CrossSeedInjector injects a torrent onto instances that already hold its data.
+type CrossSeedInjector interface {
+	CrossSeed(ctx context.Context, req *crossseed.CrossSeedRequest) (*crossseed.CrossSeedResponse, error)
+}
+
+// SetCrossSeedInjector enables the cross_seed duplicate policy.
+func (h *Handler) SetCrossSeedInjector(injector CrossSeedInjector) {
+	h.crossSeedInjector = injector
+}
+
+// addItem is one torrent of a torrents/add request.
+type addItem struct {
+	// data is the .torrent file; empty for URLs.
+	data []byte
+	// index is the position among the request's files or URLs.
+	index int
+	name  string
+	// hashes are the lowercase infohashes (v1 and v2), empty when unknown
+	// (e.g. an http URL qBittorrent downloads itself).
+	hashes []string
+}
+
+// addDuplicate is an add item that already exists on an instance.
+type addDuplicate struct {
+	item         addItem
+	instanceID   int
+	instanceName string
+}
+
+// duplicateGuardMiddleware checks torrents/add requests against every instance
+// and applies the client API key's duplicate policy.
+func (h *Handler) duplicateGuardMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientAPIKey := GetClientAPIKeyFromContext(r.Context())
+		if clientAPIKey == nil || clientAPIKey.DuplicatePolicy == models.DuplicatePolicyOff || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if proxyEndpoint(h.stripProxyPrefix(r.URL.Path, chi.URLParam(r, "api-key"))) != "torrents/add" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		files, urls, err := readAddItems(r)
+		if err != nil {
+			log.Warn().Err(err).Int("keyId", clientAPIKey.ID).Msg("Failed to read torrents/add request for duplicate check")
+			http.Error(w, "Invalid form data", http.StatusBadRequest)
+			return
+		}
+
+		duplicates, err := h.findDuplicates(r.Context(), append(files, urls...))
+		if err != nil {
+			// The check is a safeguard; an unavailable instance must not block adds.
+			log.Warn().Err(err).Int("keyId", clientAPIKey.ID).Msg("Duplicate check failed, forwarding torrents/add unchanged")
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(duplicates) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.applyDuplicatePolicy(w, r, next, clientAPIKey, duplicates, len(files)+len(urls))
+	})
+}
+
+func (h *Handler) applyDuplicatePolicy(w http.ResponseWriter, r *http.Request, next http.Handler, clientAPIKey *models.ClientAPIKey, duplicates []addDuplicate, total int) {
+	logDuplicates(clientAPIKey, duplicates, string(clientAPIKey.DuplicatePolicy))
+
+	if clientAPIKey.DuplicatePolicy == models.DuplicatePolicyAllow {
+		w.Header().Set(duplicateDecisionHeader, fmt.Sprintf("allowed %d duplicate(s)", len(duplicates)))
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	decision := fmt.Sprintf("rejected %d duplicate(s)", len(duplicates))
+	injected := 0
+	if clientAPIKey.DuplicatePolicy == models.DuplicatePolicyCrossSeed {
+		injected = h.injectDuplicates(r, clientAPIKey, duplicates)
+		decision = fmt.Sprintf("cross-seeded %d of %d duplicate(s)", injected, len(duplicates))
+	}
+	w.Header().Set(duplicateDecisionHeader, decision)
+
+	if len(duplicates) == total {
+		// qBittorrent answers "Ok." when at least one torrent was added.
+		writeAddResult(w, injected > 0)
+		return
+	}
+
+	if err := dropAddItems(r, duplicates); err != nil {
+		log.Warn().Err(err).Int("keyId", clientAPIKey.ID).Msg("Failed to remove duplicates from torrents/add request")
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+	next.ServeHTTP(w, r)
+}
+
+// injectDuplicates hands duplicate .torrent files to cross-seed injection on the
+// key's instances and returns how many were added. Magnets have no file list
+// to match, so they are always rejected.
+func (h *Handler) injectDuplicates(r *http.Request, clientAPIKey *models.ClientAPIKey, duplicates []addDuplicate) int {
+	if h.crossSeedInjector == nil {
+		log.Warn().Int("keyId", clientAPIKey.ID).Msg("Cross-seed is not available, rejecting duplicates instead")
+		return 0
+	}
+
+	form, err := peekForm(r)
+	if err != nil {
+		return 0
+	}
+	var tags []string
+	for tag := range strings.SplitSeq(form.Get("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	targets := []int{GetInstanceIDFromContext(r.Context())}
+	if virtual := getVirtualInstance(r.Context()); virtual != nil {
+		targets = virtual.members
+	}
+
+	skipIfExists := true
+	injected := 0
+	for _, duplicate := range duplicates {
+		if len(duplicate.item.data) == 0 {
+			continue
+		}
+		response, err := h.crossSeedInjector.CrossSeed(r.Context(), &crossseed.CrossSeedRequest{
+			TorrentData:       base64.StdEncoding.EncodeToString(duplicate.item.data),
+			TargetInstanceIDs: targets,
+			Category:          form.Get("category"),
+			Tags:              tags,
+			SkipIfExists:      &skipIfExists,
+		})
+		if err != nil {
+			log.Warn().Err(err).Int("keyId", clientAPIKey.ID).Str("torrent", duplicate.item.name).Msg("Cross-seed injection of duplicate failed")
+			continue
+		}
+		if response.Success {
+			injected++
+		}
+		log.Info().
+			Int("keyId", clientAPIKey.ID).
+			Str("torrent", duplicate.item.name).
+			Bool("injected", response.Success).
+			Msg("Redirected duplicate torrents/add to cross-seed injection")
+	}
+	return injected
+}
+
+// findDuplicates returns the items that already exist on an active instance.
+func (h *Handler) findDuplicates(ctx context.Context, items []addItem) ([]addDuplicate, error) {
+	if h.syncManager == nil || h.instanceStore == nil {
+		return nil, errors.New("sync manager not available")
+	}
+	instances, err := h.instanceStore.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var duplicates []addDuplicate
+	for _, item := range items {
+		if len(item.hashes) == 0 {
+			continue
+		}
+		for _, instance := range instances {
+			if instance == nil || !instance.IsActive {
+				continue
+			}
+			_, exists, err := h.syncManager.HasTorrentByAnyHash(ctx, instance.ID, item.hashes)
+			if err != nil {
+				return nil, fmt.Errorf("instance %d: %w", instance.ID, err)
+			}
+			if exists {
+				duplicates = append(duplicates, addDuplicate{item: item, instanceID: instance.ID, instanceName: instance.Name})
+				break
+			}
+		}
+	}
+	return duplicates, nil
+}
+
+func logDuplicates(clientAPIKey *models.ClientAPIKey, duplicates []addDuplicate, policy string) {
+	for _, duplicate := range duplicates {
+		log.Warn().
+			Int("keyId", clientAPIKey.ID).
+			Str("client", clientAPIKey.ClientName).
+			Str("torrent", duplicate.item.name).
+			Strs("hashes", duplicate.item.hashes).
+			Int("existingInstanceId", duplicate.instanceID).
+			Str("existingInstance", duplicate.instanceName).
+			Str("policy", policy).
+			Msg("Proxy torrents/add targets a torrent that already exists")
+	}
+}
+
+func writeAddResult(w http.ResponseWriter, ok bool) {
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	if ok {
+		_, _ = io.WriteString(w, "Ok.")
+		return
+	}
+	_, _ = io.WriteString(w, "Fails.")
+}
+
+// readAddItems returns the .torrent files and URLs of a torrents/add request
+// without consuming it.
+func readAddItems(r *http.Request) (files, urls []addItem, err error) {
+	body, err := bufferRequestBody(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+	}
+	defer restoreBody(r, body)
+
+	clone := r.Clone(r.Context())
+	restoreBody(clone, body)
+	if strings.HasPrefix(clone.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := clone.ParseMultipartForm(1 << 20); err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+		}
+		defer func() { _ = clone.MultipartForm.RemoveAll() }()
+
+		for i, header := range clone.MultipartForm.File["torrents"] {
+			data, err := readFormFile(header)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+			}
+			files = append(files, torrentFileItem(i, header.Filename, data))
+		}
+	} else if err := clone.ParseForm(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+	}
+
+	for i, raw := range splitAddURLs(clone.Form.Get("urls")) {
+		urls = append(urls, addItem{index: i, name: raw, hashes: magnetHashes(raw)})
+	}
+	return files, urls, nil
+}
+
+func readFormFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+func torrentFileItem(index int, filename string, data []byte) addItem {
+	item := addItem{data: data, index: index, name: filename}
+	mi, err := metainfo.Load(bytes.NewReader(data))
+	if err != nil {
+		// qBittorrent rejects it anyway; leave the item unchecked.
+		return item
+	}
+	item.hashes = []string{strings.ToLower(mi.HashInfoBytes().HexString())}
+	if info, err := mi.UnmarshalInfo(); err == nil {
+		if info.Name != "" {
+			item.name = info.Name
+		}
+		if info.HasV2() {
+			item.hashes = append(item.hashes, strings.ToLower(metainfo.HashV2Bytes([]byte(mi.InfoBytes)).HexString()))
+		}
+	}
+	return item
+}
+
+// splitAddURLs splits the urls field, which qBittorrent separates by newlines.
+func splitAddURLs(value string) []string {
+	var urls []string
+	for line := range strings.SplitSeq(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			urls = append(urls, line)
+		}
+	}
+	return urls
+}
+
+// magnetHashes returns the lowercase hex infohashes of a magnet link, or nil.
+// Both hex and base32 btih hashes and SHA-256 btmh hashes are understood.
+func magnetHashes(raw string) []string {
+	parsed, err := url.Parse(raw)
+	if err != nil || !strings.EqualFold(parsed.Scheme, "magnet") {
+		return nil
+	}
+
+	var hashes []string
+	for _, xt := range parsed.Query()["xt"] {
+		lower := strings.ToLower(xt)
+		switch {
+		case strings.HasPrefix(lower, "urn:btih:"):
+			value := xt[len("urn:btih:"):]
+			switch len(value) {
+			case 40:
+				if _, err := hex.DecodeString(value); err == nil {
+					hashes = append(hashes, strings.ToLower(value))
+				}
+			case 32:
+				if decoded, err := base32.StdEncoding.DecodeString(strings.ToUpper(value)); err == nil {
+					hashes = append(hashes, hex.EncodeToString(decoded))
+				}
+			}
+		case strings.HasPrefix(lower, "urn:btmh:1220"):
+			// 0x12 0x20 is the multihash prefix for a 32 byte SHA-256 digest.
+			value := lower[len("urn:btmh:1220"):]
+			if _, err := hex.DecodeString(value); err == nil && len(value) == 64 {
+				hashes = append(hashes, value)
+			}
+		}
+	}
+	return hashes
+}
+
+// dropAddItems removes the duplicate items from the torrents/add request in r.
+func dropAddItems(r *http.Request, duplicates []addDuplicate) error {
+	dropFiles := make(map[int]bool)
+	dropURLs := make(map[int]bool)
+	for _, duplicate := range duplicates {
+		if len(duplicate.item.data) > 0 {
+			dropFiles[duplicate.item.index] = true
+		} else {
+			dropURLs[duplicate.item.index] = true
+		}
+	}
+
+	form, err := peekForm(r)
+	if err != nil {
+		return err
+	}
+	var keptURLs []string
+	for i, raw := range splitAddURLs(form.Get("urls")) {
+		if !dropURLs[i] {
+			keptURLs = append(keptURLs, raw)
+		}
+	}
+	urls := strings.Join(keptURLs, "\n")
+
+	if query := r.URL.Query(); query.Has("urls") {
+		query.Del("urls")
+		r.URL.RawQuery = query.Encode()
+	}
+
+	body, err := bufferRequestBody(r)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var rewritten []byte
+	var contentType string
+	if mediaType == "multipart/form-data" {
+		rewritten, contentType, err = dropMultipartItems(body, params["boundary"], dropFiles, urls)
+	} else {
+		var values url.Values
+		values, err = url.ParseQuery(string(body))
+		if err == nil {
+			values.Del("urls")
+			if urls != "" {
+				values.Set("urls", urls)
+			}
+			rewritten, contentType = []byte(values.Encode()), "application/x-www-form-urlencoded"
+		}
+	}
+	if err != nil {
+		restoreBody(r, body)
+		return fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+	}
+
+	r.Header.Set("Content-Type", contentType)
+	r.Header.Del("Content-Length")
+	restoreBody(r, rewritten)
+	return nil
+}
+
+// dropMultipartItems copies a multipart torrents/add body without the dropped
+// torrent files and with urls replacing the urls field.
+func dropMultipartItems(body []byte, boundary string, dropFiles map[int]bool, urls string) ([]byte, string, error) {
+	if boundary == "" {
+		return nil, "", errors.New("missing multipart boundary")
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	fileIndex := 0
+	for {
+		part, err := reader.NextRawPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if part.FormName() == "urls" && part.FileName() == "" {
+			continue
+		}
+		if part.FormName() == "torrents" && part.FileName() != "" {
+			drop := dropFiles[fileIndex]
+			fileIndex++
+			if drop {
+				continue
+			}
+		}
+		dst, err := writer.CreatePart(part.Header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(dst, part); err != nil {
+			return nil, "", err
+		}
+	}
+	if urls != "" {
+		if err := writer.WriteField("urls", urls); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package proxy
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMagnetHashes(t *testing.T) {
+	const v1 = "c12fe1c06bba254a9dc9f519b335aa7c1367a88a"
+	v2 := strings.Repeat("ab", 32)
+
+	assert.Equal(t, []string{v1}, magnetHashes("magnet:?xt=urn:btih:"+strings.ToUpper(v1)+"&dn=x"))
+	assert.Equal(t, []string{v1}, magnetHashes("magnet:?xt=urn:btih:YEX6DQDLXISUVHOJ6UM3GNNKPQJWPKEK"))
+	assert.Equal(t, []string{v1, v2}, magnetHashes("magnet:?xt=urn:btih:"+v1+"&xt=urn:btmh:1220"+v2))
+	assert.Empty(t, magnetHashes("https://tracker.example/download/1.torrent"))
+	assert.Empty(t, magnetHashes("magnet:?xt=urn:btih:nothex"))
+}
+
+func TestTorrentFileItem(t *testing.T) {
+	info := "d6:lengthi1e4:name8:show.mkv12:piece lengthi16384e6:pieces20:" + strings.Repeat("x", 20) + "e"
+	sum := sha1.Sum([]byte(info))
+
+	item := torrentFileItem(0, "upload.torrent", []byte("d4:info"+info+"e"))
+	assert.Equal(t, "show.mkv", item.name)
+	assert.Equal(t, []string{hex.EncodeToString(sum[:])}, item.hashes)
+
+	item = torrentFileItem(1, "broken.torrent", []byte("not bencode"))
+	assert.Equal(t, "broken.torrent", item.name)
+	assert.Empty(t, item.hashes)
+}
+
+func TestDropAddItems(t *testing.T) {
+	t.Run("urlencoded", func(t *testing.T) {
+		req := formRequest("torrents/add", "urls="+url.QueryEscape("magnet:?a\nmagnet:?b\nmagnet:?c")+"&category=tv")
+		duplicates := []addDuplicate{{item: addItem{index: 0}}, {item: addItem{index: 2}}}
+		require.NoError(t, dropAddItems(req, duplicates))
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		values, err := url.ParseQuery(string(body))
+		require.NoError(t, err)
+		assert.Equal(t, "magnet:?b", values.Get("urls"))
+		assert.Equal(t, "tv", values.Get("category"))
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		require.NoError(t, mw.WriteField("urls", "magnet:?a"))
+		for _, name := range []string{"one.torrent", "two.torrent"} {
+			fw, err := mw.CreateFormFile("torrents", name)
+			require.NoError(t, err)
+			_, err = fw.Write([]byte(name))
+			require.NoError(t, err)
+		}
+		require.NoError(t, mw.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/proxy/abc123/api/v2/torrents/add", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		duplicates := []addDuplicate{{item: addItem{index: 0, data: []byte("one.torrent")}}, {item: addItem{index: 0}}}
+		require.NoError(t, dropAddItems(req, duplicates))
+
+		require.NoError(t, req.ParseMultipartForm(1<<20))
+		assert.Empty(t, req.MultipartForm.Value["urls"])
+		require.Len(t, req.MultipartForm.File["torrents"], 1)
+		assert.Equal(t, "two.torrent", req.MultipartForm.File["torrents"][0].Filename)
+	})
+}
+
+func TestWriteAddResult(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeAddResult(rec, false)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Fails.", rec.Body.String())
+}
@@ -46,6 +46,11 @@ type Handler struct {
 	reannounceService *reannounce.Service
 	bufferPool        *BufferPool
 	proxy             *httputil.ReverseProxy
+
+	virtualInstanceStore *models.VirtualInstanceStore
+	virtual              virtualInstanceState
+	crossSeedInjector    CrossSeedInjector
+	auditRecorder        AuditRecorder
 }
 
 const (
@@ -376,21 +381,31 @@ func (crw *capturingResponseWriter) Write(b []byte) (int, error) {
 // prepareProxyContextMiddleware adds proxy context to the request
 func (h *Handler) prepareProxyContextMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		proxyCtx, err := h.prepareProxyContext(r)
+		r, err := h.withProxyContext(r)
 		if err != nil {
 			h.writeProxyError(w)
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), proxyContextKey, proxyCtx)
-		if isHTTPSRequest(r) {
-			ctx = context.WithValue(ctx, clientHTTPSContextKey, true)
-		}
-
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r)
 	})
 }
 
+// withProxyContext returns r with the proxy context for its instance attached.
+func (h *Handler) withProxyContext(r *http.Request) (*http.Request, error) {
+	proxyCtx, err := h.prepareProxyContext(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.WithValue(r.Context(), proxyContextKey, proxyCtx)
+	if isHTTPSRequest(r) {
+		ctx = context.WithValue(ctx, clientHTTPSContextKey, true)
+	}
+
+	return r.WithContext(ctx), nil
+}
+
 // Routes sets up the proxy routes
 func (h *Handler) Routes(r chi.Router) {
 	// Proxy route with API key parameter
@@ -399,6 +414,17 @@ func (h *Handler) Routes(r chi.Router) {
 
 	// Scoped proxy routes retain API key middleware and prepare proxy context
 	proxyRouter.Route(proxyRoute, func(pr chi.Router) {
+		// Audit write calls, including ones rejected by the middlewares below
+		pr.Use(h.auditMiddleware)
+		// Resolve virtual instances, rewrite torrents/add with the key's add policy,
+		// then reject requests outside the key's scope before anything else happens
+		pr.Use(h.virtualInstanceMiddleware)
+		pr.Use(h.addPolicyMiddleware)
+		pr.Use(h.scopeMiddleware)
+		// Keep torrents that already exist on an instance from being downloaded again
+		pr.Use(h.duplicateGuardMiddleware)
+		// Virtual instances are served across their members instead of one instance
+		pr.Use(h.serveVirtualInstanceMiddleware)
 		// Apply proxy context middleware (adds instance info to context)
 		pr.Use(h.prepareProxyContextMiddleware)
 
@@ -1231,6 +1257,12 @@ func (h *Handler) handleTorrentsInfo(w http.ResponseWriter, r *http.Request) {
 		Int("hashCount", uniqueHashCount).
 		Msg("Handling torrents/info request via qui sync manager")
 
+	// Virtual instances merge their members' torrents
+	if virtual := getVirtualInstance(ctx); virtual != nil {
+		h.writeVirtualTorrentsInfo(ctx, w, virtual, limit, offset, sort, order, filters)
+		return
+	}
+
 	// Use qui's sync manager
 	response, err := h.syncManager.GetTorrentsWithFilters(ctx, instanceID, limit, offset, sort, order, "", filters)
 	if err != nil {
@@ -0,0 +1,288 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+const scopeDeniedReasonHeader = "X-Qui-Scope-Denied"
+
+var errInvalidProxyForm = errors.New("invalid form data")
+
+// scopeExemptEndpoints are always allowed so clients can connect and detect the API version.
+var scopeExemptEndpoints = map[string]struct{}{
+	"auth/login":          {},
+	"auth/logout":         {},
+	"app/version":         {},
+	"app/webapiVersion":   {},
+	"app/buildInfo":       {},
+	"torrents/categories": {},
+	"torrents/tags":       {},
+}
+
+// readOnlyEndpoints are the Web API endpoints that don't change anything.
+var readOnlyEndpoints = map[string]struct{}{
+	"app/version":                     {},
+	"app/webapiVersion":               {},
+	"app/buildInfo":                   {},
+	"app/preferences":                 {},
+	"app/defaultSavePath":             {},
+	"app/networkInterfaceList":        {},
+	"app/networkInterfaceAddressList": {},
+	"log/main":                        {},
+	"log/peers":                       {},
+	"sync/maindata":                   {},
+	"sync/torrentPeers":               {},
+	"transfer/info":                   {},
+	"transfer/speedLimitsMode":        {},
+	"transfer/downloadLimit":          {},
+	"transfer/uploadLimit":            {},
+	"torrents/info":                   {},
+	"torrents/count":                  {},
+	"torrents/properties":             {},
+	"torrents/trackers":               {},
+	"torrents/webseeds":               {},
+	"torrents/files":                  {},
+	"torrents/pieceStates":            {},
+	"torrents/pieceHashes":            {},
+	"torrents/downloadLimit":          {},
+	"torrents/uploadLimit":            {},
+	"torrents/categories":             {},
+	"torrents/tags":                   {},
+	"torrents/export":                 {},
+	"torrents/search":                 {},
+	"torrents/mediainfo":              {},
+	"search/status":                   {},
+	"search/results":                  {},
+	"search/plugins":                  {},
+	"rss/items":                       {},
+	"rss/rules":                       {},
+	"rss/matchingArticles":            {},
+}
+
+// torrentScopedEndpoints are the write endpoints a key restricted to categories
+// or tags may call: each one only touches the torrents named in hashes/hash.
+// Every other write (preferences, categories, tags, transfer limits, RSS,
+// search, ...) affects the whole instance and is denied for such keys.
+var torrentScopedEndpoints = map[string]struct{}{
+	"torrents/stop":                     {},
+	"torrents/start":                    {},
+	"torrents/pause":                    {},
+	"torrents/resume":                   {},
+	"torrents/delete":                   {},
+	"torrents/recheck":                  {},
+	"torrents/reannounce":               {},
+	"torrents/addTrackers":              {},
+	"torrents/editTracker":              {},
+	"torrents/removeTrackers":           {},
+	"torrents/addPeers":                 {},
+	"torrents/addWebSeeds":              {},
+	"torrents/editWebSeed":              {},
+	"torrents/removeWebSeeds":           {},
+	"torrents/increasePrio":             {},
+	"torrents/decreasePrio":             {},
+	"torrents/topPrio":                  {},
+	"torrents/bottomPrio":               {},
+	"torrents/filePrio":                 {},
+	"torrents/setDownloadLimit":         {},
+	"torrents/setUploadLimit":           {},
+	"torrents/setShareLimits":           {},
+	"torrents/setLocation":              {},
+	"torrents/setSavePath":              {},
+	"torrents/setDownloadPath":          {},
+	"torrents/rename":                   {},
+	"torrents/renameFile":               {},
+	"torrents/renameFolder":             {},
+	"torrents/setComment":               {},
+	"torrents/setCategory":              {},
+	"torrents/addTags":                  {},
+	"torrents/removeTags":               {},
+	"torrents/setTags":                  {},
+	"torrents/setAutoManagement":        {},
+	"torrents/toggleSequentialDownload": {},
+	"torrents/toggleFirstLastPiecePrio": {},
+	"torrents/setForceStart":            {},
+	"torrents/setSuperSeeding":          {},
+}
+
+// proxyEndpoint returns the Web API endpoint of a proxy path relative to /api/v2/,
+// or "" when the path is not a Web API call (e.g. the qBittorrent WebUI).
+func proxyEndpoint(strippedPath string) string {
+	endpoint, ok := strings.CutPrefix(strippedPath, "/api/v2/")
+	if !ok {
+		return ""
+	}
+	return strings.Trim(endpoint, "/")
+}
+
+// scopeMiddleware rejects requests outside the client API key's scope before they
+// are forwarded or answered from qui's cache.
+func (h *Handler) scopeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientAPIKey := GetClientAPIKeyFromContext(r.Context())
+		if clientAPIKey == nil || clientAPIKey.Scope.IsZero() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		endpoint := proxyEndpoint(h.stripProxyPrefix(r.URL.Path, chi.URLParam(r, "api-key")))
+		reason, err := h.checkScope(r, clientAPIKey.Scope, endpoint)
+		if err != nil {
+			log.Warn().Err(err).Int("keyId", clientAPIKey.ID).Str("endpoint", endpoint).Msg("Failed to check client API key scope")
+			if errors.Is(err, errInvalidProxyForm) {
+				http.Error(w, "Invalid form data", http.StatusBadRequest)
+				return
+			}
+			h.writeProxyError(w)
+			return
+		}
+		if reason != "" {
+			log.Warn().
+				Int("keyId", clientAPIKey.ID).
+				Str("client", clientAPIKey.ClientName).
+				Str("endpoint", endpoint).
+				Str("reason", reason).
+				Msg("Client API key scope denied proxy request")
+			writeScopeDenied(w, reason)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeScopeDenied answers like qBittorrent does for forbidden requests; the
+// reason is only exposed in a header so clients see a plain 403.
+func writeScopeDenied(w http.ResponseWriter, reason string) {
+	w.Header().Set(scopeDeniedReasonHeader, reason)
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.WriteHeader(http.StatusForbidden)
+	_, _ = io.WriteString(w, "Forbidden")
+}
+
+// checkScope returns why scope denies the request, or "" when it is allowed.
+func (h *Handler) checkScope(r *http.Request, scope models.ClientAPIKeyScope, endpoint string) (string, error) {
+	if endpoint == "" {
+		return "only Web API endpoints are allowed", nil
+	}
+	if _, ok := scopeExemptEndpoints[endpoint]; ok {
+		return "", nil
+	}
+	if !scope.AllowsEndpoint(endpoint) {
+		return "endpoint not allowed", nil
+	}
+	if _, ok := readOnlyEndpoints[endpoint]; ok {
+		return "", nil
+	}
+	if scope.ReadOnly {
+		return "read-only key", nil
+	}
+	if !scope.ForbidDeleteFiles && !scope.RestrictsTorrents() {
+		return "", nil
+	}
+	if scope.RestrictsTorrents() && endpoint != "torrents/add" {
+		if _, ok := torrentScopedEndpoints[endpoint]; !ok {
+			return "endpoint not allowed for category or tag restricted keys", nil
+		}
+	}
+
+	form, err := peekForm(r)
+	if err != nil {
+		return "", err
+	}
+
+	if scope.ForbidDeleteFiles && endpoint == "torrents/delete" {
+		// qBittorrent reads deleteFiles as a case-insensitive "true". A
+		// repeated parameter may resolve differently there than here.
+		values := form["deleteFiles"]
+		if len(values) > 1 {
+			return "deleteFiles must not be repeated", nil
+		}
+		if len(values) == 1 && strings.EqualFold(strings.TrimSpace(values[0]), "true") {
+			return "deleting files is not allowed", nil
+		}
+	}
+	if !scope.RestrictsTorrents() {
+		return "", nil
+	}
+
+	switch endpoint {
+	case "torrents/add":
+		if !scope.AllowsTorrent(form.Get("category"), form.Get("tags")) {
+			return "category or tags not allowed", nil
+		}
+		return "", nil
+	case "torrents/setCategory":
+		if !scope.AllowsCategory(form.Get("category")) {
+			return "category not allowed", nil
+		}
+	}
+
+	hashes := normalizeHashes(append(strings.Split(form.Get("hashes"), "|"), form.Get("hash")))
+	if len(hashes) == 0 {
+		return "no torrents targeted", nil
+	}
+	return h.checkScopeTorrents(r.Context(), scope, hashes)
+}
+
+// checkScopeTorrents verifies every targeted torrent is within the scope's categories/tags.
+func (h *Handler) checkScopeTorrents(ctx context.Context, scope models.ClientAPIKeyScope, hashes []string) (string, error) {
+	for _, hash := range hashes {
+		if hash == "ALL" {
+			return "targeting all torrents is not allowed", nil
+		}
+	}
+	if h.syncManager == nil {
+		return "torrents could not be verified", nil
+	}
+
+	torrents, err := h.keyTorrents(ctx)
+	if err != nil {
+		return "", err
+	}
+	allowed := make(map[string]bool, len(torrents))
+	for _, torrent := range torrents {
+		allowed[strings.ToUpper(torrent.Hash)] = scope.AllowsTorrent(torrent.Category, torrent.Tags)
+	}
+	for _, hash := range hashes {
+		if !allowed[hash] {
+			return "torrent outside allowed categories or tags", nil
+		}
+	}
+	return "", nil
+}
+
+// peekForm parses the request's query and form body without consuming it,
+// so the request can still be forwarded unchanged.
+func peekForm(r *http.Request) (url.Values, error) {
+	body, err := bufferRequestBody(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+	}
+	defer restoreBody(r, body)
+
+	clone := r.Clone(r.Context())
+	restoreBody(clone, body)
+	if strings.HasPrefix(clone.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := clone.ParseMultipartForm(1 << 20); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+		}
+		defer func() { _ = clone.MultipartForm.RemoveAll() }()
+	} else if err := clone.ParseForm(); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidProxyForm, err)
+	}
+	return clone.Form, nil
+}
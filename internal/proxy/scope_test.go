@@ -0,0 +1,156 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func serveScoped(t *testing.T, scope models.ClientAPIKeyScope, req *http.Request) (*httptest.ResponseRecorder, []byte) {
+	t.Helper()
+
+	h := NewHandler(nil, nil, nil, nil, nil, nil, "/")
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("api-key", "abc123")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+	ctx = context.WithValue(ctx, ClientAPIKeyContextKey, &models.ClientAPIKey{ID: 1, ClientName: "sonarr", InstanceID: 1, Scope: scope})
+	ctx = context.WithValue(ctx, InstanceIDContextKey, 1)
+
+	var forwarded []byte
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		forwarded = append([]byte{}, body...)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	h.scopeMiddleware(next).ServeHTTP(rec, req.WithContext(ctx))
+	return rec, forwarded
+}
+
+func formRequest(path, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/proxy/abc123/api/v2/"+path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestScopeMiddleware(t *testing.T) {
+	t.Run("full access", func(t *testing.T) {
+		rec, _ := serveScoped(t, models.ClientAPIKeyScope{}, formRequest("torrents/delete", "hashes=all&deleteFiles=true"))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("read only", func(t *testing.T) {
+		scope := models.ClientAPIKeyScope{ReadOnly: true}
+		rec, _ := serveScoped(t, scope, httptest.NewRequest(http.MethodGet, "/proxy/abc123/api/v2/torrents/info", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		rec, _ = serveScoped(t, scope, formRequest("auth/login", "username=a&password=b"))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec, _ = serveScoped(t, scope, formRequest("torrents/pause", "hashes=abc"))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, "Forbidden", rec.Body.String())
+		assert.Equal(t, "read-only key", rec.Header().Get(scopeDeniedReasonHeader))
+
+		rec, _ = serveScoped(t, scope, httptest.NewRequest(http.MethodGet, "/proxy/abc123/", nil))
+		assert.Equal(t, http.StatusForbidden, rec.Code, "the WebUI is not reachable with a scoped key")
+	})
+
+	t.Run("allowed endpoints", func(t *testing.T) {
+		scope := models.ClientAPIKeyScope{AllowedEndpoints: []string{"torrents/add", "sync/*"}}
+		rec, _ := serveScoped(t, scope, formRequest("torrents/add", "urls=magnet"))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		rec, _ = serveScoped(t, scope, httptest.NewRequest(http.MethodGet, "/proxy/abc123/api/v2/sync/maindata", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		rec, _ = serveScoped(t, scope, httptest.NewRequest(http.MethodGet, "/proxy/abc123/api/v2/app/webapiVersion", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		rec, _ = serveScoped(t, scope, formRequest("torrents/delete", "hashes=abc"))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("forbid deleting files", func(t *testing.T) {
+		scope := models.ClientAPIKeyScope{ForbidDeleteFiles: true}
+		rec, _ := serveScoped(t, scope, formRequest("torrents/delete", "hashes=abc&deleteFiles=true"))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		rec, forwarded := serveScoped(t, scope, formRequest("torrents/delete", "hashes=abc&deleteFiles=false"))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "hashes=abc&deleteFiles=false", string(forwarded), "the body is forwarded unchanged")
+
+		rec, _ = serveScoped(t, scope, formRequest("torrents/delete", "hashes=abc&deleteFiles=tRue"))
+		assert.Equal(t, http.StatusForbidden, rec.Code, "qBittorrent reads the value case-insensitively")
+
+		rec, _ = serveScoped(t, scope, formRequest("torrents/delete", "hashes=abc&deleteFiles=false&deleteFiles=true"))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, "deleteFiles must not be repeated", rec.Header().Get(scopeDeniedReasonHeader))
+
+		req := formRequest("torrents/delete", "hashes=abc&deleteFiles=false")
+		req.URL.RawQuery = "deleteFiles=TRUE"
+		rec, _ = serveScoped(t, scope, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code, "query and body values are checked together")
+	})
+
+	t.Run("categories", func(t *testing.T) {
+		scope := models.ClientAPIKeyScope{Categories: []string{"tv"}}
+
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		require.NoError(t, mw.WriteField("urls", "magnet:?xt=urn:btih:abc"))
+		require.NoError(t, mw.WriteField("category", "tv"))
+		require.NoError(t, mw.Close())
+		payload := body.String()
+
+		req := httptest.NewRequest(http.MethodPost, "/proxy/abc123/api/v2/torrents/add", strings.NewReader(payload))
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		rec, forwarded := serveScoped(t, scope, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, payload, string(forwarded))
+
+		rec, _ = serveScoped(t, scope, formRequest("torrents/add", "urls=magnet&category=movies"))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		rec, _ = serveScoped(t, scope, formRequest("torrents/setCategory", "hashes=abc&category=movies"))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		rec, _ = serveScoped(t, scope, formRequest("torrents/delete", "hashes=all"))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, "targeting all torrents is not allowed", rec.Header().Get(scopeDeniedReasonHeader))
+		rec, _ = serveScoped(t, scope, formRequest("torrents/addTags", "tags=tv"))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, "no torrents targeted", rec.Header().Get(scopeDeniedReasonHeader))
+	})
+
+	t.Run("categories deny instance-wide writes", func(t *testing.T) {
+		scope := models.ClientAPIKeyScope{Categories: []string{"tv"}}
+
+		for _, req := range []*http.Request{
+			formRequest("app/setPreferences", `json={"autorun_enabled":true,"autorun_program":"sh -c id"}`),
+			formRequest("torrents/removeCategories", "categories=movies"),
+			formRequest("torrents/createCategory", "category=tv-new&savePath=/"),
+			formRequest("torrents/deleteTags", "tags=sonarr"),
+			formRequest("transfer/setUploadLimit", "limit=1"),
+			formRequest("rss/addFeed", "url=https://example.com/rss"),
+		} {
+			rec, forwarded := serveScoped(t, scope, req)
+			assert.Equal(t, http.StatusForbidden, rec.Code, req.URL.Path)
+			assert.Equal(t, "endpoint not allowed for category or tag restricted keys", rec.Header().Get(scopeDeniedReasonHeader), req.URL.Path)
+			assert.Nil(t, forwarded, req.URL.Path)
+		}
+
+		rec, _ := serveScoped(t, models.ClientAPIKeyScope{Tags: []string{"sonarr"}}, formRequest("app/setPreferences", `json={"save_path":"/tmp"}`))
+		assert.Equal(t, http.StatusForbidden, rec.Code, "tag restricted keys are denied too")
+	})
+}
@@ -0,0 +1,700 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+	"github.com/autobrr/qui/pkg/stringutils"
+)
+
+const virtualInstanceContextKey contextKey = "virtual_instance"
+
+// virtualInstanceState holds the runtime state shared by all virtual instances.
+type virtualInstanceState struct {
+	mu sync.Mutex
+	// next is the round-robin position per virtual instance.
+	next map[int]int
+	// snapshots is the last sync/maindata contribution of each member instance.
+	snapshots map[int]*virtualMemberSnapshot
+	// rid numbers the (always full) sync/maindata responses.
+	rid atomic.Int64
+}
+
+// virtualInstance is a virtual instance with the members that can currently serve requests.
+type virtualInstance struct {
+	*models.VirtualInstance
+	// members are the active member instance IDs in ascending order. The first
+	// one answers requests that don't belong to a specific torrent.
+	members []int
+}
+
+func (v *virtualInstance) primary() int {
+	return v.members[0]
+}
+
+// SetVirtualInstanceStore enables client API keys that target a virtual instance.
+func (h *Handler) SetVirtualInstanceStore(store *models.VirtualInstanceStore) {
+	h.virtualInstanceStore = store
+}
+
+func getVirtualInstance(ctx context.Context) *virtualInstance {
+	if v, ok := ctx.Value(virtualInstanceContextKey).(*virtualInstance); ok {
+		return v
+	}
+	return nil
+}
+
+// virtualInstanceMiddleware loads the virtual instance of keys that target one.
+func (h *Handler) virtualInstanceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientAPIKey := GetClientAPIKeyFromContext(r.Context())
+		if clientAPIKey == nil || clientAPIKey.VirtualInstanceID == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		virtual, err := h.loadVirtualInstance(r.Context(), *clientAPIKey.VirtualInstanceID)
+		if err != nil {
+			log.Error().Err(err).Int("keyId", clientAPIKey.ID).Int("virtualInstanceId", *clientAPIKey.VirtualInstanceID).Msg("Failed to load virtual instance for proxy request")
+			h.writeProxyError(w)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), virtualInstanceContextKey, virtual)))
+	})
+}
+
+func (h *Handler) loadVirtualInstance(ctx context.Context, id int) (*virtualInstance, error) {
+	if h.virtualInstanceStore == nil {
+		return nil, errors.New("virtual instances are not available")
+	}
+	stored, err := h.virtualInstanceStore.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	virtual := &virtualInstance{VirtualInstance: stored}
+	if h.instanceStore == nil {
+		virtual.members = slices.Sorted(slices.Values(stored.MemberInstanceIDs))
+	} else {
+		instances, err := h.instanceStore.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			if instance != nil && instance.IsActive && stored.HasMember(instance.ID) {
+				virtual.members = append(virtual.members, instance.ID)
+			}
+		}
+		slices.Sort(virtual.members)
+	}
+	if len(virtual.members) == 0 {
+		return nil, fmt.Errorf("virtual instance %q has no active members", stored.Name)
+	}
+	return virtual, nil
+}
+
+// serveVirtualInstanceMiddleware answers requests of virtual instance keys by
+// merging or routing them across the member instances.
+func (h *Handler) serveVirtualInstanceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		virtual := getVirtualInstance(r.Context())
+		if virtual == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		h.serveVirtual(w, r, virtual)
+	})
+}
+
+func (h *Handler) serveVirtual(w http.ResponseWriter, r *http.Request, virtual *virtualInstance) {
+	endpoint := proxyEndpoint(h.stripProxyPrefix(r.URL.Path, chi.URLParam(r, "api-key")))
+
+	switch endpoint {
+	case "":
+		// There is no single WebUI to show for several instances.
+		http.NotFound(w, r)
+	case "auth/login":
+		h.handleAuthLogin(w, withInstanceID(r, virtual.primary()))
+	case "torrents/info":
+		h.handleTorrentsInfo(w, r)
+	case "sync/maindata":
+		h.handleVirtualMainData(w, r, virtual)
+	case "torrents/categories":
+		h.handleVirtualCategories(w, r, virtual)
+	case "torrents/tags":
+		h.handleVirtualTags(w, r, virtual)
+	case "torrents/add":
+		h.handleVirtualAdd(w, r, virtual)
+	default:
+		h.routeVirtual(w, r, virtual, endpoint)
+	}
+}
+
+// writeVirtualTorrentsInfo answers torrents/info with the merged torrents of the members.
+func (h *Handler) writeVirtualTorrentsInfo(ctx context.Context, w http.ResponseWriter, virtual *virtualInstance, limit, offset int, sort, order string, filters qbittorrent.FilterOptions) {
+	response, err := h.syncManager.GetCrossInstanceTorrentsWithFilters(ctx, limit, offset, sort, order, "", filters, virtual.members)
+	if err != nil {
+		log.Error().Err(err).Int("virtualInstanceId", virtual.ID).Msg("Failed to get torrents for virtual instance")
+		h.writeProxyError(w)
+		return
+	}
+
+	torrents := make([]any, len(response.CrossInstanceTorrents))
+	for i, torrent := range response.CrossInstanceTorrents {
+		torrents[i] = torrent.Torrent
+	}
+	writeVirtualJSON(w, virtual, torrents)
+}
+
+// handleVirtualMainData answers sync/maindata with a full update merged across
+// the members. Every response is a full update, which clients handle like a
+// fresh session, so a member that is briefly unreachable keeps contributing
+// its last known torrents instead of having them disappear from the client.
+func (h *Handler) handleVirtualMainData(w http.ResponseWriter, r *http.Request, virtual *virtualInstance) {
+	if h.syncManager == nil {
+		h.writeProxyError(w)
+		return
+	}
+
+	ctx := r.Context()
+	snapshots := make([]*virtualMemberSnapshot, 0, len(virtual.members))
+	for _, instanceID := range virtual.members {
+		fresh, err := h.fetchMemberSnapshot(ctx, instanceID)
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return
+		}
+		snapshot, err := h.virtual.resolveSnapshot(instanceID, fresh, err)
+		if err != nil {
+			log.Error().Err(err).Int("virtualInstanceId", virtual.ID).Msg("Failed to build sync/maindata for virtual instance")
+			h.writeProxyError(w)
+			return
+		}
+		if snapshot.stale {
+			log.Warn().Int("virtualInstanceId", virtual.ID).Int("instanceId", instanceID).Msg("Serving last known torrents of unreachable virtual instance member")
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	var freeSpace int64
+	for _, instanceID := range virtual.members {
+		if space, err := h.syncManager.GetFreeSpace(ctx, instanceID); err == nil {
+			freeSpace = max(freeSpace, space)
+		}
+	}
+
+	writeVirtualJSON(w, virtual, mergeVirtualMainData(h.virtual.rid.Add(1), snapshots, freeSpace))
+}
+
+// virtualMemberSnapshot is what one member contributes to a virtual sync/maindata response.
+type virtualMemberSnapshot struct {
+	torrents         []*qbt.Torrent
+	categories       map[string]qbt.Category
+	tags             []string
+	useSubcategories bool
+	// stale is set when the member could not be reached and this is its last known state.
+	stale bool
+}
+
+func (h *Handler) fetchMemberSnapshot(ctx context.Context, instanceID int) (*virtualMemberSnapshot, error) {
+	response, err := h.syncManager.GetTorrentsWithFilters(qbittorrent.WithSkipTrackerHydration(ctx), instanceID, 0, 0, "", "", "", qbittorrent.FilterOptions{})
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &virtualMemberSnapshot{
+		torrents:         make([]*qbt.Torrent, 0, len(response.Torrents)),
+		categories:       response.Categories,
+		tags:             response.Tags,
+		useSubcategories: response.UseSubcategories,
+	}
+	for _, torrent := range response.Torrents {
+		snapshot.torrents = append(snapshot.torrents, torrent.Torrent)
+	}
+	return snapshot, nil
+}
+
+// resolveSnapshot remembers a member's fresh snapshot, or falls back to the
+// last one when fetching failed. Without an earlier snapshot the error is
+// returned, so clients never see a member's torrents vanish.
+func (s *virtualInstanceState) resolveSnapshot(instanceID int, fresh *virtualMemberSnapshot, fetchErr error) (*virtualMemberSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if fetchErr == nil {
+		if s.snapshots == nil {
+			s.snapshots = make(map[int]*virtualMemberSnapshot)
+		}
+		s.snapshots[instanceID] = fresh
+		return fresh, nil
+	}
+
+	last, ok := s.snapshots[instanceID]
+	if !ok {
+		return nil, fmt.Errorf("instance %d: %w", instanceID, fetchErr)
+	}
+	stale := *last
+	stale.stale = true
+	return &stale, nil
+}
+
+// mergeVirtualMainData builds a full sync/maindata update from the member
+// snapshots, which are ordered by instance ID.
+func mergeVirtualMainData(rid int64, snapshots []*virtualMemberSnapshot, freeSpace int64) map[string]any {
+	torrents := make(map[string]*qbt.Torrent)
+	categories := make(map[string]qbt.Category)
+	tagSet := make(map[string]struct{})
+	var downloadSpeed, uploadSpeed int64
+	var useSubcategories bool
+	for _, snapshot := range snapshots {
+		for _, torrent := range snapshot.torrents {
+			// A torrent on several members is listed once, from the lowest instance ID.
+			if _, exists := torrents[torrent.Hash]; exists {
+				continue
+			}
+			torrents[torrent.Hash] = torrent
+			// Speeds of an unreachable member are unknown rather than what they last were.
+			if !snapshot.stale {
+				downloadSpeed += torrent.DlSpeed
+				uploadSpeed += torrent.UpSpeed
+			}
+		}
+		for name, category := range snapshot.categories {
+			if existing, exists := categories[name]; !exists || (existing.SavePath == "" && category.SavePath != "") {
+				categories[name] = category
+			}
+		}
+		for _, tag := range snapshot.tags {
+			tagSet[tag] = struct{}{}
+		}
+		useSubcategories = useSubcategories || snapshot.useSubcategories
+	}
+
+	tags := slices.SortedFunc(maps.Keys(tagSet), stringutils.CompareFold)
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return map[string]any{
+		"rid":         rid,
+		"full_update": true,
+		"torrents":    torrents,
+		"categories":  categories,
+		"tags":        tags,
+		"server_state": map[string]any{
+			"connection_status": "connected",
+			"dl_info_speed":     downloadSpeed,
+			"up_info_speed":     uploadSpeed,
+			// The member with the most space is where free space placement adds to.
+			"free_space_on_disk": freeSpace,
+			"use_subcategories":  useSubcategories,
+		},
+	}
+}
+
+func (h *Handler) handleVirtualCategories(w http.ResponseWriter, r *http.Request, virtual *virtualInstance) {
+	if !h.validateQueryParams(w, r, map[string]struct{}{}, "torrents/categories") {
+		return
+	}
+	response, err := h.mergedVirtualState(r.Context(), virtual)
+	if err != nil {
+		log.Error().Err(err).Int("virtualInstanceId", virtual.ID).Msg("Failed to get categories for virtual instance")
+		h.writeProxyError(w)
+		return
+	}
+	categories := response.Categories
+	if categories == nil {
+		categories = map[string]qbt.Category{}
+	}
+	writeVirtualJSON(w, virtual, categories)
+}
+
+func (h *Handler) handleVirtualTags(w http.ResponseWriter, r *http.Request, virtual *virtualInstance) {
+	if !h.validateQueryParams(w, r, map[string]struct{}{}, "torrents/tags") {
+		return
+	}
+	response, err := h.mergedVirtualState(r.Context(), virtual)
+	if err != nil {
+		log.Error().Err(err).Int("virtualInstanceId", virtual.ID).Msg("Failed to get tags for virtual instance")
+		h.writeProxyError(w)
+		return
+	}
+	tags := response.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	writeVirtualJSON(w, virtual, tags)
+}
+
+// mergedVirtualState returns every member torrent plus the merged categories and tags.
+func (h *Handler) mergedVirtualState(ctx context.Context, virtual *virtualInstance) (*qbittorrent.TorrentResponse, error) {
+	if h.syncManager == nil {
+		return nil, errors.New("sync manager not available")
+	}
+	return h.syncManager.GetCrossInstanceTorrentsWithFilters(qbittorrent.WithSkipTrackerHydration(ctx), 0, 0, "", "", "", qbittorrent.FilterOptions{}, virtual.members)
+}
+
+// handleVirtualAdd forwards torrents/add to the member chosen by the placement policy.
+func (h *Handler) handleVirtualAdd(w http.ResponseWriter, r *http.Request, virtual *virtualInstance) {
+	form, err := peekForm(r)
+	if err != nil {
+		log.Warn().Err(err).Int("virtualInstanceId", virtual.ID).Msg("Failed to parse torrents/add for virtual instance")
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	category := form.Get("category")
+	instanceID := h.placeTorrent(r.Context(), virtual, category)
+
+	log.Debug().
+		Int("virtualInstanceId", virtual.ID).
+		Int("instanceId", instanceID).
+		Str("placement", string(virtual.Placement)).
+		Str("category", category).
+		Msg("Routing torrents/add for virtual instance")
+
+	h.serveMember(w, r, instanceID, "torrents/add")
+}
+
+// placeTorrent picks the member instance a new torrent with category is added to.
+func (h *Handler) placeTorrent(ctx context.Context, virtual *virtualInstance, category string) int {
+	switch virtual.Placement {
+	case models.PlacementCategory:
+		if instanceID, ok := virtual.CategoryInstances[category]; ok && slices.Contains(virtual.members, instanceID) {
+			return instanceID
+		}
+		return h.placeByFreeSpace(ctx, virtual)
+	case models.PlacementFreeSpace:
+		return h.placeByFreeSpace(ctx, virtual)
+	default:
+		return h.placeRoundRobin(virtual)
+	}
+}
+
+// placeByFreeSpace picks the member with the most free space, falling back to
+// round-robin when no member reports it.
+func (h *Handler) placeByFreeSpace(ctx context.Context, virtual *virtualInstance) int {
+	best, bestSpace := 0, int64(-1)
+	if h.syncManager != nil {
+		for _, instanceID := range virtual.members {
+			space, err := h.syncManager.GetFreeSpace(ctx, instanceID)
+			if err != nil {
+				log.Debug().Err(err).Int("instanceId", instanceID).Msg("Free space unavailable for virtual instance placement")
+				continue
+			}
+			if space > bestSpace {
+				best, bestSpace = instanceID, space
+			}
+		}
+	}
+	if best == 0 {
+		return h.placeRoundRobin(virtual)
+	}
+	return best
+}
+
+func (h *Handler) placeRoundRobin(virtual *virtualInstance) int {
+	h.virtual.mu.Lock()
+	defer h.virtual.mu.Unlock()
+
+	if h.virtual.next == nil {
+		h.virtual.next = make(map[int]int)
+	}
+	position := h.virtual.next[virtual.ID] % len(virtual.members)
+	h.virtual.next[virtual.ID] = position + 1
+	return virtual.members[position]
+}
+
+// routeVirtual forwards a request to the members holding the torrents it
+// targets. Changes reach every member holding a torrent, reads only the
+// lowest one. Requests without torrents go to the primary member when they
+// only read, and to every member when they change torrent state (e.g.
+// createCategory).
+func (h *Handler) routeVirtual(w http.ResponseWriter, r *http.Request, virtual *virtualInstance, endpoint string) {
+	form, err := peekForm(r)
+	if err != nil {
+		log.Warn().Err(err).Int("virtualInstanceId", virtual.ID).Str("endpoint", endpoint).Msg("Failed to parse request for virtual instance")
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	_, readOnly := readOnlyEndpoints[endpoint]
+	field, hashes := targetHashes(form)
+	if len(hashes) == 0 {
+		if readOnly || !strings.HasPrefix(endpoint, "torrents/") {
+			h.serveMember(w, r, virtual.primary(), endpoint)
+			return
+		}
+		h.fanOut(w, r, endpoint, virtual.members, "", nil)
+		return
+	}
+
+	if slices.ContainsFunc(hashes, func(hash string) bool { return strings.EqualFold(hash, "all") }) {
+		h.fanOut(w, r, endpoint, virtual.members, "", nil)
+		return
+	}
+
+	owners, err := h.virtualTorrentOwners(r.Context(), virtual)
+	if err != nil {
+		log.Error().Err(err).Int("virtualInstanceId", virtual.ID).Msg("Failed to resolve torrent owners for virtual instance")
+		h.writeProxyError(w)
+		return
+	}
+	byInstance := groupHashesByOwner(hashes, owners, !readOnly)
+	if len(byInstance) == 0 {
+		// qBittorrent answers unknown single-torrent lookups with 404.
+		if field == "hash" {
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	instanceIDs := slices.Sorted(maps.Keys(byInstance))
+	if len(instanceIDs) == 1 && len(byInstance[instanceIDs[0]]) == len(hashes) {
+		h.serveMember(w, r, instanceIDs[0], endpoint)
+		return
+	}
+	h.fanOut(w, r, endpoint, instanceIDs, field, byInstance)
+}
+
+// targetHashes returns the form field naming the targeted torrents and its hashes.
+func targetHashes(form url.Values) (field string, hashes []string) {
+	for _, name := range []string{"hashes", "hash"} {
+		if value := form.Get(name); value != "" {
+			for hash := range strings.SplitSeq(value, "|") {
+				if hash = strings.TrimSpace(hash); hash != "" {
+					hashes = append(hashes, hash)
+				}
+			}
+			return name, hashes
+		}
+	}
+	return "", nil
+}
+
+// groupHashesByOwner groups hashes by the instances holding them. A hash on
+// several members goes to all of them when every is set, and to the lowest
+// instance ID otherwise. Unknown hashes are dropped.
+func groupHashesByOwner(hashes []string, owners map[string][]int, every bool) map[int][]string {
+	byInstance := make(map[int][]string)
+	for _, hash := range hashes {
+		holders := owners[strings.ToUpper(hash)]
+		if !every && len(holders) > 1 {
+			holders = holders[:1]
+		}
+		for _, instanceID := range holders {
+			byInstance[instanceID] = append(byInstance[instanceID], hash)
+		}
+	}
+	return byInstance
+}
+
+// virtualTorrentOwners maps uppercase hashes to the members holding the
+// torrent, in ascending instance ID order.
+func (h *Handler) virtualTorrentOwners(ctx context.Context, virtual *virtualInstance) (map[string][]int, error) {
+	if h.syncManager == nil {
+		return nil, errors.New("sync manager not available")
+	}
+	owners := make(map[string][]int)
+	for _, instanceID := range virtual.members {
+		torrents, err := h.syncManager.GetAllTorrents(ctx, instanceID)
+		if err != nil {
+			return nil, fmt.Errorf("instance %d: %w", instanceID, err)
+		}
+		for _, torrent := range torrents {
+			hash := strings.ToUpper(torrent.Hash)
+			if !slices.Contains(owners[hash], instanceID) {
+				owners[hash] = append(owners[hash], instanceID)
+			}
+		}
+	}
+	return owners, nil
+}
+
+// keyTorrents returns the torrents a request's key can reach: those of its
+// instance, or of every member of its virtual instance.
+func (h *Handler) keyTorrents(ctx context.Context) ([]qbt.Torrent, error) {
+	virtual := getVirtualInstance(ctx)
+	if virtual == nil {
+		return h.syncManager.GetAllTorrents(ctx, GetInstanceIDFromContext(ctx))
+	}
+	var all []qbt.Torrent
+	for _, instanceID := range virtual.members {
+		torrents, err := h.syncManager.GetAllTorrents(ctx, instanceID)
+		if err != nil {
+			return nil, fmt.Errorf("instance %d: %w", instanceID, err)
+		}
+		all = append(all, torrents...)
+	}
+	return all, nil
+}
+
+// fanOut sends the request to several members and answers with the first
+// failure, or the first response when all succeed. When field is set, each
+// member only receives its own hashes from byInstance.
+func (h *Handler) fanOut(w http.ResponseWriter, r *http.Request, endpoint string, instanceIDs []int, field string, byInstance map[int][]string) {
+	body, err := bufferRequestBody(r)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	var responses []*bufferedResponseWriter
+	for _, instanceID := range instanceIDs {
+		req := r.Clone(r.Context())
+		restoreBody(req, body)
+		if field != "" {
+			if err := rewriteTargetHashes(req, body, field, byInstance[instanceID]); err != nil {
+				log.Warn().Err(err).Str("endpoint", endpoint).Msg("Failed to split request across virtual instance members")
+				http.Error(w, "Invalid form data", http.StatusBadRequest)
+				return
+			}
+		}
+
+		recorder := newBufferedResponseWriter()
+		h.serveMember(recorder, req, instanceID, endpoint)
+		responses = append(responses, recorder)
+	}
+
+	chosen := responses[0]
+	for _, response := range responses {
+		if response.status >= http.StatusBadRequest {
+			chosen = response
+			break
+		}
+	}
+	chosen.writeTo(w)
+}
+
+// rewriteTargetHashes replaces the hashes in field of req with hashes, in the
+// query or body, wherever the request carried them.
+func rewriteTargetHashes(req *http.Request, body []byte, field string, hashes []string) error {
+	value := strings.Join(hashes, "|")
+
+	if query := req.URL.Query(); query.Has(field) {
+		query.Set(field, value)
+		req.URL.RawQuery = query.Encode()
+	}
+	if len(body) == 0 {
+		return nil
+	}
+
+	overrides := map[string]string{field: value}
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	var rewritten []byte
+	var contentType string
+	var err error
+	if mediaType == "multipart/form-data" {
+		rewritten, contentType, err = rewriteMultipartForm(body, params["boundary"], overrides)
+	} else {
+		rewritten, contentType, err = rewriteURLEncodedForm(body, overrides)
+	}
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Del("Content-Length")
+	restoreBody(req, rewritten)
+	return nil
+}
+
+// serveMember serves r as a request for one member instance, using the same
+// intercepted handlers as keys bound to that instance.
+func (h *Handler) serveMember(w http.ResponseWriter, r *http.Request, instanceID int, endpoint string) {
+	recordAuditRouting(r.Context(), instanceID)
+	r, err := h.withProxyContext(withInstanceID(r, instanceID))
+	if err != nil {
+		h.writeProxyError(w)
+		return
+	}
+	h.memberEndpointHandler(endpoint)(w, r)
+}
+
+func (h *Handler) memberEndpointHandler(endpoint string) http.HandlerFunc {
+	switch endpoint {
+	case "torrents/reannounce":
+		return h.handleReannounce
+	case "sync/torrentPeers":
+		return h.handleTorrentPeers
+	case "torrents/properties":
+		return h.handleTorrentProperties
+	case "torrents/trackers":
+		return h.handleTorrentTrackers
+	case "torrents/files":
+		return h.handleTorrentFiles
+	case "torrents/mediainfo":
+		return h.handleTorrentMediaInfo
+	case "torrents/setLocation":
+		return h.handleSetLocation
+	case "torrents/renameFile":
+		return h.handleRenameFile
+	case "torrents/renameFolder":
+		return h.handleRenameFolder
+	case "torrents/delete":
+		return h.handleDeleteTorrents
+	default:
+		return h.ServeHTTP
+	}
+}
+
+func withInstanceID(r *http.Request, instanceID int) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), InstanceIDContextKey, instanceID))
+}
+
+func writeVirtualJSON(w http.ResponseWriter, virtual *virtualInstance, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		log.Error().Err(err).Int("virtualInstanceId", virtual.ID).Msg("Failed to encode virtual instance response")
+	}
+}
+
+// bufferedResponseWriter holds a member's response until fanOut picks one.
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponseWriter() *bufferedResponseWriter {
+	return &bufferedResponseWriter{header: make(http.Header), status: http.StatusOK}
+}
+
+func (b *bufferedResponseWriter) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponseWriter) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponseWriter) writeTo(w http.ResponseWriter) {
+	maps.Copy(w.Header(), b.header)
+	w.WriteHeader(b.status)
+	_, _ = w.Write(b.body.Bytes())
+}
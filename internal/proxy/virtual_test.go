@@ -0,0 +1,155 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestTargetHashes(t *testing.T) {
+	field, hashes := targetHashes(url.Values{"hashes": {"abc| def|"}})
+	assert.Equal(t, "hashes", field)
+	assert.Equal(t, []string{"abc", "def"}, hashes)
+
+	field, hashes = targetHashes(url.Values{"hash": {"abc"}})
+	assert.Equal(t, "hash", field)
+	assert.Equal(t, []string{"abc"}, hashes)
+
+	field, hashes = targetHashes(url.Values{"category": {"tv"}})
+	assert.Empty(t, field)
+	assert.Empty(t, hashes)
+}
+
+func TestGroupHashesByOwner(t *testing.T) {
+	owners := map[string][]int{"AAA": {1}, "BBB": {2}, "CCC": {1}}
+	grouped := groupHashesByOwner([]string{"aaa", "bbb", "ccc", "ddd"}, owners, true)
+	assert.Equal(t, map[int][]string{1: {"aaa", "ccc"}, 2: {"bbb"}}, grouped)
+
+	// A torrent on two members: changes reach both, reads only the first.
+	owners["EEE"] = []int{1, 2}
+	grouped = groupHashesByOwner([]string{"bbb", "eee"}, owners, true)
+	assert.Equal(t, map[int][]string{1: {"eee"}, 2: {"bbb", "eee"}}, grouped)
+	grouped = groupHashesByOwner([]string{"bbb", "eee"}, owners, false)
+	assert.Equal(t, map[int][]string{1: {"eee"}, 2: {"bbb"}}, grouped)
+}
+
+func TestVirtualMainDataWithMemberDown(t *testing.T) {
+	var state virtualInstanceState
+	errUnreachable := errors.New("connection refused")
+
+	member1 := &virtualMemberSnapshot{
+		torrents:   []*qbt.Torrent{{Hash: "aaa", Category: "tv", DlSpeed: 10}},
+		categories: map[string]qbt.Category{"tv": {Name: "tv"}},
+		tags:       []string{"sonarr"},
+	}
+	member2 := &virtualMemberSnapshot{
+		torrents:   []*qbt.Torrent{{Hash: "bbb", Category: "movies", DlSpeed: 5}, {Hash: "aaa", DlSpeed: 1}},
+		categories: map[string]qbt.Category{"movies": {Name: "movies", SavePath: "/movies"}},
+		tags:       []string{"radarr"},
+	}
+
+	// A member that was never reached has no torrents to show, so the request fails.
+	_, err := state.resolveSnapshot(2, nil, errUnreachable)
+	require.ErrorIs(t, err, errUnreachable)
+
+	for instanceID, snapshot := range map[int]*virtualMemberSnapshot{1: member1, 2: member2} {
+		resolved, err := state.resolveSnapshot(instanceID, snapshot, nil)
+		require.NoError(t, err)
+		assert.False(t, resolved.stale)
+	}
+
+	// Member 2 goes down: its last known torrents are still served.
+	up, err := state.resolveSnapshot(1, member1, nil)
+	require.NoError(t, err)
+	down, err := state.resolveSnapshot(2, nil, errUnreachable)
+	require.NoError(t, err)
+	assert.True(t, down.stale)
+	assert.False(t, member2.stale, "the stored snapshot is not modified")
+
+	data := mergeVirtualMainData(7, []*virtualMemberSnapshot{up, down}, 100)
+	assert.Equal(t, int64(7), data["rid"])
+	assert.Equal(t, true, data["full_update"])
+
+	torrents := data["torrents"].(map[string]*qbt.Torrent)
+	require.Len(t, torrents, 2)
+	assert.Equal(t, "tv", torrents["aaa"].Category, "duplicates come from the lowest member")
+	assert.Equal(t, "movies", torrents["bbb"].Category)
+	assert.Len(t, data["categories"], 2)
+	assert.Equal(t, []string{"radarr", "sonarr"}, data["tags"])
+
+	serverState := data["server_state"].(map[string]any)
+	assert.Equal(t, int64(10), serverState["dl_info_speed"], "speeds of the unreachable member are not counted")
+	assert.Equal(t, int64(100), serverState["free_space_on_disk"])
+}
+
+func TestPlaceTorrent(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil, nil, nil, "/")
+	virtual := &virtualInstance{
+		VirtualInstance: &models.VirtualInstance{
+			ID:                1,
+			Placement:         models.PlacementCategory,
+			CategoryInstances: map[string]int{"tv": 3, "movies": 9},
+		},
+		members: []int{2, 3},
+	}
+
+	assert.Equal(t, 3, h.placeTorrent(context.Background(), virtual, "tv"))
+
+	// Unmapped categories and inactive members fall back to free space, which
+	// without space information places round-robin.
+	assert.Equal(t, 2, h.placeTorrent(context.Background(), virtual, "movies"))
+	assert.Equal(t, 3, h.placeTorrent(context.Background(), virtual, ""))
+	assert.Equal(t, 2, h.placeTorrent(context.Background(), virtual, ""))
+
+	other := &virtualInstance{
+		VirtualInstance: &models.VirtualInstance{ID: 2, Placement: models.PlacementRoundRobin},
+		members:         []int{5, 6},
+	}
+	assert.Equal(t, 5, h.placeTorrent(context.Background(), other, ""), "round-robin positions are per virtual instance")
+}
+
+func TestRewriteTargetHashes(t *testing.T) {
+	body := []byte("hashes=aaa%7Cbbb&deleteFiles=false")
+	req, err := http.NewRequest(http.MethodPost, "/api/v2/torrents/delete", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	require.NoError(t, rewriteTargetHashes(req, body, "hashes", []string{"bbb"}))
+	rewritten, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	values, err := url.ParseQuery(string(rewritten))
+	require.NoError(t, err)
+	assert.Equal(t, "bbb", values.Get("hashes"))
+	assert.Equal(t, "false", values.Get("deleteFiles"))
+
+	req, err = http.NewRequest(http.MethodGet, "/api/v2/torrents/properties?hash=aaa", nil)
+	require.NoError(t, err)
+	require.NoError(t, rewriteTargetHashes(req, nil, "hash", []string{"AAA"}))
+	assert.Equal(t, "hash=AAA", req.URL.RawQuery)
+}
+
+func TestBufferedResponseWriter(t *testing.T) {
+	buffered := newBufferedResponseWriter()
+	buffered.Header().Set("Content-Type", "text/plain")
+	buffered.WriteHeader(http.StatusConflict)
+	_, _ = buffered.Write([]byte("Conflict"))
+
+	rec := httptest.NewRecorder()
+	buffered.writeTo(rec)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "Conflict", rec.Body.String())
+}
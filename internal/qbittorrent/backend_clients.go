@@ -0,0 +1,376 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+
+	"github.com/autobrr/qui/internal/clients"
+	"github.com/autobrr/qui/pkg/stringutils"
+)
+
+// BackendClient returns the client of a Transmission or Deluge instance. ok is
+// false for qBittorrent instances, which take the regular sync path.
+func (sm *SyncManager) BackendClient(ctx context.Context, instanceID int) (client clients.Client, ok bool, err error) {
+	if sm == nil || sm.clientPool == nil {
+		return nil, false, nil
+	}
+	backends := sm.clientPool.BackendPool()
+	if !backends.IsManaged(ctx, instanceID) {
+		return nil, false, nil
+	}
+	client, err = backends.Get(ctx, instanceID)
+	return client, true, err
+}
+
+// backendExportTorrent reads the .torrent file a Transmission or Deluge
+// instance keeps for hash. Neither client serves these files over RPC, so
+// this needs local filesystem access to the client's config directory.
+func (sm *SyncManager) backendExportTorrent(ctx context.Context, client clients.Client, instanceID int, hash string) ([]byte, string, string, error) {
+	instance, err := sm.clientPool.instanceStore.Get(ctx, instanceID)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get instance %d: %w", instanceID, err)
+	}
+	if !instance.HasLocalFilesystemAccess {
+		return nil, "", "", fmt.Errorf("export torrent from %s without local filesystem access: %w", client.Type(), clients.ErrUnsupported)
+	}
+
+	path, err := client.TorrentFilePath(ctx, hash)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to export torrent: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to export torrent: %w", err)
+	}
+
+	suggestedName := strings.TrimSpace(hash)
+	trackerDomain := ""
+	if torrents, err := client.Torrents(ctx); err == nil {
+		for _, torrent := range torrents {
+			if !strings.EqualFold(torrent.Hash, hash) {
+				continue
+			}
+			if name := strings.TrimSpace(torrent.Name); name != "" {
+				suggestedName = name
+			}
+			trackerDomain = sm.primaryTrackerDomain(torrent)
+			break
+		}
+	}
+
+	return data, suggestedName, trackerDomain, nil
+}
+
+// backendMainData builds the slice of MainData the filters and counts read:
+// tracker URLs to hashes.
+func backendMainData(torrents []qbt.Torrent) *qbt.MainData {
+	trackers := make(map[string][]string)
+	for i := range torrents {
+		for _, tracker := range torrents[i].Trackers {
+			if tracker.Url != "" {
+				trackers[tracker.Url] = append(trackers[tracker.Url], torrents[i].Hash)
+			}
+		}
+	}
+	return &qbt.MainData{Trackers: trackers}
+}
+
+// backendCategories derives the category list from the torrents, since
+// Transmission and Deluge keep no separate category registry.
+func backendCategories(torrents []qbt.Torrent) map[string]qbt.Category {
+	categories := make(map[string]qbt.Category)
+	for i := range torrents {
+		if name := torrents[i].Category; name != "" {
+			categories[name] = qbt.Category{Name: name}
+		}
+	}
+	return categories
+}
+
+func backendTags(torrents []qbt.Torrent) []string {
+	seen := make(map[string]struct{})
+	for i := range torrents {
+		for tag := range strings.SplitSeq(torrents[i].Tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				seen[tag] = struct{}{}
+			}
+		}
+	}
+	tags := make([]string, 0, len(seen))
+	for tag := range seen {
+		tags = append(tags, tag)
+	}
+	slices.SortFunc(tags, stringutils.CompareFold)
+	return tags
+}
+
+func splitBackendTags(tags string) []string {
+	var result []string
+	for tag := range strings.SplitSeq(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// backendTorrentsWithFilters serves GetTorrentsWithFilters for a backend
+// instance, reusing the manual filter, search, sort and count helpers.
+func (sm *SyncManager) backendTorrentsWithFilters(ctx context.Context, client clients.Client, instanceID int, limit, offset int, sort, order, search string, filters FilterOptions) (*TorrentResponse, error) {
+	torrents, err := client.Torrents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mainData := backendMainData(torrents)
+	categories := backendCategories(torrents)
+	tags := backendTags(torrents)
+
+	// Sorting works in place, so the filtered list must not share the slice the
+	// counts are computed from.
+	filtered := slices.Clone(torrents)
+	if !filters.IsEmpty() {
+		filtered = sm.applyManualFilters(nil, filtered, filters, mainData, categories, false)
+	}
+	if search != "" {
+		filtered = sm.filterTorrentsBySearch(filtered, search)
+	}
+	sm.sortBackendTorrents(filtered, sort, order == "desc")
+
+	stats := sm.calculateStats(filtered)
+	counts, _, _ := sm.calculateCountsFromTorrentsWithTrackers(ctx, nil, torrents, mainData, nil, false, false)
+
+	total := len(filtered)
+	start := min(max(offset, 0), total)
+	end := total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+	page := filtered[start:end]
+
+	views := make([]TorrentView, len(page))
+	for i := range page {
+		views[i] = TorrentView{Torrent: &page[i]}
+	}
+
+	return &TorrentResponse{
+		Torrents:        views,
+		Total:           total,
+		ActiveTaskCount: sm.GetActiveTaskCount(ctx, instanceID),
+		Stats:           stats,
+		Counts:          counts,
+		Categories:      categories,
+		Tags:            tags,
+		HasMore:         limit > 0 && end < total,
+	}, nil
+}
+
+// sortBackendTorrents sorts like GetTorrentsWithFilters. Fields qui re-sorts
+// itself reuse those helpers; the rest stand in for the library's sort.
+func (sm *SyncManager) sortBackendTorrents(torrents []qbt.Torrent, sort string, desc bool) {
+	switch sort {
+	case "", "name":
+		sm.sortTorrentsByNameCaseInsensitive(torrents, desc)
+		return
+	case "state":
+		sm.sortTorrentsByStatus(torrents, desc, false)
+		return
+	case "tracker":
+		sm.sortTorrentsByTracker(torrents, desc)
+		return
+	case "priority":
+		sm.sortTorrentsByPriority(torrents, desc)
+		return
+	case "eta":
+		sm.sortTorrentsByETA(torrents, desc)
+		return
+	case "added_on":
+		sm.sortTorrentsByTimestamp(torrents, desc, func(t qbt.Torrent) int64 { return t.AddedOn })
+		return
+	case "completion_on":
+		sm.sortTorrentsByTimestamp(torrents, desc, func(t qbt.Torrent) int64 { return NormalizeCompletionTimestamp(t.CompletionOn) })
+		return
+	}
+
+	var compare func(a, b *qbt.Torrent) int
+	switch sort {
+	case "size":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.Size, b.Size) }
+	case "total_size":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.TotalSize, b.TotalSize) }
+	case "progress":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.Progress, b.Progress) }
+	case "dlspeed":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.DlSpeed, b.DlSpeed) }
+	case "upspeed":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.UpSpeed, b.UpSpeed) }
+	case "ratio":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.Ratio, b.Ratio) }
+	case "downloaded":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.Downloaded, b.Downloaded) }
+	case "uploaded":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.Uploaded, b.Uploaded) }
+	case "amount_left":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.AmountLeft, b.AmountLeft) }
+	case "num_seeds":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.NumSeeds, b.NumSeeds) }
+	case "num_leechs":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.NumLeechs, b.NumLeechs) }
+	case "time_active":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.TimeActive, b.TimeActive) }
+	case "seeding_time":
+		compare = func(a, b *qbt.Torrent) int { return cmp.Compare(a.SeedingTime, b.SeedingTime) }
+	case "category":
+		compare = func(a, b *qbt.Torrent) int { return stringutils.CompareFold(a.Category, b.Category) }
+	case "tags":
+		compare = func(a, b *qbt.Torrent) int { return stringutils.CompareFold(a.Tags, b.Tags) }
+	case "save_path":
+		compare = func(a, b *qbt.Torrent) int { return stringutils.CompareFold(a.SavePath, b.SavePath) }
+	default:
+		sm.sortTorrentsByNameCaseInsensitive(torrents, desc)
+		return
+	}
+
+	slices.SortStableFunc(torrents, func(a, b qbt.Torrent) int {
+		result := compare(&a, &b)
+		if result == 0 {
+			return cmp.Compare(a.Hash, b.Hash)
+		}
+		if desc {
+			return -result
+		}
+		return result
+	})
+}
+
+// backendFilterTorrents applies the library filter options GetTorrents callers use.
+func (sm *SyncManager) backendFilterTorrents(torrents []qbt.Torrent, filter qbt.TorrentFilterOptions) []qbt.Torrent {
+	filters := FilterOptions{Hashes: filter.Hashes}
+	if filter.Filter != "" && filter.Filter != qbt.TorrentFilterAll {
+		filters.Status = []string{string(filter.Filter)}
+	}
+	if filter.Category != "" {
+		filters.Categories = []string{filter.Category}
+	}
+	if filter.Tag != "" {
+		filters.Tags = []string{filter.Tag}
+	}
+	if filters.IsEmpty() {
+		return torrents
+	}
+	return sm.applyManualFilters(nil, torrents, filters, backendMainData(torrents), backendCategories(torrents), false)
+}
+
+// backendAddRequest maps qBittorrent add options onto an AddRequest. Options
+// the backends have no equivalent for are ignored.
+func backendAddRequest(options map[string]string) clients.AddRequest {
+	return clients.AddRequest{
+		SavePath: options["savepath"],
+		Category: options["category"],
+		Tags:     splitBackendTags(options["tags"]),
+		Paused:   options["paused"] == "true" || options["stopped"] == "true",
+	}
+}
+
+func backendAdd(ctx context.Context, client clients.Client, req clients.AddRequest) (*qbt.TorrentAddResponse, error) {
+	if err := client.Add(ctx, req); err != nil {
+		return nil, err
+	}
+	return &qbt.TorrentAddResponse{SuccessCount: len(req.Files) + len(req.URLs)}, nil
+}
+
+func backendBulkAction(ctx context.Context, client clients.Client, hashes []string, action string) error {
+	switch action {
+	case "pause":
+		return client.Stop(ctx, hashes)
+	case "resume":
+		return client.Start(ctx, hashes)
+	case "delete":
+		return client.Delete(ctx, hashes, false)
+	case "deleteWithFiles":
+		return client.Delete(ctx, hashes, true)
+	default:
+		return fmt.Errorf("%s on %s instances: %w", action, client.Type(), clients.ErrUnsupported)
+	}
+}
+
+// backendUpdateTags rewrites the tags of each torrent with update, since the
+// backends only replace a torrent's tags wholesale.
+func backendUpdateTags(ctx context.Context, client clients.Client, hashes []string, update func(current []string) []string) error {
+	if !client.Capabilities().Tags {
+		return fmt.Errorf("tags on %s instances: %w", client.Type(), clients.ErrUnsupported)
+	}
+	torrents, err := client.Torrents(ctx)
+	if err != nil {
+		return err
+	}
+	current := make(map[string][]string, len(torrents))
+	for i := range torrents {
+		current[strings.ToLower(torrents[i].Hash)] = splitBackendTags(torrents[i].Tags)
+	}
+
+	var errs []error
+	for _, hash := range hashes {
+		tags, ok := current[strings.ToLower(hash)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("torrent %s not found", hash))
+			continue
+		}
+		errs = append(errs, client.SetTags(ctx, hash, update(tags)))
+	}
+	return errors.Join(errs...)
+}
+
+func backendAddTags(ctx context.Context, client clients.Client, hashes []string, added []string) error {
+	return backendUpdateTags(ctx, client, hashes, func(current []string) []string {
+		for _, tag := range added {
+			if !slices.Contains(current, tag) {
+				current = append(current, tag)
+			}
+		}
+		return current
+	})
+}
+
+func backendRemoveTags(ctx context.Context, client clients.Client, hashes []string, removed []string) error {
+	return backendUpdateTags(ctx, client, hashes, func(current []string) []string {
+		return slices.DeleteFunc(current, func(tag string) bool { return slices.Contains(removed, tag) })
+	})
+}
+
+func backendSetTags(ctx context.Context, client clients.Client, hashes []string, replacement []string) error {
+	return backendUpdateTags(ctx, client, hashes, func([]string) []string { return replacement })
+}
+
+// backendDeleteTags removes tags from every torrent carrying them, which is
+// what deleting a tag does in qBittorrent. The backends keep no tag list of
+// their own.
+func backendDeleteTags(ctx context.Context, client clients.Client, tags []string) error {
+	if !client.Capabilities().Tags {
+		return fmt.Errorf("tags on %s instances: %w", client.Type(), clients.ErrUnsupported)
+	}
+	torrents, err := client.Torrents(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range torrents {
+		current := splitBackendTags(torrents[i].Tags)
+		kept := slices.DeleteFunc(slices.Clone(current), func(tag string) bool { return slices.Contains(tags, tag) })
+		if len(kept) != len(current) {
+			errs = append(errs, client.SetTags(ctx, torrents[i].Hash, kept))
+		}
+	}
+	return errors.Join(errs...)
+}
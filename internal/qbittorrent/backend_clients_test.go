@@ -0,0 +1,116 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/clients"
+	"github.com/autobrr/qui/internal/models"
+)
+
+type backendInstances map[int]*models.Instance
+
+func (b backendInstances) Get(_ context.Context, id int) (*models.Instance, error) {
+	instance, ok := b[id]
+	if !ok {
+		return nil, models.ErrInstanceNotFound
+	}
+	return instance, nil
+}
+
+func (b backendInstances) GetDecryptedPassword(*models.Instance) (string, error) {
+	return "", nil
+}
+
+func (b backendInstances) GetDecryptedBasicPassword(*models.Instance) (*string, error) {
+	return nil, nil
+}
+
+// transmissionLabels fakes the labels of a Transmission instance over RPC.
+func transmissionLabels(t *testing.T, labels map[string][]string) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") == "" {
+			w.Header().Set("X-Transmission-Session-Id", "session-1")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		var req struct {
+			Method    string `json:"method"`
+			Arguments struct {
+				IDs    []string `json:"ids"`
+				Labels []string `json:"labels"`
+			} `json:"arguments"`
+		}
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		arguments := map[string]any{}
+		switch req.Method {
+		case "torrent-get":
+			torrents := make([]map[string]any, 0, len(labels))
+			for hash, tags := range labels {
+				torrents = append(torrents, map[string]any{"hashString": hash, "labels": tags})
+			}
+			arguments["torrents"] = torrents
+		case "torrent-set":
+			for _, hash := range req.Arguments.IDs {
+				labels[hash] = req.Arguments.Labels
+			}
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": "success", "arguments": arguments})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestBackendTagRuleActions runs the calls an automation tag rule makes
+// against a Transmission instance.
+func TestBackendTagRuleActions(t *testing.T) {
+	ctx := context.Background()
+	labels := map[string][]string{
+		"abc": {"keep", "stale"},
+		"def": {"stale"},
+	}
+	server := transmissionLabels(t, labels)
+
+	pool := &ClientPool{}
+	pool.SetBackendPool(clients.NewPool(backendInstances{
+		1: {ID: 1, Host: server.URL, ClientType: models.ClientTypeTransmission, IsActive: true},
+	}))
+	sm := NewSyncManager(pool, nil)
+
+	require.NoError(t, sm.SetTorrentTags(ctx, 1, []string{"abc"}, []string{"keep", "matched"}))
+	assert.Equal(t, []string{"keep", "matched"}, labels["abc"])
+
+	require.NoError(t, sm.AddTorrentTags(ctx, 1, []string{"def"}, []string{"matched"}))
+	assert.Equal(t, []string{"stale", "matched"}, labels["def"])
+
+	require.NoError(t, sm.RemoveTorrentTags(ctx, 1, []string{"def"}, []string{"stale"}))
+	assert.Equal(t, []string{"matched"}, labels["def"])
+
+	// Resetting a tag removes it from every torrent.
+	require.NoError(t, sm.DeleteTags(ctx, 1, []string{"matched"}))
+	assert.Equal(t, []string{"keep"}, labels["abc"])
+	assert.Empty(t, labels["def"])
+
+	require.ErrorIs(t, sm.SetAutoTMM(ctx, 1, []string{"abc"}, true), clients.ErrUnsupported)
+	require.ErrorIs(t, sm.CreateTags(ctx, 1, []string{"new"}), clients.ErrUnsupported)
+}
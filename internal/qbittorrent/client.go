@@ -64,6 +64,7 @@ var errInvalidWebAPIVersion = errors.New("invalid qBittorrent WebAPI version")
 type Client struct {
 	*qbt.Client
 	instanceID                 int
+	webUI                      webUIAccess
 	webAPIVersion              string
 	supportsSetTags            bool
 	supportsSetComment         bool
@@ -117,6 +118,10 @@ type Client struct {
 	addedState        map[string]struct{}
 	addedHandler      TorrentAddedHandler
 	addedInit         bool
+	stateMu           sync.Mutex
+	stateByHash       map[string]qbt.TorrentState
+	stateHandler      TorrentStateChangeHandler
+	stateInit         bool
 
 	// activeTaskCount caches the number of running/queued torrent-creation tasks.
 	// It is refreshed at most once per activeTaskCountTTL with single-flight, so the
@@ -165,6 +170,7 @@ func NewClientWithTimeout(instanceID int, instanceHost, username, password, apiK
 	client := &Client{
 		Client:          qbtClient,
 		instanceID:      instanceID,
+		webUI:           webUIAccess{host: instanceHost, basicUser: cfg.BasicUser, basicPass: cfg.BasicPass, apiKey: apiKey},
 		lastHealthCheck: time.Now(),
 		isHealthy:       true,
 		optimisticUpdates: ttlcache.New(ttlcache.Options[string, *OptimisticTorrentUpdate]{}.
@@ -204,6 +210,7 @@ func NewClientWithTimeout(instanceID int, instanceHost, username, password, apiK
 		client.updateServerState(data)
 		client.handleCompletionUpdates(data)
 		client.handleAddedUpdates(data)
+		client.handleStateChangeUpdates(data)
 		log.Trace().Int("instanceID", instanceID).Int("torrentCount", len(data.Torrents)).Msg("Sync manager update received, marking client as healthy")
 
 		client.dispatchMainData(data)
@@ -671,6 +678,16 @@ func (c *Client) SetTorrentAddedHandler(handler TorrentAddedHandler) {
 	c.addedMu.Unlock()
 }
 
+// SetTorrentStateChangeHandler registers a callback to be invoked when a known torrent changes state.
+func (c *Client) SetTorrentStateChangeHandler(handler TorrentStateChangeHandler) {
+	c.stateMu.Lock()
+	c.stateHandler = handler
+	if c.stateByHash == nil {
+		c.stateByHash = make(map[string]qbt.TorrentState)
+	}
+	c.stateMu.Unlock()
+}
+
 func (c *Client) StartSyncManager(ctx context.Context) error {
 	c.mu.RLock()
 	syncManager := c.syncManager
@@ -824,6 +841,68 @@ func (c *Client) handleAddedUpdates(data *qbt.MainData) {
 	}
 }
 
+func (c *Client) handleStateChangeUpdates(data *qbt.MainData) {
+	if data == nil {
+		return
+	}
+
+	c.stateMu.Lock()
+	handler := c.stateHandler
+	if handler == nil {
+		// Nobody is listening: drop the baseline so a handler registered later
+		// starts from a fresh snapshot instead of replaying stale transitions.
+		c.stateByHash = nil
+		c.stateInit = false
+		c.stateMu.Unlock()
+		return
+	}
+	if c.stateByHash == nil {
+		c.stateByHash = make(map[string]qbt.TorrentState)
+	}
+
+	for _, removed := range data.TorrentsRemoved {
+		delete(c.stateByHash, normalizeHashForCompletion(removed))
+	}
+
+	if !c.stateInit {
+		if len(data.Torrents) == 0 {
+			c.stateMu.Unlock()
+			return
+		}
+		for hash, torrent := range data.Torrents {
+			c.stateByHash[normalizeHashForCompletion(hash)] = torrent.State
+		}
+		c.stateInit = true
+		c.stateMu.Unlock()
+		return
+	}
+
+	type stateChange struct {
+		torrent  qbt.Torrent
+		previous qbt.TorrentState
+	}
+
+	changed := make([]stateChange, 0)
+	for hash, torrent := range data.Torrents {
+		if torrent.State == "" {
+			continue
+		}
+		normalized := normalizeHashForCompletion(hash)
+		previous, known := c.stateByHash[normalized]
+		c.stateByHash[normalized] = torrent.State
+		// Unknown hashes are new torrents; the added handler owns those.
+		if !known || previous == torrent.State {
+			continue
+		}
+		changed = append(changed, stateChange{torrent: torrent, previous: previous})
+	}
+	c.stateMu.Unlock()
+
+	for _, change := range changed {
+		go handler(context.Background(), c.instanceID, change.torrent, change.previous)
+	}
+}
+
 // NormalizeCompletionTimestamp returns ts when it holds a real completion
 // timestamp (completion_on / seen_complete) and 0 when it holds a
 // never-completed sentinel. The sentinel differs per qbit version: 5.x emits
@@ -0,0 +1,102 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+)
+
+type observedStateChange struct {
+	hash     string
+	current  qbt.TorrentState
+	previous qbt.TorrentState
+}
+
+func TestHandleStateChangeUpdatesBaselinesThenReportsTransitions(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{instanceID: 5}
+
+	seen := make(chan observedStateChange, 4)
+	client.SetTorrentStateChangeHandler(func(_ context.Context, instanceID int, torrent qbt.Torrent, previous qbt.TorrentState) {
+		if instanceID != 5 {
+			t.Errorf("unexpected instanceID %d", instanceID)
+		}
+		seen <- observedStateChange{hash: torrent.Hash, current: torrent.State, previous: previous}
+	})
+
+	// Startup snapshot only establishes the baseline.
+	client.handleStateChangeUpdates(&qbt.MainData{
+		Torrents: map[string]qbt.Torrent{
+			"abc": {Hash: "abc", State: qbt.TorrentStateDownloading},
+		},
+	})
+	requireNoStateChange(t, seen, 200*time.Millisecond)
+
+	// Unchanged state and brand-new torrents are not transitions.
+	client.handleStateChangeUpdates(&qbt.MainData{
+		Torrents: map[string]qbt.Torrent{
+			"abc": {Hash: "abc", State: qbt.TorrentStateDownloading},
+			"def": {Hash: "def", State: qbt.TorrentStateStalledDl},
+		},
+	})
+	requireNoStateChange(t, seen, 200*time.Millisecond)
+
+	client.handleStateChangeUpdates(&qbt.MainData{
+		Torrents: map[string]qbt.Torrent{
+			"abc": {Hash: "abc", State: qbt.TorrentStateStalledDl},
+			"def": {Hash: "def", State: qbt.TorrentStateStalledDl},
+		},
+	})
+
+	select {
+	case got := <-seen:
+		if got.hash != "abc" || got.current != qbt.TorrentStateStalledDl || got.previous != qbt.TorrentStateDownloading {
+			t.Fatalf("unexpected state change: %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a state change event")
+	}
+	requireNoStateChange(t, seen, 200*time.Millisecond)
+}
+
+func TestHandleStateChangeUpdatesForgetsRemovedTorrents(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{instanceID: 6}
+
+	seen := make(chan observedStateChange, 2)
+	client.SetTorrentStateChangeHandler(func(_ context.Context, _ int, torrent qbt.Torrent, previous qbt.TorrentState) {
+		seen <- observedStateChange{hash: torrent.Hash, current: torrent.State, previous: previous}
+	})
+
+	client.handleStateChangeUpdates(&qbt.MainData{
+		Torrents: map[string]qbt.Torrent{
+			"abc": {Hash: "abc", State: qbt.TorrentStateUploading},
+		},
+	})
+	client.handleStateChangeUpdates(&qbt.MainData{TorrentsRemoved: []string{"abc"}})
+
+	// Re-added under the same hash: treated as new, not as a transition.
+	client.handleStateChangeUpdates(&qbt.MainData{
+		Torrents: map[string]qbt.Torrent{
+			"abc": {Hash: "abc", State: qbt.TorrentStateStalledUp},
+		},
+	})
+	requireNoStateChange(t, seen, 200*time.Millisecond)
+}
+
+func requireNoStateChange(t *testing.T, ch <-chan observedStateChange, d time.Duration) {
+	t.Helper()
+
+	select {
+	case got := <-ch:
+		t.Fatalf("unexpected state change event: %+v", got)
+	case <-time.After(d):
+	}
+}
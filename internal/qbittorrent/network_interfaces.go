@@ -0,0 +1,259 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+)
+
+// Addresses qBittorrent accepts for current_interface_address besides the
+// ones an interface reports: all addresses, all IPv4 and all IPv6.
+var wildcardInterfaceAddresses = []string{"", "0.0.0.0", "::"}
+
+// ErrInvalidNetworkBinding is returned when a preferences update names a
+// network interface or address the instance does not have.
+var ErrInvalidNetworkBinding = errors.New("invalid network binding")
+
+// webUIAccess is what raw Web API requests need for endpoints go-qbittorrent
+// does not wrap. The session cookie comes from the go-qbittorrent client's jar.
+type webUIAccess struct {
+	host      string
+	basicUser string
+	basicPass string
+	apiKey    string
+}
+
+// NetworkInterface is an entry of qBittorrent's network interface list. Value
+// is what current_network_interface takes; Name is the display name.
+type NetworkInterface struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// GetNetworkInterfacesCtx lists the network interfaces qBittorrent can bind to.
+func (c *Client) GetNetworkInterfacesCtx(ctx context.Context) ([]NetworkInterface, error) {
+	var interfaces []NetworkInterface
+	if err := c.webAPIGet(ctx, "app/networkInterfaceList", nil, &interfaces); err != nil {
+		return nil, err
+	}
+	if interfaces == nil {
+		interfaces = []NetworkInterface{}
+	}
+	return interfaces, nil
+}
+
+// GetNetworkInterfaceAddressesCtx lists the addresses of iface, or of every
+// interface when iface is empty.
+func (c *Client) GetNetworkInterfaceAddressesCtx(ctx context.Context, iface string) ([]string, error) {
+	var addresses []string
+	if err := c.webAPIGet(ctx, "app/networkInterfaceAddressList", url.Values{"iface": {iface}}, &addresses); err != nil {
+		return nil, err
+	}
+	if addresses == nil {
+		addresses = []string{}
+	}
+	return addresses, nil
+}
+
+// webAPIGet issues a GET against a Web API endpoint and decodes the JSON
+// response into out. A rejected session is renewed once.
+func (c *Client) webAPIGet(ctx context.Context, endpoint string, params url.Values, out any) error {
+	if c.webUI.host == "" {
+		return errors.New("qBittorrent host is not set")
+	}
+	target := strings.TrimRight(c.webUI.host, "/") + "/api/v2/" + endpoint
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+		if c.webUI.basicUser != "" {
+			req.SetBasicAuth(c.webUI.basicUser, c.webUI.basicPass)
+		}
+		if c.webUI.apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+c.webUI.apiKey)
+		}
+
+		httpClient := c.GetHTTPClient()
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("%s: %w", endpoint, err)
+		}
+
+		if resp.StatusCode == http.StatusForbidden && attempt == 0 && c.webUI.apiKey == "" {
+			resp.Body.Close()
+			if err := c.LoginCtx(ctx); err != nil {
+				return fmt.Errorf("%s: renew session: %w", endpoint, err)
+			}
+			continue
+		}
+
+		err = decodeWebAPIResponse(resp, out)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", endpoint, err)
+		}
+		return nil
+	}
+}
+
+func decodeWebAPIResponse(resp *http.Response, out any) error {
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// GetNetworkInterfaces lists the network interfaces an instance can bind to.
+func (sm *SyncManager) GetNetworkInterfaces(ctx context.Context, instanceID int) ([]NetworkInterface, error) {
+	client, err := sm.clientPool.GetClient(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	interfaces, err := client.GetNetworkInterfacesCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network interfaces: %w", err)
+	}
+	return interfaces, nil
+}
+
+// GetNetworkInterfaceAddresses lists the addresses of an instance's network
+// interface, or of all interfaces when iface is empty.
+func (sm *SyncManager) GetNetworkInterfaceAddresses(ctx context.Context, instanceID int, iface string) ([]string, error) {
+	client, err := sm.clientPool.GetClient(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	addresses, err := client.GetNetworkInterfaceAddressesCtx(ctx, iface)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network interface addresses: %w", err)
+	}
+	return addresses, nil
+}
+
+// ValidateNetworkBindingPreferences checks current_network_interface and
+// current_interface_address in a preferences update against the instance's
+// live interface list, so a typo cannot cut a VPN-bound instance off.
+// Validation failures wrap ErrInvalidNetworkBinding.
+func (sm *SyncManager) ValidateNetworkBindingPreferences(ctx context.Context, instanceID int, prefs map[string]any) error {
+	_, hasIface := prefs["current_network_interface"]
+	_, hasAddress := prefs["current_interface_address"]
+	if !hasIface && !hasAddress {
+		return nil
+	}
+
+	client, err := sm.clientPool.GetClient(ctx, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %w", err)
+	}
+	return client.ValidateNetworkBinding(ctx, prefs)
+}
+
+// ValidateNetworkBinding checks the network binding keys in a preferences
+// update. A value equal to the instance's current one is accepted even when
+// the interface is down, so unrelated settings can still be saved.
+func (c *Client) ValidateNetworkBinding(ctx context.Context, prefs map[string]any) error {
+	rawIface, hasIface := prefs["current_network_interface"]
+	rawAddress, hasAddress := prefs["current_interface_address"]
+	if !hasIface && !hasAddress {
+		return nil
+	}
+
+	iface, ok := rawIface.(string)
+	if hasIface && !ok {
+		return fmt.Errorf("%w: current_network_interface must be a string", ErrInvalidNetworkBinding)
+	}
+	address, ok := rawAddress.(string)
+	if hasAddress && !ok {
+		return fmt.Errorf("%w: current_interface_address must be a string", ErrInvalidNetworkBinding)
+	}
+
+	appPrefs, err := c.GetAppPreferences(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get app preferences: %w", err)
+	}
+	current, err := currentNetworkBinding(appPrefs)
+	if err != nil {
+		return err
+	}
+	// Validate the binding the instance ends up with: a key the update leaves
+	// out keeps its current value.
+	if !hasIface {
+		iface = current.Interface
+	}
+	if !hasAddress {
+		address = current.Address
+	}
+
+	if iface != "" && iface != current.Interface {
+		interfaces, err := c.GetNetworkInterfacesCtx(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get network interfaces: %w", err)
+		}
+		if !slices.ContainsFunc(interfaces, func(ni NetworkInterface) bool { return ni.Value == iface }) {
+			return fmt.Errorf("%w: network interface %q does not exist on the instance", ErrInvalidNetworkBinding, iface)
+		}
+	}
+
+	if slices.Contains(wildcardInterfaceAddresses, address) {
+		return nil
+	}
+	if address == current.Address && iface == current.Interface {
+		return nil
+	}
+	addresses, err := c.GetNetworkInterfaceAddressesCtx(ctx, iface)
+	if err != nil {
+		return fmt.Errorf("failed to get network interface addresses: %w", err)
+	}
+	if !slices.Contains(addresses, address) {
+		if !hasAddress {
+			return fmt.Errorf("%w: the bound address %q does not belong to network interface %q, set current_interface_address too", ErrInvalidNetworkBinding, address, iface)
+		}
+		if iface == "" {
+			return fmt.Errorf("%w: address %q does not exist on the instance", ErrInvalidNetworkBinding, address)
+		}
+		return fmt.Errorf("%w: address %q does not belong to network interface %q", ErrInvalidNetworkBinding, address, iface)
+	}
+	return nil
+}
+
+type networkBinding struct {
+	Interface string `json:"current_network_interface"`
+	Address   string `json:"current_interface_address"`
+}
+
+func currentNetworkBinding(prefs *qbt.AppPreferences) (networkBinding, error) {
+	var binding networkBinding
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return binding, fmt.Errorf("encode preferences: %w", err)
+	}
+	if err := json.Unmarshal(data, &binding); err != nil {
+		return binding, fmt.Errorf("decode preferences: %w", err)
+	}
+	return binding, nil
+}
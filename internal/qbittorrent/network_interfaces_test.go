@@ -0,0 +1,107 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// networkInterfaceServer emulates a qBittorrent bound to tun0 with an eth0
+// fallback. The first interface list request is rejected as an expired session.
+func networkInterfaceServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var logins atomic.Int32
+	var rejected atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session"})
+			_, _ = w.Write([]byte("Ok."))
+		case "/api/v2/app/preferences":
+			_, _ = w.Write([]byte(`{"current_network_interface":"tun0","current_interface_address":"10.8.0.2"}`))
+		case "/api/v2/app/networkInterfaceList":
+			if rejected.CompareAndSwap(false, true) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`[{"name":"tun0","value":"tun0"},{"name":"Ethernet","value":"eth0"}]`))
+		case "/api/v2/app/networkInterfaceAddressList":
+			switch r.URL.Query().Get("iface") {
+			case "tun0":
+				_, _ = w.Write([]byte(`["10.8.0.2"]`))
+			case "eth0":
+				_, _ = w.Write([]byte(`["192.168.1.20","fe80::1"]`))
+			default:
+				_, _ = w.Write([]byte(`["10.8.0.2","192.168.1.20","fe80::1"]`))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &logins
+}
+
+func newNetworkInterfaceClient(srv *httptest.Server) *Client {
+	return &Client{
+		Client: qbt.NewClient(qbt.Config{Host: srv.URL, Timeout: 5}),
+		webUI:  webUIAccess{host: srv.URL},
+	}
+}
+
+func TestGetNetworkInterfacesRenewsSession(t *testing.T) {
+	t.Parallel()
+
+	srv, logins := networkInterfaceServer(t)
+	client := newNetworkInterfaceClient(srv)
+
+	interfaces, err := client.GetNetworkInterfacesCtx(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []NetworkInterface{{Name: "tun0", Value: "tun0"}, {Name: "Ethernet", Value: "eth0"}}, interfaces)
+	assert.Equal(t, int32(1), logins.Load())
+
+	addresses, err := client.GetNetworkInterfaceAddressesCtx(context.Background(), "eth0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.20", "fe80::1"}, addresses)
+}
+
+func TestValidateNetworkBinding(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := networkInterfaceServer(t)
+	client := newNetworkInterfaceClient(srv)
+	ctx := context.Background()
+
+	for name, prefs := range map[string]map[string]any{
+		"unrelated keys":          {"max_connec": 100},
+		"unchanged binding":       {"current_network_interface": "tun0", "current_interface_address": "10.8.0.2"},
+		"any interface":           {"current_network_interface": "", "current_interface_address": ""},
+		"other interface":         {"current_network_interface": "eth0", "current_interface_address": "192.168.1.20"},
+		"wildcard address":        {"current_network_interface": "eth0", "current_interface_address": "::"},
+		"address of current link": {"current_interface_address": "10.8.0.2"},
+		"interface only, any":     {"current_network_interface": ""},
+	} {
+		assert.NoError(t, client.ValidateNetworkBinding(ctx, prefs), name)
+	}
+
+	for name, prefs := range map[string]map[string]any{
+		"unknown interface":         {"current_network_interface": "wg0"},
+		"address of other iface":    {"current_network_interface": "eth0", "current_interface_address": "10.8.0.2"},
+		"address on current iface":  {"current_interface_address": "192.168.1.20"},
+		"non-string interface":      {"current_network_interface": 1},
+		"unknown address, no iface": {"current_network_interface": "", "current_interface_address": "203.0.113.5"},
+		"interface only":            {"current_network_interface": "eth0"},
+	} {
+		assert.ErrorIs(t, client.ValidateNetworkBinding(ctx, prefs), ErrInvalidNetworkBinding, name)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
+	"github.com/autobrr/qui/internal/clients"
 	"github.com/autobrr/qui/internal/models"
 )
 
@@ -28,6 +29,9 @@ var (
 	ErrInstanceInBackoff = errors.New("qBittorrent instance is in backoff")
 	// ErrHealthCheckInProgress classifies a transient in-flight health-check blocker.
 	ErrHealthCheckInProgress = errors.New("qBittorrent instance health check already in progress")
+	// ErrNotQBittorrent indicates the instance is a Transmission or Deluge client,
+	// which is served through the client backends instead.
+	ErrNotQBittorrent = errors.New("instance is not a qBittorrent client")
 )
 
 // Backoff constants
@@ -149,7 +153,9 @@ type ClientPool struct {
 	syncEventSinkSeq  uint64
 	completionHandler TorrentCompletionHandler
 	addedHandler      TorrentAddedHandler
-	syncManager       *SyncManager // Reference for starting background tasks
+	stateHandler      TorrentStateChangeHandler
+	syncManager       *SyncManager  // Reference for starting background tasks
+	backends          *clients.Pool // Transmission and Deluge instances
 }
 
 // NewClientPool creates a new client pool
@@ -224,6 +230,22 @@ func (cp *ClientPool) SetTorrentAddedHandler(handler TorrentAddedHandler) {
 	}
 }
 
+// SetTorrentStateChangeHandler registers a callback for new and existing clients when torrent states change.
+func (cp *ClientPool) SetTorrentStateChangeHandler(handler TorrentStateChangeHandler) {
+	cp.mu.Lock()
+	cp.stateHandler = handler
+
+	clients := make([]*Client, 0, len(cp.clients))
+	for _, client := range cp.clients {
+		clients = append(clients, client)
+	}
+	cp.mu.Unlock()
+
+	for _, client := range clients {
+		client.SetTorrentStateChangeHandler(handler)
+	}
+}
+
 // SetSyncManager sets the SyncManager reference used for background tasks and
 // passes through any existing sync event sink.
 func (cp *ClientPool) SetSyncManager(sm *SyncManager) {
@@ -252,6 +274,23 @@ func (cp *ClientPool) applySyncManagerSinkIfCurrent(sm *SyncManager, sink SyncEv
 	sm.SetSyncEventSink(sink)
 }
 
+// SetBackendPool registers the pool serving Transmission and Deluge instances.
+func (cp *ClientPool) SetBackendPool(backends *clients.Pool) {
+	cp.mu.Lock()
+	cp.backends = backends
+	cp.mu.Unlock()
+}
+
+// BackendPool returns the pool serving Transmission and Deluge instances, or nil.
+func (cp *ClientPool) BackendPool() *clients.Pool {
+	if cp == nil {
+		return nil
+	}
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	return cp.backends
+}
+
 // getInstanceLock gets or creates a per-instance creation lock
 func (cp *ClientPool) getInstanceLock(instanceID int) *sync.Mutex {
 	cp.creationMu.Lock()
@@ -394,6 +433,10 @@ func (cp *ClientPool) createClientWithTimeout(ctx context.Context, instanceID in
 		return nil, ErrInstanceDisabled
 	}
 
+	if !instance.IsQBittorrent() {
+		return nil, fmt.Errorf("%w: %s", ErrNotQBittorrent, instance.ClientType)
+	}
+
 	password, err := cp.decryptField(instanceID, instance.Name, "password", func() (string, error) {
 		return cp.instanceStore.GetDecryptedPassword(instance)
 	})
@@ -441,6 +484,7 @@ func (cp *ClientPool) createClientWithTimeout(ctx context.Context, instanceID in
 	cp.resetFailureTrackingLocked(instanceID)
 	completionHandler := cp.completionHandler
 	addedHandler := cp.addedHandler
+	stateHandler := cp.stateHandler
 	cp.mu.Unlock()
 
 	if completionHandler != nil {
@@ -449,6 +493,9 @@ func (cp *ClientPool) createClientWithTimeout(ctx context.Context, instanceID in
 	if addedHandler != nil {
 		client.SetTorrentAddedHandler(addedHandler)
 	}
+	if stateHandler != nil {
+		client.SetTorrentStateChangeHandler(stateHandler)
+	}
 
 	// Start the sync manager
 	if err := client.StartSyncManager(ctx); err != nil {
@@ -500,8 +547,11 @@ func (cp *ClientPool) RemoveClient(instanceID int) {
 	cp.mu.Lock()
 	delete(cp.clients, instanceID)
 	sm := cp.syncManager
+	backends := cp.backends
 	cp.mu.Unlock()
 
+	backends.Remove(instanceID)
+
 	// Stop background tracker health refresh
 	if sm != nil {
 		sm.StopTrackerHealthRefresh(instanceID)
@@ -31,6 +31,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/autobrr/qui/internal/clients"
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/services/trackericons"
 	"github.com/autobrr/qui/pkg/stringutils"
@@ -65,6 +66,9 @@ type TorrentCompletionHandler func(ctx context.Context, instanceID int, torrent
 // TorrentAddedHandler is invoked when a torrent is first seen as new.
 type TorrentAddedHandler func(ctx context.Context, instanceID int, torrent qbt.Torrent)
 
+// TorrentStateChangeHandler is invoked when a known torrent moves to a different state.
+type TorrentStateChangeHandler func(ctx context.Context, instanceID int, torrent qbt.Torrent, previous qbt.TorrentState)
+
 // Global URL cache for domain extraction - shared across all sync managers
 var urlCache = ttlcache.New(ttlcache.Options[string, string]{}.SetDefaultTTL(5 * time.Minute))
 
@@ -1291,6 +1295,14 @@ func (sm *SyncManager) SetTorrentAddedHandler(handler TorrentAddedHandler) {
 	sm.clientPool.SetTorrentAddedHandler(handler)
 }
 
+// SetTorrentStateChangeHandler registers a callback for torrent state transitions across all clients.
+func (sm *SyncManager) SetTorrentStateChangeHandler(handler TorrentStateChangeHandler) {
+	if sm == nil || sm.clientPool == nil {
+		return
+	}
+	sm.clientPool.SetTorrentStateChangeHandler(handler)
+}
+
 // InvalidateFileCache invalidates the file cache for a torrent
 func (sm *SyncManager) InvalidateFileCache(ctx context.Context, instanceID int, hash string) error {
 	fm := sm.getFilesManager()
@@ -1307,6 +1319,17 @@ func (sm *SyncManager) GetErrorStore() *models.InstanceErrorStore {
 
 // GetTorrents gets torrents with the specified filter options
 func (sm *SyncManager) GetTorrents(ctx context.Context, instanceID int, filter qbt.TorrentFilterOptions) ([]qbt.Torrent, error) {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return nil, err
+		}
+		torrents, err := backend.Torrents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return sm.backendFilterTorrents(torrents, filter), nil
+	}
+
 	_, syncManager, _, err := sm.readMainData(ctx, instanceID, mainDataRead)
 	if err != nil {
 		return nil, err
@@ -1395,6 +1418,13 @@ func (sm *SyncManager) validateTorrentsExist(client *Client, hashes []string, op
 // from the response. When the context skips tracker hydration, cached tracker
 // health is still used for tracker-health filters and state sorting when available.
 func (sm *SyncManager) GetTorrentsWithFilters(ctx context.Context, instanceID int, limit, offset int, sort, order, search string, filters FilterOptions) (*TorrentResponse, error) {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return nil, err
+		}
+		return sm.backendTorrentsWithFilters(ctx, backend, instanceID, limit, offset, sort, order, search, filters)
+	}
+
 	var filteredTorrents []qbt.Torrent
 	var allTorrentsForCounts []qbt.Torrent
 	var err error
@@ -2334,6 +2364,13 @@ func (sm *SyncManager) GetQBittorrentSyncManager(ctx context.Context, instanceID
 
 // BulkAction performs bulk operations on torrents
 func (sm *SyncManager) BulkAction(ctx context.Context, instanceID int, hashes []string, action string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return backendBulkAction(ctx, backend, hashes, action)
+	}
+
 	// Get client and sync manager
 	client, syncManager, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -2702,6 +2739,15 @@ func bulkActionSyncRetry(
 
 // AddTorrent adds a new torrent from file content
 func (sm *SyncManager) AddTorrent(ctx context.Context, instanceID int, fileContent []byte, options map[string]string) (*qbt.TorrentAddResponse, error) {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return nil, err
+		}
+		req := backendAddRequest(options)
+		req.Files = [][]byte{fileContent}
+		return backendAdd(ctx, backend, req)
+	}
+
 	// Get client and sync manager
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -2722,6 +2768,15 @@ func (sm *SyncManager) AddTorrent(ctx context.Context, instanceID int, fileConte
 
 // AddTorrentFromURLs adds new torrents from URLs or magnet links
 func (sm *SyncManager) AddTorrentFromURLs(ctx context.Context, instanceID int, urls []string, options map[string]string) (*qbt.TorrentAddResponse, error) {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return nil, err
+		}
+		req := backendAddRequest(options)
+		req.URLs = urls
+		return backendAdd(ctx, backend, req)
+	}
+
 	// Get client and sync manager
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -2745,6 +2800,17 @@ func addTorrentURLsErrorSummary(urls []string) string {
 
 // GetCategories gets all categories
 func (sm *SyncManager) GetCategories(ctx context.Context, instanceID int) (map[string]qbt.Category, error) {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return nil, err
+		}
+		torrents, err := backend.Torrents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return backendCategories(torrents), nil
+	}
+
 	// Get client and sync manager
 	_, syncManager, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -2769,6 +2835,17 @@ func (sm *SyncManager) GetCategories(ctx context.Context, instanceID int) (map[s
 
 // GetTags gets all tags
 func (sm *SyncManager) GetTags(ctx context.Context, instanceID int) ([]string, error) {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return nil, err
+		}
+		torrents, err := backend.Torrents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return backendTags(torrents), nil
+	}
+
 	// Get client and sync manager
 	_, syncManager, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -2796,6 +2873,13 @@ func (sm *SyncManager) GetTags(ctx context.Context, instanceID int) ([]string, e
 // SetTorrentTags replaces all tags on torrents (for qBit 5.1+ / WebAPI 2.11.4+).
 // Returns an error wrapping qbt.ErrUnsupportedVersion if the client doesn't support SetTags.
 func (sm *SyncManager) SetTorrentTags(ctx context.Context, instanceID int, hashes []string, tags []string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return backendSetTags(ctx, backend, hashes, splitBackendTags(strings.Join(tags, ",")))
+	}
+
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
 		return err
@@ -2807,6 +2891,13 @@ func (sm *SyncManager) SetTorrentTags(ctx context.Context, instanceID int, hashe
 
 // AddTorrentTags adds tags to torrents (works with all qBittorrent versions).
 func (sm *SyncManager) AddTorrentTags(ctx context.Context, instanceID int, hashes []string, tags []string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return backendAddTags(ctx, backend, hashes, splitBackendTags(strings.Join(tags, ",")))
+	}
+
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
 		return err
@@ -2818,6 +2909,13 @@ func (sm *SyncManager) AddTorrentTags(ctx context.Context, instanceID int, hashe
 
 // RemoveTorrentTags removes tags from torrents (works with all qBittorrent versions).
 func (sm *SyncManager) RemoveTorrentTags(ctx context.Context, instanceID int, hashes []string, tags []string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return backendRemoveTags(ctx, backend, hashes, splitBackendTags(strings.Join(tags, ",")))
+	}
+
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
 		return err
@@ -2846,6 +2944,13 @@ func (sm *SyncManager) GetTorrentProperties(ctx context.Context, instanceID int,
 
 // GetTorrentTrackers gets trackers for a specific torrent
 func (sm *SyncManager) GetTorrentTrackers(ctx context.Context, instanceID int, hash string) ([]qbt.TorrentTracker, error) {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return nil, err
+		}
+		return backend.Trackers(ctx, hash)
+	}
+
 	// Get client and sync manager
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -2931,6 +3036,21 @@ func (sm *SyncManager) GetTorrentPieceStates(ctx context.Context, instanceID int
 //   - Cached entries are returned first; only cache misses are fetched concurrently. Empty/whitespace hashes
 //     are ignored defensively.
 func (sm *SyncManager) GetTorrentFilesBatch(ctx context.Context, instanceID int, hashes []string) (map[string]qbt.TorrentFiles, error) {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return nil, err
+		}
+		filesByHash := make(map[string]qbt.TorrentFiles, len(hashes))
+		for _, hash := range hashes {
+			files, err := backend.Files(ctx, hash)
+			if err != nil {
+				return nil, err
+			}
+			filesByHash[canonicalizeHash(hash)] = files
+		}
+		return filesByHash, nil
+	}
+
 	start := time.Now()
 
 	client, err := sm.getTorrentFilesClient(ctx, instanceID)
@@ -3252,6 +3372,13 @@ func (sm *SyncManager) ExportTorrent(ctx context.Context, instanceID int, hash s
 		return nil, "", "", errors.New("torrent hash is required")
 	}
 
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return nil, "", "", err
+		}
+		return sm.backendExportTorrent(ctx, backend, instanceID, hash)
+	}
+
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
 		return nil, "", "", err
@@ -4440,6 +4567,13 @@ func resumeWhenCompleteStopped(state qbt.TorrentState) bool {
 // GetAllTorrents returns the current torrent list for an instance without pagination,
 // with optimistic updates applied.
 func (sm *SyncManager) GetAllTorrents(ctx context.Context, instanceID int) ([]qbt.Torrent, error) {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return nil, err
+		}
+		return backend.Torrents(ctx)
+	}
+
 	// Get client and sync manager
 	client, syncManager, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -6350,6 +6484,13 @@ func (sm *SyncManager) calculateStats(torrents []qbt.Torrent) *TorrentStats {
 
 // AddTags adds tags to the specified torrents (keeps existing tags)
 func (sm *SyncManager) AddTags(ctx context.Context, instanceID int, hashes []string, tags string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return backendAddTags(ctx, backend, hashes, splitBackendTags(tags))
+	}
+
 	// Get client and sync manager
 	client, syncManager, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -6391,6 +6532,13 @@ func (sm *SyncManager) AddTags(ctx context.Context, instanceID int, hashes []str
 
 // RemoveTags removes specific tags from the specified torrents
 func (sm *SyncManager) RemoveTags(ctx context.Context, instanceID int, hashes []string, tags string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return backendRemoveTags(ctx, backend, hashes, splitBackendTags(tags))
+	}
+
 	// Get client and sync manager
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -6415,6 +6563,13 @@ func (sm *SyncManager) RemoveTags(ctx context.Context, instanceID int, hashes []
 // SetTags sets tags on the specified torrents (replaces all existing tags)
 // This uses the new qBittorrent 5.1+ API if available, otherwise falls back to RemoveTags + AddTags
 func (sm *SyncManager) SetTags(ctx context.Context, instanceID int, hashes []string, tags string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return backendSetTags(ctx, backend, hashes, splitBackendTags(tags))
+	}
+
 	client, err := sm.clientPool.GetClient(ctx, instanceID)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %w", err)
@@ -6501,6 +6656,13 @@ func (sm *SyncManager) SetComment(ctx context.Context, instanceID int, hashes []
 
 // SetCategory sets the category for the specified torrents
 func (sm *SyncManager) SetCategory(ctx context.Context, instanceID int, hashes []string, category string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return backend.SetCategory(ctx, hashes, category)
+	}
+
 	// Get client and sync manager
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -6525,6 +6687,13 @@ func (sm *SyncManager) SetCategory(ctx context.Context, instanceID int, hashes [
 
 // SetAutoTMM sets the automatic torrent management for torrents
 func (sm *SyncManager) SetAutoTMM(ctx context.Context, instanceID int, hashes []string, enable bool) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("automatic torrent management on %s instances: %w", backend.Type(), clients.ErrUnsupported)
+	}
+
 	// Get client and sync manager
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -6574,6 +6743,13 @@ func (sm *SyncManager) SetForceStart(ctx context.Context, instanceID int, hashes
 
 // CreateTags creates new tags
 func (sm *SyncManager) CreateTags(ctx context.Context, instanceID int, tags []string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("creating tags on %s instances: %w", backend.Type(), clients.ErrUnsupported)
+	}
+
 	client, err := sm.clientPool.GetClient(ctx, instanceID)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %w", err)
@@ -6591,6 +6767,13 @@ func (sm *SyncManager) CreateTags(ctx context.Context, instanceID int, tags []st
 
 // DeleteTags deletes tags
 func (sm *SyncManager) DeleteTags(ctx context.Context, instanceID int, tags []string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return backendDeleteTags(ctx, backend, tags)
+	}
+
 	client, err := sm.clientPool.GetClient(ctx, instanceID)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %w", err)
@@ -6837,6 +7020,17 @@ func (sm *SyncManager) GetActiveTrackers(ctx context.Context, instanceID int) (m
 // shareLimitsMode (MatchAny / MatchAll) is sent only when SupportsShareLimitsMode (Web API >= 2.16.0).
 // Action and mode must be qBittorrent/Qt meta enum names (Default, Stop, Remove, …).
 func (sm *SyncManager) SetTorrentShareLimit(ctx context.Context, instanceID int, hashes []string, ratioLimit float64, seedingTimeLimit, inactiveSeedingTimeLimit int64, shareLimitAction, shareLimitsMode string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return backend.SetShareLimits(ctx, hashes, clients.ShareLimits{
+			RatioLimit:               ratioLimit,
+			SeedingTimeLimit:         seedingTimeLimit,
+			InactiveSeedingTimeLimit: inactiveSeedingTimeLimit,
+		})
+	}
+
 	// Get client and sync manager
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -6926,6 +7120,13 @@ func (sm *SyncManager) SetTorrentDownloadLimit(ctx context.Context, instanceID i
 
 // SetLocation sets the save location for torrents
 func (sm *SyncManager) SetLocation(ctx context.Context, instanceID int, hashes []string, location string) error {
+	if backend, ok, err := sm.BackendClient(ctx, instanceID); ok {
+		if err != nil {
+			return err
+		}
+		return backend.SetLocation(ctx, hashes, location)
+	}
+
 	// Get client and sync manager
 	client, _, err := sm.getClientAndSyncManager(ctx, instanceID)
 	if err != nil {
@@ -0,0 +1,275 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+const (
+	// transferTickInterval matches the finest transfer stats resolution.
+	transferTickInterval = time.Minute
+	// transferSampleInterval is how often per-torrent counters are sampled.
+	transferSampleInterval = 5 * time.Minute
+	// transferSampleRetention covers the longest history window (7 days) plus slack.
+	transferSampleRetention  = 8 * 24 * time.Hour
+	transferSamplePruneEvery = time.Hour
+)
+
+// transferCounters are a torrent's cumulative counters as of the previous sample.
+type transferCounters struct {
+	uploaded   int64
+	downloaded int64
+}
+
+// TransferSampler reads each torrent's uploaded/downloaded counters from the sync
+// cache and records them twice: into hourly per-torrent buckets, written only when
+// they change, so automations can look at upload over recent windows; and, each
+// minute, as the growth per primary tracker and instance for the transfer stats.
+type TransferSampler struct {
+	syncManager   *SyncManager
+	instanceStore *models.InstanceStore
+	store         *models.TorrentTransferSampleStore
+	stats         *models.TransferStatsStore
+	now           func() time.Time
+
+	mu         sync.Mutex
+	last       map[int]map[string]models.TorrentTransferSample // instanceID -> hash -> newest sample
+	counters   map[int]map[string]transferCounters             // instanceID -> hash -> counters
+	lastSample time.Time
+	lastPrune  time.Time
+}
+
+// NewTransferSampler constructs a TransferSampler. Either store may be nil.
+func NewTransferSampler(syncManager *SyncManager, instanceStore *models.InstanceStore, store *models.TorrentTransferSampleStore, stats *models.TransferStatsStore) *TransferSampler {
+	return &TransferSampler{
+		syncManager:   syncManager,
+		instanceStore: instanceStore,
+		store:         store,
+		stats:         stats,
+		now:           time.Now,
+		last:          make(map[int]map[string]models.TorrentTransferSample),
+		counters:      make(map[int]map[string]transferCounters),
+	}
+}
+
+// Start launches the background sampling loop.
+func (ts *TransferSampler) Start(ctx context.Context) {
+	if ts == nil || ts.syncManager == nil || (ts.store == nil && ts.stats == nil) {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(transferTickInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ts.sampleAll(ctx)
+			}
+		}
+	}()
+}
+
+func (ts *TransferSampler) sampleAll(ctx context.Context) {
+	instances, err := ts.instanceStore.List(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("transfer sampler: failed to list instances")
+		return
+	}
+
+	now := ts.now()
+	sampleTorrents := ts.store != nil && now.Sub(ts.lastSample) >= transferSampleInterval
+	prune := now.Sub(ts.lastPrune) >= transferSamplePruneEvery
+	for _, instance := range instances {
+		if instance == nil || !instance.IsActive {
+			continue
+		}
+		torrents, err := ts.syncManager.GetAllTorrents(ctx, instance.ID)
+		if err != nil {
+			log.Debug().Err(err).Int("instanceID", instance.ID).Msg("transfer sampler: torrents unavailable")
+			continue
+		}
+		// An empty list is more likely a sync hiccup than a cleared client; wait for data.
+		if len(torrents) == 0 {
+			continue
+		}
+
+		if ts.stats != nil {
+			if err := ts.recordStats(ctx, instance.ID, torrents, now); err != nil {
+				log.Warn().Err(err).Int("instanceID", instance.ID).Msg("transfer sampler: failed to record transfer stats")
+			}
+		}
+		if ts.store == nil {
+			continue
+		}
+		if sampleTorrents {
+			if err := ts.sampleInstance(ctx, instance.ID, torrents, now); err != nil {
+				log.Warn().Err(err).Int("instanceID", instance.ID).Msg("transfer sampler: failed to record samples")
+				continue
+			}
+		}
+		if prune {
+			if _, err := ts.store.Prune(ctx, instance.ID, now.Add(-transferSampleRetention).Unix()); err != nil {
+				log.Warn().Err(err).Int("instanceID", instance.ID).Msg("transfer sampler: failed to prune samples")
+			}
+		}
+	}
+
+	if sampleTorrents {
+		ts.lastSample = now
+	}
+	if prune {
+		if ts.stats != nil {
+			if _, err := ts.stats.Prune(ctx, now); err != nil {
+				log.Warn().Err(err).Msg("transfer sampler: failed to prune transfer stats")
+			}
+		}
+		ts.lastPrune = now
+	}
+}
+
+// sampleInstance records changed counters for an instance's torrents and drops the
+// samples of torrents that are gone.
+func (ts *TransferSampler) sampleInstance(ctx context.Context, instanceID int, torrents []qbt.Torrent, now time.Time) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	last, ok := ts.last[instanceID]
+	if !ok {
+		stored, err := ts.store.LatestAt(ctx, instanceID, now.Unix())
+		if err != nil {
+			return err
+		}
+		last = stored
+	}
+
+	samples, gone := diffTransferSamples(last, torrents, now)
+	if err := ts.store.Upsert(ctx, instanceID, samples); err != nil {
+		return err
+	}
+	if err := ts.store.DeleteTorrents(ctx, instanceID, gone); err != nil {
+		return err
+	}
+
+	for _, sample := range samples {
+		last[sample.Hash] = sample
+	}
+	for _, hash := range gone {
+		delete(last, hash)
+	}
+	ts.last[instanceID] = last
+	return nil
+}
+
+// recordStats adds the counter growth since the previous tick to the transfer stats
+// of the instance and its trackers.
+func (ts *TransferSampler) recordStats(ctx context.Context, instanceID int, torrents []qbt.Torrent, now time.Time) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	last, seen := ts.counters[instanceID]
+	samples, counters := aggregateTransferStats(last, seen, torrents, ts.syncManager.primaryTrackerDomain)
+	if err := ts.stats.Record(ctx, instanceID, now, samples); err != nil {
+		return err
+	}
+	ts.counters[instanceID] = counters
+	return nil
+}
+
+// diffTransferSamples returns samples for torrents whose counters changed since last
+// (or that were never sampled) and the hashes in last no longer present in torrents.
+func diffTransferSamples(last map[string]models.TorrentTransferSample, torrents []qbt.Torrent, now time.Time) (samples []models.TorrentTransferSample, gone []string) {
+	bucket := now.Truncate(time.Hour).Unix()
+	nowUnix := now.Unix()
+
+	present := make(map[string]struct{}, len(torrents))
+	for _, torrent := range torrents {
+		present[torrent.Hash] = struct{}{}
+
+		sample := models.TorrentTransferSample{
+			Hash:        torrent.Hash,
+			BucketStart: bucket,
+			Uploaded:    torrent.Uploaded,
+			Downloaded:  torrent.Downloaded,
+			// Without history, assume the torrent just uploaded rather than
+			// guessing it has been idle since it was added.
+			LastUploadAt: nowUnix,
+		}
+		if previous, ok := last[torrent.Hash]; ok {
+			if previous.Uploaded == torrent.Uploaded && previous.Downloaded == torrent.Downloaded {
+				continue
+			}
+			if torrent.Uploaded <= previous.Uploaded {
+				sample.LastUploadAt = previous.LastUploadAt
+			}
+		}
+		samples = append(samples, sample)
+	}
+
+	for hash := range last {
+		if _, ok := present[hash]; !ok {
+			gone = append(gone, hash)
+		}
+	}
+	return samples, gone
+}
+
+// aggregateTransferStats returns the instance total and per-tracker samples for
+// torrents, plus their counters for the next call. Without a previous sample
+// (hasLast false) only torrent counts are recorded: counters accumulated before
+// qui started are not transfer of this minute. Torrents first seen later count
+// from zero, and shrinking counters are treated as no transfer.
+func aggregateTransferStats(last map[string]transferCounters, hasLast bool, torrents []qbt.Torrent, trackerOf func(qbt.Torrent) string) ([]models.TransferStatsSample, map[string]transferCounters) {
+	counters := make(map[string]transferCounters, len(torrents))
+	total := models.TransferStatsSample{}
+	trackers := make(map[string]*models.TransferStatsSample)
+
+	for _, torrent := range torrents {
+		current := transferCounters{uploaded: torrent.Uploaded, downloaded: torrent.Downloaded}
+		counters[torrent.Hash] = current
+
+		var uploaded, downloaded int64
+		if hasLast {
+			previous := last[torrent.Hash]
+			uploaded = max(current.uploaded-previous.uploaded, 0)
+			downloaded = max(current.downloaded-previous.downloaded, 0)
+		}
+
+		total.Uploaded += uploaded
+		total.Downloaded += downloaded
+		total.TorrentCount++
+
+		tracker := trackerOf(torrent)
+		if tracker == "" {
+			continue
+		}
+		sample := trackers[tracker]
+		if sample == nil {
+			sample = &models.TransferStatsSample{Tracker: tracker}
+			trackers[tracker] = sample
+		}
+		sample.Uploaded += uploaded
+		sample.Downloaded += downloaded
+		sample.TorrentCount++
+	}
+
+	samples := make([]models.TransferStatsSample, 0, len(trackers)+1)
+	samples = append(samples, total)
+	for _, tracker := range slices.Sorted(maps.Keys(trackers)) {
+		samples = append(samples, *trackers[tracker])
+	}
+	return samples, counters
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package qbittorrent
+
+import (
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestDiffTransferSamples(t *testing.T) {
+	now := time.Unix(1_700_003_700, 0)
+	bucket := now.Truncate(time.Hour).Unix()
+	earlier := now.Add(-3 * time.Hour).Unix()
+
+	last := map[string]models.TorrentTransferSample{
+		"unchanged":  {Hash: "unchanged", Uploaded: 10, Downloaded: 5, LastUploadAt: earlier},
+		"uploading":  {Hash: "uploading", Uploaded: 10, Downloaded: 5, LastUploadAt: earlier},
+		"downloaded": {Hash: "downloaded", Uploaded: 10, Downloaded: 5, LastUploadAt: earlier},
+		"removed":    {Hash: "removed", Uploaded: 10, LastUploadAt: earlier},
+	}
+	torrents := []qbt.Torrent{
+		{Hash: "unchanged", Uploaded: 10, Downloaded: 5},
+		{Hash: "uploading", Uploaded: 20, Downloaded: 5},
+		{Hash: "downloaded", Uploaded: 10, Downloaded: 50},
+		{Hash: "new", Uploaded: 7},
+	}
+
+	samples, gone := diffTransferSamples(last, torrents, now)
+	assert.Equal(t, []string{"removed"}, gone)
+	require.Len(t, samples, 3)
+
+	byHash := make(map[string]models.TorrentTransferSample, len(samples))
+	for _, sample := range samples {
+		assert.Equal(t, bucket, sample.BucketStart)
+		byHash[sample.Hash] = sample
+	}
+	assert.NotContains(t, byHash, "unchanged")
+	assert.Equal(t, now.Unix(), byHash["uploading"].LastUploadAt)
+	assert.Equal(t, earlier, byHash["downloaded"].LastUploadAt, "download-only changes keep the last upload time")
+	assert.Equal(t, int64(50), byHash["downloaded"].Downloaded)
+	assert.Equal(t, now.Unix(), byHash["new"].LastUploadAt)
+}
+
+func TestAggregateTransferStats(t *testing.T) {
+	trackerOf := func(torrent qbt.Torrent) string { return torrent.Tracker }
+	torrents := []qbt.Torrent{
+		{Hash: "a", Tracker: "tracker.example", Uploaded: 100, Downloaded: 50},
+		{Hash: "b", Tracker: "tracker.example", Uploaded: 30},
+		{Hash: "c", Tracker: "other.example", Uploaded: 5, Downloaded: 5},
+		{Hash: "d", Uploaded: 40},
+	}
+
+	samples, counters := aggregateTransferStats(nil, false, torrents, trackerOf)
+	require.Len(t, samples, 3)
+	assert.Equal(t, models.TransferStatsSample{TorrentCount: 4}, samples[0], "the first sample only records counts")
+	assert.Equal(t, models.TransferStatsSample{Tracker: "other.example", TorrentCount: 1}, samples[1])
+	assert.Equal(t, models.TransferStatsSample{Tracker: "tracker.example", TorrentCount: 2}, samples[2])
+
+	torrents = []qbt.Torrent{
+		{Hash: "a", Tracker: "tracker.example", Uploaded: 160, Downloaded: 50},
+		{Hash: "b", Tracker: "tracker.example", Uploaded: 10},
+		{Hash: "d", Uploaded: 45},
+		{Hash: "e", Tracker: "other.example", Uploaded: 8, Downloaded: 20},
+	}
+	samples, counters = aggregateTransferStats(counters, true, torrents, trackerOf)
+	require.Len(t, samples, 3)
+	assert.Equal(t, models.TransferStatsSample{Uploaded: 73, Downloaded: 20, TorrentCount: 4}, samples[0])
+	assert.Equal(t, models.TransferStatsSample{Tracker: "other.example", Uploaded: 8, Downloaded: 20, TorrentCount: 1}, samples[1], "new torrents count from zero")
+	assert.Equal(t, models.TransferStatsSample{Tracker: "tracker.example", Uploaded: 60, TorrentCount: 2}, samples[2], "shrinking counters add nothing")
+	assert.Len(t, counters, 4)
+}
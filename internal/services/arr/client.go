@@ -22,6 +22,14 @@ import (
 const (
 	defaultTimeout   = 15 * time.Second
 	defaultUserAgent = "qui/1.0"
+
+	// queuePageSize and maxQueuePages bound a full queue fetch.
+	queuePageSize = 250
+	maxQueuePages = 40
+
+	// historyEventDownloadImported is the history event recorded when a completed
+	// download has been imported (same name in Sonarr and Radarr).
+	historyEventDownloadImported = "downloadFolderImported"
 )
 
 // Client is an HTTP client for communicating with Sonarr/Radarr v3 API
@@ -310,6 +318,57 @@ func (c *Client) GetSonarrSeasonEpisodes(ctx context.Context, seriesID, seasonNu
 	return episodes, nil
 }
 
+// GetQueue returns every item in the download queue, following pagination.
+// Items arr cannot map to a series/movie are included so manual grabs still count.
+func (c *Client) GetQueue(ctx context.Context) ([]QueueRecord, error) {
+	params := url.Values{}
+	params.Set("pageSize", strconv.Itoa(queuePageSize))
+	switch c.instanceType {
+	case models.ArrInstanceTypeSonarr:
+		params.Set("includeUnknownSeriesItems", "true")
+	case models.ArrInstanceTypeRadarr:
+		params.Set("includeUnknownMovieItems", "true")
+	default:
+		return nil, fmt.Errorf("unsupported instance type: %s", c.instanceType)
+	}
+
+	var records []QueueRecord
+	for page := 1; page <= maxQueuePages; page++ {
+		params.Set("page", strconv.Itoa(page))
+		var resp QueueResponse
+		if err := c.getJSON(ctx, "/api/v3/queue", params, &resp); err != nil {
+			return nil, err
+		}
+		records = append(records, resp.Records...)
+		if len(resp.Records) == 0 || len(records) >= resp.TotalRecords {
+			break
+		}
+	}
+
+	return records, nil
+}
+
+// GetImportHistorySince returns download-folder import events recorded after since.
+func (c *Client) GetImportHistorySince(ctx context.Context, since time.Time) ([]HistoryRecord, error) {
+	params := url.Values{}
+	params.Set("date", since.UTC().Format(time.RFC3339))
+	params.Set("eventType", historyEventDownloadImported)
+
+	var records []HistoryRecord
+	if err := c.getJSON(ctx, "/api/v3/history/since", params, &records); err != nil {
+		return nil, err
+	}
+
+	// Older versions ignore the eventType filter; keep only import events.
+	imports := records[:0]
+	for _, record := range records {
+		if strings.EqualFold(record.EventType, historyEventDownloadImported) {
+			imports = append(imports, record)
+		}
+	}
+	return imports, nil
+}
+
 func (c *Client) getJSON(ctx context.Context, path string, params url.Values, target any) error {
 	u, err := url.Parse(c.baseURL + path)
 	if err != nil {
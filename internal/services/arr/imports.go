@@ -0,0 +1,207 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package arr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+const (
+	// DefaultImportCacheTTL is how long an instance's queue/history snapshot is reused
+	// before it is refreshed. Automation runs evaluate thousands of torrents against
+	// the same snapshot instead of calling the arr APIs per torrent.
+	DefaultImportCacheTTL = 2 * time.Minute
+
+	// ImportHistoryLookback bounds how far back import history is tracked.
+	// Torrents imported before that are reported as not imported.
+	ImportHistoryLookback = 90 * 24 * time.Hour
+
+	// importHistoryOverlap re-reads a little history on each refresh so events
+	// written while the previous request was in flight are not missed.
+	importHistoryOverlap = time.Minute
+)
+
+// ImportStatus is the merged queue and import-history state of every enabled
+// Sonarr/Radarr instance, keyed by lowercase infohash (the arr download ID).
+type ImportStatus struct {
+	InQueue  map[string]struct{}
+	Imported map[string]time.Time // most recent import per download
+}
+
+// IsInQueue reports whether any arr instance still has hash in its download queue.
+func (s *ImportStatus) IsInQueue(hash string) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.InQueue[normalizeDownloadID(hash)]
+	return ok
+}
+
+// ImportedAt returns when hash was last imported by any arr instance.
+func (s *ImportStatus) ImportedAt(hash string) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+	at, ok := s.Imported[normalizeDownloadID(hash)]
+	return at, ok
+}
+
+// importSnapshot is the cached state of one arr instance.
+type importSnapshot struct {
+	mu        sync.Mutex // serializes refreshes of this instance
+	fetchedAt time.Time
+	inQueue   map[string]struct{}
+	imported  map[string]time.Time
+}
+
+// WithImportCacheTTL sets how long per-instance queue/history snapshots are reused
+func (s *Service) WithImportCacheTTL(ttl time.Duration) *Service {
+	s.importTTL = ttl
+	return s
+}
+
+// ImportStatus returns the queue/import state across all enabled arr instances.
+// Each instance is refreshed at most once per import cache TTL; when a refresh
+// fails the previous snapshot is reused. An error is returned only if some
+// enabled instance has never been fetched successfully, because a partial view
+// would report unimported torrents as safe to remove.
+func (s *Service) ImportStatus(ctx context.Context) (*ImportStatus, error) {
+	instances, err := s.instanceStore.ListEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ImportStatus{
+		InQueue:  make(map[string]struct{}),
+		Imported: make(map[string]time.Time),
+	}
+
+	var errs []error
+	for _, instance := range instances {
+		if err := s.mergeImportSnapshot(ctx, instance, status); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", instance.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return status, nil
+}
+
+// mergeImportSnapshot adds instance's cached state to status, refreshing it when stale.
+func (s *Service) mergeImportSnapshot(ctx context.Context, instance *models.ArrInstance, status *ImportStatus) error {
+	s.importCacheMu.Lock()
+	snapshot := s.importCache[instance.ID]
+	if snapshot == nil {
+		snapshot = &importSnapshot{}
+		s.importCache[instance.ID] = snapshot
+	}
+	s.importCacheMu.Unlock()
+
+	snapshot.mu.Lock()
+	defer snapshot.mu.Unlock()
+
+	now := time.Now()
+	if snapshot.fetchedAt.IsZero() || now.Sub(snapshot.fetchedAt) >= s.importTTL {
+		if err := s.refreshImportSnapshot(ctx, instance, snapshot, now); err != nil {
+			if snapshot.fetchedAt.IsZero() {
+				return err
+			}
+			log.Warn().Err(err).
+				Int("instanceId", instance.ID).
+				Str("instanceName", instance.Name).
+				Time("fetchedAt", snapshot.fetchedAt).
+				Msg("[ARR-IMPORTS] Refresh failed, using previous snapshot")
+		}
+	}
+
+	for hash := range snapshot.inQueue {
+		status.InQueue[hash] = struct{}{}
+	}
+	for hash, at := range snapshot.imported {
+		if at.After(status.Imported[hash]) {
+			status.Imported[hash] = at
+		}
+	}
+	return nil
+}
+
+// refreshImportSnapshot fetches the full queue and the import history written since
+// the previous refresh (or the lookback window on first fetch). Must hold snapshot.mu.
+func (s *Service) refreshImportSnapshot(ctx context.Context, instance *models.ArrInstance, snapshot *importSnapshot, now time.Time) error {
+	client := s.clientForInstance(instance)
+	if client == nil {
+		return errors.New("failed to build client")
+	}
+
+	queue, err := client.GetQueue(ctx)
+	if err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+
+	cutoff := now.Add(-ImportHistoryLookback)
+	since := cutoff
+	if !snapshot.fetchedAt.IsZero() {
+		since = snapshot.fetchedAt.Add(-importHistoryOverlap)
+	}
+	history, err := client.GetImportHistorySince(ctx, since)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+
+	inQueue := make(map[string]struct{}, len(queue))
+	for _, record := range queue {
+		if id := normalizeDownloadID(record.DownloadID); id != "" {
+			inQueue[id] = struct{}{}
+		}
+	}
+
+	imported := snapshot.imported
+	if imported == nil {
+		imported = make(map[string]time.Time, len(history))
+	}
+	for _, record := range history {
+		id := normalizeDownloadID(record.DownloadID)
+		if id == "" {
+			continue
+		}
+		if record.Date.After(imported[id]) {
+			imported[id] = record.Date
+		}
+	}
+	for id, at := range imported {
+		if at.Before(cutoff) {
+			delete(imported, id)
+		}
+	}
+
+	snapshot.inQueue = inQueue
+	snapshot.imported = imported
+	snapshot.fetchedAt = now
+
+	log.Debug().
+		Int("instanceId", instance.ID).
+		Str("instanceName", instance.Name).
+		Int("queued", len(inQueue)).
+		Int("imported", len(imported)).
+		Int("newImports", len(history)).
+		Msg("[ARR-IMPORTS] Refreshed queue and import history")
+
+	return nil
+}
+
+// normalizeDownloadID maps an arr download ID or qBittorrent hash to the shared key form.
+func normalizeDownloadID(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
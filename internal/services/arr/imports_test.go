@@ -0,0 +1,109 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package arr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestClient_GetQueuePaginates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v3/queue", r.URL.Path)
+		require.Equal(t, "true", r.URL.Query().Get("includeUnknownSeriesItems"))
+		switch r.URL.Query().Get("page") {
+		case "1":
+			_, _ = w.Write([]byte(`{"page":1,"pageSize":2,"totalRecords":3,"records":[{"downloadId":"AAA"},{"downloadId":"BBB"}]}`))
+		case "2":
+			_, _ = w.Write([]byte(`{"page":2,"pageSize":2,"totalRecords":3,"records":[{"downloadId":"CCC"}]}`))
+		default:
+			t.Fatalf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", nil, nil, models.ArrInstanceTypeSonarr, 0)
+	records, err := client.GetQueue(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "CCC", records[2].DownloadID)
+}
+
+func TestClient_GetImportHistorySinceFiltersEvents(t *testing.T) {
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v3/history/since", r.URL.Path)
+		require.Equal(t, "2026-01-02T03:04:05Z", r.URL.Query().Get("date"))
+		require.Equal(t, historyEventDownloadImported, r.URL.Query().Get("eventType"))
+		_, _ = w.Write([]byte(`[
+			{"downloadId":"AAA","eventType":"grabbed","date":"2026-01-03T00:00:00Z"},
+			{"downloadId":"AAA","eventType":"downloadFolderImported","date":"2026-01-04T00:00:00Z"}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", nil, nil, models.ArrInstanceTypeRadarr, 0)
+	records, err := client.GetImportHistorySince(context.Background(), since)
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, historyEventDownloadImported, records[0].EventType)
+}
+
+func TestService_ImportStatusCachesPerInstance(t *testing.T) {
+	importedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	var queueCalls, historyCalls atomic.Int32
+
+	service, _ := newArrLookupTestService(t, models.ArrInstanceTypeRadarr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/queue":
+			queueCalls.Add(1)
+			_, _ = w.Write([]byte(`{"page":1,"pageSize":250,"totalRecords":1,"records":[{"downloadId":"ABCDEF"}]}`))
+		case "/api/v3/history/since":
+			historyCalls.Add(1)
+			_, _ = fmt.Fprintf(w, `[{"downloadId":"123456","eventType":"downloadFolderImported","date":%q}]`, importedAt.Format(time.RFC3339))
+		default:
+			t.Fatalf("unexpected ARR request: %s", r.URL.Path)
+		}
+	}))
+
+	status, err := service.ImportStatus(context.Background())
+	require.NoError(t, err)
+	assert.True(t, status.IsInQueue("abcdef"))
+	assert.False(t, status.IsInQueue("123456"))
+	at, ok := status.ImportedAt("123456")
+	require.True(t, ok)
+	assert.True(t, at.Equal(importedAt))
+
+	_, err = service.ImportStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), queueCalls.Load(), "second call within TTL should use the cached snapshot")
+	assert.Equal(t, int32(1), historyCalls.Load())
+
+	service.WithImportCacheTTL(0)
+	_, err = service.ImportStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), queueCalls.Load())
+}
+
+func TestService_ImportStatusErrorsWithoutSnapshot(t *testing.T) {
+	service, _ := newArrLookupTestService(t, models.ArrInstanceTypeSonarr, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	status, err := service.ImportStatus(context.Background())
+	require.Error(t, err)
+	assert.Nil(t, status)
+}
@@ -5,6 +5,7 @@ package arr
 
 import (
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/autobrr/qui/internal/models"
@@ -16,6 +17,31 @@ type SystemStatusResponse struct {
 	Version string `json:"version"`
 }
 
+// QueueResponse represents one page of /api/v3/queue (both Sonarr and Radarr)
+type QueueResponse struct {
+	Page         int           `json:"page"`
+	PageSize     int           `json:"pageSize"`
+	TotalRecords int           `json:"totalRecords"`
+	Records      []QueueRecord `json:"records"`
+}
+
+// QueueRecord is the subset of queue item fields needed to match torrents by infohash
+type QueueRecord struct {
+	ID                   int    `json:"id"`
+	Title                string `json:"title"`
+	DownloadID           string `json:"downloadId"`
+	Status               string `json:"status"`
+	TrackedDownloadState string `json:"trackedDownloadState"`
+}
+
+// HistoryRecord is the subset of /api/v3/history/since fields needed to track imports
+type HistoryRecord struct {
+	ID         int       `json:"id"`
+	DownloadID string    `json:"downloadId"`
+	EventType  string    `json:"eventType"`
+	Date       time.Time `json:"date"`
+}
+
 // SonarrParseResponse represents the response from Sonarr's /api/v3/parse endpoint
 type SonarrParseResponse struct {
 	Title             string                   `json:"title"`
@@ -70,6 +70,11 @@ type Service struct {
 	// Cache cleanup scheduling
 	cleanupMu        sync.Mutex
 	nextCacheCleanup time.Time
+
+	// Per-instance queue/import history snapshots
+	importTTL     time.Duration
+	importCacheMu sync.Mutex
+	importCache   map[int]*importSnapshot
 }
 
 // NewService creates a new ARR service
@@ -79,6 +84,8 @@ func NewService(instanceStore *models.ArrInstanceStore, cacheStore *models.ArrID
 		cacheStore:    cacheStore,
 		positiveTTL:   DefaultPositiveCacheTTL,
 		negativeTTL:   DefaultNegativeCacheTTL,
+		importTTL:     DefaultImportCacheTTL,
+		importCache:   make(map[int]*importSnapshot),
 	}
 }
 
@@ -0,0 +1,146 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package audit records mutating API and proxy calls to the audit log.
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+const (
+	defaultQueueSize = 512
+	pruneInterval    = 6 * time.Hour
+	writeTimeout     = 5 * time.Second
+)
+
+// Recorder accepts audit entries. Implementations must not block the caller.
+type Recorder interface {
+	Record(entry *models.AuditEntry)
+}
+
+type Service struct {
+	store     *models.AuditStore
+	logger    zerolog.Logger
+	queue     chan *models.AuditEntry
+	startOnce sync.Once
+
+	mu       sync.RWMutex
+	settings models.AuditSettings
+}
+
+func NewService(store *models.AuditStore, logger zerolog.Logger) *Service {
+	if store == nil {
+		return nil
+	}
+
+	return &Service{
+		store:    store,
+		logger:   logger,
+		queue:    make(chan *models.AuditEntry, defaultQueueSize),
+		settings: models.AuditSettings{Enabled: true, RetentionDays: models.DefaultAuditRetentionDays},
+	}
+}
+
+// Start loads the settings and starts the writer and retention workers.
+func (s *Service) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+
+	s.startOnce.Do(func() {
+		if settings, err := s.store.GetSettings(ctx); err != nil {
+			s.logger.Error().Err(err).Msg("audit: failed to load settings")
+		} else {
+			s.setSettings(settings)
+		}
+
+		go s.writer(ctx)
+		go s.pruner(ctx)
+	})
+}
+
+// Record queues entry for writing. Entries are dropped when auditing is
+// disabled or the queue is full.
+func (s *Service) Record(entry *models.AuditEntry) {
+	if s == nil || entry == nil || !s.Settings().Enabled {
+		return
+	}
+
+	select {
+	case s.queue <- entry:
+	default:
+		s.logger.Warn().Str("endpoint", entry.Endpoint).Msg("audit: queue full, dropping entry")
+	}
+}
+
+// Settings returns the cached audit settings.
+func (s *Service) Settings() models.AuditSettings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings
+}
+
+// UpdateSettings persists settings and applies them immediately.
+func (s *Service) UpdateSettings(ctx context.Context, settings *models.AuditSettings) (*models.AuditSettings, error) {
+	updated, err := s.store.UpdateSettings(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	s.setSettings(updated)
+	s.prune(ctx)
+	return updated, nil
+}
+
+func (s *Service) setSettings(settings *models.AuditSettings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settings = *settings
+}
+
+func (s *Service) writer(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case entry := <-s.queue:
+			writeCtx, cancel := context.WithTimeout(ctx, writeTimeout)
+			if err := s.store.Create(writeCtx, entry); err != nil {
+				s.logger.Error().Err(err).Str("endpoint", entry.Endpoint).Msg("audit: failed to write entry")
+			}
+			cancel()
+		}
+	}
+}
+
+func (s *Service) pruner(ctx context.Context) {
+	s.prune(ctx)
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.prune(ctx)
+		}
+	}
+}
+
+func (s *Service) prune(ctx context.Context) {
+	deleted, err := s.store.Prune(ctx, s.Settings().RetentionDays)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("audit: failed to prune entries")
+		return
+	}
+	if deleted > 0 {
+		s.logger.Debug().Int64("deleted", deleted).Msg("audit: pruned entries")
+	}
+}
@@ -121,11 +121,32 @@ const (
 	FieldExistsOnSameInstance   = models.FieldExistsOnSameInstance
 	FieldSeedingOnSameInstance  = models.FieldSeedingOnSameInstance
 	FieldCrossSeedTags          = models.FieldCrossSeedTags
+	FieldArrImported            = models.FieldArrImported
+	FieldArrInQueue             = models.FieldArrInQueue
+
+	// Sonarr/Radarr import age
+	FieldArrImportAge = models.FieldArrImportAge
+
+	// Tracker seeding requirements
+	FieldHnRSatisfied        = models.FieldHnRSatisfied
+	FieldHnRRemainingSeconds = models.FieldHnRRemainingSeconds
+
+	// Rule history fields
+	FieldRuleMatchStreak  = models.FieldRuleMatchStreak
+	FieldConditionTrueFor = models.FieldConditionTrueFor
+
+	// Transfer history fields
+	FieldUploadedLast24h = models.FieldUploadedLast24h
+	FieldUploadedLast7d  = models.FieldUploadedLast7d
+	FieldIdleUploadDays  = models.FieldIdleUploadDays
 
 	// Enum-like fields
 	FieldHardlinkScope      = models.FieldHardlinkScope
 	FieldHardlinkScopeCross = models.FieldHardlinkScopeCross
 
+	// Expression field
+	FieldExpr = models.FieldExpr
+
 	// Hardlink scope values
 	HardlinkScopeNone               = models.HardlinkScopeNone
 	HardlinkScopeTorrentsOnly       = models.HardlinkScopeTorrentsOnly
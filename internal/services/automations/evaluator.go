@@ -18,6 +18,7 @@ import (
 
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/qbittorrent"
+	"github.com/autobrr/qui/internal/services/arr"
 	"github.com/autobrr/qui/pkg/releases"
 	"github.com/autobrr/qui/pkg/stringutils"
 )
@@ -123,6 +124,24 @@ type EvalContext struct {
 	// its same-instance cross-seeds (self excluded). Built when rules use CROSS_SEED_TAGS.
 	SameInstanceCrossSeedTagsByHash map[string][]string
 
+	// ArrImports holds the Sonarr/Radarr queue and import history keyed by infohash.
+	// Built when rules use ARR_* fields; nil when unavailable, in which case those fields never match.
+	ArrImports *arr.ImportStatus
+
+	// TrackerRequirements resolves tracker domains to seeding requirements.
+	// Built when rules use HNR_* fields; nil when unavailable, in which case those fields never match.
+	TrackerRequirements *models.TrackerRequirementIndex
+
+	// RuleHistory holds this run's match streaks keyed by rule ID, then torrent hash.
+	// Built when rules use RULE_MATCH_STREAK/CONDITION_TRUE_FOR; a rule missing from
+	// the map has unknown history and those fields never match.
+	RuleHistory map[int]map[string]ruleStreak
+
+	// TransferHistory holds sampled transfer counters for the instance.
+	// Built when rules use UPLOADED_LAST_*/IDLE_UPLOAD_DAYS; nil when unavailable,
+	// in which case those fields never match.
+	TransferHistory *transferHistory
+
 	// TrackerDisplayNameByDomain maps lowercase tracker domains to their display names.
 	// Used for UseTrackerAsTag with UseDisplayName option.
 	TrackerDisplayNameByDomain map[string]string
@@ -277,6 +296,9 @@ func ConditionUsesField(cond *RuleCondition, field ConditionField) bool {
 	if cond.Field == field {
 		return true
 	}
+	if cond.Field == FieldExpr && exprUsesField(cond.Value, field) {
+		return true
+	}
 	for _, child := range cond.Conditions {
 		if ConditionUsesField(child, field) {
 			return true
@@ -301,8 +323,8 @@ func EvaluateConditionWithContext(cond *RuleCondition, torrent qbt.Torrent, ctx
 	}
 
 	// Compile regex if needed, but skip for EXISTS_IN/CONTAINS_IN operators
-	// (cond.Value is a category name, not a pattern)
-	if cond.Operator != OperatorExistsIn && cond.Operator != OperatorContainsIn {
+	// (cond.Value is a category name, not a pattern) and EXPR (cond.Value is an expression)
+	if cond.Field != FieldExpr && cond.Operator != OperatorExistsIn && cond.Operator != OperatorContainsIn {
 		if cond.Regex || cond.Operator == OperatorMatches {
 			if err := cond.CompileRegex(); err != nil {
 				log.Debug().
@@ -651,6 +673,79 @@ func evaluateLeaf(cond *RuleCondition, torrent qbt.Torrent, ctx *EvalContext) bo
 		}
 		return compareBool(seeding, cond)
 
+	case FieldArrImported:
+		// Unknown import state never matches, not even "is not imported",
+		// so delete rules can't remove torrents arr hasn't confirmed.
+		if ctx == nil || ctx.ArrImports == nil {
+			return false
+		}
+		_, imported := ctx.ArrImports.ImportedAt(torrent.Hash)
+		return compareBool(imported, cond)
+
+	case FieldArrInQueue:
+		if ctx == nil || ctx.ArrImports == nil {
+			return false
+		}
+		return compareBool(ctx.ArrImports.IsInQueue(torrent.Hash), cond)
+
+	case FieldArrImportAge:
+		if ctx == nil || ctx.ArrImports == nil {
+			return false
+		}
+		importedAt, ok := ctx.ArrImports.ImportedAt(torrent.Hash)
+		if !ok {
+			return false
+		}
+		return compareAgeIfSet(importedAt.Unix(), cond, ctx)
+
+	case FieldHnRSatisfied:
+		// Unknown requirements never match, so "satisfied" gates on deletes stay closed.
+		status, ok := torrentHnRStatus(torrent, ctx)
+		if !ok {
+			return false
+		}
+		return compareBool(status.Satisfied, cond)
+
+	case FieldHnRRemainingSeconds:
+		status, ok := torrentHnRStatus(torrent, ctx)
+		if !ok || status.RemainingSeconds < 0 {
+			return false
+		}
+		return compareInt64(status.RemainingSeconds, cond)
+
+	case FieldRuleMatchStreak:
+		// Unknown history never matches, not even "streak = 0".
+		streak, ok := ruleMatchStreak(torrent, ctx)
+		if !ok {
+			return false
+		}
+		return compareInt64(int64(streak.MatchCount), cond)
+
+	case FieldConditionTrueFor:
+		streak, ok := ruleMatchStreak(torrent, ctx)
+		if !ok {
+			return false
+		}
+		return compareInt64(streak.trueForSeconds(), cond)
+
+	case FieldUploadedLast24h, FieldUploadedLast7d:
+		// Unknown history never matches, so "uploaded less than" gates on deletes stay closed.
+		uploaded, ok := torrentUploadedLast(torrent, ctx, cond.Field)
+		if !ok {
+			return false
+		}
+		return compareInt64(uploaded, cond)
+
+	case FieldIdleUploadDays:
+		days, ok := torrentIdleUploadDays(torrent, ctx)
+		if !ok {
+			return false
+		}
+		return compareFloat64(days, cond)
+
+	case FieldExpr:
+		return evaluateExpr(cond, torrent, ctx)
+
 	case FieldCrossSeedTags:
 		// Tags across this torrent and its same-instance cross-seeds: positive
 		// operators match when ANY copy carries a matching tag, NOT_* when none
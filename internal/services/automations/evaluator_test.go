@@ -10,6 +10,7 @@ import (
 	qbt "github.com/autobrr/go-qbittorrent"
 
 	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/arr"
 	"github.com/autobrr/qui/pkg/releases"
 )
 
@@ -3821,3 +3822,40 @@ func TestEvaluateCondition_CrossSeedTags(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateCondition_ArrImportFields(t *testing.T) {
+	const now = int64(1_700_000_000)
+	ctx := &EvalContext{
+		NowUnix: now,
+		ArrImports: &arr.ImportStatus{
+			InQueue:  map[string]struct{}{"queued": {}},
+			Imported: map[string]time.Time{"imported": time.Unix(now-2*3600, 0)},
+		},
+	}
+	imported := qbt.Torrent{Hash: "IMPORTED"}
+	queued := qbt.Torrent{Hash: "queued"}
+
+	tests := []struct {
+		name     string
+		cond     *RuleCondition
+		torrent  qbt.Torrent
+		ctx      *EvalContext
+		expected bool
+	}{
+		{name: "imported", cond: &RuleCondition{Field: FieldArrImported, Operator: OperatorEqual, Value: "true"}, torrent: imported, ctx: ctx, expected: true},
+		{name: "queued not imported", cond: &RuleCondition{Field: FieldArrImported, Operator: OperatorEqual, Value: "false"}, torrent: queued, ctx: ctx, expected: true},
+		{name: "in queue", cond: &RuleCondition{Field: FieldArrInQueue, Operator: OperatorEqual, Value: "true"}, torrent: queued, ctx: ctx, expected: true},
+		{name: "imported not in queue", cond: &RuleCondition{Field: FieldArrInQueue, Operator: OperatorEqual, Value: "true"}, torrent: imported, ctx: ctx, expected: false},
+		{name: "import age", cond: &RuleCondition{Field: FieldArrImportAge, Operator: OperatorGreaterThan, Value: "3600"}, torrent: imported, ctx: ctx, expected: true},
+		{name: "import age not imported", cond: &RuleCondition{Field: FieldArrImportAge, Operator: OperatorGreaterThan, Value: "0"}, torrent: queued, ctx: ctx, expected: false},
+		{name: "unknown state never matches", cond: &RuleCondition{Field: FieldArrImported, Operator: OperatorEqual, Value: "false"}, torrent: queued, ctx: &EvalContext{}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EvaluateConditionWithContext(tt.cond, tt.torrent, tt.ctx, 0); got != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/ttlcache"
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+	"github.com/expr-lang/expr/vm"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+const exprProgramTTL = 5 * time.Minute
+
+// ExprEnv is the environment EXPR conditions are evaluated against. It embeds the
+// raw qBittorrent torrent (so `Ratio > 2 && Category == "tv"` works as in the
+// torrent list filter) and adds the derived values the evaluator computes for
+// the dedicated condition fields.
+type ExprEnv struct {
+	qbt.Torrent
+
+	// Release metadata parsed from the torrent name.
+	ContentType   string
+	EffectiveName string
+	RlsSource     string
+	RlsResolution string
+	RlsCodec      string
+	RlsHDR        string
+	RlsAudio      string
+	RlsChannels   string
+	RlsGroup      string
+	RlsYear       int64
+
+	// Ages in seconds; -1 when the underlying timestamp is unset.
+	AddedOnAge      int64
+	CompletionOnAge int64
+	LastActivityAge int64
+
+	// Filesystem facts. Empty/false when unknown or without local access.
+	HardlinkScope      string
+	HardlinkScopeCross string
+	HasMissingFiles    bool
+
+	IsUnregistered         bool
+	ExistsOnOtherInstance  bool
+	SeedingOnOtherInstance bool
+	ExistsOnSameInstance   bool
+	SeedingOnSameInstance  bool
+
+	// Sonarr/Radarr state. ArrImportAge is -1 when not imported or unknown.
+	ArrImported  bool
+	ArrInQueue   bool
+	ArrImportAge int64
+
+	// Tracker seeding requirements. HnRRemainingSeconds is -1 when unknown or
+	// when only reaching the ratio can satisfy the requirement.
+	HnRSatisfied        bool
+	HnRRemainingSeconds int64
+
+	// Sampled upload history. UploadedLast24h, UploadedLast7d and IdleUploadDays
+	// are -1 when the torrent's history doesn't reach back far enough.
+	UploadedLast24h int64
+	UploadedLast7d  int64
+	IdleUploadDays  float64
+
+	// FreeSpace is the projected free space in bytes, including space already
+	// scheduled to be cleared by earlier matches in this run.
+	FreeSpace int64
+}
+
+// exprIdentifierFields maps ExprEnv members backed by lazily built EvalContext
+// data to the condition field that makes the service build that data. An
+// expression referencing one of them counts as using the field.
+var exprIdentifierFields = map[string]ConditionField{
+	"ContentType":            FieldContentType,
+	"EffectiveName":          FieldEffectiveName,
+	"RlsSource":              FieldRlsSource,
+	"RlsResolution":          FieldRlsResolution,
+	"RlsCodec":               FieldRlsCodec,
+	"RlsHDR":                 FieldRlsHDR,
+	"RlsAudio":               FieldRlsAudio,
+	"RlsChannels":            FieldRlsChannels,
+	"RlsGroup":               FieldRlsGroup,
+	"RlsYear":                FieldRlsYear,
+	"HardlinkScope":          FieldHardlinkScope,
+	"HardlinkScopeCross":     FieldHardlinkScopeCross,
+	"HasMissingFiles":        FieldHasMissingFiles,
+	"IsUnregistered":         FieldIsUnregistered,
+	"ExistsOnOtherInstance":  FieldExistsOnOtherInstance,
+	"SeedingOnOtherInstance": FieldSeedingOnOtherInstance,
+	"ExistsOnSameInstance":   FieldExistsOnSameInstance,
+	"SeedingOnSameInstance":  FieldSeedingOnSameInstance,
+	"FreeSpace":              FieldFreeSpace,
+	"ArrImported":            FieldArrImported,
+	"ArrInQueue":             FieldArrInQueue,
+	"ArrImportAge":           FieldArrImportAge,
+	"HnRSatisfied":           FieldHnRSatisfied,
+	"HnRRemainingSeconds":    FieldHnRRemainingSeconds,
+	"UploadedLast24h":        FieldUploadedLast24h,
+	"UploadedLast7d":         FieldUploadedLast7d,
+	"IdleUploadDays":         FieldIdleUploadDays,
+}
+
+// compiledExpr is a compiled EXPR condition plus the derived fields it reads.
+type compiledExpr struct {
+	program *vm.Program
+	uses    map[ConditionField]struct{}
+}
+
+func (c *compiledExpr) usesField(field ConditionField) bool {
+	_, ok := c.uses[field]
+	return ok
+}
+
+// exprPrograms caches compiled programs per rule and expression source, so an
+// edited rule never runs a stale program.
+var exprPrograms = ttlcache.New(ttlcache.Options[string, *compiledExpr]{}.SetDefaultTTL(exprProgramTTL))
+
+// CompileExpr compiles an EXPR condition source against ExprEnv. The expression
+// must evaluate to a boolean.
+func CompileExpr(source string) error {
+	_, err := compileExpr(source)
+	return err
+}
+
+func compileExpr(source string) (*compiledExpr, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, errors.New("expression is empty")
+	}
+
+	program, err := expr.Compile(source, expr.Env(ExprEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := parser.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	collector := &exprIdentifierCollector{uses: make(map[ConditionField]struct{})}
+	ast.Walk(&tree.Node, collector)
+
+	return &compiledExpr{program: program, uses: collector.uses}, nil
+}
+
+type exprIdentifierCollector struct {
+	uses map[ConditionField]struct{}
+}
+
+func (c *exprIdentifierCollector) Visit(node *ast.Node) {
+	ident, ok := (*node).(*ast.IdentifierNode)
+	if !ok {
+		return
+	}
+	if field, ok := exprIdentifierFields[ident.Value]; ok {
+		c.uses[field] = struct{}{}
+	}
+}
+
+// cachedExpr returns the compiled program for source, compiling it on a cache miss.
+func cachedExpr(ruleID int, source string) (*compiledExpr, error) {
+	key := strconv.Itoa(ruleID) + "|" + source
+	if compiled, ok := exprPrograms.Get(key); ok {
+		return compiled, nil
+	}
+
+	compiled, err := compileExpr(source)
+	if err != nil {
+		return nil, err
+	}
+	if ok := exprPrograms.Set(key, compiled, exprProgramTTL); !ok {
+		log.Trace().Int("ruleID", ruleID).Msg("automations: failed to cache expression")
+	}
+	return compiled, nil
+}
+
+// exprUsesField reports whether an EXPR source reads a member backed by field.
+// Sources that fail to compile use nothing.
+func exprUsesField(source string, field ConditionField) bool {
+	compiled, err := cachedExpr(0, source)
+	if err != nil {
+		return false
+	}
+	return compiled.usesField(field)
+}
+
+// evaluateExpr runs an EXPR condition against torrent. Compile and runtime
+// errors never match.
+func evaluateExpr(cond *RuleCondition, torrent qbt.Torrent, ctx *EvalContext) bool {
+	ruleID := 0
+	if ctx != nil {
+		ruleID = ctx.ActiveRuleID
+	}
+
+	compiled, err := cachedExpr(ruleID, cond.Value)
+	if err != nil {
+		log.Debug().Err(err).Int("ruleID", ruleID).Str("expr", cond.Value).Msg("automations: expression compilation failed")
+		return false
+	}
+
+	output, err := expr.Run(compiled.program, buildExprEnv(torrent, ctx, compiled))
+	if err != nil {
+		log.Debug().Err(err).Int("ruleID", ruleID).Str("hash", torrent.Hash).Str("expr", cond.Value).Msg("automations: expression evaluation failed")
+		return false
+	}
+	matched, ok := output.(bool)
+	return ok && matched
+}
+
+// buildExprEnv fills the derived members the compiled expression reads. Ages
+// are always set; everything backed by EvalContext data is filled on demand.
+func buildExprEnv(torrent qbt.Torrent, ctx *EvalContext, compiled *compiledExpr) ExprEnv {
+	nowUnix := evaluateTime(ctx).Unix()
+	env := ExprEnv{
+		Torrent:             torrent,
+		AddedOnAge:          exprAge(torrent.AddedOn, nowUnix),
+		CompletionOnAge:     exprAge(qbittorrent.NormalizeCompletionTimestamp(torrent.CompletionOn), nowUnix),
+		LastActivityAge:     exprAge(torrent.LastActivity, nowUnix),
+		ArrImportAge:        -1,
+		HnRRemainingSeconds: -1,
+		UploadedLast24h:     -1,
+		UploadedLast7d:      -1,
+		IdleUploadDays:      -1,
+	}
+
+	if compiled.usesField(FieldContentType) {
+		env.ContentType = torrentContentType(torrent, ctx)
+	}
+	if compiled.usesField(FieldEffectiveName) {
+		env.EffectiveName = torrentEffectiveName(torrent, ctx)
+	}
+	if compiled.usesField(FieldRlsSource) {
+		env.RlsSource = torrentRlsSource(torrent, ctx)
+	}
+	if compiled.usesField(FieldRlsResolution) {
+		env.RlsResolution = torrentRlsResolution(torrent, ctx)
+	}
+	if compiled.usesField(FieldRlsCodec) {
+		env.RlsCodec = torrentRlsCodec(torrent, ctx)
+	}
+	if compiled.usesField(FieldRlsHDR) {
+		env.RlsHDR = torrentRlsHDR(torrent, ctx)
+	}
+	if compiled.usesField(FieldRlsAudio) {
+		env.RlsAudio = torrentRlsAudio(torrent, ctx)
+	}
+	if compiled.usesField(FieldRlsChannels) {
+		env.RlsChannels = torrentRlsChannels(torrent, ctx)
+	}
+	if compiled.usesField(FieldRlsGroup) {
+		env.RlsGroup = torrentRlsGroup(torrent, ctx)
+	}
+	if compiled.usesField(FieldRlsYear) {
+		env.RlsYear = torrentRlsYear(torrent, ctx)
+	}
+
+	if ctx == nil {
+		return env
+	}
+
+	if ctx.InstanceHasLocalAccess {
+		env.HardlinkScope = ctx.HardlinkScopeByHash[torrent.Hash]
+		env.HardlinkScopeCross = ctx.HardlinkCrossScopeByHash[torrent.Hash]
+		env.HasMissingFiles = ctx.HasMissingFilesByHash[torrent.Hash]
+	}
+	_, env.IsUnregistered = ctx.UnregisteredSet[torrent.Hash]
+	_, env.ExistsOnOtherInstance = ctx.CrossInstanceHashSet[torrent.Hash]
+	_, env.SeedingOnOtherInstance = ctx.CrossInstanceSeedingHashSet[torrent.Hash]
+	_, env.ExistsOnSameInstance = ctx.SameInstanceCrossSeedHashSet[torrent.Hash]
+	_, env.SeedingOnSameInstance = ctx.SameInstanceCrossSeedSeedingHashSet[torrent.Hash]
+	env.FreeSpace = ctx.FreeSpace + ctx.SpaceToClear
+	if importedAt, ok := ctx.ArrImports.ImportedAt(torrent.Hash); ok {
+		env.ArrImported = true
+		env.ArrImportAge = exprAge(importedAt.Unix(), nowUnix)
+	}
+	env.ArrInQueue = ctx.ArrImports.IsInQueue(torrent.Hash)
+	if status, ok := torrentHnRStatus(torrent, ctx); ok {
+		env.HnRSatisfied = status.Satisfied
+		env.HnRRemainingSeconds = status.RemainingSeconds
+	}
+	if uploaded, ok := torrentUploadedLast(torrent, ctx, FieldUploadedLast24h); ok {
+		env.UploadedLast24h = uploaded
+	}
+	if uploaded, ok := torrentUploadedLast(torrent, ctx, FieldUploadedLast7d); ok {
+		env.UploadedLast7d = uploaded
+	}
+	if days, ok := torrentIdleUploadDays(torrent, ctx); ok {
+		env.IdleUploadDays = days
+	}
+
+	return env
+}
+
+func exprAge(timestamp, nowUnix int64) int64 {
+	if timestamp <= 0 {
+		return -1
+	}
+	return max(nowUnix-timestamp, 0)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+
+	"github.com/autobrr/qui/pkg/releases"
+)
+
+func TestCompileExpr(t *testing.T) {
+	valid := []string{
+		`Ratio > 2 && Category == "tv"`,
+		`AddedOnAge > 86400 && HardlinkScope == "none"`,
+		`RlsResolution in ["1080P", "2160P"] || ExistsOnOtherInstance`,
+		`FreeSpace < 100 * 1024 * 1024 * 1024 and not IsUnregistered`,
+	}
+	for _, source := range valid {
+		if err := CompileExpr(source); err != nil {
+			t.Fatalf("CompileExpr(%q) unexpected error: %v", source, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		`Ratio +`,
+		`Ratio * 2`,         // not a boolean
+		`NoSuchField == 1`,  // unknown identifier
+		`Category > 1 && 1`, // type mismatch
+	}
+	for _, source := range invalid {
+		if err := CompileExpr(source); err == nil {
+			t.Fatalf("CompileExpr(%q) expected error", source)
+		}
+	}
+}
+
+func TestEvaluateCondition_Expr(t *testing.T) {
+	const now = int64(1_700_000_000)
+	torrent := qbt.Torrent{
+		Hash:     "abc",
+		Name:     "Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+		Category: "tv",
+		Ratio:    2.5,
+		AddedOn:  now - 3*86400,
+	}
+	ctx := &EvalContext{
+		NowUnix:                now,
+		ReleaseParser:          releases.NewDefaultParser(),
+		InstanceHasLocalAccess: true,
+		HardlinkScopeByHash:    map[string]string{"abc": HardlinkScopeNone},
+		CrossInstanceHashSet:   map[string]struct{}{"abc": {}},
+		UnregisteredSet:        map[string]struct{}{},
+		FreeSpace:              10,
+		SpaceToClear:           5,
+	}
+
+	tests := []struct {
+		name     string
+		expr     string
+		negate   bool
+		ctx      *EvalContext
+		expected bool
+	}{
+		{name: "torrent fields", expr: `Ratio > 2 && Category == "tv"`, ctx: ctx, expected: true},
+		{name: "age", expr: `AddedOnAge >= 3 * 86400 && CompletionOnAge == -1`, ctx: ctx, expected: true},
+		{name: "release fields", expr: `RlsResolution == "1080P"`, ctx: ctx, expected: true},
+		{name: "hardlink scope", expr: `HardlinkScope == "none"`, ctx: ctx, expected: true},
+		{name: "cross instance", expr: `ExistsOnOtherInstance && !IsUnregistered`, ctx: ctx, expected: true},
+		{name: "projected free space", expr: `FreeSpace == 15`, ctx: ctx, expected: true},
+		{name: "negated", expr: `Ratio > 2`, negate: true, ctx: ctx, expected: false},
+		{name: "no local access hides hardlink scope", expr: `HardlinkScope == "none"`, ctx: &EvalContext{HardlinkScopeByHash: ctx.HardlinkScopeByHash}, expected: false},
+		{name: "nil context", expr: `Ratio > 2`, ctx: nil, expected: true},
+		{name: "invalid expression never matches", expr: `Ratio +`, ctx: ctx, expected: false},
+		{name: "invalid expression negated still evaluates", expr: `Ratio +`, negate: true, ctx: ctx, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond := &RuleCondition{Field: FieldExpr, Operator: OperatorEqual, Value: tt.expr, Negate: tt.negate}
+			if got := EvaluateConditionWithContext(cond, torrent, tt.ctx, 0); got != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConditionUsesField_Expr(t *testing.T) {
+	cond := &RuleCondition{
+		Operator: OperatorAnd,
+		Conditions: []*RuleCondition{
+			{Field: FieldRatio, Operator: OperatorGreaterThan, Value: "1"},
+			{Field: FieldExpr, Value: `HardlinkScope == "none" && FreeSpace < 1000`},
+		},
+	}
+
+	if !ConditionUsesField(cond, FieldHardlinkScope) {
+		t.Fatal("expected expression to use HARDLINK_SCOPE")
+	}
+	if !ConditionUsesField(cond, FieldFreeSpace) {
+		t.Fatal("expected expression to use FREE_SPACE")
+	}
+	if ConditionUsesField(cond, FieldExistsOnOtherInstance) {
+		t.Fatal("expected expression not to use EXISTS_ON_OTHER_INSTANCE")
+	}
+	if ConditionUsesField(&RuleCondition{Field: FieldExpr, Value: `HardlinkScope ==`}, FieldHardlinkScope) {
+		t.Fatal("expected invalid expression to use nothing")
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/qbittorrent"
+)
+
+// ruleHistoryRetention drops streaks that have not been extended for this long,
+// e.g. for disabled rules or torrents the rule stopped seeing.
+const ruleHistoryRetention = 30 * 24 * time.Hour
+
+// ruleHistoryFields are the condition fields backed by rule match streaks.
+var ruleHistoryFields = []ConditionField{FieldRuleMatchStreak, FieldConditionTrueFor}
+
+// ruleStreak is a torrent's match streak for a rule as of the current run.
+// The zero value means the torrent did not match on this run.
+type ruleStreak struct {
+	FirstMatchedAt time.Time
+	LastMatchedAt  time.Time
+	MatchCount     int
+}
+
+// trueForSeconds is how long the streak has lasted, 0 when not matching.
+func (s ruleStreak) trueForSeconds() int64 {
+	if s.MatchCount == 0 {
+		return 0
+	}
+	return int64(s.LastMatchedAt.Sub(s.FirstMatchedAt) / time.Second)
+}
+
+// ruleMatchStreak returns the torrent's streak for the rule being evaluated.
+// ok is false when history was not loaded for the rule.
+func ruleMatchStreak(torrent qbt.Torrent, ctx *EvalContext) (streak ruleStreak, ok bool) {
+	if ctx == nil || ctx.RuleHistory == nil {
+		return ruleStreak{}, false
+	}
+	byHash, ok := ctx.RuleHistory[ctx.ActiveRuleID]
+	if !ok {
+		return ruleStreak{}, false
+	}
+	return byHash[torrent.Hash], true
+}
+
+// withoutRuleHistoryFields returns a copy of cond with every history leaf removed.
+// Groups left without children are dropped; nil means nothing is left to check.
+func withoutRuleHistoryFields(cond *RuleCondition) *RuleCondition {
+	if cond == nil {
+		return nil
+	}
+	if !cond.IsGroup() {
+		for _, field := range ruleHistoryFields {
+			if cond.Field == field {
+				return nil
+			}
+		}
+		return cond
+	}
+
+	children := make([]*RuleCondition, 0, len(cond.Conditions))
+	for _, child := range cond.Conditions {
+		if stripped := withoutRuleHistoryFields(child); stripped != nil {
+			children = append(children, stripped)
+		}
+	}
+	if len(children) == 0 {
+		return nil
+	}
+	cloned := *cond
+	cloned.Conditions = children
+	return &cloned
+}
+
+// ruleHistoryBaseConditions returns the enabled action conditions of rule that use
+// a history field, with the history leaves removed. A torrent extends its streak
+// when any of them matches; a nil entry always matches.
+func ruleHistoryBaseConditions(rule *models.Automation) []*RuleCondition {
+	if rule == nil {
+		return nil
+	}
+	var bases []*RuleCondition
+	for _, cond := range enabledActionConditions(rule.Conditions) {
+		if conditionUsesRuleHistoryFields(cond) {
+			bases = append(bases, withoutRuleHistoryFields(cond))
+		}
+	}
+	return bases
+}
+
+// ruleHistorySignature fingerprints what a rule's streaks are counted against,
+// so stored streaks are discarded once the tracker filter or conditions change.
+func ruleHistorySignature(rule *models.Automation, bases []*RuleCondition) string {
+	payload, err := json.Marshal(struct {
+		Tracker    string           `json:"tracker"`
+		Conditions []*RuleCondition `json:"conditions"`
+	}{rule.TrackerPattern, bases})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:8])
+}
+
+// observeRuleHistory extends or resets each torrent's streak for rules and stores the
+// result in evalCtx.RuleHistory. Torrents skipped this run keep their previous streak;
+// torrents no longer present are dropped.
+func observeRuleHistory(
+	rules []*models.Automation,
+	torrents []qbt.Torrent,
+	evalCtx *EvalContext,
+	sm *qbittorrent.SyncManager,
+	skipCheck func(hash string) bool,
+	prior map[int]map[string]*models.AutomationObservation,
+	now time.Time,
+) map[int]string {
+	type observedRule struct {
+		rule      *models.Automation
+		bases     []*RuleCondition
+		signature string
+		streaks   map[string]ruleStreak
+	}
+
+	observed := make([]*observedRule, 0, len(rules))
+	signatures := make(map[int]string, len(rules))
+	for _, rule := range rules {
+		bases := ruleHistoryBaseConditions(rule)
+		if len(bases) == 0 {
+			continue
+		}
+		o := &observedRule{
+			rule:      rule,
+			bases:     bases,
+			signature: ruleHistorySignature(rule, bases),
+			streaks:   make(map[string]ruleStreak),
+		}
+		observed = append(observed, o)
+		signatures[rule.ID] = o.signature
+	}
+	if len(observed) == 0 {
+		return signatures
+	}
+
+	for _, torrent := range torrents {
+		skipped := skipCheck != nil && skipCheck(torrent.Hash)
+		var trackerDomains []string
+		if !skipped {
+			trackerDomains = collectTrackerDomains(torrent, sm)
+		}
+
+		for _, o := range observed {
+			previous := prior[o.rule.ID][torrent.Hash]
+			if previous != nil && previous.Signature != o.signature {
+				previous = nil
+			}
+
+			if skipped {
+				if previous != nil {
+					o.streaks[torrent.Hash] = ruleStreak{
+						FirstMatchedAt: previous.FirstMatchedAt,
+						LastMatchedAt:  previous.LastMatchedAt,
+						MatchCount:     previous.MatchCount,
+					}
+				}
+				continue
+			}
+
+			if !matchesTracker(o.rule.TrackerPattern, trackerDomains) {
+				continue
+			}
+			loadRuleScopedEvalContext(o.rule, torrents, evalCtx, sm)
+			if !anyConditionMatches(o.bases, torrent, evalCtx) {
+				continue
+			}
+
+			streak := ruleStreak{FirstMatchedAt: now, LastMatchedAt: now, MatchCount: 1}
+			if previous != nil {
+				streak.FirstMatchedAt = previous.FirstMatchedAt
+				streak.MatchCount = previous.MatchCount + 1
+			}
+			o.streaks[torrent.Hash] = streak
+		}
+	}
+
+	if evalCtx.RuleHistory == nil {
+		evalCtx.RuleHistory = make(map[int]map[string]ruleStreak, len(observed))
+	}
+	for _, o := range observed {
+		evalCtx.RuleHistory[o.rule.ID] = o.streaks
+	}
+	return signatures
+}
+
+func anyConditionMatches(conds []*RuleCondition, torrent qbt.Torrent, evalCtx *EvalContext) bool {
+	for _, cond := range conds {
+		if cond == nil || EvaluateConditionWithContext(cond, torrent, evalCtx, 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAutomationObservationStore wires the store that persists rule match streaks
+// for RULE_MATCH_STREAK and CONDITION_TRUE_FOR. Safe to call once at startup.
+func (s *Service) SetAutomationObservationStore(store *models.AutomationObservationStore) {
+	if s == nil {
+		return
+	}
+	s.observationStore = store
+}
+
+// applyRuleHistory loads the stored streaks of rules and advances them for this run.
+// Returns the per-rule signatures needed to save the result, or nil when history is
+// unavailable, in which case the history fields don't match.
+func (s *Service) applyRuleHistory(ctx context.Context, instanceID int, rules []*models.Automation, torrents []qbt.Torrent, evalCtx *EvalContext, skipCheck func(hash string) bool, now time.Time) map[int]string {
+	if s.observationStore == nil || evalCtx == nil {
+		return nil
+	}
+	stored, err := s.observationStore.ListForInstance(ctx, instanceID)
+	if err != nil {
+		log.Warn().Err(err).Int("instanceID", instanceID).Msg("automations: failed to load rule history, RULE_MATCH_STREAK/CONDITION_TRUE_FOR conditions will not match")
+		return nil
+	}
+
+	prior := make(map[int]map[string]*models.AutomationObservation)
+	for _, observation := range stored {
+		if prior[observation.AutomationID] == nil {
+			prior[observation.AutomationID] = make(map[string]*models.AutomationObservation)
+		}
+		prior[observation.AutomationID][observation.Hash] = observation
+	}
+
+	return observeRuleHistory(rules, torrents, evalCtx, s.syncManager, skipCheck, prior, now)
+}
+
+// saveRuleHistory persists the streaks computed by applyRuleHistory.
+func (s *Service) saveRuleHistory(ctx context.Context, instanceID int, evalCtx *EvalContext, signatures map[int]string) {
+	if s.observationStore == nil || evalCtx == nil {
+		return
+	}
+	for ruleID, signature := range signatures {
+		// Ephemeral dry-run rules have no row to attach history to.
+		if ruleID >= dryRunEphemeralRuleIDBase {
+			continue
+		}
+		streaks := evalCtx.RuleHistory[ruleID]
+		observations := make([]*models.AutomationObservation, 0, len(streaks))
+		for hash, streak := range streaks {
+			observations = append(observations, &models.AutomationObservation{
+				Hash:           hash,
+				Signature:      signature,
+				FirstMatchedAt: streak.FirstMatchedAt,
+				LastMatchedAt:  streak.LastMatchedAt,
+				MatchCount:     streak.MatchCount,
+			})
+		}
+		if err := s.observationStore.ReplaceForRule(ctx, ruleID, instanceID, observations); err != nil {
+			log.Warn().Err(err).Int("instanceID", instanceID).Int("ruleID", ruleID).Msg("automations: failed to save rule history")
+		}
+	}
+}
+
+// pruneRuleHistory drops streaks that have not been extended within ruleHistoryRetention.
+func (s *Service) pruneRuleHistory(ctx context.Context) {
+	if s.observationStore == nil {
+		return
+	}
+	pruned, err := s.observationStore.DeleteOlderThan(ctx, time.Now().Add(-ruleHistoryRetention))
+	if err != nil {
+		log.Warn().Err(err).Msg("automations: failed to prune rule history")
+		return
+	}
+	if pruned > 0 {
+		log.Debug().Int64("count", pruned).Msg("automations: pruned stale rule history")
+	}
+}
+
+// rulesUseRuleHistoryFields returns the enabled rules that use a history field.
+func rulesUseRuleHistoryFields(rules []*models.Automation) []*models.Automation {
+	var matching []*models.Automation
+	for _, rule := range rules {
+		if rule == nil || !rule.Enabled {
+			continue
+		}
+		for _, field := range ruleHistoryFields {
+			if actionConditionsUseField(rule.Conditions, field) {
+				matching = append(matching, rule)
+				break
+			}
+		}
+	}
+	return matching
+}
+
+// conditionUsesRuleHistoryFields checks if a condition tree uses a history field.
+func conditionUsesRuleHistoryFields(cond *RuleCondition) bool {
+	for _, field := range ruleHistoryFields {
+		if ConditionUsesField(cond, field) {
+			return true
+		}
+	}
+	return false
+}
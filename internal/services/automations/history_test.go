@@ -0,0 +1,109 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func newStalledStreakRule() *models.Automation {
+	return &models.Automation{
+		ID:             7,
+		Enabled:        true,
+		TrackerPattern: "*",
+		Conditions: &models.ActionConditions{
+			Pause: &models.PauseAction{
+				Enabled: true,
+				Condition: &RuleCondition{
+					Operator: OperatorAnd,
+					Conditions: []*RuleCondition{
+						{Field: FieldState, Operator: OperatorEqual, Value: "stalled_downloading"},
+						{Field: FieldRuleMatchStreak, Operator: OperatorGreaterThanOrEqual, Value: "3"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestWithoutRuleHistoryFields(t *testing.T) {
+	state := &RuleCondition{Field: FieldState, Operator: OperatorEqual, Value: "stalled_downloading"}
+	streak := &RuleCondition{Field: FieldRuleMatchStreak, Operator: OperatorGreaterThan, Value: "2"}
+	trueFor := &RuleCondition{Field: FieldConditionTrueFor, Operator: OperatorGreaterThan, Value: "60"}
+
+	assert.Nil(t, withoutRuleHistoryFields(streak))
+	assert.Same(t, state, withoutRuleHistoryFields(state))
+
+	group := &RuleCondition{Operator: OperatorAnd, Conditions: []*RuleCondition{
+		state,
+		{Operator: OperatorOr, Conditions: []*RuleCondition{streak, trueFor}},
+	}}
+	stripped := withoutRuleHistoryFields(group)
+	require.NotNil(t, stripped)
+	assert.Equal(t, []*RuleCondition{state}, stripped.Conditions)
+	assert.Len(t, group.Conditions, 2, "original tree is left untouched")
+
+	assert.Nil(t, withoutRuleHistoryFields(&RuleCondition{Operator: OperatorAnd, Conditions: []*RuleCondition{streak, trueFor}}))
+}
+
+func TestObserveRuleHistory(t *testing.T) {
+	rule := newStalledStreakRule()
+	bases := ruleHistoryBaseConditions(rule)
+	signature := ruleHistorySignature(rule, bases)
+	start := time.Unix(1_700_000_000, 0)
+	now := start.Add(2 * time.Hour)
+
+	torrents := []qbt.Torrent{
+		{Hash: "continuing", State: qbt.TorrentStateStalledDl},
+		{Hash: "new", State: qbt.TorrentStateStalledDl},
+		{Hash: "recovered", State: qbt.TorrentStateDownloading},
+		{Hash: "edited", State: qbt.TorrentStateStalledDl},
+		{Hash: "skipped", State: qbt.TorrentStateDownloading},
+	}
+	prior := map[int]map[string]*models.AutomationObservation{
+		rule.ID: {
+			"continuing": {Hash: "continuing", Signature: signature, FirstMatchedAt: start, LastMatchedAt: start.Add(time.Hour), MatchCount: 2},
+			"recovered":  {Hash: "recovered", Signature: signature, FirstMatchedAt: start, LastMatchedAt: start, MatchCount: 5},
+			"edited":     {Hash: "edited", Signature: "stale", FirstMatchedAt: start, LastMatchedAt: start, MatchCount: 9},
+			"skipped":    {Hash: "skipped", Signature: signature, FirstMatchedAt: start, LastMatchedAt: start, MatchCount: 4},
+			"gone":       {Hash: "gone", Signature: signature, FirstMatchedAt: start, LastMatchedAt: start, MatchCount: 1},
+		},
+	}
+	skip := func(hash string) bool { return hash == "skipped" }
+
+	evalCtx := &EvalContext{}
+	signatures := observeRuleHistory([]*models.Automation{rule}, torrents, evalCtx, nil, skip, prior, now)
+	require.Equal(t, map[int]string{rule.ID: signature}, signatures)
+
+	streaks := evalCtx.RuleHistory[rule.ID]
+	assert.Equal(t, ruleStreak{FirstMatchedAt: start, LastMatchedAt: now, MatchCount: 3}, streaks["continuing"])
+	assert.Equal(t, ruleStreak{FirstMatchedAt: now, LastMatchedAt: now, MatchCount: 1}, streaks["new"])
+	assert.Equal(t, 1, streaks["edited"].MatchCount, "streaks counted against old conditions start over")
+	assert.Equal(t, 4, streaks["skipped"].MatchCount, "skipped torrents keep their streak")
+	assert.NotContains(t, streaks, "recovered")
+	assert.NotContains(t, streaks, "gone")
+
+	evalCtx.ActiveRuleID = rule.ID
+	pause := rule.Conditions.Pause.Condition
+	assert.True(t, EvaluateConditionWithContext(pause, torrents[0], evalCtx, 0))
+	assert.False(t, EvaluateConditionWithContext(pause, torrents[1], evalCtx, 0))
+
+	trueFor := &RuleCondition{Field: FieldConditionTrueFor, Operator: OperatorGreaterThanOrEqual, Value: "7200"}
+	assert.True(t, EvaluateConditionWithContext(trueFor, torrents[0], evalCtx, 0))
+	assert.False(t, EvaluateConditionWithContext(trueFor, torrents[1], evalCtx, 0))
+
+	zeroStreak := &RuleCondition{Field: FieldRuleMatchStreak, Operator: OperatorEqual, Value: "0"}
+	assert.True(t, EvaluateConditionWithContext(zeroStreak, torrents[2], evalCtx, 0))
+
+	evalCtx.ActiveRuleID = 99
+	assert.False(t, EvaluateConditionWithContext(zeroStreak, torrents[2], evalCtx, 0), "unknown history never matches")
+	assert.False(t, EvaluateConditionWithContext(zeroStreak, torrents[2], nil, 0))
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// HnRRisk is a torrent that has not yet met its tracker's seeding requirement.
+type HnRRisk struct {
+	Hash               string  `json:"hash"`
+	Name               string  `json:"name"`
+	Category           string  `json:"category"`
+	State              string  `json:"state"`
+	Tracker            string  `json:"tracker"`
+	RequirementID      int     `json:"requirementId"`
+	RequirementName    string  `json:"requirementName"`
+	Ratio              float64 `json:"ratio"`
+	SeedingTimeSeconds int64   `json:"seedingTimeSeconds"`
+	MinRatio           float64 `json:"minRatio"`
+	MinSeedTimeSeconds int64   `json:"minSeedTimeSeconds"`
+	// RemainingSeconds is -1 when only reaching the ratio can satisfy the requirement.
+	RemainingSeconds int64 `json:"remainingSeconds"`
+}
+
+// torrentTrackerDomains returns the torrent's tracker domains, current tracker first.
+func torrentTrackerDomains(torrent qbt.Torrent) []string {
+	domains := make([]string, 0, len(torrent.Trackers)+1)
+	seen := make(map[string]struct{}, len(torrent.Trackers)+1)
+	add := func(raw string) {
+		domain := extractTrackerDomain(raw)
+		if domain == "" {
+			return
+		}
+		if _, ok := seen[domain]; ok {
+			return
+		}
+		seen[domain] = struct{}{}
+		domains = append(domains, domain)
+	}
+
+	add(torrent.Tracker)
+	for _, tracker := range torrent.Trackers {
+		add(tracker.Url)
+	}
+	return domains
+}
+
+// torrentHnRStatus checks torrent against its tracker's requirement. Torrents on
+// trackers without a requirement are satisfied. ok is false when requirements
+// were not loaded.
+func torrentHnRStatus(torrent qbt.Torrent, ctx *EvalContext) (status models.HnRStatus, ok bool) {
+	if ctx == nil || ctx.TrackerRequirements == nil {
+		return models.HnRStatus{}, false
+	}
+	requirement := ctx.TrackerRequirements.Match(torrentTrackerDomains(torrent)...)
+	if requirement == nil {
+		return models.HnRStatus{Satisfied: true}, true
+	}
+	return requirement.Evaluate(torrent.Category, torrent.Ratio, torrent.SeedingTime), true
+}
+
+// HnRAtRisk returns the torrents that have not met their tracker's requirement,
+// closest to satisfying first; ratio-only shortfalls are listed last.
+func HnRAtRisk(torrents []qbt.Torrent, index *models.TrackerRequirementIndex) []HnRRisk {
+	risks := make([]HnRRisk, 0)
+	if index.Len() == 0 {
+		return risks
+	}
+
+	for _, torrent := range torrents {
+		domains := torrentTrackerDomains(torrent)
+		requirement := index.Match(domains...)
+		if requirement == nil {
+			continue
+		}
+		status := requirement.Evaluate(torrent.Category, torrent.Ratio, torrent.SeedingTime)
+		if status.Satisfied {
+			continue
+		}
+		risks = append(risks, HnRRisk{
+			Hash:               torrent.Hash,
+			Name:               torrent.Name,
+			Category:           torrent.Category,
+			State:              string(torrent.State),
+			Tracker:            domains[0],
+			RequirementID:      requirement.ID,
+			RequirementName:    requirement.Name,
+			Ratio:              torrent.Ratio,
+			SeedingTimeSeconds: torrent.SeedingTime,
+			MinRatio:           status.MinRatio,
+			MinSeedTimeSeconds: status.MinSeedTimeSeconds,
+			RemainingSeconds:   status.RemainingSeconds,
+		})
+	}
+
+	slices.SortFunc(risks, func(a, b HnRRisk) int {
+		if (a.RemainingSeconds < 0) != (b.RemainingSeconds < 0) {
+			if a.RemainingSeconds < 0 {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Or(cmp.Compare(a.RemainingSeconds, b.RemainingSeconds), cmp.Compare(a.Name, b.Name), cmp.Compare(a.Hash, b.Hash))
+	})
+	return risks
+}
+
+// SetTrackerRequirementStore wires the tracker requirement store used by HNR_*
+// fields and the at-risk report. Safe to call once at startup.
+func (s *Service) SetTrackerRequirementStore(store *models.TrackerRequirementStore) {
+	if s == nil {
+		return
+	}
+	s.trackerRequirementStore = store
+}
+
+// HnRReport lists the instance's torrents at risk of a hit-and-run.
+func (s *Service) HnRReport(ctx context.Context, instanceID int) ([]HnRRisk, error) {
+	if s == nil || s.syncManager == nil || s.trackerRequirementStore == nil {
+		return nil, errors.New("tracker requirements are not configured")
+	}
+
+	requirements, err := s.trackerRequirementStore.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	torrents, err := s.syncManager.GetAllTorrents(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return HnRAtRisk(torrents, models.NewTrackerRequirementIndex(requirements)), nil
+}
+
+// applyTrackerRequirements loads tracker requirements into evalCtx.
+// On failure TrackerRequirements stays nil so HNR_* conditions don't match.
+func (s *Service) applyTrackerRequirements(ctx context.Context, instanceID int, evalCtx *EvalContext) {
+	if s.trackerRequirementStore == nil || evalCtx == nil {
+		return
+	}
+	requirements, err := s.trackerRequirementStore.List(ctx)
+	if err != nil {
+		log.Warn().Err(err).Int("instanceID", instanceID).Msg("automations: failed to load tracker requirements, HNR_* conditions will not match")
+		return
+	}
+	evalCtx.TrackerRequirements = models.NewTrackerRequirementIndex(requirements)
+}
+
+// rulesUseHnRFields checks if any enabled rule uses an HNR_* field.
+func rulesUseHnRFields(rules []*models.Automation) bool {
+	return rulesUseCondition(rules, FieldHnRSatisfied) ||
+		rulesUseCondition(rules, FieldHnRRemainingSeconds)
+}
+
+// conditionUsesHnRFields checks if a condition tree or sorting config uses an HNR_* field.
+func conditionUsesHnRFields(cond *RuleCondition, config *models.SortingConfig) bool {
+	for _, field := range []ConditionField{FieldHnRSatisfied, FieldHnRRemainingSeconds} {
+		if ConditionUsesField(cond, field) || sortingConfigUsesField(config, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func newTestRequirementIndex() *models.TrackerRequirementIndex {
+	return models.NewTrackerRequirementIndex([]*models.TrackerRequirement{
+		{ID: 1, Name: "Strict", Enabled: true, Domains: []string{"strict.example"}, MinRatio: 1, MinSeedTimeMinutes: 60},
+		{ID: 2, Name: "Ratio", Enabled: true, Domains: []string{"ratio.example"}, MinRatio: 2},
+	})
+}
+
+func TestEvaluateCondition_HnRFields(t *testing.T) {
+	ctx := &EvalContext{TrackerRequirements: newTestRequirementIndex()}
+
+	pending := qbt.Torrent{Hash: "a", Tracker: "https://strict.example/announce", Ratio: 0.2, SeedingTime: 1800}
+	done := qbt.Torrent{Hash: "b", Tracker: "https://strict.example/announce", Ratio: 1.5}
+	untracked := qbt.Torrent{Hash: "c", Tracker: "https://public.example/announce"}
+	ratioOnly := qbt.Torrent{Hash: "d", Tracker: "udp://ratio.example:1337/announce", Ratio: 1}
+	viaTrackerList := qbt.Torrent{Hash: "e", Ratio: 0.2, Trackers: []qbt.TorrentTracker{{Url: "https://strict.example/announce"}}}
+
+	satisfied := &RuleCondition{Field: FieldHnRSatisfied, Operator: OperatorEqual, Value: "true"}
+	remainingOver := &RuleCondition{Field: FieldHnRRemainingSeconds, Operator: OperatorGreaterThan, Value: "1000"}
+	remainingZero := &RuleCondition{Field: FieldHnRRemainingSeconds, Operator: OperatorEqual, Value: "0"}
+
+	assert.False(t, EvaluateConditionWithContext(satisfied, pending, ctx, 0))
+	assert.True(t, EvaluateConditionWithContext(remainingOver, pending, ctx, 0), "1800s of 3600s seeded")
+	assert.True(t, EvaluateConditionWithContext(satisfied, done, ctx, 0))
+	assert.True(t, EvaluateConditionWithContext(remainingZero, done, ctx, 0))
+	assert.True(t, EvaluateConditionWithContext(satisfied, untracked, ctx, 0), "no requirement means nothing to protect")
+	assert.False(t, EvaluateConditionWithContext(satisfied, ratioOnly, ctx, 0))
+	assert.False(t, EvaluateConditionWithContext(remainingZero, ratioOnly, ctx, 0), "ratio-only shortfall has no remaining time")
+	assert.False(t, EvaluateConditionWithContext(satisfied, viaTrackerList, ctx, 0))
+
+	unloaded := &EvalContext{}
+	assert.False(t, EvaluateConditionWithContext(satisfied, done, unloaded, 0), "unknown requirements never match")
+	assert.False(t, EvaluateConditionWithContext(remainingZero, done, nil, 0))
+
+	expr := &RuleCondition{Field: FieldExpr, Value: `!HnRSatisfied && HnRRemainingSeconds > 1000`}
+	assert.True(t, EvaluateConditionWithContext(expr, pending, ctx, 0))
+	assert.True(t, ConditionUsesField(expr, FieldHnRSatisfied))
+}
+
+func TestHnRAtRisk(t *testing.T) {
+	torrents := []qbt.Torrent{
+		{Hash: "1", Name: "ratio short", Tracker: "https://ratio.example/a", Ratio: 1},
+		{Hash: "2", Name: "almost", Tracker: "https://strict.example/a", Ratio: 0.5, SeedingTime: 3500},
+		{Hash: "3", Name: "far", Tracker: "https://strict.example/a", Ratio: 0.5, SeedingTime: 100},
+		{Hash: "4", Name: "done", Tracker: "https://strict.example/a", Ratio: 1},
+		{Hash: "5", Name: "public", Tracker: "https://public.example/a"},
+	}
+
+	risks := HnRAtRisk(torrents, newTestRequirementIndex())
+	require.Len(t, risks, 3)
+	assert.Equal(t, []string{"almost", "far", "ratio short"}, []string{risks[0].Name, risks[1].Name, risks[2].Name})
+	assert.Equal(t, int64(100), risks[0].RemainingSeconds)
+	assert.Equal(t, "strict.example", risks[0].Tracker)
+	assert.Equal(t, "Strict", risks[0].RequirementName)
+	assert.Equal(t, int64(-1), risks[2].RemainingSeconds)
+
+	assert.Empty(t, HnRAtRisk(torrents, nil))
+}
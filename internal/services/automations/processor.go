@@ -105,6 +105,15 @@ type torrentDesiredState struct {
 	exportToInstance         *models.ExportToInstanceAction
 	exportToInstanceRuleID   int
 	exportToInstanceRuleName string
+
+	// Webhooks (accumulated - every matching rule fires its own webhook)
+	webhooks []webhookMatch
+}
+
+// webhookMatch records a rule whose webhook action matched the torrent.
+type webhookMatch struct {
+	rule   ruleRef
+	action *models.WebhookAction
 }
 
 type ruleRef struct {
@@ -143,13 +152,15 @@ type ruleRunStats struct {
 	ExternalProgramConditionNotMet   int
 	ExportToInstanceApplied          int
 	ExportToInstanceConditionNotMet  int
+	WebhookApplied                   int
+	WebhookConditionNotMet           int
 }
 
 func (s *ruleRunStats) totalApplied() int {
 	if s == nil {
 		return 0
 	}
-	return s.SpeedApplied + s.ShareApplied + s.PauseApplied + s.ResumeApplied + s.RecheckApplied + s.ReannounceApplied + s.AutoManageApplied + s.TagConditionMet + s.CategoryApplied + s.DeleteApplied + s.MoveApplied + s.ExternalProgramApplied + s.ExportToInstanceApplied
+	return s.SpeedApplied + s.ShareApplied + s.PauseApplied + s.ResumeApplied + s.RecheckApplied + s.ReannounceApplied + s.AutoManageApplied + s.TagConditionMet + s.CategoryApplied + s.DeleteApplied + s.MoveApplied + s.ExternalProgramApplied + s.ExportToInstanceApplied + s.WebhookApplied
 }
 
 func getOrCreateRuleStats(m map[int]*ruleRunStats, rule *models.Automation) *ruleRunStats {
@@ -512,6 +523,24 @@ func processRuleForTorrent(rule *models.Automation, torrent qbt.Torrent, state *
 		}
 	}
 
+	// Webhook (accumulated)
+	if conditions.Webhook != nil && conditions.Webhook.Enabled && conditions.Webhook.URL != "" {
+		shouldApply := conditions.Webhook.Condition == nil ||
+			EvaluateConditionWithContext(conditions.Webhook.Condition, torrent, evalCtx, 0)
+
+		if shouldApply {
+			if stats != nil {
+				stats.WebhookApplied++
+			}
+			state.webhooks = append(state.webhooks, webhookMatch{
+				rule:   ruleRef{id: rule.ID, name: rule.Name},
+				action: conditions.Webhook,
+			})
+		} else if stats != nil {
+			stats.WebhookConditionNotMet++
+		}
+	}
+
 	// Delete
 	if conditions.Delete != nil && conditions.Delete.Enabled {
 		// Safety: delete must always have an explicit condition.
@@ -827,7 +856,8 @@ func hasActions(state *torrentDesiredState) bool {
 		state.shouldDelete ||
 		state.shouldMove ||
 		state.externalProgramID != nil ||
-		state.exportToInstance != nil
+		state.exportToInstance != nil ||
+		len(state.webhooks) > 0
 }
 
 // selectTrackerTag picks the best tracker domain to use as a tag.
@@ -1231,6 +1261,12 @@ func getNumericFieldValue(t qbt.Torrent, field models.ConditionField, evalCtx *E
 		return float64(evaluateTime(evalCtx).Month())
 	case models.FieldSystemYear:
 		return float64(evaluateTime(evalCtx).Year())
+	case models.FieldUploadedLast24h, models.FieldUploadedLast7d:
+		uploaded, _ := torrentUploadedLast(t, evalCtx, field)
+		return float64(uploaded)
+	case models.FieldIdleUploadDays:
+		days, _ := torrentIdleUploadDays(t, evalCtx)
+		return days
 	default:
 		return 0
 	}
@@ -0,0 +1,125 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// maxWindowedCronSlots bounds the search for a cron slot inside the active window.
+const maxWindowedCronSlots = 1000
+
+func ruleInterval(rule *models.Automation) time.Duration {
+	if rule != nil && rule.IntervalSeconds != nil {
+		return time.Duration(*rule.IntervalSeconds) * time.Second
+	}
+	return DefaultRuleInterval
+}
+
+// ruleIsDue reports whether an interval-triggered rule should run at now.
+// Rules outside their active window never run. Cron rules run once per elapsed
+// slot (and consume the slot even if nothing matches); other rules run when
+// their interval has passed since they last processed a torrent.
+func (s *Service) ruleIsDue(instanceID int, rule *models.Automation, now time.Time) bool {
+	if !rule.Schedule.InWindow(now) {
+		return false
+	}
+
+	key := ruleKey{instanceID, rule.ID}
+
+	sched, err := rule.Schedule.CronSchedule()
+	if err != nil {
+		log.Warn().Err(err).Int("instanceID", instanceID).Int("ruleID", rule.ID).Str("ruleName", rule.Name).Msg("automations: invalid cron schedule, skipping rule")
+		return false
+	}
+	if sched == nil {
+		s.mu.RLock()
+		lastRun := s.lastRuleRun[key]
+		s.mu.RUnlock()
+		return now.Sub(lastRun) >= ruleInterval(rule)
+	}
+
+	loc, err := rule.Schedule.Location()
+	if err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last := s.lastCronRun[key]
+	if last.IsZero() {
+		// No record (fresh start or pruned): only a slot crossed since the
+		// previous scan tick is due, so restarts never replay old slots.
+		last = now.Add(-s.cfg.ScanInterval)
+	}
+	next := sched.Next(last.In(loc))
+	if next.IsZero() || next.After(now) {
+		return false
+	}
+	s.lastCronRun[key] = now
+	return true
+}
+
+// NextRunAt estimates when rule will next be evaluated by the scheduler.
+// Returns nil for disabled or event-triggered rules and for schedules that never fire.
+func (s *Service) NextRunAt(instanceID int, rule *models.Automation, now time.Time) *time.Time {
+	if s == nil || rule == nil || !rule.Enabled || rule.TriggerMode.IsEvent() {
+		return nil
+	}
+
+	sched, err := rule.Schedule.CronSchedule()
+	if err != nil {
+		return nil
+	}
+
+	var next time.Time
+	if sched != nil {
+		loc, err := rule.Schedule.Location()
+		if err != nil {
+			return nil
+		}
+		next = sched.Next(now.In(loc))
+		for i := 0; i < maxWindowedCronSlots && !next.IsZero() && !rule.Schedule.InWindow(next); i++ {
+			next = sched.Next(next)
+		}
+		if !next.IsZero() && !rule.Schedule.InWindow(next) {
+			next = time.Time{}
+		}
+	} else {
+		s.mu.RLock()
+		lastRun := s.lastRuleRun[ruleKey{instanceID, rule.ID}]
+		s.mu.RUnlock()
+
+		next = lastRun.Add(ruleInterval(rule))
+		if next.Before(now) {
+			next = now
+		}
+		next = rule.Schedule.NextWindowOpen(next)
+	}
+
+	if next.IsZero() {
+		return nil
+	}
+	next = next.Truncate(time.Second)
+	return &next
+}
+
+// AnnotateNextRuns fills NextRunAt on each rule for API responses.
+func (s *Service) AnnotateNextRuns(instanceID int, rules []*models.Automation) {
+	if s == nil {
+		return
+	}
+	now := time.Now()
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		rule.NextRunAt = s.NextRunAt(instanceID, rule, now)
+	}
+}
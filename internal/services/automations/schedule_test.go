@@ -0,0 +1,95 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func newScheduleTestService() *Service {
+	return &Service{
+		cfg:         DefaultConfig(),
+		lastRuleRun: make(map[ruleKey]time.Time),
+		lastCronRun: make(map[ruleKey]time.Time),
+	}
+}
+
+func TestRuleIsDueCronConsumesSlot(t *testing.T) {
+	t.Parallel()
+
+	s := newScheduleTestService()
+	rule := &models.Automation{ID: 1, Enabled: true, Schedule: &models.AutomationSchedule{Cron: "0 2 * * *", Timezone: "UTC"}}
+
+	before := time.Date(2026, time.March, 4, 1, 59, 50, 0, time.UTC)
+	assert.False(t, s.ruleIsDue(1, rule, before))
+
+	slot := time.Date(2026, time.March, 4, 2, 0, 5, 0, time.UTC)
+	assert.True(t, s.ruleIsDue(1, rule, slot))
+	assert.False(t, s.ruleIsDue(1, rule, slot.Add(20*time.Second)), "slot already consumed")
+	assert.True(t, s.ruleIsDue(1, rule, slot.Add(24*time.Hour)))
+}
+
+func TestRuleIsDueCronDoesNotReplayAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	s := newScheduleTestService()
+	rule := &models.Automation{ID: 1, Enabled: true, Schedule: &models.AutomationSchedule{Cron: "0 2 * * *", Timezone: "UTC"}}
+
+	// Fresh service well after the 02:00 slot must not run until the next slot.
+	assert.False(t, s.ruleIsDue(1, rule, time.Date(2026, time.March, 4, 9, 0, 0, 0, time.UTC)))
+}
+
+func TestRuleIsDueRespectsActiveWindow(t *testing.T) {
+	t.Parallel()
+
+	s := newScheduleTestService()
+	rule := &models.Automation{
+		ID:       1,
+		Enabled:  true,
+		Schedule: &models.AutomationSchedule{Timezone: "UTC", ActiveWindow: &models.ActiveWindow{Start: "01:00", End: "06:00"}},
+	}
+
+	assert.False(t, s.ruleIsDue(1, rule, time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)))
+	assert.True(t, s.ruleIsDue(1, rule, time.Date(2026, time.March, 4, 3, 0, 0, 0, time.UTC)))
+
+	s.lastRuleRun[ruleKey{1, 1}] = time.Date(2026, time.March, 4, 3, 0, 0, 0, time.UTC)
+	assert.False(t, s.ruleIsDue(1, rule, time.Date(2026, time.March, 4, 3, 0, 10, 0, time.UTC)), "interval not yet elapsed")
+}
+
+func TestNextRunAt(t *testing.T) {
+	t.Parallel()
+
+	s := newScheduleTestService()
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC) // Wednesday
+
+	cronRule := &models.Automation{ID: 1, Enabled: true, Schedule: &models.AutomationSchedule{Cron: "30 2 * * *", Timezone: "UTC"}}
+	next := s.NextRunAt(1, cronRule, now)
+	require.NotNil(t, next)
+	assert.True(t, next.Equal(time.Date(2026, time.March, 5, 2, 30, 0, 0, time.UTC)))
+
+	// Hourly cron limited to a weekend window skips ahead to Saturday.
+	windowed := &models.Automation{ID: 2, Enabled: true, Schedule: &models.AutomationSchedule{
+		Cron:         "@hourly",
+		Timezone:     "UTC",
+		ActiveWindow: &models.ActiveWindow{Days: []int{0, 6}, Start: "00:00", End: "00:00"},
+	}}
+	next = s.NextRunAt(1, windowed, now)
+	require.NotNil(t, next)
+	assert.True(t, next.Equal(time.Date(2026, time.March, 7, 0, 0, 0, 0, time.UTC)))
+
+	// Interval rule that has never run is due now.
+	intervalRule := &models.Automation{ID: 3, Enabled: true}
+	next = s.NextRunAt(1, intervalRule, now)
+	require.NotNil(t, next)
+	assert.True(t, next.Equal(now))
+
+	assert.Nil(t, s.NextRunAt(1, &models.Automation{ID: 4, Enabled: true, TriggerMode: models.TriggerOnAdded}, now))
+	assert.Nil(t, s.NextRunAt(1, &models.Automation{ID: 5, Enabled: false}, now))
+}
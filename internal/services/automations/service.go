@@ -24,6 +24,7 @@ import (
 	"github.com/autobrr/qui/internal/models"
 	"github.com/autobrr/qui/internal/qbittorrent"
 	"github.com/autobrr/qui/internal/services/activity"
+	"github.com/autobrr/qui/internal/services/arr"
 	"github.com/autobrr/qui/internal/services/crossseed"
 	"github.com/autobrr/qui/internal/services/externalprograms"
 	"github.com/autobrr/qui/internal/services/notifications"
@@ -533,6 +534,12 @@ type CrossMatcher interface {
 	BuildCrossMatchSets(ctx context.Context, currentInstanceID int, needs CrossMatchNeeds) *CrossMatchResult
 }
 
+// ArrImportProvider reports Sonarr/Radarr queue and import history state.
+// Implementations cache per arr instance; it is called once per automation run.
+type ArrImportProvider interface {
+	ImportStatus(ctx context.Context) (*arr.ImportStatus, error)
+}
+
 // Service periodically applies automation rules to torrents for all active instances.
 type Service struct {
 	cfg                       Config
@@ -544,6 +551,10 @@ type Service struct {
 	notifier                  notifications.Notifier
 	externalProgramService    *externalprograms.Service // for executing external programs
 	crossMatcher              CrossMatcher
+	arrImports                ArrImportProvider
+	trackerRequirementStore   *models.TrackerRequirementStore
+	observationStore          *models.AutomationObservationStore
+	transferSampleStore       *models.TorrentTransferSampleStore
 	activityRuns              *activityRunStore
 	releaseParser             *releases.Parser
 
@@ -551,10 +562,14 @@ type Service struct {
 	// that havent disappeared from sync data yet
 	lastApplied           map[int]map[string]time.Time // instanceID -> hash -> timestamp
 	lastRuleRun           map[ruleKey]time.Time        // per-rule cadence tracking
+	lastCronRun           map[ruleKey]time.Time        // per-rule cron slot tracking
 	lastFreeSpaceDeleteAt map[int]time.Time            // instanceID -> last FREE_SPACE delete timestamp
 	inFlightExports       map[string]struct{}          // "targetInstanceID:hash" -> in-progress export
 	mu                    sync.RWMutex
 
+	triggers   *triggerQueue
+	triggerCtx context.Context // base context for event-triggered runs; set by Start
+
 	activityPublisher activity.Publisher
 }
 
@@ -591,8 +606,11 @@ func NewService(cfg Config, instanceStore *models.InstanceStore, ruleStore *mode
 		releaseParser:             releases.NewDefaultParser(),
 		lastApplied:               make(map[int]map[string]time.Time),
 		lastRuleRun:               make(map[ruleKey]time.Time),
+		lastCronRun:               make(map[ruleKey]time.Time),
 		lastFreeSpaceDeleteAt:     make(map[int]time.Time),
 		inFlightExports:           make(map[string]struct{}),
+		triggers:                  newTriggerQueue(triggerDebounce),
+		triggerCtx:                context.Background(),
 		activityPublisher:         activity.NopPublisher{},
 	}
 }
@@ -606,6 +624,15 @@ func (s *Service) SetActivityPublisher(publisher activity.Publisher) {
 	s.activityPublisher = publisher
 }
 
+// SetArrImportProvider wires the arr service used for ARR_* condition fields.
+// Without it those fields never match. Safe to call once at startup.
+func (s *Service) SetArrImportProvider(provider ArrImportProvider) {
+	if s == nil || provider == nil {
+		return
+	}
+	s.arrImports = provider
+}
+
 // cleanupStaleEntries removes entries from lastApplied and lastRuleRun maps
 // that are older than the cutoff to prevent unbounded memory growth.
 func (s *Service) cleanupStaleEntries() {
@@ -628,6 +655,14 @@ func (s *Service) cleanupStaleEntries() {
 		}
 	}
 
+	// A pruned cron entry falls back to "due since the previous tick", so
+	// dropping it can delay a slot but never replays one.
+	for key, ts := range s.lastCronRun {
+		if ts.Before(ruleCutoff) {
+			delete(s.lastCronRun, key)
+		}
+	}
+
 	// Clean up FREE_SPACE cooldown entries older than 10 minutes
 	for instanceID, ts := range s.lastFreeSpaceDeleteAt {
 		if ts.Before(cutoff) {
@@ -644,6 +679,9 @@ func (s *Service) Start(ctx context.Context) {
 	if s == nil {
 		return
 	}
+	s.mu.Lock()
+	s.triggerCtx = ctx
+	s.mu.Unlock()
 	go s.loop(ctx)
 }
 
@@ -678,6 +716,7 @@ func (s *Service) loop(ctx context.Context) {
 						log.Info().Int64("count", pruned).Msg("automations: pruned old activity entries")
 					}
 				}
+				s.pruneRuleHistory(ctx)
 				s.cleanupStaleEntries()
 				lastPrune = time.Now()
 			}
@@ -1003,6 +1042,15 @@ func (s *Service) PreviewDeleteRule(ctx context.Context, instanceID int, rule *m
 		deleteCondition = rule.Conditions.Delete.Condition
 		s.setupPreviewTrackerDisplayNames(ctx, instanceID, rule.Conditions.Delete.Condition, evalCtx)
 		s.setupPreviewCrossMatchContext(ctx, instanceID, rule, rule.Conditions.Delete.Condition, evalCtx)
+		if conditionUsesArrImportFields(rule.Conditions.Delete.Condition, rule.SortingConfig) {
+			s.applyArrImportStatus(ctx, instanceID, evalCtx)
+		}
+		if conditionUsesHnRFields(rule.Conditions.Delete.Condition, rule.SortingConfig) {
+			s.applyTrackerRequirements(ctx, instanceID, evalCtx)
+		}
+		if conditionUsesTransferHistoryFields(rule.Conditions.Delete.Condition, rule.SortingConfig) {
+			s.applyTransferHistory(ctx, instanceID, evalCtx)
+		}
 	}
 	hardlinkIndex := s.setupDeleteHardlinkContext(ctx, instanceID, rule, torrents, evalCtx, instance)
 	s.setupMissingFilesContext(ctx, instanceID, rule, deleteCondition, torrents, evalCtx, instance)
@@ -1012,6 +1060,11 @@ func (s *Service) PreviewDeleteRule(ctx context.Context, instanceID int, rule *m
 		return nil, err
 	}
 
+	// Preview streaks as the next run would compute them, without saving.
+	if conditionUsesRuleHistoryFields(deleteCondition) {
+		s.applyRuleHistory(ctx, instanceID, []*models.Automation{rule}, torrents, evalCtx, nil, time.Now())
+	}
+
 	SortTorrentsWithFallback(torrents, rule.SortingConfig, evalCtx, instanceID, rule.Name)
 	scoreByHash := buildPreviewScoreMap(torrents, rule, evalCtx)
 
@@ -1539,6 +1592,15 @@ func (s *Service) PreviewCategoryRule(ctx context.Context, instanceID int, rule
 	if rule != nil && rule.Conditions != nil && rule.Conditions.Category != nil {
 		s.setupPreviewTrackerDisplayNames(ctx, instanceID, rule.Conditions.Category.Condition, evalCtx)
 		s.setupPreviewCrossMatchContext(ctx, instanceID, rule, rule.Conditions.Category.Condition, evalCtx)
+		if conditionUsesArrImportFields(rule.Conditions.Category.Condition, rule.SortingConfig) {
+			s.applyArrImportStatus(ctx, instanceID, evalCtx)
+		}
+		if conditionUsesHnRFields(rule.Conditions.Category.Condition, rule.SortingConfig) {
+			s.applyTrackerRequirements(ctx, instanceID, evalCtx)
+		}
+		if conditionUsesTransferHistoryFields(rule.Conditions.Category.Condition, rule.SortingConfig) {
+			s.applyTransferHistory(ctx, instanceID, evalCtx)
+		}
 	}
 	s.setupCategoryHardlinkContext(ctx, instanceID, rule, torrents, evalCtx, instance)
 	s.setupMissingFilesContext(ctx, instanceID, rule, getCategoryAction(rule).condition, torrents, evalCtx, instance)
@@ -1548,6 +1610,11 @@ func (s *Service) PreviewCategoryRule(ctx context.Context, instanceID int, rule
 		return nil, err
 	}
 
+	// Preview streaks as the next run would compute them, without saving.
+	if conditionUsesRuleHistoryFields(getCategoryAction(rule).condition) {
+		s.applyRuleHistory(ctx, instanceID, []*models.Automation{rule}, torrents, evalCtx, nil, time.Now())
+	}
+
 	SortTorrentsWithFallback(torrents, rule.SortingConfig, evalCtx, instanceID, rule.Name)
 	scoreByHash := buildPreviewScoreMap(torrents, rule, evalCtx)
 
@@ -1884,7 +1951,7 @@ func (s *Service) blockedDeleteCandidates(
 }
 
 func (s *Service) applyForInstance(ctx context.Context, instanceID int, force bool) error {
-	rules, err := s.ruleStore.ListByInstance(ctx, instanceID)
+	rules, err := s.ruleStore.ListForInstance(ctx, instanceID)
 	if err != nil {
 		log.Error().Err(err).Int("instanceID", instanceID).Msg("automations: failed to load rules")
 		s.notifyAutomationFailure(ctx, instanceID, err)
@@ -1894,6 +1961,13 @@ func (s *Service) applyForInstance(ctx context.Context, instanceID int, force bo
 		return nil
 	}
 
+	return s.applyRuleSetForInstance(ctx, instanceID, force, rules, nil)
+}
+
+// applyRuleSetForInstance splits rules into dry-run and live sets, applies both and
+// signals connected clients when activity was recorded. A non-nil scope limits
+// processing to the given torrent hashes.
+func (s *Service) applyRuleSetForInstance(ctx context.Context, instanceID int, force bool, rules []*models.Automation, scope map[string]struct{}) error {
 	var liveRules []*models.Automation
 	var dryRunRules []*models.Automation
 	for _, rule := range rules {
@@ -1904,11 +1978,11 @@ func (s *Service) applyForInstance(ctx context.Context, instanceID int, force bo
 		}
 	}
 
-	dryActivity, err := s.applyRulesForInstance(ctx, instanceID, force, dryRunRules, true)
+	dryActivity, err := s.applyScopedRulesForInstance(ctx, instanceID, force, dryRunRules, true, scope)
 	if err != nil {
 		return err
 	}
-	liveActivity, err := s.applyRulesForInstance(ctx, instanceID, force, liveRules, false)
+	liveActivity, err := s.applyScopedRulesForInstance(ctx, instanceID, force, liveRules, false, scope)
 	if err != nil {
 		return err
 	}
@@ -1929,6 +2003,13 @@ func (s *Service) applyForInstance(ctx context.Context, instanceID int, force bo
 }
 
 func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, force bool, rules []*models.Automation, dryRun bool) ([]*models.AutomationActivity, error) {
+	return s.applyScopedRulesForInstance(ctx, instanceID, force, rules, dryRun, nil)
+}
+
+// applyScopedRulesForInstance is applyRulesForInstance restricted to the torrents in scope.
+// A nil scope processes every torrent. Conditions still see the full torrent list
+// (category index, grouping, cross-seed lookups), only the processed set is narrowed.
+func (s *Service) applyScopedRulesForInstance(ctx context.Context, instanceID int, force bool, rules []*models.Automation, dryRun bool, scope map[string]struct{}) ([]*models.AutomationActivity, error) {
 	if len(rules) == 0 {
 		return nil, nil
 	}
@@ -1938,16 +2019,12 @@ func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, for
 	eligibleRules := make([]*models.Automation, 0, len(rules))
 	for _, rule := range rules {
 		if !force {
-			interval := DefaultRuleInterval
-			if rule.IntervalSeconds != nil {
-				interval = time.Duration(*rule.IntervalSeconds) * time.Second
+			// Event-triggered rules only run when their torrent event fires.
+			if rule.TriggerMode.IsEvent() {
+				continue
 			}
-			key := ruleKey{instanceID, rule.ID}
-			s.mu.RLock()
-			lastRun := s.lastRuleRun[key]
-			s.mu.RUnlock()
-			if now.Sub(lastRun) < interval {
-				continue // skip, interval not elapsed
+			if !s.ruleIsDue(instanceID, rule, now) {
+				continue // skip, outside active window or not yet due
 			}
 		}
 		eligibleRules = append(eligibleRules, rule)
@@ -2082,6 +2159,21 @@ func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, for
 		s.applyCrossMatchResult(evalCtx, s.buildCrossMatchSets(ctx, instanceID, needs))
 	}
 
+	// On-demand Sonarr/Radarr queue and import history
+	if rulesUseArrImportFields(eligibleRules) {
+		s.applyArrImportStatus(ctx, instanceID, evalCtx)
+	}
+
+	// Tracker seeding requirements (hit-and-run protection)
+	if rulesUseHnRFields(eligibleRules) {
+		s.applyTrackerRequirements(ctx, instanceID, evalCtx)
+	}
+
+	// Sampled upload history
+	if rulesUseTransferHistoryFields(eligibleRules) {
+		s.applyTransferHistory(ctx, instanceID, evalCtx)
+	}
+
 	// Get free space on instance (only if rules use FREE_SPACE field)
 	// Also pre-compute hardlink groups for FREE_SPACE projection if needed
 	if rulesUseCondition(eligibleRules, FieldFreeSpace) {
@@ -2168,6 +2260,12 @@ func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, for
 
 	// Skip checker for recently processed torrents
 	skipCheck := func(hash string) bool {
+		if scope != nil {
+			// Event-scoped runs target exactly the torrents that fired the event,
+			// so the recent-processing debounce does not apply to them.
+			_, inScope := scope[hash]
+			return !inScope
+		}
 		s.mu.RLock()
 		ts, exists := instLastApplied[hash]
 		s.mu.RUnlock()
@@ -2187,6 +2285,13 @@ func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, for
 		}
 	}
 
+	// Advance per-torrent match streaks before evaluating RULE_MATCH_STREAK/CONDITION_TRUE_FOR.
+	// On-demand dry runs (forced) read history but never advance the stored streaks.
+	var historySignatures map[int]string
+	if historyRules := rulesUseRuleHistoryFields(eligibleRules); len(historyRules) > 0 {
+		historySignatures = s.applyRuleHistory(ctx, instanceID, historyRules, torrents, evalCtx, skipCheck, now)
+	}
+
 	// Process all torrents through all eligible rules, batching by sort order
 	ruleStats := make(map[int]*ruleRunStats)
 	states := make(map[string]*torrentDesiredState)
@@ -2194,6 +2299,10 @@ func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, for
 	// Group rules into batches based on sorting config equality
 	s.buildAndExecuteBatches(instanceID, eligibleRules, torrents, evalCtx, skipCheck, ruleStats, states)
 
+	if len(historySignatures) > 0 && !(dryRun && force) {
+		s.saveRuleHistory(ctx, instanceID, evalCtx, historySignatures)
+	}
+
 	if len(states) == 0 {
 		log.Trace().
 			Int("instanceID", instanceID).
@@ -2230,6 +2339,7 @@ func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, for
 				Int("moveAlreadyAtDest", stats.MoveAlreadyAtDestination).
 				Int("moveBlockedByCrossSeed", stats.MoveBlockedByCrossSeed).
 				Int("exportToInstanceNoMatch", stats.ExportToInstanceConditionNotMet).
+				Int("webhookNoMatch", stats.WebhookConditionNotMet).
 				Msg("automations: rule matched trackers but applied no actions")
 		}
 	}
@@ -2284,6 +2394,8 @@ func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, for
 	var programExecutions []pendingProgramExec
 	// Export to instance execution tracking
 	var exportExecutions []pendingExportToInstance
+	// Webhook matches per rule
+	webhooksByRule := make(map[int]*pendingWebhook)
 	deleteHashesByMode := make(map[string][]string)
 	pendingByHash := make(map[string]pendingDeletion)
 
@@ -2660,6 +2772,9 @@ func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, for
 			moveRuleByHash[hash] = state.moveRule
 		}
 
+		// Webhooks
+		collectWebhookMatches(webhooksByRule, torrent, state, evalCtx)
+
 		// External program execution
 		if state.externalProgramID != nil {
 			programExecutions = append(programExecutions, pendingProgramExec{
@@ -2743,6 +2858,8 @@ func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, for
 		}
 	}
 
+	webhookExecutions := buildWebhookDeliveries(webhooksByRule)
+
 	if dryRun {
 		activities := s.recordDryRunActivities(
 			ctx,
@@ -2761,6 +2878,7 @@ func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, for
 			pendingByHash,
 			programExecutions,
 			exportExecutions,
+			webhookExecutions,
 			torrentByHash,
 			torrents,
 			states,
@@ -3772,6 +3890,9 @@ func (s *Service) applyRulesForInstance(ctx context.Context, instanceID int, for
 	// Execute external programs (async, fire-and-forget)
 	s.executeExternalProgramsFromAutomation(ctx, instanceID, programExecutions)
 
+	// Send webhooks (async, fire-and-forget)
+	s.executeWebhooks(ctx, instanceID, webhookExecutions)
+
 	// Execute export to instance — collect results before notification
 	exportResults := s.executeExportToInstance(ctx, instanceID, exportExecutions)
 
@@ -4932,8 +5053,19 @@ func ruleUsesCondition(rule *models.Automation, field ConditionField) bool {
 }
 
 func actionConditionsUseField(ac *models.ActionConditions, field ConditionField) bool {
+	for _, cond := range enabledActionConditions(ac) {
+		if conditionTreeUsesField(cond, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// enabledActionConditions returns the conditions of every enabled action, including
+// tag actions. Entries may be nil for actions without a condition.
+func enabledActionConditions(ac *models.ActionConditions) []*models.RuleCondition {
 	if ac == nil {
-		return false
+		return nil
 	}
 	conds := make([]*models.RuleCondition, 0, 10)
 	if ac.SpeedLimits != nil && ac.SpeedLimits.Enabled {
@@ -4972,18 +5104,15 @@ func actionConditionsUseField(ac *models.ActionConditions, field ConditionField)
 	if ac.ExportToInstance != nil && ac.ExportToInstance.Enabled {
 		conds = append(conds, ac.ExportToInstance.Condition)
 	}
-	for _, cond := range conds {
-		if conditionTreeUsesField(cond, field) {
-			return true
-		}
+	if ac.Webhook != nil && ac.Webhook.Enabled {
+		conds = append(conds, ac.Webhook.Condition)
 	}
 	for _, action := range ac.TagActions() {
-		if action != nil && action.Enabled && conditionTreeUsesField(action.Condition, field) {
-			return true
+		if action != nil && action.Enabled {
+			conds = append(conds, action.Condition)
 		}
 	}
-
-	return false
+	return conds
 }
 
 func conditionTreeUsesField(cond *models.RuleCondition, field ConditionField) bool {
@@ -5114,6 +5243,37 @@ func (s *Service) buildCrossMatchSets(ctx context.Context, instanceID int, needs
 	return &CrossMatchResult{}
 }
 
+// applyArrImportStatus loads the cached arr queue/import state into evalCtx.
+// On failure ArrImports stays nil so ARR_* conditions don't match.
+func (s *Service) applyArrImportStatus(ctx context.Context, instanceID int, evalCtx *EvalContext) {
+	if s.arrImports == nil || evalCtx == nil {
+		return
+	}
+	status, err := s.arrImports.ImportStatus(ctx)
+	if err != nil {
+		log.Warn().Err(err).Int("instanceID", instanceID).Msg("automations: failed to load arr import status, ARR_* conditions will not match")
+		return
+	}
+	evalCtx.ArrImports = status
+}
+
+// rulesUseArrImportFields checks if any enabled rule uses an ARR_* field.
+func rulesUseArrImportFields(rules []*models.Automation) bool {
+	return rulesUseCondition(rules, FieldArrImported) ||
+		rulesUseCondition(rules, FieldArrInQueue) ||
+		rulesUseCondition(rules, FieldArrImportAge)
+}
+
+// conditionUsesArrImportFields checks if a condition tree or sorting config uses an ARR_* field.
+func conditionUsesArrImportFields(cond *RuleCondition, config *models.SortingConfig) bool {
+	for _, field := range []ConditionField{FieldArrImported, FieldArrInQueue, FieldArrImportAge} {
+		if ConditionUsesField(cond, field) || sortingConfigUsesField(config, field) {
+			return true
+		}
+	}
+	return false
+}
+
 // applyCrossMatchResult populates the EvalContext with cross-match hash sets.
 func (s *Service) applyCrossMatchResult(evalCtx *EvalContext, result *CrossMatchResult) {
 	if result == nil {
@@ -5332,6 +5492,7 @@ func (s *Service) recordDryRunActivities(
 	pendingByHash map[string]pendingDeletion,
 	programExecutions []pendingProgramExec,
 	exportExecutions []pendingExportToInstance,
+	webhookExecutions []pendingWebhook,
 	torrentByHash map[string]qbt.Torrent,
 	torrents []qbt.Torrent,
 	states map[string]*torrentDesiredState,
@@ -5834,6 +5995,33 @@ func (s *Service) recordDryRunActivities(
 		}
 	}
 
+	// Webhooks
+	if len(webhookExecutions) > 0 {
+		type webhookGroup struct {
+			mode     string
+			requests int
+			hashes   []string
+		}
+		groups := make(map[int]*webhookGroup)
+		for _, delivery := range webhookExecutions {
+			group := groups[delivery.rule.id]
+			if group == nil {
+				group = &webhookGroup{mode: delivery.action.EffectiveMode()}
+				groups[delivery.rule.id] = group
+			}
+			group.requests++
+			for _, torrent := range delivery.torrents {
+				group.hashes = append(group.hashes, torrent.Hash)
+			}
+		}
+		for ruleID, group := range groups {
+			uniqueHashes := dedupeHashes(group.hashes)
+			createActivity(models.ActivityActionWebhook, map[string]any{"ruleId": ruleID, "mode": group.mode, "requests": group.requests, "count": len(uniqueHashes)}, func() []ActivityRunTorrent {
+				return buildRunItemsFromHashes(uniqueHashes, torrentByHash, s.syncManager)
+			})
+		}
+	}
+
 	// Export to instance
 	if len(exportExecutions) > 0 {
 		const alreadyExistsReason = "Already exists on target instance"
@@ -2142,6 +2142,7 @@ func TestRecordDryRunActivities_Deletes(t *testing.T) {
 		pending,
 		nil,
 		nil,
+		nil,
 		map[string]qbt.Torrent{"abc123": torrent},
 		[]qbt.Torrent{torrent},
 		map[string]*torrentDesiredState{},
@@ -2192,6 +2193,7 @@ func TestRecordDryRunActivities_Resumes(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		map[string]qbt.Torrent{"abc123": torrent},
 		[]qbt.Torrent{torrent},
 		map[string]*torrentDesiredState{},
@@ -2287,6 +2289,7 @@ func TestRecordDryRunActivities_Categories_IncludeCrossSeeds_DoesNotRequireCondi
 		nil,
 		nil,
 		nil,
+		nil,
 		torrentByHash,
 		torrents,
 		states,
@@ -2341,6 +2344,7 @@ func TestRecordDryRunActivities_NoMatches_LogsSummary(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		true,
 	)
 
@@ -2405,6 +2409,7 @@ func TestRecordDryRunActivities_CategoryUnknownGroupID_DoesNotPanicAndSkips(t *t
 			nil,
 			nil,
 			nil,
+			nil,
 			map[string]qbt.Torrent{"abc123": torrent},
 			[]qbt.Torrent{torrent},
 			states,
@@ -2507,6 +2512,7 @@ func TestRecordDryRunActivities_MoveGroupRequiresAllMembersMatchCondition(t *tes
 		nil,
 		nil,
 		nil,
+		nil,
 		torrentByHash,
 		torrents,
 		states,
@@ -2554,6 +2560,7 @@ func TestRecordDryRunActivities_NoMatches_DoesNotLogSummaryWhenDisabled(t *testi
 		nil,
 		nil,
 		nil,
+		nil,
 		false,
 	)
 
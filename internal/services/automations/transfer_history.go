@@ -0,0 +1,142 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"context"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// transferHistoryFields are the condition fields backed by sampled transfer counters.
+var transferHistoryFields = []ConditionField{FieldUploadedLast24h, FieldUploadedLast7d, FieldIdleUploadDays}
+
+const (
+	transferWindowDay  = 24 * time.Hour
+	transferWindowWeek = 7 * 24 * time.Hour
+)
+
+// transferHistory holds sampled transfer counters for the torrents of one instance.
+// Samples are hourly, so window boundaries are accurate to about an hour.
+type transferHistory struct {
+	// Latest is each torrent's newest sample.
+	Latest map[string]models.TorrentTransferSample
+	// DayAgo and WeekAgo are each torrent's newest sample at the start of the
+	// 24 hour and 7 day windows; torrents sampled only later are missing.
+	DayAgo  map[string]models.TorrentTransferSample
+	WeekAgo map[string]models.TorrentTransferSample
+	// NowUnix is the time the windows end at.
+	NowUnix int64
+}
+
+// uploadedInWindow returns how much the torrent uploaded since windowStart (unix
+// seconds) given its samples at that time. Torrents added inside the window count
+// all their upload. ok is false when the torrent has no baseline sample.
+func uploadedInWindow(torrent qbt.Torrent, baseline map[string]models.TorrentTransferSample, windowStart int64) (uploaded int64, ok bool) {
+	if torrent.AddedOn > 0 && torrent.AddedOn >= windowStart {
+		return torrent.Uploaded, true
+	}
+	sample, ok := baseline[torrent.Hash]
+	if !ok {
+		return 0, false
+	}
+	delta := torrent.Uploaded - sample.Uploaded
+	if delta < 0 {
+		// Counters went backwards, e.g. the torrent was re-added; only the new counter is known.
+		return torrent.Uploaded, true
+	}
+	return delta, true
+}
+
+// torrentUploadedLast returns the torrent's upload for UPLOADED_LAST_24H or UPLOADED_LAST_7D.
+// ok is false when history was not loaded or does not reach back far enough.
+func torrentUploadedLast(torrent qbt.Torrent, ctx *EvalContext, field ConditionField) (uploaded int64, ok bool) {
+	if ctx == nil || ctx.TransferHistory == nil {
+		return 0, false
+	}
+	h := ctx.TransferHistory
+	if field == FieldUploadedLast7d {
+		return uploadedInWindow(torrent, h.WeekAgo, h.NowUnix-int64(transferWindowWeek/time.Second))
+	}
+	return uploadedInWindow(torrent, h.DayAgo, h.NowUnix-int64(transferWindowDay/time.Second))
+}
+
+// torrentIdleUploadDays returns the days since the torrent last uploaded anything.
+// ok is false when history was not loaded or the torrent was never sampled.
+func torrentIdleUploadDays(torrent qbt.Torrent, ctx *EvalContext) (days float64, ok bool) {
+	if ctx == nil || ctx.TransferHistory == nil {
+		return 0, false
+	}
+	h := ctx.TransferHistory
+	sample, ok := h.Latest[torrent.Hash]
+	if !ok {
+		return 0, false
+	}
+	if torrent.Uploaded > sample.Uploaded {
+		// Uploaded since the last sample was taken.
+		return 0, true
+	}
+	idle := max(h.NowUnix-sample.LastUploadAt, 0)
+	return float64(idle) / float64(transferWindowDay/time.Second), true
+}
+
+// SetTransferSampleStore wires the store holding sampled transfer counters used by
+// UPLOADED_LAST_24H, UPLOADED_LAST_7D and IDLE_UPLOAD_DAYS. Safe to call once at startup.
+func (s *Service) SetTransferSampleStore(store *models.TorrentTransferSampleStore) {
+	if s == nil {
+		return
+	}
+	s.transferSampleStore = store
+}
+
+// applyTransferHistory loads sampled transfer counters into evalCtx.
+// On failure TransferHistory stays nil so the transfer history fields don't match.
+func (s *Service) applyTransferHistory(ctx context.Context, instanceID int, evalCtx *EvalContext) {
+	if s.transferSampleStore == nil || evalCtx == nil {
+		return
+	}
+	now := evaluateTime(evalCtx)
+
+	history := &transferHistory{NowUnix: now.Unix()}
+	for _, snapshot := range []struct {
+		dest *map[string]models.TorrentTransferSample
+		at   time.Time
+	}{
+		{&history.Latest, now},
+		{&history.DayAgo, now.Add(-transferWindowDay)},
+		{&history.WeekAgo, now.Add(-transferWindowWeek)},
+	} {
+		samples, err := s.transferSampleStore.LatestAt(ctx, instanceID, snapshot.at.Unix())
+		if err != nil {
+			log.Warn().Err(err).Int("instanceID", instanceID).Msg("automations: failed to load transfer history, UPLOADED_LAST_*/IDLE_UPLOAD_DAYS conditions will not match")
+			return
+		}
+		*snapshot.dest = samples
+	}
+	evalCtx.TransferHistory = history
+}
+
+// rulesUseTransferHistoryFields checks if any enabled rule uses a transfer history field.
+func rulesUseTransferHistoryFields(rules []*models.Automation) bool {
+	for _, field := range transferHistoryFields {
+		if rulesUseCondition(rules, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionUsesTransferHistoryFields checks if a condition tree or sorting config uses a transfer history field.
+func conditionUsesTransferHistoryFields(cond *RuleCondition, config *models.SortingConfig) bool {
+	for _, field := range transferHistoryFields {
+		if ConditionUsesField(cond, field) || sortingConfigUsesField(config, field) {
+			return true
+		}
+	}
+	return false
+}
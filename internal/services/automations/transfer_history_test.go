@@ -0,0 +1,85 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestTransferHistoryFields(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	day := int64(24 * time.Hour / time.Second)
+
+	ctx := &EvalContext{
+		NowUnix: now.Unix(),
+		TransferHistory: &transferHistory{
+			NowUnix: now.Unix(),
+			Latest: map[string]models.TorrentTransferSample{
+				"busy":  {Hash: "busy", Uploaded: 5_000, LastUploadAt: now.Unix()},
+				"idle":  {Hash: "idle", Uploaded: 1_000, LastUploadAt: now.Unix() - 3*day},
+				"reset": {Hash: "reset", Uploaded: 100, LastUploadAt: now.Unix()},
+			},
+			DayAgo: map[string]models.TorrentTransferSample{
+				"busy":  {Hash: "busy", Uploaded: 1_000},
+				"idle":  {Hash: "idle", Uploaded: 1_000},
+				"reset": {Hash: "reset", Uploaded: 9_000},
+			},
+			WeekAgo: map[string]models.TorrentTransferSample{
+				"idle": {Hash: "idle", Uploaded: 200},
+			},
+		},
+	}
+
+	busy := qbt.Torrent{Hash: "busy", Uploaded: 6_000, AddedOn: now.Unix() - 30*day}
+	idle := qbt.Torrent{Hash: "idle", Uploaded: 1_000, AddedOn: now.Unix() - 30*day}
+	reset := qbt.Torrent{Hash: "reset", Uploaded: 100, AddedOn: now.Unix() - 30*day}
+	fresh := qbt.Torrent{Hash: "fresh", Uploaded: 300, AddedOn: now.Unix() - 3600}
+	unknown := qbt.Torrent{Hash: "unknown", Uploaded: 300, AddedOn: now.Unix() - 30*day}
+
+	uploaded := func(field ConditionField, torrent qbt.Torrent) (int64, bool) {
+		return torrentUploadedLast(torrent, ctx, field)
+	}
+	assertValue := func(want int64, field ConditionField, torrent qbt.Torrent) {
+		t.Helper()
+		got, ok := uploaded(field, torrent)
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+	assertValue(5_000, FieldUploadedLast24h, busy)
+	assertValue(0, FieldUploadedLast24h, idle)
+	assertValue(800, FieldUploadedLast7d, idle)
+	assertValue(100, FieldUploadedLast24h, reset)
+	assertValue(300, FieldUploadedLast7d, fresh)
+
+	_, ok := uploaded(FieldUploadedLast7d, busy)
+	assert.False(t, ok, "history that doesn't reach the window start is unknown")
+	_, ok = uploaded(FieldUploadedLast24h, unknown)
+	assert.False(t, ok)
+
+	days, ok := torrentIdleUploadDays(idle, ctx)
+	assert.True(t, ok)
+	assert.InDelta(t, 3.0, days, 0.001)
+	days, ok = torrentIdleUploadDays(busy, ctx)
+	assert.True(t, ok)
+	assert.Zero(t, days, "upload since the newest sample counts as active")
+	_, ok = torrentIdleUploadDays(unknown, ctx)
+	assert.False(t, ok)
+
+	idleRule := &RuleCondition{Field: FieldIdleUploadDays, Operator: OperatorGreaterThan, Value: "2"}
+	assert.True(t, EvaluateConditionWithContext(idleRule, idle, ctx, 0))
+	assert.False(t, EvaluateConditionWithContext(idleRule, busy, ctx, 0))
+
+	lowUpload := &RuleCondition{Field: FieldUploadedLast24h, Operator: OperatorLessThan, Value: "1"}
+	assert.True(t, EvaluateConditionWithContext(lowUpload, idle, ctx, 0))
+	assert.False(t, EvaluateConditionWithContext(lowUpload, unknown, ctx, 0), "unknown history never matches")
+	assert.False(t, EvaluateConditionWithContext(lowUpload, idle, &EvalContext{}, 0))
+
+	assert.InDelta(t, 5_000, getNumericFieldValue(busy, FieldUploadedLast24h, ctx), 0)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// triggerDebounce coalesces bursts of torrent events (a season pack being added,
+// a queue of torrents finishing together) into one scoped run per instance and trigger.
+const triggerDebounce = 2 * time.Second
+
+type triggerBatchKey struct {
+	instanceID int
+	trigger    models.AutomationTrigger
+}
+
+// triggerQueue collects hashes per instance/trigger and flushes each batch once
+// no new event has arrived for the debounce window.
+type triggerQueue struct {
+	debounce time.Duration
+	mu       sync.Mutex
+	pending  map[triggerBatchKey]map[string]struct{}
+	timers   map[triggerBatchKey]*time.Timer
+}
+
+func newTriggerQueue(debounce time.Duration) *triggerQueue {
+	return &triggerQueue{
+		debounce: debounce,
+		pending:  make(map[triggerBatchKey]map[string]struct{}),
+		timers:   make(map[triggerBatchKey]*time.Timer),
+	}
+}
+
+// add records hash for key and (re)arms the flush timer.
+func (q *triggerQueue) add(key triggerBatchKey, hash string, flush func(key triggerBatchKey)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	hashes := q.pending[key]
+	if hashes == nil {
+		hashes = make(map[string]struct{})
+		q.pending[key] = hashes
+	}
+	hashes[hash] = struct{}{}
+
+	if timer, ok := q.timers[key]; ok {
+		timer.Reset(q.debounce)
+		return
+	}
+	q.timers[key] = time.AfterFunc(q.debounce, func() { flush(key) })
+}
+
+// take removes and returns the pending hashes for key.
+func (q *triggerQueue) take(key triggerBatchKey) map[string]struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	hashes := q.pending[key]
+	delete(q.pending, key)
+	delete(q.timers, key)
+	return hashes
+}
+
+// HandleTorrentAdded queues rules with the onAdded trigger for a newly seen torrent.
+func (s *Service) HandleTorrentAdded(_ context.Context, instanceID int, torrent qbt.Torrent) {
+	s.enqueueTrigger(instanceID, models.TriggerOnAdded, torrent.Hash)
+}
+
+// HandleTorrentCompleted queues rules with the onCompleted trigger for a finished torrent.
+func (s *Service) HandleTorrentCompleted(_ context.Context, instanceID int, torrent qbt.Torrent) {
+	s.enqueueTrigger(instanceID, models.TriggerOnCompleted, torrent.Hash)
+}
+
+// HandleTorrentStateChange queues rules with the onStateChange trigger for a torrent
+// whose state changed since the previous sync.
+func (s *Service) HandleTorrentStateChange(_ context.Context, instanceID int, torrent qbt.Torrent, _ qbt.TorrentState) {
+	s.enqueueTrigger(instanceID, models.TriggerOnStateChange, torrent.Hash)
+}
+
+func (s *Service) enqueueTrigger(instanceID int, trigger models.AutomationTrigger, hash string) {
+	if s == nil || s.triggers == nil || instanceID <= 0 {
+		return
+	}
+	hash = strings.TrimSpace(hash)
+	if hash == "" {
+		return
+	}
+	s.triggers.add(triggerBatchKey{instanceID: instanceID, trigger: trigger}, hash, s.flushTrigger)
+}
+
+func (s *Service) flushTrigger(key triggerBatchKey) {
+	hashes := s.triggers.take(key)
+	if len(hashes) == 0 {
+		return
+	}
+
+	s.mu.RLock()
+	baseCtx := s.triggerCtx
+	s.mu.RUnlock()
+	if baseCtx == nil {
+		baseCtx = context.Background()
+	}
+	if baseCtx.Err() != nil {
+		return
+	}
+
+	if err := s.applyTriggeredRules(baseCtx, key.instanceID, key.trigger, hashes); err != nil {
+		log.Error().
+			Err(err).
+			Int("instanceID", key.instanceID).
+			Str("trigger", string(key.trigger)).
+			Msg("automations: triggered apply failed")
+	}
+}
+
+// applyTriggeredRules runs every enabled rule using trigger against the given hashes only.
+// Dry-run rules record simulated activity exactly like interval runs.
+func (s *Service) applyTriggeredRules(ctx context.Context, instanceID int, trigger models.AutomationTrigger, hashes map[string]struct{}) error {
+	if s == nil || s.syncManager == nil || s.ruleStore == nil || s.instanceStore == nil || len(hashes) == 0 {
+		return nil
+	}
+
+	instance, err := s.instanceStore.Get(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	if !instance.IsActive {
+		return nil
+	}
+
+	rules, err := s.ruleStore.ListForInstance(ctx, instanceID)
+	if err != nil {
+		log.Error().Err(err).Int("instanceID", instanceID).Msg("automations: failed to load rules")
+		s.notifyAutomationFailure(ctx, instanceID, err)
+		return err
+	}
+
+	triggered := rulesForTrigger(rules, trigger, time.Now())
+	if len(triggered) == 0 {
+		return nil
+	}
+
+	log.Debug().
+		Int("instanceID", instanceID).
+		Str("trigger", string(trigger)).
+		Int("rules", len(triggered)).
+		Int("torrents", len(hashes)).
+		Msg("automations: running event-triggered rules")
+
+	// force bypasses the interval cadence; the scope restricts processing to
+	// the torrents that fired the event.
+	return s.applyRuleSetForInstance(ctx, instanceID, true, triggered, hashes)
+}
+
+// rulesForTrigger returns the enabled rules configured for trigger whose active
+// window (if any) contains now, preserving sort order.
+func rulesForTrigger(rules []*models.Automation, trigger models.AutomationTrigger, now time.Time) []*models.Automation {
+	var matched []*models.Automation
+	for _, rule := range rules {
+		if rule == nil || !rule.Enabled {
+			continue
+		}
+		if models.NormalizeAutomationTrigger(rule.TriggerMode) != trigger {
+			continue
+		}
+		if !rule.Schedule.InWindow(now) {
+			continue
+		}
+		matched = append(matched, rule)
+	}
+	return matched
+}
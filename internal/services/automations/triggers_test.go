@@ -0,0 +1,98 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestRulesForTrigger(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC) // Wednesday
+	nightOnly := &models.AutomationSchedule{
+		Timezone:     "UTC",
+		ActiveWindow: &models.ActiveWindow{Start: "01:00", End: "06:00"},
+	}
+
+	rules := []*models.Automation{
+		{ID: 1, Enabled: true},
+		{ID: 2, Enabled: true, TriggerMode: models.TriggerOnAdded},
+		{ID: 3, Enabled: false, TriggerMode: models.TriggerOnAdded},
+		{ID: 4, Enabled: true, TriggerMode: models.TriggerOnCompleted},
+		{ID: 5, Enabled: true, TriggerMode: models.TriggerOnAdded},
+		{ID: 6, Enabled: true, TriggerMode: models.TriggerOnAdded, Schedule: nightOnly},
+		nil,
+	}
+
+	ids := func(list []*models.Automation) []int {
+		out := make([]int, 0, len(list))
+		for _, r := range list {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []int{2, 5}, ids(rulesForTrigger(rules, models.TriggerOnAdded, now)))
+	assert.Equal(t, []int{4}, ids(rulesForTrigger(rules, models.TriggerOnCompleted, now)))
+	assert.Empty(t, rulesForTrigger(rules, models.TriggerOnStateChange, now))
+	// Empty trigger mode is the legacy interval behavior.
+	assert.Equal(t, []int{1}, ids(rulesForTrigger(rules, models.TriggerInterval, now)))
+}
+
+func TestTriggerQueueCoalescesBurstIntoOneFlush(t *testing.T) {
+	t.Parallel()
+
+	q := newTriggerQueue(50 * time.Millisecond)
+	key := triggerBatchKey{instanceID: 1, trigger: models.TriggerOnAdded}
+	otherKey := triggerBatchKey{instanceID: 1, trigger: models.TriggerOnCompleted}
+
+	var mu sync.Mutex
+	flushed := make(map[triggerBatchKey][]map[string]struct{})
+	done := make(chan struct{}, 4)
+	flush := func(k triggerBatchKey) {
+		hashes := q.take(k)
+		mu.Lock()
+		flushed[k] = append(flushed[k], hashes)
+		mu.Unlock()
+		done <- struct{}{}
+	}
+
+	q.add(key, "aaa", flush)
+	q.add(key, "bbb", flush)
+	q.add(key, "aaa", flush)
+	q.add(otherKey, "ccc", flush)
+
+	for range 2 {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for trigger flush")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, flushed[key], 1)
+	assert.Equal(t, map[string]struct{}{"aaa": {}, "bbb": {}}, flushed[key][0])
+	require.Len(t, flushed[otherKey], 1)
+	assert.Equal(t, map[string]struct{}{"ccc": {}}, flushed[otherKey][0])
+}
+
+func TestNormalizeAutomationTrigger(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, models.TriggerInterval, models.NormalizeAutomationTrigger(""))
+	assert.Equal(t, models.TriggerInterval, models.NormalizeAutomationTrigger("bogus"))
+	assert.Equal(t, models.TriggerOnStateChange, models.NormalizeAutomationTrigger(" onStateChange "))
+	assert.True(t, models.TriggerOnAdded.IsEvent())
+	assert.False(t, models.TriggerInterval.IsEvent())
+}
@@ -0,0 +1,405 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+// WebhookEvent is the event name sent in every webhook payload.
+const WebhookEvent = "automation.matched"
+
+const (
+	// webhookConcurrency bounds in-flight requests per automation run.
+	webhookConcurrency = 4
+	// webhookMaxResponseBytes is how much of a response body is read (and drained).
+	webhookMaxResponseBytes = 4096
+)
+
+var (
+	webhookHTTPClient = &http.Client{}
+	// webhookRetryBackoff is multiplied by the attempt number between retries.
+	webhookRetryBackoff = 2 * time.Second
+)
+
+// WebhookRule identifies the rule that fired a webhook.
+type WebhookRule struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// WebhookInstance identifies the qBittorrent instance the rule ran on.
+type WebhookInstance struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// WebhookTorrent is the per-torrent data exposed to webhook payloads and templates.
+type WebhookTorrent struct {
+	Name         string   `json:"name"`
+	Hash         string   `json:"hash"`
+	Tracker      string   `json:"tracker"`
+	Category     string   `json:"category"`
+	Tags         []string `json:"tags"`
+	SavePath     string   `json:"savePath"`
+	ContentPath  string   `json:"contentPath"`
+	DownloadPath string   `json:"downloadPath,omitempty"`
+	State        string   `json:"state"`
+	Size         int64    `json:"size"`
+	Ratio        float64  `json:"ratio"`
+}
+
+// WebhookPayload is the default JSON body and the data passed to body templates.
+// Torrent is set only in per-torrent mode.
+type WebhookPayload struct {
+	Event     string           `json:"event"`
+	Rule      WebhookRule      `json:"rule"`
+	Instance  WebhookInstance  `json:"instance"`
+	Torrents  []WebhookTorrent `json:"torrents"`
+	Torrent   *WebhookTorrent  `json:"torrent,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// pendingWebhook is one request to send: a rule's matches for the run in batch
+// mode, or a single torrent in per-torrent mode.
+type pendingWebhook struct {
+	rule     ruleRef
+	action   *models.WebhookAction
+	torrents []WebhookTorrent
+}
+
+var webhookTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": strings.Join,
+}
+
+// ValidateWebhookTemplate checks that a body template parses and renders valid
+// JSON for a sample payload. An empty template uses the default payload.
+func ValidateWebhookTemplate(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return nil
+	}
+	sample := WebhookTorrent{
+		Name:     "Example.Release.2026.1080p.WEB-DL",
+		Hash:     strings.Repeat("0", 40),
+		Tracker:  "tracker.example",
+		Category: "tv",
+		Tags:     []string{"example"},
+		SavePath: "/downloads",
+	}
+	_, err := renderWebhookBody(body, WebhookPayload{
+		Event:     WebhookEvent,
+		Rule:      WebhookRule{ID: 1, Name: "Example"},
+		Instance:  WebhookInstance{ID: 1, Name: "qBittorrent"},
+		Torrents:  []WebhookTorrent{sample},
+		Torrent:   &sample,
+		Timestamp: time.Now().UTC(),
+	})
+	return err
+}
+
+// renderWebhookBody executes the body template against payload, or marshals the
+// payload when no template is configured.
+func renderWebhookBody(body string, payload WebhookPayload) ([]byte, error) {
+	if strings.TrimSpace(body) == "" {
+		return json.Marshal(payload)
+	}
+
+	tmpl, err := template.New("webhookBody").Funcs(webhookTemplateFuncs).Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, payload); err != nil {
+		return nil, err
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, errors.New("rendered body is not valid JSON")
+	}
+	return buf.Bytes(), nil
+}
+
+// newWebhookTorrent snapshots the payload fields for a matched torrent.
+func newWebhookTorrent(torrent qbt.Torrent, state *torrentDesiredState, evalCtx *EvalContext) WebhookTorrent {
+	tracker := ""
+	if state != nil {
+		tracker = selectTrackerTag(state.trackerDomains, false, evalCtx)
+	}
+	tags := []string{}
+	for tag := range strings.SplitSeq(torrent.Tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return WebhookTorrent{
+		Name:         torrent.Name,
+		Hash:         torrent.Hash,
+		Tracker:      tracker,
+		Category:     torrent.Category,
+		Tags:         tags,
+		SavePath:     torrent.SavePath,
+		ContentPath:  torrent.ContentPath,
+		DownloadPath: torrent.DownloadPath,
+		State:        string(torrent.State),
+		Size:         torrent.Size,
+		Ratio:        torrent.Ratio,
+	}
+}
+
+// collectWebhookMatches adds the webhooks a torrent's state fired to byRule,
+// keyed by rule ID so batch mode sends one request per rule.
+func collectWebhookMatches(byRule map[int]*pendingWebhook, torrent qbt.Torrent, state *torrentDesiredState, evalCtx *EvalContext) {
+	if len(state.webhooks) == 0 {
+		return
+	}
+	payloadTorrent := newWebhookTorrent(torrent, state, evalCtx)
+	for _, match := range state.webhooks {
+		pending := byRule[match.rule.id]
+		if pending == nil {
+			pending = &pendingWebhook{rule: match.rule, action: match.action}
+			byRule[match.rule.id] = pending
+		}
+		pending.torrents = append(pending.torrents, payloadTorrent)
+	}
+}
+
+// buildWebhookDeliveries flattens collected matches into requests, splitting
+// per-torrent rules into one request per torrent. Output is ordered by rule ID
+// and torrent name so runs are reproducible.
+func buildWebhookDeliveries(byRule map[int]*pendingWebhook) []pendingWebhook {
+	if len(byRule) == 0 {
+		return nil
+	}
+
+	ruleIDs := make([]int, 0, len(byRule))
+	for id := range byRule {
+		ruleIDs = append(ruleIDs, id)
+	}
+	slices.Sort(ruleIDs)
+
+	var deliveries []pendingWebhook
+	for _, id := range ruleIDs {
+		pending := byRule[id]
+		slices.SortFunc(pending.torrents, func(a, b WebhookTorrent) int {
+			if c := strings.Compare(a.Name, b.Name); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Hash, b.Hash)
+		})
+		if pending.action.EffectiveMode() == models.WebhookModePerTorrent {
+			for _, torrent := range pending.torrents {
+				deliveries = append(deliveries, pendingWebhook{
+					rule:     pending.rule,
+					action:   pending.action,
+					torrents: []WebhookTorrent{torrent},
+				})
+			}
+			continue
+		}
+		deliveries = append(deliveries, *pending)
+	}
+	return deliveries
+}
+
+// webhookResult is the outcome of one delivery.
+type webhookResult struct {
+	status   int
+	attempts int
+	err      error
+}
+
+// executeWebhooks sends deliveries in the background and records one activity
+// per request. Like external programs, delivery outlives the automation pass.
+func (s *Service) executeWebhooks(ctx context.Context, instanceID int, deliveries []pendingWebhook) {
+	if len(deliveries) == 0 {
+		return
+	}
+
+	instance := WebhookInstance{ID: instanceID}
+	if s.instanceStore != nil {
+		if inst, err := s.instanceStore.Get(ctx, instanceID); err == nil && inst != nil {
+			instance.Name = inst.Name
+		}
+	}
+
+	log.Debug().
+		Int("instanceID", instanceID).
+		Int("deliveries", len(deliveries)).
+		Msg("automations: sending webhooks")
+
+	go func() { //nolint:gosec // G118: webhook delivery runs past the automation pass that queued it
+		sem := make(chan struct{}, webhookConcurrency)
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			sem <- struct{}{}
+			wg.Go(func() {
+				defer func() { <-sem }()
+				s.sendWebhook(context.Background(), instance, delivery)
+			})
+		}
+		wg.Wait()
+	}()
+}
+
+// sendWebhook renders and sends one delivery, then records its activity.
+func (s *Service) sendWebhook(ctx context.Context, instance WebhookInstance, delivery pendingWebhook) {
+	payload := WebhookPayload{
+		Event:     WebhookEvent,
+		Rule:      WebhookRule{ID: delivery.rule.id, Name: delivery.rule.name},
+		Instance:  instance,
+		Torrents:  delivery.torrents,
+		Timestamp: time.Now().UTC(),
+	}
+	mode := delivery.action.EffectiveMode()
+	if mode == models.WebhookModePerTorrent && len(delivery.torrents) == 1 {
+		payload.Torrent = &delivery.torrents[0]
+	}
+
+	var result webhookResult
+	body, err := renderWebhookBody(delivery.action.BodyTemplate, payload)
+	if err != nil {
+		result.err = fmt.Errorf("render body: %w", err)
+	} else {
+		result = deliverWebhook(ctx, delivery.action, body)
+	}
+
+	logEvent := log.Debug()
+	if result.err != nil {
+		logEvent = log.Warn().Err(result.err)
+	}
+	logEvent.
+		Int("instanceID", instance.ID).
+		Int("ruleID", delivery.rule.id).
+		Str("ruleName", delivery.rule.name).
+		Str("host", webhookHost(delivery.action.URL)).
+		Int("status", result.status).
+		Int("attempts", result.attempts).
+		Int("torrents", len(delivery.torrents)).
+		Msg("automations: webhook delivered")
+
+	if s.activityStore == nil {
+		return
+	}
+
+	ruleID := delivery.rule.id
+	activity := &models.AutomationActivity{
+		InstanceID: instance.ID,
+		Action:     models.ActivityActionWebhook,
+		RuleID:     &ruleID,
+		RuleName:   delivery.rule.name,
+		Outcome:    models.ActivityOutcomeSuccess,
+	}
+	if payload.Torrent != nil {
+		activity.Hash = payload.Torrent.Hash
+		activity.TorrentName = payload.Torrent.Name
+		activity.TrackerDomain = payload.Torrent.Tracker
+	}
+	if result.err != nil {
+		activity.Outcome = models.ActivityOutcomeFailed
+		activity.Reason = result.err.Error()
+	}
+	activity.Details, _ = json.Marshal(map[string]any{
+		"host":     webhookHost(delivery.action.URL),
+		"status":   result.status,
+		"attempts": result.attempts,
+		"count":    len(delivery.torrents),
+		"mode":     mode,
+	})
+	if err := s.activityStore.Create(ctx, activity); err != nil {
+		log.Warn().Err(err).Int("ruleID", ruleID).Msg("automations: failed to record webhook activity")
+	}
+}
+
+// deliverWebhook POSTs body to the action URL, retrying network errors, 429 and
+// 5xx responses up to action.Retries times.
+func deliverWebhook(ctx context.Context, action *models.WebhookAction, body []byte) webhookResult {
+	var result webhookResult
+	for attempt := 0; attempt <= action.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				result.err = ctx.Err()
+				return result
+			case <-time.After(time.Duration(attempt) * webhookRetryBackoff):
+			}
+		}
+
+		result.attempts = attempt + 1
+		status, err := postWebhook(ctx, action, body)
+		result.status = status
+		result.err = err
+		if err == nil {
+			return result
+		}
+		if status != 0 && status != http.StatusTooManyRequests && status < http.StatusInternalServerError {
+			return result
+		}
+	}
+	return result
+}
+
+// postWebhook sends a single request. A non-2xx status is returned with an error.
+func postWebhook(ctx context.Context, action *models.WebhookAction, body []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, action.Timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSpace(action.URL), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "qui")
+	for name, value := range action.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := webhookHTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBytes))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := strings.TrimSpace(string(snippet))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		if msg == "" {
+			return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, msg)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookHost returns the URL host for logs and activity details. The full URL
+// is never recorded because it commonly carries a token.
+func webhookHost(rawURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+	return parsed.Host
+}
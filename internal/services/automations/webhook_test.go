@@ -0,0 +1,169 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package automations
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+)
+
+func TestRenderWebhookBody(t *testing.T) {
+	payload := WebhookPayload{
+		Event:    WebhookEvent,
+		Rule:     WebhookRule{ID: 7, Name: "Cleanup"},
+		Instance: WebhookInstance{ID: 1, Name: "seedbox"},
+		Torrents: []WebhookTorrent{{Name: "A \"quoted\" name", Hash: "abc", Tags: []string{"x", "y"}}},
+	}
+
+	t.Run("default payload", func(t *testing.T) {
+		body, err := renderWebhookBody("", payload)
+		require.NoError(t, err)
+
+		var decoded WebhookPayload
+		require.NoError(t, json.Unmarshal(body, &decoded))
+		assert.Equal(t, "Cleanup", decoded.Rule.Name)
+		assert.Equal(t, "abc", decoded.Torrents[0].Hash)
+	})
+
+	t.Run("template escapes with json", func(t *testing.T) {
+		body, err := renderWebhookBody(`{"content":{{ json (printf "%s matched %d" .Rule.Name (len .Torrents)) }},"names":[{{ range $i, $t := .Torrents }}{{ if $i }},{{ end }}{{ json $t.Name }}{{ end }}],"tags":{{ json (join (index .Torrents 0).Tags ",") }}}`, payload)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"content":"Cleanup matched 1","names":["A \"quoted\" name"],"tags":"x,y"}`, string(body))
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := renderWebhookBody(`{"name": {{ .Rule.Name }}}`, payload)
+		require.Error(t, err)
+	})
+}
+
+func TestValidateWebhookTemplate(t *testing.T) {
+	require.NoError(t, ValidateWebhookTemplate(""))
+	require.NoError(t, ValidateWebhookTemplate(`{"hash":{{ json .Torrent.Hash }}}`))
+	require.Error(t, ValidateWebhookTemplate(`{{ .Missing }`))
+	require.Error(t, ValidateWebhookTemplate(`{{ .NoSuchField }}`))
+	require.Error(t, ValidateWebhookTemplate(`not json`))
+}
+
+func TestBuildWebhookDeliveries(t *testing.T) {
+	batch := &models.WebhookAction{Enabled: true, URL: "http://example.invalid"}
+	perTorrent := &models.WebhookAction{Enabled: true, URL: "http://example.invalid", Mode: models.WebhookModePerTorrent}
+
+	byRule := make(map[int]*pendingWebhook)
+	for _, torrent := range []qbt.Torrent{{Hash: "b", Name: "Beta"}, {Hash: "a", Name: "Alpha"}} {
+		state := &torrentDesiredState{webhooks: []webhookMatch{
+			{rule: ruleRef{id: 2, name: "per torrent"}, action: perTorrent},
+			{rule: ruleRef{id: 1, name: "batch"}, action: batch},
+		}}
+		collectWebhookMatches(byRule, torrent, state, nil)
+	}
+
+	deliveries := buildWebhookDeliveries(byRule)
+	require.Len(t, deliveries, 3)
+
+	assert.Equal(t, 1, deliveries[0].rule.id)
+	require.Len(t, deliveries[0].torrents, 2)
+	assert.Equal(t, "Alpha", deliveries[0].torrents[0].Name)
+
+	assert.Equal(t, 2, deliveries[1].rule.id)
+	assert.Equal(t, []WebhookTorrent{{Name: "Alpha", Hash: "a", Tags: []string{}}}, deliveries[1].torrents)
+	assert.Equal(t, "Beta", deliveries[2].torrents[0].Name)
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	prevBackoff := webhookRetryBackoff
+	webhookRetryBackoff = time.Millisecond
+	t.Cleanup(func() { webhookRetryBackoff = prevBackoff })
+
+	t.Run("retries server errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"ok":true}`, string(body))
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		action := &models.WebhookAction{
+			Enabled: true,
+			URL:     server.URL,
+			Headers: map[string]string{"Authorization": "Bearer token"},
+			Retries: 3,
+		}
+		result := deliverWebhook(context.Background(), action, []byte(`{"ok":true}`))
+
+		require.NoError(t, result.err)
+		assert.Equal(t, http.StatusNoContent, result.status)
+		assert.Equal(t, 3, result.attempts)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			http.Error(w, "bad payload", http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		action := &models.WebhookAction{Enabled: true, URL: server.URL, Retries: 3}
+		result := deliverWebhook(context.Background(), action, []byte(`{}`))
+
+		require.ErrorContains(t, result.err, "bad payload")
+		assert.Equal(t, http.StatusBadRequest, result.status)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		action := &models.WebhookAction{Enabled: true, URL: server.URL, TimeoutSeconds: 1}
+		result := deliverWebhook(context.Background(), action, []byte(`{}`))
+
+		require.Error(t, result.err)
+		assert.Equal(t, 0, result.status)
+		assert.Equal(t, 1, result.attempts)
+	})
+}
+
+func TestWebhookActionValidate(t *testing.T) {
+	valid := &models.WebhookAction{Enabled: true, URL: "https://hooks.example.com/x", Mode: models.WebhookModePerTorrent, Retries: 2, TimeoutSeconds: 30}
+	require.NoError(t, valid.Validate())
+	require.NoError(t, (&models.WebhookAction{URL: "ftp://disabled"}).Validate())
+
+	for _, action := range []*models.WebhookAction{
+		{Enabled: true, URL: ""},
+		{Enabled: true, URL: "ftp://example.com"},
+		{Enabled: true, URL: "https://example.com", Mode: "sometimes"},
+		{Enabled: true, URL: "https://example.com", Retries: models.WebhookMaxRetries + 1},
+		{Enabled: true, URL: "https://example.com", TimeoutSeconds: models.WebhookMaxTimeoutSeconds + 1},
+	} {
+		assert.Error(t, action.Validate(), "%+v", action)
+	}
+}
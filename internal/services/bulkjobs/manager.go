@@ -0,0 +1,593 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package bulkjobs runs torrent bulk actions as server-side jobs. Hashes are
+// processed in chunks so progress can be reported and a job can be canceled
+// between chunks. For reversible actions the values a job overwrites are
+// captured before each chunk, so the most recent job can be undone.
+//
+// Jobs live in memory only; they are lost when qui restarts.
+package bulkjobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/services/activity"
+)
+
+const (
+	// ChunkSize is the number of hashes sent to a client per call.
+	ChunkSize = 200
+	// maxJobs bounds how many finished jobs are kept for listing and undo.
+	maxJobs = 50
+	// maxJobErrors bounds the per-hash errors kept on a single job.
+	maxJobErrors = 1000
+	queueSize    = 100
+)
+
+var (
+	ErrJobNotFound    = errors.New("bulk job not found")
+	ErrJobFinished    = errors.New("bulk job already finished")
+	ErrNothingToUndo  = errors.New("no bulk job to undo")
+	ErrQueueFull      = errors.New("too many bulk jobs queued")
+	ErrNoTargets      = errors.New("bulk job has no targets")
+	ErrNotReversible  = errors.New("bulk action cannot be undone")
+	errJobCanceled    = errors.New("bulk job canceled")
+	reversibleActions = []string{
+		"setCategory", "addTags", "removeTags", "setTags",
+		"setShareLimit", "setUploadLimit", "setDownloadLimit", "setLocation",
+	}
+)
+
+// IsReversible reports whether action can be undone by a later undo job.
+func IsReversible(action string) bool {
+	return slices.Contains(reversibleActions, action)
+}
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+func (s Status) finished() bool {
+	return s == StatusCompleted || s == StatusFailed || s == StatusCanceled
+}
+
+// HashError is the error a single torrent hit during a job.
+type HashError struct {
+	InstanceID int    `json:"instanceId"`
+	Hash       string `json:"hash"`
+	Error      string `json:"error"`
+}
+
+// Job is a snapshot of a bulk job's state.
+type Job struct {
+	ID          int         `json:"id"`
+	Action      string      `json:"action"`
+	InstanceIDs []int       `json:"instanceIds"`
+	Status      Status      `json:"status"`
+	Total       int         `json:"total"`
+	Processed   int         `json:"processed"`
+	Failed      int         `json:"failed"`
+	Errors      []HashError `json:"errors,omitempty"`
+	Reversible  bool        `json:"reversible"`
+	UndoOf      int         `json:"undoOf,omitempty"`
+	UndoneBy    int         `json:"undoneBy,omitempty"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	StartedAt   *time.Time  `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time  `json:"finishedAt,omitempty"`
+}
+
+// RunFunc applies a job's action to hashes on one instance.
+type RunFunc func(ctx context.Context, instanceID int, hashes []string) error
+
+// Spec describes a job to submit.
+type Spec struct {
+	Action string
+	// Targets are the hashes to act on, by instance.
+	Targets map[int][]string
+	Run     RunFunc
+}
+
+// Client is the part of the sync manager used to capture and restore the
+// values reversible actions overwrite.
+type Client interface {
+	GetTorrents(ctx context.Context, instanceID int, filter qbt.TorrentFilterOptions) ([]qbt.Torrent, error)
+	SetCategory(ctx context.Context, instanceID int, hashes []string, category string) error
+	SetTags(ctx context.Context, instanceID int, hashes []string, tags string) error
+	SetTorrentShareLimit(ctx context.Context, instanceID int, hashes []string, ratioLimit float64, seedingTimeLimit, inactiveSeedingTimeLimit int64, shareLimitAction, shareLimitsMode string) error
+	SetTorrentUploadLimit(ctx context.Context, instanceID int, hashes []string, limitKBs int64) error
+	SetTorrentDownloadLimit(ctx context.Context, instanceID int, hashes []string, limitKBs int64) error
+	SetLocation(ctx context.Context, instanceID int, hashes []string, location string) error
+	SetAutoTMM(ctx context.Context, instanceID int, hashes []string, enable bool) error
+}
+
+// priorState holds the values of one torrent before a reversible job changed it.
+type priorState struct {
+	Category                 string
+	Tags                     string
+	RatioLimit               float64
+	SeedingTimeLimit         int64
+	InactiveSeedingTimeLimit int64
+	ShareLimitAction         string
+	ShareLimitsMode          string
+	UpLimit                  int64
+	DlLimit                  int64
+	SavePath                 string
+	AutoManaged              bool
+}
+
+type job struct {
+	Job
+	spec   Spec
+	cancel context.CancelFunc
+	// prior maps instance ID to hash to the captured values.
+	prior map[int]map[string]priorState
+}
+
+type Manager struct {
+	client Client
+
+	mu     sync.Mutex
+	jobs   []*job
+	nextID int
+	queue  chan *job
+
+	activityPublisher activity.Publisher
+	now               func() time.Time
+}
+
+func NewManager(client Client) *Manager {
+	return &Manager{
+		client:            client,
+		nextID:            1,
+		queue:             make(chan *job, queueSize),
+		activityPublisher: activity.NopPublisher{},
+		now:               func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SetActivityPublisher wires the qui server-event hub so job progress is pushed
+// to connected clients. Safe to call once at startup.
+func (m *Manager) SetActivityPublisher(publisher activity.Publisher) {
+	if m == nil || publisher == nil {
+		return
+	}
+	m.activityPublisher = publisher
+}
+
+// Start runs queued jobs one at a time until ctx is done.
+func (m *Manager) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case j := <-m.queue:
+				m.run(ctx, j)
+			}
+		}
+	}()
+}
+
+// Submit queues a job and returns its initial state.
+func (m *Manager) Submit(spec Spec) (*Job, error) {
+	return m.submit(spec, 0)
+}
+
+func (m *Manager) submit(spec Spec, undoOf int) (*Job, error) {
+	total := 0
+	for _, hashes := range spec.Targets {
+		total += len(hashes)
+	}
+	if total == 0 {
+		return nil, ErrNoTargets
+	}
+
+	m.mu.Lock()
+	j := &job{
+		Job: Job{
+			ID:          m.nextID,
+			Action:      spec.Action,
+			InstanceIDs: slices.Sorted(maps.Keys(spec.Targets)),
+			Status:      StatusQueued,
+			Total:       total,
+			Reversible:  undoOf == 0 && IsReversible(spec.Action),
+			UndoOf:      undoOf,
+			CreatedAt:   m.now(),
+		},
+		spec: spec,
+	}
+	if j.Reversible {
+		j.prior = make(map[int]map[string]priorState)
+	}
+
+	select {
+	case m.queue <- j:
+	default:
+		m.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+	m.nextID++
+	m.jobs = append(m.jobs, j)
+	m.pruneLocked()
+	snapshot := j.snapshot()
+	m.mu.Unlock()
+
+	m.emit(j.ID)
+	return snapshot, nil
+}
+
+// List returns the kept jobs, newest first.
+func (m *Manager) List() []*Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]*Job, 0, len(m.jobs))
+	for i := len(m.jobs) - 1; i >= 0; i-- {
+		jobs = append(jobs, m.jobs[i].snapshot())
+	}
+	return jobs
+}
+
+func (m *Manager) Get(id int) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j := m.findLocked(id)
+	if j == nil {
+		return nil, ErrJobNotFound
+	}
+	return j.snapshot(), nil
+}
+
+// Cancel stops a job. A queued job is canceled right away; a running job stops
+// after its current chunk.
+func (m *Manager) Cancel(id int) (*Job, error) {
+	m.mu.Lock()
+	j := m.findLocked(id)
+	if j == nil {
+		m.mu.Unlock()
+		return nil, ErrJobNotFound
+	}
+	switch {
+	case j.Status.finished():
+		m.mu.Unlock()
+		return nil, ErrJobFinished
+	case j.Status == StatusQueued:
+		m.finishLocked(j, StatusCanceled)
+	case j.cancel != nil:
+		j.cancel()
+	}
+	snapshot := j.snapshot()
+	m.mu.Unlock()
+
+	m.emit(id)
+	return snapshot, nil
+}
+
+// UndoLast queues a job restoring the values the last finished job overwrote,
+// if that job was reversible. Undo jobs and jobs that processed nothing are
+// skipped, and each job can be undone only once.
+func (m *Manager) UndoLast() (*Job, error) {
+	m.mu.Lock()
+	var target *job
+	for i := len(m.jobs) - 1; i >= 0; i-- {
+		j := m.jobs[i]
+		if j.UndoOf != 0 || !j.Status.finished() || j.Processed == 0 {
+			continue
+		}
+		if j.Reversible && j.UndoneBy == 0 && len(j.prior) > 0 {
+			target = j
+		}
+		break
+	}
+	if target == nil {
+		m.mu.Unlock()
+		return nil, ErrNothingToUndo
+	}
+	spec := Spec{
+		Action:  target.Action,
+		Targets: make(map[int][]string, len(target.prior)),
+	}
+	prior := target.prior
+	for instanceID, states := range prior {
+		spec.Targets[instanceID] = slices.Sorted(maps.Keys(states))
+	}
+	spec.Run = func(ctx context.Context, instanceID int, hashes []string) error {
+		return m.restore(ctx, target.Action, instanceID, hashes, prior[instanceID])
+	}
+	// Claim the target before releasing the lock so a second undo request
+	// cannot pick it too.
+	target.UndoneBy = -1
+	m.mu.Unlock()
+
+	undo, err := m.submit(spec, target.ID)
+
+	m.mu.Lock()
+	if err != nil {
+		target.UndoneBy = 0
+	} else {
+		target.UndoneBy = undo.ID
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	m.emit(target.ID)
+	return undo, nil
+}
+
+func (m *Manager) run(parent context.Context, j *job) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	m.mu.Lock()
+	if j.Status != StatusQueued {
+		m.mu.Unlock()
+		return
+	}
+	started := m.now()
+	j.Status = StatusRunning
+	j.StartedAt = &started
+	j.cancel = cancel
+	m.mu.Unlock()
+	m.emit(j.ID)
+
+	for _, instanceID := range j.InstanceIDs {
+		for chunk := range slices.Chunk(j.spec.Targets[instanceID], ChunkSize) {
+			if ctx.Err() != nil {
+				m.finish(j, StatusCanceled)
+				return
+			}
+			m.runChunk(ctx, j, instanceID, chunk)
+			m.emit(j.ID)
+		}
+	}
+
+	m.mu.Lock()
+	status := StatusCompleted
+	if j.Failed == j.Total {
+		status = StatusFailed
+	}
+	m.mu.Unlock()
+	m.finish(j, status)
+}
+
+// runChunk applies the job to one chunk. When the chunk fails as a whole, the
+// hashes are retried one by one so errors can be reported per hash.
+func (m *Manager) runChunk(ctx context.Context, j *job, instanceID int, hashes []string) {
+	if j.Reversible {
+		if err := m.capture(ctx, j, instanceID, hashes); err != nil {
+			// Without the prior values the chunk could not be undone, so it
+			// is not applied either.
+			m.recordChunk(j, instanceID, hashes, fmt.Errorf("capture current values: %w", err))
+			return
+		}
+	}
+
+	err := j.spec.Run(ctx, instanceID, hashes)
+	if err == nil || len(hashes) == 1 {
+		m.recordChunk(j, instanceID, hashes, err)
+		return
+	}
+
+	log.Debug().Err(err).Int("jobID", j.ID).Int("instanceID", instanceID).Int("hashes", len(hashes)).
+		Msg("bulkjobs: chunk failed, retrying per hash")
+	for _, hash := range hashes {
+		if ctx.Err() != nil {
+			m.recordChunk(j, instanceID, []string{hash}, errJobCanceled)
+			continue
+		}
+		m.recordChunk(j, instanceID, []string{hash}, j.spec.Run(ctx, instanceID, []string{hash}))
+	}
+}
+
+func (m *Manager) recordChunk(j *job, instanceID int, hashes []string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j.Processed += len(hashes)
+	if err == nil {
+		return
+	}
+	j.Failed += len(hashes)
+	for _, hash := range hashes {
+		if len(j.Errors) >= maxJobErrors {
+			break
+		}
+		j.Errors = append(j.Errors, HashError{InstanceID: instanceID, Hash: hash, Error: err.Error()})
+	}
+}
+
+func (m *Manager) capture(ctx context.Context, j *job, instanceID int, hashes []string) error {
+	torrents, err := m.client.GetTorrents(ctx, instanceID, qbt.TorrentFilterOptions{Hashes: hashes})
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	states := j.prior[instanceID]
+	if states == nil {
+		states = make(map[string]priorState, len(hashes))
+		j.prior[instanceID] = states
+	}
+	for i := range torrents {
+		t := &torrents[i]
+		states[t.Hash] = priorState{
+			Category:                 t.Category,
+			Tags:                     t.Tags,
+			RatioLimit:               t.RatioLimit,
+			SeedingTimeLimit:         t.SeedingTimeLimit,
+			InactiveSeedingTimeLimit: t.InactiveSeedingTimeLimit,
+			ShareLimitAction:         t.ShareLimitAction,
+			ShareLimitsMode:          t.ShareLimitsMode,
+			UpLimit:                  t.UpLimit,
+			DlLimit:                  t.DlLimit,
+			SavePath:                 t.SavePath,
+			AutoManaged:              t.AutoManaged,
+		}
+	}
+	return nil
+}
+
+// restore puts back the field action changed, one call per distinct prior value.
+func (m *Manager) restore(ctx context.Context, action string, instanceID int, hashes []string, states map[string]priorState) error {
+	type group struct {
+		state  priorState
+		hashes []string
+	}
+	var groups []*group
+	index := make(map[priorState]*group)
+	for _, hash := range hashes {
+		state, ok := states[hash]
+		if !ok {
+			continue
+		}
+		key := restoreKey(action, state)
+		g, ok := index[key]
+		if !ok {
+			g = &group{state: state}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.hashes = append(g.hashes, hash)
+	}
+
+	var errs []error
+	for _, g := range groups {
+		var err error
+		switch action {
+		case "setCategory":
+			err = m.client.SetCategory(ctx, instanceID, g.hashes, g.state.Category)
+		case "addTags", "removeTags", "setTags":
+			err = m.client.SetTags(ctx, instanceID, g.hashes, g.state.Tags)
+		case "setShareLimit":
+			err = m.client.SetTorrentShareLimit(ctx, instanceID, g.hashes, g.state.RatioLimit, g.state.SeedingTimeLimit, g.state.InactiveSeedingTimeLimit, g.state.ShareLimitAction, g.state.ShareLimitsMode)
+		case "setUploadLimit":
+			err = m.client.SetTorrentUploadLimit(ctx, instanceID, g.hashes, bytesToKiB(g.state.UpLimit))
+		case "setDownloadLimit":
+			err = m.client.SetTorrentDownloadLimit(ctx, instanceID, g.hashes, bytesToKiB(g.state.DlLimit))
+		case "setLocation":
+			err = m.client.SetLocation(ctx, instanceID, g.hashes, g.state.SavePath)
+			// Moving a torrent turns automatic management off; turn it back on
+			// for the ones that had it.
+			if err == nil && g.state.AutoManaged {
+				err = m.client.SetAutoTMM(ctx, instanceID, g.hashes, true)
+			}
+		default:
+			return fmt.Errorf("%w: %s", ErrNotReversible, action)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restoreKey keeps only the field action changed, so torrents that share it are
+// restored in one call.
+func restoreKey(action string, state priorState) priorState {
+	switch action {
+	case "setCategory":
+		return priorState{Category: state.Category}
+	case "addTags", "removeTags", "setTags":
+		return priorState{Tags: state.Tags}
+	case "setShareLimit":
+		return priorState{
+			RatioLimit:               state.RatioLimit,
+			SeedingTimeLimit:         state.SeedingTimeLimit,
+			InactiveSeedingTimeLimit: state.InactiveSeedingTimeLimit,
+			ShareLimitAction:         state.ShareLimitAction,
+			ShareLimitsMode:          state.ShareLimitsMode,
+		}
+	case "setUploadLimit":
+		return priorState{UpLimit: state.UpLimit}
+	case "setDownloadLimit":
+		return priorState{DlLimit: state.DlLimit}
+	case "setLocation":
+		return priorState{SavePath: state.SavePath, AutoManaged: state.AutoManaged}
+	}
+	return state
+}
+
+// bytesToKiB converts a qBittorrent speed limit in bytes/s to the KiB/s the
+// setters take. Zero and negative values mean unlimited.
+func bytesToKiB(limit int64) int64 {
+	if limit <= 0 {
+		return 0
+	}
+	return (limit + 1023) / 1024
+}
+
+func (m *Manager) finish(j *job, status Status) {
+	m.mu.Lock()
+	m.finishLocked(j, status)
+	m.mu.Unlock()
+	m.emit(j.ID)
+
+	log.Debug().Int("jobID", j.ID).Str("action", j.Action).Str("status", string(status)).
+		Int("total", j.Total).Int("failed", j.Failed).Msg("bulkjobs: job finished")
+}
+
+func (m *Manager) finishLocked(j *job, status Status) {
+	finished := m.now()
+	j.Status = status
+	j.FinishedAt = &finished
+	j.cancel = nil
+}
+
+func (m *Manager) findLocked(id int) *job {
+	for _, j := range m.jobs {
+		if j.ID == id {
+			return j
+		}
+	}
+	return nil
+}
+
+// pruneLocked drops the oldest finished jobs beyond maxJobs.
+func (m *Manager) pruneLocked() {
+	for len(m.jobs) > maxJobs {
+		idx := slices.IndexFunc(m.jobs, func(j *job) bool { return j.Status.finished() })
+		if idx < 0 {
+			return
+		}
+		m.jobs = slices.Delete(m.jobs, idx, idx+1)
+	}
+}
+
+func (m *Manager) emit(id int) {
+	m.activityPublisher.Publish(activity.Event{
+		Kind:       activity.KindBulkJob,
+		ResourceID: strconv.Itoa(id),
+	})
+}
+
+func (j *job) snapshot() *Job {
+	snapshot := j.Job
+	snapshot.InstanceIDs = slices.Clone(j.InstanceIDs)
+	snapshot.Errors = slices.Clone(j.Errors)
+	if snapshot.UndoneBy < 0 {
+		snapshot.UndoneBy = 0
+	}
+	return &snapshot
+}
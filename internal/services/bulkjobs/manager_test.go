@@ -0,0 +1,327 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package bulkjobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient keeps per-torrent state the restore calls write back to.
+type fakeClient struct {
+	mu       sync.Mutex
+	torrents map[string]*qbt.Torrent
+	calls    []string
+}
+
+func newFakeClient(n int) *fakeClient {
+	c := &fakeClient{torrents: make(map[string]*qbt.Torrent, n)}
+	for i := range n {
+		hash := fmt.Sprintf("hash%04d", i)
+		category := "movies"
+		if i%2 == 1 {
+			category = "tv"
+		}
+		c.torrents[hash] = &qbt.Torrent{Hash: hash, Category: category, UpLimit: 2048}
+	}
+	return c
+}
+
+func (c *fakeClient) GetTorrents(_ context.Context, _ int, filter qbt.TorrentFilterOptions) ([]qbt.Torrent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var torrents []qbt.Torrent
+	for _, hash := range filter.Hashes {
+		if t, ok := c.torrents[hash]; ok {
+			torrents = append(torrents, *t)
+		}
+	}
+	return torrents, nil
+}
+
+func (c *fakeClient) SetCategory(_ context.Context, _ int, hashes []string, category string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, "category:"+category)
+	for _, hash := range hashes {
+		c.torrents[hash].Category = category
+	}
+	return nil
+}
+
+func (c *fakeClient) SetTags(context.Context, int, []string, string) error { return nil }
+
+func (c *fakeClient) SetTorrentShareLimit(_ context.Context, _ int, hashes []string, ratioLimit float64, _, _ int64, shareLimitAction, shareLimitsMode string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, hash := range hashes {
+		t := c.torrents[hash]
+		t.RatioLimit = ratioLimit
+		t.ShareLimitAction = shareLimitAction
+		t.ShareLimitsMode = shareLimitsMode
+	}
+	return nil
+}
+
+func (c *fakeClient) SetTorrentUploadLimit(_ context.Context, _ int, hashes []string, limitKBs int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, hash := range hashes {
+		c.torrents[hash].UpLimit = limitKBs * 1024
+	}
+	return nil
+}
+
+func (c *fakeClient) SetTorrentDownloadLimit(context.Context, int, []string, int64) error { return nil }
+
+func (c *fakeClient) SetLocation(_ context.Context, _ int, hashes []string, location string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, hash := range hashes {
+		c.torrents[hash].SavePath = location
+		c.torrents[hash].AutoManaged = false
+	}
+	return nil
+}
+
+func (c *fakeClient) SetAutoTMM(_ context.Context, _ int, hashes []string, enable bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, hash := range hashes {
+		c.torrents[hash].AutoManaged = enable
+	}
+	return nil
+}
+
+func (c *fakeClient) hashes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hashes := make([]string, 0, len(c.torrents))
+	for hash := range c.torrents {
+		hashes = append(hashes, hash)
+	}
+	slices.Sort(hashes)
+	return hashes
+}
+
+func (c *fakeClient) category(hash string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.torrents[hash].Category
+}
+
+func (c *fakeClient) torrent(hash string) qbt.Torrent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return *c.torrents[hash]
+}
+
+func waitFinished(t *testing.T, m *Manager, id int) *Job {
+	t.Helper()
+	var job *Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Get(id)
+		require.NoError(t, err)
+		return job.Status.finished()
+	}, 2*time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestManagerRunsInChunksAndUndoes(t *testing.T) {
+	ctx := t.Context()
+	client := newFakeClient(ChunkSize + 50)
+	m := NewManager(client)
+	m.Start(ctx)
+
+	var chunks []int
+	job, err := m.Submit(Spec{
+		Action:  "setCategory",
+		Targets: map[int][]string{1: client.hashes()},
+		Run: func(ctx context.Context, instanceID int, hashes []string) error {
+			chunks = append(chunks, len(hashes))
+			return client.SetCategory(ctx, instanceID, hashes, "archive")
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, job.Reversible)
+
+	job = waitFinished(t, m, job.ID)
+	assert.Equal(t, StatusCompleted, job.Status)
+	assert.Equal(t, ChunkSize+50, job.Processed)
+	assert.Zero(t, job.Failed)
+	assert.Equal(t, []int{ChunkSize, 50}, chunks)
+	assert.Equal(t, "archive", client.category("hash0001"))
+
+	undo, err := m.UndoLast()
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, undo.UndoOf)
+	assert.False(t, undo.Reversible)
+
+	undo = waitFinished(t, m, undo.ID)
+	assert.Equal(t, StatusCompleted, undo.Status)
+	assert.Equal(t, "movies", client.category("hash0000"))
+	assert.Equal(t, "tv", client.category("hash0001"))
+
+	original, err := m.Get(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, undo.ID, original.UndoneBy)
+
+	_, err = m.UndoLast()
+	require.ErrorIs(t, err, ErrNothingToUndo)
+}
+
+func TestManagerUndoRestoresShareLimitAction(t *testing.T) {
+	ctx := t.Context()
+	client := newFakeClient(2)
+	client.torrents["hash0000"].RatioLimit = 2
+	client.torrents["hash0000"].ShareLimitAction = "Remove"
+	client.torrents["hash0000"].ShareLimitsMode = "Custom"
+	m := NewManager(client)
+	m.Start(ctx)
+
+	job, err := m.Submit(Spec{
+		Action:  "setShareLimit",
+		Targets: map[int][]string{1: client.hashes()},
+		Run: func(ctx context.Context, instanceID int, hashes []string) error {
+			return client.SetTorrentShareLimit(ctx, instanceID, hashes, 1, 0, 0, "Stop", "Default")
+		},
+	})
+	require.NoError(t, err)
+	waitFinished(t, m, job.ID)
+
+	undo, err := m.UndoLast()
+	require.NoError(t, err)
+	waitFinished(t, m, undo.ID)
+
+	restored := client.torrent("hash0000")
+	assert.InDelta(t, 2.0, restored.RatioLimit, 0)
+	assert.Equal(t, "Remove", restored.ShareLimitAction)
+	assert.Equal(t, "Custom", restored.ShareLimitsMode)
+	assert.Empty(t, client.torrent("hash0001").ShareLimitAction)
+}
+
+func TestManagerUndoRestoresAutoManagement(t *testing.T) {
+	ctx := t.Context()
+	client := newFakeClient(2)
+	client.torrents["hash0000"].SavePath = "/data/movies"
+	client.torrents["hash0000"].AutoManaged = true
+	client.torrents["hash0001"].SavePath = "/data/manual"
+	m := NewManager(client)
+	m.Start(ctx)
+
+	job, err := m.Submit(Spec{
+		Action:  "setLocation",
+		Targets: map[int][]string{1: client.hashes()},
+		Run: func(ctx context.Context, instanceID int, hashes []string) error {
+			return client.SetLocation(ctx, instanceID, hashes, "/data/archive")
+		},
+	})
+	require.NoError(t, err)
+	waitFinished(t, m, job.ID)
+	assert.False(t, client.torrent("hash0000").AutoManaged)
+
+	undo, err := m.UndoLast()
+	require.NoError(t, err)
+	waitFinished(t, m, undo.ID)
+
+	assert.Equal(t, "/data/movies", client.torrent("hash0000").SavePath)
+	assert.True(t, client.torrent("hash0000").AutoManaged)
+	assert.Equal(t, "/data/manual", client.torrent("hash0001").SavePath)
+	assert.False(t, client.torrent("hash0001").AutoManaged)
+}
+
+func TestManagerReportsPerHashErrors(t *testing.T) {
+	ctx := t.Context()
+	m := NewManager(newFakeClient(0))
+	m.Start(ctx)
+
+	job, err := m.Submit(Spec{
+		Action:  "recheck",
+		Targets: map[int][]string{2: {"aaa", "bad", "ccc"}},
+		Run: func(_ context.Context, _ int, hashes []string) error {
+			if slices.Contains(hashes, "bad") {
+				return errors.New("torrent not found")
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, job.Reversible)
+
+	job = waitFinished(t, m, job.ID)
+	assert.Equal(t, StatusCompleted, job.Status)
+	assert.Equal(t, 3, job.Processed)
+	assert.Equal(t, 1, job.Failed)
+	assert.Equal(t, []HashError{{InstanceID: 2, Hash: "bad", Error: "torrent not found"}}, job.Errors)
+
+	_, err = m.UndoLast()
+	require.ErrorIs(t, err, ErrNothingToUndo)
+}
+
+func TestManagerCancel(t *testing.T) {
+	ctx := t.Context()
+	client := newFakeClient(ChunkSize * 3)
+	m := NewManager(client)
+
+	release := make(chan struct{})
+	running, err := m.Submit(Spec{
+		Action:  "setUploadLimit",
+		Targets: map[int][]string{1: client.hashes()},
+		Run: func(ctx context.Context, instanceID int, hashes []string) error {
+			<-release
+			return client.SetTorrentUploadLimit(ctx, instanceID, hashes, 100)
+		},
+	})
+	require.NoError(t, err)
+	queued, err := m.Submit(Spec{
+		Action:  "pause",
+		Targets: map[int][]string{1: {"hash0000"}},
+		Run:     func(context.Context, int, []string) error { return nil },
+	})
+	require.NoError(t, err)
+
+	canceled, err := m.Cancel(queued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCanceled, canceled.Status)
+	_, err = m.Cancel(queued.ID)
+	require.ErrorIs(t, err, ErrJobFinished)
+
+	m.Start(ctx)
+	require.Eventually(t, func() bool {
+		job, _ := m.Get(running.ID)
+		return job.Status == StatusRunning
+	}, 2*time.Second, 5*time.Millisecond)
+	_, err = m.Cancel(running.ID)
+	require.NoError(t, err)
+	close(release)
+
+	job := waitFinished(t, m, running.ID)
+	assert.Equal(t, StatusCanceled, job.Status)
+	assert.Equal(t, ChunkSize, job.Processed)
+
+	// Only the chunk that ran is restored.
+	undo, err := m.UndoLast()
+	require.NoError(t, err)
+	assert.Equal(t, ChunkSize, undo.Total)
+
+	_, err = m.Get(999)
+	require.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestBytesToKiB(t *testing.T) {
+	assert.Equal(t, int64(0), bytesToKiB(-1))
+	assert.Equal(t, int64(0), bytesToKiB(0))
+	assert.Equal(t, int64(1), bytesToKiB(1))
+	assert.Equal(t, int64(2), bytesToKiB(2048))
+}
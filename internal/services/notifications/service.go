@@ -416,6 +416,9 @@ func (s *Service) formatEvent(ctx context.Context, event Event, humanReadableMet
 	case EventAutomationsRunFailed:
 		title := "Automations run failed"
 		return formatAutomationsEvent(instanceLabel, title, event.Title, customMessage, humanReadableMetrics)
+	case EventPreferenceDrift:
+		title := "Preference drift detected"
+		return formatCustomEvent(instanceLabel, title, event.Title, customMessage)
 	default:
 		return "", ""
 	}
@@ -886,7 +889,8 @@ func discordEventColor(eventType EventType) int {
 		EventCrossSeedSearchFailed,
 		EventCrossSeedCompletionFailed,
 		EventCrossSeedWebhookFailed,
-		EventAutomationsRunFailed:
+		EventAutomationsRunFailed,
+		EventPreferenceDrift:
 		return discordColorError
 	case EventTorrentCompleted,
 		EventBackupSucceeded,
@@ -29,6 +29,7 @@ const (
 	EventCrossSeedWebhookFailed       EventType = "cross_seed_webhook_failed"
 	EventAutomationsActionsApplied    EventType = "automations_actions_applied"
 	EventAutomationsRunFailed         EventType = "automations_run_failed"
+	EventPreferenceDrift              EventType = "preference_drift_detected"
 )
 
 type EventDefinition struct {
@@ -56,6 +57,7 @@ var eventDefinitions = []EventDefinition{
 	{Type: EventCrossSeedWebhookFailed, Label: "Cross-seed webhook check failed", Description: "A webhook check run fails."},
 	{Type: EventAutomationsActionsApplied, Label: "Automations actions applied", Description: "Automation rules applied actions (summary counts and samples; only when actions occur)."},
 	{Type: EventAutomationsRunFailed, Label: "Automations run failed", Description: "Automation rules failed to run for an instance (system error)."},
+	{Type: EventPreferenceDrift, Label: "Preference drift detected", Description: "An instance's qBittorrent preferences no longer match a preference profile with drift alerts on."},
 }
 
 var eventTypeIndex = func() map[string]int {
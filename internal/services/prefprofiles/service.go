@@ -0,0 +1,356 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package prefprofiles compares and applies preference profiles across
+// qBittorrent instances and alerts when instances drift away from them.
+package prefprofiles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/rs/zerolog/log"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/notifications"
+)
+
+// driftCheckInterval is how often profiles with drift alerts are compared
+// against their instances.
+const driftCheckInterval = 15 * time.Minute
+
+// ErrNotQBittorrent is returned for instances that run another client.
+var ErrNotQBittorrent = errors.New("preference profiles only apply to qBittorrent instances")
+
+// PreferencesClient reads and writes qBittorrent app preferences.
+type PreferencesClient interface {
+	GetAppPreferences(ctx context.Context, instanceID int) (qbt.AppPreferences, error)
+	SetAppPreferences(ctx context.Context, instanceID int, prefs map[string]any) error
+}
+
+type instanceGetter interface {
+	Get(ctx context.Context, id int) (*models.Instance, error)
+}
+
+// KeyDiff is one preference whose value on an instance differs from the profile.
+type KeyDiff struct {
+	Key      string `json:"key"`
+	Expected any    `json:"expected"`
+	Actual   any    `json:"actual"`
+	// Missing is set when the instance does not report the preference at all,
+	// usually because its qBittorrent version predates it.
+	Missing bool `json:"missing,omitempty"`
+}
+
+// InstanceDiff is the outcome of comparing a profile with one instance.
+type InstanceDiff struct {
+	InstanceID   int       `json:"instanceId"`
+	InstanceName string    `json:"instanceName"`
+	InSync       bool      `json:"inSync"`
+	Differences  []KeyDiff `json:"differences"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// PushResult is the outcome of applying a profile to one instance.
+type PushResult struct {
+	InstanceID   int    `json:"instanceId"`
+	InstanceName string `json:"instanceName"`
+	Success      bool   `json:"success"`
+	Error        string `json:"error,omitempty"`
+}
+
+// Service diffs and pushes preference profiles and runs the drift check.
+type Service struct {
+	store     *models.PreferenceProfileStore
+	instances instanceGetter
+	prefs     PreferencesClient
+	notifier  notifications.Notifier
+
+	mu sync.Mutex
+	// drift holds the fingerprint of the last drift alerted per profile and
+	// instance, so an unchanged drift is only reported once.
+	drift map[driftKey]string
+}
+
+type driftKey struct {
+	profileID  int
+	instanceID int
+}
+
+// NewService constructs a Service.
+func NewService(store *models.PreferenceProfileStore, instances instanceGetter, prefs PreferencesClient, notifier notifications.Notifier) *Service {
+	return &Service{
+		store:     store,
+		instances: instances,
+		prefs:     prefs,
+		notifier:  notifier,
+		drift:     make(map[driftKey]string),
+	}
+}
+
+// Diff compares the profile's preferences with an instance's current ones and
+// returns the differing keys sorted by name.
+func Diff(expected, current map[string]any) []KeyDiff {
+	diffs := []KeyDiff{}
+	for _, key := range slices.Sorted(maps.Keys(expected)) {
+		want := normalizeValue(expected[key])
+		got, ok := current[key]
+		if !ok {
+			diffs = append(diffs, KeyDiff{Key: key, Expected: want, Missing: true})
+			continue
+		}
+		got = normalizeValue(got)
+		if !reflect.DeepEqual(want, got) {
+			diffs = append(diffs, KeyDiff{Key: key, Expected: want, Actual: got})
+		}
+	}
+	return diffs
+}
+
+// normalizeValue round-trips a value through JSON so ints, floats and
+// json.Numbers compare equal.
+func normalizeValue(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return value
+	}
+	return out
+}
+
+// CurrentPreferences returns an instance's app preferences keyed by their
+// Web API names.
+func (s *Service) CurrentPreferences(ctx context.Context, instanceID int) (map[string]any, *models.Instance, error) {
+	instance, err := s.instances.Get(ctx, instanceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if instance.ClientType.OrDefault() != models.ClientTypeQBittorrent {
+		return nil, instance, ErrNotQBittorrent
+	}
+
+	prefs, err := s.prefs.GetAppPreferences(ctx, instanceID)
+	if err != nil {
+		return nil, instance, err
+	}
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return nil, instance, fmt.Errorf("encode preferences: %w", err)
+	}
+	current := map[string]any{}
+	if err := json.Unmarshal(data, &current); err != nil {
+		return nil, instance, fmt.Errorf("decode preferences: %w", err)
+	}
+	return current, instance, nil
+}
+
+// Snapshot captures an instance's current values for keys. With no keys it
+// captures every key a profile can manage.
+func (s *Service) Snapshot(ctx context.Context, instanceID int, keys []string) (map[string]any, error) {
+	if len(keys) == 0 {
+		for _, group := range models.PreferenceKeyGroups {
+			keys = append(keys, group.Keys...)
+		}
+	}
+	for _, key := range keys {
+		if !models.IsPreferenceProfileKey(key) {
+			return nil, fmt.Errorf("%w: %q", models.ErrUnmanagedPreference, key)
+		}
+	}
+
+	current, _, err := s.CurrentPreferences(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make(map[string]any, len(keys))
+	for _, key := range keys {
+		if value, ok := current[key]; ok {
+			snapshot[key] = value
+		}
+	}
+	return snapshot, nil
+}
+
+// DiffInstances compares profile with each instance. An instance that cannot
+// be read is reported with its error rather than failing the whole diff.
+func (s *Service) DiffInstances(ctx context.Context, profile *models.PreferenceProfile, instanceIDs []int) []InstanceDiff {
+	results := make([]InstanceDiff, 0, len(instanceIDs))
+	for _, id := range instanceIDs {
+		result := InstanceDiff{InstanceID: id, Differences: []KeyDiff{}}
+		current, instance, err := s.CurrentPreferences(ctx, id)
+		if instance != nil {
+			result.InstanceName = instance.Name
+		}
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.Differences = Diff(profile.Preferences, current)
+		result.InSync = len(result.Differences) == 0
+		results = append(results, result)
+	}
+	return results
+}
+
+// Push applies profile to each instance.
+func (s *Service) Push(ctx context.Context, profile *models.PreferenceProfile, instanceIDs []int) []PushResult {
+	results := make([]PushResult, 0, len(instanceIDs))
+	for _, id := range instanceIDs {
+		result := PushResult{InstanceID: id}
+		instance, err := s.instances.Get(ctx, id)
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case instance.ClientType.OrDefault() != models.ClientTypeQBittorrent:
+			result.InstanceName = instance.Name
+			result.Error = ErrNotQBittorrent.Error()
+		default:
+			result.InstanceName = instance.Name
+			if err := s.prefs.SetAppPreferences(ctx, id, maps.Clone(profile.Preferences)); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Success = true
+				s.clearDrift(profile.ID, id)
+			}
+		}
+		if result.Error != "" {
+			log.Warn().Int("profileID", profile.ID).Int("instanceID", id).Str("error", result.Error).Msg("preference profiles: push failed")
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Start launches the periodic drift check.
+func (s *Service) Start(ctx context.Context) {
+	if s == nil || s.store == nil || s.prefs == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(driftCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.CheckDrift(ctx)
+			}
+		}
+	}()
+}
+
+// CheckDrift compares every profile with drift alerts against its instances
+// and notifies once per new drift. Inactive instances are skipped.
+func (s *Service) CheckDrift(ctx context.Context) {
+	profiles, err := s.store.List(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("preference profiles: failed to list profiles")
+		return
+	}
+
+	seen := make(map[driftKey]struct{})
+	for _, profile := range profiles {
+		if !profile.DriftAlerts {
+			continue
+		}
+		for _, id := range profile.InstanceIDs {
+			key := driftKey{profileID: profile.ID, instanceID: id}
+			seen[key] = struct{}{}
+
+			instance, err := s.instances.Get(ctx, id)
+			if err != nil || !instance.IsActive {
+				continue
+			}
+			current, _, err := s.CurrentPreferences(ctx, id)
+			if err != nil {
+				log.Debug().Err(err).Int("profileID", profile.ID).Int("instanceID", id).Msg("preference profiles: drift check skipped")
+				continue
+			}
+
+			diffs := Diff(profile.Preferences, current)
+			if len(diffs) == 0 {
+				s.clearDrift(profile.ID, id)
+				continue
+			}
+			if !s.recordDrift(key, fingerprint(diffs)) {
+				continue
+			}
+			s.notifyDrift(ctx, profile, instance, diffs)
+		}
+	}
+
+	// Forget profiles and instances that no longer take part.
+	s.mu.Lock()
+	for key := range s.drift {
+		if _, ok := seen[key]; !ok {
+			delete(s.drift, key)
+		}
+	}
+	s.mu.Unlock()
+}
+
+// recordDrift stores fp and reports whether it differs from the last one.
+func (s *Service) recordDrift(key driftKey, fp string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.drift[key] == fp {
+		return false
+	}
+	s.drift[key] = fp
+	return true
+}
+
+func (s *Service) clearDrift(profileID, instanceID int) {
+	s.mu.Lock()
+	delete(s.drift, driftKey{profileID: profileID, instanceID: instanceID})
+	s.mu.Unlock()
+}
+
+func (s *Service) notifyDrift(ctx context.Context, profile *models.PreferenceProfile, instance *models.Instance, diffs []KeyDiff) {
+	if s.notifier == nil {
+		return
+	}
+	lines := make([]string, 0, len(diffs)+1)
+	lines = append(lines, "Profile: "+profile.Name)
+	for _, diff := range diffs {
+		lines = append(lines, diffLine(diff))
+	}
+	s.notifier.Notify(ctx, notifications.Event{
+		Type:         notifications.EventPreferenceDrift,
+		InstanceID:   instance.ID,
+		InstanceName: instance.Name,
+		Message:      strings.Join(lines, "\n"),
+	})
+}
+
+func diffLine(diff KeyDiff) string {
+	if diff.Missing {
+		return fmt.Sprintf("%s: expected %v, not reported by the instance", diff.Key, diff.Expected)
+	}
+	return fmt.Sprintf("%s: expected %v, found %v", diff.Key, diff.Expected, diff.Actual)
+}
+
+func fingerprint(diffs []KeyDiff) string {
+	var b strings.Builder
+	for _, diff := range diffs {
+		b.WriteString(diffLine(diff))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
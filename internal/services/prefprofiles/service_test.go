@@ -0,0 +1,127 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package prefprofiles
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/autobrr/qui/internal/models"
+	"github.com/autobrr/qui/internal/services/notifications"
+	"github.com/autobrr/qui/internal/testutil/testdb"
+)
+
+type fakeInstances map[int]*models.Instance
+
+func (f fakeInstances) Get(_ context.Context, id int) (*models.Instance, error) {
+	instance, ok := f[id]
+	if !ok {
+		return nil, models.ErrInstanceNotFound
+	}
+	return instance, nil
+}
+
+type fakePreferences struct {
+	prefs map[int]qbt.AppPreferences
+	set   map[int]map[string]any
+}
+
+func (f *fakePreferences) GetAppPreferences(_ context.Context, instanceID int) (qbt.AppPreferences, error) {
+	prefs, ok := f.prefs[instanceID]
+	if !ok {
+		return qbt.AppPreferences{}, errors.New("unreachable")
+	}
+	return prefs, nil
+}
+
+func (f *fakePreferences) SetAppPreferences(_ context.Context, instanceID int, prefs map[string]any) error {
+	if f.set == nil {
+		f.set = map[int]map[string]any{}
+	}
+	f.set[instanceID] = prefs
+	return nil
+}
+
+type recordingNotifier struct {
+	events []notifications.Event
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, event notifications.Event) {
+	n.events = append(n.events, event)
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	diffs := Diff(
+		map[string]any{"max_connec": 500, "queueing_enabled": true, "disk_cache_ttl": 60, "disk_io_read_mode": 1},
+		map[string]any{"max_connec": 500.0, "queueing_enabled": false, "disk_cache_ttl": 60.0},
+	)
+	assert.Equal(t, []KeyDiff{
+		{Key: "disk_io_read_mode", Expected: 1.0, Missing: true},
+		{Key: "queueing_enabled", Expected: true, Actual: false},
+	}, diffs)
+}
+
+func TestServiceDiffPushAndDrift(t *testing.T) {
+	ctx := context.Background()
+	store := models.NewPreferenceProfileStore(testdb.NewMigratedSQLite(t, "prefprofiles"))
+	instances := fakeInstances{
+		1: {ID: 1, Name: "seedbox", IsActive: true},
+		2: {ID: 2, Name: "home", IsActive: true},
+		3: {ID: 3, Name: "transmission", ClientType: models.ClientTypeTransmission, IsActive: true},
+	}
+	prefs := &fakePreferences{prefs: map[int]qbt.AppPreferences{
+		1: {MaxConnec: 500, QueueingEnabled: true},
+		2: {MaxConnec: 200, QueueingEnabled: true},
+	}}
+	notifier := &recordingNotifier{}
+	svc := NewService(store, instances, prefs, notifier)
+
+	profile, err := store.Create(ctx, &models.PreferenceProfile{
+		Name:        "Seedbox",
+		Preferences: map[string]any{"max_connec": 500, "queueing_enabled": true},
+		InstanceIDs: []int{1, 2},
+		DriftAlerts: true,
+	})
+	require.NoError(t, err)
+
+	diffs := svc.DiffInstances(ctx, profile, []int{1, 2, 3})
+	require.Len(t, diffs, 3)
+	assert.True(t, diffs[0].InSync)
+	assert.False(t, diffs[1].InSync)
+	assert.Equal(t, []KeyDiff{{Key: "max_connec", Expected: 500.0, Actual: 200.0}}, diffs[1].Differences)
+	assert.Equal(t, ErrNotQBittorrent.Error(), diffs[2].Error)
+
+	snapshot, err := svc.Snapshot(ctx, 2, []string{"max_connec"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"max_connec": 200.0}, snapshot)
+	_, err = svc.Snapshot(ctx, 2, []string{"save_path"})
+	require.Error(t, err)
+
+	// Drift is reported once until it changes.
+	svc.CheckDrift(ctx)
+	svc.CheckDrift(ctx)
+	require.Len(t, notifier.events, 1)
+	event := notifier.events[0]
+	assert.Equal(t, notifications.EventPreferenceDrift, event.Type)
+	assert.Equal(t, 2, event.InstanceID)
+	assert.Contains(t, event.Message, "max_connec: expected 500, found 200")
+
+	prefs.prefs[2] = qbt.AppPreferences{MaxConnec: 300, QueueingEnabled: true}
+	svc.CheckDrift(ctx)
+	require.Len(t, notifier.events, 2)
+
+	results := svc.Push(ctx, profile, []int{2, 3})
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Success)
+	assert.False(t, results[1].Success)
+	assert.Equal(t, profile.Preferences, prefs.set[2])
+	assert.NotContains(t, prefs.set, 3)
+}
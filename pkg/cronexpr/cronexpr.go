@@ -0,0 +1,99 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package cronexpr parses standard five-field cron expressions
+// (minute hour day-of-month month day-of-week) and computes activation times.
+//
+// Parsing and matching are delegated to github.com/robfig/cron/v3, so the
+// syntax is the one documented there: '*' and '?', lists ("1,15"), ranges
+// ("1-5"), steps ("*/15", "10-50/10"), month and weekday names ("JAN", "MON")
+// and the descriptors @yearly, @annually, @monthly, @weekly, @daily,
+// @midnight and @hourly. When both day-of-month and day-of-week are
+// restricted, a time matches if either matches (classic Vixie cron semantics).
+//
+// Activations are computed in wall-clock time of the location passed to Next.
+// A slot that falls into a daylight saving gap is skipped for that day. In an
+// overlap, schedules with a fixed hour fire once, at the first occurrence,
+// while wildcard-hour schedules keep firing through both halves.
+package cronexpr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	spec *cron.SpecSchedule
+	expr string
+}
+
+// starBit is set by robfig/cron on fields written as '*' or '?'.
+const starBit = 1 << 63
+
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Parse parses a five-field cron expression or a descriptor.
+func Parse(expr string) (*Schedule, error) {
+	trimmed := strings.TrimSpace(expr)
+	if trimmed == "" {
+		return nil, errors.New("empty cron expression")
+	}
+	if upper := strings.ToUpper(trimmed); strings.HasPrefix(upper, "TZ=") || strings.HasPrefix(upper, "CRON_TZ=") {
+		return nil, errors.New("time zone prefixes are not supported, set the schedule time zone instead")
+	}
+
+	parsed, err := parser.Parse(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	spec, ok := parsed.(*cron.SpecSchedule)
+	if !ok {
+		// @every yields a fixed-delay schedule; rules have their own interval for that.
+		return nil, fmt.Errorf("unsupported cron descriptor %q", trimmed)
+	}
+
+	return &Schedule{spec: spec, expr: trimmed}, nil
+}
+
+// String returns the expression the schedule was parsed from.
+func (s *Schedule) String() string {
+	if s == nil {
+		return ""
+	}
+	return s.expr
+}
+
+// Next returns the first activation strictly after t, in t's location.
+// It returns the zero time if no activation exists within five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s == nil || s.spec == nil {
+		return time.Time{}
+	}
+
+	// robfig/cron works with second precision; start from the end of t's
+	// minute so an activation is never returned twice for the same slot.
+	next := s.spec.Next(t.Truncate(time.Minute).Add(time.Minute - time.Second))
+	for !next.IsZero() && s.spec.Hour&starBit == 0 && repeatedWallClock(next) {
+		next = s.spec.Next(next)
+	}
+	return next
+}
+
+// repeatedWallClock reports whether t is the second occurrence of its wall
+// clock time, i.e. it falls into the repeated part of a DST overlap.
+func repeatedWallClock(t time.Time) bool {
+	_, offset := t.Zone()
+	// Look far enough back to be on the other side of any transition.
+	_, before := t.Add(-3 * time.Hour).Zone()
+	shift := before - offset
+	if shift <= 0 {
+		return false
+	}
+	earlier := t.Add(-time.Duration(shift) * time.Second)
+	return earlier.Day() == t.Day() && earlier.Hour() == t.Hour() && earlier.Minute() == t.Minute()
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2026, s0up and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package cronexpr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	t.Parallel()
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every 5m",
+		"@every5m",
+		"CRON_TZ=UTC 0 2 * * *",
+	}
+	for _, expr := range invalid {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, time.March, 4, 10, 17, 30, 0, time.UTC) // Wednesday
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", base, time.Date(2026, 3, 4, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", base, time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 2 * * *", base, time.Date(2026, 3, 5, 2, 0, 0, 0, time.UTC)},
+		{"30 1 * * MON-FRI", time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC), time.Date(2026, 3, 9, 1, 30, 0, 0, time.UTC)},
+		{"0 0 * * SUN", base, time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", base, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", base, time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", base, time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"5/20 * * * *", base, time.Date(2026, 3, 4, 10, 25, 0, 0, time.UTC)},
+		// Day-of-month and day-of-week both restricted: either matches.
+		{"0 0 15 * FRI", base, time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", base, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Strictly after: an exact match moves to the next slot.
+		{"0 10 * * *", time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC), time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		sched, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		if got := sched.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%q.Next(%s) = %s, want %s", tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestNextImpossibleScheduleReturnsZero(t *testing.T) {
+	t.Parallel()
+
+	sched, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := sched.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Fatalf("expected zero time, got %s", got)
+	}
+}
+
+func TestNextRespectsLocation(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	sched, err := Parse("0 3 * * *")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	from := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC).In(loc) // 02:00 local
+	want := time.Date(2026, 3, 4, 3, 0, 0, 0, loc)
+	if got := sched.Next(from); !got.Equal(want) {
+		t.Fatalf("Next = %s, want %s", got, want)
+	}
+}
+
+func TestNextAcrossDSTGap(t *testing.T) {
+	t.Parallel()
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	// 2026-03-08 02:00 EST jumps to 03:00 EDT, so 02:30 does not exist that day.
+	sched, err := Parse("30 2 * * *")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	from := time.Date(2026, 3, 7, 12, 0, 0, 0, loc)
+	want := time.Date(2026, 3, 9, 2, 30, 0, 0, loc)
+	if got := sched.Next(from); !got.Equal(want) {
+		t.Fatalf("Next(%s) = %s, want %s", from, got, want)
+	}
+
+	// Slots around the gap keep firing on the hour.
+	hourly, err := Parse("0 * * * *")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	from = time.Date(2026, 3, 8, 1, 30, 0, 0, loc)
+	got := hourly.Next(from)
+	if wantTime := time.Date(2026, 3, 8, 3, 0, 0, 0, loc); !got.Equal(wantTime) {
+		t.Fatalf("Next(%s) = %s, want %s", from, got, wantTime)
+	}
+	if gotDelta := got.Sub(from); gotDelta != 30*time.Minute {
+		t.Fatalf("expected the next slot 30 minutes later, got %s", gotDelta)
+	}
+}
+
+func TestNextAcrossDSTOverlap(t *testing.T) {
+	t.Parallel()
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	// 2026-11-01 02:00 EDT falls back to 01:00 EST, so 01:30 happens twice.
+	sched, err := Parse("30 1 * * *")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	first := sched.Next(time.Date(2026, 10, 31, 12, 0, 0, 0, loc))
+	if want := time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC); !first.Equal(want) {
+		t.Fatalf("first = %s, want %s (01:30 EDT)", first, want)
+	}
+	second := sched.Next(first)
+	if want := time.Date(2026, 11, 2, 6, 30, 0, 0, time.UTC); !second.Equal(want) {
+		t.Fatalf("second = %s, want %s (01:30 EST the next day)", second, want)
+	}
+
+	// Starting inside the repeated hour does not replay the slot either.
+	from := time.Date(2026, 11, 1, 6, 15, 0, 0, time.UTC).In(loc) // 01:15 EST
+	if got := sched.Next(from); !got.Equal(second) {
+		t.Fatalf("Next(%s) = %s, want %s", from, got, second)
+	}
+
+	// Schedules with a wildcard hour still tick through both halves of the overlap.
+	everyMinute, err := Parse("* * * * *")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	from = time.Date(2026, 11, 1, 5, 59, 0, 0, time.UTC).In(loc) // 01:59 EDT
+	if got, want := everyMinute.Next(from), from.Add(time.Minute); !got.Equal(want) {
+		t.Fatalf("Next(%s) = %s, want %s", from, got, want)
+	}
+}
@@ -11,7 +11,7 @@ import { Switch } from "@/components/ui/switch"
 import { useInstances } from "@/hooks/useInstances"
 import { DEFAULT_REANNOUNCE_SETTINGS, instanceUrlSchema } from "@/lib/instance-validation"
 import { formatErrorMessage } from "@/lib/utils"
-import type { Instance, InstanceFormData } from "@/types"
+import type { ClientType, Instance, InstanceFormData } from "@/types"
 import { useForm } from "@tanstack/react-form"
 import { useEffect, useRef, useState } from "react"
 import { useTranslation } from "react-i18next"
@@ -35,6 +35,7 @@ function getInstanceFormDefaults(instance?: Instance): InstanceFormData {
   return {
     name: instance?.name ?? "",
     host: instance?.host ?? "http://localhost:8080",
+    clientType: instance?.clientType ?? "qbittorrent",
     username: instance?.username ?? "",
     password: "",
     apiKey: instance?.hasApiKey ? "<redacted>" : "",
@@ -222,6 +223,27 @@ export function InstanceForm({ instance, onSuccess, onCancel, formId }: Instance
           )}
         </form.Field>
 
+        <form.Field name="clientType">
+          {(field) => (
+            <div className="space-y-2">
+              <Label htmlFor="client-type" className="flex items-center gap-2">
+                {t("form.labels.clientType")}
+                <FieldHelp>{t("form.labels.clientTypeDescription")}</FieldHelp>
+              </Label>
+              <select
+                id="client-type"
+                value={field.state.value}
+                onChange={(e) => field.handleChange(e.target.value as ClientType)}
+                className="flex h-10 w-full rounded-md border border-input bg-background px-3 py-2 text-sm ring-offset-background"
+              >
+                <option value="qbittorrent">{t("form.clientType.qbittorrent")}</option>
+                <option value="transmission">{t("form.clientType.transmission")}</option>
+                <option value="deluge">{t("form.clientType.deluge")}</option>
+              </select>
+            </div>
+          )}
+        </form.Field>
+
         <form.Field
           name="host"
           validators={{
@@ -14,8 +14,10 @@ import { Switch } from "@/components/ui/switch"
 import { Textarea } from "@/components/ui/textarea"
 import { useInstancePreferences } from "@/hooks/useInstancePreferences"
 import { useQBittorrentFieldVisibility } from "@/hooks/useQBittorrentAppInfo"
+import { api } from "@/lib/api"
 import { useIncognitoMode } from "@/lib/incognito"
 import { useForm } from "@tanstack/react-form"
+import { useQuery } from "@tanstack/react-query"
 import { AlertTriangle, Globe, Server, Shield, Wifi } from "lucide-react"
 import React from "react"
 import { useTranslation } from "react-i18next"
@@ -33,6 +35,12 @@ const sanitizeBtProtocol = (value: unknown): 0 | 1 | 2 => {
   return Math.min(2, Math.max(0, numeric)) as 0 | 1 | 2
 }
 
+// Radix Select items cannot have an empty value, so "any interface" and "all
+// addresses" use a placeholder that maps back to qBittorrent's empty string.
+const ANY_VALUE = "__any__"
+const toSelectValue = (value: string) => value === "" ? ANY_VALUE : value
+const fromSelectValue = (value: string) => value === ANY_VALUE ? "" : value
+
 const sanitizeUtpTcpMixedMode = (value: unknown): 0 | 1 => {
   const numeric = typeof value === "number" ? value : parseInt(String(value), 10)
   return numeric === 1 ? 1 : 0
@@ -121,12 +129,74 @@ function NumberInput({
   )
 }
 
+interface InterfaceAddressSelectProps {
+  instanceId: number
+  iface: string
+  value: string
+  onChange: (value: string) => void
+  blurred: boolean
+}
+
+function InterfaceAddressSelect({ instanceId, iface, value, onChange, blurred }: InterfaceAddressSelectProps) {
+  const { t } = useTranslation("instances")
+  const addressesQuery = useQuery({
+    queryKey: ["network-interface-addresses", instanceId, iface],
+    queryFn: () => api.getNetworkInterfaceAddresses(instanceId, iface),
+    staleTime: 60_000,
+  })
+
+  const addresses = addressesQuery.data ?? []
+  const wildcards = [
+    { value: ANY_VALUE, label: t("preferences.connectionSettings.allAddresses") },
+    { value: "0.0.0.0", label: t("preferences.connectionSettings.allIPv4Addresses") },
+    { value: "::", label: t("preferences.connectionSettings.allIPv6Addresses") },
+  ]
+  const isUnavailable = value !== "" && value !== "0.0.0.0" && value !== "::" && !addresses.includes(value)
+
+  return (
+    <div className="space-y-2">
+      <Label htmlFor="interface_address" className="flex items-center gap-2">
+        {t("preferences.connectionSettings.interfaceAddress")}
+        <FieldHelp>{t("preferences.connectionSettings.interfaceAddressSelectDescription")}</FieldHelp>
+      </Label>
+      <Select
+        value={toSelectValue(value)}
+        onValueChange={(next) => onChange(fromSelectValue(next))}
+        disabled={addressesQuery.isLoading}
+      >
+        <SelectTrigger id="interface_address" className={blurred ? "blur-sm" : undefined}>
+          <SelectValue />
+        </SelectTrigger>
+        <SelectContent>
+          {wildcards.map((option) => (
+            <SelectItem key={option.value} value={option.value}>{option.label}</SelectItem>
+          ))}
+          {addresses.map((address) => (
+            <SelectItem key={address} value={address}>{address}</SelectItem>
+          ))}
+          {!addressesQuery.isLoading && isUnavailable && (
+            <SelectItem value={value}>
+              {t("preferences.connectionSettings.interfaceUnavailable", { name: value })}
+            </SelectItem>
+          )}
+        </SelectContent>
+      </Select>
+    </div>
+  )
+}
+
 export function ConnectionSettingsForm({ instanceId, onSuccess }: ConnectionSettingsFormProps) {
   const { t } = useTranslation("instances")
   const { preferences, isLoading, updatePreferences, isUpdating } = useInstancePreferences(instanceId)
   const fieldVisibility = useQBittorrentFieldVisibility(instanceId)
   const [incognitoMode] = useIncognitoMode()
 
+  const networkInterfacesQuery = useQuery({
+    queryKey: ["network-interfaces", instanceId],
+    queryFn: () => api.getNetworkInterfaces(instanceId),
+    staleTime: 60_000,
+  })
+
   const form = useForm({
     defaultValues: {
       listen_port: 0,
@@ -422,40 +492,63 @@ export function ConnectionSettingsForm({ instanceId, onSuccess }: ConnectionSett
           <div className="space-y-4">
             <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
               <form.Field name="current_network_interface">
-                {(field) => (
-                  <div className="space-y-2">
-                    <Label htmlFor="network_interface" className="flex items-center gap-2">
-                      {t("preferences.connectionSettings.networkInterfaceReadOnly")}
-                      <FieldHelp>{t("preferences.connectionSettings.networkInterfaceDescription")}</FieldHelp>
-                    </Label>
-                    <Input
-                      id="network_interface"
-                      value={field.state.value || t("preferences.connectionSettings.autoDetect")}
-                      readOnly
-                      className={incognitoMode ? "bg-muted blur-sm select-none" : "bg-muted"}
-                      disabled
-                    />
-                  </div>
-                )}
+                {(field) => {
+                  const interfaces = networkInterfacesQuery.data ?? []
+                  const isUnavailable = field.state.value !== "" && !interfaces.some((iface) => iface.value === field.state.value)
+
+                  return (
+                    <div className="space-y-2">
+                      <Label htmlFor="network_interface" className="flex items-center gap-2">
+                        {t("preferences.connectionSettings.networkInterfaceLabel")}
+                        <FieldHelp>{t("preferences.connectionSettings.networkInterfaceSelectDescription")}</FieldHelp>
+                      </Label>
+                      <Select
+                        value={toSelectValue(field.state.value)}
+                        onValueChange={(value) => {
+                          field.handleChange(fromSelectValue(value))
+                          // Addresses belong to an interface, so a new interface starts from all addresses.
+                          form.setFieldValue("current_interface_address", "")
+                        }}
+                        disabled={networkInterfacesQuery.isLoading}
+                      >
+                        <SelectTrigger id="network_interface" className={incognitoMode ? "blur-sm" : undefined}>
+                          <SelectValue />
+                        </SelectTrigger>
+                        <SelectContent>
+                          <SelectItem value={ANY_VALUE}>{t("preferences.connectionSettings.anyInterface")}</SelectItem>
+                          {interfaces.map((iface) => (
+                            <SelectItem key={iface.value} value={iface.value}>{iface.name}</SelectItem>
+                          ))}
+                          {!networkInterfacesQuery.isLoading && isUnavailable && (
+                            <SelectItem value={field.state.value}>
+                              {t("preferences.connectionSettings.interfaceUnavailable", { name: field.state.value })}
+                            </SelectItem>
+                          )}
+                        </SelectContent>
+                      </Select>
+                      {networkInterfacesQuery.isError && (
+                        <p className="text-xs text-destructive">{t("preferences.connectionSettings.interfacesLoadFailed")}</p>
+                      )}
+                    </div>
+                  )
+                }}
               </form.Field>
 
-              <form.Field name="current_interface_address">
-                {(field) => (
-                  <div className="space-y-2">
-                    <Label htmlFor="interface_address" className="flex items-center gap-2">
-                      {t("preferences.connectionSettings.interfaceIpAddress")}
-                      <FieldHelp>{t("preferences.connectionSettings.interfaceIpAddressDescription")}</FieldHelp>
-                    </Label>
-                    <Input
-                      id="interface_address"
-                      value={field.state.value || t("preferences.connectionSettings.autoDetect")}
-                      readOnly
-                      disabled
-                      className={incognitoMode ? "bg-muted blur-sm select-none" : "bg-muted"}
-                    />
-                  </div>
+              <form.Subscribe selector={(state) => state.values.current_network_interface}>
+                {(selectedInterface) => (
+                  <form.Field name="current_interface_address">
+                    {(field) => (
+                      <InterfaceAddressSelect
+                        instanceId={instanceId}
+                        iface={selectedInterface}
+                        value={field.state.value}
+                        onChange={(value) => field.handleChange(value)}
+                        blurred={incognitoMode}
+                      />
+                    )}
+                  </form.Field>
                 )}
-              </form.Field>
+              </form.Subscribe>
             </div>
 
             <form.Field name="reannounce_when_address_changed">
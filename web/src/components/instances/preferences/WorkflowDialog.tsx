@@ -271,6 +271,9 @@ const SIMPLE_SORT_FIELD_SET = new Set<ConditionField>([
   "NUM_COMPLETE",
   "NUM_INCOMPLETE",
   "TRACKERS_COUNT",
+  "UPLOADED_LAST_24H",
+  "UPLOADED_LAST_7D",
+  "IDLE_UPLOAD_DAYS",
   "NAME",
   "CATEGORY",
   "TAGS",
@@ -306,6 +309,9 @@ const SCORE_MULTIPLIER_FIELD_SET = new Set<ConditionField>([
   "NUM_COMPLETE",
   "NUM_INCOMPLETE",
   "TRACKERS_COUNT",
+  "UPLOADED_LAST_24H",
+  "UPLOADED_LAST_7D",
+  "IDLE_UPLOAD_DAYS",
 ])
 
 const SIMPLE_SORT_DISABLED_FIELDS = Object.keys(CONDITION_FIELDS)
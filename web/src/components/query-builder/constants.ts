@@ -113,6 +113,16 @@ export const CONDITION_FIELDS = {
   EXISTS_ON_SAME_INSTANCE: { label: "Cross-seed(s) Exists on Same Instance", type: "boolean" as const, description: "A cross-seed (same content, different hash) exists on this instance" },
   SEEDING_ON_SAME_INSTANCE: { label: "Cross-seed(s) Seeding on Same Instance", type: "boolean" as const, description: "A cross-seed is actively seeding on this instance" },
   CROSS_SEED_TAGS: { label: "Cross-seed Tags", type: "string" as const, description: "Tags across this torrent and its same-instance cross-seeds" },
+  ARR_IMPORTED: { label: "Imported by Sonarr/Radarr", type: "boolean" as const, description: "An enabled Sonarr/Radarr instance recorded an import for this download. Never matches when arr state is unavailable." },
+  ARR_IN_QUEUE: { label: "In Sonarr/Radarr Queue", type: "boolean" as const, description: "The download is still in an enabled Sonarr/Radarr queue" },
+  ARR_IMPORT_AGE: { label: "Imported Age", type: "duration" as const, description: "Time since Sonarr/Radarr last imported this download" },
+  HNR_SATISFIED: { label: "H&R Requirement Met", type: "boolean" as const, description: "The torrent meets its tracker's seeding requirement (always true for trackers without one). Never matches when requirements can't be loaded." },
+  HNR_REMAINING_SECONDS: { label: "H&R Seed Time Remaining", type: "duration" as const, description: "Seed time still needed to meet the tracker's requirement. Doesn't match when only the ratio can satisfy it." },
+  RULE_MATCH_STREAK: { label: "Match Streak", type: "integer" as const, description: "Consecutive rule runs in which the rest of this action's condition matched, including the current run" },
+  CONDITION_TRUE_FOR: { label: "Condition True For", type: "duration" as const, description: "How long the rest of this action's condition has matched without interruption" },
+  UPLOADED_LAST_24H: { label: "Uploaded (Last 24h)", type: "bytes" as const, description: "Uploaded during the last 24 hours, from sampled transfer history. Doesn't match until history covers the window." },
+  UPLOADED_LAST_7D: { label: "Uploaded (Last 7d)", type: "bytes" as const, description: "Uploaded during the last 7 days, from sampled transfer history. Doesn't match until history covers the window." },
+  IDLE_UPLOAD_DAYS: { label: "Days Without Upload", type: "float" as const, description: "Days since the torrent last uploaded anything, from sampled transfer history" },
 
   // Enum-like fields
   HARDLINK_SCOPE: { label: "Hardlink scope", type: "hardlinkScope" as const, description: "Where hardlinks for this torrent's files exist. Requires Local Filesystem Access." },
@@ -288,12 +298,20 @@ export const FIELD_GROUPS = [
   },
   {
     label: "Tracker",
-    fields: ["TRACKER", "TRACKERS", "TRACKERS_COUNT", "PRIVATE", "IS_UNREGISTERED", "TRACKER_STATUS", "TRACKER_MESSAGE", "COMMENT"],
+    fields: ["TRACKER", "TRACKERS", "TRACKERS_COUNT", "PRIVATE", "IS_UNREGISTERED", "TRACKER_STATUS", "TRACKER_MESSAGE", "COMMENT", "HNR_SATISFIED", "HNR_REMAINING_SECONDS"],
   },
   {
     label: "Cross-Seed",
     fields: ["EXISTS_ON_OTHER_INSTANCE", "SEEDING_ON_OTHER_INSTANCE", "EXISTS_ON_SAME_INSTANCE", "SEEDING_ON_SAME_INSTANCE", "CROSS_SEED_TAGS"],
   },
+  {
+    label: "Sonarr/Radarr",
+    fields: ["ARR_IMPORTED", "ARR_IN_QUEUE", "ARR_IMPORT_AGE"],
+  },
+  {
+    label: "History",
+    fields: ["RULE_MATCH_STREAK", "CONDITION_TRUE_FOR", "UPLOADED_LAST_24H", "UPLOADED_LAST_7D", "IDLE_UPLOAD_DAYS"],
+  },
   {
     label: "Mode",
     fields: ["AUTO_MANAGED", "FIRST_LAST_PIECE_PRIO", "FORCE_START", "SEQUENTIAL_DOWNLOAD", "SUPER_SEEDING"],
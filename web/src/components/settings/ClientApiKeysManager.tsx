@@ -46,7 +46,7 @@ import { useIncognitoMode } from "@/lib/incognito"
 import { copyTextToClipboard } from "@/lib/utils"
 import { useForm } from "@tanstack/react-form"
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query"
-import { Copy, Eye, EyeOff, Plus, Server, Trash2 } from "lucide-react"
+import { Copy, Eye, EyeOff, Layers, Plus, Server, Trash2 } from "lucide-react"
 import { useState } from "react"
 import { useTranslation } from "react-i18next"
 import { toast } from "sonner"
@@ -390,7 +390,12 @@ export function ClientApiKeysManager() {
                         <Badge variant="outline" className="text-xs">
                           {t("clientApiKeys.idLabel", { id: key.id })}
                         </Badge>
-                        {key.instance ? (
+                        {key.virtualInstance ? (
+                          <Badge variant="secondary" className="text-xs flex items-center gap-1">
+                            <Layers className="h-3 w-3 shrink-0" />
+                            {truncateInstanceName(key.virtualInstance.name)}
+                          </Badge>
+                        ) : key.instance ? (
                           key.instance.name.length > 20 ? (
                             <Tooltip>
                               <TooltipTrigger asChild>
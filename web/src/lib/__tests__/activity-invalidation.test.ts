@@ -29,6 +29,7 @@ const ALL_KINDS_MAP = {
   "search.history": true,
   "tracker.icons": true,
   "theme.settings": true,
+  "bulkjob.progress": true,
 } satisfies Record<ActivityEvent["kind"], true>
 
 const ALL_KINDS = Object.keys(ALL_KINDS_MAP) as ActivityEvent["kind"][]
@@ -60,6 +61,14 @@ describe("activityQueryKeys", () => {
     expect(activityQueryKeys(ev({ kind: "dirscan.run" }))).toEqual([["dir-scan"]])
   })
 
+  it("keys bulk jobs by job id carried in resourceId", () => {
+    expect(activityQueryKeys(ev({ kind: "bulkjob.progress", resourceId: "7" }))).toEqual([
+      ["bulk-jobs", "list"],
+      ["bulk-jobs", 7],
+    ])
+    expect(activityQueryKeys(ev({ kind: "bulkjob.progress" }))).toEqual([["bulk-jobs"]])
+  })
+
   it("invalidates both cross-seed search keys for a search event", () => {
     expect(activityQueryKeys(ev({ kind: "crossseed.search" }))).toEqual([
       ["cross-seed", "search-status"],
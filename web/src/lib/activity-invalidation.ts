@@ -42,6 +42,8 @@ export function activityQueryKeys(event: ActivityEvent): QueryKey[] {
       return [["tracker-icons"]]
     case "theme.settings":
       return [["theme-settings"]]
+    case "bulkjob.progress":
+      return event.resourceId ? [["bulk-jobs", "list"], ["bulk-jobs", Number(event.resourceId)]] : [["bulk-jobs"]]
     default:
       return []
   }
@@ -77,6 +79,7 @@ export const ACTIVITY_FEATURE_PREFIXES: QueryKey[] = [
   ["searchHistory"],
   ["tracker-icons"],
   ["theme-settings"],
+  ["bulk-jobs"],
 ]
 
 /**
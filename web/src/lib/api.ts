@@ -9,6 +9,7 @@ import type {
   AddTorrentResponse,
   ApplicationInfo,
   AppPreferences,
+  NetworkInterface,
   AsyncIndexerFilteringState,
   AuthResponse,
   Automation,
@@ -18,11 +19,28 @@ import type {
   AutomationInput,
   AutomationPreviewInput,
   AutomationPreviewResult,
+  BackupDestination,
+  BackupEncryption,
   BackupManifest,
   BackupRun,
   BackupRunsResponse,
+  BulkJob,
+  AuditEntry,
+  AuditFilter,
+  AuditSettings,
   BackupSettings,
   Category,
+  ClientApiKeyAddPolicy,
+  ClientApiKeyDuplicatePolicy,
+  ClientApiKeyScope,
+  VirtualInstance,
+  VirtualInstanceInput,
+  PreferenceKeyGroup,
+  PreferenceProfile,
+  PreferenceProfileDiff,
+  PreferenceProfileInput,
+  PreferenceProfilePushResult,
+  PreferenceValue,
   CrossSeedApplyResponse,
   CrossSeedAutomationSettings,
   CrossSeedAutomationSettingsPatch,
@@ -87,11 +105,14 @@ import type {
   QBittorrentAppInfo,
   RefreshRSSItemRequest,
   RegexValidationResult,
+  RemoteBackupRun,
   RemoveRSSItemRequest,
   RenameRSSRuleRequest,
   RestoreMode,
   RestorePlan,
   RestoreResult,
+  RestoreStateField,
+  RestoreTarget,
   RSSItems,
   RSSMatchingArticles,
   RSSRules,
@@ -122,6 +143,9 @@ import type {
   TrackerCustomization,
   TrackerCustomizationInput,
   TransferInfo,
+  TransferStatsQuery,
+  TransferStatsSeries,
+  TransferStatsTrackers,
   BuiltinTheme,
   ThemeSettings,
   User,
@@ -693,6 +717,8 @@ class ApiClient {
     includeCategories: boolean
     includeTags: boolean
     includeSavePaths: boolean
+    destination?: BackupDestination | null
+    encryption?: BackupEncryption | null
   }): Promise<BackupSettings> {
     return this.request<BackupSettings>(`/instances/${instanceId}/backups/settings`, {
       method: "PUT",
@@ -733,9 +759,12 @@ class ApiClient {
     })
   }
 
-  async importBackupManifest(instanceId: number, manifestFile: File): Promise<BackupRun> {
+  async importBackupManifest(instanceId: number, manifestFile: File, passphrase?: string): Promise<BackupRun> {
     const formData = new FormData()
     formData.append("archive", manifestFile)
+    if (passphrase) {
+      formData.append("passphrase", passphrase)
+    }
 
     const response = await ssoSafeFetch(`${API_BASE}/instances/${instanceId}/backups/import`, {
       method: "POST",
@@ -754,7 +783,7 @@ class ApiClient {
   async previewRestore(
     instanceId: number,
     runId: number,
-    payload: { mode?: RestoreMode; excludeHashes?: string[] } = {}
+    payload: { mode?: RestoreMode; excludeHashes?: string[]; target?: RestoreTarget; stateFields?: RestoreStateField[] } = {}
   ): Promise<RestorePlan> {
     return this.request<RestorePlan>(`/instances/${instanceId}/backups/runs/${runId}/restore/preview`, {
       method: "POST",
@@ -772,6 +801,8 @@ class ApiClient {
       startPaused?: boolean
       skipHashCheck?: boolean
       autoResumeVerified?: boolean
+      target?: RestoreTarget
+      stateFields?: RestoreStateField[]
     }
   ): Promise<RestoreResult> {
     return this.request<RestoreResult>(`/instances/${instanceId}/backups/runs/${runId}/restore`, {
@@ -780,6 +811,52 @@ class ApiClient {
     })
   }
 
+  async testBackupDestination(instanceId: number, destination: BackupDestination): Promise<{ ok: boolean }> {
+    return this.request<{ ok: boolean }>(`/instances/${instanceId}/backups/destination/test`, {
+      method: "POST",
+      body: JSON.stringify(destination),
+    })
+  }
+
+  async listRemoteBackupRuns(instanceId: number): Promise<RemoteBackupRun[]> {
+    return this.request<RemoteBackupRun[]>(`/instances/${instanceId}/backups/remote/runs`)
+  }
+
+  async getRemoteBackupManifest(instanceId: number, name: string): Promise<BackupManifest> {
+    return this.request<BackupManifest>(`/instances/${instanceId}/backups/remote/runs/${encodeURIComponent(name)}/manifest`)
+  }
+
+  async previewRemoteRestore(
+    instanceId: number,
+    name: string,
+    payload: { mode?: RestoreMode; excludeHashes?: string[]; target?: RestoreTarget; stateFields?: RestoreStateField[] } = {}
+  ): Promise<RestorePlan> {
+    return this.request<RestorePlan>(`/instances/${instanceId}/backups/remote/runs/${encodeURIComponent(name)}/restore/preview`, {
+      method: "POST",
+      body: JSON.stringify(payload),
+    })
+  }
+
+  async executeRemoteRestore(
+    instanceId: number,
+    name: string,
+    payload: {
+      mode: RestoreMode
+      dryRun?: boolean
+      excludeHashes?: string[]
+      startPaused?: boolean
+      skipHashCheck?: boolean
+      autoResumeVerified?: boolean
+      target?: RestoreTarget
+      stateFields?: RestoreStateField[]
+    }
+  ): Promise<RestoreResult> {
+    return this.request<RestoreResult>(`/instances/${instanceId}/backups/remote/runs/${encodeURIComponent(name)}/restore`, {
+      method: "POST",
+      body: JSON.stringify(payload),
+    })
+  }
+
   getBackupDownloadUrl(instanceId: number, runId: number, format?: string): string {
     const url = new URL(withBasePath(`/api/instances/${instanceId}/backups/runs/${runId}/download`), window.location.origin)
     if (format && format !== "zip") {
@@ -1076,6 +1153,34 @@ class ApiClient {
     })
   }
 
+  // startBulkJob runs a bulk action as a background job. Progress arrives as
+  // "bulkjob.progress" activity events; read it with getBulkJob.
+  async startBulkJob(
+    instanceId: number,
+    data: Parameters<ApiClient["bulkAction"]>[1]
+  ): Promise<BulkJob> {
+    return this.request(`/instances/${instanceId}/torrents/bulk-action`, {
+      method: "POST",
+      body: JSON.stringify({ ...data, async: true }),
+    })
+  }
+
+  async getBulkJobs(): Promise<BulkJob[]> {
+    return this.request("/bulk-jobs")
+  }
+
+  async getBulkJob(id: number): Promise<BulkJob> {
+    return this.request(`/bulk-jobs/${id}`)
+  }
+
+  async cancelBulkJob(id: number): Promise<BulkJob> {
+    return this.request(`/bulk-jobs/${id}/cancel`, { method: "POST" })
+  }
+
+  async undoLastBulkJob(): Promise<BulkJob> {
+    return this.request("/bulk-jobs/undo", { method: "POST" })
+  }
+
   async analyzeTorrentForCrossSeedSearch(
     instanceId: number,
     hash: string
@@ -1953,6 +2058,10 @@ class ApiClient {
     id: number
     clientName: string
     instanceId: number
+    virtualInstanceId?: number
+    scope: ClientApiKeyScope
+    addPolicy: ClientApiKeyAddPolicy
+    duplicatePolicy: ClientApiKeyDuplicatePolicy
     createdAt: string
     lastUsedAt?: string
     instance?: {
@@ -1960,19 +2069,28 @@ class ApiClient {
       name: string
       host: string
     } | null
+    virtualInstance?: VirtualInstance
   }[]> {
     return this.request("/client-api-keys")
   }
 
   async createClientApiKey(data: {
     clientName: string
-    instanceId: number
+    instanceId?: number
+    virtualInstanceId?: number
+    scope?: ClientApiKeyScope
+    addPolicy?: ClientApiKeyAddPolicy
+    duplicatePolicy?: ClientApiKeyDuplicatePolicy
   }): Promise<{
     key: string
     clientApiKey: {
       id: number
       clientName: string
       instanceId: number
+      virtualInstanceId?: number
+      scope: ClientApiKeyScope
+      addPolicy: ClientApiKeyAddPolicy
+      duplicatePolicy: ClientApiKeyDuplicatePolicy
       createdAt: string
     }
     instance?: {
@@ -1980,6 +2098,7 @@ class ApiClient {
       name: string
       host: string
     }
+    virtualInstance?: VirtualInstance
     proxyUrl: string
   }> {
     return this.request("/client-api-keys", {
@@ -1988,10 +2107,101 @@ class ApiClient {
     })
   }
 
+  async updateClientApiKeyScope(id: number, scope: ClientApiKeyScope): Promise<ClientApiKeyScope> {
+    return this.request(`/client-api-keys/${id}/scope`, {
+      method: "PUT",
+      body: JSON.stringify(scope),
+    })
+  }
+
+  async updateClientApiKeyAddPolicy(id: number, addPolicy: ClientApiKeyAddPolicy): Promise<ClientApiKeyAddPolicy> {
+    return this.request(`/client-api-keys/${id}/add-policy`, {
+      method: "PUT",
+      body: JSON.stringify(addPolicy),
+    })
+  }
+
+  async updateClientApiKeyDuplicatePolicy(
+    id: number,
+    duplicatePolicy: ClientApiKeyDuplicatePolicy
+  ): Promise<{ duplicatePolicy: ClientApiKeyDuplicatePolicy }> {
+    return this.request(`/client-api-keys/${id}/duplicate-policy`, {
+      method: "PUT",
+      body: JSON.stringify({ duplicatePolicy }),
+    })
+  }
+
   async deleteClientApiKey(id: number): Promise<void> {
     return this.request(`/client-api-keys/${id}`, { method: "DELETE" })
   }
 
+  async getVirtualInstances(): Promise<VirtualInstance[]> {
+    return this.request("/virtual-instances")
+  }
+
+  async createVirtualInstance(data: VirtualInstanceInput): Promise<VirtualInstance> {
+    return this.request("/virtual-instances", {
+      method: "POST",
+      body: JSON.stringify(data),
+    })
+  }
+
+  async updateVirtualInstance(id: number, data: VirtualInstanceInput): Promise<VirtualInstance> {
+    return this.request(`/virtual-instances/${id}`, {
+      method: "PUT",
+      body: JSON.stringify(data),
+    })
+  }
+
+  async deleteVirtualInstance(id: number): Promise<void> {
+    return this.request(`/virtual-instances/${id}`, { method: "DELETE" })
+  }
+
+  async getPreferenceProfiles(): Promise<PreferenceProfile[]> {
+    return this.request("/preference-profiles")
+  }
+
+  async getPreferenceProfileKeys(): Promise<PreferenceKeyGroup[]> {
+    return this.request("/preference-profiles/keys")
+  }
+
+  async createPreferenceProfile(data: PreferenceProfileInput): Promise<PreferenceProfile> {
+    return this.request("/preference-profiles", {
+      method: "POST",
+      body: JSON.stringify(data),
+    })
+  }
+
+  async updatePreferenceProfile(id: number, data: PreferenceProfileInput): Promise<PreferenceProfile> {
+    return this.request(`/preference-profiles/${id}`, {
+      method: "PUT",
+      body: JSON.stringify(data),
+    })
+  }
+
+  async deletePreferenceProfile(id: number): Promise<void> {
+    return this.request(`/preference-profiles/${id}`, { method: "DELETE" })
+  }
+
+  async snapshotPreferences(instanceId: number, keys: string[] = []): Promise<{ preferences: Record<string, PreferenceValue> }> {
+    return this.request("/preference-profiles/snapshot", {
+      method: "POST",
+      body: JSON.stringify({ instanceId, keys }),
+    })
+  }
+
+  async diffPreferenceProfile(id: number, instanceIds?: number[]): Promise<PreferenceProfileDiff[]> {
+    const params = instanceIds?.length ? `?instanceIds=${instanceIds.join(",")}` : ""
+    return this.request(`/preference-profiles/${id}/diff${params}`)
+  }
+
+  async pushPreferenceProfile(id: number, instanceIds?: number[]): Promise<PreferenceProfilePushResult[]> {
+    return this.request(`/preference-profiles/${id}/push`, {
+      method: "POST",
+      body: JSON.stringify({ instanceIds: instanceIds ?? [] }),
+    })
+  }
+
   // License endpoints
   async activateLicense(licenseKey: string): Promise<{
     valid: boolean
@@ -2070,6 +2280,15 @@ class ApiClient {
     return this.request<AppPreferences>(`/instances/${instanceId}/preferences`)
   }
 
+  async getNetworkInterfaces(instanceId: number): Promise<NetworkInterface[]> {
+    return this.request<NetworkInterface[]>(`/instances/${instanceId}/preferences/network-interfaces`)
+  }
+
+  async getNetworkInterfaceAddresses(instanceId: number, iface: string): Promise<string[]> {
+    const params = new URLSearchParams({ iface })
+    return this.request<string[]>(`/instances/${instanceId}/preferences/network-interfaces/addresses?${params}`)
+  }
+
   async updateInstancePreferences(
     instanceId: number,
     preferences: Partial<AppPreferences>
@@ -2267,6 +2486,40 @@ class ApiClient {
     })
   }
 
+  // Audit log endpoints
+  async getAuditEntries(filter: AuditFilter = {}): Promise<AuditEntry[]> {
+    const query = queryParams(filter).toString()
+    return this.request<AuditEntry[]>(`/audit${query ? `?${query}` : ""}`)
+  }
+
+  getAuditExportUrl(format: "csv" | "json", filter: AuditFilter = {}): string {
+    const params = queryParams(filter)
+    params.set("format", format)
+    return `${API_BASE}/audit/export?${params.toString()}`
+  }
+
+  async getAuditSettings(): Promise<AuditSettings> {
+    return this.request<AuditSettings>("/audit/settings")
+  }
+
+  async updateAuditSettings(data: AuditSettings): Promise<AuditSettings> {
+    return this.request<AuditSettings>("/audit/settings", {
+      method: "PUT",
+      body: JSON.stringify(data),
+    })
+  }
+
+  // Transfer stats endpoints
+  async getTransferStats(query: TransferStatsQuery = {}): Promise<TransferStatsSeries> {
+    const params = queryParams(query).toString()
+    return this.request<TransferStatsSeries>(`/transfer-stats${params ? `?${params}` : ""}`)
+  }
+
+  async getTransferStatsByTracker(query: Omit<TransferStatsQuery, "tracker"> = {}): Promise<TransferStatsTrackers> {
+    const params = queryParams(query).toString()
+    return this.request<TransferStatsTrackers>(`/transfer-stats/trackers${params ? `?${params}` : ""}`)
+  }
+
   // Torznab Indexer endpoints
   async listTorznabIndexers(): Promise<TorznabIndexer[]> {
     return this.request<TorznabIndexer[]>("/torznab/indexers")
@@ -2820,3 +3073,13 @@ function parseContentDispositionFilename(header: string | null): string | null {
 
   return null
 }
+
+function queryParams(values: object): URLSearchParams {
+  const params = new URLSearchParams()
+  for (const [key, value] of Object.entries(values)) {
+    if (value !== undefined && value !== "") {
+      params.set(key, String(value))
+    }
+  }
+  return params
+}
@@ -46,6 +46,7 @@ function makeInstance(overrides: Partial<InstanceResponse> = {}): InstanceRespon
     name: "qbit",
     host: "http://localhost:8080",
     username: "user",
+    clientType: "qbittorrent",
     tlsSkipVerify: false,
     hasLocalFilesystemAccess: true,
     useHardlinks: false,
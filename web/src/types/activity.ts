@@ -17,6 +17,7 @@ export type ActivityEventKind =
   | "search.history"
   | "tracker.icons"
   | "theme.settings"
+  | "bulkjob.progress"
 
 // ActivityEvent is a small qui-owned server signal. It carries identifiers only
 // (never payload data); the frontend reacts by invalidating the matching query.
@@ -33,6 +33,11 @@ export interface ApplicationInfo {
   database: ApplicationDatabaseInfo
 }
 
+export interface NetworkInterface {
+  name: string
+  value: string
+}
+
 export interface AppPreferences {
   // Core limits and speeds (fully supported)
   dl_limit: number
@@ -57,14 +62,10 @@ export interface AppPreferences {
   bittorrent_protocol: number
   utp_tcp_mixed_mode: number
 
-  // Network interface fields - displayed as read-only in UI
-  // TODO: These fields are configurable in qBittorrent API but go-qbittorrent library
-  // lacks the required endpoints for proper dropdown selection:
-  // - /api/v2/app/networkInterfaceList
-  // - /api/v2/app/networkInterfaceAddressList
-  // Currently shown as read-only inputs displaying actual qBittorrent values
-  current_network_interface: string // Shows current interface (empty = auto-detect)
-  current_interface_address: string // Shows current interface IP address
+  // Network binding; choices come from api.getNetworkInterfaces and
+  // api.getNetworkInterfaceAddresses
+  current_network_interface: string // Empty = any interface
+  current_interface_address: string // Empty = all addresses, "0.0.0.0" = all IPv4, "::" = all IPv6
 
   announce_ip: string
   reannounce_when_address_changed: boolean
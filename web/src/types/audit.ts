@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2026, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+export type AuditSource = "api" | "proxy"
+
+export type AuditActorType = "user" | "api_key" | "client_key"
+
+export interface AuditEntry {
+  id: number
+  createdAt: string
+  source: AuditSource
+  actorType: AuditActorType
+  actor: string
+  instanceId?: number
+  method: string
+  endpoint: string
+  hashes: string[]
+  status: number
+  success: boolean
+}
+
+export interface AuditFilter {
+  from?: string
+  to?: string
+  source?: AuditSource
+  actorType?: AuditActorType
+  actor?: string
+  instanceId?: number
+  endpoint?: string
+  hash?: string
+  success?: boolean
+  limit?: number
+  offset?: number
+}
+
+export interface AuditSettings {
+  enabled: boolean
+  retentionDays: number
+  updatedAt?: string
+}
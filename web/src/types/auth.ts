@@ -15,3 +15,53 @@ export interface AuthResponse {
   user: User
   message?: string
 }
+
+/** Limits what a client API key may do through the proxy. Empty grants full access. */
+export interface ClientApiKeyScope {
+  readOnly?: boolean
+  /** Web API endpoints relative to /api/v2/, e.g. "torrents/add" or "sync/*" */
+  allowedEndpoints?: string[]
+  forbidDeleteFiles?: boolean
+  categories?: string[]
+  tags?: string[]
+}
+
+/** Rewrites torrents/add requests made through the proxy with a client API key. */
+export interface ClientApiKeyAddPolicy {
+  /** Used when the request sets no category, or always with forceCategory */
+  category?: string
+  forceCategory?: boolean
+  /** Added to the requested tags */
+  tags?: string[]
+  /** Go template with .Category, .Tags, .SavePath and .Client */
+  savePath?: string
+  paused?: boolean
+  /** Bytes per second, -1 for no limit */
+  uploadLimit?: number
+  ratioLimit?: number
+  /** Minutes */
+  seedingTimeLimit?: number
+  /** Minutes */
+  inactiveSeedingTimeLimit?: number
+}
+
+/** What the proxy does when torrents/add adds a torrent that already exists on any instance; "" disables the check. */
+export type ClientApiKeyDuplicatePolicy = "" | "allow" | "reject" | "cross_seed"
+
+export type VirtualInstancePlacement = "round_robin" | "free_space" | "category"
+
+/** Several instances served through the proxy as a single qBittorrent instance. */
+export interface VirtualInstance {
+  id: number
+  name: string
+  memberInstanceIds: number[]
+  placement: VirtualInstancePlacement
+  /** Member instance per category for category placement */
+  categoryInstances: Record<string, number>
+  createdAt: string
+  updatedAt: string
+}
+
+export type VirtualInstanceInput = Pick<VirtualInstance, "name" | "memberInstanceIds" | "placement"> & {
+  categoryInstances?: Record<string, number>
+}
@@ -103,6 +103,19 @@ export type ConditionField =
   | "EXISTS_ON_SAME_INSTANCE"
   | "CROSS_SEED_TAGS"
   | "SEEDING_ON_SAME_INSTANCE"
+  | "ARR_IMPORTED"
+  | "ARR_IN_QUEUE"
+  // Sonarr/Radarr import age
+  | "ARR_IMPORT_AGE"
+  // Tracker seeding requirements
+  | "HNR_SATISFIED"
+  | "HNR_REMAINING_SECONDS"
+  // Rule history
+  | "RULE_MATCH_STREAK"
+  | "CONDITION_TRUE_FOR"
+  | "UPLOADED_LAST_24H"
+  | "UPLOADED_LAST_7D"
+  | "IDLE_UPLOAD_DAYS"
   // Enum-like fields
   | "HARDLINK_SCOPE"
   | "HARDLINK_SCOPE_CROSS"
@@ -250,6 +263,17 @@ export interface ExportToInstanceAction {
   condition?: RuleCondition
 }
 
+export interface WebhookAction {
+  enabled: boolean
+  url: string
+  headers?: Record<string, string>
+  bodyTemplate?: string
+  timeoutSeconds?: number
+  retries?: number
+  mode?: "batch" | "perTorrent"
+  condition?: RuleCondition
+}
+
 export interface ActionConditions {
   schemaVersion: string
   grouping?: GroupingConfig
@@ -269,6 +293,7 @@ export interface ActionConditions {
   externalProgram?: ExternalProgramAction
   autoManagement?: AutoManagementAction
   exportToInstance?: ExportToInstanceAction
+  webhook?: WebhookAction
 }
 
 export type FreeSpaceSource =
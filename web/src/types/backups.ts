@@ -21,10 +21,42 @@ export interface BackupSettings {
   includeCategories: boolean
   includeTags: boolean
   includeSavePaths: boolean
+  destination?: BackupDestination | null
+  encryption?: BackupEncryption | null
   createdAt?: string
   updatedAt?: string
 }
 
+export type BackupDestinationType = "s3" | "webdav" | "sftp"
+
+export interface BackupDestination {
+  type: BackupDestinationType
+  endpoint: string
+  bucket?: string
+  region?: string
+  pathStyle?: boolean
+  path?: string
+  username?: string
+  password?: string
+  privateKey?: string
+  hostKey?: string
+}
+
+export type BackupEncryptionMode = "passphrase" | "age"
+
+export interface BackupEncryption {
+  mode: BackupEncryptionMode
+  passphrase?: string
+  identity?: string
+  recipient?: string
+}
+
+export interface RemoteBackupRun {
+  name: string
+  sizeBytes: number
+  modifiedAt?: string
+}
+
 export interface BackupRun {
   id: number
   instanceId: number
@@ -61,6 +93,27 @@ export interface BackupManifestItem {
   tags?: string[]
   torrentBlob?: string
   savePath?: string | null
+  state?: BackupTorrentState
+}
+
+export interface BackupFileState {
+  index: number
+  name?: string
+  priority: number
+}
+
+export interface BackupTorrentState {
+  ratioLimit: number
+  seedingTimeLimit: number
+  inactiveSeedingTimeLimit: number
+  shareLimitAction?: string
+  uploadLimit?: number
+  downloadLimit?: number
+  sequentialDownload?: boolean
+  firstLastPiecePrio?: boolean
+  trackers?: string[]
+  files?: BackupFileState[]
+  addedOn?: number
 }
 
 export interface BackupCategorySnapshot {
@@ -68,6 +121,7 @@ export interface BackupCategorySnapshot {
 }
 
 export interface BackupManifest {
+  version?: number
   instanceId: number
   kind: BackupRunKind
   generatedAt: string
@@ -79,6 +133,25 @@ export interface BackupManifest {
 
 export type RestoreMode = "incremental" | "overwrite" | "complete"
 
+export interface RestorePathMapping {
+  from: string
+  to: string
+}
+
+export interface RestoreTrackerRewrite {
+  from: string
+  to: string
+}
+
+export interface RestoreTarget {
+  instanceId?: number
+  pathMappings?: RestorePathMapping[]
+  categoryPaths?: Record<string, string>
+  trackerRewrites?: RestoreTrackerRewrite[]
+}
+
+export type RestoreStateField = "shareLimits" | "speedLimits" | "trackers" | "files" | "downloadOrder"
+
 export interface RestorePlanCategorySpec {
   name: string
   savePath?: string | null
@@ -100,6 +173,8 @@ export interface RestoreDiffChange {
 
 export interface RestorePlanTorrentSpec {
   manifest: BackupManifestItem
+  dataPath?: string
+  dataMissing?: boolean
 }
 
 export interface RestorePlanTorrentUpdate {
@@ -114,6 +189,14 @@ export interface RestorePlanTorrentUpdate {
     infoHashV2?: string
     savePath?: string
     sizeBytes?: number
+    ratioLimit?: number
+    seedingTimeLimit?: number
+    inactiveSeedingTimeLimit?: number
+    shareLimitAction?: string
+    uploadLimit?: number
+    downloadLimit?: number
+    sequentialDownload?: boolean
+    firstLastPiecePrio?: boolean
   }
   desired: BackupManifestItem & { torrentBlob?: string }
   changes: RestoreDiffChange[]
@@ -123,6 +206,10 @@ export interface RestorePlan {
   mode: RestoreMode
   runId: number
   instanceId: number
+  sourceInstanceId: number
+  trackerRewrites?: RestoreTrackerRewrite[]
+  stateFields: RestoreStateField[]
+  warnings?: string[]
   categories: {
     create?: RestorePlanCategorySpec[]
     update?: RestorePlanCategoryUpdate[]
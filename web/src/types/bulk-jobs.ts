@@ -0,0 +1,29 @@
+/*
+ * Copyright (c) 2026, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+export type BulkJobStatus = "queued" | "running" | "completed" | "failed" | "canceled"
+
+export interface BulkJobHashError {
+  instanceId: number
+  hash: string
+  error: string
+}
+
+export interface BulkJob {
+  id: number
+  action: string
+  instanceIds: number[]
+  status: BulkJobStatus
+  total: number
+  processed: number
+  failed: number
+  errors?: BulkJobHashError[]
+  reversible: boolean
+  undoOf?: number
+  undoneBy?: number
+  createdAt: string
+  startedAt?: string
+  finishedAt?: string
+}
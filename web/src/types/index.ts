@@ -26,3 +26,7 @@ export * from "./logs"
 export * from "./dir-scan"
 export * from "./rss"
 export * from "./arr"
+export * from "./audit"
+export * from "./transfer-stats"
+export * from "./preference-profiles"
+export * from "./bulk-jobs"
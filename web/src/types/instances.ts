@@ -3,11 +3,14 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
+export type ClientType = "qbittorrent" | "transmission" | "deluge"
+
 export interface Instance {
   id: number
   name: string
   host: string
   username: string
+  clientType: ClientType
   hasApiKey?: boolean
   basicUsername?: string
   tlsSkipVerify: boolean
@@ -28,6 +31,7 @@ export interface Instance {
 export interface InstanceFormData {
   name: string
   host: string
+  clientType?: ClientType
   username?: string
   password?: string
   apiKey?: string
@@ -143,6 +147,12 @@ export interface InstanceResponse extends Instance {
 }
 
 export interface InstanceCapabilities {
+  clientType: ClientType
+  supportsCategories: boolean
+  supportsTags: boolean
+  supportsSetLocation: boolean
+  supportsShareLimits: boolean
+  supportsBackups: boolean
   supportsTorrentCreation: boolean
   supportsTorrentExport: boolean
   supportsSetTags: boolean
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2026, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+export type PreferenceValue = boolean | number | string
+
+export interface PreferenceKeyGroup {
+  name: "connections" | "queueing" | "speed_schedule" | "disk_cache"
+  keys: string[]
+}
+
+export interface PreferenceProfileInput {
+  name: string
+  description?: string
+  preferences: Record<string, PreferenceValue>
+  instanceIds: number[]
+  driftAlerts: boolean
+}
+
+export interface PreferenceProfile extends PreferenceProfileInput {
+  id: number
+  description: string
+  createdAt: string
+  updatedAt: string
+}
+
+export interface PreferenceKeyDiff {
+  key: string
+  expected: PreferenceValue
+  actual?: PreferenceValue
+  missing?: boolean
+}
+
+export interface PreferenceProfileDiff {
+  instanceId: number
+  instanceName: string
+  inSync: boolean
+  differences: PreferenceKeyDiff[]
+  error?: string
+}
+
+export interface PreferenceProfilePushResult {
+  instanceId: number
+  instanceName: string
+  success: boolean
+  error?: string
+}
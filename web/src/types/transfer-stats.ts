@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2026, s0up and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+export type TransferStatsResolution = "minute" | "hour" | "day"
+
+export interface TransferStatsPoint {
+  bucketStart: number
+  uploaded: number
+  downloaded: number
+  torrentCount: number
+}
+
+export interface TransferStatsSeries {
+  instanceId?: number
+  tracker?: string
+  resolution: TransferStatsResolution
+  from: string
+  to: string
+  points: TransferStatsPoint[]
+}
+
+export interface TransferStatsTrackerTotal {
+  tracker: string
+  uploaded: number
+  downloaded: number
+}
+
+export interface TransferStatsTrackers {
+  instanceId?: number
+  resolution: TransferStatsResolution
+  from: string
+  to: string
+  trackers: TransferStatsTrackerTotal[]
+}
+
+export interface TransferStatsQuery {
+  instanceId?: number
+  tracker?: string
+  from?: string
+  to?: string
+  resolution?: TransferStatsResolution | "auto"
+}